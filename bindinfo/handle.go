@@ -0,0 +1,169 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bindinfo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// CaptureMinExecCount is the min number of the executions of a statement digest before its plan is captured.
+	CaptureMinExecCount = 2
+	// captureCapacity is the max number of the digests whose executions are counted for the capture.
+	captureCapacity = 1000
+)
+
+// Baseline sources.
+const (
+	// SourceCapture means the baseline is the plan captured when the statement is executed repeatedly.
+	SourceCapture = "capture"
+	// SourceEvolve means the baseline is a faster plan promoted by the evolution.
+	SourceEvolve = "evolve"
+)
+
+// TableAccessPath is the way a plan reads a table.
+type TableAccessPath struct {
+	// DB and Table are the lower case names of the database and the table, the table name is
+	// the alias if the table is aliased in the statement.
+	DB    string
+	Table string
+	// Index is the lower case name of the index used to read the table, the table is read
+	// by a table scan if it's empty.
+	Index string
+}
+
+// Baseline is the accepted plan of a statement digest. The plan is recorded as the access
+// paths of the tables, which are forced like the index hints when the statement is planned.
+type Baseline struct {
+	DB     string
+	Digest string
+	// SQL is the text of the statement when the baseline is captured, the evolution executes
+	// it to compare the plans.
+	SQL         string
+	AccessPaths []TableAccessPath
+	Source      string
+	UpdateTime  time.Time
+}
+
+// AccessPathOf returns the access path of the table, ok is false if the baseline doesn't
+// contain the table.
+func (b *Baseline) AccessPathOf(db, table string) (path TableAccessPath, ok bool) {
+	for _, path := range b.AccessPaths {
+		if path.DB == db && path.Table == table {
+			return path, true
+		}
+	}
+	return path, false
+}
+
+// SameAccessPaths checks whether the access paths are the same as the ones of the baseline.
+func (b *Baseline) SameAccessPaths(paths []TableAccessPath) bool {
+	if len(b.AccessPaths) != len(paths) {
+		return false
+	}
+	for i := range paths {
+		if b.AccessPaths[i] != paths[i] {
+			return false
+		}
+	}
+	return true
+}
+
+type baselineKey struct {
+	db     string
+	digest string
+}
+
+// Handle manages the plan baselines of a domain. The baselines are kept in memory, they are
+// captured again after the server restarts.
+type Handle struct {
+	mu        sync.RWMutex
+	baselines map[baselineKey]*Baseline
+	// execCounts counts the executions of the digests which have no baselines yet.
+	execCounts map[baselineKey]int
+}
+
+// NewHandle creates a Handle.
+func NewHandle() *Handle {
+	return &Handle{
+		baselines:  make(map[baselineKey]*Baseline),
+		execCounts: make(map[baselineKey]int),
+	}
+}
+
+// Get returns the baseline of the digest, nil is returned if there is none.
+func (h *Handle) Get(db, digest string) *Baseline {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.baselines[baselineKey{db: db, digest: digest}]
+}
+
+// Capture counts an execution of the statement, its plan becomes the baseline when the
+// digest is executed CaptureMinExecCount times. It returns whether the plan is captured.
+func (h *Handle) Capture(b *Baseline) bool {
+	key := baselineKey{db: b.DB, digest: b.Digest}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.baselines[key]; ok {
+		return false
+	}
+	count, ok := h.execCounts[key]
+	if !ok && len(h.execCounts) >= captureCapacity {
+		// The counts are only used to find the repeated digests, they are dropped together
+		// when there are too many.
+		h.execCounts = make(map[baselineKey]int)
+	}
+	count++
+	if count < CaptureMinExecCount {
+		h.execCounts[key] = count
+		return false
+	}
+	delete(h.execCounts, key)
+	b.Source, b.UpdateTime = SourceCapture, time.Now()
+	h.baselines[key] = b
+	return true
+}
+
+// Evolve replaces the baseline of the digest with the faster access paths.
+func (h *Handle) Evolve(db, digest string, paths []TableAccessPath) {
+	key := baselineKey{db: db, digest: digest}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	old, ok := h.baselines[key]
+	if !ok {
+		return
+	}
+	b := *old
+	b.AccessPaths, b.Source, b.UpdateTime = paths, SourceEvolve, time.Now()
+	h.baselines[key] = &b
+}
+
+// Baselines returns all the baselines ordered by the databases and the statements.
+func (h *Handle) Baselines() []*Baseline {
+	h.mu.RLock()
+	baselines := make([]*Baseline, 0, len(h.baselines))
+	for _, b := range h.baselines {
+		baselines = append(baselines, b)
+	}
+	h.mu.RUnlock()
+	sort.Slice(baselines, func(i, j int) bool {
+		if baselines[i].DB != baselines[j].DB {
+			return baselines[i].DB < baselines[j].DB
+		}
+		return baselines[i].SQL < baselines[j].SQL
+	})
+	return baselines
+}
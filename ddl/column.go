@@ -410,6 +410,10 @@ func (w *worker) doModifyColumn(t *meta.Meta, job *model.Job, newCol *model.Colu
 			}
 		}
 	}
+	// Change the name in the TTL config.
+	if tblInfo.TTLInfo != nil && tblInfo.TTLInfo.ColumnName.L == oldName.L {
+		tblInfo.TTLInfo.ColumnName = newCol.Name
+	}
 
 	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
 	if err != nil {
@@ -460,6 +464,7 @@ func updateColumnDefaultValue(t *meta.Meta, job *model.Job, newCol *model.Column
 	}
 	// The newCol's offset may be the value of the old schema version, so we can't use newCol directly.
 	oldCol.DefaultValue = newCol.DefaultValue
+	oldCol.DefaultIsExpr = newCol.DefaultIsExpr
 	oldCol.Flag = newCol.Flag
 
 	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
@@ -536,9 +541,21 @@ func modifyColsFromNull2NotNull(w *worker, dbInfo *model.DBInfo, tblInfo *model.
 	return nil
 }
 
-func generateOriginDefaultValue(col *model.ColumnInfo) (interface{}, error) {
+func generateOriginDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo) (interface{}, error) {
 	var err error
 	odValue := col.GetDefaultValue()
+	if col.DefaultIsExpr {
+		// The existing rows get the value the expression is evaluated to when the column is added.
+		d, err := table.GetColDefaultValue(ctx, col)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if d.IsNull() {
+			odValue = nil
+		} else if odValue, err = d.ToString(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	if odValue == nil && mysql.HasNotNullFlag(col.Flag) {
 		zeroVal := table.GetZeroValue(col)
 		odValue, err = zeroVal.ToString()
@@ -773,6 +773,48 @@ func (s *testDBSuite5) TestCheckColumnDefaultValue(c *C) {
 	c.Assert(tblInfo.Meta().Columns[0].DefaultValue, Equals, "")
 }
 
+func (s *testDBSuite5) TestColumnDefaultValueExpr(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t_default_expr")
+	tk.MustExec("create table t_default_expr (id int, u varchar(36) default (uuid()), b blob default (repeat('ab', 2)), c int not null default (1 + 2))")
+	tk.MustQuery("show create table t_default_expr").Check(testutil.RowsWithSep("|",
+		"t_default_expr CREATE TABLE `t_default_expr` (\n"+
+			"  `id` int(11) DEFAULT NULL,\n"+
+			"  `u` varchar(36) DEFAULT (uuid()),\n"+
+			"  `b` blob DEFAULT (repeat(\"ab\", 2)),\n"+
+			"  `c` int(11) NOT NULL DEFAULT (1 + 2)\n"+
+			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
+	))
+	colExtraSQL := "select extra from information_schema.columns where table_name = 't_default_expr' and column_name = 'c'"
+	tk.MustQuery(colExtraSQL).Check(testkit.Rows("DEFAULT_GENERATED"))
+
+	// The expressions are evaluated for each row.
+	tk.MustExec("insert into t_default_expr (id) values (1), (2)")
+	tk.MustExec("insert into t_default_expr (id, u, c) values (3, 'x', 4)")
+	tk.MustQuery("select id, length(u), b, c from t_default_expr order by id").Check(testkit.Rows("1 36 abab 3", "2 36 abab 3", "3 1 abab 4"))
+	tk.MustQuery("select count(distinct u) from t_default_expr").Check(testkit.Rows("3"))
+
+	// The existing rows get the value when the column is added.
+	tk.MustExec("alter table t_default_expr add column d int default (2 * 5)")
+	tk.MustExec("insert into t_default_expr (id) values (4)")
+	tk.MustQuery("select id, d from t_default_expr order by id").Check(testkit.Rows("1 10", "2 10", "3 10", "4 10"))
+
+	tk.MustExec("alter table t_default_expr alter column c set default (5 - 1)")
+	tk.MustExec("insert into t_default_expr (id) values (5)")
+	tk.MustExec("alter table t_default_expr alter column c set default 6")
+	tk.MustExec("insert into t_default_expr (id) values (6)")
+	tk.MustQuery("select c from t_default_expr where id > 4 order by id").Check(testkit.Rows("4", "6"))
+	tk.MustQuery(colExtraSQL).Check(testkit.Rows(""))
+
+	tk.MustGetErrCode("create table t_default_expr_err (a int, b int default (a + 1))", mysql.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create table t_default_expr_err (a varchar(10) default (@a))", mysql.ErrDefValGeneratedVariables)
+	tk.MustGetErrCode("create table t_default_expr_err (a int default ((select 1)))", mysql.ErrDefValGeneratedFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t_default_expr_err (a int default ((1, 2)))", mysql.ErrDefValGeneratedRowValue)
+	tk.MustGetErrCode("alter table t_default_expr alter column c set default ((select 1))", mysql.ErrDefValGeneratedFunctionIsNotAllowed)
+	tk.MustGetErrCode("create table t_default_expr_err (a int default (uuid()))", mysql.ErrInvalidDefault)
+}
+
 func (s *testDBSuite3) TestColumnModifyingDefinition(c *C) {
 	s.tk = testkit.NewTestKit(c, s.store)
 	s.tk.MustExec("use test")
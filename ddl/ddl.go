@@ -102,6 +102,28 @@ var (
 	// ErrGeneratedColumnFunctionIsNotAllowed returns for unsupported functions for generated columns.
 	ErrGeneratedColumnFunctionIsNotAllowed = terror.ClassDDL.New(mysql.ErrGeneratedColumnFunctionIsNotAllowed, mysql.MySQLErrName[mysql.ErrGeneratedColumnFunctionIsNotAllowed])
 	errUnsupportedIndexType                = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "index type"))
+	// errUnsupportedCacheSysTable returns for caching the tables of the system databases.
+	errUnsupportedCacheSysTable = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "cache the tables of the system databases"))
+	// ErrFunctionalIndexOnField returns for the expression indexes on a plain column.
+	ErrFunctionalIndexOnField = terror.ClassDDL.New(mysql.ErrFunctionalIndexOnField, mysql.MySQLErrName[mysql.ErrFunctionalIndexOnField])
+	// ErrFunctionalIndexFunctionIsNotAllowed returns for unsupported functions for expression indexes.
+	ErrFunctionalIndexFunctionIsNotAllowed = terror.ClassDDL.New(mysql.ErrFunctionalIndexFunctionIsNotAllowed, mysql.MySQLErrName[mysql.ErrFunctionalIndexFunctionIsNotAllowed])
+	// ErrFunctionalIndexRowValueIsNotAllowed forbids the expression indexes to refer to row values.
+	ErrFunctionalIndexRowValueIsNotAllowed = terror.ClassDDL.New(mysql.ErrFunctionalIndexRowValueIsNotAllowed, mysql.MySQLErrName[mysql.ErrFunctionalIndexRowValueIsNotAllowed])
+	// ErrFunctionalIndexPrimaryKey forbids to build the primary key on expressions.
+	ErrFunctionalIndexPrimaryKey = terror.ClassDDL.New(mysql.ErrFunctionalIndexPrimaryKey, mysql.MySQLErrName[mysql.ErrFunctionalIndexPrimaryKey])
+	// ErrFunctionalIndexRefAutoIncrement forbids the expression indexes to refer to the auto_increment column.
+	ErrFunctionalIndexRefAutoIncrement = terror.ClassDDL.New(mysql.ErrFunctionalIndexRefAutoIncrement, mysql.MySQLErrName[mysql.ErrFunctionalIndexRefAutoIncrement])
+	// ErrDependentByFunctionalIndex returns for dropping or renaming a column used by an expression index.
+	ErrDependentByFunctionalIndex = terror.ClassDDL.New(mysql.ErrDependentByFunctionalIndex, mysql.MySQLErrName[mysql.ErrDependentByFunctionalIndex])
+	// ErrDefValGeneratedVariables forbids the default value expressions to refer to variables.
+	ErrDefValGeneratedVariables = terror.ClassDDL.New(mysql.ErrDefValGeneratedVariables, mysql.MySQLErrName[mysql.ErrDefValGeneratedVariables])
+	// ErrDefValGeneratedRowValue forbids the default value expressions to refer to row values.
+	ErrDefValGeneratedRowValue = terror.ClassDDL.New(mysql.ErrDefValGeneratedRowValue, mysql.MySQLErrName[mysql.ErrDefValGeneratedRowValue])
+	// ErrDefValGeneratedFunctionIsNotAllowed returns for the unsupported expressions in the default values.
+	ErrDefValGeneratedFunctionIsNotAllowed = terror.ClassDDL.New(mysql.ErrDefValGeneratedFunctionIsNotAllowed, mysql.MySQLErrName[mysql.ErrDefValGeneratedFunctionIsNotAllowed])
+	// errUnsupportedDefaultValueRefColumn returns for the default value expressions referring to columns.
+	errUnsupportedDefaultValueRefColumn = terror.ClassDDL.New(mysql.ErrUnsupportedDDLOperation, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation], "default value expression referring to columns"))
 
 	// ErrDupKeyName returns for duplicated key name
 	ErrDupKeyName = terror.ClassDDL.New(mysql.ErrDupKeyName, mysql.MySQLErrName[mysql.ErrDupKeyName])
@@ -117,6 +139,8 @@ var (
 	ErrCantRemoveAllFields = terror.ClassDDL.New(mysql.ErrCantRemoveAllFields, mysql.MySQLErrName[mysql.ErrCantRemoveAllFields])
 	// ErrCantDropFieldOrKey returns for dropping a non-existent field or key.
 	ErrCantDropFieldOrKey = terror.ClassDDL.New(mysql.ErrCantDropFieldOrKey, mysql.MySQLErrName[mysql.ErrCantDropFieldOrKey])
+	// ErrKeyDoesNotExist returns for altering a non-existent key.
+	ErrKeyDoesNotExist = terror.ClassDDL.New(mysql.ErrKeyDoesNotExist, mysql.MySQLErrName[mysql.ErrKeyDoesNotExist])
 	// ErrInvalidOnUpdate returns for invalid ON UPDATE clause.
 	ErrInvalidOnUpdate = terror.ClassDDL.New(mysql.ErrInvalidOnUpdate, mysql.MySQLErrName[mysql.ErrInvalidOnUpdate])
 	// ErrTooLongIdent returns for too long name of database/table/column/index.
@@ -141,6 +165,8 @@ var (
 	ErrConflictingDeclarations = terror.ClassDDL.New(mysql.ErrConflictingDeclarations, fmt.Sprintf(mysql.MySQLErrName[mysql.ErrConflictingDeclarations], "CHARACTER SET ", "%s", "CHARACTER SET ", "%s"))
 	// ErrPrimaryCantHaveNull returns All parts of a PRIMARY KEY must be NOT NULL; if you need NULL in a key, use UNIQUE instead
 	ErrPrimaryCantHaveNull = terror.ClassDDL.New(mysql.ErrPrimaryCantHaveNull, mysql.MySQLErrName[mysql.ErrPrimaryCantHaveNull])
+	// ErrPKIndexCantBeInvisible returns for making the primary key invisible.
+	ErrPKIndexCantBeInvisible = terror.ClassDDL.New(mysql.ErrPKIndexCantBeInvisible, mysql.MySQLErrName[mysql.ErrPKIndexCantBeInvisible])
 	// ErrErrorOnRename returns error for wrong database name in alter table rename
 	ErrErrorOnRename = terror.ClassDDL.New(mysql.ErrErrorOnRename, mysql.MySQLErrName[mysql.ErrErrorOnRename])
 
@@ -152,6 +178,14 @@ var (
 	ErrAlterOperationNotSupported = terror.ClassDDL.New(mysql.ErrAlterOperationNotSupportedReason, mysql.MySQLErrName[mysql.ErrAlterOperationNotSupportedReason])
 	// ErrTableCantHandleFt returns FULLTEXT keys are not supported by table type
 	ErrTableCantHandleFt = terror.ClassDDL.New(mysql.ErrTableCantHandleFt, mysql.MySQLErrName[mysql.ErrTableCantHandleFt])
+	// ErrUnsupportedColumnInTTLConfig returns when the TTL column is not an integer column.
+	ErrUnsupportedColumnInTTLConfig = terror.ClassDDL.New(mysql.ErrUnsupportedColumnInTTLConfig, mysql.MySQLErrName[mysql.ErrUnsupportedColumnInTTLConfig])
+	// ErrTTLColumnCannotDrop returns when dropping the TTL column.
+	ErrTTLColumnCannotDrop = terror.ClassDDL.New(mysql.ErrTTLColumnCannotDrop, mysql.MySQLErrName[mysql.ErrTTLColumnCannotDrop])
+	// ErrSetTTLOptionForNonTTLTable returns when setting TTL_ENABLE on a table without TTL.
+	ErrSetTTLOptionForNonTTLTable = terror.ClassDDL.New(mysql.ErrSetTTLOptionForNonTTLTable, mysql.MySQLErrName[mysql.ErrSetTTLOptionForNonTTLTable])
+	// ErrOptOnCacheTable returns when the operation is unsupported on the cached tables.
+	ErrOptOnCacheTable = terror.ClassDDL.New(mysql.ErrOptOnCacheTable, mysql.MySQLErrName[mysql.ErrOptOnCacheTable])
 )
 
 // DDL is responsible for updating schema in data store and maintaining in-memory InfoSchema cache.
@@ -159,6 +193,9 @@ type DDL interface {
 	CreateSchema(ctx sessionctx.Context, name model.CIStr, charsetInfo *ast.CharsetOpt) error
 	DropSchema(ctx sessionctx.Context, schema model.CIStr) error
 	CreateTable(ctx sessionctx.Context, stmt *ast.CreateTableStmt) error
+	// CreateTableWithInfo creates a table in the schema from the table info, the
+	// table gets a new table ID while the column and index IDs are kept.
+	CreateTableWithInfo(ctx sessionctx.Context, schema model.CIStr, info *model.TableInfo) error
 	DropTable(ctx sessionctx.Context, tableIdent ast.Ident) (err error)
 	CreateIndex(ctx sessionctx.Context, tableIdent ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
 		columnNames []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error
@@ -498,6 +535,10 @@ func init() {
 		mysql.ErrCoalesceOnlyOnHashPartition:          mysql.ErrCoalesceOnlyOnHashPartition,
 		mysql.ErrCollationCharsetMismatch:             mysql.ErrCollationCharsetMismatch,
 		mysql.ErrConflictingDeclarations:              mysql.ErrConflictingDeclarations,
+		mysql.ErrDefValGeneratedFunctionIsNotAllowed:  mysql.ErrDefValGeneratedFunctionIsNotAllowed,
+		mysql.ErrDefValGeneratedRowValue:              mysql.ErrDefValGeneratedRowValue,
+		mysql.ErrDefValGeneratedVariables:             mysql.ErrDefValGeneratedVariables,
+		mysql.ErrDependentByFunctionalIndex:           mysql.ErrDependentByFunctionalIndex,
 		mysql.ErrDependentByGeneratedColumn:           mysql.ErrDependentByGeneratedColumn,
 		mysql.ErrDropLastPartition:                    mysql.ErrDropLastPartition,
 		mysql.ErrDropPartitionNonExistent:             mysql.ErrDropPartitionNonExistent,
@@ -506,6 +547,11 @@ func init() {
 		mysql.ErrFieldNotFoundPart:                    mysql.ErrFieldNotFoundPart,
 		mysql.ErrFieldTypeNotAllowedAsPartitionField:  mysql.ErrFieldTypeNotAllowedAsPartitionField,
 		mysql.ErrFileNotFound:                         mysql.ErrFileNotFound,
+		mysql.ErrFunctionalIndexFunctionIsNotAllowed:  mysql.ErrFunctionalIndexFunctionIsNotAllowed,
+		mysql.ErrFunctionalIndexOnField:               mysql.ErrFunctionalIndexOnField,
+		mysql.ErrFunctionalIndexPrimaryKey:            mysql.ErrFunctionalIndexPrimaryKey,
+		mysql.ErrFunctionalIndexRefAutoIncrement:      mysql.ErrFunctionalIndexRefAutoIncrement,
+		mysql.ErrFunctionalIndexRowValueIsNotAllowed:  mysql.ErrFunctionalIndexRowValueIsNotAllowed,
 		mysql.ErrGeneratedColumnFunctionIsNotAllowed:  mysql.ErrGeneratedColumnFunctionIsNotAllowed,
 		mysql.ErrGeneratedColumnNonPrior:              mysql.ErrGeneratedColumnNonPrior,
 		mysql.ErrGeneratedColumnRefAutoInc:            mysql.ErrGeneratedColumnRefAutoInc,
@@ -522,10 +568,12 @@ func init() {
 		mysql.ErrInvalidUseOfNull:                     mysql.ErrInvalidUseOfNull,
 		mysql.ErrJSONUsedAsKey:                        mysql.ErrJSONUsedAsKey,
 		mysql.ErrKeyColumnDoesNotExits:                mysql.ErrKeyColumnDoesNotExits,
+		mysql.ErrKeyDoesNotExist:                      mysql.ErrKeyDoesNotExist,
 		mysql.ErrLockWaitTimeout:                      mysql.ErrLockWaitTimeout,
 		mysql.ErrNoParts:                              mysql.ErrNoParts,
 		mysql.ErrNotOwner:                             mysql.ErrNotOwner,
 		mysql.ErrOnlyOnRangeListPartition:             mysql.ErrOnlyOnRangeListPartition,
+		mysql.ErrOptOnCacheTable:                      mysql.ErrOptOnCacheTable,
 		mysql.ErrPartitionColumnList:                  mysql.ErrPartitionColumnList,
 		mysql.ErrPartitionFuncNotAllowed:              mysql.ErrPartitionFuncNotAllowed,
 		mysql.ErrPartitionFunctionIsNotAllowed:        mysql.ErrPartitionFunctionIsNotAllowed,
@@ -536,14 +584,17 @@ func init() {
 		mysql.ErrPartitionWrongNoSubpart:              mysql.ErrPartitionWrongNoSubpart,
 		mysql.ErrPartitionWrongValues:                 mysql.ErrPartitionWrongValues,
 		mysql.ErrPartitionsMustBeDefined:              mysql.ErrPartitionsMustBeDefined,
+		mysql.ErrPKIndexCantBeInvisible:               mysql.ErrPKIndexCantBeInvisible,
 		mysql.ErrPrimaryCantHaveNull:                  mysql.ErrPrimaryCantHaveNull,
 		mysql.ErrRangeNotIncreasing:                   mysql.ErrRangeNotIncreasing,
 		mysql.ErrRowSinglePartitionField:              mysql.ErrRowSinglePartitionField,
 		mysql.ErrSameNamePartition:                    mysql.ErrSameNamePartition,
+		mysql.ErrSetTTLOptionForNonTTLTable:           mysql.ErrSetTTLOptionForNonTTLTable,
 		mysql.ErrSubpartition:                         mysql.ErrSubpartition,
 		mysql.ErrSystemVersioningWrongPartitions:      mysql.ErrSystemVersioningWrongPartitions,
 		mysql.ErrTableCantHandleFt:                    mysql.ErrTableCantHandleFt,
 		mysql.ErrTableMustHaveColumns:                 mysql.ErrTableMustHaveColumns,
+		mysql.ErrTTLColumnCannotDrop:                  mysql.ErrTTLColumnCannotDrop,
 		mysql.ErrTooLongIdent:                         mysql.ErrTooLongIdent,
 		mysql.ErrTooLongIndexComment:                  mysql.ErrTooLongIndexComment,
 		mysql.ErrTooLongKey:                           mysql.ErrTooLongKey,
@@ -554,6 +605,7 @@ func init() {
 		mysql.ErrUnknownCharacterSet:                  mysql.ErrUnknownCharacterSet,
 		mysql.ErrUnknownCollation:                     mysql.ErrUnknownCollation,
 		mysql.ErrUnknownPartition:                     mysql.ErrUnknownPartition,
+		mysql.ErrUnsupportedColumnInTTLConfig:         mysql.ErrUnsupportedColumnInTTLConfig,
 		mysql.ErrUnsupportedDDLOperation:              mysql.ErrUnsupportedDDLOperation,
 		mysql.ErrUnsupportedOnGeneratedColumn:         mysql.ErrUnsupportedOnGeneratedColumn,
 		mysql.ErrViewWrongList:                        mysql.ErrViewWrongList,
@@ -35,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/set"
 )
@@ -126,6 +127,9 @@ func setColumnFlagWithConstraint(colMap map[string]*table.Column, v *ast.Constra
 	switch v.Tp {
 	case ast.ConstraintPrimaryKey:
 		for _, key := range v.Keys {
+			if key.Expr != nil {
+				continue
+			}
 			c, ok := colMap[key.Column.Name.L]
 			if !ok {
 				continue
@@ -136,6 +140,9 @@ func setColumnFlagWithConstraint(colMap map[string]*table.Column, v *ast.Constra
 		}
 	case ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
 		for i, key := range v.Keys {
+			if key.Expr != nil {
+				continue
+			}
 			c, ok := colMap[key.Column.Name.L]
 			if !ok {
 				continue
@@ -154,6 +161,9 @@ func setColumnFlagWithConstraint(colMap map[string]*table.Column, v *ast.Constra
 		}
 	case ast.ConstraintKey, ast.ConstraintIndex:
 		for i, key := range v.Keys {
+			if key.Expr != nil {
+				continue
+			}
 			c, ok := colMap[key.Column.Name.L]
 			if !ok {
 				continue
@@ -475,6 +485,28 @@ func getDefaultValue(ctx sessionctx.Context, col *table.Column, c *ast.ColumnOpt
 	return v.ToString()
 }
 
+// setDefaultValueExpr sets expr as the default value of the column, which is evaluated whenever
+// the default value is used. The expression is checked by checkDefaultValue later.
+func setDefaultValueExpr(col *table.Column, expr ast.ExprNode) error {
+	if len(findColumnNamesInExpr(expr)) > 0 {
+		return errUnsupportedDefaultValueRefColumn
+	}
+	// The expression is stored as its string, so it must be formattable.
+	switch findUnformattableExpr(expr).(type) {
+	case nil:
+	case *ast.VariableExpr:
+		return ErrDefValGeneratedVariables.GenWithStackByArgs(col.Name.O)
+	case *ast.RowExpr:
+		return ErrDefValGeneratedRowValue.GenWithStackByArgs(col.Name.O)
+	default:
+		return ErrDefValGeneratedFunctionIsNotAllowed.GenWithStackByArgs(col.Name.O)
+	}
+	var sb strings.Builder
+	expr.Format(&sb)
+	col.DefaultIsExpr = true
+	return errors.Trace(col.SetDefaultValue(sb.String()))
+}
+
 func removeOnUpdateNowFlag(c *table.Column) {
 	// For timestamp Col, if it is set null or default value,
 	// OnUpdateNowFlag should be removed.
@@ -532,7 +564,7 @@ func checkPriKeyConstraint(col *table.Column, hasDefaultValue, hasNullFlag bool,
 	// Such as: create table t1 (id int , age int, primary key(id))
 	if !mysql.HasPriKeyFlag(col.Flag) && outPriKeyConstraint != nil {
 		for _, key := range outPriKeyConstraint.Keys {
-			if key.Column.Name.L != col.Name.L {
+			if key.Expr != nil || key.Column.Name.L != col.Name.L {
 				continue
 			}
 			col.Flag |= mysql.PriKeyFlag
@@ -673,7 +705,10 @@ func checkDuplicateConstraint(namesMap map[string]bool, name string) error {
 
 func setEmptyConstraintName(namesMap map[string]bool, constr *ast.Constraint) {
 	if constr.Name == "" && len(constr.Keys) > 0 {
-		colName := constr.Keys[0].Column.Name.L
+		colName := anonymousExpressionIndexName
+		if constr.Keys[0].Column != nil {
+			colName = constr.Keys[0].Column.Name.L
+		}
 		constrName := colName
 		i := 2
 		if strings.EqualFold(constrName, mysql.PrimaryKeyName) {
@@ -733,6 +768,9 @@ func buildTableInfo(ctx sessionctx.Context, d *ddl, tableName model.CIStr, cols
 			if err != nil {
 				return nil, err
 			}
+			if constr.Option != nil && constr.Option.Visibility == ast.IndexVisibilityInvisible {
+				return nil, ErrPKIndexCantBeInvisible
+			}
 			if len(constr.Keys) == 1 {
 				switch lastCol.Tp {
 				case mysql.TypeLong, mysql.TypeLonglong,
@@ -749,6 +787,17 @@ func buildTableInfo(ctx sessionctx.Context, d *ddl, tableName model.CIStr, cols
 			sc.AppendWarning(ErrTableCantHandleFt)
 			continue
 		}
+		// Build the hidden columns for the expression parts of the index.
+		hiddenCols, err := buildHiddenColumnInfo(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tbInfo.Columns)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, hiddenCol := range hiddenCols {
+			hiddenCol.ID = allocateColumnID(tbInfo)
+			hiddenCol.Offset = len(tbInfo.Columns)
+			hiddenCol.State = model.StatePublic
+			tbInfo.Columns = append(tbInfo.Columns, hiddenCol)
+		}
 		// build index info.
 		idxInfo, err := buildIndexInfo(tbInfo, model.NewCIStr(constr.Name), constr.Keys, model.StatePublic)
 		if err != nil {
@@ -775,6 +824,7 @@ func buildTableInfo(ctx sessionctx.Context, d *ddl, tableName model.CIStr, cols
 			} else {
 				idxInfo.Tp = constr.Option.Tp
 			}
+			idxInfo.Invisible = constr.Option.Visibility == ast.IndexVisibilityInvisible
 		} else {
 			// Use btree as default index type.
 			idxInfo.Tp = model.IndexTypeBtree
@@ -840,9 +890,51 @@ func buildTableInfoWithCheck(ctx sessionctx.Context, d *ddl, s *ast.CreateTableS
 	}
 	tbInfo.Charset, tbInfo.Collate = charset.GetDefaultCharsetAndCollate()
 
+	tbInfo.TTLInfo, err = buildTTLInfo(tbInfo, s.Options)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	return tbInfo, nil
 }
 
+// buildTTLInfo applies the TTL table options to the TTL config of the table, and returns the new TTL config.
+func buildTTLInfo(tbInfo *model.TableInfo, options []*ast.TableOption) (*model.TTLInfo, error) {
+	var ttlInfo *model.TTLInfo
+	if tbInfo.TTLInfo != nil {
+		ttlInfo = tbInfo.TTLInfo.Clone()
+	}
+	// TTL_ENABLE may be set before TTL in the same statement.
+	var enable *bool
+	for _, op := range options {
+		switch op.Tp {
+		case ast.TableOptionTTL:
+			colName := op.ColumnName.Name
+			col := model.FindColumnInfo(tbInfo.Columns, colName.L)
+			if col == nil {
+				return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(colName, tbInfo.Name)
+			}
+			if !mysql.IsIntegerType(col.Tp) {
+				return nil, ErrUnsupportedColumnInTTLConfig.GenWithStackByArgs(col.Name)
+			}
+			if ttlInfo == nil {
+				ttlInfo = &model.TTLInfo{Enable: true}
+			}
+			ttlInfo.ColumnName = col.Name
+			ttlInfo.IntervalValue = op.UintValue
+			ttlInfo.IntervalUnit = op.StrValue
+		case ast.TableOptionTTLEnable:
+			enable = &op.BoolValue
+		}
+	}
+	if enable != nil {
+		if ttlInfo == nil {
+			return nil, ErrSetTTLOptionForNonTTLTable.GenWithStackByArgs("TTL_ENABLE")
+		}
+		ttlInfo.Enable = *enable
+	}
+	return ttlInfo, nil
+}
+
 func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err error) {
 	ident := ast.Ident{Schema: s.Table.Schema, Name: s.Table.Name}
 	is := d.GetInfoSchemaWithInterceptor(ctx)
@@ -890,6 +982,43 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	return errors.Trace(err)
 }
 
+func (d *ddl) CreateTableWithInfo(ctx sessionctx.Context, dbName model.CIStr, info *model.TableInfo) (err error) {
+	is := d.GetInfoSchemaWithInterceptor(ctx)
+	schema, ok := is.SchemaByName(dbName)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(dbName)
+	}
+	if is.TableExists(dbName, info.Name) {
+		return infoschema.ErrTableExists.GenWithStackByArgs(ast.Ident{Schema: dbName, Name: info.Name})
+	}
+
+	tbInfo := info.Clone()
+	genIDs, err := d.genGlobalIDs(1)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tbInfo.ID = genIDs[0]
+	tbInfo.State = model.StatePublic
+	err = checkTableInfoValid(tbInfo)
+	if err != nil {
+		return err
+	}
+	tbInfo.State = model.StateNone
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionCreateTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func checkCharsetAndCollation(cs string, co string) error {
 	if !charset.ValidCharsetAndCollation(cs, co) {
 		return ErrUnknownCharacterSet.GenWithStackByArgs(cs)
@@ -931,6 +1060,11 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 	}
 
 	for _, spec := range validSpecs {
+		if spec.Tp != ast.AlterTableCache && spec.Tp != ast.AlterTableNoCache {
+			if err = d.checkCacheTableOp(ident, "Alter Table"); err != nil {
+				return errors.Trace(err)
+			}
+		}
 		switch spec.Tp {
 		case ast.AlterTableAddColumns:
 			if len(spec.NewColumns) != 1 {
@@ -965,6 +1099,16 @@ func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.A
 			err = d.ChangeColumn(ctx, ident, spec)
 		case ast.AlterTableAlterColumn:
 			err = d.AlterColumn(ctx, ident, spec)
+		case ast.AlterTableIndexInvisible:
+			err = d.AlterIndexVisibility(ctx, ident, model.NewCIStr(spec.Name), spec.Visibility)
+		case ast.AlterTableOption:
+			err = d.AlterTableTTLInfo(ctx, ident, spec.Options)
+		case ast.AlterTableRemoveTTL:
+			err = d.AlterTableRemoveTTL(ctx, ident)
+		case ast.AlterTableCache:
+			err = d.AlterTableCache(ctx, ident)
+		case ast.AlterTableNoCache:
+			err = d.AlterTableNoCache(ctx, ident)
 		case ast.AlterTablePartition:
 			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
 			err = errors.New("alter table partition is unsupported")
@@ -1082,7 +1226,7 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 		return errors.Trace(err)
 	}
 
-	col.OriginDefaultValue, err = generateOriginDefaultValue(col.ToInfo())
+	col.OriginDefaultValue, err = generateOriginDefaultValue(ctx, col.ToInfo())
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1115,7 +1259,7 @@ func (d *ddl) DropColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTa
 
 	// Check whether dropped column has existed.
 	colName := spec.OldColumnName.Name
-	col := table.FindCol(t.Cols(), colName.L)
+	col := table.FindCol(t.VisibleCols(), colName.L)
 	if col == nil {
 		err = ErrCantDropFieldOrKey.GenWithStack("column %s doesn't exist", colName)
 		if spec.IfExists {
@@ -1133,6 +1277,9 @@ func (d *ddl) DropColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTa
 	if col.IsPKHandleColumn(tblInfo) {
 		return errUnsupportedPKHandle
 	}
+	if err = checkDependedByExpressionIndex(tblInfo, colName); err != nil {
+		return errors.Trace(err)
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -1245,6 +1392,10 @@ func modifiable(origin *types.FieldType, to *types.FieldType) error {
 }
 
 func setDefaultValue(ctx sessionctx.Context, col *table.Column, option *ast.ColumnOption) (bool, error) {
+	if expr, ok := option.Expr.(*ast.ParenthesesExpr); ok {
+		return true, errors.Trace(setDefaultValueExpr(col, expr.Expr))
+	}
+	col.DefaultIsExpr = false
 	hasDefaultValue := false
 	value, err := getDefaultValue(ctx, col, option)
 	if err != nil {
@@ -1336,7 +1487,7 @@ func (d *ddl) getModifiableColumnJob(ctx sessionctx.Context, ident ast.Ident, or
 		return nil, errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
 	}
 
-	col := table.FindCol(t.Cols(), originalColName.L)
+	col := table.FindCol(t.VisibleCols(), originalColName.L)
 	if col == nil {
 		return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(originalColName, ident.Name)
 	}
@@ -1347,6 +1498,9 @@ func (d *ddl) getModifiableColumnJob(ctx sessionctx.Context, ident ast.Ident, or
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if err = checkDependedByExpressionIndex(t.Meta(), originalColName); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -1574,6 +1728,7 @@ func (d *ddl) AlterColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Alt
 	// Clean the NoDefaultValueFlag value.
 	col.Flag &= ^mysql.NoDefaultValueFlag
 	if len(specNewColumn.Options) == 0 {
+		col.DefaultIsExpr = false
 		err = col.SetDefaultValue(nil)
 		if err != nil {
 			return errors.Trace(err)
@@ -1630,6 +1785,128 @@ func (d *ddl) AlterTableComment(ctx sessionctx.Context, ident ast.Ident, spec *a
 	return errors.Trace(err)
 }
 
+// AlterTableTTLInfo changes the TTL config of the table.
+func (d *ddl) AlterTableTTLInfo(ctx sessionctx.Context, ident ast.Ident, options []*ast.TableOption) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	ttlInfo, err := buildTTLInfo(tb.Meta(), options)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTTLInfo,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{ttlInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTableRemoveTTL removes the TTL config of the table.
+func (d *ddl) AlterTableRemoveTTL(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tb.Meta().TTLInfo == nil {
+		// Nothing need to do.
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTTLRemove,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTableCache makes the table a cached table, whose data is cached in the memory of TiDB.
+func (d *ddl) AlterTableCache(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if util.IsMemOrSysDB(schema.Name.L) {
+		return errUnsupportedCacheSysTable
+	}
+	if tb.Meta().TableCacheStatusType == model.TableCacheStatusEnable {
+		// Nothing need to do.
+		return nil
+	}
+	// Check before the job is put to the queue, the table is checked again when the job runs.
+	if err = tables.CheckCachedTableSize(d.store, tb.Meta().ID); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterCacheTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTableNoCache makes the cached table a normal table.
+func (d *ddl) AlterTableNoCache(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if tb.Meta().TableCacheStatusType == model.TableCacheStatusDisable {
+		// Nothing need to do.
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterNoCacheTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// checkCacheTableOp returns an error if the table is a cached table, the DDL operations except for
+// ALTER TABLE ... NOCACHE are unsupported on the cached tables.
+func (d *ddl) checkCacheTableOp(ident ast.Ident, op string) error {
+	tb, err := d.infoHandle.Get().TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		// The error is returned by the operation itself.
+		return nil
+	}
+	if tb.Meta().TableCacheStatusType == model.TableCacheStatusEnable {
+		return ErrOptOnCacheTable.GenWithStackByArgs(op)
+	}
+	return nil
+}
+
 // AlterTableCharset changes the table charset and collate.
 func (d *ddl) AlterTableCharsetAndCollate(ctx sessionctx.Context, ident ast.Ident, toCharset, toCollate string) error {
 	// use the last one.
@@ -1745,6 +2022,9 @@ func (d *ddl) DropTable(ctx sessionctx.Context, ti ast.Ident) (err error) {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if tb.Meta().TableCacheStatusType == model.TableCacheStatusEnable {
+		return ErrOptOnCacheTable.GenWithStackByArgs("Drop Table")
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -1789,10 +2069,17 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if t.Meta().TableCacheStatusType == model.TableCacheStatusEnable {
+		return ErrOptOnCacheTable.GenWithStackByArgs("Create Index")
+	}
 
 	// Deal with anonymous index.
 	if len(indexName.L) == 0 {
-		indexName = getAnonymousIndex(t, idxColNames[0].Column.Name)
+		colName := model.NewCIStr(anonymousExpressionIndexName)
+		if idxColNames[0].Column != nil {
+			colName = idxColNames[0].Column.Name
+		}
+		indexName = getAnonymousIndex(t, colName)
 	}
 
 	if indexInfo := t.Meta().FindIndexByName(indexName.L); indexInfo != nil {
@@ -1809,12 +2096,19 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	}
 
 	tblInfo := t.Meta()
+	// Build the hidden columns for the expression parts of the index.
+	hiddenCols, err := buildHiddenColumnInfo(ctx, idxColNames, indexName, tblInfo, tblInfo.Columns)
+	if err != nil {
+		return errors.Trace(err)
+	}
 	// Check before the job is put to the queue.
 	// This check is redundant, but useful. If DDL check fail before the job is put
 	// to job queue, the fail path logic is super fast.
 	// After DDL job is put to the queue, and if the check fail, TiDB will run the DDL cancel logic.
 	// The recover step causes DDL wait a few seconds, makes the unit test painfully slow.
-	_, err = buildIndexColumns(tblInfo.Columns, idxColNames)
+	allCols := make([]*model.ColumnInfo, 0, len(tblInfo.Columns)+len(hiddenCols))
+	allCols = append(append(allCols, tblInfo.Columns...), hiddenCols...)
+	_, err = buildIndexColumns(allCols, idxColNames)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1829,7 +2123,7 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddIndex,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{unique, indexName, idxColNames, indexOption},
+		Args:       []interface{}{unique, indexName, idxColNames, indexOption, hiddenCols},
 		Priority:   ctx.GetSessionVars().DDLReorgPriority,
 	}
 
@@ -1843,6 +2137,36 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	return errors.Trace(err)
 }
 
+// AlterIndexVisibility makes the index visible or invisible to the optimizer.
+func (d *ddl) AlterIndexVisibility(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, visibility ast.IndexVisibility) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	invisible := visibility == ast.IndexVisibilityInvisible
+	if err = checkAlterIndexVisibility(t.Meta(), indexName, invisible); err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindIndexByName(indexName.L).Invisible == invisible {
+		// Nothing need to do.
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterIndexVisibility,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{indexName, invisible},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
 	return d.dropIndex(ctx, ti, false, indexName, ifExists)
 }
@@ -1857,6 +2181,9 @@ func (d *ddl) dropIndex(ctx sessionctx.Context, ti ast.Ident, isPK bool, indexNa
 	if err != nil {
 		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ti.Schema, ti.Name))
 	}
+	if t.Meta().TableCacheStatusType == model.TableCacheStatusEnable {
+		return ErrOptOnCacheTable.GenWithStackByArgs("Drop Index")
+	}
 
 	indexInfo := t.Meta().FindIndexByName(indexName.L)
 	if isPK {
@@ -1911,6 +2238,9 @@ func isDroppableColumn(tblInfo *model.TableInfo, colName model.CIStr) error {
 	if isColumnWithIndex(colName.L, tblInfo.Indices) {
 		return errCantDropColWithIndex.GenWithStack("can't drop column %s with index covered now", colName)
 	}
+	if tblInfo.TTLInfo != nil && tblInfo.TTLInfo.ColumnName.L == colName.L {
+		return ErrTTLColumnCannotDrop.GenWithStackByArgs(colName)
+	}
 	return nil
 }
 
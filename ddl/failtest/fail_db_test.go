@@ -30,6 +30,7 @@ import (
 	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/session"
@@ -136,6 +137,38 @@ func (s *testFailDBSuite) TestAddIndexFailed(c *C) {
 
 }
 
+func (s *testFailDBSuite) TestAddIndexBackfillBatchFailed(c *C) {
+	// Fail the first batches of every backfill worker, the reorganization should
+	// retry them and finish adding the index.
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/ddl/mockBackfillBatchErr", `3*return(-1)`), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/ddl/mockBackfillBatchErr"), IsNil)
+	}()
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database if not exists test_backfill_failed")
+	defer tk.MustExec("drop database test_backfill_failed")
+	tk.MustExec("use test_backfill_failed")
+
+	tk.MustExec("create table t(a bigint PRIMARY KEY, b int)")
+	for i := 0; i < 100; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values(%v, %v)", i, i))
+	}
+	tk.MustExec("alter table t add index idx_b(b)")
+	tk.MustQuery("select count(b) from t use index(idx_b) where b >= 50").Check(testkit.Rows("50"))
+
+	// The failed batches are recorded in the job, which shows they were retried.
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	jobs, err := meta.NewMeta(txn).GetAllHistoryDDLJobs()
+	c.Assert(err, IsNil)
+	c.Assert(txn.Rollback(), IsNil)
+	job := jobs[len(jobs)-1]
+	c.Assert(job.Type, Equals, model.ActionAddIndex)
+	c.Assert(job.State, Equals, model.JobStateSynced)
+	c.Assert(job.ErrorCount, Greater, int64(0))
+	c.Assert(job.Error, ErrorMatches, ".*mock backfill batch error.*")
+}
+
 // TestFailSchemaSyncer test when the schema syncer is done,
 // should prohibit DML executing until the syncer is restartd by loadSchemaInLoop.
 func (s *testFailDBSuite) TestFailSchemaSyncer(c *C) {
@@ -0,0 +1,202 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/types"
+)
+
+// expressionIndexPrefix is the prefix of the names of the hidden columns built for the expression indexes.
+const expressionIndexPrefix = "_V$"
+
+// anonymousExpressionIndexName is the name of the anonymous index whose first part is an expression.
+const anonymousExpressionIndexName = "expression_index"
+
+// buildHiddenColumnInfo builds a hidden virtual column for each expression part of the index, the
+// expression part is replaced with the hidden column, so the index is built on the columns as usual.
+// The expressions can only refer to the visible public columns in existCols.
+func buildHiddenColumnInfo(ctx sessionctx.Context, idxColNames []*ast.IndexPartSpecification, indexName model.CIStr,
+	tblInfo *model.TableInfo, existCols []*model.ColumnInfo) ([]*model.ColumnInfo, error) {
+	visibleCols := make([]*model.ColumnInfo, 0, len(existCols))
+	for _, col := range existCols {
+		if !col.Hidden && col.State == model.StatePublic {
+			visibleCols = append(visibleCols, col)
+		}
+	}
+	columns, names := expression.ColumnInfos2ColumnsAndNames(ctx, model.NewCIStr(""), tblInfo.Name, visibleCols)
+	schema := expression.NewSchema(columns...)
+
+	hiddenCols := make([]*model.ColumnInfo, 0, len(idxColNames))
+	for i, idxPart := range idxColNames {
+		if idxPart.Expr == nil {
+			continue
+		}
+		if _, ok := idxPart.Expr.(*ast.ColumnNameExpr); ok {
+			return nil, errors.Trace(ErrFunctionalIndexOnField)
+		}
+		// The expression is stored as its string, so it must be formattable.
+		switch findUnformattableExpr(idxPart.Expr).(type) {
+		case nil:
+		case *ast.RowExpr:
+			return nil, ErrFunctionalIndexRowValueIsNotAllowed.GenWithStackByArgs(indexName.O)
+		default:
+			return nil, ErrFunctionalIndexFunctionIsNotAllowed.GenWithStackByArgs(indexName.O)
+		}
+		expr, err := expression.RewriteAstExpr(ctx, idxPart.Expr, schema, names)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if expression.IsMutableEffectsExpr(expr) {
+			return nil, ErrFunctionalIndexFunctionIsNotAllowed.GenWithStackByArgs(indexName.O)
+		}
+		// The columns are resolved by the table being read, which may be aliased in the statements.
+		for _, name := range findColumnNamesInExpr(idxPart.Expr) {
+			name.Schema, name.Table = model.CIStr{}, model.CIStr{}
+			// The auto_increment values may be allocated after the expressions are evaluated by insert.
+			if col := model.FindColumnInfo(visibleCols, name.Name.L); col != nil && mysql.HasAutoIncrementFlag(col.Flag) {
+				return nil, ErrFunctionalIndexRefAutoIncrement.GenWithStackByArgs(indexName.O)
+			}
+		}
+		var sb strings.Builder
+		idxPart.Expr.Format(&sb)
+
+		colInfo := &model.ColumnInfo{
+			Name:                model.NewCIStr(fmt.Sprintf("%s_%s_%d", expressionIndexPrefix, indexName.O, i)),
+			FieldType:           *expr.GetType(),
+			Hidden:              true,
+			GeneratedExprString: sb.String(),
+			Version:             model.CurrLatestColumnInfoVersion,
+		}
+		// The hidden columns are not stored, the rows written before the index is added don't have them either.
+		colInfo.Flag &= ^mysql.NotNullFlag
+		hiddenCols = append(hiddenCols, colInfo)
+		idxPart.Column = &ast.ColumnName{Name: colInfo.Name}
+		idxPart.Expr = nil
+		idxPart.Length = types.UnspecifiedLength
+	}
+	return hiddenCols, nil
+}
+
+// findColumnNamesInExpr returns the names of the columns referred by the expression.
+func findColumnNamesInExpr(expr ast.ExprNode) []*ast.ColumnName {
+	c := &columnNameExtractor{}
+	expr.Accept(c)
+	return c.names
+}
+
+type columnNameExtractor struct {
+	names []*ast.ColumnName
+}
+
+// Enter implements ast.Visitor interface.
+func (c *columnNameExtractor) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
+	return inNode, false
+}
+
+// Leave implements ast.Visitor interface.
+func (c *columnNameExtractor) Leave(inNode ast.Node) (node ast.Node, ok bool) {
+	if colNameExpr, ok := inNode.(*ast.ColumnNameExpr); ok {
+		c.names = append(c.names, colNameExpr.Name)
+	}
+	return inNode, true
+}
+
+// findUnformattableExpr returns the first node of the expression which can't be formatted into the
+// expression string of a column, such as the subqueries, the row values and the variables, or nil if
+// there is none.
+func findUnformattableExpr(expr ast.ExprNode) ast.Node {
+	f := &unformattableExprFinder{}
+	expr.Accept(f)
+	return f.found
+}
+
+type unformattableExprFinder struct {
+	found ast.Node
+}
+
+// Enter implements ast.Visitor interface.
+func (f *unformattableExprFinder) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
+	if f.found != nil {
+		return inNode, true
+	}
+	switch inNode.(type) {
+	case *ast.SubqueryExpr, *ast.RowExpr, *ast.VariableExpr, *ast.DefaultExpr, *ast.ValuesExpr, *ast.AggregateFuncExpr:
+		f.found = inNode
+		return inNode, true
+	}
+	return inNode, false
+}
+
+// Leave implements ast.Visitor interface.
+func (f *unformattableExprFinder) Leave(inNode ast.Node) (node ast.Node, ok bool) {
+	return inNode, true
+}
+
+// checkDependedByExpressionIndex checks whether the column is referred by the hidden columns of the
+// expression indexes, such a column can't be dropped or renamed.
+func checkDependedByExpressionIndex(tblInfo *model.TableInfo, colName model.CIStr) error {
+	for _, col := range tblInfo.Columns {
+		if !col.Hidden || !col.IsGenerated() {
+			continue
+		}
+		expr, err := tables.ParseExpression(col.GeneratedExprString)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, name := range findColumnNamesInExpr(expr) {
+			if name.Name.L == colName.L {
+				return ErrDependentByFunctionalIndex.GenWithStackByArgs(colName.O)
+			}
+		}
+	}
+	return nil
+}
+
+// removeDependentHiddenColumns removes the hidden columns built for the expression parts of the
+// dropped index, the offsets of the remaining columns are adjusted.
+func removeDependentHiddenColumns(tblInfo *model.TableInfo, idxInfo *model.IndexInfo) {
+	hiddenColNames := make(map[string]struct{}, len(idxInfo.Columns))
+	for _, idxCol := range idxInfo.Columns {
+		if tblInfo.Columns[idxCol.Offset].Hidden {
+			hiddenColNames[idxCol.Name.L] = struct{}{}
+		}
+	}
+	if len(hiddenColNames) == 0 {
+		return
+	}
+	newCols := make([]*model.ColumnInfo, 0, len(tblInfo.Columns))
+	for _, col := range tblInfo.Columns {
+		if _, ok := hiddenColNames[col.Name.L]; ok {
+			continue
+		}
+		col.Offset = len(newCols)
+		newCols = append(newCols, col)
+	}
+	tblInfo.Columns = newCols
+	for _, idx := range tblInfo.Indices {
+		for _, idxCol := range idx.Columns {
+			idxCol.Offset = model.FindColumnInfo(newCols, idxCol.Name.L).Offset
+		}
+	}
+}
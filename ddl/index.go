@@ -82,6 +82,7 @@ func buildIndexColumns(columns []*model.ColumnInfo, idxColNames []*ast.IndexPart
 			Name:   col.Name,
 			Offset: col.Offset,
 			Length: ic.Length,
+			Desc:   ic.Desc,
 		})
 	}
 
@@ -91,6 +92,9 @@ func buildIndexColumns(columns []*model.ColumnInfo, idxColNames []*ast.IndexPart
 func checkPKOnGeneratedColumn(tblInfo *model.TableInfo, idxColNames []*ast.IndexPartSpecification) (*model.ColumnInfo, error) {
 	var lastCol *model.ColumnInfo
 	for _, colName := range idxColNames {
+		if colName.Expr != nil {
+			return nil, errors.Trace(ErrFunctionalIndexPrimaryKey)
+		}
 		lastCol = getColumnInfoByName(tblInfo, colName.Column.Name.L)
 		if lastCol == nil {
 			return nil, errKeyColumnDoesNotExits.GenWithStackByArgs(colName.Column.Name)
@@ -321,12 +325,13 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 		indexOption *ast.IndexOption
 		sqlMode     mysql.SQLMode
 		warnings    []string
+		hiddenCols  []*model.ColumnInfo
 	)
 	if isPK {
 		// Notice: sqlMode and warnings is used to support non-strict mode.
 		err = job.DecodeArgs(&unique, &indexName, &idxColNames, &indexOption, &sqlMode, &warnings)
 	} else {
-		err = job.DecodeArgs(&unique, &indexName, &idxColNames, &indexOption)
+		err = job.DecodeArgs(&unique, &indexName, &idxColNames, &indexOption, &hiddenCols)
 	}
 	if err != nil {
 		job.State = model.JobStateCancelled
@@ -344,6 +349,17 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	}
 
 	if indexInfo == nil {
+		// The hidden columns of the expression index are public at once, they are not stored in the rows.
+		for _, hiddenCol := range hiddenCols {
+			if columnInfo := model.FindColumnInfo(tblInfo.Columns, hiddenCol.Name.L); columnInfo != nil {
+				job.State = model.JobStateCancelled
+				return ver, infoschema.ErrColumnExists.GenWithStackByArgs(hiddenCol.Name)
+			}
+			hiddenCol.ID = allocateColumnID(tblInfo)
+			hiddenCol.Offset = len(tblInfo.Columns)
+			hiddenCol.State = model.StatePublic
+			tblInfo.Columns = append(tblInfo.Columns, hiddenCol)
+		}
 		indexInfo, err = buildIndexInfo(tblInfo, indexName, idxColNames, model.StateNone)
 		if err != nil {
 			job.State = model.JobStateCancelled
@@ -357,6 +373,7 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 			} else {
 				indexInfo.Tp = indexOption.Tp
 			}
+			indexInfo.Invisible = indexOption.Visibility == ast.IndexVisibilityInvisible
 		} else {
 			// Use btree as default index type.
 			indexInfo.Tp = model.IndexTypeBtree
@@ -498,6 +515,7 @@ func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 		tblInfo.Indices = newIndices
 		// Set column index flag.
 		dropIndexColumnFlag(tblInfo, indexInfo)
+		removeDependentHiddenColumns(tblInfo, indexInfo)
 
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != model.StateNone)
 		if err != nil {
@@ -549,6 +567,47 @@ func checkDropIndex(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Inde
 	return tblInfo, indexInfo, nil
 }
 
+func onAlterIndexVisibility(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var (
+		indexName model.CIStr
+		invisible bool
+	)
+	if err := job.DecodeArgs(&indexName, &invisible); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if err = checkAlterIndexVisibility(tblInfo, indexName, invisible); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	tblInfo.FindIndexByName(indexName.L).Invisible = invisible
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return ver, nil
+}
+
+// checkAlterIndexVisibility checks whether the visibility of the index can be changed, the
+// primary key can't be invisible.
+func checkAlterIndexVisibility(tblInfo *model.TableInfo, indexName model.CIStr, invisible bool) error {
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil || indexInfo.State != model.StatePublic {
+		return ErrKeyDoesNotExist.GenWithStackByArgs(indexName.O, tblInfo.Name.O)
+	}
+	if invisible && indexInfo.Primary {
+		return ErrPKIndexCantBeInvisible
+	}
+	return nil
+}
+
 func checkDropIndexOnAutoIncrementColumn(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) error {
 	cols := tblInfo.Columns
 	for _, idxCol := range indexInfo.Columns {
@@ -939,6 +998,14 @@ func (w *addIndexWorker) handleBackfillTask(d *ddlCtx, task *reorgIndexTask) *ad
 			return result
 		}
 
+		failpoint.Inject("mockBackfillBatchErr", func(val failpoint.Value) {
+			// The value is the worker ID to fail, -1 fails every worker.
+			if id := val.(int); id < 0 || id == w.id {
+				result.err = errors.Errorf("mock backfill batch error, worker ID %d", w.id)
+				failpoint.Return(result)
+			}
+		})
+
 		taskCtx, err := w.backfillIndexInTxn(handleRange)
 		if err != nil {
 			result.err = err
@@ -998,14 +1065,14 @@ func (w *addIndexWorker) run(d *ddlCtx) {
 	logutil.BgLogger().Info("[ddl] add index worker exit", zap.Int("workerID", w.id))
 }
 
-func makeupDecodeColMap(t table.Table, indexInfo *model.IndexInfo) (map[int64]decoder.Column, error) {
+func makeupDecodeColMap(sessCtx sessionctx.Context, t table.Table, indexInfo *model.IndexInfo) (map[int64]decoder.Column, error) {
 	cols := t.Cols()
 	indexedCols := make([]*table.Column, len(indexInfo.Columns))
 	for i, v := range indexInfo.Columns {
 		indexedCols[i] = cols[v.Offset]
 	}
 
-	decodeColMap, err := decoder.BuildFullDecodeColMap(indexedCols)
+	decodeColMap, err := decoder.BuildFullDecodeColMap(sessCtx, t, indexedCols)
 	if err != nil {
 		return nil, err
 	}
@@ -1206,10 +1273,6 @@ func (w *worker) addPhysicalTableIndex(t table.PhysicalTable, indexInfo *model.I
 	totalAddedCount := job.GetRowCount()
 
 	startHandle, endHandle := reorgInfo.StartHandle, reorgInfo.EndHandle
-	decodeColMap, err := makeupDecodeColMap(t, indexInfo)
-	if err != nil {
-		return errors.Trace(err)
-	}
 
 	// variable.ddlReorgWorkerCounter can be modified by system variable "tidb_ddl_reorg_worker_cnt".
 	workerCnt := variable.GetDDLReorgWorkerCounter()
@@ -1236,6 +1299,11 @@ func (w *worker) addPhysicalTableIndex(t table.PhysicalTable, indexInfo *model.I
 		// Enlarge the worker size.
 		for i := len(idxWorkers); i < int(workerCnt); i++ {
 			sessCtx := newContext(reorgInfo.d.store)
+			// The generated column expressions are built for each worker, they are evaluated concurrently.
+			decodeColMap, err := makeupDecodeColMap(sessCtx, t, indexInfo)
+			if err != nil {
+				return errors.Trace(err)
+			}
 			idxWorker := newAddIndexWorker(sessCtx, w, i, t, indexInfo, decodeColMap)
 			idxWorker.priority = job.Priority
 			idxWorkers = append(idxWorkers, idxWorker)
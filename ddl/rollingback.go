@@ -243,7 +243,8 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		err = rollingbackDropTableOrView(t, job)
 	case model.ActionDropSchema:
 		err = rollingbackDropSchema(t, job)
-	case model.ActionShardRowID,
+	case model.ActionShardRowID, model.ActionAlterIndexVisibility, model.ActionAlterTTLInfo, model.ActionAlterTTLRemove,
+		model.ActionAlterCacheTable, model.ActionAlterNoCacheTable,
 		model.ActionModifyColumn,
 		model.ActionModifyTableCharsetAndCollate, model.ActionModifySchemaCharsetAndCollate:
 		ver, err = cancelOnlyNotHandledJob(job)
@@ -15,6 +15,7 @@ package distsql
 
 import (
 	"context"
+	"unsafe"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
@@ -58,3 +59,22 @@ func Analyze(ctx context.Context, client kv.Client, kvReq *kv.Request, vars *kv.
 	}
 	return result, nil
 }
+
+// CanUseChunkRPC indicates whether the coprocessor responses can be requested
+// and decoded in the chunk format.
+func CanUseChunkRPC(ctx sessionctx.Context) bool {
+	if !ctx.GetSessionVars().EnableChunkRPC {
+		return false
+	}
+	return isLittleEndian()
+}
+
+// nativeEndianProbe is used to detect the byte order of the machine.
+var nativeEndianProbe uint16 = 1
+
+// isLittleEndian checks the byte order of the machine. The fixed-length data
+// of the chunk format is the memory layout of the columns and the lengths are
+// encoded in little endian, so it is only decoded on little endian machines.
+func isLittleEndian() bool {
+	return *(*byte)(unsafe.Pointer(&nativeEndianProbe)) == 1
+}
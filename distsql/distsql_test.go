@@ -16,6 +16,7 @@ package distsql
 import (
 	"context"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/cznic/mathutil"
@@ -28,12 +29,17 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
 func (s *testSuite) createSelectNormal(batch, totalRows int, c *C) (*selectResult, []*types.FieldType) {
+	return s.createSelectWithEncodeType(batch, totalRows, tipb.EncodeType_TypeDefault, c)
+}
+
+func (s *testSuite) createSelectWithEncodeType(batch, totalRows int, encodeType tipb.EncodeType, c *C) (*selectResult, []*types.FieldType) {
 	request, err := (&RequestBuilder{}).SetKeyRanges(nil).
-		SetDAGRequest(&tipb.DAGRequest{}).
+		SetDAGRequest(&tipb.DAGRequest{EncodeType: encodeType}).
 		SetDesc(false).
 		SetKeepOrder(false).
 		SetFromSessionVars(variable.NewSessionVars()).
@@ -41,19 +47,7 @@ func (s *testSuite) createSelectNormal(batch, totalRows int, c *C) (*selectResul
 	c.Assert(err, IsNil)
 
 	/// 4 int64 types.
-	colTypes := []*types.FieldType{
-		{
-			Tp:      mysql.TypeLonglong,
-			Flen:    mysql.MaxIntWidth,
-			Decimal: 0,
-			Flag:    mysql.BinaryFlag,
-			Charset: charset.CharsetBin,
-			Collate: charset.CollationBin,
-		},
-	}
-	colTypes = append(colTypes, colTypes[0])
-	colTypes = append(colTypes, colTypes[0])
-	colTypes = append(colTypes, colTypes[0])
+	colTypes := mockIntTypes(4)
 
 	// Test Next.
 	var response SelectResult
@@ -69,6 +63,7 @@ func (s *testSuite) createSelectNormal(batch, totalRows int, c *C) (*selectResul
 	c.Assert(ok, IsTrue)
 	resp.total = totalRows
 	resp.batch = batch
+	resp.encodeType = encodeType
 
 	return result, colTypes
 }
@@ -92,6 +87,38 @@ func (s *testSuite) TestSelectNormal(c *C) {
 	c.Assert(err, IsNil)
 }
 
+func (s *testSuite) TestSelectResultChunkEncode(c *C) {
+	for _, encodeType := range []tipb.EncodeType{tipb.EncodeType_TypeDefault, tipb.EncodeType_TypeChunk} {
+		// The responses have 100 rows, they are read into chunks with 32 required rows.
+		response, colTypes := s.createSelectWithEncodeType(100, 250, encodeType, c)
+		chk := chunk.New(colTypes, 32, 32)
+		numAllRows := 0
+		for {
+			err := response.Next(context.TODO(), chk)
+			c.Assert(err, IsNil)
+			if chk.NumRows() == 0 {
+				break
+			}
+			for i := 0; i < chk.NumRows(); i++ {
+				for j := range colTypes {
+					c.Assert(chk.GetRow(i).GetInt64(j), Equals, int64(1))
+				}
+			}
+			numAllRows += chk.NumRows()
+		}
+		c.Assert(numAllRows, Equals, 250)
+		c.Assert(response.Close(), IsNil)
+	}
+}
+
+func (s *testSuite) TestCanUseChunkRPC(c *C) {
+	sctx := mock.NewContext()
+	sctx.GetSessionVars().EnableChunkRPC = true
+	c.Assert(CanUseChunkRPC(sctx), Equals, isLittleEndian())
+	sctx.GetSessionVars().EnableChunkRPC = false
+	c.Assert(CanUseChunkRPC(sctx), IsFalse)
+}
+
 func (s *testSuite) TestAnalyze(c *C) {
 	request, err := (&RequestBuilder{}).SetKeyRanges(nil).
 		SetAnalyzeRequest(&tipb.AnalyzeReq{}).
@@ -120,7 +147,11 @@ type mockResponse struct {
 	count int
 	total int
 	batch int
-	ctx   sessionctx.Context
+	// encodeType is the encoding of the rows in the responses.
+	encodeType tipb.EncodeType
+	// colTypes is the types of the rows, there are 4 int64 columns if it's nil.
+	colTypes []*types.FieldType
+	ctx      sessionctx.Context
 	sync.Mutex
 }
 
@@ -144,20 +175,40 @@ func (resp *mockResponse) Next(ctx context.Context) (kv.ResultSubset, error) {
 	numRows := mathutil.Min(resp.batch, resp.total-resp.count)
 	resp.count += numRows
 
+	colTypes := resp.colTypes
+	if colTypes == nil {
+		colTypes = mockIntTypes(4)
+	}
+	datums := make([]types.Datum, 0, len(colTypes))
+	for _, ft := range colTypes {
+		datums = append(datums, mockDatum(ft))
+	}
+
 	var chunks []tipb.Chunk
-	datum := types.NewIntDatum(1)
-	bytes := make([]byte, 0, 100)
-	bytes, _ = codec.EncodeValue(nil, bytes, datum, datum, datum, datum)
-	chunks = make([]tipb.Chunk, numRows)
-	for i := range chunks {
-		chkData := make([]byte, len(bytes))
-		copy(chkData, bytes)
-		chunks[i] = tipb.Chunk{RowsData: chkData}
+	switch resp.encodeType {
+	case tipb.EncodeType_TypeDefault:
+		bytes := make([]byte, 0, 100)
+		bytes, _ = codec.EncodeValue(nil, bytes, datums...)
+		chunks = make([]tipb.Chunk, numRows)
+		for i := range chunks {
+			chkData := make([]byte, len(bytes))
+			copy(chkData, bytes)
+			chunks[i] = tipb.Chunk{RowsData: chkData}
+		}
+	case tipb.EncodeType_TypeChunk:
+		chk := chunk.NewChunkWithCapacity(colTypes, numRows)
+		for i := 0; i < numRows; i++ {
+			for j := range datums {
+				chk.AppendDatum(j, &datums[j])
+			}
+		}
+		chunks = []tipb.Chunk{{RowsData: chunk.NewCodec(colTypes).Encode(chk)}}
 	}
 
 	respPB := &tipb.SelectResponse{
 		Chunks:       chunks,
 		OutputCounts: []int64{1},
+		EncodeType:   resp.encodeType,
 	}
 	respBytes, err := respPB.Marshal()
 	if err != nil {
@@ -166,6 +217,32 @@ func (resp *mockResponse) Next(ctx context.Context) (kv.ResultSubset, error) {
 	return &mockResultSubset{respBytes}, nil
 }
 
+func mockIntTypes(n int) []*types.FieldType {
+	colTypes := make([]*types.FieldType, 0, n)
+	for i := 0; i < n; i++ {
+		colTypes = append(colTypes, &types.FieldType{
+			Tp:      mysql.TypeLonglong,
+			Flen:    mysql.MaxIntWidth,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+			Charset: charset.CharsetBin,
+			Collate: charset.CollationBin,
+		})
+	}
+	return colTypes
+}
+
+// mockDatum returns the value of the column of type ft in every row.
+func mockDatum(ft *types.FieldType) types.Datum {
+	switch ft.Tp {
+	case mysql.TypeDouble:
+		return types.NewFloat64Datum(1.5)
+	case mysql.TypeVarchar:
+		return types.NewStringDatum("abcdefghij")
+	}
+	return types.NewIntDatum(1)
+}
+
 // mockResultSubset implements kv.ResultSubset interface.
 // Used only for test.
 type mockResultSubset struct{ data []byte }
@@ -178,3 +255,81 @@ func (r *mockResultSubset) MemSize() int64 { return int64(cap(r.data)) }
 
 // RespTime implements kv.ResultSubset interface.
 func (r *mockResultSubset) RespTime() time.Duration { return 0 }
+
+// cachedResponse implements kv.Response interface, it returns the prepared
+// results so that the benchmarks only measure the decoding.
+type cachedResponse struct {
+	subsets []kv.ResultSubset
+	idx     int
+}
+
+// Next implements kv.Response interface.
+func (resp *cachedResponse) Next(ctx context.Context) (kv.ResultSubset, error) {
+	if resp.idx >= len(resp.subsets) {
+		return nil, nil
+	}
+	resp.idx++
+	return resp.subsets[resp.idx-1], nil
+}
+
+// Close implements kv.Response interface.
+func (resp *cachedResponse) Close() error {
+	return nil
+}
+
+func benchmarkSelectResult(b *testing.B, encodeType tipb.EncodeType) {
+	// A wide scan with 32 columns of int, double and varchar.
+	colTypes := make([]*types.FieldType, 0, 32)
+	for i := 0; i < 32; i++ {
+		switch i % 3 {
+		case 0:
+			colTypes = append(colTypes, mockIntTypes(1)[0])
+		case 1:
+			colTypes = append(colTypes, types.NewFieldType(mysql.TypeDouble))
+		case 2:
+			colTypes = append(colTypes, types.NewFieldType(mysql.TypeVarchar))
+		}
+	}
+	sctx := mock.NewContext()
+	mockResp := &mockResponse{total: 8192, batch: 1024, encodeType: encodeType, colTypes: colTypes, ctx: sctx}
+	var subsets []kv.ResultSubset
+	for {
+		subset, err := mockResp.Next(context.TODO())
+		if err != nil {
+			b.Fatal(err)
+		}
+		if subset == nil {
+			break
+		}
+		subsets = append(subsets, subset)
+	}
+
+	chk := chunk.New(colTypes, 1024, 1024)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := &selectResult{
+			label:      "dag",
+			resp:       &cachedResponse{subsets: subsets},
+			rowLen:     len(colTypes),
+			fieldTypes: colTypes,
+			ctx:        sctx,
+		}
+		for {
+			if err := r.Next(context.TODO(), chk); err != nil {
+				b.Fatal(err)
+			}
+			if chk.NumRows() == 0 {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkSelectResultDefaultEncode(b *testing.B) {
+	benchmarkSelectResult(b, tipb.EncodeType_TypeDefault)
+}
+
+func BenchmarkSelectResultChunkEncode(b *testing.B) {
+	benchmarkSelectResult(b, tipb.EncodeType_TypeChunk)
+}
@@ -14,7 +14,9 @@
 package distsql
 
 import (
+	"bytes"
 	"math"
+	"sort"
 
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -123,6 +125,9 @@ func (builder *RequestBuilder) SetFromSessionVars(sv *variable.SessionVars) *Req
 	builder.Request.IsolationLevel = builder.getIsolationLevel()
 	builder.Request.NotFillCache = sv.StmtCtx.NotFillCache
 	builder.Request.ReplicaRead = sv.GetReplicaRead()
+	builder.Request.Paging = sv.EnablePaging
+	builder.Request.MinPagingSize = uint64(sv.MinPagingSize)
+	builder.Request.MaxPagingSize = uint64(sv.MaxPagingSize)
 	return builder
 }
 
@@ -181,9 +186,15 @@ func TableHandlesToKVRanges(tid int64, handles []int64) []kv.KeyRange {
 
 // IndexRangesToKVRanges converts index ranges to "KeyRange".
 func IndexRangesToKVRanges(sc *stmtctx.StatementContext, tid, idxID int64, ranges []*ranger.Range) ([]kv.KeyRange, error) {
+	return IndexRangesToKVRangesWithDesc(sc, tid, idxID, nil, ranges)
+}
+
+// IndexRangesToKVRangesWithDesc converts index ranges to "KeyRange" for an index
+// whose i-th column is in descending order if descs[i] is true.
+func IndexRangesToKVRangesWithDesc(sc *stmtctx.StatementContext, tid, idxID int64, descs []bool, ranges []*ranger.Range) ([]kv.KeyRange, error) {
 	krs := make([]kv.KeyRange, 0, len(ranges))
 	for _, ran := range ranges {
-		low, high, err := encodeIndexKey(sc, ran)
+		low, high, err := encodeIndexKey(sc, ran, descs)
 		if err != nil {
 			return nil, err
 		}
@@ -191,18 +202,53 @@ func IndexRangesToKVRanges(sc *stmtctx.StatementContext, tid, idxID int64, range
 		endKey := tablecodec.EncodeIndexSeekKey(tid, idxID, high)
 		krs = append(krs, kv.KeyRange{StartKey: startKey, EndKey: endKey})
 	}
+	if len(descs) > 0 {
+		// The ranges are sorted by the values, but the keys of the descending
+		// columns are in the reverse order.
+		sort.Slice(krs, func(i, j int) bool {
+			return bytes.Compare(krs[i].StartKey, krs[j].StartKey) < 0
+		})
+	}
 	return krs, nil
 }
 
-func encodeIndexKey(sc *stmtctx.StatementContext, ran *ranger.Range) ([]byte, []byte, error) {
-	low, err := codec.EncodeKey(sc, nil, ran.LowVal...)
+// reverseDescRange reverses the range if the column it varies on is in descending order,
+// so that its low bound is still encoded to the smaller key.
+func reverseDescRange(sc *stmtctx.StatementContext, ran *ranger.Range, descs []bool) (*ranger.Range, error) {
+	i, err := ran.PrefixEqualLen(sc)
+	if err != nil {
+		return nil, err
+	}
+	if i == len(ran.LowVal) {
+		i--
+	}
+	if i < 0 || i >= len(descs) || !descs[i] {
+		return ran, nil
+	}
+	return &ranger.Range{
+		LowVal:      ran.HighVal,
+		HighVal:     ran.LowVal,
+		LowExclude:  ran.HighExclude,
+		HighExclude: ran.LowExclude,
+	}, nil
+}
+
+func encodeIndexKey(sc *stmtctx.StatementContext, ran *ranger.Range, descs []bool) ([]byte, []byte, error) {
+	if len(descs) > 0 {
+		var err error
+		ran, err = reverseDescRange(sc, ran, descs)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	low, err := codec.EncodeKeyWithDesc(sc, nil, descs, ran.LowVal...)
 	if err != nil {
 		return nil, nil, err
 	}
 	if ran.LowExclude {
 		low = []byte(kv.Key(low).PrefixNext())
 	}
-	high, err := codec.EncodeKey(sc, nil, ran.HighVal...)
+	high, err := codec.EncodeKeyWithDesc(sc, nil, descs, ran.HighVal...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -308,6 +308,8 @@ func (s *testSuite) TestRequestBuilder1(c *C) {
 		NotFillCache:   false,
 		SyncLog:        false,
 		ReplicaRead:    kv.ReplicaReadLeader,
+		MinPagingSize:  variable.DefMinPagingSize,
+		MaxPagingSize:  variable.DefMaxPagingSize,
 	}
 	c.Assert(actual, DeepEquals, expect)
 }
@@ -381,6 +383,8 @@ func (s *testSuite) TestRequestBuilder2(c *C) {
 		NotFillCache:   false,
 		SyncLog:        false,
 		ReplicaRead:    kv.ReplicaReadLeader,
+		MinPagingSize:  variable.DefMinPagingSize,
+		MaxPagingSize:  variable.DefMaxPagingSize,
 	}
 	c.Assert(actual, DeepEquals, expect)
 }
@@ -424,6 +428,8 @@ func (s *testSuite) TestRequestBuilder3(c *C) {
 		NotFillCache:   false,
 		SyncLog:        false,
 		ReplicaRead:    kv.ReplicaReadLeader,
+		MinPagingSize:  variable.DefMinPagingSize,
+		MaxPagingSize:  variable.DefMaxPagingSize,
 	}
 	c.Assert(actual, DeepEquals, expect)
 }
@@ -467,6 +473,8 @@ func (s *testSuite) TestRequestBuilder4(c *C) {
 		NotFillCache:   false,
 		SyncLog:        false,
 		ReplicaRead:    kv.ReplicaReadLeader,
+		MinPagingSize:  variable.DefMinPagingSize,
+		MaxPagingSize:  variable.DefMaxPagingSize,
 	}
 	c.Assert(actual, DeepEquals, expect)
 }
@@ -534,6 +542,8 @@ func (s *testSuite) TestRequestBuilder7(c *C) {
 		NotFillCache:   false,
 		SyncLog:        false,
 		ReplicaRead:    kv.ReplicaReadFollower,
+		MinPagingSize:  variable.DefMinPagingSize,
+		MaxPagingSize:  variable.DefMaxPagingSize,
 	}
 
 	c.Assert(actual, DeepEquals, expect)
@@ -50,6 +50,8 @@ type selectResult struct {
 	selectResp     *tipb.SelectResponse
 	selectRespSize int // record the selectResp.Size() when it is initialized.
 	respChkIdx     int
+	// respChunkDecoder decodes the responses in the chunk format.
+	respChunkDecoder *chunk.Decoder
 
 	partialCount int64 // number of partial results.
 
@@ -110,6 +112,16 @@ func (r *selectResult) Next(ctx context.Context, chk *chunk.Chunk) error {
 			return nil
 		}
 	}
+	switch r.selectResp.GetEncodeType() {
+	case tipb.EncodeType_TypeDefault:
+		return r.readFromDefault(ctx, chk)
+	case tipb.EncodeType_TypeChunk:
+		return r.readFromChunk(ctx, chk)
+	}
+	return errors.Errorf("unsupported encode type:%v", r.selectResp.GetEncodeType())
+}
+
+func (r *selectResult) readFromDefault(ctx context.Context, chk *chunk.Chunk) error {
 	for !chk.IsFull() {
 		if r.respChkIdx == len(r.selectResp.Chunks) {
 			err := r.fetchResp(ctx)
@@ -128,6 +140,46 @@ func (r *selectResult) Next(ctx context.Context, chk *chunk.Chunk) error {
 	return nil
 }
 
+// readFromChunk decodes the responses in the chunk format column by column.
+func (r *selectResult) readFromChunk(ctx context.Context, chk *chunk.Chunk) error {
+	if r.respChunkDecoder == nil {
+		r.respChunkDecoder = chunk.NewDecoder(
+			chunk.NewChunkWithCapacity(r.fieldTypes, 0),
+			r.fieldTypes,
+		)
+	}
+
+	for !chk.IsFull() {
+		if r.respChkIdx == len(r.selectResp.Chunks) {
+			err := r.fetchResp(ctx)
+			if err != nil || r.selectResp == nil {
+				return err
+			}
+		}
+
+		if r.respChunkDecoder.IsFinished() {
+			r.respChunkDecoder.Reset(r.selectResp.Chunks[r.respChkIdx].RowsData)
+		}
+		// If the remained rows are more than 80% of the required rows but not
+		// more than the required rows, swap the columns of the decoded chunk into
+		// chk to avoid copying the data, otherwise append the data to chk.
+		remainedRows := r.respChunkDecoder.RemainedRows()
+		if remainedRows > int(float64(chk.RequiredRows())*0.8) && remainedRows <= chk.RequiredRows() {
+			if chk.NumRows() > 0 {
+				return nil
+			}
+			r.respChunkDecoder.ReuseIntermChk(chk)
+			r.respChkIdx++
+			return nil
+		}
+		r.respChunkDecoder.Decode(chk)
+		if r.respChunkDecoder.IsFinished() {
+			r.respChkIdx++
+		}
+	}
+	return nil
+}
+
 // NextRaw returns the next raw partial result.
 func (r *selectResult) NextRaw(ctx context.Context) (data []byte, err error) {
 	resultSubset, err := r.resp.Next(ctx)
@@ -24,6 +24,7 @@ import (
 	"github.com/ngaut/pools"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/bindinfo"
 	"github.com/pingcap/tidb/ddl"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
@@ -57,6 +58,8 @@ type Domain struct {
 	exit            chan struct{}
 	etcdClient      *clientv3.Client
 	gvc             GlobalVariableCache
+	globalVarsCh    chan struct{}
+	baselineHandle  *bindinfo.Handle
 	wg              sync.WaitGroup
 }
 
@@ -243,6 +246,17 @@ func (do *Domain) InfoSchema() infoschema.InfoSchema {
 	return do.infoHandle.Get()
 }
 
+// GetSnapshotInfoSchema gets the information schema at snapshotTS.
+func (do *Domain) GetSnapshotInfoSchema(snapshotTS uint64) (infoschema.InfoSchema, error) {
+	snapHandle := do.infoHandle.EmptyClone()
+	// The handle is empty, so the schema is fully loaded at snapshotTS.
+	_, _, _, err := do.loadInfoSchema(snapHandle, initialVersion, snapshotTS)
+	if err != nil {
+		return nil, err
+	}
+	return snapHandle.Get(), nil
+}
+
 // DDL gets DDL from domain.
 func (do *Domain) DDL() ddl.DDL {
 	return do.ddl
@@ -464,9 +478,11 @@ func NewDomain(store kv.Storage, ddlLease time.Duration, statsLease time.Duratio
 		store:           store,
 		SchemaValidator: NewSchemaValidator(ddlLease),
 		exit:            make(chan struct{}),
+		globalVarsCh:    make(chan struct{}, 1),
 		sysSessionPool:  newSessionPool(capacity, factory),
 		statsLease:      statsLease,
 		infoHandle:      infoschema.NewHandle(store),
+		baselineHandle:  bindinfo.NewHandle(),
 	}
 }
 
@@ -624,6 +640,81 @@ func (do *Domain) CreateStatsHandle(ctx sessionctx.Context) {
 	atomic.StorePointer(&do.statsHandle, unsafe.Pointer(statistics.NewHandle(ctx, do.statsLease)))
 }
 
+// PlanBaselineHandle returns the handle of the plan baselines.
+func (do *Domain) PlanBaselineHandle() *bindinfo.Handle {
+	return do.baselineHandle
+}
+
+const (
+	globalVarsKey = "/tidb/global_variables"
+	// globalVarsReloadInterval is the longest time for a TiDB server to apply
+	// the global variables changed by others, in case the notification is lost.
+	globalVarsReloadInterval = time.Minute
+)
+
+// LoadGlobalVarsLoop creates a goroutine which calls load to reload the global
+// variables in a loop. The loop is woken up by NotifyUpdateGlobalVars of any
+// TiDB server sharing the same store. It should be called only once in BootstrapSession.
+func (do *Domain) LoadGlobalVarsLoop(load func() error) error {
+	if err := load(); err != nil {
+		return err
+	}
+
+	var watchCh clientv3.WatchChan
+	if do.etcdClient != nil {
+		watchCh = do.etcdClient.Watch(context.Background(), globalVarsKey)
+	}
+
+	do.wg.Add(1)
+	go func() {
+		defer do.wg.Done()
+		defer recoverInDomain("loadGlobalVarsInLoop", false)
+		var count int
+		for {
+			ok := true
+			select {
+			case <-do.exit:
+				return
+			case <-do.globalVarsCh:
+			case _, ok = <-watchCh:
+			case <-time.After(globalVarsReloadInterval):
+			}
+			if !ok {
+				logutil.BgLogger().Error("load global variables loop watch channel closed")
+				watchCh = do.etcdClient.Watch(context.Background(), globalVarsKey)
+				count++
+				if count > 10 {
+					time.Sleep(time.Duration(count) * time.Second)
+				}
+				continue
+			}
+
+			count = 0
+			if err := load(); err != nil {
+				logutil.BgLogger().Error("load global variables failed", zap.Error(err))
+			}
+		}
+	}()
+	return nil
+}
+
+// NotifyUpdateGlobalVars is called after a global variable is changed. It
+// expires the local global variable cache and wakes up the loops started by
+// LoadGlobalVarsLoop on all the TiDB servers.
+func (do *Domain) NotifyUpdateGlobalVars() {
+	do.gvc.Expire()
+	if do.etcdClient != nil {
+		_, err := do.etcdClient.KV.Put(context.Background(), globalVarsKey, "")
+		if err != nil {
+			logutil.BgLogger().Warn("notify update global variables failed", zap.Error(err))
+		}
+	}
+	select {
+	case do.globalVarsCh <- struct{}{}:
+	default:
+	}
+}
+
 // UpdateTableStatsLoop creates a goroutine loads stats info and updates stats info in a loop.
 // It will also start a goroutine to analyze tables automatically.
 // It should be called only once in BootstrapSession.
@@ -665,6 +756,56 @@ func (do *Domain) loadStatsWorker() {
 	}
 }
 
+// EvolvePlanBaselinesInterval is the interval of the evolution of the plan baselines.
+var EvolvePlanBaselinesInterval = 10 * time.Minute
+
+// EvolvePlanBaselinesLoop creates a goroutine which calls evolve to evolve the plan baselines
+// in a loop. It should be called only once in BootstrapSession.
+func (do *Domain) EvolvePlanBaselinesLoop(evolve func() error) {
+	do.wg.Add(1)
+	go func() {
+		defer do.wg.Done()
+		defer recoverInDomain("evolvePlanBaselinesLoop", false)
+		ticker := time.NewTicker(EvolvePlanBaselinesInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-ticker.C:
+				if err := evolve(); err != nil {
+					logutil.BgLogger().Warn("evolve plan baselines failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// TTLJobInterval is the interval of the TTL jobs which delete the expired rows.
+var TTLJobInterval = time.Hour
+
+// TTLJobLoop creates a goroutine which calls run to delete the expired rows of the TTL tables
+// in a loop. It should be called only once in BootstrapSession.
+func (do *Domain) TTLJobLoop(run func() error) {
+	do.wg.Add(1)
+	go func() {
+		defer do.wg.Done()
+		defer recoverInDomain("ttlJobLoop", false)
+		ticker := time.NewTicker(TTLJobInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-ticker.C:
+				if err := run(); err != nil {
+					logutil.BgLogger().Warn("run TTL jobs failed", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
 func recoverInDomain(funcName string, quit bool) {
 	r := recover()
 	if r == nil {
@@ -55,6 +55,14 @@ func (gvc *GlobalVariableCache) Get() (succ bool, rows []chunk.Row, fields []*as
 	return
 }
 
+// Expire makes the cached global variables expired, so they will be loaded
+// from the storage next time.
+func (gvc *GlobalVariableCache) Expire() {
+	gvc.Lock()
+	gvc.lastModify = time.Time{}
+	gvc.Unlock()
+}
+
 // Disable disables the global variabe cache, used in test only.
 func (gvc *GlobalVariableCache) Disable() {
 	gvc.Lock()
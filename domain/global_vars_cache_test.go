@@ -80,6 +80,45 @@ func (gvcSuite *testGVCSuite) TestSimple(c *C) {
 	c.Assert(fields, DeepEquals, []*ast.ResultField{rf, rf1})
 }
 
+func (gvcSuite *testGVCSuite) TestLoadGlobalVarsLoop(c *C) {
+	defer testleak.AfterTest(c)()
+	testleak.BeforeTest()
+
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	defer store.Close()
+	ddlLease := 50 * time.Millisecond
+	dom := NewDomain(store, ddlLease, 0, mockFactory)
+	err = dom.Init(ddlLease, sysMockFactory)
+	c.Assert(err, IsNil)
+	defer dom.Close()
+
+	loaded := make(chan struct{}, 1)
+	err = dom.LoadGlobalVarsLoop(func() error {
+		loaded <- struct{}{}
+		return nil
+	})
+	c.Assert(err, IsNil)
+	// The global variables are loaded once when the loop starts.
+	<-loaded
+
+	ck := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeString)}, 1)
+	ck.AppendString(0, "variable1")
+	gvc := dom.GetGlobalVarsCache()
+	gvc.Update([]chunk.Row{ck.GetRow(0)}, []*ast.ResultField{getResultField("c", 1, 0)})
+	succ, _, _ := gvc.Get()
+	c.Assert(succ, IsTrue)
+
+	dom.NotifyUpdateGlobalVars()
+	succ, _, _ = gvc.Get()
+	c.Assert(succ, IsFalse)
+	select {
+	case <-loaded:
+	case <-time.After(5 * time.Second):
+		c.Fatal("global variables are not reloaded after notified")
+	}
+}
+
 func getResultField(colName string, id, offset int) *ast.ResultField {
 	return &ast.ResultField{
 		Column: &model.ColumnInfo{
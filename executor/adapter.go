@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
@@ -42,6 +43,12 @@ type recordSet struct {
 	stmt       *ExecStmt
 	lastErr    error
 	txnStartTS uint64
+	// logger carries the log fields of the statement to the log lines emitted while fetching rows.
+	logger *zap.Logger
+	// deadline is the time to abort the statement at, it's zero if max_execution_time is not set.
+	deadline time.Time
+	// cancel releases the context with the deadline which the executors are opened in.
+	cancel context.CancelFunc
 }
 
 func (a *recordSet) Fields() []*ast.ResultField {
@@ -91,6 +98,9 @@ func colNames2ResultFields(schema *expression.Schema, names []*types.FieldName,
 // next query.
 // If stmt is not nil and chunk with some rows inside, we simply update last query found rows by the number of row in chunk.
 func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
+	if a.logger != nil {
+		ctx = logutil.WithLogger(ctx, a.logger)
+	}
 	defer func() {
 		r := recover()
 		if r == nil {
@@ -100,8 +110,14 @@ func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		logutil.Logger(ctx).Error("execute sql panic", zap.String("sql", a.stmt.Text), zap.Stack("stack"))
 	}()
 
+	if !a.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, a.deadline)
+		defer cancel()
+	}
 	err = Next(ctx, a.executor, req)
 	if err != nil {
+		err = convertDeadlineErr(ctx, err)
 		a.lastErr = err
 		return err
 	}
@@ -124,7 +140,10 @@ func (a *recordSet) NewChunk() *chunk.Chunk {
 }
 
 func (a *recordSet) Close() error {
-	err := a.executor.Close()
+	err := Close(a.executor)
+	if a.cancel != nil {
+		a.cancel()
+	}
 	sessVars := a.stmt.Ctx.GetSessionVars()
 	sessVars.PrevStmt = FormatSQL(a.stmt.OriginText())
 	return err
@@ -177,14 +196,46 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	}()
 
 	sctx := a.Ctx
+	a.recordWorkload()
+	a.capturePlanBaseline()
+	cacheKey, cacheTableIDs, useResultCache := a.resultCacheKeyOf()
+	var tableVersions map[int64]uint64
+	if useResultCache {
+		if result, ok := globalResultCache.get(cacheKey); ok {
+			sctx.GetSessionVars().FoundInResultCache = true
+			return &cachedRecordSet{stmt: a, result: result}, nil
+		}
+		// The versions are fetched before reading the data, so the result is not cached if
+		// any of the tables is modified during the execution.
+		tableVersions = globalResultCache.versionsOf(cacheTableIDs)
+	}
+	// The deadline is put into the context which the executors are opened in, so
+	// the RPCs sent by the background workers of the executors are aborted as well.
+	var deadline time.Time
+	cancel := context.CancelFunc(func() {})
+	if timeout := a.maxExecutionTime(); timeout > 0 {
+		deadline = time.Now().Add(time.Duration(timeout) * time.Millisecond)
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+	returnRecordSet := false
+	defer func() {
+		// The returned record set releases the context when it's closed.
+		if !returnRecordSet {
+			cancel()
+		}
+	}()
 	e, err := a.buildExecutor()
 	if err != nil {
 		return nil, err
 	}
+	// The transaction is activated while building the executor if the statement reads data.
+	if startTS := sctx.GetSessionVars().TxnCtx.StartTS; startTS != 0 {
+		ctx = logutil.WithFields(ctx, zap.Uint64("txnStartTS", startTS))
+	}
 
-	if err = e.Open(ctx); err != nil {
+	if err = Open(ctx, e); err != nil {
 		terror.Call(e.Close)
-		return nil, err
+		return nil, convertDeadlineErr(ctx, err)
 	}
 
 	if handled, result, err := a.handleNoDelay(ctx, e); handled {
@@ -199,11 +250,41 @@ func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
 	if txn.Valid() {
 		txnStartTS = txn.StartTS()
 	}
-	return &recordSet{
+	rs := &recordSet{
 		executor:   e,
 		stmt:       a,
 		txnStartTS: txnStartTS,
-	}, nil
+		logger:     logutil.Logger(ctx),
+		deadline:   deadline,
+		cancel:     cancel,
+	}
+	returnRecordSet = true
+	if useResultCache {
+		return newResultCachingRecordSet(rs, cacheKey, tableVersions), nil
+	}
+	return rs, nil
+}
+
+// maxExecutionTime returns the timeout of the statement in milliseconds. Like
+// MySQL, only the SELECT statements are limited by max_execution_time.
+func (a *ExecStmt) maxExecutionTime() uint64 {
+	if _, ok := a.StmtNode.(*ast.SelectStmt); !ok {
+		return 0
+	}
+	sessVars := a.Ctx.GetSessionVars()
+	if sessVars.StmtCtx.HasMaxExecutionTime {
+		return sessVars.StmtCtx.MaxExecutionTime
+	}
+	return sessVars.MaxExecutionTime
+}
+
+// convertDeadlineErr converts the error caused by exceeding the deadline of ctx
+// to ErrMaxExecTimeExceeded.
+func convertDeadlineErr(ctx context.Context, err error) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return ErrMaxExecTimeExceeded.GenWithStackByArgs()
+	}
+	return err
 }
 
 func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor) (bool, sqlexec.RecordSet, error) {
@@ -221,7 +302,7 @@ func (a *ExecStmt) handleNoDelay(ctx context.Context, e Executor) (bool, sqlexec
 func (a *ExecStmt) handleNoDelayExecutor(ctx context.Context, e Executor) (sqlexec.RecordSet, error) {
 	var err error
 	defer func() {
-		terror.Log(e.Close())
+		terror.Log(Close(e))
 	}()
 
 	err = Next(ctx, e, newFirstChunk(e))
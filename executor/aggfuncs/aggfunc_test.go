@@ -78,7 +78,7 @@ func (s *testSuite) testMergePartialResult(c *C, p aggTest) {
 	iter := chunk.NewIterator4Chunk(srcChk)
 
 	args := []expression.Expression{&expression.Column{RetType: p.dataType, Index: 0}}
-	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args)
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args, false)
 	c.Assert(err, IsNil)
 	partialDesc, finalDesc := desc.Split([]int{0, 1})
 
@@ -169,7 +169,7 @@ func (s *testSuite) testAggFunc(c *C, p aggTest) {
 	srcChk.AppendDatum(0, &types.Datum{})
 
 	args := []expression.Expression{&expression.Column{RetType: p.dataType, Index: 0}}
-	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args)
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, p.funcName, args, false)
 	c.Assert(err, IsNil)
 	finalFunc := aggfuncs.Build(s.ctx, desc, 0)
 	finalPr := finalFunc.AllocPartialResult()
@@ -28,6 +28,7 @@ var (
 	_ AggFunc = (*countOriginal4Int)(nil)
 	_ AggFunc = (*countOriginal4Real)(nil)
 	_ AggFunc = (*countOriginal4String)(nil)
+	_ AggFunc = (*countOriginalWithDistinct)(nil)
 
 	// All the AggFunc implementations for "FIRSTROW" are listed here.
 	_ AggFunc = (*firstRow4Int)(nil)
@@ -49,11 +50,37 @@ var (
 	_ AggFunc = (*avgOriginal4Float64)(nil)
 	_ AggFunc = (*avgPartial4Float64)(nil)
 
+	_ AggFunc = (*avgOriginal4DistinctInt64)(nil)
+	_ AggFunc = (*avgOriginal4DistinctFloat64)(nil)
+
 	// All the AggFunc implementations for "SUM" are listed here.
 	_ AggFunc = (*sum4Int64)(nil)
 	_ AggFunc = (*sum4Float64)(nil)
+	_ AggFunc = (*sum4DistinctInt64)(nil)
+	_ AggFunc = (*sum4DistinctFloat64)(nil)
+
+	// All the AggFunc implementations for "GROUP_CONCAT" are listed here.
+	_ AggFunc = (*groupConcat)(nil)
+	_ AggFunc = (*groupConcatDistinct)(nil)
+
+	// All the AggFunc implementations for "BIT_OR", "BIT_XOR" and "BIT_AND" are listed here.
+	_ AggFunc = (*bitOrUint64)(nil)
+	_ AggFunc = (*bitXorUint64)(nil)
+	_ AggFunc = (*bitAndUint64)(nil)
+
+	// All the AggFunc implementations for "VAR_POP" and "STDDEV_POP" are listed here.
+	_ AggFunc = (*varPop4Float64)(nil)
+	_ AggFunc = (*stddevPop4Float64)(nil)
+
+	// All the AggFunc implementations for the window functions are listed here.
+	_ AggFunc = (*rowNumber)(nil)
+	_ AggFunc = (*rank)(nil)
 )
 
+// distinctEntryOverhead is the estimated bytes held by an entry of a
+// deduplication set besides the value itself.
+const distinctEntryOverhead = 16
+
 // PartialResult represents data structure to store the partial result for the
 // aggregate functions. Here we use unsafe.Pointer to allow the partial result
 // to be any type.
@@ -96,6 +123,42 @@ type AggFunc interface {
 	AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error
 }
 
+// VecAggFunc is the interface implemented by the aggregate functions which
+// can update the partial results with a whole input chunk. The arguments are
+// evaluated on the chunk once in a vectorized manner instead of row by row.
+type VecAggFunc interface {
+	AggFunc
+
+	// VecUpdatePartialResults updates the partial results of many data groups
+	// using the input chunk. The rows of the i-th group are the ones at the
+	// indexes groupRows[i] of the input, and its partial result is prs[i].
+	VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error
+}
+
+// UpdatePartialResults updates the partial results of many data groups using
+// the input chunk, see VecAggFunc for the meaning of the arguments. It uses
+// the vectorized evaluation if the aggregate function supports it.
+func UpdatePartialResults(sctx sessionctx.Context, af AggFunc, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	if vecAf, ok := af.(VecAggFunc); ok {
+		return vecAf.VecUpdatePartialResults(sctx, input, groupRows, prs)
+	}
+	return updatePartialResultsByRows(sctx, af, input, groupRows, prs)
+}
+
+func updatePartialResultsByRows(sctx sessionctx.Context, af AggFunc, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	var rows []chunk.Row
+	for i, idxes := range groupRows {
+		rows = rows[:0]
+		for _, idx := range idxes {
+			rows = append(rows, input.GetRow(idx))
+		}
+		if err := af.UpdatePartialResult(sctx, rows, prs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type baseAggFunc struct {
 	// args stores the input arguments for an aggregate function, we should
 	// call arg.EvalXXX to get the actual input data for this function.
@@ -109,3 +172,13 @@ type baseAggFunc struct {
 func (*baseAggFunc) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
 	return nil
 }
+
+// consumeDistinctMemory reports the memory held by the new entries of the
+// deduplication sets to the statement, it returns an error if the memory quota
+// of the statement is exceeded.
+func consumeDistinctMemory(sctx sessionctx.Context, bytes int64) error {
+	if bytes == 0 {
+		return nil
+	}
+	return sctx.GetSessionVars().StmtCtx.ConsumeMemory("aggregate distinct set", bytes)
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// All the VecAggFunc implementations are listed here for navigation.
+var (
+	_ VecAggFunc = (*countPartial)(nil)
+	_ VecAggFunc = (*countOriginal4Int)(nil)
+	_ VecAggFunc = (*countOriginal4Real)(nil)
+	_ VecAggFunc = (*countOriginal4String)(nil)
+
+	_ VecAggFunc = (*avgOriginal4Int64)(nil)
+	_ VecAggFunc = (*avgPartial4Int64)(nil)
+	_ VecAggFunc = (*avgOriginal4Float64)(nil)
+	_ VecAggFunc = (*avgPartial4Float64)(nil)
+
+	_ VecAggFunc = (*sum4Int64)(nil)
+	_ VecAggFunc = (*sum4Float64)(nil)
+)
+
+// vecEvalArg evaluates arg on the whole input chunk. The caller should put
+// the returned column back by expression.PutColumn after using it.
+func vecEvalArg(sctx sessionctx.Context, arg expression.Expression, input *chunk.Chunk) (*chunk.Column, error) {
+	buf, err := expression.GetColumn(arg.GetType().EvalType(), input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := expression.VecEval(sctx, arg, input, buf); err != nil {
+		expression.PutColumn(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (e *sum4Int64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	buf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(buf)
+	i64s := buf.Int64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4Int64)(prs[i])
+		for _, idx := range idxes {
+			if buf.IsNull(idx) {
+				continue
+			}
+			if p.isNull {
+				p.val = i64s[idx]
+				p.isNull = false
+				continue
+			}
+			newSum, err := types.AddInt64(p.val, i64s[idx])
+			if err != nil {
+				return err
+			}
+			p.val = newSum
+		}
+	}
+	return nil
+}
+
+func (e *sum4Float64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	buf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(buf)
+	f64s := buf.Float64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4SumFloat64)(prs[i])
+		for _, idx := range idxes {
+			if buf.IsNull(idx) {
+				continue
+			}
+			if p.isNull {
+				p.val = f64s[idx]
+				p.isNull = false
+				continue
+			}
+			p.val += f64s[idx]
+		}
+	}
+	return nil
+}
+
+// The distinct versions embed the functions above but have to deduplicate
+// the input, so they fall back to the row by row updating.
+
+func (e *sum4DistinctInt64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	return updatePartialResultsByRows(sctx, e, input, groupRows, prs)
+}
+
+func (e *sum4DistinctFloat64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	return updatePartialResultsByRows(sctx, e, input, groupRows, prs)
+}
+
+// vecCountNotNull adds the number of the not null values of arg in each group
+// to the partial results of "COUNT".
+func vecCountNotNull(sctx sessionctx.Context, arg expression.Expression, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	buf, err := vecEvalArg(sctx, arg, input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(buf)
+	for i, idxes := range groupRows {
+		p := (*partialResult4Count)(prs[i])
+		for _, idx := range idxes {
+			if !buf.IsNull(idx) {
+				*p++
+			}
+		}
+	}
+	return nil
+}
+
+func (e *countOriginal4Int) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	return vecCountNotNull(sctx, e.args[0], input, groupRows, prs)
+}
+
+func (e *countOriginal4Real) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	return vecCountNotNull(sctx, e.args[0], input, groupRows, prs)
+}
+
+func (e *countOriginal4String) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	return vecCountNotNull(sctx, e.args[0], input, groupRows, prs)
+}
+
+func (e *countPartial) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	buf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(buf)
+	i64s := buf.Int64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4Count)(prs[i])
+		for _, idx := range idxes {
+			if !buf.IsNull(idx) {
+				*p += i64s[idx]
+			}
+		}
+	}
+	return nil
+}
+
+func (e *avgOriginal4Int64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	buf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(buf)
+	i64s := buf.Int64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4AvgInt64)(prs[i])
+		for _, idx := range idxes {
+			if buf.IsNull(idx) {
+				continue
+			}
+			newSum, err := types.AddInt64(p.sum, i64s[idx])
+			if err != nil {
+				return err
+			}
+			p.sum = newSum
+			p.count++
+		}
+	}
+	return nil
+}
+
+func (e *avgPartial4Int64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	countBuf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(countBuf)
+	sumBuf, err := vecEvalArg(sctx, e.args[1], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(sumBuf)
+	counts, sums := countBuf.Int64s(), sumBuf.Int64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4AvgInt64)(prs[i])
+		for _, idx := range idxes {
+			if sumBuf.IsNull(idx) || countBuf.IsNull(idx) {
+				continue
+			}
+			newSum, err := types.AddInt64(p.sum, sums[idx])
+			if err != nil {
+				return err
+			}
+			p.sum = newSum
+			p.count += counts[idx]
+		}
+	}
+	return nil
+}
+
+func (e *avgOriginal4Float64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	buf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(buf)
+	f64s := buf.Float64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4AvgFloat64)(prs[i])
+		for _, idx := range idxes {
+			if buf.IsNull(idx) {
+				continue
+			}
+			p.sum += f64s[idx]
+			p.count++
+		}
+	}
+	return nil
+}
+
+func (e *avgPartial4Float64) VecUpdatePartialResults(sctx sessionctx.Context, input *chunk.Chunk, groupRows [][]int, prs []PartialResult) error {
+	countBuf, err := vecEvalArg(sctx, e.args[0], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(countBuf)
+	sumBuf, err := vecEvalArg(sctx, e.args[1], input)
+	if err != nil {
+		return err
+	}
+	defer expression.PutColumn(sumBuf)
+	counts, sums := countBuf.Int64s(), sumBuf.Float64s()
+	for i, idxes := range groupRows {
+		p := (*partialResult4AvgFloat64)(prs[i])
+		for _, idx := range idxes {
+			if sumBuf.IsNull(idx) || countBuf.IsNull(idx) {
+				continue
+			}
+			p.sum += sums[idx]
+			p.count += counts[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+// buildVecAggInput builds an input chunk for the arguments of desc, every
+// 7th row of which is null, and distributes its rows into numGroups groups.
+func buildVecAggInput(desc *aggregation.AggFuncDesc, numRows, numGroups int) (*chunk.Chunk, [][]int) {
+	tps := make([]*types.FieldType, 0, len(desc.Args))
+	for _, arg := range desc.Args {
+		tps = append(tps, arg.GetType())
+	}
+	input := chunk.NewChunkWithCapacity(tps, numRows)
+	for i := 0; i < numRows; i++ {
+		for j, tp := range tps {
+			dt := getDataGenFunc(tp)(i % 10)
+			if i%7 == 0 {
+				dt = types.Datum{}
+			}
+			input.AppendDatum(j, &dt)
+		}
+	}
+	groupRows := make([][]int, numGroups)
+	for i := 0; i < numRows; i++ {
+		groupRows[i%numGroups] = append(groupRows[i%numGroups], i)
+	}
+	return input, groupRows
+}
+
+func (s *testSuite) testVecUpdatePartialResults(c *C, desc *aggregation.AggFuncDesc, isVec bool) {
+	af := aggfuncs.Build(s.ctx, desc, 0)
+	_, ok := af.(aggfuncs.VecAggFunc)
+	c.Assert(ok, Equals, isVec, Commentf("%v", desc.Name))
+	input, groupRows := buildVecAggInput(desc, 50, 3)
+
+	vecPrs := make([]aggfuncs.PartialResult, 0, len(groupRows))
+	rowPrs := make([]aggfuncs.PartialResult, 0, len(groupRows))
+	for range groupRows {
+		vecPrs = append(vecPrs, af.AllocPartialResult())
+		rowPrs = append(rowPrs, af.AllocPartialResult())
+	}
+	c.Assert(aggfuncs.UpdatePartialResults(s.ctx, af, input, groupRows, vecPrs), IsNil)
+	for i, idxes := range groupRows {
+		for _, idx := range idxes {
+			c.Assert(af.UpdatePartialResult(s.ctx, []chunk.Row{input.GetRow(idx)}, rowPrs[i]), IsNil)
+		}
+	}
+
+	vecChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, len(groupRows))
+	rowChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, len(groupRows))
+	for i := range groupRows {
+		c.Assert(af.AppendFinalResult2Chunk(s.ctx, vecPrs[i], vecChk), IsNil)
+		c.Assert(af.AppendFinalResult2Chunk(s.ctx, rowPrs[i], rowChk), IsNil)
+	}
+	for i := range groupRows {
+		vecDt := vecChk.GetRow(i).GetDatum(0, desc.RetTp)
+		rowDt := rowChk.GetRow(i).GetDatum(0, desc.RetTp)
+		c.Assert(vecDt.IsNull(), IsFalse)
+		cmp, err := vecDt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &rowDt)
+		c.Assert(err, IsNil)
+		c.Assert(cmp, Equals, 0, Commentf("%v group %d: %v vs %v", desc.Name, i, vecDt, rowDt))
+	}
+}
+
+func (s *testSuite) TestVecUpdatePartialResults(c *C) {
+	for _, name := range []string{ast.AggFuncSum, ast.AggFuncCount, ast.AggFuncAvg} {
+		for _, tp := range []byte{mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeString} {
+			if tp == mysql.TypeString && name != ast.AggFuncCount {
+				continue
+			}
+			args := []expression.Expression{&expression.Column{RetType: types.NewFieldType(tp), Index: 0}}
+			desc, err := aggregation.NewAggFuncDesc(s.ctx, name, args, false)
+			c.Assert(err, IsNil)
+			s.testVecUpdatePartialResults(c, desc, true)
+			_, finalDesc := desc.Split([]int{0, 1})
+			s.testVecUpdatePartialResults(c, finalDesc, true)
+
+			// The distinct functions fall back to the row by row updating.
+			desc, err = aggregation.NewAggFuncDesc(s.ctx, name, args, true)
+			c.Assert(err, IsNil)
+			s.testVecUpdatePartialResults(c, desc, name == ast.AggFuncSum)
+		}
+	}
+}
+
+func benchmarkUpdatePartialResults(b *testing.B, ctx sessionctx.Context, desc *aggregation.AggFuncDesc, vec bool) {
+	af := aggfuncs.Build(ctx, desc, 0)
+	input, groupRows := buildVecAggInput(desc, 1024, 16)
+	prs := make([]aggfuncs.PartialResult, 0, len(groupRows))
+	for range groupRows {
+		prs = append(prs, af.AllocPartialResult())
+	}
+	rows := make([]chunk.Row, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if vec {
+			if err := aggfuncs.UpdatePartialResults(ctx, af, input, groupRows, prs); err != nil {
+				b.Fatal(err)
+			}
+			continue
+		}
+		// This is how the aggregate functions were updated before the
+		// vectorized evaluation, one row at a time.
+		for j := 0; j < input.NumRows(); j++ {
+			rows[0] = input.GetRow(j)
+			if err := af.UpdatePartialResult(ctx, rows, prs[j%len(groupRows)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkUpdatePartialResults(b *testing.B) {
+	ctx := mock.NewContext()
+	for _, name := range []string{ast.AggFuncSum, ast.AggFuncCount, ast.AggFuncAvg} {
+		for _, tp := range []byte{mysql.TypeLonglong, mysql.TypeDouble} {
+			args := []expression.Expression{&expression.Column{RetType: types.NewFieldType(tp), Index: 0}}
+			desc, err := aggregation.NewAggFuncDesc(ctx, name, args, false)
+			if err != nil {
+				b.Fatal(err)
+			}
+			for _, vec := range []bool{false, true} {
+				b.Run(fmt.Sprintf("%s(%s)-vec:%v", name, types.TypeStr(tp), vec), func(b *testing.B) {
+					benchmarkUpdatePartialResults(b, ctx, desc, vec)
+				})
+			}
+		}
+	}
+}
@@ -14,10 +14,14 @@
 package aggfuncs
 
 import (
+	"strconv"
+
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -37,10 +41,83 @@ func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal
 		return buildMaxMin(aggFuncDesc, ordinal, true)
 	case ast.AggFuncMin:
 		return buildMaxMin(aggFuncDesc, ordinal, false)
+	case ast.AggFuncGroupConcat:
+		return buildGroupConcat(ctx, aggFuncDesc, ordinal)
+	case ast.AggFuncBitOr:
+		return buildBitOr(aggFuncDesc, ordinal)
+	case ast.AggFuncBitXor:
+		return buildBitXor(aggFuncDesc, ordinal)
+	case ast.AggFuncBitAnd:
+		return buildBitAnd(aggFuncDesc, ordinal)
+	case ast.AggFuncVarPop:
+		return buildVarPop(aggFuncDesc, ordinal)
+	case ast.AggFuncStddevPop:
+		return buildStddevPop(aggFuncDesc, ordinal)
 	}
 	return nil
 }
 
+// BuildWindowEvaluator builds the WindowEvaluator of the window function according
+// to the input windowFuncDesc, the results are appended to the column ordinal. It
+// returns nil if the function or the frame is not supported.
+func BuildWindowEvaluator(ctx sessionctx.Context, windowFuncDesc *aggregation.WindowFuncDesc, ordinal int) WindowEvaluator {
+	fn := buildWindowFunction(ctx, windowFuncDesc, ordinal)
+	if fn == nil {
+		return nil
+	}
+	frame := windowFuncDesc.EffectiveFrame()
+	if frame == nil {
+		return &partitionEvaluator{fn: fn}
+	}
+	if frame.Type == aggregation.Rows {
+		return &rowFrameEvaluator{fn: fn, start: frame.Start, end: frame.End}
+	}
+	e := &rangeFrameEvaluator{
+		fn:          fn,
+		start:       frame.Start,
+		end:         frame.End,
+		rowComparer: buildRowComparer(windowFuncDesc.OrderBy),
+	}
+	for _, b := range []*aggregation.FrameBound{frame.Start, frame.End} {
+		if b.UnBounded || b.Type == aggregation.CurrentRow || e.cmpOffset != nil {
+			continue
+		}
+		// The bounds with offsets need exactly one ORDER BY item of numeric type.
+		if len(windowFuncDesc.OrderBy) != 1 {
+			return nil
+		}
+		e.cmpOffset = buildRangeOffsetCompareFunc(windowFuncDesc.OrderBy[0].Col.RetType)
+		if e.cmpOffset == nil {
+			return nil
+		}
+	}
+	return e
+}
+
+// buildWindowFunction builds the AggFunc implementation for the window function,
+// the aggregate functions are built in CompleteMode.
+func buildWindowFunction(ctx sessionctx.Context, windowFuncDesc *aggregation.WindowFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    windowFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	switch windowFuncDesc.Name {
+	case ast.WindowFuncRowNumber:
+		return &rowNumber{base}
+	case ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		return &rank{
+			baseAggFunc: base,
+			isDense:     windowFuncDesc.Name == ast.WindowFuncDenseRank,
+			rowComparer: buildRowComparer(windowFuncDesc.OrderBy),
+		}
+	}
+	aggFuncDesc, err := aggregation.NewAggFuncDesc(ctx, windowFuncDesc.Name, windowFuncDesc.Args, false)
+	if err != nil {
+		return nil
+	}
+	return Build(ctx, aggFuncDesc, ordinal)
+}
+
 // buildCount builds the AggFunc implementation for function "COUNT".
 func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 	base := baseAggFunc{
@@ -48,6 +125,11 @@ func buildCount(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		ordinal: ordinal,
 	}
 
+	// The distinct count function is used in all the modes, see countOriginalWithDistinct.
+	if aggFuncDesc.HasDistinct {
+		return &countOriginalWithDistinct{base}
+	}
+
 	switch aggFuncDesc.Mode {
 	case aggregation.CompleteMode, aggregation.Partial1Mode:
 		switch aggFuncDesc.Args[0].GetType().EvalType() {
@@ -73,6 +155,14 @@ func buildSum(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 			ordinal: ordinal,
 		},
 	}
+	if aggFuncDesc.HasDistinct {
+		switch aggFuncDesc.RetTp.EvalType() {
+		case types.ETInt:
+			return &sum4DistinctInt64{sum4Int64{base}}
+		default:
+			return &sum4DistinctFloat64{sum4Float64{base}}
+		}
+	}
 	switch aggFuncDesc.RetTp.EvalType() {
 	case types.ETInt:
 		return &sum4Int64{base}
@@ -87,6 +177,16 @@ func buildAvg(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
 		args:    aggFuncDesc.Args,
 		ordinal: ordinal,
 	}
+	// The distinct avg functions are used in all the modes, the final phase
+	// merges the deduplication sets of the partial results.
+	if aggFuncDesc.HasDistinct {
+		switch aggFuncDesc.RetTp.EvalType() {
+		case types.ETInt:
+			return &avgOriginal4DistinctInt64{baseAvgInt64{base}}
+		default:
+			return &avgOriginal4DistinctFloat64{baseAvgFloat64{base}}
+		}
+	}
 	switch aggFuncDesc.Mode {
 	// Build avg functions which consume the original data and update their
 	// partial results.
@@ -170,3 +270,83 @@ func buildMaxMin(aggFuncDesc *aggregation.AggFuncDesc, ordinal int, isMax bool)
 	}
 	return nil
 }
+
+// buildGroupConcat builds the AggFunc implementation for function "GROUP_CONCAT".
+// The last argument is the separator, it's a constant.
+func buildGroupConcat(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	sepArg, ok := aggFuncDesc.Args[len(aggFuncDesc.Args)-1].(*expression.Constant)
+	if !ok {
+		return nil
+	}
+	sep, err := sepArg.Value.ToString()
+	if err != nil {
+		return nil
+	}
+	s, err := variable.GetSessionSystemVar(ctx.GetSessionVars(), variable.GroupConcatMaxLen)
+	if err != nil {
+		return nil
+	}
+	maxLen, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return nil
+	}
+	base := baseGroupConcat4String{
+		baseAggFunc: baseAggFunc{
+			args:    aggFuncDesc.Args,
+			ordinal: ordinal,
+		},
+		sep:    sep,
+		maxLen: maxLen,
+	}
+	// The group_concat functions are used in all the modes, the final phase
+	// concatenates or merges the partial results.
+	if aggFuncDesc.HasDistinct {
+		return &groupConcatDistinct{base}
+	}
+	return &groupConcat{base}
+}
+
+// buildBitOr builds the AggFunc implementation for function "BIT_OR".
+func buildBitOr(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &bitOrUint64{baseBitAggFunc{base}}
+}
+
+// buildBitXor builds the AggFunc implementation for function "BIT_XOR".
+func buildBitXor(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &bitXorUint64{baseBitAggFunc{base}}
+}
+
+// buildBitAnd builds the AggFunc implementation for function "BIT_AND".
+func buildBitAnd(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &bitAndUint64{baseBitAggFunc{base}}
+}
+
+// buildVarPop builds the AggFunc implementation for function "VAR_POP".
+func buildVarPop(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &varPop4Float64{base}
+}
+
+// buildStddevPop builds the AggFunc implementation for function "STDDEV_POP".
+func buildStddevPop(aggFuncDesc *aggregation.AggFuncDesc, ordinal int) AggFunc {
+	base := baseAggFunc{
+		args:    aggFuncDesc.Args,
+		ordinal: ordinal,
+	}
+	return &stddevPop4Float64{varPop4Float64{base}}
+}
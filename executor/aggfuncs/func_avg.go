@@ -17,6 +17,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/set"
 )
 
 // All the following avg function implementations return the decimal result,
@@ -216,3 +217,123 @@ func (e *avgPartial4Float64) MergePartialResult(sctx sessionctx.Context, src Par
 	p2.count += p1.count
 	return nil
 }
+
+// partialResult4AvgDistinctInt64 embeds partialResult4AvgInt64 as the first
+// field, so baseAvgInt64.AppendFinalResult2Chunk can be reused on it.
+type partialResult4AvgDistinctInt64 struct {
+	partialResult4AvgInt64
+	valSet set.Int64Set
+}
+
+// avgOriginal4DistinctInt64 is used for both the partial and the final phase
+// of "AVG(DISTINCT ...)", the final phase merges the deduplication sets.
+type avgOriginal4DistinctInt64 struct {
+	baseAvgInt64
+}
+
+func (e *avgOriginal4DistinctInt64) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4AvgDistinctInt64{valSet: set.NewInt64Set()})
+}
+
+func (e *avgOriginal4DistinctInt64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4AvgDistinctInt64)(pr)
+	p.sum = 0
+	p.count = 0
+	p.valSet = set.NewInt64Set()
+}
+
+func (e *avgOriginal4DistinctInt64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgDistinctInt64)(pr)
+	var memDelta int64
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalInt(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull || p.valSet.Exist(input) {
+			continue
+		}
+		p.valSet.Insert(input)
+		memDelta += 8 + distinctEntryOverhead
+		newSum, err := types.AddInt64(p.sum, input)
+		if err != nil {
+			return err
+		}
+		p.sum = newSum
+		p.count++
+	}
+	return consumeDistinctMemory(sctx, memDelta)
+}
+
+func (e *avgOriginal4DistinctInt64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4AvgDistinctInt64)(src), (*partialResult4AvgDistinctInt64)(dst)
+	for val := range p1.valSet {
+		if p2.valSet.Exist(val) {
+			continue
+		}
+		p2.valSet.Insert(val)
+		newSum, err := types.AddInt64(p2.sum, val)
+		if err != nil {
+			return err
+		}
+		p2.sum = newSum
+		p2.count++
+	}
+	return nil
+}
+
+// partialResult4AvgDistinctFloat64 embeds partialResult4AvgFloat64 as the
+// first field, so baseAvgFloat64.AppendFinalResult2Chunk can be reused on it.
+type partialResult4AvgDistinctFloat64 struct {
+	partialResult4AvgFloat64
+	valSet set.Float64Set
+}
+
+// avgOriginal4DistinctFloat64 is used for both the partial and the final
+// phase of "AVG(DISTINCT ...)", the final phase merges the deduplication sets.
+type avgOriginal4DistinctFloat64 struct {
+	baseAvgFloat64
+}
+
+func (e *avgOriginal4DistinctFloat64) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4AvgDistinctFloat64{valSet: set.NewFloat64Set()})
+}
+
+func (e *avgOriginal4DistinctFloat64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4AvgDistinctFloat64)(pr)
+	p.sum = 0
+	p.count = 0
+	p.valSet = set.NewFloat64Set()
+}
+
+func (e *avgOriginal4DistinctFloat64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgDistinctFloat64)(pr)
+	var memDelta int64
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull || p.valSet.Exist(input) {
+			continue
+		}
+		p.valSet.Insert(input)
+		memDelta += 8 + distinctEntryOverhead
+		p.sum += input
+		p.count++
+	}
+	return consumeDistinctMemory(sctx, memDelta)
+}
+
+func (e *avgOriginal4DistinctFloat64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4AvgDistinctFloat64)(src), (*partialResult4AvgDistinctFloat64)(dst)
+	for val := range p1.valSet {
+		if p2.valSet.Exist(val) {
+			continue
+		}
+		p2.valSet.Insert(val)
+		p2.sum += val
+		p2.count++
+	}
+	return nil
+}
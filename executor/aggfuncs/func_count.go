@@ -2,7 +2,10 @@ package aggfuncs
 
 import (
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/set"
 )
 
 type baseCount struct {
@@ -117,3 +120,84 @@ func (*countPartial) MergePartialResult(sctx sessionctx.Context, src, dst Partia
 	*p2 += *p1
 	return nil
 }
+
+// partialResult4CountWithDistinct stores the distinct values of the arguments
+// encoded as strings, the number of them is the result of "COUNT(DISTINCT ...)".
+type partialResult4CountWithDistinct struct {
+	count  int64
+	valSet set.StringSet
+}
+
+// countOriginalWithDistinct is used for both the partial and the final phase
+// of "COUNT(DISTINCT ...)", because the final phase has to merge the
+// deduplication sets rather than the counts of the partial results.
+type countOriginalWithDistinct struct {
+	baseAggFunc
+}
+
+func (e *countOriginalWithDistinct) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4CountWithDistinct{valSet: set.NewStringSet()})
+}
+
+func (e *countOriginalWithDistinct) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4CountWithDistinct)(pr)
+	p.count = 0
+	p.valSet = set.NewStringSet()
+}
+
+func (e *countOriginalWithDistinct) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4CountWithDistinct)(pr)
+	chk.AppendInt64(e.ordinal, p.count)
+	return nil
+}
+
+func (e *countOriginalWithDistinct) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4CountWithDistinct)(pr)
+	sc := sctx.GetSessionVars().StmtCtx
+	var memDelta int64
+	for _, row := range rowsInGroup {
+		key, hasNull, err := e.evalAndEncode(sc, row)
+		if err != nil {
+			return err
+		}
+		if hasNull || p.valSet.Exist(key) {
+			continue
+		}
+		p.valSet.Insert(key)
+		p.count++
+		memDelta += int64(len(key)) + distinctEntryOverhead
+	}
+	return consumeDistinctMemory(sctx, memDelta)
+}
+
+// evalAndEncode encodes the values of all the arguments into a key, rows with
+// NULL in any argument are not counted.
+func (e *countOriginalWithDistinct) evalAndEncode(sc *stmtctx.StatementContext, row chunk.Row) (key string, hasNull bool, err error) {
+	var buf []byte
+	for _, arg := range e.args {
+		d, err := arg.Eval(row)
+		if err != nil {
+			return "", false, err
+		}
+		if d.IsNull() {
+			return "", true, nil
+		}
+		buf, err = codec.EncodeValue(sc, buf, d)
+		if err != nil {
+			return "", false, err
+		}
+	}
+	return string(buf), false, nil
+}
+
+func (e *countOriginalWithDistinct) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4CountWithDistinct)(src), (*partialResult4CountWithDistinct)(dst)
+	for key := range p1.valSet {
+		if p2.valSet.Exist(key) {
+			continue
+		}
+		p2.valSet.Insert(key)
+		p2.count++
+	}
+	return nil
+}
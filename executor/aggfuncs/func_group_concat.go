@@ -0,0 +1,246 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"bytes"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/set"
+)
+
+type baseGroupConcat4String struct {
+	baseAggFunc
+
+	// sep is the separator between the values, it's the last argument.
+	sep    string
+	maxLen uint64
+}
+
+// concatArgs evaluates the arguments except the separator and appends them to
+// buf, rows with NULL in any argument are skipped.
+func (e *baseGroupConcat4String) concatArgs(sctx sessionctx.Context, row chunk.Row, buf []byte) ([]byte, bool, error) {
+	for _, arg := range e.args[:len(e.args)-1] {
+		d, err := arg.Eval(row)
+		if err != nil {
+			return nil, false, err
+		}
+		if d.IsNull() {
+			return nil, true, nil
+		}
+		s, err := d.ToString()
+		if err != nil {
+			return nil, false, err
+		}
+		buf = append(buf, s...)
+	}
+	return buf, false, nil
+}
+
+// appendTruncatedWarning appends a warning when the result is longer than
+// group_concat_max_len and is cut. The arguments of the final phase are the
+// same as the partial phase, so the warning refers to the original expression.
+func (e *baseGroupConcat4String) appendTruncatedWarning(sctx sessionctx.Context) {
+	sctx.GetSessionVars().StmtCtx.AppendWarning(expression.ErrCutValueGroupConcat.GenWithStackByArgs(e.args[0].String()))
+}
+
+type partialResult4GroupConcat struct {
+	buffer    *bytes.Buffer
+	truncated bool
+}
+
+// groupConcat is used for both the partial and the final phase of
+// "GROUP_CONCAT", the final phase concatenates the partial results.
+type groupConcat struct {
+	baseGroupConcat4String
+}
+
+func (e *groupConcat) AllocPartialResult() PartialResult {
+	return PartialResult(new(partialResult4GroupConcat))
+}
+
+func (e *groupConcat) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcat)(pr)
+	p.buffer = nil
+	p.truncated = false
+}
+
+func (e *groupConcat) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcat)(pr)
+	if p.buffer == nil {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	if p.truncated {
+		e.appendTruncatedWarning(sctx)
+	}
+	chk.AppendString(e.ordinal, p.buffer.String())
+	return nil
+}
+
+// truncate cuts the buffer to group_concat_max_len.
+func (e *groupConcat) truncate(p *partialResult4GroupConcat) {
+	if uint64(p.buffer.Len()) > e.maxLen {
+		p.buffer.Truncate(int(e.maxLen))
+		p.truncated = true
+	}
+}
+
+func (e *groupConcat) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4GroupConcat)(pr)
+	var buf []byte
+	for _, row := range rowsInGroup {
+		if p.truncated {
+			break
+		}
+		var isNull bool
+		var err error
+		buf, isNull, err = e.concatArgs(sctx, row, buf[:0])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if p.buffer == nil {
+			p.buffer = &bytes.Buffer{}
+		} else {
+			p.buffer.WriteString(e.sep)
+		}
+		p.buffer.Write(buf)
+		e.truncate(p)
+	}
+	return nil
+}
+
+func (e *groupConcat) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4GroupConcat)(src), (*partialResult4GroupConcat)(dst)
+	if p1.buffer == nil {
+		return nil
+	}
+	if p2.buffer == nil {
+		p2.buffer = bytes.NewBuffer(append([]byte(nil), p1.buffer.Bytes()...))
+		p2.truncated = p1.truncated
+		return nil
+	}
+	if p2.truncated {
+		return nil
+	}
+	p2.buffer.WriteString(e.sep)
+	p2.buffer.Write(p1.buffer.Bytes())
+	p2.truncated = p1.truncated
+	e.truncate(p2)
+	return nil
+}
+
+// partialResult4GroupConcatDistinct stores the distinct values in the order
+// they are met, valSet stores the encoded keys of the arguments of them.
+type partialResult4GroupConcatDistinct struct {
+	keys   []string
+	vals   []string
+	valSet set.StringSet
+}
+
+// groupConcatDistinct is used for both the partial and the final phase of
+// "GROUP_CONCAT(DISTINCT ...)", the final phase merges the distinct values of
+// the partial results, so they are concatenated and cut only in the end.
+type groupConcatDistinct struct {
+	baseGroupConcat4String
+}
+
+func (e *groupConcatDistinct) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4GroupConcatDistinct{valSet: set.NewStringSet()})
+}
+
+func (e *groupConcatDistinct) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcatDistinct)(pr)
+	p.keys = nil
+	p.vals = nil
+	p.valSet = set.NewStringSet()
+}
+
+func (e *groupConcatDistinct) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcatDistinct)(pr)
+	if len(p.vals) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	var buffer bytes.Buffer
+	for i, val := range p.vals {
+		if i > 0 {
+			buffer.WriteString(e.sep)
+		}
+		buffer.WriteString(val)
+		if uint64(buffer.Len()) > e.maxLen {
+			buffer.Truncate(int(e.maxLen))
+			e.appendTruncatedWarning(sctx)
+			break
+		}
+	}
+	chk.AppendString(e.ordinal, buffer.String())
+	return nil
+}
+
+func (e *groupConcatDistinct) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4GroupConcatDistinct)(pr)
+	sc := sctx.GetSessionVars().StmtCtx
+	var memDelta int64
+	for _, row := range rowsInGroup {
+		var key, val []byte
+		hasNull := false
+		for _, arg := range e.args[:len(e.args)-1] {
+			d, err := arg.Eval(row)
+			if err != nil {
+				return err
+			}
+			if d.IsNull() {
+				hasNull = true
+				break
+			}
+			key, err = codec.EncodeValue(sc, key, d)
+			if err != nil {
+				return err
+			}
+			s, err := d.ToString()
+			if err != nil {
+				return err
+			}
+			val = append(val, s...)
+		}
+		if hasNull || p.valSet.Exist(string(key)) {
+			continue
+		}
+		p.valSet.Insert(string(key))
+		p.keys = append(p.keys, string(key))
+		p.vals = append(p.vals, string(val))
+		memDelta += int64(2*len(key)+len(val)) + distinctEntryOverhead
+	}
+	return consumeDistinctMemory(sctx, memDelta)
+}
+
+func (e *groupConcatDistinct) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4GroupConcatDistinct)(src), (*partialResult4GroupConcatDistinct)(dst)
+	for i, key := range p1.keys {
+		if p2.valSet.Exist(key) {
+			continue
+		}
+		p2.valSet.Insert(key)
+		p2.keys = append(p2.keys, key)
+		p2.vals = append(p2.vals, p1.vals[i])
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// evalGroupConcat evaluates "GROUP_CONCAT(col0, col1 SEPARATOR sep)", every
+// group of rows is updated to a partial result, then they are merged by the
+// final function.
+func (s *testSuite) evalGroupConcat(c *C, distinct bool, sep string, groups ...[][]interface{}) types.Datum {
+	tps := []*types.FieldType{types.NewFieldType(mysql.TypeString), types.NewFieldType(mysql.TypeLonglong)}
+	args := []expression.Expression{
+		&expression.Column{RetType: tps[0], Index: 0},
+		&expression.Column{RetType: tps[1], Index: 1},
+		&expression.Constant{Value: types.NewStringDatum(sep), RetType: types.NewFieldType(mysql.TypeString)},
+	}
+	desc, err := aggregation.NewAggFuncDesc(s.ctx, ast.AggFuncGroupConcat, args, distinct)
+	c.Assert(err, IsNil)
+	partialDesc, finalDesc := desc.Split([]int{0})
+	partialFunc := aggfuncs.Build(s.ctx, partialDesc, 0)
+	finalFunc := aggfuncs.Build(s.ctx, finalDesc, 0)
+	c.Assert(partialFunc, NotNil)
+	c.Assert(finalFunc, NotNil)
+
+	finalPr := finalFunc.AllocPartialResult()
+	for _, group := range groups {
+		chk := chunk.NewChunkWithCapacity(tps, len(group))
+		for _, row := range group {
+			d0, d1 := types.NewDatum(row[0]), types.NewDatum(row[1])
+			chk.AppendDatum(0, &d0)
+			chk.AppendDatum(1, &d1)
+		}
+		rows := make([]chunk.Row, 0, chk.NumRows())
+		for i := 0; i < chk.NumRows(); i++ {
+			rows = append(rows, chk.GetRow(i))
+		}
+		pr := partialFunc.AllocPartialResult()
+		c.Assert(partialFunc.UpdatePartialResult(s.ctx, rows, pr), IsNil)
+		c.Assert(finalFunc.MergePartialResult(s.ctx, pr, finalPr), IsNil)
+	}
+	retTp := types.NewFieldType(mysql.TypeVarString)
+	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{retTp}, 1)
+	c.Assert(finalFunc.AppendFinalResult2Chunk(s.ctx, finalPr, resultChk), IsNil)
+	return resultChk.GetRow(0).GetDatum(0, retTp)
+}
+
+func (s *testSuite) TestGroupConcat(c *C) {
+	group1 := [][]interface{}{{"a", 1}, {nil, 2}, {"b", 2}, {"a", 1}}
+	group2 := [][]interface{}{{"c", nil}, {"b", 2}, {"c", 3}}
+
+	d := s.evalGroupConcat(c, false, ",")
+	c.Assert(d.IsNull(), IsTrue)
+	d = s.evalGroupConcat(c, false, ",", group1)
+	c.Assert(d.GetString(), Equals, "a1,b2,a1")
+	d = s.evalGroupConcat(c, false, "--", group1, group2)
+	c.Assert(d.GetString(), Equals, "a1--b2--a1--b2--c3")
+	d = s.evalGroupConcat(c, true, ",", group1, group2)
+	c.Assert(d.GetString(), Equals, "a1,b2,c3")
+	d = s.evalGroupConcat(c, true, ",", [][]interface{}{{nil, 1}})
+	c.Assert(d.IsNull(), IsTrue)
+	c.Assert(s.ctx.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+}
+
+func (s *testSuite) TestGroupConcatMaxLen(c *C) {
+	sessVars := s.ctx.GetSessionVars()
+	err := variable.SetSessionSystemVar(sessVars, variable.GroupConcatMaxLen, types.NewStringDatum("4"))
+	c.Assert(err, IsNil)
+	defer func() {
+		err := variable.SetSessionSystemVar(sessVars, variable.GroupConcatMaxLen, types.NewStringDatum("1024"))
+		c.Assert(err, IsNil)
+	}()
+
+	group1 := [][]interface{}{{"a", 1}, {"b", 2}}
+	group2 := [][]interface{}{{"c", 3}}
+	d := s.evalGroupConcat(c, false, ",", group1, group2)
+	c.Assert(d.GetString(), Equals, "a1,b")
+	c.Assert(sessVars.StmtCtx.WarningCount(), Equals, uint16(1))
+	c.Assert(expression.ErrCutValueGroupConcat.Equal(sessVars.StmtCtx.GetWarnings()[0].Err), IsTrue)
+
+	sessVars.StmtCtx.SetWarnings(nil)
+	d = s.evalGroupConcat(c, true, ",", group2, group1)
+	c.Assert(d.GetString(), Equals, "c3,a")
+	c.Assert(sessVars.StmtCtx.WarningCount(), Equals, uint16(1))
+
+	sessVars.StmtCtx.SetWarnings(nil)
+	d = s.evalGroupConcat(c, false, ",", [][]interface{}{{"ab", 12}})
+	c.Assert(d.GetString(), Equals, "ab12")
+	c.Assert(sessVars.StmtCtx.WarningCount(), Equals, uint16(0))
+}
@@ -0,0 +1,103 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// rank is used for both "RANK" and "DENSE_RANK", the rows of the partition are
+// kept to find the peers of the next row.
+type rank struct {
+	baseAggFunc
+	isDense bool
+	rowComparer
+}
+
+type partialResult4Rank struct {
+	curIdx   int64
+	lastRank int64
+	rows     []chunk.Row
+}
+
+func (r *rank) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4Rank{})
+}
+
+func (r *rank) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4Rank)(pr)
+	p.curIdx = 0
+	p.lastRank = 0
+	p.rows = p.rows[:0]
+}
+
+func (r *rank) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Rank)(pr)
+	p.rows = append(p.rows, rowsInGroup...)
+	return nil
+}
+
+// AppendFinalResult2Chunk appends the rank of the next row of the partition. The
+// peers share the same rank, "RANK" leaves gaps after the peers while "DENSE_RANK"
+// doesn't.
+func (r *rank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4Rank)(pr)
+	p.curIdx++
+	if p.curIdx == 1 {
+		p.lastRank = 1
+	} else if r.compareRows(p.rows[p.curIdx-2], p.rows[p.curIdx-1]) != 0 {
+		if r.isDense {
+			p.lastRank++
+		} else {
+			p.lastRank = p.curIdx
+		}
+	}
+	chk.AppendInt64(r.ordinal, p.lastRank)
+	return nil
+}
+
+// rowComparer compares the rows by the ORDER BY items of a window.
+type rowComparer struct {
+	cmpFuncs []chunk.CompareFunc
+	items    []aggregation.WindowOrderItem
+}
+
+func buildRowComparer(items []aggregation.WindowOrderItem) rowComparer {
+	rc := rowComparer{
+		cmpFuncs: make([]chunk.CompareFunc, 0, len(items)),
+		items:    items,
+	}
+	for _, item := range items {
+		rc.cmpFuncs = append(rc.cmpFuncs, chunk.GetCompareFunc(item.Col.RetType))
+	}
+	return rc
+}
+
+// compareRows compares the rows in the order of the window, it returns 0 if
+// the rows are peers.
+func (rc *rowComparer) compareRows(prev, curr chunk.Row) int {
+	for i, item := range rc.items {
+		res := rc.cmpFuncs[i](prev, item.Col.Index, curr, item.Col.Index)
+		if res == 0 {
+			continue
+		}
+		if item.Desc {
+			return -res
+		}
+		return res
+	}
+	return 0
+}
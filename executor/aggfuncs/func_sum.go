@@ -17,6 +17,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/set"
 )
 
 type partialResult4SumFloat64 struct {
@@ -152,3 +153,137 @@ func (e *sum4Int64) MergePartialResult(sctx sessionctx.Context, src, dst Partial
 	p2.isNull = false
 	return nil
 }
+
+// partialResult4SumDistinctFloat64 embeds partialResult4SumFloat64 as the
+// first field, so the methods of sum4Float64 which only read the sum can be
+// reused on it.
+type partialResult4SumDistinctFloat64 struct {
+	partialResult4SumFloat64
+	valSet set.Float64Set
+}
+
+// sum4DistinctFloat64 is used for both the partial and the final phase of
+// "SUM(DISTINCT ...)", the final phase merges the deduplication sets.
+type sum4DistinctFloat64 struct {
+	sum4Float64
+}
+
+func (e *sum4DistinctFloat64) AllocPartialResult() PartialResult {
+	p := &partialResult4SumDistinctFloat64{valSet: set.NewFloat64Set()}
+	p.isNull = true
+	return PartialResult(p)
+}
+
+func (e *sum4DistinctFloat64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4SumDistinctFloat64)(pr)
+	p.val = 0
+	p.isNull = true
+	p.valSet = set.NewFloat64Set()
+}
+
+func (e *sum4DistinctFloat64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumDistinctFloat64)(pr)
+	var memDelta int64
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull || p.valSet.Exist(input) {
+			continue
+		}
+		p.valSet.Insert(input)
+		memDelta += 8 + distinctEntryOverhead
+		p.val += input
+		p.isNull = false
+	}
+	return consumeDistinctMemory(sctx, memDelta)
+}
+
+func (e *sum4DistinctFloat64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4SumDistinctFloat64)(src), (*partialResult4SumDistinctFloat64)(dst)
+	for val := range p1.valSet {
+		if p2.valSet.Exist(val) {
+			continue
+		}
+		p2.valSet.Insert(val)
+		p2.val += val
+		p2.isNull = false
+	}
+	return nil
+}
+
+// partialResult4SumDistinctInt64 embeds partialResult4Int64 as the first
+// field, so the methods of sum4Int64 which only read the sum can be reused
+// on it.
+type partialResult4SumDistinctInt64 struct {
+	partialResult4Int64
+	valSet set.Int64Set
+}
+
+// sum4DistinctInt64 is used for both the partial and the final phase of
+// "SUM(DISTINCT ...)", the final phase merges the deduplication sets.
+type sum4DistinctInt64 struct {
+	sum4Int64
+}
+
+func (e *sum4DistinctInt64) AllocPartialResult() PartialResult {
+	p := &partialResult4SumDistinctInt64{valSet: set.NewInt64Set()}
+	p.isNull = true
+	return PartialResult(p)
+}
+
+func (e *sum4DistinctInt64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4SumDistinctInt64)(pr)
+	p.val = 0
+	p.isNull = true
+	p.valSet = set.NewInt64Set()
+}
+
+func (e *sum4DistinctInt64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumDistinctInt64)(pr)
+	var memDelta int64
+	for _, row := range rowsInGroup {
+		input, isNull, err := e.args[0].EvalInt(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull || p.valSet.Exist(input) {
+			continue
+		}
+		p.valSet.Insert(input)
+		memDelta += 8 + distinctEntryOverhead
+		if err = p.add(input); err != nil {
+			return err
+		}
+	}
+	return consumeDistinctMemory(sctx, memDelta)
+}
+
+func (e *sum4DistinctInt64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4SumDistinctInt64)(src), (*partialResult4SumDistinctInt64)(dst)
+	for val := range p1.valSet {
+		if p2.valSet.Exist(val) {
+			continue
+		}
+		p2.valSet.Insert(val)
+		if err := p2.add(val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *partialResult4SumDistinctInt64) add(val int64) error {
+	if p.isNull {
+		p.val = val
+		p.isNull = false
+		return nil
+	}
+	newSum, err := types.AddInt64(p.val, val)
+	if err != nil {
+		return err
+	}
+	p.val = newSum
+	return nil
+}
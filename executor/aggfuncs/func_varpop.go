@@ -0,0 +1,134 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// partialResult4VarPop stores the number, the sum and the sum of the squared
+// differences from the mean of the values, the latter is updated with the
+// Youngs-Cramer algorithm so that it's numerically stable.
+type partialResult4VarPop struct {
+	count    int64
+	sum      float64
+	variance float64
+}
+
+// varPop4Float64 is used for both the partial and the final phase of
+// "VAR_POP", the final phase merges the partial results.
+type varPop4Float64 struct {
+	baseAggFunc
+}
+
+func (e *varPop4Float64) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4VarPop{})
+}
+
+func (e *varPop4Float64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4VarPop)(pr)
+	p.count = 0
+	p.sum = 0
+	p.variance = 0
+}
+
+func (e *varPop4Float64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4VarPop)(pr)
+	if p.count == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendFloat64(e.ordinal, p.variance/float64(p.count))
+	return nil
+}
+
+// evalFloat64Arg evaluates the argument as a float64, the arguments which are
+// not numbers are converted first.
+func evalFloat64Arg(sctx sessionctx.Context, arg expression.Expression, row chunk.Row) (float64, bool, error) {
+	switch arg.GetType().EvalType() {
+	case types.ETInt:
+		input, isNull, err := arg.EvalInt(sctx, row)
+		if mysql.HasUnsignedFlag(arg.GetType().Flag) {
+			return float64(uint64(input)), isNull, err
+		}
+		return float64(input), isNull, err
+	case types.ETReal:
+		return arg.EvalReal(sctx, row)
+	}
+	d, err := arg.Eval(row)
+	if err != nil || d.IsNull() {
+		return 0, d.IsNull(), err
+	}
+	input, err := d.ToFloat64(sctx.GetSessionVars().StmtCtx)
+	return input, false, err
+}
+
+func (e *varPop4Float64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4VarPop)(pr)
+	for _, row := range rowsInGroup {
+		input, isNull, err := evalFloat64Arg(sctx, e.args[0], row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.count++
+		p.sum += input
+		if p.count > 1 {
+			count := float64(p.count)
+			t := count*input - p.sum
+			p.variance += t * t / (count * (count - 1))
+		}
+	}
+	return nil
+}
+
+func (e *varPop4Float64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4VarPop)(src), (*partialResult4VarPop)(dst)
+	if p1.count == 0 {
+		return nil
+	}
+	if p2.count == 0 {
+		*p2 = *p1
+		return nil
+	}
+	srcCount, dstCount := float64(p1.count), float64(p2.count)
+	t := (srcCount/dstCount)*p2.sum - p1.sum
+	p2.variance += p1.variance + ((dstCount/srcCount)/(dstCount+srcCount))*t*t
+	p2.count += p1.count
+	p2.sum += p1.sum
+	return nil
+}
+
+// stddevPop4Float64 is the square root of varPop4Float64.
+type stddevPop4Float64 struct {
+	varPop4Float64
+}
+
+func (e *stddevPop4Float64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4VarPop)(pr)
+	if p.count == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendFloat64(e.ordinal, math.Sqrt(p.variance/float64(p.count)))
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+)
+
+func (s *testSuite) TestMergePartialResult4VarPop(c *C) {
+	tests := []aggTest{
+		buildAggTester(ast.AggFuncVarPop, mysql.TypeDouble, 5, 2.0, 2.0/3, 1.734375),
+		buildAggTester(ast.AggFuncStddevPop, mysql.TypeDouble, 5, math.Sqrt(2), math.Sqrt(2.0/3), math.Sqrt(1.734375)),
+	}
+	for _, test := range tests {
+		s.testMergePartialResult(c, test)
+	}
+}
+
+func (s *testSuite) TestVarPop(c *C) {
+	tests := []aggTest{
+		buildAggTester(ast.AggFuncVarPop, mysql.TypeLonglong, 5, nil, 2.0),
+		buildAggTester(ast.AggFuncVarPop, mysql.TypeDouble, 5, nil, 2.0),
+		buildAggTester(ast.AggFuncStddevPop, mysql.TypeLonglong, 5, nil, math.Sqrt(2)),
+		buildAggTester(ast.AggFuncStddevPop, mysql.TypeString, 5, nil, math.Sqrt(2)),
+	}
+	for _, test := range tests {
+		s.testAggFunc(c, test)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// All the WindowEvaluator implementations are listed here for navigation.
+var (
+	_ WindowEvaluator = (*partitionEvaluator)(nil)
+	_ WindowEvaluator = (*rowFrameEvaluator)(nil)
+	_ WindowEvaluator = (*rangeFrameEvaluator)(nil)
+)
+
+// WindowEvaluator is the interface to evaluate the window functions.
+type WindowEvaluator interface {
+	// AppendPartitionResults evaluates the window function for every row of a
+	// partition and appends the results to the chunk. The rows must be all the
+	// rows of the partition sorted by the ORDER BY items of the window.
+	AppendPartitionResults(sctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error
+}
+
+// partitionEvaluator evaluates the window functions on the whole partition. The
+// ranking functions number the rows when the results are appended one by one.
+type partitionEvaluator struct {
+	fn AggFunc
+}
+
+func (e *partitionEvaluator) AppendPartitionResults(sctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error {
+	pr := e.fn.AllocPartialResult()
+	if err := e.fn.UpdatePartialResult(sctx, rows, pr); err != nil {
+		return err
+	}
+	for range rows {
+		if err := e.fn.AppendFinalResult2Chunk(sctx, pr, chk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendFrameResults evaluates the aggregate function on the frame of every row,
+// the frame of the i-th row is the rows in [start, end).
+func appendFrameResults(sctx sessionctx.Context, fn AggFunc, rows []chunk.Row, chk *chunk.Chunk,
+	bounds func(i int) (start, end int)) error {
+	pr := fn.AllocPartialResult()
+	for i := range rows {
+		start, end := bounds(i)
+		fn.ResetPartialResult(pr)
+		if start < end {
+			if err := fn.UpdatePartialResult(sctx, rows[start:end], pr); err != nil {
+				return err
+			}
+		}
+		if err := fn.AppendFinalResult2Chunk(sctx, pr, chk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowFrameEvaluator evaluates the aggregate functions on the ROWS frames.
+type rowFrameEvaluator struct {
+	fn    AggFunc
+	start *aggregation.FrameBound
+	end   *aggregation.FrameBound
+}
+
+func (e *rowFrameEvaluator) AppendPartitionResults(sctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error {
+	n := len(rows)
+	return appendFrameResults(sctx, e.fn, rows, chk, func(i int) (int, int) {
+		return rowBound(e.start, n, i, false), rowBound(e.end, n, i, true)
+	})
+}
+
+// rowBound returns the index of the bound of the i-th row's frame in a partition
+// of n rows, the index of the end bound is exclusive.
+func rowBound(b *aggregation.FrameBound, n, i int, isEnd bool) int {
+	if b.UnBounded {
+		if b.Type == aggregation.Preceding {
+			return 0
+		}
+		return n
+	}
+	offset := n
+	if b.Num < uint64(n) {
+		offset = int(b.Num)
+	}
+	idx := i
+	switch b.Type {
+	case aggregation.Preceding:
+		idx -= offset
+	case aggregation.Following:
+		idx += offset
+	}
+	if isEnd {
+		idx++
+	}
+	if idx < 0 {
+		return 0
+	}
+	if idx > n {
+		return n
+	}
+	return idx
+}
+
+// rangeFrameEvaluator evaluates the aggregate functions on the RANGE frames. The
+// CURRENT ROW bounds are the first and the last peers of the row, the bounds with
+// offsets are the rows whose ORDER BY value is the value of the row moved by the
+// offsets, there is only one ORDER BY item of integer or real type for them.
+type rangeFrameEvaluator struct {
+	fn    AggFunc
+	start *aggregation.FrameBound
+	end   *aggregation.FrameBound
+	rowComparer
+	// cmpOffset compares the non-null ORDER BY value of `row` with the value of
+	// `cur` plus or minus the offset.
+	cmpOffset func(row, cur chunk.Row, idx int, offset uint64, add bool) int
+}
+
+func (e *rangeFrameEvaluator) AppendPartitionResults(sctx sessionctx.Context, rows []chunk.Row, chk *chunk.Chunk) error {
+	return appendFrameResults(sctx, e.fn, rows, chk, func(i int) (int, int) {
+		return e.bound(rows, i, e.start, false), e.bound(rows, i, e.end, true)
+	})
+}
+
+// bound returns the index of the bound of the i-th row's frame, the index of the
+// end bound is exclusive. The rows are sorted in the order of the window, so the
+// bounds are found by binary search.
+func (e *rangeFrameEvaluator) bound(rows []chunk.Row, i int, b *aggregation.FrameBound, isEnd bool) int {
+	if b.UnBounded {
+		if b.Type == aggregation.Preceding {
+			return 0
+		}
+		return len(rows)
+	}
+	cmp := func(j int) int {
+		if b.Type == aggregation.CurrentRow {
+			return e.compareRows(rows[j], rows[i])
+		}
+		return e.compareWithOffset(rows[j], rows[i], b)
+	}
+	if isEnd {
+		return sort.Search(len(rows), func(j int) bool { return cmp(j) > 0 })
+	}
+	return sort.Search(len(rows), func(j int) bool { return cmp(j) >= 0 })
+}
+
+// compareWithOffset compares the ORDER BY value of row with the value of the bound
+// for cur in the order of the window. NULL values are only peers of each other.
+func (e *rangeFrameEvaluator) compareWithOffset(row, cur chunk.Row, b *aggregation.FrameBound) int {
+	item := e.items[0]
+	idx := item.Col.Index
+	var res int
+	switch {
+	case row.IsNull(idx) && cur.IsNull(idx):
+		res = 0
+	case row.IsNull(idx):
+		res = -1
+	case cur.IsNull(idx):
+		res = 1
+	default:
+		// PRECEDING moves to the smaller values in ascending order, and to the
+		// larger values in descending order.
+		add := (b.Type == aggregation.Preceding) == item.Desc
+		res = e.cmpOffset(row, cur, idx, b.Num, add)
+	}
+	if item.Desc {
+		return -res
+	}
+	return res
+}
+
+// buildRangeOffsetCompareFunc returns the function comparing the ORDER BY values
+// with offsets, it returns nil if the type doesn't support offsets.
+func buildRangeOffsetCompareFunc(tp *types.FieldType) func(row, cur chunk.Row, idx int, offset uint64, add bool) int {
+	switch tp.EvalType() {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(tp.Flag) {
+			return compareUint64WithOffset
+		}
+		return compareInt64WithOffset
+	case types.ETReal:
+		if tp.Tp == mysql.TypeFloat {
+			return compareFloat32WithOffset
+		}
+		return compareFloat64WithOffset
+	}
+	return nil
+}
+
+func compareInt64WithOffset(row, cur chunk.Row, idx int, offset uint64, add bool) int {
+	v, base := row.GetInt64(idx), cur.GetInt64(idx)
+	var target int64
+	if add {
+		// The target is larger than any int64 value.
+		if offset > uint64(math.MaxInt64)-uint64(base) {
+			return -1
+		}
+		target = int64(uint64(base) + offset)
+	} else {
+		// The target is smaller than any int64 value.
+		if offset > uint64(base)+(1<<63) {
+			return 1
+		}
+		target = int64(uint64(base) - offset)
+	}
+	return types.CompareInt64(v, target)
+}
+
+func compareUint64WithOffset(row, cur chunk.Row, idx int, offset uint64, add bool) int {
+	v, base := row.GetUint64(idx), cur.GetUint64(idx)
+	var target uint64
+	if add {
+		if offset > math.MaxUint64-base {
+			return -1
+		}
+		target = base + offset
+	} else {
+		if offset > base {
+			return 1
+		}
+		target = base - offset
+	}
+	return types.CompareUint64(v, target)
+}
+
+func compareFloat32WithOffset(row, cur chunk.Row, idx int, offset uint64, add bool) int {
+	return compareFloatWithOffset(float64(row.GetFloat32(idx)), float64(cur.GetFloat32(idx)), offset, add)
+}
+
+func compareFloat64WithOffset(row, cur chunk.Row, idx int, offset uint64, add bool) int {
+	return compareFloatWithOffset(row.GetFloat64(idx), cur.GetFloat64(idx), offset, add)
+}
+
+func compareFloatWithOffset(v, base float64, offset uint64, add bool) int {
+	if add {
+		return types.CompareFloat64(v, base+float64(offset))
+	}
+	return types.CompareFloat64(v, base-float64(offset))
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggfuncs_test
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor/aggfuncs"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/expression/aggregation"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+type windowTest struct {
+	name    string
+	orderBy []aggregation.WindowOrderItem
+	frame   *aggregation.WindowFrame
+	results []interface{}
+}
+
+// buildWindowPartition builds a partition of rows (o, v), the nil values of o are NULL.
+func buildWindowPartition(os []interface{}, vs []int64) *chunk.Chunk {
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	chk := chunk.NewChunkWithCapacity([]*types.FieldType{tp, tp}, len(os))
+	for i, o := range os {
+		if o == nil {
+			chk.AppendNull(0)
+		} else {
+			chk.AppendInt64(0, o.(int64))
+		}
+		chk.AppendInt64(1, vs[i])
+	}
+	return chk
+}
+
+func (s *testSuite) testWindowFunc(c *C, chk *chunk.Chunk, tt windowTest) {
+	var args []expression.Expression
+	if tt.name != ast.WindowFuncRowNumber && tt.name != ast.WindowFuncRank && tt.name != ast.WindowFuncDenseRank {
+		args = append(args, &expression.Column{Index: 1, RetType: types.NewFieldType(mysql.TypeLonglong)})
+	}
+	desc, err := aggregation.NewWindowFuncDesc(s.ctx, tt.name, args)
+	c.Assert(err, IsNil)
+	desc.OrderBy = tt.orderBy
+	desc.Frame = tt.frame
+	evaluator := aggfuncs.BuildWindowEvaluator(s.ctx, desc, 0)
+	c.Assert(evaluator, NotNil)
+
+	rows := make([]chunk.Row, 0, chk.NumRows())
+	for i := 0; i < chk.NumRows(); i++ {
+		rows = append(rows, chk.GetRow(i))
+	}
+	resultChk := chunk.NewChunkWithCapacity([]*types.FieldType{desc.RetTp}, len(rows))
+	c.Assert(evaluator.AppendPartitionResults(s.ctx, rows, resultChk), IsNil)
+	c.Assert(resultChk.NumRows(), Equals, len(tt.results))
+	for i, expected := range tt.results {
+		dt := resultChk.GetRow(i).GetDatum(0, desc.RetTp)
+		expectedDt := types.NewDatum(expected)
+		res, err := dt.CompareDatum(s.ctx.GetSessionVars().StmtCtx, &expectedDt)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, 0, Commentf("%s: row %d got %v, expected %v", tt.name, i, dt.GetValue(), expected))
+	}
+}
+
+func (s *testSuite) TestWindowFunctions(c *C) {
+	orderCol := &expression.Column{Index: 0, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	asc := []aggregation.WindowOrderItem{{Col: orderCol}}
+	desc := []aggregation.WindowOrderItem{{Col: orderCol, Desc: true}}
+	rowsFrame := func(start, end aggregation.FrameBound) *aggregation.WindowFrame {
+		return &aggregation.WindowFrame{Type: aggregation.Rows, Start: &start, End: &end}
+	}
+	rangeFrame := func(start, end aggregation.FrameBound) *aggregation.WindowFrame {
+		return &aggregation.WindowFrame{Type: aggregation.Ranges, Start: &start, End: &end}
+	}
+	preceding := func(n uint64) aggregation.FrameBound {
+		return aggregation.FrameBound{Type: aggregation.Preceding, Num: n}
+	}
+	following := func(n uint64) aggregation.FrameBound {
+		return aggregation.FrameBound{Type: aggregation.Following, Num: n}
+	}
+	current := aggregation.FrameBound{Type: aggregation.CurrentRow}
+	unboundedFollowing := aggregation.FrameBound{Type: aggregation.Following, UnBounded: true}
+
+	ascChk := buildWindowPartition([]interface{}{nil, int64(1), int64(2), int64(2), int64(4), int64(7)}, []int64{10, 1, 2, 3, 4, 5})
+	tests := []windowTest{
+		{ast.WindowFuncRowNumber, asc, nil, []interface{}{1, 2, 3, 4, 5, 6}},
+		{ast.WindowFuncRank, asc, nil, []interface{}{1, 2, 3, 3, 5, 6}},
+		{ast.WindowFuncDenseRank, asc, nil, []interface{}{1, 2, 3, 3, 4, 5}},
+		// The frames are ignored by the ranking functions.
+		{ast.WindowFuncRank, asc, rowsFrame(current, current), []interface{}{1, 2, 3, 3, 5, 6}},
+		// The default frame is the whole partition without ORDER BY.
+		{ast.AggFuncSum, nil, nil, []interface{}{25, 25, 25, 25, 25, 25}},
+		// The default frame ends at the peers of the current row with ORDER BY.
+		{ast.AggFuncSum, asc, nil, []interface{}{10, 11, 16, 16, 20, 25}},
+		{ast.AggFuncSum, asc, rowsFrame(preceding(1), following(1)), []interface{}{11, 13, 6, 9, 12, 9}},
+		{ast.AggFuncCount, asc, rowsFrame(following(2), following(3)), []interface{}{2, 2, 2, 1, 0, 0}},
+		{ast.AggFuncSum, asc, rowsFrame(following(2), following(3)), []interface{}{5, 7, 9, 5, nil, nil}},
+		{ast.AggFuncMax, asc, rowsFrame(current, unboundedFollowing), []interface{}{10, 5, 5, 5, 5, 5}},
+		{ast.AggFuncSum, asc, rowsFrame(preceding(math.MaxUint64), current), []interface{}{10, 11, 13, 16, 20, 25}},
+		// NULL values are only peers of each other in RANGE frames.
+		{ast.AggFuncSum, asc, rangeFrame(preceding(1), following(1)), []interface{}{10, 6, 6, 6, 4, 5}},
+		{ast.AggFuncCount, asc, rangeFrame(current, unboundedFollowing), []interface{}{6, 5, 4, 4, 2, 1}},
+		{ast.AggFuncCount, asc, rangeFrame(following(1), following(3)), []interface{}{1, 3, 1, 1, 1, 0}},
+	}
+	for _, tt := range tests {
+		s.testWindowFunc(c, ascChk, tt)
+	}
+
+	descChk := buildWindowPartition([]interface{}{int64(7), int64(4), int64(2), int64(2), int64(1), nil}, []int64{5, 4, 3, 2, 1, 10})
+	tests = []windowTest{
+		{ast.WindowFuncRank, desc, nil, []interface{}{1, 2, 3, 3, 5, 6}},
+		{ast.AggFuncSum, desc, nil, []interface{}{5, 9, 14, 14, 15, 25}},
+		// PRECEDING means the larger values in descending order.
+		{ast.AggFuncSum, desc, rangeFrame(preceding(2), current), []interface{}{5, 4, 9, 9, 6, 10}},
+		{ast.AggFuncSum, desc, rangeFrame(current, following(1)), []interface{}{5, 4, 6, 6, 1, 10}},
+	}
+	for _, tt := range tests {
+		s.testWindowFunc(c, descChk, tt)
+	}
+
+	// The offsets never overflow the ORDER BY values.
+	edgeChk := buildWindowPartition([]interface{}{int64(math.MinInt64), int64(0), int64(math.MaxInt64)}, []int64{1, 2, 3})
+	tests = []windowTest{
+		{ast.AggFuncSum, asc, rangeFrame(preceding(1), following(1)), []interface{}{1, 2, 3}},
+		{ast.AggFuncSum, asc, rangeFrame(preceding(math.MaxUint64), current), []interface{}{1, 3, 6}},
+	}
+	for _, tt := range tests {
+		s.testWindowFunc(c, edgeChk, tt)
+	}
+	edgeChk = buildWindowPartition([]interface{}{int64(math.MaxInt64), int64(0), int64(math.MinInt64)}, []int64{3, 2, 1})
+	s.testWindowFunc(c, edgeChk, windowTest{ast.AggFuncSum, desc, rangeFrame(preceding(math.MaxUint64), current), []interface{}{3, 5, 6}})
+	s.testWindowFunc(c, edgeChk, windowTest{ast.AggFuncSum, desc, rangeFrame(current, following(1)), []interface{}{3, 2, 1}})
+
+	realTp := types.NewFieldType(mysql.TypeDouble)
+	realChk := chunk.NewChunkWithCapacity([]*types.FieldType{realTp, types.NewFieldType(mysql.TypeLonglong)}, 4)
+	for i, o := range []float64{0.5, 1.2, 1.5, 3} {
+		realChk.AppendFloat64(0, o)
+		realChk.AppendInt64(1, int64(i+1))
+	}
+	realOrderBy := []aggregation.WindowOrderItem{{Col: &expression.Column{Index: 0, RetType: realTp}}}
+	s.testWindowFunc(c, realChk, windowTest{ast.AggFuncSum, realOrderBy, rangeFrame(preceding(1), current), []interface{}{1, 3, 6, 4}})
+}
+
+func (s *testSuite) TestUnsupportedWindowFrame(c *C) {
+	intCol := &expression.Column{Index: 0, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	strCol := &expression.Column{Index: 1, RetType: types.NewFieldType(mysql.TypeVarString)}
+	frame := &aggregation.WindowFrame{
+		Type:  aggregation.Ranges,
+		Start: &aggregation.FrameBound{Type: aggregation.Preceding, Num: 1},
+		End:   &aggregation.FrameBound{Type: aggregation.CurrentRow},
+	}
+	desc, err := aggregation.NewWindowFuncDesc(s.ctx, ast.AggFuncCount, []expression.Expression{intCol})
+	c.Assert(err, IsNil)
+	desc.Frame = frame
+	// The RANGE frames with offsets need exactly one ORDER BY item of numeric type.
+	desc.OrderBy = []aggregation.WindowOrderItem{{Col: intCol}, {Col: strCol}}
+	c.Assert(aggfuncs.BuildWindowEvaluator(s.ctx, desc, 0), IsNil)
+	desc.OrderBy = []aggregation.WindowOrderItem{{Col: strCol}}
+	c.Assert(aggfuncs.BuildWindowEvaluator(s.ctx, desc, 0), IsNil)
+	desc.OrderBy = []aggregation.WindowOrderItem{{Col: intCol}}
+	c.Assert(aggfuncs.BuildWindowEvaluator(s.ctx, desc, 0), NotNil)
+	// The RANGE frames without offsets work with any ORDER BY items.
+	frame.Start = &aggregation.FrameBound{Type: aggregation.Preceding, UnBounded: true}
+	desc.OrderBy = []aggregation.WindowOrderItem{{Col: intCol}, {Col: strCol}}
+	c.Assert(aggfuncs.BuildWindowEvaluator(s.ctx, desc, 0), NotNil)
+}
@@ -28,13 +28,9 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
-	"github.com/pingcap/tidb/util/set"
-	"github.com/spaolacci/murmur3"
 	"go.uber.org/zap"
 )
 
-type aggPartialResultMapper map[string][]aggfuncs.PartialResult
-
 // baseHashAggWorker stores the common attributes of HashAggFinalWorker and HashAggPartialWorker.
 type baseHashAggWorker struct {
 	ctx          sessionctx.Context
@@ -57,13 +53,16 @@ func newBaseHashAggWorker(ctx sessionctx.Context, finishCh <-chan struct{}, aggF
 type HashAggPartialWorker struct {
 	baseHashAggWorker
 
-	inputCh           chan *chunk.Chunk
-	outputChs         []chan *HashAggIntermData
-	globalOutputCh    chan *AfFinalResult
-	giveBackCh        chan<- *HashAggInput
-	partialResultsMap aggPartialResultMapper
-	groupByItems      []expression.Expression
-	groupKey          [][]byte
+	inputCh        chan *chunk.Chunk
+	outputChs      []chan *HashAggIntermData
+	globalOutputCh chan *AfFinalResult
+	giveBackCh     chan<- *HashAggInput
+	groupMap       *aggGroupMap
+	groupByItems   []expression.Expression
+	groupKey       [][]byte
+	// groupPos is the positions of the groups in the batch of the chunk being updated, -1 means
+	// the group is not in the batch.
+	groupPos []int
 	// chk stores the input data from child,
 	// and is reused by childExec and partial worker.
 	chk *chunk.Chunk
@@ -76,12 +75,10 @@ type HashAggFinalWorker struct {
 
 	rowBuffer           []types.Datum
 	mutableRow          chunk.MutRow
-	partialResultMap    aggPartialResultMapper
-	groupSet            set.StringSet
+	groupMap            *aggGroupMap
 	inputCh             chan *HashAggIntermData
 	outputCh            chan *AfFinalResult
 	finalResultHolderCh chan *chunk.Chunk
-	groupKeys           [][]byte
 }
 
 // AfFinalResult indicates aggregation functions final result.
@@ -148,6 +145,8 @@ type HashAggExec struct {
 	partialWorkers   []HashAggPartialWorker
 	finalWorkers     []HashAggFinalWorker
 	defaultVal       *chunk.Chunk
+	// workerTokens is the number of tokens acquired from the concurrency governor.
+	workerTokens int
 
 	// isChildReturnEmpty indicates whether the child executor only returns an empty input.
 	isChildReturnEmpty bool
@@ -166,21 +165,22 @@ type HashAggInput struct {
 
 // HashAggIntermData indicates the intermediate data of aggregation execution.
 type HashAggIntermData struct {
-	groupKeys        []string
-	cursor           int
-	partialResultMap aggPartialResultMapper
+	// groupIdxs are the indexes of the groups in groupMap.
+	groupIdxs []int
+	cursor    int
+	groupMap  *aggGroupMap
 }
 
 // getPartialResultBatch fetches a batch of partial results from HashAggIntermData.
-func (d *HashAggIntermData) getPartialResultBatch(sc *stmtctx.StatementContext, prs [][]aggfuncs.PartialResult, aggFuncs []aggfuncs.AggFunc, maxChunkSize int) (_ [][]aggfuncs.PartialResult, groupKeys []string, reachEnd bool) {
-	keyStart := d.cursor
-	for ; d.cursor < len(d.groupKeys) && len(prs) < maxChunkSize; d.cursor++ {
-		prs = append(prs, d.partialResultMap[d.groupKeys[d.cursor]])
+func (d *HashAggIntermData) getPartialResultBatch(sc *stmtctx.StatementContext, prs [][]aggfuncs.PartialResult, aggFuncs []aggfuncs.AggFunc, maxChunkSize int) (_ [][]aggfuncs.PartialResult, groupIdxs []int, reachEnd bool) {
+	idxStart := d.cursor
+	for ; d.cursor < len(d.groupIdxs) && len(prs) < maxChunkSize; d.cursor++ {
+		prs = append(prs, d.groupMap.PartialResults(d.groupIdxs[d.cursor]))
 	}
-	if d.cursor == len(d.groupKeys) {
+	if d.cursor == len(d.groupIdxs) {
 		reachEnd = true
 	}
-	return prs, d.groupKeys[keyStart:d.cursor], reachEnd
+	return prs, d.groupIdxs[idxStart:d.cursor], reachEnd
 }
 
 // Close implements the Executor Close interface.
@@ -208,6 +208,8 @@ func (e *HashAggExec) Close() error {
 	for range e.finalOutputCh {
 	}
 	e.executed = false
+	globalConcurrencyGovernor.release(e.workerTokens)
+	e.workerTokens = 0
 
 	return e.baseExecutor.Close()
 }
@@ -225,8 +227,9 @@ func (e *HashAggExec) Open(ctx context.Context) error {
 
 func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	sessionVars := e.ctx.GetSessionVars()
-	finalConcurrency := sessionVars.HashAggFinalConcurrency
-	partialConcurrency := sessionVars.HashAggPartialConcurrency
+	finalConcurrency := globalConcurrencyGovernor.acquire(sessionVars.HashAggFinalConcurrency)
+	partialConcurrency := globalConcurrencyGovernor.acquire(sessionVars.HashAggPartialConcurrency)
+	e.workerTokens = finalConcurrency + partialConcurrency
 	e.isChildReturnEmpty = true
 	e.finalOutputCh = make(chan *AfFinalResult, finalConcurrency)
 	e.inputCh = make(chan *HashAggInput, partialConcurrency)
@@ -252,7 +255,7 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 			outputChs:         e.partialOutputChs,
 			giveBackCh:        e.inputCh,
 			globalOutputCh:    e.finalOutputCh,
-			partialResultsMap: make(aggPartialResultMapper),
+			groupMap:          newAggGroupMap(),
 			groupByItems:      e.GroupByItems,
 			chk:               newFirstChunk(e.children[0]),
 			groupKey:          make([][]byte, 0, 8),
@@ -269,14 +272,12 @@ func (e *HashAggExec) initForParallelExec(ctx sessionctx.Context) {
 	for i := 0; i < finalConcurrency; i++ {
 		e.finalWorkers[i] = HashAggFinalWorker{
 			baseHashAggWorker:   newBaseHashAggWorker(e.ctx, e.finishCh, e.FinalAggFuncs, e.maxChunkSize),
-			partialResultMap:    make(aggPartialResultMapper),
-			groupSet:            set.NewStringSet(),
+			groupMap:            newAggGroupMap(),
 			inputCh:             e.partialOutputChs[i],
 			outputCh:            e.finalOutputCh,
 			finalResultHolderCh: make(chan *chunk.Chunk, 1),
 			rowBuffer:           make([]types.Datum, 0, e.Schema().Len()),
 			mutableRow:          chunk.MutRowFromTypes(retTypes(e)),
-			groupKeys:           make([][]byte, 0, 8),
 		}
 		e.finalWorkers[i].finalResultHolderCh <- newFirstChunk(e)
 	}
@@ -320,7 +321,7 @@ func (w *HashAggPartialWorker) run(ctx sessionctx.Context, waitGroup *sync.WaitG
 		if !w.getChildInput() {
 			return
 		}
-		if err := w.updatePartialResult(ctx, sc, w.chk, len(w.partialResultsMap)); err != nil {
+		if err := w.updatePartialResult(ctx, sc, w.chk, w.groupMap.Len()); err != nil {
 			w.globalOutputCh <- &AfFinalResult{err: err}
 			return
 		}
@@ -336,15 +337,38 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 		return err
 	}
 
-	partialResults := w.getPartialResult(sc, w.groupKey, w.partialResultsMap)
+	// Batch the rows of the same group, so the aggregate functions can update
+	// the partial results with the whole chunk. The groupKey buffer is reused
+	// across the chunks, so it may be longer than the current chunk.
 	numRows := chk.NumRows()
-	rows := make([]chunk.Row, 1)
+	groupIdxs := make([]int, 0, numRows)
+	groupRows := make([][]int, 0, numRows)
 	for i := 0; i < numRows; i++ {
-		for j, af := range w.aggFuncs {
-			rows[0] = chk.GetRow(i)
-			if err = af.UpdatePartialResult(ctx, rows, partialResults[i][j]); err != nil {
-				return err
-			}
+		idx := w.groupMap.GetOrPut(w.groupKey[i], w.aggFuncs)
+		for len(w.groupPos) < w.groupMap.Len() {
+			w.groupPos = append(w.groupPos, -1)
+		}
+		pos := w.groupPos[idx]
+		if pos < 0 {
+			pos = len(groupRows)
+			w.groupPos[idx] = pos
+			groupIdxs = append(groupIdxs, idx)
+			groupRows = append(groupRows, nil)
+		}
+		groupRows[pos] = append(groupRows[pos], i)
+	}
+	groupPrs := make([][]aggfuncs.PartialResult, 0, len(groupIdxs))
+	for _, idx := range groupIdxs {
+		groupPrs = append(groupPrs, w.groupMap.PartialResults(idx))
+		w.groupPos[idx] = -1
+	}
+	prs := make([]aggfuncs.PartialResult, len(groupPrs))
+	for j, af := range w.aggFuncs {
+		for i := range groupPrs {
+			prs[i] = groupPrs[i][j]
+		}
+		if err = aggfuncs.UpdatePartialResults(ctx, af, chk, groupRows, prs); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -355,22 +379,22 @@ func (w *HashAggPartialWorker) updatePartialResult(ctx sessionctx.Context, sc *s
 func (w *HashAggPartialWorker) shuffleIntermData(sc *stmtctx.StatementContext, finalConcurrency int) {
 	// TODO: implement the method body. Shuffle the data to final workers.
 	/* Your code here */
-	groupKeysSlice := make([][]string, finalConcurrency)
-	for groupKey := range w.partialResultsMap {
-		finalWokerIdx := int(murmur3.Sum32([]byte(groupKey))) % finalConcurrency
-		if groupKeysSlice[finalWokerIdx] == nil {
-			groupKeysSlice[finalWokerIdx] = make([]string, 0, len(w.partialResultsMap)/finalConcurrency)
+	groupIdxsSlice := make([][]int, finalConcurrency)
+	for idx := 0; idx < w.groupMap.Len(); idx++ {
+		finalWokerIdx := int(w.groupMap.Hash(idx) % uint64(finalConcurrency))
+		if groupIdxsSlice[finalWokerIdx] == nil {
+			groupIdxsSlice[finalWokerIdx] = make([]int, 0, w.groupMap.Len()/finalConcurrency)
 		}
-		groupKeysSlice[finalWokerIdx] = append(groupKeysSlice[finalWokerIdx], groupKey)
+		groupIdxsSlice[finalWokerIdx] = append(groupIdxsSlice[finalWokerIdx], idx)
 	}
 
-	for i := range groupKeysSlice {
-		if groupKeysSlice[i] == nil {
+	for i := range groupIdxsSlice {
+		if groupIdxsSlice[i] == nil {
 			continue
 		}
 		w.outputChs[i] <- &HashAggIntermData{
-			groupKeys:        groupKeysSlice[i],
-			partialResultMap: w.partialResultsMap,
+			groupIdxs: groupIdxsSlice[i],
+			groupMap:  w.groupMap,
 		}
 	}
 }
@@ -413,22 +437,6 @@ func getGroupKey(ctx sessionctx.Context, input *chunk.Chunk, groupKey [][]byte,
 	return groupKey, nil
 }
 
-func (w baseHashAggWorker) getPartialResult(sc *stmtctx.StatementContext, groupKey [][]byte, mapper aggPartialResultMapper) [][]aggfuncs.PartialResult {
-	n := len(groupKey)
-	partialResults := make([][]aggfuncs.PartialResult, n)
-	for i := 0; i < n; i++ {
-		var ok bool
-		if partialResults[i], ok = mapper[string(groupKey[i])]; ok {
-			continue
-		}
-		for _, af := range w.aggFuncs {
-			partialResults[i] = append(partialResults[i], af.AllocPartialResult())
-		}
-		mapper[string(groupKey[i])] = partialResults[i]
-	}
-	return partialResults
-}
-
 func (w *HashAggFinalWorker) getPartialInput() (input *HashAggIntermData, ok bool) {
 	select {
 	case <-w.finishCh:
@@ -448,7 +456,7 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 		input            *HashAggIntermData
 		ok               bool
 		intermDataBuffer [][]aggfuncs.PartialResult
-		groupKeys        []string
+		groupIdxs        []int
 		sc               = sctx.GetSessionVars().StmtCtx
 	)
 	for {
@@ -460,20 +468,12 @@ func (w *HashAggFinalWorker) consumeIntermData(sctx sessionctx.Context) (err err
 		}
 		// Consume input in batches, size of every batch is less than w.maxChunkSize.
 		for reachEnd := false; !reachEnd; {
-			intermDataBuffer, groupKeys, reachEnd = input.getPartialResultBatch(sc, intermDataBuffer[:0], w.aggFuncs, w.maxChunkSize)
-			groupKeysLen := len(groupKeys)
-			w.groupKeys = w.groupKeys[:0]
-			for i := 0; i < groupKeysLen; i++ {
-				w.groupKeys = append(w.groupKeys, []byte(groupKeys[i]))
-			}
-			finalPartialResults := w.getPartialResult(sc, w.groupKeys, w.partialResultMap)
-			for i, groupKey := range groupKeys {
-				if !w.groupSet.Exist(groupKey) {
-					w.groupSet.Insert(groupKey)
-				}
+			intermDataBuffer, groupIdxs, reachEnd = input.getPartialResultBatch(sc, intermDataBuffer[:0], w.aggFuncs, w.maxChunkSize)
+			for i, idx := range groupIdxs {
+				finalPartialResults := w.groupMap.PartialResults(w.groupMap.GetOrPut(input.groupMap.Key(idx), w.aggFuncs))
 				prs := intermDataBuffer[i]
 				for j, af := range w.aggFuncs {
-					if err = af.MergePartialResult(sctx, prs[j], finalPartialResults[i][j]); err != nil {
+					if err = af.MergePartialResult(sctx, prs[j], finalPartialResults[j]); err != nil {
 						return err
 					}
 				}
@@ -487,14 +487,10 @@ func (w *HashAggFinalWorker) getFinalResult(sctx sessionctx.Context) {
 	if finished {
 		return
 	}
-	w.groupKeys = w.groupKeys[:0]
-	for groupKey := range w.groupSet {
-		w.groupKeys = append(w.groupKeys, []byte(groupKey))
-	}
-	partialResults := w.getPartialResult(sctx.GetSessionVars().StmtCtx, w.groupKeys, w.partialResultMap)
-	for i := 0; i < len(w.groupSet); i++ {
+	for idx := 0; idx < w.groupMap.Len(); idx++ {
+		partialResults := w.groupMap.PartialResults(idx)
 		for j, af := range w.aggFuncs {
-			if err := af.AppendFinalResult2Chunk(sctx, partialResults[i][j], result); err != nil {
+			if err := af.AppendFinalResult2Chunk(sctx, partialResults[j], result); err != nil {
 				logutil.BgLogger().Error("HashAggFinalWorker failed to append final result to Chunk", zap.Error(err))
 			}
 		}
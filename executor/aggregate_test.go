@@ -14,7 +14,12 @@
 package executor_test
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testutil"
 )
@@ -97,3 +102,165 @@ func (s *testSuiteAgg) TestInjectProjBelowTopN(c *C) {
 		tk.MustQuery(tt).Check(testkit.Rows(output[i]...))
 	}
 }
+
+func (s *testSuiteAgg) TestMoreAggFuncs(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(10), d double)")
+	tk.MustQuery("select bit_and(b), bit_or(b), bit_xor(b), var_pop(b), stddev_pop(b), group_concat(c) from t").Check(testkit.Rows(
+		"18446744073709551615 0 0 <nil> <nil> <nil>",
+	))
+	tk.MustExec("insert into t values (1, 1, 'x', 1.5), (1, 3, 'x', 2.5), (1, 6, 'y', null), " +
+		"(2, null, null, null), (2, 5, 'z', 4)")
+
+	tk.MustQuery("select a, bit_and(b), bit_or(b), bit_xor(b) from t group by a order by a").Check(testkit.Rows(
+		"1 0 7 4",
+		"2 5 5 5",
+	))
+	tk.MustQuery("select a, var_pop(b), stddev_pop(d), variance(d), std(b) from t group by a order by a").Check(testkit.Rows(
+		"1 4.222222222222222 0.5 0.25 2.0548046676563256",
+		"2 0 0 0 0",
+	))
+	tk.MustQuery("select a, group_concat(c), group_concat(distinct c, b separator '|') from t group by a order by a").Check(testkit.Rows(
+		"1 x,x,y x1|x3|y6",
+		"2 z z5",
+	))
+	// ORDER BY inside GROUP_CONCAT is not supported.
+	_, err := tk.Exec("select group_concat(distinct c order by c) from t")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("set @@session.group_concat_max_len = 4")
+	tk.MustQuery("select group_concat(c separator '') from t").Check(testkit.Rows("xxyz"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustQuery("select group_concat(c, b) from t").Check(testkit.Rows("x1,x"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1260 Some rows were cut by GROUPCONCAT(test.t.c)"))
+
+	// The aggregation grouped by the unique key is not eliminated.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 3), (2, null)")
+	tk.MustQuery("select a, bit_or(b), var_pop(b) from t group by a order by a").Check(testkit.Rows(
+		"1 3 0",
+		"2 0 <nil>",
+	))
+}
+
+func (s *testSuiteAgg) TestAggDistinct(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c varchar(10), d double)")
+	tk.MustExec("insert into t values (1, 1, 'x', 1.5), (1, 1, 'x', 1.5), (1, 2, 'y', 2.5), " +
+		"(2, null, 'x', null), (2, 3, null, 3), (2, 3, 'z', 3), (3, null, null, null)")
+
+	tk.MustQuery("select a, count(distinct b), count(b), sum(distinct b), sum(distinct d), avg(distinct d) from t group by a order by a").Check(testkit.Rows(
+		"1 2 3 3 4 2",
+		"2 1 2 3 3 3",
+		"3 0 0 <nil> <nil> <nil>",
+	))
+	// Rows with NULL in any argument are not counted.
+	tk.MustQuery("select a, count(distinct b, c), count(distinct c) from t group by a order by a").Check(testkit.Rows(
+		"1 2 2",
+		"2 1 2",
+		"3 0 0",
+	))
+	tk.MustQuery("select count(distinct b), sum(distinct b), count(all b), max(distinct b), min(distinct c) from t").Check(testkit.Rows("3 6 5 3 x"))
+	tk.MustQuery("select count(distinct b), count(b) from t").Check(testkit.Rows("3 5"))
+
+	// The distinct aggregate functions are not pushed down to the coprocessor.
+	tk.MustQuery("explain select count(distinct b) from t").Check(testkit.Rows(
+		"HashAgg_5 1.00 root funcs:count(distinct test.t.b)->Column#6",
+		"└─TableReader_9 10000.00 root data:TableScan_8",
+		"  └─TableScan_8 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a varchar(2000))")
+	values := make([]string, 0, 600)
+	for i := 0; i < 600; i++ {
+		values = append(values, fmt.Sprintf("('%04d%s')", i, strings.Repeat("x", 1996)))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustQuery("select count(distinct a) from t").Check(testkit.Rows("600"))
+	err := tk.QueryToErr("select /*+ MEMORY_QUOTA(1 MB) */ count(distinct a) from t")
+	c.Assert(stmtctx.ErrMemExceedThreshold.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop table t")
+}
+
+func (s *testSuiteAgg) TestAggWithSmallChunks(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	values := make([]string, 0, 40)
+	for i := 0; i < 40; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d)", i%3, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	defer tk.MustExec(fmt.Sprintf("set @@tidb_max_chunk_size = %d", variable.DefMaxChunkSize))
+	tk.MustExec("set @@tidb_hashagg_partial_concurrency = 1")
+	defer tk.MustExec(fmt.Sprintf("set @@tidb_hashagg_partial_concurrency = %d", variable.DefTiDBHashAggPartialConcurrency))
+
+	// The aggregation is not pushed down through the join, and the last chunk
+	// of its input is shorter than the previous ones.
+	tk.MustQuery("select count(*), sum(t1.b) from t t1 join t t2 on t1.b = t2.b").Check(testkit.Rows("40 780"))
+	tk.MustQuery("select t1.a, count(*), sum(t1.b) from t t1 join t t2 on t1.b = t2.b group by t1.a order by t1.a").Check(testkit.Rows("0 14 273", "1 13 247", "2 13 260"))
+	tk.MustExec("drop table t")
+}
+
+func (s *testSuiteAgg) TestHaving(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (1, 2), (2, 3), (3, 4), (3, 5)")
+
+	// Aliases of the select fields.
+	tk.MustQuery("select a as x, sum(b) as s from t group by a having x > 1 and s > 4").Check(testkit.Rows("3 9"))
+	tk.MustQuery("select sum(b) + 1 as s from t group by a having s > 4").Check(testkit.Rows("10"))
+	tk.MustQuery("select count(*) as cnt from t group by a having cnt = max(b) - min(b) + 1 order by cnt").Check(testkit.Rows("1", "2", "2"))
+	// Aggregate functions and group by columns which are not in the select fields.
+	tk.MustQuery("select a from t group by a having sum(b) > 3 and count(distinct b) > 1").Check(testkit.Rows("3"))
+	tk.MustQuery("select count(*) from t group by a having a > 1 order by count(*)").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t having sum(b) > 3").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t group by a having avg(a + b) > 4 order by sum(b)").Check(testkit.Rows("2", "3"))
+
+	_, err := tk.Exec("select a from t group by a having b > 1")
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column 'b' in 'having clause'")
+	_, err = tk.Exec("select a, sum(b) as s from t group by a having sum(s) > 4")
+	c.Assert(err.Error(), Equals, "[planner:1247]Reference 's' not supported (reference to group function)")
+}
+
+func (s *testSuiteAgg) TestRollup(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 10), (1, 2, 20), (2, 1, 30), (2, null, 40)")
+
+	tk.MustQuery("select a, b, sum(c), grouping(a), grouping(b), grouping(a, b) from t group by a, b with rollup " +
+		"order by grouping(a), a, grouping(b), b").Check(testkit.Rows(
+		"1 1 10 0 0 0",
+		"1 2 20 0 0 0",
+		"1 <nil> 30 0 1 1",
+		"2 <nil> 40 0 0 0",
+		"2 1 30 0 0 0",
+		"2 <nil> 70 0 1 1",
+		"<nil> <nil> 100 1 1 3",
+	))
+	// The aggregate functions read the original values of the group-by columns.
+	tk.MustQuery("select a, count(a), sum(a) from t group by a with rollup order by a").Check(testkit.Rows(
+		"<nil> 4 6",
+		"1 2 2",
+		"2 2 4",
+	))
+	tk.MustQuery("select a, sum(c) from t group by a with rollup having grouping(a) = 1").Check(testkit.Rows("<nil> 100"))
+	tk.MustQuery("select * from (select b, count(*) cnt from t group by b with rollup) s where s.b is null order by cnt").Check(testkit.Rows(
+		"<nil> 1",
+		"<nil> 4",
+	))
+
+	_, err := tk.Exec("select grouping(a) from t group by a")
+	c.Assert(err.Error(), Equals, "[planner:1111]Invalid use of group function")
+	_, err = tk.Exec("select a from t where grouping(a) = 0 group by a with rollup")
+	c.Assert(err.Error(), Equals, "[planner:1111]Invalid use of group function")
+	_, err = tk.Exec("select grouping(c) from t group by a with rollup")
+	c.Assert(err.Error(), Equals, "[planner:3600]Argument #1 of GROUPING function is not in GROUP BY")
+}
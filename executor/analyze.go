@@ -130,14 +130,19 @@ type analyzeTask struct {
 	taskType taskType
 	idxExec  *AnalyzeIndexExec
 	colExec  *AnalyzeColumnsExec
+	job      *statistics.AnalyzeJob
 }
 
 var errAnalyzeWorkerPanic = errors.New("analyze worker panic")
 
 func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultCh chan<- analyzeResult, isCloseChanThread bool) {
 	var task *analyzeTask
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
 	defer func() {
 		if r := recover(); r != nil {
+			if task != nil {
+				statsHandle.RemoveAnalyzeJob(task.job)
+			}
 			buf := make([]byte, 4096)
 			stackSize := runtime.Stack(buf, false)
 			buf = buf[:stackSize]
@@ -159,12 +164,16 @@ func (e *AnalyzeExec) analyzeWorker(taskCh <-chan *analyzeTask, resultCh chan<-
 		if !ok {
 			break
 		}
+		statsHandle.AddAnalyzeJob(task.job)
+		var result analyzeResult
 		switch task.taskType {
 		case colTask:
-			resultCh <- analyzeColumnsPushdown(task.colExec)
+			result = analyzeColumnsPushdown(task.colExec)
 		case idxTask:
-			resultCh <- analyzeIndexPushdown(task.idxExec)
+			result = analyzeIndexPushdown(task.idxExec)
 		}
+		statsHandle.RemoveAnalyzeJob(task.job)
+		resultCh <- result
 	}
 }
 
@@ -204,6 +213,7 @@ type AnalyzeIndexExec struct {
 	analyzePB       *tipb.AnalyzeReq
 	result          distsql.SelectResult
 	countNullRes    distsql.SelectResult
+	job             *statistics.AnalyzeJob
 }
 
 // fetchAnalyzeResult builds and dispatches the `kv.Request` from given ranges, and stores the `SelectResult`
@@ -273,6 +283,9 @@ func (e *AnalyzeIndexExec) buildStatsFromResult(result distsql.SelectResult, nee
 			return nil, nil, err
 		}
 		respHist := statistics.HistogramFromProto(resp.Hist)
+		if l := respHist.Len(); l > 0 {
+			e.job.Update(respHist.Buckets[l-1].Count)
+		}
 		hist, err = statistics.MergeHistograms(e.ctx.GetSessionVars().StmtCtx, hist, respHist, defaultNumBuckets)
 		if err != nil {
 			return nil, nil, err
@@ -348,6 +361,7 @@ type AnalyzeColumnsExec struct {
 	concurrency     int
 	analyzePB       *tipb.AnalyzeReq
 	resultHandler   *tableResultHandler
+	job             *statistics.AnalyzeJob
 }
 
 func (e *AnalyzeColumnsExec) open(ranges []*ranger.Range) error {
@@ -423,8 +437,12 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range) (hists []*statis
 			return nil, nil, err
 		}
 		sc := e.ctx.GetSessionVars().StmtCtx
+		var rowCount int64
 		if e.pkInfo != nil {
 			respHist := statistics.HistogramFromProto(resp.PkHist)
+			if l := respHist.Len(); l > 0 {
+				rowCount = respHist.Buckets[l-1].Count
+			}
 			pkHist, err = statistics.MergeHistograms(sc, pkHist, respHist, defaultNumBuckets)
 			if err != nil {
 				return nil, nil, err
@@ -432,8 +450,13 @@ func (e *AnalyzeColumnsExec) buildStats(ranges []*ranger.Range) (hists []*statis
 		}
 		for i, rc := range resp.Collectors {
 			respSample := statistics.SampleCollectorFromProto(rc)
+			// Every collector sees all the rows of the region.
+			if i == 0 {
+				rowCount = respSample.Count + respSample.NullCount
+			}
 			collectors[i].MergeSampleCollector(sc, respSample)
 		}
+		e.job.Update(rowCount)
 	}
 	timeZone := e.ctx.GetSessionVars().Location()
 	if e.pkInfo != nil {
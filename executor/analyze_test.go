@@ -15,8 +15,10 @@ package executor_test
 
 import (
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -39,3 +41,31 @@ func (s *testSuite1) TestAnalyzeRestrict(c *C) {
 	ctx.GetSessionVars().InRestrictedSQL = true
 	tk.MustExec("analyze table t")
 }
+
+func (s *testSuite1) TestShowAnalyzeProgress(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, index idx(a))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("analyze table t")
+	// The finished jobs are not shown.
+	tk.MustQuery("admin show analyze progress").Check(testkit.Rows())
+
+	statsHandle := domain.GetDomain(tk.Se).StatsHandle()
+	job1 := &statistics.AnalyzeJob{DBName: "test", TableName: "t", JobInfo: "analyze index idx", TotalRows: 4}
+	statsHandle.AddAnalyzeJob(job1)
+	defer statsHandle.RemoveAnalyzeJob(job1)
+	job1.Update(1)
+	job2 := &statistics.AnalyzeJob{DBName: "test", TableName: "t1", JobInfo: "analyze columns"}
+	statsHandle.AddAnalyzeJob(job2)
+	defer statsHandle.RemoveAnalyzeJob(job2)
+	job2.Update(3)
+	tk.MustQuery("admin show analyze progress").CheckAt([]int{0, 1, 2, 4, 5, 6}, [][]interface{}{
+		{"test", "t", "analyze index idx", "1", "4", "25"},
+		{"test", "t1", "analyze columns", "3", "<nil>", "<nil>"},
+	})
+	// The remaining time can't be estimated without the total rows.
+	rows := tk.MustQuery("admin show analyze progress").Rows()
+	c.Assert(rows[1][7], Equals, "<nil>")
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"strconv"
 
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
@@ -158,7 +159,8 @@ func getKeysNeedCheckOneRow(ctx sessionctx.Context, t table.Table, row []types.D
 
 // getOldRow gets the table record row from storage for batch check.
 // t could be a normal table or a partition, but it must not be a PartitionedTable.
-func getOldRow(ctx context.Context, sctx sessionctx.Context, txn kv.Transaction, t table.Table, handle int64) ([]types.Datum, error) {
+func getOldRow(ctx context.Context, sctx sessionctx.Context, txn kv.Transaction, t table.Table, handle int64,
+	genExprs []expression.Expression) ([]types.Datum, error) {
 	oldValue, err := txn.Get(ctx, t.RecordKey(handle))
 	if err != nil {
 		return nil, err
@@ -180,5 +182,9 @@ func getOldRow(ctx context.Context, sctx sessionctx.Context, txn kv.Transaction,
 			}
 		}
 	}
+	// The generated columns aren't stored, they are evaluated to remove the old index entries.
+	if err = evalGeneratedColumns(sctx, t, genExprs, oldRow); err != nil {
+		return nil, err
+	}
 	return oldRow, nil
 }
@@ -0,0 +1,392 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// backupMetaFile is the name of the file which describes a backup.
+	backupMetaFile = "backupmeta"
+	// localStoragePrefix is the optional scheme of a local storage path.
+	localStoragePrefix = "local://"
+	// restoreBatchSize is the number of key-value pairs written in one transaction during restore.
+	restoreBatchSize = 1024
+)
+
+// backupMeta describes the content of a backup.
+type backupMeta struct {
+	BackupTS uint64          `json:"backup_ts"`
+	Schemas  []*backupSchema `json:"schemas"`
+}
+
+// backupSchema describes a backed up database.
+type backupSchema struct {
+	Name    model.CIStr    `json:"name"`
+	Charset string         `json:"charset"`
+	Collate string         `json:"collate"`
+	Tables  []*backupTable `json:"tables"`
+}
+
+// backupTable describes a backed up table and the file which holds its key-value pairs.
+type backupTable struct {
+	Info     *model.TableInfo `json:"info"`
+	File     string           `json:"file"`
+	KVCount  int64            `json:"kv_count"`
+	Size     int64            `json:"size"`
+	Checksum uint32           `json:"checksum"`
+}
+
+// BRIEExec represents an executor for BRIE statements (BACKUP and RESTORE).
+// A backup writes every key-value pair of the tables at a snapshot into local files,
+// each pair is encoded as a uvarint length prefixed key followed by a uvarint length prefixed value.
+type BRIEExec struct {
+	baseExecutor
+
+	stmt *ast.BRIEStmt
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *BRIEExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	dir := strings.TrimPrefix(e.stmt.Storage, localStoragePrefix)
+	var (
+		size     int64
+		backupTS uint64
+		err      error
+	)
+	switch e.stmt.Kind {
+	case ast.BRIEKindBackup:
+		size, backupTS, err = e.backup(dir)
+	case ast.BRIEKindRestore:
+		size, backupTS, err = e.restore(ctx, dir)
+	}
+	if err != nil {
+		return err
+	}
+	req.AppendString(0, e.stmt.Storage)
+	req.AppendInt64(1, size)
+	req.AppendUint64(2, backupTS)
+	return nil
+}
+
+func (e *BRIEExec) backup(dir string) (int64, uint64, error) {
+	metaPath := filepath.Join(dir, backupMetaFile)
+	if _, err := os.Stat(metaPath); err == nil {
+		return 0, 0, errors.Errorf("backup meta file %s already exists", metaPath)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+
+	store := e.ctx.GetStore()
+	ver, err := store.CurrentVersion()
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	snapshot, err := store.GetSnapshot(ver)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	is := domain.GetDomain(e.ctx).InfoSchema()
+
+	meta := &backupMeta{BackupTS: ver.Ver}
+	var totalSize int64
+	for _, name := range e.stmt.Schemas {
+		dbName := model.NewCIStr(name)
+		dbInfo, ok := is.SchemaByName(dbName)
+		if !ok {
+			return 0, 0, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(name)
+		}
+		schema := &backupSchema{Name: dbInfo.Name, Charset: dbInfo.Charset, Collate: dbInfo.Collate}
+		for _, tbl := range is.SchemaTables(dbName) {
+			table := &backupTable{
+				Info: tbl.Meta(),
+				File: fmt.Sprintf("%d_%d.kv", dbInfo.ID, tbl.Meta().ID),
+			}
+			if err = backupTableRange(snapshot, filepath.Join(dir, table.File), table); err != nil {
+				return 0, 0, err
+			}
+			totalSize += table.Size
+			schema.Tables = append(schema.Tables, table)
+		}
+		meta.Schemas = append(meta.Schemas, schema)
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	if err = ioutil.WriteFile(metaPath, data, 0644); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	logutil.BgLogger().Info("backup finished", zap.String("storage", e.stmt.Storage),
+		zap.Uint64("backupTS", meta.BackupTS), zap.Int64("size", totalSize))
+	return totalSize, meta.BackupTS, nil
+}
+
+// backupTableRange writes all the key-value pairs of the table into the file, and fills the statistics of table.
+func backupTableRange(snapshot kv.Snapshot, path string, table *backupTable) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	prefix := tablecodec.EncodeTablePrefix(table.Info.ID)
+	it, err := snapshot.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer it.Close()
+
+	w := bufio.NewWriter(f)
+	hash := crc32.NewIEEE()
+	out := io.MultiWriter(w, hash)
+	var lenBuf [binary.MaxVarintLen64]byte
+	for it.Valid() && it.Key().HasPrefix(prefix) {
+		for _, b := range [][]byte{it.Key(), it.Value()} {
+			n := binary.PutUvarint(lenBuf[:], uint64(len(b)))
+			if _, err = out.Write(lenBuf[:n]); err != nil {
+				return errors.Trace(err)
+			}
+			if _, err = out.Write(b); err != nil {
+				return errors.Trace(err)
+			}
+			table.Size += int64(n + len(b))
+		}
+		table.KVCount++
+		if err = it.Next(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	table.Checksum = hash.Sum32()
+	return errors.Trace(w.Flush())
+}
+
+func (e *BRIEExec) restore(ctx context.Context, dir string) (int64, uint64, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, backupMetaFile))
+	if err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+	meta := &backupMeta{}
+	if err = json.Unmarshal(data, meta); err != nil {
+		return 0, 0, errors.Trace(err)
+	}
+
+	// Restoring creates databases and tables, so commit the previous transaction like other DDLs.
+	if err = e.ctx.NewTxn(ctx); err != nil {
+		return 0, 0, err
+	}
+	dom := domain.GetDomain(e.ctx)
+	// The tables may be created even if the restore fails, so the InfoSchema in TxnCtx is always
+	// updated to pass the schema check.
+	defer func() {
+		is := dom.InfoSchema()
+		txnCtx := e.ctx.GetSessionVars().TxnCtx
+		txnCtx.InfoSchema = is
+		txnCtx.SchemaVersion = is.SchemaMetaVersion()
+		e.ctx.GetSessionVars().SetStatusFlag(mysql.ServerStatusInTrans, false)
+	}()
+	var totalSize int64
+	for _, name := range e.stmt.Schemas {
+		schema := findBackupSchema(meta, name)
+		if schema == nil {
+			return 0, 0, errors.Errorf("database %s is not found in the backup", name)
+		}
+		size, err := e.restoreSchema(dom, dir, schema)
+		if err != nil {
+			return 0, 0, err
+		}
+		totalSize += size
+	}
+
+	logutil.BgLogger().Info("restore finished", zap.String("storage", e.stmt.Storage),
+		zap.Uint64("backupTS", meta.BackupTS), zap.Int64("size", totalSize))
+	return totalSize, meta.BackupTS, nil
+}
+
+func findBackupSchema(meta *backupMeta, name string) *backupSchema {
+	for _, schema := range meta.Schemas {
+		if schema.Name.L == strings.ToLower(name) {
+			return schema
+		}
+	}
+	return nil
+}
+
+func (e *BRIEExec) restoreSchema(dom *domain.Domain, dir string, schema *backupSchema) (int64, error) {
+	if _, ok := dom.InfoSchema().SchemaByName(schema.Name); !ok {
+		charsetOpt := &ast.CharsetOpt{Chs: schema.Charset, Col: schema.Collate}
+		if err := dom.DDL().CreateSchema(e.ctx, schema.Name, charsetOpt); err != nil {
+			return 0, err
+		}
+	}
+	var totalSize int64
+	for _, table := range schema.Tables {
+		if err := dom.DDL().CreateTableWithInfo(e.ctx, schema.Name, table.Info); err != nil {
+			return 0, err
+		}
+		if err := dom.Reload(); err != nil {
+			return 0, errors.Trace(err)
+		}
+		tbl, err := dom.InfoSchema().TableByName(schema.Name, table.Info.Name)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		maxHandle, err := e.restoreTableRange(filepath.Join(dir, table.File), table, tbl.Meta().ID)
+		if err != nil {
+			return 0, err
+		}
+		// Make sure the new rows do not conflict with the restored ones.
+		if err = tbl.RebaseAutoID(e.ctx, maxHandle, false); err != nil {
+			return 0, errors.Trace(err)
+		}
+		totalSize += table.Size
+	}
+	return totalSize, nil
+}
+
+// restoreTableRange loads the key-value pairs from the file, rewrites their table ID to newID,
+// and returns the max handle of the restored rows. The file is read in batches and the checksum
+// is verified while it's read, so a corrupted file fails the restore after the pairs before the
+// corruption are loaded.
+func (e *BRIEExec) restoreTableRange(path string, table *backupTable, newID int64) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if stat.Size() != table.Size {
+		return 0, errors.Errorf("backup file %s is corrupted", path)
+	}
+
+	hash := crc32.NewIEEE()
+	r := bufio.NewReader(io.TeeReader(f, hash))
+	oldPrefix := tablecodec.EncodeTablePrefix(table.Info.ID)
+	newPrefix := tablecodec.EncodeTablePrefix(newID)
+	var maxHandle int64
+	for {
+		// The batch is read before the transaction, so the transaction can be retried.
+		pairs, err := readBackupPairs(r, table.Size)
+		if err != nil {
+			return 0, errors.Errorf("backup file %s is corrupted: %v", path, err)
+		}
+		if len(pairs) == 0 {
+			break
+		}
+		err = kv.RunInNewTxn(e.ctx.GetStore(), false, func(txn kv.Transaction) error {
+			for _, pair := range pairs {
+				if !pair.key.HasPrefix(oldPrefix) {
+					return errors.Errorf("key %X in backup file %s does not belong to table %d", pair.key, path, table.Info.ID)
+				}
+				newKey := append(append(kv.Key{}, newPrefix...), pair.key[len(oldPrefix):]...)
+				if err1 := txn.Set(newKey, pair.value); err1 != nil {
+					return err1
+				}
+				if _, _, isRecord, err1 := tablecodec.DecodeKeyHead(newKey); err1 == nil && isRecord {
+					_, handle, err1 := tablecodec.DecodeRecordKey(newKey)
+					if err1 != nil {
+						return err1
+					}
+					if handle > maxHandle {
+						maxHandle = handle
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	if hash.Sum32() != table.Checksum {
+		return 0, errors.Errorf("backup file %s is corrupted", path)
+	}
+	return maxHandle, nil
+}
+
+type backupPair struct {
+	key   kv.Key
+	value []byte
+}
+
+// readBackupPairs reads at most restoreBatchSize key-value pairs, no pair is returned at the end of r.
+func readBackupPairs(r *bufio.Reader, maxLen int64) ([]backupPair, error) {
+	var pairs []backupPair
+	for len(pairs) < restoreBatchSize {
+		key, err := readLengthPrefixed(r, maxLen)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		value, err := readLengthPrefixed(r, maxLen)
+		if err != nil {
+			return nil, errors.New("invalid length prefixed data in backup file")
+		}
+		pairs = append(pairs, backupPair{key: key, value: value})
+	}
+	return pairs, nil
+}
+
+// readLengthPrefixed reads a byte slice no longer than maxLen, io.EOF is returned only if r has no data.
+func readLengthPrefixed(r *bufio.Reader, maxLen int64) ([]byte, error) {
+	l, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil || l > uint64(maxLen) {
+		return nil, errors.New("invalid length prefixed data in backup file")
+	}
+	buf := make([]byte, l)
+	if _, err = io.ReadFull(r, buf); err != nil {
+		return nil, errors.New("invalid length prefixed data in backup file")
+	}
+	return buf, nil
+}
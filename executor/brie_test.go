@@ -0,0 +1,99 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite3) TestBackupAndRestore(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists brie")
+	tk.MustExec("create database brie")
+	tk.MustExec("use brie")
+	tk.MustExec("create table t (id int primary key auto_increment, a int, b varchar(20), index idx_a(a))")
+	tk.MustExec("create table t2 (a int)")
+	for i := 0; i < 3000; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t (a, b) values (%d, 'v%d')", i%100, i))
+	}
+	tk.MustExec("insert into t2 values (1), (2)")
+
+	dir := c.MkDir() + "/backup"
+	result := tk.MustQuery(fmt.Sprintf("backup database brie to 'local://%s'", dir))
+	c.Assert(result.Rows(), HasLen, 1)
+	c.Assert(result.Rows()[0][0], Equals, "local://"+dir)
+	// The destination can not be reused.
+	err := tk.QueryToErr(fmt.Sprintf("backup database brie to '%s'", dir))
+	c.Assert(err, NotNil)
+	err = tk.QueryToErr(fmt.Sprintf("backup database not_exist to '%s'", c.MkDir()))
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrDatabaseNotExists), IsTrue, Commentf("err %v", err))
+
+	// Rows written after the backup are not restored.
+	tk.MustExec("insert into t2 values (3)")
+	tk.MustExec("drop database brie")
+	tk.MustQuery(fmt.Sprintf("restore database brie from '%s'", dir))
+	tk.MustExec("use brie")
+	tk.MustQuery("select count(*), sum(id) from t").Check(testkit.Rows("3000 4501500"))
+	tk.MustQuery("select count(*) from t use index(idx_a) where a = 7").Check(testkit.Rows("30"))
+	tk.MustQuery("select b from t where id = 3000").Check(testkit.Rows("v2999"))
+	tk.MustQuery("select a from t2 order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t use index(idx_a) where a = 99 order by id limit 1").Check(testkit.Rows("100"))
+
+	// The auto increment ID continues after the restored rows.
+	tk.MustExec("insert into t (a, b) values (1000, 'new')")
+	tk.MustQuery("select id > 3000 from t where a = 1000").Check(testkit.Rows("1"))
+
+	// Restoring into existing tables fails.
+	err = tk.QueryToErr(fmt.Sprintf("restore database brie from '%s'", dir))
+	c.Assert(terror.ErrorEqual(err, infoschema.ErrTableExists), IsTrue, Commentf("err %v", err))
+	err = tk.QueryToErr(fmt.Sprintf("restore database other from '%s'", dir))
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite3) TestRestoreCorruptedBackup(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists brie_corrupted")
+	tk.MustExec("create database brie_corrupted")
+	tk.MustExec("use brie_corrupted")
+	tk.MustExec("create table t (a int primary key, b varchar(20))")
+	tk.MustExec("insert into t values (1, 'a'), (2, 'b')")
+	dir := c.MkDir() + "/backup"
+	tk.MustQuery(fmt.Sprintf("backup database brie_corrupted to '%s'", dir))
+	tk.MustExec("drop database brie_corrupted")
+
+	// Flip the last byte of the data file, the pairs are still readable but the checksum mismatches.
+	files, err := filepath.Glob(filepath.Join(dir, "*.kv"))
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+	data, err := ioutil.ReadFile(files[0])
+	c.Assert(err, IsNil)
+	data[len(data)-1] ^= 0xff
+	c.Assert(ioutil.WriteFile(files[0], data, 0644), IsNil)
+	err = tk.QueryToErr(fmt.Sprintf("restore database brie_corrupted from '%s'", dir))
+	c.Assert(err, ErrorMatches, ".*is corrupted.*")
+
+	// A truncated file is rejected before any pair is loaded.
+	tk.MustExec("drop database brie_corrupted")
+	c.Assert(ioutil.WriteFile(files[0], data[:len(data)-1], 0644), IsNil)
+	err = tk.QueryToErr(fmt.Sprintf("restore database brie_corrupted from '%s'", dir))
+	c.Assert(err, ErrorMatches, ".*is corrupted.*")
+	tk.MustQuery("select count(*) from brie_corrupted.t").Check(testkit.Rows("0"))
+}
@@ -29,10 +29,12 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/expression/aggregation"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/statistics"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/admin"
@@ -96,6 +98,10 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildUnionScanExec(v)
 	case *plannercore.PhysicalHashJoin:
 		return b.buildHashJoin(v)
+	case *plannercore.PhysicalApply:
+		return b.buildApply(v)
+	case *plannercore.PhysicalMaxOneRow:
+		return b.buildMaxOneRow(v)
 	case *plannercore.PhysicalMergeJoin:
 		return b.buildMergeJoin(v)
 	case *plannercore.PhysicalSelection:
@@ -104,6 +110,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildHashAgg(v)
 	case *plannercore.PhysicalProjection:
 		return b.buildProjection(v)
+	case *plannercore.PhysicalExpand:
+		return b.buildExpand(v)
 	case *plannercore.PhysicalMemTable:
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
@@ -116,6 +124,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildIndexReader(v)
 	case *plannercore.PhysicalIndexLookUpReader:
 		return b.buildIndexLookUpReader(v)
+	case *plannercore.PhysicalTableLookUpReader:
+		return b.buildTableLookUpReader(v)
 	default:
 		if mp, ok := p.(MockPhysicalPlan); ok {
 			return mp.GetExecutor()
@@ -202,6 +212,38 @@ func (b *executorBuilder) buildShow(v *plannercore.PhysicalShow) Executor {
 }
 
 func (b *executorBuilder) buildSimple(v *plannercore.Simple) Executor {
+	switch s := v.Statement.(type) {
+	case *ast.BRIEStmt:
+		return b.buildBRIE(s, v)
+	case *ast.ImportIntoStmt:
+		return b.buildImport(s, v)
+	case *ast.AdminStmt:
+		switch s.Tp {
+		case ast.AdminExport:
+			return b.buildExport(s.Export, v)
+		case ast.AdminRecommendIndex:
+			return &RecommendIndexExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())}
+		case ast.AdminShowBaselines:
+			return &ShowBaselinesExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())}
+		case ast.AdminShowDDLProgress:
+			return b.buildShowDDLProgress(v)
+		case ast.AdminShowAnalyzeProgress:
+			return &ShowAnalyzeProgressExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())}
+		case ast.AdminScatterTable:
+			return &ScatterTableExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+				tblInfo:      s.Tables[0].TableInfo,
+				index:        s.Index,
+			}
+		case ast.AdminShowTableRegions, ast.AdminShowTableDistributions:
+			return &ShowTableRegionsExec{
+				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+				tp:           s.Tp,
+				tblInfo:      s.Tables[0].TableInfo,
+				index:        s.Index,
+			}
+		}
+	}
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())
 	base.initCap = chunk.ZeroCapacity
 	e := &SimpleExec{
@@ -212,6 +254,58 @@ func (b *executorBuilder) buildSimple(v *plannercore.Simple) Executor {
 	return e
 }
 
+func (b *executorBuilder) buildShowDDLProgress(v *plannercore.Simple) Executor {
+	// Like buildShowDDL, the jobs are read here because next will be called after the transaction
+	// has been committed.
+	txn, err := b.ctx.Txn(true)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	jobs, err := admin.GetDDLJobs(txn)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	return &ShowDDLProgressExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		is:           b.is,
+		jobs:         jobs,
+	}
+}
+
+func (b *executorBuilder) buildBRIE(s *ast.BRIEStmt, v *plannercore.Simple) Executor {
+	e := &BRIEExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		stmt:         s,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildImport(s *ast.ImportIntoStmt, v *plannercore.Simple) Executor {
+	tbl, ok := b.is.TableByID(s.Table.TableInfo.ID)
+	if !ok {
+		b.err = infoschema.ErrTableNotExists.GenWithStackByArgs(s.Table.Schema.O, s.Table.Name.O)
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())
+	base.initCap = chunk.ZeroCapacity
+	e := &ImportExec{
+		baseExecutor: base,
+		stmt:         s,
+		tbl:          tbl,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildExport(opt *ast.ExportOption, v *plannercore.Simple) Executor {
+	e := &ExportExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		opt:          opt,
+	}
+	return e
+}
+
 func (b *executorBuilder) buildSet(v *plannercore.Set) Executor {
 	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID())
 	base.initCap = chunk.ZeroCapacity
@@ -223,6 +317,9 @@ func (b *executorBuilder) buildSet(v *plannercore.Set) Executor {
 }
 
 func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
+	if b.err = checkTableNotImporting(v.Table); b.err != nil {
+		return nil
+	}
 	b.startTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
 	selectExec := b.build(v.SelectPlan)
 	if b.err != nil {
@@ -242,6 +339,7 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		Columns:                   v.Columns,
 		Lists:                     v.Lists,
 		SetList:                   v.SetList,
+		GenExprs:                  v.GenExprs,
 		allAssignmentsAreConstant: v.AllAssignmentsAreConstant,
 		hasRefCols:                v.NeedFillDefaultValue,
 		SelectExec:                selectExec,
@@ -323,6 +421,7 @@ func (b *executorBuilder) buildUnionScanFromReader(reader Executor, v *plannerco
 			for i, col := range x.columns {
 				if col.Name.L == ic.Name.L {
 					us.usedIndex = append(us.usedIndex, i)
+					us.usedIndexDesc = append(us.usedIndexDesc, ic.Desc)
 					break
 				}
 			}
@@ -338,6 +437,7 @@ func (b *executorBuilder) buildUnionScanFromReader(reader Executor, v *plannerco
 			for i, col := range x.columns {
 				if col.Name.L == ic.Name.L {
 					us.usedIndex = append(us.usedIndex, i)
+					us.usedIndexDesc = append(us.usedIndexDesc, ic.Desc)
 					break
 				}
 			}
@@ -351,9 +451,32 @@ func (b *executorBuilder) buildUnionScanFromReader(reader Executor, v *plannerco
 		// The mem table will not be written by sql directly, so we can omit the union scan to avoid err reporting.
 		return reader
 	}
+	us.cacheTable = b.getCacheTable(us.table)
+	if b.err != nil {
+		return nil
+	}
 	return us
 }
 
+// getCacheTable returns the cached data of the table if it can be read by the statement, otherwise it
+// returns nil and the data is read from the storage.
+func (b *executorBuilder) getCacheTable(tbl table.Table) kv.MemBuffer {
+	cachedTable, ok := tbl.(table.CachedTable)
+	if !ok {
+		return nil
+	}
+	startTS, err := b.getStartTS()
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	cacheData := cachedTable.TryReadFromCache(b.ctx.GetStore(), startTS)
+	if cacheData != nil {
+		b.ctx.GetSessionVars().StmtCtx.ReadFromTableCache = true
+	}
+	return cacheData
+}
+
 // buildMergeJoin builds MergeJoinExec executor.
 func (b *executorBuilder) buildMergeJoin(v *plannercore.PhysicalMergeJoin) Executor {
 	leftExec := b.build(v.Children()[0])
@@ -483,6 +606,46 @@ func (b *executorBuilder) buildHashJoin(v *plannercore.PhysicalHashJoin) Executo
 	return e
 }
 
+func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
+	leftChild := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	rightChild := b.build(v.Children()[1])
+	if b.err != nil {
+		return nil
+	}
+	defaultValues := v.DefaultValues
+	if defaultValues == nil {
+		defaultValues = make([]types.Datum, rightChild.Schema().Len())
+	}
+	tupleJoiner := newJoiner(b.ctx, v.JoinType, false, defaultValues,
+		v.OtherConditions, retTypes(leftChild), retTypes(rightChild))
+	e := &NestedLoopApplyExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), leftChild, rightChild),
+		innerExec:    rightChild,
+		outerExec:    leftChild,
+		outerFilter:  v.LeftConditions,
+		innerFilter:  v.RightConditions,
+		outer:        v.JoinType != plannercore.InnerJoin,
+		joiner:       tupleJoiner,
+		outerSchema:  v.OuterSchema,
+	}
+	return e
+}
+
+func (b *executorBuilder) buildMaxOneRow(v *plannercore.PhysicalMaxOneRow) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	base := newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec)
+	base.initCap = 2
+	base.maxChunkSize = 2
+	e := &MaxOneRowExec{baseExecutor: base}
+	return e
+}
+
 func (b *executorBuilder) buildHashAgg(v *plannercore.PhysicalHashAgg) Executor {
 	src := b.build(v.Children()[0])
 	if b.err != nil {
@@ -573,6 +736,17 @@ func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Exe
 	return e
 }
 
+func (b *executorBuilder) buildExpand(v *plannercore.PhysicalExpand) Executor {
+	childExec := b.build(v.Children()[0])
+	if b.err != nil {
+		return nil
+	}
+	return &ExpandExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ExplainID(), childExec),
+		levelExprs:   v.LevelExprs,
+	}
+}
+
 func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Executor {
 	if v.RowCount != 0 && v.RowCount != 1 {
 		b.err = errors.Errorf("buildTableDual failed, invalid row count for dual table: %v", v.RowCount)
@@ -650,6 +824,9 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 	tblID2table := make(map[int64]table.Table)
 	for _, info := range v.TblColPosInfos {
 		tblID2table[info.TblID], _ = b.is.TableByID(info.TblID)
+		if b.err = checkTableNotImporting(tblID2table[info.TblID]); b.err != nil {
+			return nil
+		}
 	}
 	b.startTS = b.ctx.GetSessionVars().TxnCtx.GetForUpdateTS()
 	selExec := b.build(v.SelectPlan)
@@ -686,7 +863,8 @@ func (b *executorBuilder) buildAnalyzeIndexPushdown(task plannercore.AnalyzeInde
 	width := int32(defaultCMSketchWidth)
 	e.analyzePB.IdxReq.CmsketchDepth = &depth
 	e.analyzePB.IdxReq.CmsketchWidth = &width
-	return &analyzeTask{taskType: idxTask, idxExec: e}
+	e.job = b.newAnalyzeJob(task.DBName, task.TableName, "analyze index "+task.IndexInfo.Name.O, task.TblInfo, task.PhysicalTableID)
+	return &analyzeTask{taskType: idxTask, idxExec: e, job: e.job}
 }
 
 func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeColumnsTask) *analyzeTask {
@@ -718,7 +896,18 @@ func (b *executorBuilder) buildAnalyzeColumnsPushdown(task plannercore.AnalyzeCo
 		CmsketchWidth: &width,
 	}
 	b.err = plannercore.SetPBColumnsDefaultValue(b.ctx, e.analyzePB.ColReq.ColumnsInfo, cols)
-	return &analyzeTask{taskType: colTask, colExec: e}
+	e.job = b.newAnalyzeJob(task.DBName, task.TableName, "analyze columns", task.TblInfo, task.PhysicalTableID)
+	return &analyzeTask{taskType: colTask, colExec: e, job: e.job}
+}
+
+// newAnalyzeJob creates the progress of an analyze task, the number of the rows to scan is
+// estimated by the current stats of the table.
+func (b *executorBuilder) newAnalyzeJob(dbName, tableName, jobInfo string, tblInfo *model.TableInfo, physicalTableID int64) *statistics.AnalyzeJob {
+	job := &statistics.AnalyzeJob{DBName: dbName, TableName: tableName, JobInfo: jobInfo}
+	if statsTbl := domain.GetDomain(b.ctx).StatsHandle().GetPartitionStats(tblInfo, physicalTableID); !statsTbl.Pseudo {
+		job.TotalRows = statsTbl.Count
+	}
+	return job
 }
 
 func (b *executorBuilder) buildAnalyze(v *plannercore.Analyze) Executor {
@@ -758,6 +947,11 @@ func (b *executorBuilder) constructDAGReq(plans []plannercore.PhysicalPlan) (dag
 	dagReq = &tipb.DAGRequest{}
 	sc := b.ctx.GetSessionVars().StmtCtx
 	dagReq.Flags = sc.PushDownFlags()
+	if distsql.CanUseChunkRPC(b.ctx) {
+		dagReq.EncodeType = tipb.EncodeType_TypeChunk
+	} else {
+		dagReq.EncodeType = tipb.EncodeType_TypeDefault
+	}
 	dagReq.Executors, err = constructDistExec(b.ctx, plans)
 	return dagReq, err
 }
@@ -783,9 +977,10 @@ func buildNoRangeTableReader(b *executorBuilder, v *plannercore.PhysicalTableRea
 		columns:      ts.Columns,
 		plans:        v.TablePlans,
 	}
+	e.virtualColumnIndex, e.virtualColumnRetFieldTypes = buildVirtualColumnInfo(v.Schema())
 
-	for i := range v.Schema().Columns {
-		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(i))
+	for _, col := range v.OutputColumns {
+		dagReq.OutputOffsets = append(dagReq.OutputOffsets, uint32(col.Index))
 	}
 
 	return e, nil
@@ -893,6 +1088,7 @@ func buildNoRangeIndexLookUpReader(b *executorBuilder, v *plannercore.PhysicalIn
 		idxPlans:          v.IndexPlans,
 		tblPlans:          v.TablePlans,
 	}
+	e.virtualColumnIndex, e.virtualColumnRetFieldTypes = buildVirtualColumnInfo(v.Schema())
 
 	if v.ExtraHandleCol != nil {
 		e.handleIdx = v.ExtraHandleCol.Index
@@ -917,6 +1113,48 @@ func (b *executorBuilder) buildIndexLookUpReader(v *plannercore.PhysicalIndexLoo
 	return ret
 }
 
+func (b *executorBuilder) buildTableLookUpReader(v *plannercore.PhysicalTableLookUpReader) *IndexLookUpExecutor {
+	filterReq, err := b.constructDAGReq(v.FilterPlans)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	tableReq, err := b.constructDAGReq(v.TablePlans)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	filterReq.OutputOffsets = []uint32{uint32(v.HandleCol.Index)}
+	for i := 0; i < v.Schema().Len(); i++ {
+		tableReq.OutputOffsets = append(tableReq.OutputOffsets, uint32(i))
+	}
+
+	fts := v.FilterPlans[0].(*plannercore.PhysicalTableScan)
+	ts := v.TablePlans[0].(*plannercore.PhysicalTableScan)
+	tbl, _ := b.is.TableByID(ts.Table.ID)
+	startTS, err := b.getStartTS()
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	e := &IndexLookUpExecutor{
+		baseExecutor:      newBaseExecutor(b.ctx, v.Schema(), v.ExplainID()),
+		dagPB:             filterReq,
+		startTS:           startTS,
+		table:             tbl,
+		desc:              fts.Desc,
+		ranges:            fts.Ranges,
+		tableRequest:      tableReq,
+		columns:           ts.Columns,
+		dataReaderBuilder: &dataReaderBuilder{executorBuilder: b},
+		idxPlans:          v.FilterPlans,
+		tblPlans:          v.TablePlans,
+	}
+	sctx := b.ctx.GetSessionVars().StmtCtx
+	sctx.TableIDs = append(sctx.TableIDs, ts.Table.ID)
+	return e
+}
+
 // dataReaderBuilder build an executor.
 // The executor can be used to read data in the ranges which are constructed by datums.
 // Differences from executorBuilder:
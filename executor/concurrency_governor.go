@@ -0,0 +1,75 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"sync"
+
+	"github.com/pingcap/tidb/sessionctx/variable"
+)
+
+// globalConcurrencyGovernor is shared by the executors of all the statements in the server.
+var globalConcurrencyGovernor = &concurrencyGovernor{limit: variable.GetExecConcurrencyLimit}
+
+// concurrencyGovernor caps the total number of the worker goroutines of the executors of
+// all the running statements by tokens, the limit is set by `tidb_executor_concurrency_limit`.
+// Each parallel executor acquires the tokens before it starts the workers and releases them
+// when it's closed, so the statements run with fewer workers when the server is busy instead
+// of oversubscribing the CPUs with their configured concurrency.
+type concurrencyGovernor struct {
+	mu    sync.Mutex
+	inUse int
+	// limit returns the max number of tokens, 0 means unlimited.
+	limit func() int64
+}
+
+// acquire acquires at most want tokens and returns the number of the acquired tokens.
+// It never blocks: at least one token is granted even if the limit is reached, so that
+// every statement can always make progress with a single worker.
+func (g *concurrencyGovernor) acquire(want int) int {
+	if want <= 0 {
+		return 0
+	}
+	limit := int(g.limit())
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	granted := want
+	if limit > 0 {
+		if free := limit - g.inUse; granted > free {
+			granted = free
+		}
+		if granted < 1 {
+			granted = 1
+		}
+	}
+	g.inUse += granted
+	return granted
+}
+
+// release gives back the tokens acquired by acquire.
+func (g *concurrencyGovernor) release(n int) {
+	if n <= 0 {
+		return
+	}
+	g.mu.Lock()
+	g.inUse -= n
+	g.mu.Unlock()
+}
+
+// tokensInUse returns the number of the tokens which are not released.
+func (g *concurrencyGovernor) tokensInUse() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.inUse
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *pkgTestSuite) TestConcurrencyGovernor(c *C) {
+	limit := int64(0)
+	g := &concurrencyGovernor{limit: func() int64 { return limit }}
+
+	// Unlimited.
+	c.Assert(g.acquire(8), Equals, 8)
+	c.Assert(g.acquire(0), Equals, 0)
+	c.Assert(g.tokensInUse(), Equals, 8)
+	g.release(8)
+	c.Assert(g.tokensInUse(), Equals, 0)
+
+	limit = 10
+	c.Assert(g.acquire(4), Equals, 4)
+	c.Assert(g.acquire(4), Equals, 4)
+	// Only the free tokens are granted.
+	c.Assert(g.acquire(4), Equals, 2)
+	// At least one token is granted even if the limit is reached.
+	c.Assert(g.acquire(4), Equals, 1)
+	c.Assert(g.tokensInUse(), Equals, 11)
+	g.release(4)
+	c.Assert(g.acquire(5), Equals, 3)
+	g.release(4)
+	g.release(2)
+	g.release(1)
+	g.release(3)
+	c.Assert(g.tokensInUse(), Equals, 0)
+}
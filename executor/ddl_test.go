@@ -14,18 +14,22 @@
 package executor_test
 
 import (
+	"context"
 	"fmt"
 	. "github.com/pingcap/check"
 	ddlutil "github.com/pingcap/tidb/ddl/util"
 	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/util/testkit"
 	"math"
 	"strings"
+	"time"
 )
 
 func (s *testSuite6) TestCreateDropDatabase(c *C) {
@@ -166,6 +170,18 @@ func (s *testSuite6) TestMaxHandleAddIndex(c *C) {
 
 }
 
+func (s *testSuite6) TestShowDDLProgress(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tk.MustQuery("admin show ddl progress").Check(testkit.Rows())
+	// The finished jobs are moved to the history queue and not shown.
+	tk.MustExec("alter table t add index idx_b(b)")
+	tk.MustQuery("admin show ddl progress").Check(testkit.Rows())
+}
+
 func (s *testSuite6) TestSetDDLReorgWorkerCnt(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -263,3 +279,302 @@ func (s *testSuite6) TestSetDDLErrorCountLimit(c *C) {
 	res := tk.MustQuery("select @@global.tidb_ddl_error_count_limit")
 	res.Check(testkit.Rows("100"))
 }
+
+func (s *testSuite6) TestExpressionIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b varchar(20), c int)")
+	tk.MustExec("insert into t values (1, 'ab', 10), (2, 'cd', 20), (3, 'efg', 30)")
+	// The existing rows are backfilled.
+	tk.MustExec("create index idx on t((length(b)))")
+	tk.MustExec("insert into t values (4, 'hi', 40)")
+	tk.MustExec("insert into t(a, b) values (5, 'jkl')")
+	tk.MustQuery("select a from t where length(b) = 2 order by a").Check(testkit.Rows("1", "2", "4"))
+	tk.MustQuery("select a from t where length(b) = 3 order by a").Check(testkit.Rows("3", "5"))
+	explain := tk.MustQuery("explain select a from t where length(b) = 2").Rows()
+	c.Assert(fmt.Sprintf("%v", explain), Matches, `.*IndexScan.*range:\[2,2\].*`)
+	// The hidden column isn't visible to the users.
+	tk.MustQuery("select * from t where a = 5").Check(testkit.Rows("5 jkl <nil>"))
+	tk.MustGetErrCode("insert into t values (6, 'x', 60, 'x')", mysql.ErrWrongValueCountOnRow)
+	tk.MustQuery("show create table t").CheckAt([]int{1}, [][]interface{}{{"CREATE TABLE `t` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` varchar(20) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`),\n" +
+		"  KEY `idx` ((length(`b`)))\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"}})
+
+	// The index entries are removed with the rows.
+	tk.MustExec("delete from t where a = 1")
+	tk.MustExec("replace into t values (2, 'mnop', 20)")
+	tk.MustQuery("select a from t where length(b) = 2 order by a").Check(testkit.Rows("4"))
+	tk.MustQuery("select a from t where length(b) = 4").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where length(b) >= 0 order by a").Check(testkit.Rows("2", "3", "4", "5"))
+
+	tk.MustGetErrCode("alter table t drop column b", mysql.ErrDependentByFunctionalIndex)
+	tk.MustGetErrCode("alter table t change b d varchar(20)", mysql.ErrDependentByFunctionalIndex)
+	tk.MustGetErrCode("create index idx1 on t((b))", mysql.ErrFunctionalIndexOnField)
+	tk.MustGetErrCode("create index idx1 on t((@a + c))", mysql.ErrFunctionalIndexFunctionIsNotAllowed)
+	tk.MustGetErrCode("create index idx1 on t(((select 1)))", mysql.ErrFunctionalIndexFunctionIsNotAllowed)
+	tk.MustGetErrCode("create index idx1 on t(((c, c)))", mysql.ErrFunctionalIndexRowValueIsNotAllowed)
+	tk.MustExec("create unique index idx1 on t((c + 1))")
+	tk.MustGetErrCode("insert into t values (6, 'z', 40)", mysql.ErrDupEntry)
+	tk.MustExec("insert into t values (6, 'z', 50)")
+	tk.MustGetErrCode("create unique index idx2 on t((c - c))", mysql.ErrDupEntry)
+
+	// The hidden column is removed with the index.
+	tk.MustExec("drop index idx on t")
+	tk.MustExec("alter table t drop column b")
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().Columns, HasLen, 3)
+
+	tk.MustExec("drop table t")
+	tk.MustGetErrCode("create table t(a int, primary key((a + 1)))", mysql.ErrFunctionalIndexPrimaryKey)
+	tk.MustGetErrCode("create table t(a int auto_increment, key(a), key((a + 1)))", mysql.ErrFunctionalIndexRefAutoIncrement)
+	tk.MustExec("create table t(a int, b int, key((a + b)))")
+	tk.MustExec("insert into t values (1, 2), (2, 3)")
+	tk.MustQuery("select * from t where a + b = 5").Check(testkit.Rows("2 3"))
+	tk.MustQuery("show create table t").CheckAt([]int{1}, [][]interface{}{{"CREATE TABLE `t` (\n" +
+		"  `a` int(11) DEFAULT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  KEY `expression_index` ((`a` + `b`))\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"}})
+}
+
+func (s *testSuite6) TestInvisibleIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(a int primary key, b int, c int, key idx_b(b), unique key idx_c(c) invisible)")
+	tk.MustExec("insert into t values (1, 10, 100), (2, 20, 200)")
+	useIndex := func(sql string) bool {
+		rows := tk.MustQuery("explain " + sql).Rows()
+		return strings.Contains(fmt.Sprintf("%v", rows), "IndexScan")
+	}
+	c.Assert(useIndex("select a from t where b = 10"), IsTrue)
+	c.Assert(useIndex("select a from t where c = 100"), IsFalse)
+	// The invisible unique index still checks the duplicated values.
+	tk.MustGetErrCode("insert into t values (3, 30, 100)", mysql.ErrDupEntry)
+	tk.MustGetErrCode("select a from t use index(idx_c) where c = 100", mysql.ErrKeyDoesNotExist)
+
+	tk.MustExec("alter table t alter index idx_b invisible")
+	c.Assert(useIndex("select a from t where b = 10"), IsFalse)
+	tk.MustQuery("select a from t where b = 10").Check(testkit.Rows("1"))
+	tk.MustExec("insert into t values (3, 10, 300)")
+	tk.MustQuery("show create table t").CheckAt([]int{1}, [][]interface{}{{"CREATE TABLE `t` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`),\n" +
+		"  KEY `idx_b` (`b`) /*!80000 INVISIBLE */,\n" +
+		"  UNIQUE KEY `idx_c` (`c`) /*!80000 INVISIBLE */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"}})
+	tk.MustQuery("select index_name, is_visible from information_schema.statistics where table_schema = 'test' and table_name = 't'").Check(
+		testkit.Rows("PRIMARY YES", "idx_b NO", "idx_c NO"))
+
+	// The rows written when the index is invisible are read by the index after it becomes visible.
+	tk.MustExec("alter table t alter index idx_b visible")
+	c.Assert(useIndex("select a from t where b = 10"), IsTrue)
+	tk.MustQuery("select a from t where b = 10 order by a").Check(testkit.Rows("1", "3"))
+	tk.MustExec("create index idx_bc on t(b, c) invisible")
+	c.Assert(useIndex("select b, c from t where b = 10 and c = 300"), IsTrue)
+	tk.MustExec("alter table t alter index idx_b invisible")
+	c.Assert(useIndex("select b, c from t where b = 10 and c = 300"), IsFalse)
+
+	tk.MustGetErrCode("alter table t alter index idx_d invisible", mysql.ErrKeyDoesNotExist)
+	tk.MustGetErrCode("create table t1(a int, primary key(a) invisible)", mysql.ErrPKIndexCantBeInvisible)
+	tk.MustExec("create table t1(a varchar(10) primary key)")
+	tk.MustGetErrCode("alter table t1 alter index `primary` invisible", mysql.ErrPKIndexCantBeInvisible)
+	tk.MustExec("alter table t1 alter index `primary` visible")
+}
+
+func (s *testSuite6) TestDescIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(10), key idx_bc(b, c desc))")
+	tk.MustExec("insert into t values (1, 1, 'a'), (2, 1, 'b'), (3, 1, null), (4, 2, 'a'), (5, 2, 'c'), (6, null, 'b')")
+	tk.MustQuery("show create table t").CheckAt([]int{1}, [][]interface{}{{"CREATE TABLE `t` (\n" +
+		"  `a` int(11) NOT NULL,\n" +
+		"  `b` int(11) DEFAULT NULL,\n" +
+		"  `c` varchar(10) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`a`),\n" +
+		"  KEY `idx_bc` (`b`,`c` DESC)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"}})
+	tk.MustQuery("select column_name, collation from information_schema.statistics where table_schema = 'test' and table_name = 't' and index_name = 'idx_bc'").Check(
+		testkit.Rows("b A", "c D"))
+
+	hasSort := func(sql string) bool {
+		rows := tk.MustQuery("explain " + sql).Rows()
+		return strings.Contains(fmt.Sprintf("%v", rows), "Sort")
+	}
+	// The index keeps the mixed order, so it can be read in both directions without sort.
+	sql := "select b, c from t use index(idx_bc) order by b, c desc"
+	c.Assert(hasSort(sql), IsFalse)
+	tk.MustQuery(sql).Check(testkit.Rows("<nil> b", "1 b", "1 a", "1 <nil>", "2 c", "2 a"))
+	sql = "select b, c from t use index(idx_bc) order by b desc, c"
+	c.Assert(hasSort(sql), IsFalse)
+	tk.MustQuery(sql).Check(testkit.Rows("2 a", "2 c", "1 <nil>", "1 a", "1 b", "<nil> b"))
+	c.Assert(hasSort("select b, c from t use index(idx_bc) order by b, c"), IsTrue)
+	sql = "select c from t use index(idx_bc) where b = 1 order by c desc limit 2"
+	c.Assert(hasSort(sql), IsFalse)
+	tk.MustQuery(sql).Check(testkit.Rows("b", "a"))
+
+	// The ranges on the descending column.
+	tk.MustQuery("select a from t use index(idx_bc) where b = 1 and c > 'a'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t use index(idx_bc) where b = 1 and c >= 'a' order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t use index(idx_bc) where b = 1 and c < 'b'").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t use index(idx_bc) where b = 1 and c is null").Check(testkit.Rows("3"))
+	tk.MustQuery("select a from t use index(idx_bc) where b = 1 and c is not null order by a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t use index(idx_bc) where b = 2 and c in ('a', 'c', 'd') order by a").Check(testkit.Rows("4", "5"))
+	tk.MustQuery("select a from t use index(idx_bc) where (b = 1 and c > 'a') or b = 2 or b is null order by a").Check(testkit.Rows("2", "4", "5", "6"))
+
+	// The rows updated in the transaction are merged in the index order.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (7, 1, 'c')")
+	tk.MustExec("delete from t where a = 4")
+	tk.MustExec("insert into t values (8, 2, 'z')")
+	tk.MustQuery("select b, c from t use index(idx_bc) where b >= 1 order by b, c desc").Check(
+		testkit.Rows("1 c", "1 b", "1 a", "1 <nil>", "2 z", "2 c"))
+	tk.MustExec("rollback")
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (2, 3)")
+	tk.MustGetErrCode("alter table t add unique index idx_a(a desc)", mysql.ErrDupEntry)
+	tk.MustExec("delete from t where b = 3")
+	tk.MustExec("alter table t add unique index idx_a(a desc)")
+	tk.MustGetErrCode("insert into t values (1, 4)", mysql.ErrDupEntry)
+	tk.MustQuery("select max(a) from t").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where a < 2").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite6) TestTTLTable(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(id int primary key, created_at bigint, name varchar(10)) ttl = created_at + interval 1 day")
+	tk.MustQuery("show create table t").CheckAt([]int{1}, [][]interface{}{{"CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `created_at` bigint(20) DEFAULT NULL,\n" +
+		"  `name` varchar(10) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /*T!40200 TTL=`created_at` + INTERVAL 1 DAY */ /*T!40200 TTL_ENABLE='ON' */"}})
+
+	tk.MustGetErrCode("create table t1(a int, b varchar(10)) ttl = b + interval 1 day", mysql.ErrUnsupportedColumnInTTLConfig)
+	tk.MustGetErrCode("create table t1(a int) ttl = b + interval 1 day", mysql.ErrBadField)
+	tk.MustGetErrCode("create table t1(a int) ttl_enable = 'off'", mysql.ErrSetTTLOptionForNonTTLTable)
+	tk.MustGetErrCode("alter table t drop column created_at", mysql.ErrTTLColumnCannotDrop)
+	tk.MustGetErrCode("alter table t ttl = name + interval 1 day", mysql.ErrUnsupportedColumnInTTLConfig)
+
+	tk.MustExec("alter table t ttl_enable = 'off'")
+	tk.MustExec("alter table t change created_at updated_at bigint")
+	tk.MustExec("alter table t ttl = updated_at + interval 2 hour")
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().TTLInfo, DeepEquals, &model.TTLInfo{
+		ColumnName:    model.NewCIStr("updated_at"),
+		IntervalValue: 2,
+		IntervalUnit:  "HOUR",
+		Enable:        false,
+	})
+
+	tk.MustExec("alter table t remove ttl")
+	tbl, err = domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	c.Assert(tbl.Meta().TTLInfo, IsNil)
+	tk.MustExec("alter table t drop column updated_at")
+}
+
+func (s *testSuite6) TestTTLJob(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(id int primary key, created_at bigint) ttl = created_at + interval 1 hour")
+	tk.MustExec("create table t1(id int primary key, created_at bigint) ttl = created_at + interval 1 hour ttl_enable = 'off'")
+	now := time.Now()
+	expired, alive := now.Add(-2*time.Hour).Unix(), now.Add(-time.Minute).Unix()
+	for i := 0; i < 10; i++ {
+		createdAt := expired
+		if i%2 == 0 {
+			createdAt = alive
+		}
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, createdAt))
+		tk.MustExec(fmt.Sprintf("insert into t1 values (%d, %d)", i, createdAt))
+	}
+	tk.MustExec("insert into t values (10, null)")
+
+	tk.MustExec("set @@global.tidb_ttl_delete_batch_size = 2")
+	defer tk.MustExec(fmt.Sprintf("set @@global.tidb_ttl_delete_batch_size = %d", variable.DefTiDBTTLDeleteBatchSize))
+	c.Assert(executor.RunTTLJobs(context.Background(), tk.Se, now), IsNil)
+	tk.MustQuery("select id from t").Check(testkit.Rows("0", "2", "4", "6", "8", "10"))
+	// TTL_ENABLE is off.
+	tk.MustQuery("select count(*) from t1").Check(testkit.Rows("10"))
+
+	tk.MustExec("set @@global.tidb_ttl_delete_batch_size = 0")
+	tk.MustQuery("select @@global.tidb_ttl_delete_batch_size").Check(testkit.Rows("1"))
+}
+
+func (s *testSuite6) TestCacheTable(c *C) {
+	defer func(d time.Duration) { tables.CachedTableLeaseDuration = d }(tables.CachedTableLeaseDuration)
+	tables.CachedTableLeaseDuration = 500 * time.Millisecond
+
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, v int, key idx_v(v))")
+	tk.MustExec("insert into t values (1, 30), (2, 20), (3, 10)")
+	tk.MustExec("alter table t cache")
+	// Cache the table again is a no-op.
+	tk.MustExec("alter table t cache")
+	tk.MustQuery("show create table t").CheckAt([]int{1}, [][]interface{}{{"CREATE TABLE `t` (\n" +
+		"  `id` int(11) NOT NULL,\n" +
+		"  `v` int(11) DEFAULT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `idx_v` (`v`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin /* CACHED ON */"}})
+
+	tk.MustGetErrCode("alter table t add column c int", mysql.ErrOptOnCacheTable)
+	tk.MustGetErrCode("create index idx_id on t(id)", mysql.ErrOptOnCacheTable)
+	tk.MustGetErrCode("drop index idx_v on t", mysql.ErrOptOnCacheTable)
+	tk.MustGetErrCode("drop table t", mysql.ErrOptOnCacheTable)
+	tk.MustGetErrCode("alter table mysql.tidb cache", mysql.ErrUnsupportedDDLOperation)
+
+	// The cache is loaded in the background when the table is read.
+	readFromCache := func(sql string) bool {
+		tk.MustQuery(sql)
+		return tk.Se.GetSessionVars().StmtCtx.ReadFromTableCache
+	}
+	for i := 0; !readFromCache("select * from t"); i++ {
+		c.Assert(i, Less, 100)
+		time.Sleep(50 * time.Millisecond)
+	}
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 30", "2 20", "3 10"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.ReadFromTableCache, IsTrue)
+	tk.MustQuery("select v from t use index(idx_v) where v > 10 order by v desc").Check(testkit.Rows("30", "20"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.ReadFromTableCache, IsTrue)
+	tk.MustQuery("select * from t use index(idx_v) where v >= 10 order by v").Check(testkit.Rows("3 10", "2 20", "1 30"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.ReadFromTableCache, IsTrue)
+
+	// The writes wait for the cache to expire, and are visible to the reads after that.
+	tk.MustExec("insert into t values (4, 0)")
+	tk.MustExec("delete from t where id = 1")
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 20", "3 10", "4 0"))
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (5, 5)")
+	tk.MustQuery("select * from t use index(idx_v) where v >= 0 order by v").Check(testkit.Rows("4 0", "5 5", "3 10", "2 20"))
+	tk.MustExec("commit")
+	for i := 0; !readFromCache("select * from t"); i++ {
+		c.Assert(i, Less, 100)
+		time.Sleep(50 * time.Millisecond)
+	}
+	tk.MustQuery("select * from t").Check(testkit.Rows("2 20", "3 10", "4 0", "5 5"))
+
+	tk.MustExec("alter table t nocache")
+	tk.MustExec("alter table t nocache")
+	tk.MustQuery("select * from t")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.ReadFromTableCache, IsFalse)
+	tk.MustExec("drop table t")
+}
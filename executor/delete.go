@@ -106,10 +106,10 @@ func (e *DeleteExec) removeRow(ctx sessionctx.Context, t table.Table, h int64, d
 
 // Close implements the Executor Close interface.
 func (e *DeleteExec) Close() error {
-	return e.children[0].Close()
+	return Close(e.children[0])
 }
 
 // Open implements the Executor Open interface.
 func (e *DeleteExec) Open(ctx context.Context) error {
-	return e.children[0].Open(ctx)
+	return Open(ctx, e.children[0])
 }
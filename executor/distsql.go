@@ -181,7 +181,7 @@ type IndexReaderExecutor struct {
 
 	// result returns one or more distsql.PartialResult and each PartialResult is returned by one region.
 	result distsql.SelectResult
-	// columns are only required by union scan.
+	// columns are only required by union scan and virtual column.
 	columns []*model.ColumnInfo
 	// outputColumns are only required by union scan.
 	outputColumns []*expression.Column
@@ -207,7 +207,7 @@ func (e *IndexReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error
 // Open implements the Executor Open interface.
 func (e *IndexReaderExecutor) Open(ctx context.Context) error {
 	var err error
-	kvRanges, err := distsql.IndexRangesToKVRanges(e.ctx.GetSessionVars().StmtCtx, e.physicalTableID, e.index.ID, e.ranges)
+	kvRanges, err := distsql.IndexRangesToKVRangesWithDesc(e.ctx.GetSessionVars().StmtCtx, e.physicalTableID, e.index.ID, e.index.DescColumns(), e.ranges)
 	if err != nil {
 		return err
 	}
@@ -233,7 +233,9 @@ func (e *IndexReaderExecutor) open(ctx context.Context, kvRanges []kv.KeyRange)
 	return err
 }
 
-// IndexLookUpExecutor implements double read for index scan.
+// IndexLookUpExecutor implements double read for index scan. It's also used by the late
+// materialization of table scan, in which case the index is nil and the handles are read
+// by a table scan which only reads the columns used by the filters.
 type IndexLookUpExecutor struct {
 	baseExecutor
 
@@ -247,7 +249,7 @@ type IndexLookUpExecutor struct {
 	// handleIdx is the index of handle, which is only used for case of keeping order.
 	handleIdx    int
 	tableRequest *tipb.DAGRequest
-	// columns are only required by union scan.
+	// columns are only required by union scan and virtual column.
 	columns []*model.ColumnInfo
 	*dataReaderBuilder
 	// All fields above are immutable.
@@ -258,6 +260,8 @@ type IndexLookUpExecutor struct {
 
 	kvRanges      []kv.KeyRange
 	workerStarted bool
+	// workerTokens is the number of tokens acquired from the concurrency governor.
+	workerTokens int
 
 	resultCh   chan *lookupTableTask
 	resultCurr *lookupTableTask
@@ -266,12 +270,20 @@ type IndexLookUpExecutor struct {
 	tblPlans []plannercore.PhysicalPlan
 	idxCols  []*expression.Column
 	colLens  []int
+
+	// virtualColumnIndex and virtualColumnRetFieldTypes describe the virtual columns of the table reader.
+	virtualColumnIndex         []int
+	virtualColumnRetFieldTypes []*types.FieldType
 }
 
 // Open implements the Executor Open interface.
 func (e *IndexLookUpExecutor) Open(ctx context.Context) error {
+	if e.index == nil {
+		e.kvRanges = distsql.TableRangesToKVRanges(getPhysicalTableID(e.table), e.ranges)
+		return e.open(ctx)
+	}
 	var err error
-	e.kvRanges, err = distsql.IndexRangesToKVRanges(e.ctx.GetSessionVars().StmtCtx, getPhysicalTableID(e.table), e.index.ID, e.ranges)
+	e.kvRanges, err = distsql.IndexRangesToKVRangesWithDesc(e.ctx.GetSessionVars().StmtCtx, getPhysicalTableID(e.table), e.index.ID, e.index.DescColumns(), e.ranges)
 	if err != nil {
 		return err
 	}
@@ -348,7 +360,8 @@ func (e *IndexLookUpExecutor) startIndexWorker(ctx context.Context, kvRanges []k
 
 // startTableWorker launchs some background goroutines which pick tasks from workCh and execute the task.
 func (e *IndexLookUpExecutor) startTableWorker(ctx context.Context, workCh <-chan *lookupTableTask) {
-	lookupConcurrencyLimit := e.ctx.GetSessionVars().IndexLookupConcurrency
+	lookupConcurrencyLimit := globalConcurrencyGovernor.acquire(e.ctx.GetSessionVars().IndexLookupConcurrency)
+	e.workerTokens = lookupConcurrencyLimit
 	e.tblWorkerWg.Add(lookupConcurrencyLimit)
 	for i := 0; i < lookupConcurrencyLimit; i++ {
 		worker := &tableWorker{
@@ -376,6 +389,9 @@ func (e *IndexLookUpExecutor) buildTableReader(ctx context.Context, handles []in
 		startTS:      e.startTS,
 		columns:      e.columns,
 		plans:        e.tblPlans,
+
+		virtualColumnIndex:         e.virtualColumnIndex,
+		virtualColumnRetFieldTypes: e.virtualColumnRetFieldTypes,
 	}
 	tableReader, err := e.dataReaderBuilder.buildTableReaderFromHandles(ctx, tableReaderExec, handles)
 	if err != nil {
@@ -398,6 +414,8 @@ func (e *IndexLookUpExecutor) Close() error {
 	}
 	e.idxWorkerWg.Wait()
 	e.tblWorkerWg.Wait()
+	globalConcurrencyGovernor.release(e.workerTokens)
+	e.workerTokens = 0
 	e.finished = nil
 	e.workerStarted = false
 	return nil
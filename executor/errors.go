@@ -41,6 +41,8 @@ var (
 	ErrWrongObject                 = terror.ClassExecutor.New(mysql.ErrWrongObject, mysql.MySQLErrName[mysql.ErrWrongObject])
 	ErrRoleNotGranted              = terror.ClassPrivilege.New(mysql.ErrRoleNotGranted, mysql.MySQLErrName[mysql.ErrRoleNotGranted])
 	ErrQueryInterrupted            = terror.ClassExecutor.New(mysql.ErrQueryInterrupted, mysql.MySQLErrName[mysql.ErrQueryInterrupted])
+	ErrSubqueryMoreThan1Row        = terror.ClassExecutor.New(mysql.ErrSubqueryNo1Row, mysql.MySQLErrName[mysql.ErrSubqueryNo1Row])
+	ErrMaxExecTimeExceeded         = terror.ClassExecutor.New(mysql.ErrMaxExecTimeExceeded, mysql.MySQLErrName[mysql.ErrMaxExecTimeExceeded])
 )
 
 func init() {
@@ -68,6 +70,9 @@ func init() {
 		mysql.ErrRoleNotGranted:              mysql.ErrRoleNotGranted,
 		mysql.ErrQueryInterrupted:            mysql.ErrQueryInterrupted,
 		mysql.ErrWrongValueCountOnRow:        mysql.ErrWrongValueCountOnRow,
+		mysql.ErrMemExceedThreshold:          mysql.ErrMemExceedThreshold,
+		mysql.ErrSubqueryNo1Row:              mysql.ErrSubqueryNo1Row,
+		mysql.ErrMaxExecTimeExceeded:         mysql.ErrMaxExecTimeExceeded,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExecutor] = tableMySQLErrCodes
 }
@@ -19,6 +19,7 @@ import (
 	"sync/atomic"
 
 	"github.com/cznic/mathutil"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
@@ -40,6 +41,7 @@ var (
 	_ Executor = &IndexLookUpExecutor{}
 	_ Executor = &IndexReaderExecutor{}
 	_ Executor = &LimitExec{}
+	_ Executor = &MaxOneRowExec{}
 	_ Executor = &MergeJoinExec{}
 	_ Executor = &ProjectionExec{}
 	_ Executor = &SelectionExec{}
@@ -60,6 +62,8 @@ type baseExecutor struct {
 	maxChunkSize  int
 	children      []Executor
 	retFieldTypes []*types.FieldType
+	// openSpan is the tracing span the executor is opened in, its Close is traced after it.
+	openSpan opentracing.Span
 }
 
 // base returns the baseExecutor of an executor, don't override this method!
@@ -70,7 +74,7 @@ func (e *baseExecutor) base() *baseExecutor {
 // Open initializes children recursively and "childrenResults" according to children's schemas.
 func (e *baseExecutor) Open(ctx context.Context) error {
 	for _, child := range e.children {
-		err := child.Open(ctx)
+		err := Open(ctx, child)
 		if err != nil {
 			return err
 		}
@@ -82,7 +86,7 @@ func (e *baseExecutor) Open(ctx context.Context) error {
 func (e *baseExecutor) Close() error {
 	var firstErr error
 	for _, src := range e.children {
-		if err := src.Close(); err != nil && firstErr == nil {
+		if err := Close(src); err != nil && firstErr == nil {
 			firstErr = err
 		}
 	}
@@ -151,8 +155,26 @@ type Executor interface {
 	Schema() *expression.Schema
 }
 
+// Open is a wrapper function on e.Open(), it traces the open with a child span of the span in ctx.
+// The spans are created through the opentracing API, they're only recorded when the span in ctx
+// comes from a registered tracer.
+func Open(ctx context.Context, e Executor) error {
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan(fmt.Sprintf("%T.Open", e), opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+		e.base().openSpan = span
+	}
+	return e.Open(ctx)
+}
+
 // Next is a wrapper function on e.Next(), it handles some common codes.
 func Next(ctx context.Context, e Executor, req *chunk.Chunk) error {
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan(fmt.Sprintf("%T.Next", e), opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+	}
 	base := e.base()
 	sessVars := base.ctx.GetSessionVars()
 	if atomic.CompareAndSwapUint32(&sessVars.Killed, 1, 0) {
@@ -161,6 +183,16 @@ func Next(ctx context.Context, e Executor, req *chunk.Chunk) error {
 	return e.Next(ctx, req)
 }
 
+// Close is a wrapper function on e.Close(). Close has no context, so the close is
+// traced under the span the executor was opened in.
+func Close(e Executor) error {
+	if span := e.base().openSpan; span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan(fmt.Sprintf("%T.Close", e), opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+	}
+	return e.Close()
+}
+
 // ShowDDLExec represents a show DDL executor.
 type ShowDDLExec struct {
 	baseExecutor
@@ -310,6 +342,56 @@ func (e *LimitExec) adjustRequiredRows(chk *chunk.Chunk) *chunk.Chunk {
 	return chk.SetRequiredRows(mathutil.Min(limitTotal, limitRequired), e.maxChunkSize)
 }
 
+// MaxOneRowExec checks if the number of rows that a query returns is at maximum one.
+// It's built from subquery expression.
+type MaxOneRowExec struct {
+	baseExecutor
+
+	evaluated bool
+}
+
+// Open implements the Executor Open interface.
+func (e *MaxOneRowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.evaluated = false
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *MaxOneRowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.evaluated {
+		return nil
+	}
+	e.evaluated = true
+	err := Next(ctx, e.children[0], req)
+	if err != nil {
+		return err
+	}
+
+	if num := req.NumRows(); num == 0 {
+		// The subquery returns NULL if it returns no row.
+		for i := range e.schema.Columns {
+			req.AppendNull(i)
+		}
+		return nil
+	} else if num != 1 {
+		return ErrSubqueryMoreThan1Row
+	}
+
+	childChunk := newFirstChunk(e.children[0])
+	err = Next(ctx, e.children[0], childChunk)
+	if err != nil {
+		return err
+	}
+	if childChunk.NumRows() != 0 {
+		return ErrSubqueryMoreThan1Row
+	}
+	return nil
+}
+
 // TableDualExec represents a dual table executor.
 type TableDualExec struct {
 	baseExecutor
@@ -348,6 +430,7 @@ type SelectionExec struct {
 
 	batched     bool
 	filters     []expression.Expression
+	fused       *expression.FusedFilter
 	selected    []bool
 	inputIter   *chunk.Iterator4Chunk
 	inputRow    chunk.Row
@@ -363,6 +446,9 @@ func (e *SelectionExec) Open(ctx context.Context) error {
 	e.batched = expression.Vectorizable(e.filters)
 	if e.batched {
 		e.selected = make([]bool, 0, chunk.InitialCapacity)
+		if e.ctx.GetSessionVars().EnableVectorizedExpression {
+			e.fused = expression.NewFusedFilter(e.ctx, e.filters)
+		}
 	}
 	e.inputIter = chunk.NewIterator4Chunk(e.childResult)
 	e.inputRow = e.inputIter.End()
@@ -373,6 +459,7 @@ func (e *SelectionExec) Open(ctx context.Context) error {
 func (e *SelectionExec) Close() error {
 	e.childResult = nil
 	e.selected = nil
+	e.fused = nil
 	return e.baseExecutor.Close()
 }
 
@@ -413,9 +500,13 @@ func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		/* Your code here.
 		   Process and filter the child result using `expression.VectorizedFilter`.
 		*/
-		e.selected, err = expression.VectorizedFilter(e.ctx, e.filters, e.inputIter, e.selected)
-		if err != nil {
-			return err
+		if e.fused != nil {
+			e.selected = e.fused.Filter(e.childResult, e.selected)
+		} else {
+			e.selected, err = expression.VectorizedFilter(e.ctx, e.filters, e.inputIter, e.selected)
+			if err != nil {
+				return err
+			}
 		}
 		e.inputRow = e.inputIter.Begin()
 	}
@@ -563,8 +654,8 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 	if len(hints) == 0 {
 		return
 	}
-	var memoryQuotaHint, useToJAHint *ast.TableOptimizerHint
-	var memoryQuotaHintCnt, useToJAHintCnt, readReplicaHintCnt int
+	var memoryQuotaHint, useToJAHint, maxExecutionTimeHint *ast.TableOptimizerHint
+	var memoryQuotaHintCnt, useToJAHintCnt, readReplicaHintCnt, maxExecutionTimeHintCnt int
 	for _, hint := range hints {
 		switch hint.HintName.L {
 		case "memory_quota":
@@ -575,6 +666,9 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 			useToJAHintCnt++
 		case "read_consistent_replica":
 			readReplicaHintCnt++
+		case "max_execution_time":
+			maxExecutionTimeHint = hint
+			maxExecutionTimeHintCnt++
 		}
 	}
 	// Handle MEMORY_QUOTA
@@ -614,6 +708,15 @@ func handleStmtHints(hints []*ast.TableOptimizerHint) (stmtHints stmtctx.StmtHin
 		stmtHints.HasReplicaReadHint = true
 		stmtHints.ReplicaRead = byte(kv.ReplicaReadFollower)
 	}
+	// Handle MAX_EXECUTION_TIME
+	if maxExecutionTimeHintCnt != 0 {
+		if maxExecutionTimeHintCnt > 1 {
+			warn := errors.New("There are multiple MAX_EXECUTION_TIME hints, only the last one will take effect")
+			warns = append(warns, warn)
+		}
+		stmtHints.HasMaxExecutionTime = true
+		stmtHints.MaxExecutionTime = maxExecutionTimeHint.MaxExecutionTime
+	}
 	return
 }
 
@@ -624,8 +727,9 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	stmtHints, hintWarns := handleStmtHints(hints)
 	vars := ctx.GetSessionVars()
 	sc := &stmtctx.StatementContext{
-		StmtHints: stmtHints,
-		TimeZone:  vars.Location(),
+		StmtHints:   stmtHints,
+		TimeZone:    vars.Location(),
+		OriginalSQL: s.Text(),
 	}
 	if explainStmt, ok := s.(*ast.ExplainStmt); ok {
 		sc.InExplainStmt = true
@@ -648,8 +752,11 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.InInsertStmt = true
 		// For insert statement (not for update statement), disabling the StrictSQLMode
 		// should make TruncateAsWarning and DividedByZeroAsWarning.
-		sc.TruncateAsWarning = !vars.StrictSQLMode
-		sc.DividedByZeroAsWarning = !vars.StrictSQLMode
+		// The IGNORE modifier also turns these errors and the duplicate key errors into warnings.
+		sc.DupKeyAsWarning = stmt.IgnoreErr
+		sc.BadNullAsWarning = stmt.IgnoreErr
+		sc.TruncateAsWarning = !vars.StrictSQLMode || stmt.IgnoreErr
+		sc.DividedByZeroAsWarning = !vars.StrictSQLMode || stmt.IgnoreErr
 		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
 		sc.IgnoreZeroInDate = !vars.StrictSQLMode || sc.AllowInvalidDate
 	case *ast.CreateTableStmt, *ast.AlterTableStmt:
@@ -685,6 +792,7 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 		sc.IgnoreZeroInDate = true
 		sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
 	}
+	sc.VerifyRowChecksum = vars.VerifyRowChecksum
 	if vars.StmtCtx.LastInsertID > 0 {
 		sc.PrevLastInsertID = vars.StmtCtx.LastInsertID
 	} else {
@@ -699,6 +807,12 @@ func ResetContextOfStmt(ctx sessionctx.Context, s ast.StmtNode) (err error) {
 	errCount, warnCount := vars.StmtCtx.NumErrorWarnings()
 	vars.SysErrorCount = errCount
 	vars.SysWarningCount = warnCount
+	vars.PrevFoundInPlanCache = vars.FoundInPlanCache
+	vars.FoundInPlanCache = false
+	vars.PrevFoundInResultCache = vars.FoundInResultCache
+	vars.FoundInResultCache = false
+	vars.PrevFoundInPlanBaseline = vars.FoundInPlanBaseline
+	vars.FoundInPlanBaseline = false
 	vars.StmtCtx = sc
 	for _, warn := range hintWarns {
 		vars.StmtCtx.AppendWarning(warn)
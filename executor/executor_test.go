@@ -22,12 +22,14 @@ import (
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/mocktikv"
 	"github.com/pingcap/tidb/util/logutil"
@@ -37,6 +39,7 @@ import (
 	"math"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -579,6 +582,83 @@ func (s *testSuiteP1) TestOrderBy(c *C) {
 	tk.MustQuery("select c1 as c2 from t order by c2").Check(testkit.Rows("1", "2"))
 	tk.MustQuery("select sum(c1) from t order by sum(c1)").Check(testkit.Rows("3"))
 	tk.MustQuery("select c1 as c2 from t order by c2 + 1").Check(testkit.Rows("2", "1"))
+
+	// Test order by position.
+	tk.MustQuery("select c1, c2 from t order by 2").Check(testkit.Rows("2 1", "1 2"))
+	tk.MustQuery("select c1 + c2, c3 from t order by 2 desc, 1").Check(testkit.Rows("3 bcd", "3 abc"))
+	tk.MustQuery("select * from t order by 3 desc").Check(testkit.Rows("2 1 bcd", "1 2 abc"))
+	tk.MustQuery("select c1 from t order by (2) desc, 1").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select c1, sum(c2) from t group by c1 order by 2").Check(testkit.Rows("2 1", "1 2"))
+	tk.MustQuery("select distinct c1 from t order by 1 desc").Check(testkit.Rows("2", "1"))
+	_, err := tk.Exec("select c1 from t order by 0")
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column '0' in 'order clause'")
+	// The position can not refer to the auxiliary column c2.
+	_, err = tk.Exec("select c1 from t order by c2, 2")
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column '2' in 'order clause'")
+
+	// Test group by position.
+	tk.MustQuery("select c1 + c2, count(*) from t group by 1").Check(testkit.Rows("3 2"))
+	_, err = tk.Exec("select count(*) from t group by 1")
+	c.Assert(err.Error(), Equals, "[planner:1056]Can't group on 'count(*)'")
+	_, err = tk.Exec("select c1 from t group by 2")
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column '2' in 'group statement'")
+}
+
+func (s *testSuiteP1) TestSpillToDisk(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b varchar(2000))")
+	values := make([]string, 0, 600)
+	for i := 0; i < 600; i++ {
+		values = append(values, fmt.Sprintf("(%d, '%s')", i*7%600, strings.Repeat("x", 2000)))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+	defer tk.MustExec(fmt.Sprintf("set @@tidb_max_chunk_size = %d", variable.DefMaxChunkSize))
+
+	// The rows of sort are spilled as sorted runs and merged.
+	expected := make([]string, 0, 600)
+	for i := 599; i >= 0; i-- {
+		expected = append(expected, fmt.Sprintf("%d 2000", i))
+	}
+	tk.MustQuery("select /*+ MEMORY_QUOTA(1 MB) */ a, length(b) from (select a, b from t order by a desc) tt").Check(testkit.Rows(expected...))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.SpilledBytes(), Greater, int64(1<<20))
+	tk.MustQuery("select a, length(b) from (select a, b from t order by a desc) tt").Check(testkit.Rows(expected...))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.SpilledBytes(), Equals, int64(0))
+
+	// The build side rows of hash join are spilled.
+	tk.MustQuery("select /*+ MEMORY_QUOTA(1 MB) */ count(*), sum(t1.a), sum(length(t1.b) + length(t2.b)) from t t1 join t t2 on t1.a = t2.a").Check(testkit.Rows("600 179700 2400000"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.SpilledBytes(), Greater, int64(1<<20))
+	tk.MustExec("drop table t")
+}
+
+func (s *testSuiteP1) TestMaxExecutionTime(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int)")
+	values := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		values = append(values, fmt.Sprintf("(%d)", i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+
+	// The apply reads the inner table for every outer row, it takes far more than 1 ms.
+	sql := "select t1.a, (select count(*) from t t2 where t2.a >= t1.a) from t t1"
+	err := tk.QueryToErr("select /*+ MAX_EXECUTION_TIME(1) */ t1.a, (select count(*) from t t2 where t2.a >= t1.a) from t t1")
+	c.Assert(executor.ErrMaxExecTimeExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustQuery("select /*+ MAX_EXECUTION_TIME(100000) */ count(*) from t").Check(testkit.Rows("200"))
+
+	tk.MustExec("set @@max_execution_time = 1")
+	defer tk.MustExec("set @@max_execution_time = 0")
+	err = tk.QueryToErr(sql)
+	c.Assert(executor.ErrMaxExecTimeExceeded.Equal(err), IsTrue, Commentf("err %v", err))
+	// The hint overrides the variable.
+	tk.MustQuery("select /*+ MAX_EXECUTION_TIME(100000) */ count(*) from t").Check(testkit.Rows("200"))
+	// Only the SELECT statements are limited.
+	tk.MustExec("insert into t select t1.a from t t1 where (select count(*) from t t2 where t2.a >= t1.a) > 0")
+	tk.MustExec("set @@max_execution_time = 0")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("400"))
+	tk.MustExec("drop table t")
 }
 
 func (s *testSuiteP1) TestSelectErrorRow(c *C) {
@@ -889,6 +969,41 @@ func (s *testSuiteP2) TestRow(c *C) {
 	result.Check(testkit.Rows("1"))
 }
 
+func (s *testSuiteP2) TestScalarSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, s")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("create table s (a int, b int)")
+	tk.MustExec("insert t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert s values (1, 10), (1, 11), (2, 20)")
+
+	tk.MustQuery("select a, (select count(*) from s where s.a = t.a) from t").Sort().Check(testkit.Rows("1 2", "2 1", "3 0"))
+	tk.MustQuery("select a, (select max(b) from s where s.a = t.a) from t").Sort().Check(testkit.Rows("1 11", "2 20", "3 <nil>"))
+	tk.MustQuery("select a, (select max(b) from s) from t").Sort().Check(testkit.Rows("1 20", "2 20", "3 20"))
+	tk.MustQuery("select (select 5) from t").Check(testkit.Rows("5", "5", "5"))
+	tk.MustQuery("select a from t where b * 10 = (select min(b) from s where s.a = t.a)").Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where (a, 1) = (select a, 1 from s where b = 20)").Check(testkit.Rows("2"))
+	tk.MustQuery("select a, (select b from s where s.a = t.a + 1 limit 1) x from t order by x, a").Check(testkit.Rows("2 <nil>", "3 <nil>", "1 20"))
+
+	// Aggregates of outer columns are evaluated in the outer query block.
+	tk.MustQuery("select (select count(t.a) from s limit 1) from t").Check(testkit.Rows("3"))
+	tk.MustQuery("select sum(a), (select count(*) from s where s.a = max(t.a)) from t").Check(testkit.Rows("6 0"))
+
+	err := tk.QueryToErr("select a, (select b from s where s.a = t.a) from t")
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*terror.Error).Code(), Equals, terror.ErrCode(mysql.ErrSubqueryNo1Row))
+	_, err = tk.Exec("select a from t where a = (select a, b from s limit 1)")
+	c.Assert(err, NotNil)
+	c.Assert(errors.Cause(err).(*terror.Error).Code(), Equals, terror.ErrCode(mysql.ErrOperandColumns))
+	_, err = tk.Exec("insert t values ((select 1), 1)")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("delete from t where a = (select max(a) from s)")
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "3"))
+	tk.MustExec("drop table t, s")
+}
+
 func (s *testSuiteP2) TestColumnName(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
@@ -1064,6 +1179,103 @@ func (s *testSuite) TestTableScanWithPointRanges(c *C) {
 	tk.MustQuery("select * from t where id in(1, 2, 10)").Check(testkit.Rows("1", "10"))
 }
 
+func (s *testSuite) TestCoprocessorPaging(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key idx_b(b))")
+	tk.MustExec("insert into t values(1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4), (5, 5, 5), (6, 6, 6), (7, 7, 7), (8, 8, 8), (9, 9, 9), (10, 10, 10)")
+	tk.MustExec("set @@tidb_enable_paging = 1")
+	tk.MustExec("set @@tidb_min_paging_size = 1")
+	tk.MustExec("set @@tidb_max_paging_size = 4")
+
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "2", "3", "4", "5", "6", "7", "8", "9", "10"))
+	tk.MustQuery("select a from t order by a desc").Check(testkit.Rows("10", "9", "8", "7", "6", "5", "4", "3", "2", "1"))
+	tk.MustQuery("select a from t where a in (1, 3, 5, 7, 9) order by a desc").Check(testkit.Rows("9", "7", "5", "3", "1"))
+	tk.MustQuery("select * from t where c > 4 order by a limit 2").Check(testkit.Rows("5 5 5", "6 6 6"))
+	tk.MustQuery("select count(*), sum(c) from t").Check(testkit.Rows("10 55"))
+	tk.MustQuery("select b from t use index(idx_b) where b > 2 order by b desc").Check(testkit.Rows("10", "9", "8", "7", "6", "5", "4", "3"))
+	tk.MustQuery("select * from t use index(idx_b) where b between 2 and 5 order by b").Check(testkit.Rows("2 2 2", "3 3 3", "4 4 4", "5 5 5"))
+
+	_, err := tk.Exec("set @@tidb_min_paging_size = 0")
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite) TestCoprocessorChunkEncode(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b double, c varchar(10), d int unsigned, key idx_c(c))")
+	values := make([]string, 0, 200)
+	for i := 0; i < 200; i++ {
+		if i%7 == 0 {
+			values = append(values, fmt.Sprintf("(%d, null, null, null)", i))
+			continue
+		}
+		values = append(values, fmt.Sprintf("(%d, %d.5, 'v%03d', %d)", i, i, i, i*2))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+
+	sqls := []string{
+		"select * from t order by a",
+		"select * from t where a > 150 and b is not null order by a desc",
+		"select c, a from t use index(idx_c) where c > 'v100' order by c",
+		"select * from t use index(idx_c) where c between 'v010' and 'v020' order by a",
+		"select count(*), count(b), sum(d), avg(b), max(c), min(b) from t",
+		"select a > 100 k, count(c), max(b), sum(a) from t group by k order by k",
+	}
+	for _, sql := range sqls {
+		tk.MustExec("set @@tidb_enable_chunk_rpc = 0")
+		expected := tk.MustQuery(sql).Rows()
+		c.Assert(len(expected) > 0, IsTrue)
+		tk.MustExec("set @@tidb_enable_chunk_rpc = 1")
+		tk.MustQuery(sql).Check(expected)
+	}
+}
+
+func (s *testSuite) TestSelectionWithFusedFilter(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b double, c varchar(10))")
+	tk.MustExec("insert into t values(1, 1.5, 'x'), (1, 2.5, 'y'), (2, 3.5, 'x'), (3, null, null), (3, 0.5, 'z'), (3, 4.5, 'z')")
+	sql := "select * from (select a, count(*) cnt, max(b) mb, max(c) mc from t group by a) s where cnt > 1 and mb >= 2.5 and mc != 'y' order by a"
+	for _, vectorized := range []string{"1", "0"} {
+		tk.MustExec("set @@tidb_enable_vectorized_expression = " + vectorized)
+		tk.MustQuery(sql).Check(testkit.Rows("3 3 4.5 z"))
+	}
+
+	// The rows in the transaction buffer are filtered by the selection of the union scan.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values(4, 5.5, 'w')")
+	tk.MustQuery("select a from t where a > 2 and b < 5.0 order by a").Check(testkit.Rows("3", "3"))
+	tk.MustQuery("select a from t where 4 <= a and c = 'w'").Check(testkit.Rows("4"))
+	tk.MustExec("rollback")
+}
+
+func (s *testSuite) TestExecutorConcurrencyLimit(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("set @@global.tidb_executor_concurrency_limit = -1")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect tidb_executor_concurrency_limit value: '-1'"))
+	tk.MustQuery("select @@global.tidb_executor_concurrency_limit").Check(testkit.Rows("0"))
+	_, err := tk.Exec("set @@session.tidb_executor_concurrency_limit = 1")
+	c.Assert(err, NotNil)
+
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1(a int, b int, index idx(b))")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustExec("insert into t1 values(1, 1), (2, 2), (3, 3), (4, 4)")
+	tk.MustExec("insert into t2 values(1, 10), (2, 20), (2, 21), (5, 50)")
+	// The statements run with fewer workers, even a single one, when the limit is reached.
+	defer variable.SetExecConcurrencyLimit(variable.GetExecConcurrencyLimit())
+	for _, limit := range []int64{1, 3, 0} {
+		variable.SetExecConcurrencyLimit(limit)
+		tk.MustQuery("select /*+ HASH_JOIN(t1, t2) */ t1.a, t2.b from t1, t2 where t1.a = t2.a order by t2.b").Check(testkit.Rows("1 10", "2 20", "2 21"))
+		tk.MustQuery("select /*+ HASH_AGG() */ a, count(*), sum(b) from t2 group by a order by a").Check(testkit.Rows("1 1 10", "2 2 41", "5 1 50"))
+		tk.MustQuery("select a from t1 use index(idx) where b > 1 order by a").Check(testkit.Rows("2", "3", "4"))
+	}
+}
+
 func (s *testSuite) TestUnsignedPk(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
 
@@ -1150,6 +1362,180 @@ func (s *testSuite) TestLimit(c *C) {
 	))
 }
 
+func (s *testSuite) TestJSON(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t;`)
+	tk.MustExec(`create table t(id int, j json);`)
+	tk.MustExec(`insert into t values(1, '{"a": [1, "2"], "b": {"c": true}}'), (2, '[1, 2]'), (3, '"abc"'), (4, null);`)
+	tk.MustQuery(`select id, j from t order by id;`).Check(testkit.Rows(
+		`1 {"a": [1, "2"], "b": {"c": true}}`,
+		`2 [1, 2]`,
+		`3 "abc"`,
+		`4 <nil>`,
+	))
+	tk.MustQuery(`select id, json_type(j), json_unquote(j) from t order by id;`).Check(testkit.Rows(
+		`1 OBJECT {"a": [1, "2"], "b": {"c": true}}`,
+		`2 ARRAY [1, 2]`,
+		`3 STRING abc`,
+		`4 <nil> <nil>`,
+	))
+	tk.MustQuery(`select id, json_extract(j, '$.a[1]'), json_extract(j, '$[0]', '$.b') from t order by id;`).Check(testkit.Rows(
+		`1 "2" [{"a": [1, "2"], "b": {"c": true}}, {"c": true}]`,
+		`2 <nil> [1]`,
+		`3 <nil> ["abc"]`,
+		`4 <nil> <nil>`,
+	))
+	tk.MustQuery(`select id, json_set(j, '$.a', 3, '$.d', 'x') from t where id < 3 order by id;`).Check(testkit.Rows(
+		`1 {"a": 3, "b": {"c": true}, "d": "x"}`,
+		`2 [1, 2]`,
+	))
+	tk.MustQuery(`select id from t where json_contains(j, '1', '$.a') order by id;`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select id from t where j is null;`).Check(testkit.Rows("4"))
+	tk.MustQuery(`select id from t where j = '[1, 2]';`).Check(testkit.Rows("2"))
+	_, err := tk.Exec(`insert into t values(5, '{"a": 1');`)
+	c.Assert(err, NotNil)
+	_, err = tk.Exec(`select json_type(id) from t;`)
+	c.Assert(err, NotNil)
+}
+
+func (s *testSuite) TestRegexp(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t;`)
+	tk.MustExec(`create table t(id int, a varchar(20), p varchar(20));`)
+	tk.MustExec(`insert into t values(1, 'abc', '^a'), (2, 'Bcd', 'b'), (3, 'xyz', '[0-9]'), (4, null, 'a'), (5, 'a1', null);`)
+	tk.MustQuery(`select id from t where a regexp '^[ab]' order by id;`).Check(testkit.Rows("1", "2", "5"))
+	tk.MustQuery(`select id from t where a not rlike '^[ab]' order by id;`).Check(testkit.Rows("3"))
+	tk.MustQuery(`select id, a regexp p, regexp_like(a, p, 'c') from t order by id;`).Check(testkit.Rows(
+		"1 1 1",
+		"2 1 0",
+		"3 0 0",
+		"4 <nil> <nil>",
+		"5 <nil> <nil>",
+	))
+	tk.MustQuery(`select 'abc' regexp 'b' and 1, regexp_like('a
+b', '^b$', 'm');`).Check(testkit.Rows("1 1"))
+	err := tk.QueryToErr(`select a regexp '(' from t;`)
+	c.Assert(expression.ErrRegexp.Equal(err), IsTrue)
+}
+
+func (s *testSuite) TestLike(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t;`)
+	tk.MustExec(`create table t(id int, a varchar(20), p varchar(20), b varchar(20) collate utf8mb4_general_ci);`)
+	tk.MustExec(`insert into t values(1, 'abc', 'a%', 'ABC'), (2, '中文字', '_文_', 'Àbc'), (3, 'a%c', 'a|%c', 'xyz'), (4, null, 'a', null), (5, 'a_c', null, 'a_c');`)
+	tk.MustQuery(`select id from t where a like 'a%' order by id;`).Check(testkit.Rows("1", "3", "5"))
+	tk.MustQuery(`select id from t where a not like 'a_c' order by id;`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select id from t where a like '中_字' order by id;`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select id from t where a like 'a\\%c' order by id;`).Check(testkit.Rows("3"))
+	tk.MustQuery(`select id from t where a like 'a|_c' escape '|' order by id;`).Check(testkit.Rows("5"))
+	tk.MustQuery(`select id, a like p, a like p escape '|' from t order by id;`).Check(testkit.Rows(
+		"1 1 1",
+		"2 1 1",
+		"3 0 1",
+		"4 <nil> <nil>",
+		"5 <nil> <nil>",
+	))
+	// The column in the case insensitive collation matches case insensitively.
+	tk.MustQuery(`select id from t where b like 'abc' order by id;`).Check(testkit.Rows("1", "2"))
+	tk.MustQuery(`select id from t where a like 'ABC' order by id;`).Check(testkit.Rows())
+	tk.MustGetErrCode(`select 'a' like 'a' escape '||';`, mysql.ErrWrongArguments)
+}
+
+func (s *testSuite) TestRowComparison(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t;`)
+	tk.MustExec(`create table t(id int, a int, b varchar(20), key(a, b));`)
+	tk.MustExec(`insert into t values(1, 1, 'x'), (2, 1, 'y'), (3, 2, 'x'), (4, null, 'x'), (5, 2, null);`)
+	tk.MustQuery(`select (1, 2) = (1, 2), (1, 2) < (1, 3), (1, 2) <=> (1, null), (null, 2) <=> (null, 2), (1, 2) != (1, null);`).Check(testkit.Rows("1 1 0 1 <nil>"))
+	tk.MustQuery(`select (1, 2) in ((1, 2), (3, 4)), (1, 2) not in ((1, 3)), (1, null) in ((1, 2)), (1, null) in ((1, 2), (1, 3)) is null;`).Check(testkit.Rows("1 1 <nil> 1"))
+	tk.MustQuery(`select id from t where (a, b) = (1, 'y');`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select id from t where (a, b) > (1, 'x') order by id;`).Check(testkit.Rows("2", "3", "5"))
+	tk.MustQuery(`select id from t where (a, b) in ((1, 'x'), (2, 'x'), (null, 'x')) order by id;`).Check(testkit.Rows("1", "3"))
+	tk.MustQuery(`select id from t where (a, b) not in ((1, 'x'), (2, 'x')) order by id;`).Check(testkit.Rows("2"))
+	tk.MustQuery(`select id from t where (a, b) <=> (null, 'x');`).Check(testkit.Rows("4"))
+	tk.MustQuery(`select id from t where (a, b) <=> (2, null);`).Check(testkit.Rows("5"))
+	tk.MustQuery(`select id, a <=> 1, b <=> null from t order by id;`).Check(testkit.Rows("1 1 0", "2 1 0", "3 0 0", "4 0 0", "5 0 1"))
+	tk.MustGetErrCode(`select (1, 2) = (1, 2, 3);`, mysql.ErrOperandColumns)
+	tk.MustGetErrCode(`select (1, 2) in ((1, 2), 3);`, mysql.ErrOperandColumns)
+
+	// The NULL-safe equality on the inner side doesn't reject the NULL-extended rows.
+	tk.MustQuery(`select t1.id, t2.id from t t1 left join t t2 on t1.a = t2.a and t2.b = 'y' where t2.b <=> t1.b order by t1.id;`).Check(testkit.Rows("2 2", "5 <nil>"))
+}
+
+func (s *testSuite) TestCollation(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t, t1;`)
+	tk.MustExec(`create table t(id int, a varchar(20) collate utf8mb4_general_ci, b varchar(20) collate utf8mb4_unicode_ci, c varchar(20), key(a));`)
+	tk.MustExec(`insert into t values(1, 'abc', 'Straße', 'abc'), (2, 'ABC ', 'strasse', 'ABC'), (3, 'Àbc', 'STRASSE', 'xyz'), (4, 'abd', 'strase', null);`)
+	tk.MustQuery(`select id from t where a = 'abc' order by id;`).Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery(`select id from t where a > 'abc' order by id;`).Check(testkit.Rows("4"))
+	tk.MustQuery(`select id from t where a in ('ABC', 'x') order by id;`).Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery(`select id from t where b = 'strasse' order by id;`).Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery(`select id, strcmp(a, 'ABC') from t order by id;`).Check(testkit.Rows("1 0", "2 0", "3 0", "4 1"))
+	// The column in the binary collation is compared by bytes.
+	tk.MustQuery(`select id from t where c = 'abc' order by id;`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select id from t where a = c order by id;`).Check(testkit.Rows("1"))
+	tk.MustQuery(`select count(*) from t group by a order by count(*);`).Check(testkit.Rows("1", "3"))
+	tk.MustQuery(`select count(*) from t group by b order by count(*);`).Check(testkit.Rows("1", "3"))
+	// The constant is not propagated through the case insensitive equal condition.
+	tk.MustQuery(`select id from t where a = 'ABC' and a = c order by id;`).Check(testkit.Rows("1"))
+
+	tk.MustExec(`create table t1(a varchar(20) collate utf8mb4_general_ci, c varchar(20));`)
+	tk.MustExec(`insert into t1 values('ABC', 'ABC'), ('abd', 'abd');`)
+	tk.MustQuery(`select t.id, t1.a from t join t1 on t.a = t1.a order by t.id;`).Check(testkit.Rows("1 ABC", "2 ABC", "3 ABC", "4 abd"))
+	tk.MustQuery(`select /*+ TIDB_SMJ(t, t1) */ t.id from t join t1 on t.a = t1.a order by t.id;`).Check(testkit.Rows("1", "2", "3", "4"))
+	// The columns in different collations are compared by bytes.
+	tk.MustQuery(`select t.id from t join t1 on t.a = t1.c order by t.id;`).Check(testkit.Rows("4"))
+}
+
+func (s *testSuite) TestStringBuiltins(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t;`)
+	tk.MustExec(`create table t(id int, a varchar(20), n int);`)
+	tk.MustExec(`insert into t values(1, 'www.pingcap.com', 2), (2, '你好.世界', -1), (3, null, 1), (4, 'ab', null);`)
+	tk.MustQuery(`select id, substring_index(a, '.', n), lpad(a, 4, '中'), rpad(a, 4, '?'), instr(a, '世界'), replace(a, '.', '-'), repeat(a, n) from t order by id;`).Check(testkit.Rows(
+		"1 www.pingcap www. www. 0 www-pingcap-com www.pingcap.comwww.pingcap.com",
+		"2 世界 你好.世 你好.世 4 你好-世界 ",
+		"3 <nil> <nil> <nil> <nil> <nil> <nil>",
+		"4 <nil> 中中ab ab?? 0 ab <nil>",
+	))
+	tk.MustQuery(`select lpad('hi', -1, '?'), rpad('hi', 3, ''), instr('FooBar', 'bar'), repeat('ab', 0);`).Check(testkit.Rows("<nil> <nil> 4 "))
+	tk.MustQuery(`select repeat('a', 67108865);`).Check(testkit.Rows("<nil>"))
+	tk.MustQuery(`show warnings;`).Check(testkit.Rows("Warning 1301 Result of repeat() was larger than max_allowed_packet (67108864) - truncated"))
+}
+
+func (s *testSuite) TestEncryptionBuiltins(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec(`use test;`)
+	tk.MustExec(`drop table if exists t;`)
+	tk.MustExec(`create table t(id int, a varchar(20));`)
+	tk.MustExec(`insert into t values(1, 'pingcap'), (2, ''), (3, null);`)
+	tk.MustQuery(`select id, md5(a), sha1(a), crc32(a) from t order by id;`).Check(testkit.Rows(
+		"1 144653a024bda5fe1ace260bad67aff9 73bf9ef43a44f42e2ea2894d62f0917af149a006 3095267826",
+		"2 d41d8cd98f00b204e9800998ecf8427e da39a3ee5e6b4b0d3255bfef95601890afd80709 0",
+		"3 <nil> <nil> <nil>",
+	))
+	tk.MustQuery(`select sha2('abc', 224), sha2('abc', 1), sha('abc');`).Check(testkit.Rows(
+		"23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7 <nil> a9993e364706816aba3e25717850c26c9cd0d89d"))
+
+	tk.MustQuery(`select id, length(aes_encrypt(a, 'key')), aes_decrypt(aes_encrypt(a, 'key'), 'key'), aes_decrypt(aes_encrypt(a, 'key'), 'other key') from t order by id;`).Check(testkit.Rows(
+		"1 16 pingcap <nil>",
+		"2 16  <nil>",
+		"3 <nil> <nil> <nil>",
+	))
+	tk.MustExec(`set @@block_encryption_mode = 'aes-256-cbc';`)
+	tk.MustQuery(`select aes_decrypt(aes_encrypt(a, 'key', '1234567890123456'), 'key', '1234567890123456') from t where id = 1;`).Check(testkit.Rows("pingcap"))
+	tk.MustGetErrCode(`select aes_encrypt('pingcap', 'key');`, mysql.ErrWrongParamcountToNativeFct)
+	err := tk.QueryToErr(`select aes_encrypt('pingcap', 'key', 'short');`)
+	c.Assert(err, NotNil)
+}
+
 type testSuite2 struct {
 	*baseTestSuite
 }
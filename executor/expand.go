@@ -0,0 +1,76 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// ExpandExec replicates every input row once for each level of ROLLUP.
+// Every chunk of the child is output once per level, and the rows of a level
+// are the results of the level's expressions.
+type ExpandExec struct {
+	baseExecutor
+
+	levelExprs  [][]expression.Expression
+	childResult *chunk.Chunk
+	// level is the next level to output for childResult.
+	level int
+}
+
+// Open implements the Executor Open interface.
+func (e *ExpandExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.level = len(e.levelExprs)
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *ExpandExec) Close() error {
+	e.childResult = nil
+	return e.baseExecutor.Close()
+}
+
+// Next implements the Executor Next interface.
+func (e *ExpandExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.GrowAndReset(e.maxChunkSize)
+	if e.level == len(e.levelExprs) {
+		if err := Next(ctx, e.children[0], e.childResult); err != nil {
+			return err
+		}
+		if e.childResult.NumRows() == 0 {
+			return nil
+		}
+		e.level = 0
+	}
+	exprs := e.levelExprs[e.level]
+	e.level++
+	iter := chunk.NewIterator4Chunk(e.childResult)
+	for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+		for i, expr := range exprs {
+			d, err := expr.Eval(row)
+			if err != nil {
+				return err
+			}
+			req.AppendDatum(i, &d)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// Export formats.
+const (
+	// ExportFormatSQL exports the data as INSERT statements.
+	ExportFormatSQL = "sql"
+	// ExportFormatCSV exports the data as CSV files with a header line.
+	ExportFormatCSV = "csv"
+)
+
+const (
+	// exportMetaFile is the name of the file which records the snapshot of an export.
+	exportMetaFile = "metadata"
+	// defaultExportChunkRows is the default max number of rows in one data file.
+	defaultExportChunkRows = 100000
+	// exportStatementRows is the max number of rows in one INSERT statement.
+	exportStatementRows = 1000
+)
+
+// ExportConfig is the config of Export.
+type ExportConfig struct {
+	// Schemas are the names of the databases to export.
+	Schemas []string
+	// Dir is the directory the files are written to, it must be empty or not exist.
+	Dir string
+	// Format is ExportFormatSQL or ExportFormatCSV, empty means ExportFormatSQL.
+	Format string
+	// ChunkRows is the max number of rows in one data file, 0 means defaultExportChunkRows.
+	ChunkRows int64
+	// SnapshotTS is the version to read the schemas and the data at, 0 means the current version.
+	SnapshotTS uint64
+}
+
+// ExportResult is the result of Export.
+type ExportResult struct {
+	SnapshotTS uint64
+	Tables     int64
+	Rows       int64
+}
+
+// Export writes the schemas and the data of the databases into files in the mydumper layout:
+//
+//	metadata                        the snapshot TS of the export
+//	{db}-schema-create.sql          the CREATE DATABASE statement
+//	{db}.{table}-schema.sql         the CREATE TABLE statement, the same as SHOW CREATE TABLE
+//	{db}.{table}.{chunk:09d}.sql    INSERT statements, or .csv when the format is csv
+//
+// All the schemas and the data are read from a single snapshot, so the export is consistent across tables.
+func Export(ctx sessionctx.Context, cfg *ExportConfig) (*ExportResult, error) {
+	format := strings.ToLower(cfg.Format)
+	if format == "" {
+		format = ExportFormatSQL
+	}
+	if format != ExportFormatSQL && format != ExportFormatCSV {
+		return nil, errors.Errorf("unsupported export format %s", cfg.Format)
+	}
+	chunkRows := cfg.ChunkRows
+	if chunkRows <= 0 {
+		chunkRows = defaultExportChunkRows
+	}
+	if entries, err := readDirNames(cfg.Dir); err != nil {
+		return nil, err
+	} else if len(entries) > 0 {
+		return nil, errors.Errorf("export directory %s is not empty", cfg.Dir)
+	}
+	if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	store := ctx.GetStore()
+	ver := kv.Version{Ver: cfg.SnapshotTS}
+	if ver.Ver == 0 {
+		var err error
+		if ver, err = store.CurrentVersion(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	snapshot, err := store.GetSnapshot(ver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// The schemas are read at the same version as the data, so the tables are exported with
+	// the definitions they had at that version.
+	is, err := domain.GetDomain(ctx).GetSnapshotInfoSchema(ver.Ver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	startTime := time.Now()
+	res := &ExportResult{SnapshotTS: ver.Ver}
+	for _, name := range cfg.Schemas {
+		dbInfo, ok := is.SchemaByName(model.NewCIStr(name))
+		if !ok {
+			return nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(name)
+		}
+		var buf bytes.Buffer
+		if err = ConstructResultOfShowCreateDatabase(ctx, dbInfo, false, &buf); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err = writeExportFile(filepath.Join(cfg.Dir, dbInfo.Name.O+"-schema-create.sql"), buf.String()+";\n"); err != nil {
+			return nil, err
+		}
+		for _, tbl := range is.SchemaTables(dbInfo.Name) {
+			buf.Reset()
+			if err = ConstructResultOfShowCreateTable(ctx, tbl.Meta(), tbl.Allocator(ctx), &buf); err != nil {
+				return nil, errors.Trace(err)
+			}
+			prefix := filepath.Join(cfg.Dir, dbInfo.Name.O+"."+tbl.Meta().Name.O)
+			if err = writeExportFile(prefix+"-schema.sql", buf.String()+";\n"); err != nil {
+				return nil, err
+			}
+			w := &exportTableWriter{prefix: prefix, format: format, chunkRows: chunkRows, tbl: tbl}
+			rows, err := exportTableData(ctx, snapshot, tbl, w)
+			if err != nil {
+				return nil, err
+			}
+			res.Tables++
+			res.Rows += rows
+		}
+	}
+
+	meta := fmt.Sprintf("Started dump at: %s\nSHOW MASTER STATUS:\n\tLog: tidb-binlog\n\tPos: %d\n\tGTID:\n\nFinished dump at: %s\n",
+		startTime.Format("2006-01-02 15:04:05"), ver.Ver, time.Now().Format("2006-01-02 15:04:05"))
+	if err = writeExportFile(filepath.Join(cfg.Dir, exportMetaFile), meta); err != nil {
+		return nil, err
+	}
+	logutil.BgLogger().Info("export finished", zap.String("dir", cfg.Dir), zap.Uint64("snapshotTS", res.SnapshotTS),
+		zap.Int64("tables", res.Tables), zap.Int64("rows", res.Rows))
+	return res, nil
+}
+
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	return names, errors.Trace(err)
+}
+
+func writeExportFile(path, content string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = f.WriteString(content)
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return errors.Trace(err)
+}
+
+// exportTableData writes all the rows of the table in the snapshot by w, and returns the number of rows.
+func exportTableData(ctx sessionctx.Context, snapshot kv.Snapshot, tbl table.Table, w *exportTableWriter) (rows int64, err error) {
+	defer func() {
+		if err1 := w.close(); err == nil {
+			err = err1
+		}
+	}()
+
+	prefix := tablecodec.GenTableRecordPrefix(tbl.Meta().ID)
+	it, err := snapshot.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer it.Close()
+
+	cols := tbl.VisibleCols()
+	for it.Valid() && it.Key().HasPrefix(prefix) {
+		handle, err := tablecodec.DecodeRowKey(it.Key())
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		row, _, err := tables.DecodeRawRowData(ctx, tbl.Meta(), handle, cols, it.Value())
+		if err != nil {
+			return 0, err
+		}
+		if err = w.writeRow(row); err != nil {
+			return 0, err
+		}
+		rows++
+		if err = it.Next(); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	return rows, nil
+}
+
+// exportTableWriter writes the rows of a table into chunked data files.
+type exportTableWriter struct {
+	prefix    string
+	format    string
+	chunkRows int64
+	tbl       table.Table
+
+	chunk    int
+	f        *os.File
+	w        *bufio.Writer
+	fileRows int64
+	buf      []byte
+}
+
+func (w *exportTableWriter) writeRow(row []types.Datum) error {
+	if w.f != nil && w.fileRows >= w.chunkRows {
+		if err := w.close(); err != nil {
+			return err
+		}
+	}
+	if w.f == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	w.buf = w.buf[:0]
+	if w.format == ExportFormatCSV {
+		for i := range row {
+			if i > 0 {
+				w.buf = append(w.buf, ',')
+			}
+			w.buf = appendCSVValue(w.buf, &row[i])
+		}
+		w.buf = append(w.buf, '\n')
+	} else {
+		if w.fileRows%exportStatementRows == 0 {
+			if w.fileRows > 0 {
+				w.buf = append(w.buf, ";\n"...)
+			}
+			w.buf = append(w.buf, "INSERT INTO "...)
+			w.buf = appendQuotedIdent(w.buf, w.tbl.Meta().Name.O)
+			w.buf = append(w.buf, " VALUES\n"...)
+		} else {
+			w.buf = append(w.buf, ",\n"...)
+		}
+		w.buf = append(w.buf, '(')
+		for i := range row {
+			if i > 0 {
+				w.buf = append(w.buf, ',')
+			}
+			w.buf = appendSQLValue(w.buf, &row[i])
+		}
+		w.buf = append(w.buf, ')')
+	}
+	w.fileRows++
+	_, err := w.w.Write(w.buf)
+	return errors.Trace(err)
+}
+
+func (w *exportTableWriter) open() error {
+	f, err := os.Create(fmt.Sprintf("%s.%09d.%s", w.prefix, w.chunk, w.format))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.chunk++
+	w.f, w.w, w.fileRows = f, bufio.NewWriter(f), 0
+	if w.format == ExportFormatCSV {
+		w.buf = w.buf[:0]
+		for i, col := range w.tbl.VisibleCols() {
+			if i > 0 {
+				w.buf = append(w.buf, ',')
+			}
+			w.buf = appendCSVString(w.buf, col.Name.O)
+		}
+		w.buf = append(w.buf, '\n')
+		_, err = w.w.Write(w.buf)
+	}
+	return errors.Trace(err)
+}
+
+func (w *exportTableWriter) close() error {
+	if w.f == nil {
+		return nil
+	}
+	var err error
+	if w.format == ExportFormatSQL && w.fileRows > 0 {
+		_, err = w.w.WriteString(";\n")
+	}
+	if err == nil {
+		err = w.w.Flush()
+	}
+	if err1 := w.f.Close(); err == nil {
+		err = err1
+	}
+	w.f, w.w = nil, nil
+	return errors.Trace(err)
+}
+
+func appendQuotedIdent(buf []byte, name string) []byte {
+	buf = append(buf, '`')
+	buf = append(buf, strings.Replace(name, "`", "``", -1)...)
+	return append(buf, '`')
+}
+
+// appendSQLValue appends the datum as a MySQL literal.
+func appendSQLValue(buf []byte, d *types.Datum) []byte {
+	switch d.Kind() {
+	case types.KindNull:
+		return append(buf, "NULL"...)
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64:
+		s, _ := d.ToString()
+		return append(buf, s...)
+	}
+	s, _ := d.ToString()
+	buf = append(buf, '\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			buf = append(buf, '\\', '0')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\x1a':
+			buf = append(buf, '\\', 'Z')
+		case '\'', '\\':
+			buf = append(buf, '\\', c)
+		default:
+			buf = append(buf, c)
+		}
+	}
+	return append(buf, '\'')
+}
+
+// appendCSVValue appends the datum as a CSV field, NULL is written as \N.
+func appendCSVValue(buf []byte, d *types.Datum) []byte {
+	switch d.Kind() {
+	case types.KindNull:
+		return append(buf, '\\', 'N')
+	case types.KindInt64, types.KindUint64, types.KindFloat32, types.KindFloat64:
+		s, _ := d.ToString()
+		return append(buf, s...)
+	}
+	s, _ := d.ToString()
+	return appendCSVString(buf, s)
+}
+
+func appendCSVString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	buf = append(buf, strings.Replace(s, `"`, `""`, -1)...)
+	return append(buf, '"')
+}
+
+// ExportExec represents an executor for the `ADMIN EXPORT` statement.
+type ExportExec struct {
+	baseExecutor
+
+	opt  *ast.ExportOption
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *ExportExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	res, err := Export(e.ctx, &ExportConfig{
+		Schemas: e.opt.Schemas,
+		Dir:     strings.TrimPrefix(e.opt.Storage, localStoragePrefix),
+		Format:  e.opt.Format,
+	})
+	if err != nil {
+		return err
+	}
+	req.AppendString(0, e.opt.Storage)
+	req.AppendInt64(1, res.Tables)
+	req.AppendInt64(2, res.Rows)
+	req.AppendUint64(3, res.SnapshotTS)
+	return nil
+}
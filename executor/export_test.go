@@ -0,0 +1,152 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite3) TestExport(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists exp")
+	tk.MustExec("create database exp")
+	tk.MustExec("use exp")
+	tk.MustExec("create table t (id int primary key, a double, b varchar(20), index idx_b(b))")
+	tk.MustExec(`insert into t values (1, 1.5, 'it''s'), (2, null, 'a\nb'), (3, -2, null), (4, 0, 'x"y'), (5, 3, '\\')`)
+	tk.MustExec("create table empty (a int)")
+
+	dir := c.MkDir()
+	res, err := executor.Export(tk.Se, &executor.ExportConfig{Schemas: []string{"exp"}, Dir: dir, ChunkRows: 2})
+	c.Assert(err, IsNil)
+	c.Assert(res.Tables, Equals, int64(2))
+	c.Assert(res.Rows, Equals, int64(5))
+	c.Assert(res.SnapshotTS, Greater, uint64(0))
+	c.Assert(readExportDir(c, dir), DeepEquals, []string{
+		"exp-schema-create.sql", "exp.empty-schema.sql", "exp.t-schema.sql",
+		"exp.t.000000000.sql", "exp.t.000000001.sql", "exp.t.000000002.sql", "metadata",
+	})
+	data, err := ioutil.ReadFile(filepath.Join(dir, "exp.t.000000000.sql"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "INSERT INTO `t` VALUES\n(1,1.5,'it\\'s'),\n(2,NULL,'a\\nb');\n")
+
+	// The directory can not be reused.
+	_, err = executor.Export(tk.Se, &executor.ExportConfig{Schemas: []string{"exp"}, Dir: dir})
+	c.Assert(err, NotNil)
+	_, err = executor.Export(tk.Se, &executor.ExportConfig{Schemas: []string{"exp"}, Dir: c.MkDir(), Format: "xml"})
+	c.Assert(err, NotNil)
+
+	data, err = ioutil.ReadFile(filepath.Join(dir, "exp-schema-create.sql"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "CREATE DATABASE `exp` /*!40100 DEFAULT CHARACTER SET utf8mb4 */;\n")
+	data, err = ioutil.ReadFile(filepath.Join(dir, "exp.empty-schema.sql"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "CREATE TABLE `empty` (\n  `a` int(11) DEFAULT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;\n")
+
+	// Load the exported data again.
+	tk.MustExec("delete from t")
+	for _, name := range []string{"exp.t.000000000.sql", "exp.t.000000001.sql", "exp.t.000000002.sql"} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		c.Assert(err, IsNil)
+		tk.MustExec(string(data))
+	}
+	tk.MustQuery("select id, a, b from t order by id").Check(testkit.Rows(
+		"1 1.5 it's", "2 <nil> a\nb", "3 -2 <nil>", "4 0 x\"y", "5 3 \\"))
+	tk.MustQuery("select id from t use index(idx_b) where b = 'x\"y'").Check(testkit.Rows("4"))
+
+	csvDir := c.MkDir() + "/csv"
+	result := tk.MustQuery(fmt.Sprintf("admin export database exp to 'local://%s' format = 'csv'", csvDir))
+	c.Assert(result.Rows(), HasLen, 1)
+	c.Assert(result.Rows()[0][1:3], DeepEquals, []interface{}{"2", "5"})
+	data, err = ioutil.ReadFile(filepath.Join(csvDir, "exp.t.000000000.csv"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "\"id\",\"a\",\"b\"\n1,1.5,\"it's\"\n2,\\N,\"a\nb\"\n3,-2,\\N\n4,0,\"x\"\"y\"\n5,3,\"\\\"\n")
+}
+
+func (s *testSuite3) TestExportSnapshot(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists exp")
+	tk.MustExec("create database exp")
+	tk.MustExec("use exp")
+	tk.MustExec("create table t (a int)")
+	tk.MustExec("insert into t values (1)")
+	ver, err := s.store.CurrentVersion()
+	c.Assert(err, IsNil)
+	tk.MustExec("alter table t add column b int default 2")
+	tk.MustExec("insert into t values (3, 4)")
+	tk.MustExec("create table t1 (a int)")
+
+	// The schemas are exported as they were at the snapshot, like the data.
+	dir := c.MkDir()
+	res, err := executor.Export(tk.Se, &executor.ExportConfig{Schemas: []string{"exp"}, Dir: dir, SnapshotTS: ver.Ver})
+	c.Assert(err, IsNil)
+	c.Assert(res.Tables, Equals, int64(1))
+	c.Assert(res.SnapshotTS, Equals, ver.Ver)
+	c.Assert(readExportDir(c, dir), DeepEquals, []string{
+		"exp-schema-create.sql", "exp.t-schema.sql", "exp.t.000000000.sql", "metadata",
+	})
+	data, err := ioutil.ReadFile(filepath.Join(dir, "exp.t-schema.sql"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "CREATE TABLE `t` (\n  `a` int(11) DEFAULT NULL\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;\n")
+	data, err = ioutil.ReadFile(filepath.Join(dir, "exp.t.000000000.sql"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "INSERT INTO `t` VALUES\n(1);\n")
+}
+
+func (s *testSuite3) TestExportExpressionIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("drop database if exists exp")
+	tk.MustExec("create database exp")
+	tk.MustExec("use exp")
+	tk.MustExec("create table t (a int, b int, key ie((a + b)))")
+	tk.MustExec("insert into t values (1, 2)")
+
+	// The hidden column of the expression index is not exported.
+	dir := c.MkDir()
+	_, err := executor.Export(tk.Se, &executor.ExportConfig{Schemas: []string{"exp"}, Dir: dir})
+	c.Assert(err, IsNil)
+	data, err := ioutil.ReadFile(filepath.Join(dir, "exp.t.000000000.sql"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "INSERT INTO `t` VALUES\n(1,2);\n")
+	csvDir := c.MkDir()
+	_, err = executor.Export(tk.Se, &executor.ExportConfig{Schemas: []string{"exp"}, Dir: csvDir, Format: executor.ExportFormatCSV})
+	c.Assert(err, IsNil)
+	data, err = ioutil.ReadFile(filepath.Join(csvDir, "exp.t.000000000.csv"))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "\"a\",\"b\"\n1,2\n")
+
+	// The exported data is loaded again with the index rebuilt.
+	tk.MustExec("delete from t")
+	data, err = ioutil.ReadFile(filepath.Join(dir, "exp.t.000000000.sql"))
+	c.Assert(err, IsNil)
+	tk.MustExec(string(data))
+	tk.MustQuery("select * from t use index(ie) where a + b = 3").Check(testkit.Rows("1 2"))
+}
+
+func readExportDir(c *C, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -14,15 +14,18 @@
 package executor
 
 import (
+	"bytes"
 	"hash"
 	"hash/fnv"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/spaolacci/murmur3"
 )
 
 const (
@@ -41,6 +44,9 @@ const (
 	// estCountDivisor defines the divisor of innerSideEstCount.
 	// Set this divisor to prevent innerSideEstCount being too large and causing a performance regression.
 	estCountDivisor = 8
+
+	// probeChunkCacheCapacity is the number of the spilled build side chunks cached by a probe worker.
+	probeChunkCacheCapacity = 8
 )
 
 // hashContext keeps the needed hash context of a db table in hash join.
@@ -50,6 +56,9 @@ type hashContext struct {
 	buf       []byte
 	hashVals  []hash.Hash64
 	hasNull   []bool
+	// chunkCache caches the build side chunks read by the probe worker after they are spilled,
+	// it's only set for the probe side.
+	chunkCache *chunk.DiskChunkCache
 }
 
 func (hc *hashContext) initHash(rows int) {
@@ -71,17 +80,18 @@ func (hc *hashContext) initHash(rows int) {
 	}
 }
 
-// hashRowContainer handles the rows and the hash map of a table.
-// TODO: support spilling out to disk when memory is limited.
+// hashRowContainer handles the rows and the hash map of a table. The rows are
+// spilled to the disk when the memory quota is exceeded, while the hash map is
+// always kept in memory.
 type hashRowContainer struct {
-	records   *chunk.List
+	records   *chunk.RowContainer
 	hashTable *rowHashMap
 
 	sc   *stmtctx.StatementContext
 	hCtx *hashContext
 }
 
-func newHashRowContainer(sctx sessionctx.Context, estCount int, hCtx *hashContext, initList *chunk.List) *hashRowContainer {
+func newHashRowContainer(sctx sessionctx.Context, estCount int, hCtx *hashContext, records *chunk.RowContainer) *hashRowContainer {
 	maxChunkSize := sctx.GetSessionVars().MaxChunkSize
 	// The estCount from cost model is not quite accurate and we need
 	// to avoid that it's too large to consume redundant memory.
@@ -95,7 +105,7 @@ func newHashRowContainer(sctx sessionctx.Context, estCount int, hCtx *hashContex
 		estCount = 0
 	}
 	c := &hashRowContainer{
-		records:   initList,
+		records:   records,
 		hashTable: newRowHashMap(estCount),
 
 		sc:   sctx.GetSessionVars().StmtCtx,
@@ -114,7 +124,11 @@ func (c *hashRowContainer) GetMatchedRows(probeKey uint64, probeRow chunk.Row, h
 	}
 	matched = make([]chunk.Row, 0, len(innerPtrs))
 	for _, ptr := range innerPtrs {
-		matchedRow := c.records.GetRow(ptr)
+		var matchedRow chunk.Row
+		matchedRow, err = c.records.GetRowWithCache(ptr, hCtx.chunkCache)
+		if err != nil {
+			return
+		}
 		var ok bool
 		ok, err = c.matchJoinKey(matchedRow, probeRow, hCtx)
 		if err != nil {
@@ -146,8 +160,6 @@ func (c *hashRowContainer) matchJoinKey(buildRow, probeRow chunk.Row, probeHCtx
 func (c *hashRowContainer) PutChunk(chk *chunk.Chunk) error {
 	chkIdx := uint32(c.records.NumChunks())
 	numRows := chk.NumRows()
-
-	c.records.Add(chk)
 	c.hCtx.initHash(numRows)
 
 	hCtx := c.hCtx
@@ -165,7 +177,13 @@ func (c *hashRowContainer) PutChunk(chk *chunk.Chunk) error {
 		rowPtr := chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(i)}
 		c.hashTable.Put(key, rowPtr)
 	}
-	return nil
+	// The chunk is added after hashing, because it may be reused once it's spilled.
+	return c.records.Add(chk)
+}
+
+// Close releases the memory of the records and removes them from the disk if they are spilled.
+func (c *hashRowContainer) Close() error {
+	return c.records.Close()
 }
 
 // Len returns the length of the records in hashRowContainer.
@@ -224,40 +242,104 @@ type entryAddr struct {
 
 var nullEntryAddr = entryAddr{}
 
+const (
+	// minHashSlots is the minimum number of slots of a rowHashMap.
+	minHashSlots = 16
+	// fibonacciHashMultiplier spreads the hash keys over the slots, the high bits of
+	// the product are used as the slot index.
+	fibonacciHashMultiplier = 0x9E3779B97F4A7C15
+)
+
+// hashSlot is a slot of rowHashMap, the slot is empty if head is nullEntryAddr.
+type hashSlot struct {
+	hashKey uint64
+	head    entryAddr
+}
+
 // rowHashMap stores multiple rowPtr of rows for a given key with minimum GC overhead.
 // A given key can store multiple values.
+// The keys are stored in an open addressing table with linear probing, which is a
+// flat slice without any pointer, so it's much cheaper to build, probe and scan by
+// GC than the builtin map. The rowPtrs of a key are linked in the entryStore.
 // It is not thread-safe, should only be used in one goroutine.
-// TODO(fengliyuan): add unit test for this.
 type rowHashMap struct {
 	entryStore entryStore
-	hashTable  map[uint64]entryAddr
-	length     int
+	slots      []hashSlot
+	// shift is 64 - log2(len(slots)).
+	shift   uint
+	numKeys int
+	length  int
 }
 
 // newRowHashMap creates a new rowHashMap. estCount means the estimated size of the hashMap.
 // If unknown, set it to 0.
 func newRowHashMap(estCount int) *rowHashMap {
 	m := new(rowHashMap)
-	m.hashTable = make(map[uint64]entryAddr, estCount)
+	m.initSlots(estCount)
 	m.entryStore.init()
 	return m
 }
 
+// initSlots allocates enough slots for count keys under the max load factor.
+func (m *rowHashMap) initSlots(count int) {
+	numSlots, shift := minHashSlots, uint(60)
+	for numSlots*3 < count*4 {
+		numSlots *= 2
+		shift--
+	}
+	m.slots = make([]hashSlot, numSlots)
+	m.shift = shift
+}
+
+func (m *rowHashMap) slotIdx(hashKey uint64) int {
+	return int((hashKey * fibonacciHashMultiplier) >> m.shift)
+}
+
+// findSlot returns the slot of the key, or the empty slot where the key should be put.
+func (m *rowHashMap) findSlot(hashKey uint64) *hashSlot {
+	mask := len(m.slots) - 1
+	for i := m.slotIdx(hashKey); ; i = (i + 1) & mask {
+		slot := &m.slots[i]
+		if slot.head == nullEntryAddr || slot.hashKey == hashKey {
+			return slot
+		}
+	}
+}
+
+// grow doubles the slots and re-inserts all the keys.
+func (m *rowHashMap) grow() {
+	oldSlots := m.slots
+	m.slots = make([]hashSlot, len(oldSlots)*2)
+	m.shift--
+	for _, slot := range oldSlots {
+		if slot.head != nullEntryAddr {
+			*m.findSlot(slot.hashKey) = slot
+		}
+	}
+}
+
 // Put puts the key/rowPtr pairs to the rowHashMap, multiple rowPtrs are stored in a list.
 func (m *rowHashMap) Put(hashKey uint64, rowPtr chunk.RowPtr) {
-	oldEntryAddr := m.hashTable[hashKey]
-	e := entry{
-		ptr:  rowPtr,
-		next: oldEntryAddr,
+	slot := m.findSlot(hashKey)
+	if slot.head == nullEntryAddr {
+		// Keep the load factor under 3/4, the probe sequences get long quickly above it.
+		if (m.numKeys+1)*4 > len(m.slots)*3 {
+			m.grow()
+			slot = m.findSlot(hashKey)
+		}
+		slot.hashKey = hashKey
+		m.numKeys++
 	}
-	newEntryAddr := m.entryStore.put(e)
-	m.hashTable[hashKey] = newEntryAddr
+	slot.head = m.entryStore.put(entry{
+		ptr:  rowPtr,
+		next: slot.head,
+	})
 	m.length++
 }
 
 // Get gets the values of the "key" and appends them to "values".
 func (m *rowHashMap) Get(hashKey uint64) (rowPtrs []chunk.RowPtr) {
-	entryAddr := m.hashTable[hashKey]
+	entryAddr := m.findSlot(hashKey).head
 	for entryAddr != nullEntryAddr {
 		e := m.entryStore.get(entryAddr)
 		entryAddr = e.next
@@ -274,3 +356,104 @@ func (m *rowHashMap) Get(hashKey uint64) (rowPtrs []chunk.RowPtr) {
 // Len returns the number of rowPtrs in the rowHashMap, the number of keys may be less than Len
 // if the same key is put more than once.
 func (m *rowHashMap) Len() int { return m.length }
+
+// aggGroupMap maps the serialized group keys of hash aggregation to the partial results of the
+// groups. Like rowHashMap, it's an open addressing table with linear probing. The slots only hold
+// the indexes of the groups, and the keys are copied into one flat buffer, so neither of them has
+// any pointer. The groups are indexed in the order they're put.
+// It is not thread-safe, should only be used in one goroutine.
+type aggGroupMap struct {
+	slots []groupSlot
+	// shift is 64 - log2(len(slots)).
+	shift          uint
+	hashes         []uint64
+	keyData        []byte
+	keyEnds        []int
+	partialResults [][]aggfuncs.PartialResult
+}
+
+// groupSlot is a slot of aggGroupMap, idx is the index of the group plus 1, the slot is empty if
+// idx is 0. The hash is kept in the slot, so the keys are only compared when the hashes match.
+type groupSlot struct {
+	hash uint64
+	idx  uint32
+}
+
+func newAggGroupMap() *aggGroupMap {
+	return &aggGroupMap{
+		slots: make([]groupSlot, minHashSlots),
+		shift: 60,
+	}
+}
+
+func (m *aggGroupMap) slotIdx(hash uint64) int {
+	return int((hash * fibonacciHashMultiplier) >> m.shift)
+}
+
+// findSlot returns the slot of the key, or the empty slot where the key should be put.
+func (m *aggGroupMap) findSlot(hash uint64, key []byte) int {
+	mask := len(m.slots) - 1
+	for i := m.slotIdx(hash); ; i = (i + 1) & mask {
+		slot := &m.slots[i]
+		if slot.idx == 0 || (slot.hash == hash && bytes.Equal(m.Key(int(slot.idx-1)), key)) {
+			return i
+		}
+	}
+}
+
+// grow doubles the slots and re-inserts all the groups.
+func (m *aggGroupMap) grow() {
+	m.slots = make([]groupSlot, len(m.slots)*2)
+	m.shift--
+	mask := len(m.slots) - 1
+	for idx, hash := range m.hashes {
+		i := m.slotIdx(hash)
+		for m.slots[i].idx != 0 {
+			i = (i + 1) & mask
+		}
+		m.slots[i] = groupSlot{hash: hash, idx: uint32(idx + 1)}
+	}
+}
+
+// GetOrPut returns the index of the group of the key. The group is put with the partial results
+// allocated by aggFuncs if the key is not in the map.
+func (m *aggGroupMap) GetOrPut(key []byte, aggFuncs []aggfuncs.AggFunc) int {
+	hash := murmur3.Sum64(key)
+	slot := m.findSlot(hash, key)
+	if idx := m.slots[slot].idx; idx != 0 {
+		return int(idx - 1)
+	}
+	// Keep the load factor under 3/4, the probe sequences get long quickly above it.
+	if (m.Len()+1)*4 > len(m.slots)*3 {
+		m.grow()
+		slot = m.findSlot(hash, key)
+	}
+	prs := make([]aggfuncs.PartialResult, 0, len(aggFuncs))
+	for _, af := range aggFuncs {
+		prs = append(prs, af.AllocPartialResult())
+	}
+	m.hashes = append(m.hashes, hash)
+	m.keyData = append(m.keyData, key...)
+	m.keyEnds = append(m.keyEnds, len(m.keyData))
+	m.partialResults = append(m.partialResults, prs)
+	m.slots[slot] = groupSlot{hash: hash, idx: uint32(m.Len())}
+	return m.Len() - 1
+}
+
+// Len returns the number of the groups.
+func (m *aggGroupMap) Len() int { return len(m.hashes) }
+
+// Hash returns the hash of the key of the idx-th group.
+func (m *aggGroupMap) Hash(idx int) uint64 { return m.hashes[idx] }
+
+// Key returns the key of the idx-th group.
+func (m *aggGroupMap) Key(idx int) []byte {
+	start := 0
+	if idx > 0 {
+		start = m.keyEnds[idx-1]
+	}
+	return m.keyData[start:m.keyEnds[idx]]
+}
+
+// PartialResults returns the partial results of the idx-th group.
+func (m *aggGroupMap) PartialResults(idx int) []aggfuncs.PartialResult { return m.partialResults[idx] }
@@ -14,8 +14,14 @@
 package executor
 
 import (
+	"fmt"
+	"math/rand"
+	"testing"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor/aggfuncs"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/spaolacci/murmur3"
 )
 
 func (s *pkgTestSuite) TestRowHashMap(c *C) {
@@ -48,3 +54,151 @@ func (s *pkgTestSuite) TestRowHashMap(c *C) {
 	}
 	c.Check(m.Len(), Equals, totalCount)
 }
+
+func (s *pkgTestSuite) TestRowHashMapGrow(c *C) {
+	m := newRowHashMap(0)
+	c.Assert(len(m.slots), Equals, minHashSlots)
+	rawData := map[uint64][]chunk.RowPtr{}
+	for i := 0; i < 10000; i++ {
+		// The keys share the low bits, so they collide without spreading.
+		key := uint64(rand.Intn(3000)) << 32
+		ptr := chunk.RowPtr{ChkIdx: uint32(i / 1024), RowIdx: uint32(i % 1024)}
+		rawData[key] = append(rawData[key], ptr)
+		m.Put(key, ptr)
+	}
+	c.Assert(m.numKeys, Equals, len(rawData))
+	c.Assert(m.numKeys*4 <= len(m.slots)*3, IsTrue)
+	for key, ptrs := range rawData {
+		c.Check(m.Get(key), DeepEquals, ptrs)
+	}
+	c.Check(m.Get(1), IsNil)
+	c.Check(m.Len(), Equals, 10000)
+
+	m = newRowHashMap(1000)
+	c.Assert(len(m.slots), Equals, 2048)
+}
+
+// builtinRowHashMap is the rowHashMap based on the builtin map, it's used as the
+// baseline of the benchmarks.
+type builtinRowHashMap struct {
+	entryStore entryStore
+	hashTable  map[uint64]entryAddr
+}
+
+func (m *builtinRowHashMap) Put(hashKey uint64, rowPtr chunk.RowPtr) {
+	m.hashTable[hashKey] = m.entryStore.put(entry{ptr: rowPtr, next: m.hashTable[hashKey]})
+}
+
+func (m *builtinRowHashMap) Get(hashKey uint64) (rowPtrs []chunk.RowPtr) {
+	for addr := m.hashTable[hashKey]; addr != nullEntryAddr; {
+		e := m.entryStore.get(addr)
+		addr = e.next
+		rowPtrs = append(rowPtrs, e.ptr)
+	}
+	return
+}
+
+func benchmarkRowHashMap(b *testing.B, numRows, ndv int) {
+	keys := make([]uint64, numRows)
+	for i := range keys {
+		keys[i] = rand.Uint64() % uint64(ndv)
+	}
+	probeKeys := make([]uint64, ndv)
+	for i := range probeKeys {
+		probeKeys[i] = keys[rand.Intn(numRows)]
+	}
+	b.Run("builtinMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := &builtinRowHashMap{hashTable: make(map[uint64]entryAddr)}
+			m.entryStore.init()
+			for j, key := range keys {
+				m.Put(key, chunk.RowPtr{RowIdx: uint32(j)})
+			}
+			for _, key := range probeKeys {
+				_ = m.Get(key)
+			}
+		}
+	})
+	b.Run("rowHashMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := newRowHashMap(0)
+			for j, key := range keys {
+				m.Put(key, chunk.RowPtr{RowIdx: uint32(j)})
+			}
+			for _, key := range probeKeys {
+				_ = m.Get(key)
+			}
+		}
+	})
+}
+
+func BenchmarkRowHashMapUnique(b *testing.B) {
+	benchmarkRowHashMap(b, 100000, 100000)
+}
+
+func BenchmarkRowHashMapDuplicated(b *testing.B) {
+	benchmarkRowHashMap(b, 100000, 1000)
+}
+
+func (s *pkgTestSuite) TestAggGroupMap(c *C) {
+	m := newAggGroupMap()
+	keys := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		key := []byte(fmt.Sprintf("key%d", rand.Intn(3000)))
+		idx := m.GetOrPut(key, nil)
+		if expected, ok := keys[string(key)]; ok {
+			c.Assert(idx, Equals, expected)
+			continue
+		}
+		// The groups are indexed in the order they're put.
+		c.Assert(idx, Equals, len(keys))
+		keys[string(key)] = idx
+	}
+	c.Assert(m.Len(), Equals, len(keys))
+	c.Assert(m.Len()*4 <= len(m.slots)*3, IsTrue)
+	for key, idx := range keys {
+		c.Check(string(m.Key(idx)), Equals, key)
+		c.Check(m.Hash(idx), Equals, murmur3.Sum64([]byte(key)))
+		c.Check(m.PartialResults(idx), HasLen, 0)
+	}
+	// The empty key is a valid group key.
+	c.Check(m.GetOrPut(nil, nil), Equals, len(keys))
+	c.Check(m.GetOrPut([]byte{}, nil), Equals, len(keys))
+}
+
+func benchmarkAggGroupMap(b *testing.B, numRows, ndv int) {
+	keys := make([][]byte, numRows)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("group key %d", rand.Intn(ndv)))
+	}
+	b.Run("builtinMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := make(map[string][]aggfuncs.PartialResult)
+			for _, key := range keys {
+				if _, ok := m[string(key)]; !ok {
+					m[string(key)] = []aggfuncs.PartialResult{}
+				}
+			}
+		}
+	})
+	b.Run("aggGroupMap", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m := newAggGroupMap()
+			for _, key := range keys {
+				m.GetOrPut(key, nil)
+			}
+		}
+	})
+}
+
+func BenchmarkAggGroupMapUnique(b *testing.B) {
+	benchmarkAggGroupMap(b, 100000, 100000)
+}
+
+func BenchmarkAggGroupMapDuplicated(b *testing.B) {
+	benchmarkAggGroupMap(b, 100000, 1000)
+}
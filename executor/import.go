@@ -0,0 +1,390 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"go.uber.org/zap"
+)
+
+// importNullValue is the CSV field which stands for NULL.
+const importNullValue = `\N`
+
+// importingTables records the IDs of the tables in import mode on this server.
+// Writing to a table in import mode is rejected, because the import is not atomic.
+var importingTables sync.Map
+
+func checkTableNotImporting(tbl table.Table) error {
+	if _, ok := importingTables.Load(tbl.Meta().ID); ok {
+		return errors.Errorf("table %s is in import mode", tbl.Meta().Name.O)
+	}
+	return nil
+}
+
+// ImportExec represents an executor for the `IMPORT INTO` statement.
+// It encodes the rows and index entries of the CSV files on the client side, sorts the key-value pairs,
+// and writes them by kv.Ingester in large batches instead of transactions.
+// The first line of a CSV file is the header which names the columns of the fields,
+// the omitted columns are filled with their default values.
+type ImportExec struct {
+	baseExecutor
+
+	stmt *ast.ImportIntoStmt
+	tbl  table.Table
+	done bool
+}
+
+// importEncoder encodes rows of a table into key-value pairs.
+type importEncoder struct {
+	ctx     sessionctx.Context
+	tbl     table.Table
+	encoder rowcodec.Encoder
+	// genExprs are the expressions of the generated columns, in the order of the columns.
+	genExprs []expression.Expression
+
+	keys   [][]byte
+	values [][]byte
+	size   int
+	// maxAutoID is the max explicit value of the auto increment column.
+	maxAutoID int64
+}
+
+// Next implements the Executor Next interface.
+func (e *ImportExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	files, err := filepath.Glob(e.stmt.Path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(files) == 0 {
+		return errors.Errorf("no file matches %s", e.stmt.Path)
+	}
+	sort.Strings(files)
+
+	tblID := e.tbl.Meta().ID
+	if _, loaded := importingTables.LoadOrStore(tblID, struct{}{}); loaded {
+		return errors.Errorf("table %s is in import mode", e.tbl.Meta().Name.O)
+	}
+	defer importingTables.Delete(tblID)
+	if err = e.checkTableEmpty(); err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+	enc, err := newImportEncoder(e.ctx, e.tbl)
+	if err != nil {
+		return err
+	}
+	enc.encoder.Checksum = e.ctx.GetSessionVars().RowEncoder.Checksum
+	var rows int64
+	for _, file := range files {
+		n, err := enc.encodeFile(file)
+		if err != nil {
+			return err
+		}
+		rows += n
+	}
+	if err = enc.sortAndCheckDuplicate(); err != nil {
+		return err
+	}
+	tableIDs := []int64{e.tbl.Meta().ID}
+	InvalidateResultCache(tableIDs)
+	err = ingest(ctx, e.ctx.GetStore(), enc.keys, enc.values)
+	InvalidateResultCache(tableIDs)
+	if err != nil {
+		return err
+	}
+	if enc.maxAutoID > 0 {
+		if err = e.tbl.RebaseAutoID(e.ctx, enc.maxAutoID, false); err != nil {
+			return err
+		}
+	}
+	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(uint64(rows))
+	logutil.Logger(ctx).Info("import finished", zap.String("table", e.tbl.Meta().Name.O),
+		zap.Int("files", len(files)), zap.Int64("rows", rows), zap.Int("kvs", len(enc.keys)),
+		zap.Int("size", enc.size), zap.Duration("takeTime", time.Since(startTime)))
+	return nil
+}
+
+// checkTableEmpty checks there is neither row nor index entry of the table.
+func (e *ImportExec) checkTableEmpty() error {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	prefix := tablecodec.EncodeTablePrefix(e.tbl.Meta().ID)
+	it, err := txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer it.Close()
+	if it.Valid() && it.Key().HasPrefix(prefix) {
+		return errors.Errorf("table %s is not empty", e.tbl.Meta().Name.O)
+	}
+	return nil
+}
+
+// ingest writes the sorted pairs by kv.Ingester, or by transactions if the storage does not support it.
+func ingest(ctx context.Context, store kv.Storage, keys, values [][]byte) error {
+	if ingester, ok := store.(kv.Ingester); ok {
+		_, err := ingester.Ingest(ctx, keys, values)
+		return errors.Trace(err)
+	}
+	for len(keys) > 0 {
+		n := restoreBatchSize
+		if n > len(keys) {
+			n = len(keys)
+		}
+		err := kv.RunInNewTxn(store, false, func(txn kv.Transaction) error {
+			for i := 0; i < n; i++ {
+				if err := txn.Set(keys[i], values[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+		keys, values = keys[n:], values[n:]
+	}
+	return nil
+}
+
+func newImportEncoder(ctx sessionctx.Context, tbl table.Table) (*importEncoder, error) {
+	enc := &importEncoder{ctx: ctx, tbl: tbl}
+	var schema *expression.Schema
+	var names types.NameSlice
+	for _, col := range tbl.Cols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		if schema == nil {
+			schema, names = expression.TableInfo2SchemaAndNames(ctx, model.NewCIStr(""), tbl.Meta())
+		}
+		expr, err := expression.RewriteAstExpr(ctx, col.GeneratedExpr, schema, names)
+		if err != nil {
+			return nil, err
+		}
+		enc.genExprs = append(enc.genExprs, expr)
+	}
+	return enc, nil
+}
+
+func (enc *importEncoder) encodeFile(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if err != nil {
+		return 0, errors.Annotatef(err, "read header of %s", path)
+	}
+	// offsets[i] is the offset of the column of the i-th field.
+	offsets := make([]int, len(header))
+	for i, name := range header {
+		col := table.FindCol(enc.tbl.VisibleCols(), name)
+		if col == nil {
+			return 0, errors.Errorf("unknown column %s in %s", name, path)
+		}
+		offsets[i] = col.Offset
+	}
+
+	var rows int64
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rows, errors.Annotatef(err, "read %s", path)
+		}
+		if len(record) != len(offsets) {
+			return rows, errors.Errorf("row %d of %s has %d fields, but the header has %d", rows+1, path, len(record), len(offsets))
+		}
+		if err = enc.encodeRow(record, offsets); err != nil {
+			return rows, errors.Annotatef(err, "row %d of %s", rows+1, path)
+		}
+		rows++
+	}
+	return rows, nil
+}
+
+func (enc *importEncoder) encodeRow(record []string, offsets []int) error {
+	cols := enc.tbl.Cols()
+	row := make([]types.Datum, len(cols))
+	hasValue := make([]bool, len(cols))
+	var err error
+	for i, field := range record {
+		col := cols[offsets[i]]
+		hasValue[col.Offset] = true
+		if field == importNullValue {
+			continue
+		}
+		row[col.Offset], err = table.CastValue(enc.ctx, types.NewStringDatum(field), col.ToInfo())
+		if err != nil {
+			return err
+		}
+	}
+
+	meta := enc.tbl.Meta()
+	var handle int64
+	hasHandle := false
+	for _, col := range cols {
+		if col.IsGenerated() {
+			continue
+		}
+		d := &row[col.Offset]
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			if d.IsNull() || d.GetInt64() == 0 {
+				id, err := table.AllocAutoIncrementValue(context.Background(), enc.tbl, enc.ctx)
+				if err != nil {
+					return err
+				}
+				d.SetAutoID(id, col.Flag)
+			} else if d.GetInt64() > enc.maxAutoID {
+				enc.maxAutoID = d.GetInt64()
+			}
+		} else if !hasValue[col.Offset] {
+			if *d, err = table.GetColDefaultValue(enc.ctx, col.ToInfo()); err != nil {
+				return err
+			}
+		}
+		if err = col.CheckNotNull(*d); err != nil {
+			return err
+		}
+		if col.IsPKHandleColumn(meta) {
+			handle, hasHandle = d.GetInt64(), true
+		}
+	}
+	if !hasHandle {
+		if handle, err = enc.tbl.AllocHandle(enc.ctx); err != nil {
+			return err
+		}
+	}
+	// The generated columns are evaluated after the other columns are filled, the expression indexes are built on them.
+	if err = evalGeneratedColumns(enc.ctx, enc.tbl, enc.genExprs, row); err != nil {
+		return err
+	}
+
+	sc := enc.ctx.GetSessionVars().StmtCtx
+	for _, idx := range enc.tbl.Indices() {
+		indexedValues, err := idx.FetchValues(row, nil)
+		if err != nil {
+			return err
+		}
+		key, distinct, err := idx.GenIndexKey(sc, indexedValues, handle, nil)
+		if err != nil {
+			return err
+		}
+		value := []byte{'0'}
+		if distinct {
+			value = tables.EncodeHandle(handle)
+		}
+		enc.add(key, value)
+	}
+
+	colIDs := make([]int64, 0, len(cols))
+	values := make([]types.Datum, 0, len(cols))
+	for _, col := range cols {
+		if !tables.CanSkip(meta, col, row[col.Offset]) {
+			colIDs = append(colIDs, col.ID)
+			values = append(values, row[col.Offset])
+		}
+	}
+	value, err := tablecodec.EncodeRow(sc, values, colIDs, nil, nil, &enc.encoder)
+	if err != nil {
+		return err
+	}
+	enc.add(tablecodec.EncodeRowKeyWithHandle(meta.ID, handle), value)
+	return nil
+}
+
+func (enc *importEncoder) add(key, value []byte) {
+	enc.keys = append(enc.keys, key)
+	enc.values = append(enc.values, value)
+	enc.size += len(key) + len(value)
+}
+
+type importKVSorter importEncoder
+
+func (s *importKVSorter) Len() int           { return len(s.keys) }
+func (s *importKVSorter) Less(i, j int) bool { return bytes.Compare(s.keys[i], s.keys[j]) < 0 }
+func (s *importKVSorter) Swap(i, j int) {
+	s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+	s.values[i], s.values[j] = s.values[j], s.values[i]
+}
+
+// sortAndCheckDuplicate sorts the pairs by key, and returns an error if a row or unique index entry is duplicated.
+func (enc *importEncoder) sortAndCheckDuplicate() error {
+	sort.Sort((*importKVSorter)(enc))
+	for i := 1; i < len(enc.keys); i++ {
+		if !bytes.Equal(enc.keys[i-1], enc.keys[i]) {
+			continue
+		}
+		key := enc.keys[i]
+		_, indexID, isRecord, err := tablecodec.DecodeKeyHead(key)
+		if err != nil {
+			return err
+		}
+		if isRecord {
+			handle, err := tablecodec.DecodeRowKey(key)
+			if err != nil {
+				return err
+			}
+			return kv.ErrKeyExists.FastGenByArgs(handle, "PRIMARY")
+		}
+		name := ""
+		for _, idx := range enc.tbl.Meta().Indices {
+			if idx.ID == indexID {
+				name = idx.Name.O
+			}
+		}
+		return kv.ErrKeyExists.FastGenByArgs(strings.ToUpper(kv.Key(key).String()), name)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite3) TestImportInto(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists src, t, t2")
+	tk.MustExec("create table src (id int primary key, a int, b varchar(20), unique index idx_a(a), index idx_b(b))")
+	for i := 1; i <= 250; i++ {
+		tk.MustExec(fmt.Sprintf("insert into src values (%d, %d, 'b%d')", i, i*10, i%7))
+	}
+	tk.MustExec("insert into src values (251, null, null)")
+
+	// Export the table into several CSV files and import them into another table.
+	dir := c.MkDir() + "/data"
+	tk.MustQuery(fmt.Sprintf("admin export database test to '%s' format = 'csv'", dir))
+	tk.MustExec("create table t (id int primary key, a int, b varchar(20), c int default 5, unique index idx_a(a), index idx_b(b))")
+	tk.MustExec(fmt.Sprintf("import into t from '%s'", filepath.Join(dir, "test.src.*.csv")))
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(251))
+	tk.MustQuery("select count(*), sum(id), sum(a), sum(c) from t").Check(testkit.Rows("251 31626 313750 1255"))
+	tk.MustQuery("select count(*) from t use index(idx_b) where b = 'b3'").Check(testkit.Rows("36"))
+	tk.MustQuery("select id from t use index(idx_a) where a = 100").Check(testkit.Rows("10"))
+	tk.MustQuery("select id, a, b from t where id = 251").Check(testkit.Rows("251 <nil> <nil>"))
+	tk.MustExec("insert into t (id, a) values (300, 3000)")
+
+	// The target table must be empty.
+	_, err := tk.Exec(fmt.Sprintf("import into t from '%s'", filepath.Join(dir, "test.src.*.csv")))
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("import into t2 from '/not/exist.csv'")
+	c.Assert(err, NotNil)
+
+	// Auto increment IDs are allocated for the omitted column, and rebased after the explicit ones.
+	tk.MustExec("create table t2 (id int primary key auto_increment, a int not null, unique index idx_a(a))")
+	file := filepath.Join(c.MkDir(), "t2.csv")
+	c.Assert(ioutil.WriteFile(file, []byte("a\n1\n2\n"), 0644), IsNil)
+	tk.MustExec(fmt.Sprintf("import into t2 from '%s'", file))
+	tk.MustQuery("select id, a from t2 order by a").Check(testkit.Rows("1 1", "2 2"))
+	tk.MustExec("delete from t2")
+	c.Assert(ioutil.WriteFile(file, []byte("id,a\n100,1\n0,2\n"), 0644), IsNil)
+	tk.MustExec(fmt.Sprintf("import into t2 from '%s'", file))
+	tk.MustExec("insert into t2 (a) values (3)")
+	tk.MustQuery("select id > 100 from t2 where a = 3").Check(testkit.Rows("1"))
+
+	// Duplicated entries and invalid rows are rejected before anything is written.
+	tk.MustExec("delete from t2")
+	c.Assert(ioutil.WriteFile(file, []byte("id,a\n1,1\n2,1\n"), 0644), IsNil)
+	_, err = tk.Exec(fmt.Sprintf("import into t2 from '%s'", file))
+	c.Assert(terror.ErrorEqual(err, kv.ErrKeyExists), IsTrue, Commentf("err %v", err))
+	c.Assert(ioutil.WriteFile(file, []byte("id,a\n1,\\N\n"), 0644), IsNil)
+	_, err = tk.Exec(fmt.Sprintf("import into t2 from '%s'", file))
+	c.Assert(err, NotNil)
+	c.Assert(ioutil.WriteFile(file, []byte("id,x\n1,1\n"), 0644), IsNil)
+	_, err = tk.Exec(fmt.Sprintf("import into t2 from '%s'", file))
+	c.Assert(err, NotNil)
+	tk.MustQuery("select count(*) from t2").Check(testkit.Rows("0"))
+}
+
+func (s *testSuite3) TestImportIntoExpressionIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, key ie((a + b)))")
+	file := filepath.Join(c.MkDir(), "t.csv")
+	c.Assert(ioutil.WriteFile(file, []byte("a,b\n1,2\n3,\\N\n"), 0644), IsNil)
+	tk.MustExec(fmt.Sprintf("import into t from '%s'", file))
+	tk.MustQuery("select * from t use index(ie) where a + b = 3").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select * from t ignore index(ie) where a + b = 3").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select a from t use index(ie) where a + b is null").Check(testkit.Rows("3"))
+
+	// The hidden column of the expression index can't be written.
+	tk.MustExec("delete from t")
+	c.Assert(ioutil.WriteFile(file, []byte("a,b,_V$_ie_0\n1,2,3\n"), 0644), IsNil)
+	_, err := tk.Exec(fmt.Sprintf("import into t from '%s'", file))
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,335 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+const (
+	// workloadCapacity is the max number of the statement digests recorded for the index advisor.
+	workloadCapacity = 1000
+	// maxRecommendedIndexes is the max number of the indexes recommended by the index advisor.
+	maxRecommendedIndexes = 5
+	// minIndexBenefitRatio is the min ratio of the cost reduced by an index to the original cost
+	// of a statement, the indexes with less benefits are not considered to speed up the statement.
+	minIndexBenefitRatio = 0.1
+)
+
+// globalWorkload records the SELECT statements executed by all the sessions in the server.
+var globalWorkload = newStmtWorkload(workloadCapacity)
+
+type workloadKey struct {
+	db     string
+	digest string
+}
+
+// Hash implements kvcache.Key interface.
+func (key workloadKey) Hash() []byte {
+	return []byte(key.db + "." + key.digest)
+}
+
+// workloadStmt is a statement digest recorded for the index advisor.
+type workloadStmt struct {
+	db string
+	// sql is the text of the last executed statement of the digest.
+	sql       string
+	charset   string
+	collation string
+	execCount uint64
+}
+
+// stmtWorkload keeps the recently executed digests, the least recently executed one is dropped
+// when it's full.
+type stmtWorkload struct {
+	mu    sync.Mutex
+	stmts *kvcache.SimpleLRUCache
+}
+
+func newStmtWorkload(capacity uint) *stmtWorkload {
+	return &stmtWorkload{stmts: kvcache.NewSimpleLRUCache(capacity)}
+}
+
+func (w *stmtWorkload) record(digest, sql, db, charset, collation string) {
+	key := workloadKey{db: db, digest: digest}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var stmt *workloadStmt
+	if value, ok := w.stmts.Get(key); ok {
+		stmt = value.(*workloadStmt)
+	} else {
+		stmt = &workloadStmt{db: db}
+		w.stmts.Put(key, stmt)
+	}
+	stmt.sql, stmt.charset, stmt.collation = sql, charset, collation
+	stmt.execCount++
+}
+
+// statements returns the copies of the recorded statements, the most executed ones come first.
+func (w *stmtWorkload) statements() []workloadStmt {
+	w.mu.Lock()
+	values := w.stmts.Values()
+	stmts := make([]workloadStmt, 0, len(values))
+	for _, value := range values {
+		stmts = append(stmts, *value.(*workloadStmt))
+	}
+	w.mu.Unlock()
+	sort.Slice(stmts, func(i, j int) bool {
+		if stmts[i].execCount != stmts[j].execCount {
+			return stmts[i].execCount > stmts[j].execCount
+		}
+		return stmts[i].sql < stmts[j].sql
+	})
+	return stmts
+}
+
+// recordWorkload records the SELECT statements reading tables for the index advisor.
+func (a *ExecStmt) recordWorkload() {
+	vars := a.Ctx.GetSessionVars()
+	sel, ok := a.StmtNode.(*ast.SelectStmt)
+	// The statements built without text, such as the ones built by BATCH DML, can't be advised.
+	if !ok || sel.From == nil || a.Text == "" || vars.InRestrictedSQL || !vars.RecordWorkload {
+		return
+	}
+	charset, collation := vars.GetCharsetInfo()
+	globalWorkload.record(vars.StmtCtx.SQLDigest(), a.Text, vars.CurrentDB, charset, collation)
+}
+
+// indexRecommendation is an index candidate which speeds up the recorded statements.
+type indexRecommendation struct {
+	candidate *plannercore.IndexCandidate
+	// benefit is the sum of the reduced costs of all the executions of the statements.
+	benefit float64
+	digests int
+}
+
+func (r *indexRecommendation) createStatement() string {
+	cols := make([]string, 0, len(r.candidate.Columns))
+	for _, col := range r.candidate.Columns {
+		cols = append(cols, fmt.Sprintf("`%s`", col.Name.O))
+	}
+	return fmt.Sprintf("CREATE INDEX `%s` ON `%s`.`%s` (%s)", r.candidate.Name(), r.candidate.DBName.O,
+		r.candidate.Table.Name.O, strings.Join(cols, ", "))
+}
+
+// indexAdvisor estimates the costs of the recorded statements with the hypothetical indexes.
+type indexAdvisor struct {
+	ctx  context.Context
+	sctx sessionctx.Context
+	is   infoschema.InfoSchema
+	// recommendations are keyed by the tables and the columns of the indexes.
+	recommendations map[string]*indexRecommendation
+}
+
+// recommendIndexes returns the indexes which reduce the costs of the recorded statements most.
+func recommendIndexes(ctx context.Context, sctx sessionctx.Context) []*indexRecommendation {
+	vars := sctx.GetSessionVars()
+	// The statements are planned in the session of the ADMIN statement, so its states are restored at last.
+	stmtCtx, currentDB := vars.StmtCtx, vars.CurrentDB
+	defer func() {
+		vars.StmtCtx, vars.CurrentDB = stmtCtx, currentDB
+	}()
+
+	a := &indexAdvisor{
+		ctx:             ctx,
+		sctx:            sctx,
+		is:              domain.GetDomain(sctx).InfoSchema(),
+		recommendations: make(map[string]*indexRecommendation),
+	}
+	for _, stmt := range globalWorkload.statements() {
+		if err := a.analyze(&stmt); err != nil {
+			// The statements which can't be planned any more, like the ones reading the dropped tables, are skipped.
+			logutil.Logger(ctx).Debug("skip the statement for index advisor", zap.String("sql", stmt.sql), zap.Error(err))
+		}
+	}
+	return a.best()
+}
+
+func (a *indexAdvisor) analyze(stmt *workloadStmt) error {
+	logic, flag, err := a.buildLogicalPlan(stmt, nil)
+	if err != nil {
+		return err
+	}
+	candidates, err := plannercore.ExtractIndexCandidates(a.ctx, flag, logic)
+	if err != nil {
+		return err
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	cost, err := a.estimateCost(stmt, nil)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		if util.IsMemOrSysDB(candidate.DBName.L) {
+			continue
+		}
+		hypoCost, err := a.estimateCost(stmt, candidate)
+		if err != nil {
+			return err
+		}
+		if cost-hypoCost < cost*minIndexBenefitRatio {
+			continue
+		}
+		key := fmt.Sprintf("%d.%s", candidate.Table.ID, candidate.Name())
+		rec, ok := a.recommendations[key]
+		if !ok {
+			rec = &indexRecommendation{candidate: candidate}
+			a.recommendations[key] = rec
+		}
+		rec.benefit += (cost - hypoCost) * float64(stmt.execCount)
+		rec.digests++
+	}
+	return nil
+}
+
+// buildLogicalPlan builds the logical plan of the statement, the candidate is considered as an
+// existing index by the planner.
+func (a *indexAdvisor) buildLogicalPlan(stmt *workloadStmt, candidate *plannercore.IndexCandidate) (plannercore.LogicalPlan, uint64, error) {
+	vars := a.sctx.GetSessionVars()
+	vars.CurrentDB = stmt.db
+	vars.StmtCtx = &stmtctx.StatementContext{
+		InSelectStmt:      true,
+		OverflowAsWarning: true,
+		TruncateAsWarning: true,
+		IgnoreZeroInDate:  true,
+		TimeZone:          vars.Location(),
+	}
+	if candidate != nil {
+		vars.StmtCtx.HypoIndexes = map[int64][]*model.IndexInfo{
+			candidate.Table.ID: {candidate.HypoIndexInfo()},
+		}
+	}
+
+	p := parser.New()
+	p.SetSQLMode(vars.SQLMode)
+	node, err := p.ParseOneStmt(stmt.sql, stmt.charset, stmt.collation)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := plannercore.Preprocess(a.sctx, node, a.is); err != nil {
+		return nil, 0, err
+	}
+	builder := plannercore.NewPlanBuilder(a.sctx, a.is)
+	plan, err := builder.Build(a.ctx, node)
+	if err != nil {
+		return nil, 0, err
+	}
+	logic, ok := plan.(plannercore.LogicalPlan)
+	if !ok {
+		return nil, 0, errors.Errorf("unexpected plan %T", plan)
+	}
+	return logic, builder.GetOptFlag(), nil
+}
+
+func (a *indexAdvisor) estimateCost(stmt *workloadStmt, candidate *plannercore.IndexCandidate) (float64, error) {
+	logic, flag, err := a.buildLogicalPlan(stmt, candidate)
+	if err != nil {
+		return 0, err
+	}
+	_, cost, err := plannercore.DoOptimizeWithCost(a.ctx, flag, logic)
+	return cost, err
+}
+
+// best returns the recommendations with the most benefits. The indexes which are prefixes of the
+// recommended indexes are not recommended, since the longer ones can do all their work.
+func (a *indexAdvisor) best() []*indexRecommendation {
+	recs := make([]*indexRecommendation, 0, len(a.recommendations))
+	for _, rec := range a.recommendations {
+		recs = append(recs, rec)
+	}
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].benefit != recs[j].benefit {
+			return recs[i].benefit > recs[j].benefit
+		}
+		return recs[i].createStatement() < recs[j].createStatement()
+	})
+	best := make([]*indexRecommendation, 0, maxRecommendedIndexes)
+	for _, rec := range recs {
+		if len(best) == maxRecommendedIndexes {
+			break
+		}
+		covered := false
+		for _, chosen := range best {
+			if isIndexPrefix(rec.candidate, chosen.candidate) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			best = append(best, rec)
+		}
+	}
+	return best
+}
+
+// isIndexPrefix checks whether the columns of the index are a prefix of the other index.
+func isIndexPrefix(idx, other *plannercore.IndexCandidate) bool {
+	if idx.Table.ID != other.Table.ID || len(idx.Columns) > len(other.Columns) {
+		return false
+	}
+	for i, col := range idx.Columns {
+		if other.Columns[i].ID != col.ID {
+			return false
+		}
+	}
+	return true
+}
+
+// RecommendIndexExec represents an executor for the `ADMIN RECOMMEND INDEX` statement.
+type RecommendIndexExec struct {
+	baseExecutor
+
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *RecommendIndexExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	for _, rec := range recommendIndexes(ctx, e.ctx) {
+		req.AppendString(0, rec.candidate.DBName.O)
+		req.AppendString(1, rec.candidate.Table.Name.O)
+		req.AppendString(2, rec.candidate.Name())
+		req.AppendString(3, rec.candidate.ColumnNames())
+		req.AppendFloat64(4, rec.benefit)
+		req.AppendInt64(5, int64(rec.digests))
+		req.AppendString(6, rec.createStatement())
+	}
+	return nil
+}
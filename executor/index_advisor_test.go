@@ -0,0 +1,76 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+// testIndexAdvisorSuite has its own store, so the tables of the other suites are not advised.
+type testIndexAdvisorSuite struct {
+	*baseTestSuite
+}
+
+var _ = Suite(&testIndexAdvisorSuite{&baseTestSuite{}})
+
+func (s *testIndexAdvisorSuite) TestRecommendIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database index_advisor")
+	tk.MustExec("use index_advisor")
+	tk.MustExec("create table t1(a int primary key, b int, c int, d varchar(20), key idx_c(c))")
+	tk.MustExec("create table t2(a int, b int, c int)")
+	for i := 0; i < 3; i++ {
+		tk.MustQuery(fmt.Sprintf("select * from t1 where b = %d", i))
+		tk.MustQuery(fmt.Sprintf("select * from t1 where b = %d and d > 'x'", i))
+		// The existing indexes and the handle are not recommended.
+		tk.MustQuery(fmt.Sprintf("select * from t1 where c = %d", i))
+		tk.MustQuery(fmt.Sprintf("select * from t1 where a = %d", i))
+	}
+	tk.MustQuery("select * from t2 where c = 10")
+	tk.MustQuery("select * from information_schema.tables where table_name = 't1'")
+	// The estimated benefits depend on the cost model, so they are not checked.
+	cols := []int{0, 1, 2, 3, 5}
+	result := tk.MustQuery("admin recommend index")
+	result.CheckAt(cols, testkit.Rows(
+		"index_advisor t1 idx_b b 2",
+		"index_advisor t1 idx_b_d b,d 1",
+		"index_advisor t2 idx_c c 1",
+	))
+	c.Assert(result.Rows()[1][6], Equals, "CREATE INDEX `idx_b_d` ON `index_advisor`.`t1` (`b`, `d`)")
+
+	// The statements aren't recorded when the recording is disabled.
+	tk.MustExec("set @@tidb_record_workload = 0")
+	tk.MustQuery("select * from t1 where d = 'x'")
+	tk.MustExec("set @@tidb_record_workload = 1")
+	tk.MustQuery("admin recommend index").CheckAt(cols, testkit.Rows(
+		"index_advisor t1 idx_b b 2",
+		"index_advisor t1 idx_b_d b,d 1",
+		"index_advisor t2 idx_c c 1",
+	))
+
+	// The statements reading the dropped tables are skipped.
+	tk.MustExec("drop table t2")
+	tk.MustExec("create index idx_b on t1(b)")
+	tk.MustExec("use test")
+	tk.MustQuery("admin recommend index").CheckAt(cols, testkit.Rows(
+		"index_advisor t1 idx_b_d b,d 1",
+	))
+	c.Assert(tk.Se.GetSessionVars().CurrentDB, Equals, "test")
+
+	_, err := tk.Exec("admin recommend")
+	c.Assert(err, NotNil)
+}
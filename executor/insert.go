@@ -38,6 +38,9 @@ func (e *InsertExec) exec(ctx context.Context, rows [][]types.Datum) error {
 	}
 	sessVars.GetWriteStmtBufs().BufStore = kv.NewBufferStore(txn, kv.TempTxnMemBufCap)
 	sessVars.StmtCtx.AddRecordRows(uint64(len(rows)))
+	if sessVars.StmtCtx.DupKeyAsWarning {
+		return e.batchCheckAndInsert(ctx, rows, e.addRecord)
+	}
 	for _, row := range rows {
 		if _, err := e.addRecord(ctx, row); err != nil {
 			return err
@@ -59,7 +62,7 @@ func (e *InsertExec) Next(ctx context.Context, req *chunk.Chunk) error {
 func (e *InsertExec) Close() error {
 	e.ctx.GetSessionVars().CurrInsertValues = chunk.Row{}
 	if e.SelectExec != nil {
-		return e.SelectExec.Close()
+		return Close(e.SelectExec)
 	}
 	return nil
 }
@@ -67,7 +70,7 @@ func (e *InsertExec) Close() error {
 // Open implements the Executor Open interface.
 func (e *InsertExec) Open(ctx context.Context) error {
 	if e.SelectExec != nil {
-		return e.SelectExec.Open(ctx)
+		return Open(ctx, e.SelectExec)
 	}
 	if !e.allAssignmentsAreConstant {
 		e.initEvalBuffer()
@@ -23,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -45,6 +46,8 @@ type InsertValues struct {
 	Columns []*ast.ColumnName
 	Lists   [][]expression.Expression
 	SetList []*expression.Assignment
+	// GenExprs are the expressions of the generated columns, in the order of the columns in the table.
+	GenExprs []expression.Expression
 
 	insertColumns []*table.Column
 
@@ -92,7 +95,8 @@ func (e *InsertValues) initInsertColumns() error {
 	var cols []*table.Column
 	var err error
 
-	tableCols := e.Table.Cols()
+	// The hidden columns are generated from the other columns, they can't be inserted explicitly.
+	tableCols := e.Table.VisibleCols()
 
 	if len(e.SetList) > 0 {
 		// Process `set` type column.
@@ -410,7 +414,8 @@ func (e *InsertValues) getColDefaultValue(idx int, col *table.Column) (d types.D
 	if err != nil {
 		return types.Datum{}, err
 	}
-	if initialized := e.lazilyInitColDefaultValBuf(); initialized {
+	// The default value expression is evaluated for each row, so it can't be cached.
+	if initialized := e.lazilyInitColDefaultValBuf(); initialized && !col.DefaultIsExpr {
 		e.colDefaultVals[idx].val = defaultVal
 		e.colDefaultVals[idx].valid = true
 	}
@@ -454,6 +459,10 @@ func (e *InsertValues) fillColValue(ctx context.Context, datum types.Datum, idx
 // https://dev.mysql.com/doc/refman/8.0/en/innodb-auto-increment-handling.html
 func (e *InsertValues) fillRow(ctx context.Context, row []types.Datum, hasValue []bool) ([]types.Datum, error) {
 	for i, c := range e.Table.Cols() {
+		// The generated columns are evaluated after all the other columns are filled.
+		if c.IsGenerated() {
+			continue
+		}
 		var err error
 		// Get the default value for all no value columns, the auto increment column is different from the others.
 		if row[i], err = e.fillColValue(ctx, row[i], i, c, hasValue[i]); err != nil {
@@ -465,9 +474,36 @@ func (e *InsertValues) fillRow(ctx context.Context, row []types.Datum, hasValue
 			}
 		}
 	}
+	if err := evalGeneratedColumns(e.ctx, e.Table, e.GenExprs, row); err != nil {
+		return nil, err
+	}
 	return row, nil
 }
 
+// evalGeneratedColumns evaluates the generated columns of the row with genExprs, which are built
+// over the columns of the table.
+func evalGeneratedColumns(sctx sessionctx.Context, t table.Table, genExprs []expression.Expression, row []types.Datum) error {
+	if len(genExprs) == 0 {
+		return nil
+	}
+	mutRow := chunk.MutRowFromDatums(row)
+	i := 0
+	for _, col := range t.Cols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		val, err := genExprs[i].Eval(mutRow.ToRow())
+		if err != nil {
+			return err
+		}
+		if row[col.Offset], err = table.CastValue(sctx, val, col.ToInfo()); err != nil {
+			return err
+		}
+		i++
+	}
+	return nil
+}
+
 // isAutoNull can help judge whether a datum is AutoIncrement Null quickly.
 // This used to help lazyFillAutoIncrement to find consecutive N datum backwards for batch autoID alloc.
 func (e *InsertValues) isAutoNull(ctx context.Context, d types.Datum, col *table.Column) bool {
@@ -657,6 +693,56 @@ func getAutoRecordID(d types.Datum, target *types.FieldType, isInsert bool) (int
 	return recordID, nil
 }
 
+// batchCheckAndInsert checks the rows for duplicate keys before inserting them, it's used by `insert ignore`.
+// The rows which have duplicate keys with the table or the former rows are skipped,
+// and the duplicate key errors are appended as warnings.
+func (e *InsertValues) batchCheckAndInsert(ctx context.Context, rows [][]types.Datum, addRecord func(ctx context.Context, row []types.Datum) (int64, error)) error {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	// All the rows are checked here, so the keys don't need to be checked again when adding them.
+	sc.BatchCheck = true
+	toBeCheckedRows, err := getKeysNeedCheck(ctx, e.ctx, e.Table, rows)
+	if err != nil {
+		return err
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	for i, r := range toBeCheckedRows {
+		dupKey, err := findDupKey(ctx, txn, r)
+		if err != nil {
+			return err
+		}
+		if dupKey != nil {
+			sc.AppendWarning(dupKey.dupErr)
+			continue
+		}
+		sc.AddCopiedRows(1)
+		if _, err = addRecord(ctx, rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findDupKey returns the first record key or unique key of the row which already exists in txn.
+func findDupKey(ctx context.Context, txn kv.Transaction, r toBeCheckedRow) (*keyValueWithDupInfo, error) {
+	keys := r.uniqueKeys
+	if r.handleKey != nil {
+		keys = append([]*keyValueWithDupInfo{r.handleKey}, keys...)
+	}
+	for _, k := range keys {
+		_, err := txn.Get(ctx, k.newKV.key)
+		if err == nil {
+			return k, nil
+		}
+		if !kv.IsErrNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
 func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) (int64, error) {
 	txn, err := e.ctx.Txn(true)
 	if err != nil {
@@ -69,3 +69,43 @@ func (s *testSuite3) TestAllocateContinuousRowID(c *C) {
 	}
 	wg.Wait()
 }
+
+func (s *testSuite3) TestInsertIgnore(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(3) not null, unique key ub(b))")
+	tk.MustExec("insert t values (1, 1, 'a')")
+
+	tk.MustExec("insert ignore t values (1, 2, 'b'), (2, 1, 'c'), (3, 3, 'd'), (3, 4, 'e'), (5, 3, 'f')")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(1))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1062 Duplicate entry '1' for key 'PRIMARY'",
+		"Warning 1062 Duplicate entry '1' for key 'ub'",
+		"Warning 1062 Duplicate entry '3' for key 'PRIMARY'",
+		"Warning 1062 Duplicate entry '3' for key 'ub'"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 1 a", "3 3 d"))
+
+	// The data conversion errors are turned into warnings.
+	tk.MustExec("insert ignore into t values (6, 6, 'toolong'), (7, 7, null)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(2))
+	tk.MustQuery("select * from t where a > 5").Check(testkit.Rows("6 6 too", "7 7 "))
+
+	tk.MustExec("insert ignore into t select a + 10, b, c from t")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("4"))
+	tk.MustExec("insert ignore into t set a = 20, b = 20, c = 'z'")
+	tk.MustQuery("select * from t where a = 20").Check(testkit.Rows("20 20 z"))
+
+	// The rows inserted by the former statements in the transaction are checked too.
+	tk.MustExec("begin")
+	tk.MustExec("insert ignore t values (30, 30, 'a')")
+	tk.MustExec("insert ignore t values (30, 31, 'b'), (31, 30, 'c')")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(0))
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t where a >= 30").Check(testkit.Rows("30 30 a"))
+
+	// Without ignore, it's still an error.
+	_, err := tk.Exec("insert into t values (1, 100, 'a')")
+	c.Assert(err, NotNil)
+	tk.MustExec("drop table t")
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
@@ -40,7 +41,10 @@ type HashJoinExec struct {
 	innerKeys         []*expression.Column
 
 	// concurrency is the number of partition, build and join workers.
-	concurrency  uint
+	concurrency uint
+	// numWorkers is the number of the started join workers, it may be less than
+	// concurrency if the tokens of the concurrency governor are not enough.
+	numWorkers   uint
 	rowContainer *hashRowContainer
 	// joinWorkerWaitGroup is for sync multiple join workers.
 	joinWorkerWaitGroup sync.WaitGroup
@@ -103,6 +107,12 @@ func (e *HashJoinExec) Close() error {
 		e.outerChkResourceCh = nil
 		e.joinChkResourceCh = nil
 	}
+	if e.rowContainer != nil {
+		terror.Log(e.rowContainer.Close())
+		e.rowContainer = nil
+	}
+	globalConcurrencyGovernor.release(int(e.numWorkers))
+	e.numWorkers = 0
 	err := e.baseExecutor.Close()
 	return err
 }
@@ -146,6 +156,16 @@ func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 	return nil
 }
 
+// setKeyCollations sets the collations of the join keys in allTypes, so the keys
+// of both sides are hashed in the collation they are compared in.
+func (e *HashJoinExec) setKeyCollations(allTypes []*types.FieldType, keys []*expression.Column) {
+	for i, key := range keys {
+		if tp := allTypes[key.Index]; tp.EvalType() == types.ETString {
+			tp.Collate = expression.DeriveCollationFromExprs(e.innerKeys[i], e.outerKeys[i])
+		}
+	}
+}
+
 func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) error {
 	// TODO: Implementing the building hash table stage.
 
@@ -167,12 +187,18 @@ func (e *HashJoinExec) fetchAndBuildHashTable(ctx context.Context) error {
 	for _, tp := range retTypes(e.innerSideExec) {
 		innerKeyColTypes = append(innerKeyColTypes, tp.Clone())
 	}
+	e.setKeyCollations(innerKeyColTypes, e.innerKeys)
 	hCtx := &hashContext{
 		allTypes:  innerKeyColTypes,
 		keyColIdx: innerKeyColIdx,
 	}
-	initList := chunk.NewList(innerKeyColTypes, maxChunkSize, maxChunkSize)
-	e.rowContainer = newHashRowContainer(e.ctx, int(e.innerSideEstCount), hCtx, initList)
+	records := chunk.NewRowContainer(innerKeyColTypes, maxChunkSize, maxChunkSize)
+	sc := e.ctx.GetSessionVars().StmtCtx
+	records.SetMemoryConsumer(func(bytes int64) error {
+		return sc.ConsumeMemory("hash join", bytes)
+	})
+	records.SetDiskConsumer(sc.AddSpilledBytes)
+	e.rowContainer = newHashRowContainer(e.ctx, int(e.innerSideEstCount), hCtx, records)
 
 	for {
 		chk := chunk.NewChunkWithCapacity(e.innerSideExec.base().retFieldTypes, maxChunkSize)
@@ -196,15 +222,15 @@ func (e *HashJoinExec) initializeForOuter() {
 	// e.outerResultChs is for transmitting the chunks which store the data of
 	// outerSideExec, it'll be written by outer side worker goroutine, and read by join
 	// workers.
-	e.outerResultChs = make([]chan *chunk.Chunk, e.concurrency)
-	for i := uint(0); i < e.concurrency; i++ {
+	e.outerResultChs = make([]chan *chunk.Chunk, e.numWorkers)
+	for i := uint(0); i < e.numWorkers; i++ {
 		e.outerResultChs[i] = make(chan *chunk.Chunk, 1)
 	}
 
 	// e.outerChkResourceCh is for transmitting the used outerSideExec chunks from
 	// join workers to outerSideExec worker.
-	e.outerChkResourceCh = make(chan *outerChkResource, e.concurrency)
-	for i := uint(0); i < e.concurrency; i++ {
+	e.outerChkResourceCh = make(chan *outerChkResource, e.numWorkers)
+	for i := uint(0); i < e.numWorkers; i++ {
 		e.outerChkResourceCh <- &outerChkResource{
 			chk:  newFirstChunk(e.outerSideExec),
 			dest: e.outerResultChs[i],
@@ -213,15 +239,15 @@ func (e *HashJoinExec) initializeForOuter() {
 
 	// e.joinChkResourceCh is for transmitting the reused join result chunks
 	// from the main thread to join worker goroutines.
-	e.joinChkResourceCh = make([]chan *chunk.Chunk, e.concurrency)
-	for i := uint(0); i < e.concurrency; i++ {
+	e.joinChkResourceCh = make([]chan *chunk.Chunk, e.numWorkers)
+	for i := uint(0); i < e.numWorkers; i++ {
 		e.joinChkResourceCh[i] = make(chan *chunk.Chunk, 1)
 		e.joinChkResourceCh[i] <- newFirstChunk(e)
 	}
 
 	// e.joinResultCh is for transmitting the join result chunks to the main
 	// thread.
-	e.joinResultCh = make(chan *hashjoinWorkerResult, e.concurrency+1)
+	e.joinResultCh = make(chan *hashjoinWorkerResult, e.numWorkers+1)
 }
 
 // fetchOuterSideChunks get chunks from fetches chunks from the big table in a background goroutine
@@ -256,6 +282,7 @@ func (e *HashJoinExec) fetchOuterSideChunks(ctx context.Context) {
 }
 
 func (e *HashJoinExec) fetchAndProbeHashTable(ctx context.Context) {
+	e.numWorkers = uint(globalConcurrencyGovernor.acquire(int(e.concurrency)))
 	e.initializeForOuter()
 	e.joinWorkerWaitGroup.Add(1)
 	go util.WithRecovery(func() { e.fetchOuterSideChunks(ctx) }, e.handleOuterSideFetcherPanic)
@@ -265,9 +292,9 @@ func (e *HashJoinExec) fetchAndProbeHashTable(ctx context.Context) {
 		outerKeyColIdx[i] = e.outerKeys[i].Index
 	}
 
-	// Start e.concurrency join workers to outer hash table and join build side and
+	// Start e.numWorkers join workers to outer hash table and join build side and
 	// outer side rows.
-	for i := uint(0); i < e.concurrency; i++ {
+	for i := uint(0); i < e.numWorkers; i++ {
 		e.joinWorkerWaitGroup.Add(1)
 		workID := i
 		go util.WithRecovery(func() { e.runJoinWorker(workID, outerKeyColIdx) }, e.handleJoinWorkerPanic)
@@ -303,10 +330,12 @@ func (e *HashJoinExec) runJoinWorker(workerID uint, outerKeyColIdx []int) {
 	for _, tp := range retTypes(e.outerSideExec) {
 		outerKeyAllTypes = append(outerKeyAllTypes, tp.Clone())
 	}
+	e.setKeyCollations(outerKeyAllTypes, e.outerKeys)
 
 	hCtx := &hashContext{
-		allTypes:  outerKeyAllTypes,
-		keyColIdx: outerKeyColIdx,
+		allTypes:   outerKeyAllTypes,
+		keyColIdx:  outerKeyColIdx,
+		chunkCache: chunk.NewDiskChunkCache(probeChunkCacheCapacity),
 	}
 	for ok := true; ok; {
 		select {
@@ -443,3 +472,167 @@ func (e *HashJoinExec) join2Chunk(workerID uint, outerSideChk *chunk.Chunk, hCtx
 	}
 	return true, joinResult
 }
+
+var _ Executor = &NestedLoopApplyExec{}
+
+// NestedLoopApplyExec is the executor for apply.
+// For every outer row, it sets the correlated columns to the values of the row, then executes the
+// inner executor again and joins the row with all the inner rows.
+type NestedLoopApplyExec struct {
+	baseExecutor
+
+	innerExec   Executor
+	outerExec   Executor
+	innerFilter expression.CNFExprs
+	outerFilter expression.CNFExprs
+	// outer indicates whether the unmatched outer rows are outputted.
+	outer bool
+
+	joiner joiner
+
+	outerSchema []*expression.CorrelatedColumn
+
+	outerChunk       *chunk.Chunk
+	outerChunkCursor int
+	outerSelected    []bool
+	innerRows        *chunk.RowContainer
+	innerChunk       *chunk.Chunk
+	innerSelected    []bool
+	innerIter        *chunk.Iterator4RowContainer
+	outerRow         *chunk.Row
+	hasMatch         bool
+}
+
+// Close implements the Executor interface.
+func (e *NestedLoopApplyExec) Close() error {
+	if e.innerRows != nil {
+		terror.Log(e.innerRows.Close())
+		e.innerRows = nil
+	}
+	return e.outerExec.Close()
+}
+
+// Open implements the Executor interface.
+func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
+	// The inner executor is opened for every outer row.
+	err := e.outerExec.Open(ctx)
+	if err != nil {
+		return err
+	}
+	e.outerChunk = newFirstChunk(e.outerExec)
+	e.outerChunkCursor = 0
+	e.innerChunk = newFirstChunk(e.innerExec)
+	e.innerRows = chunk.NewRowContainer(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
+	sc := e.ctx.GetSessionVars().StmtCtx
+	e.innerRows.SetMemoryConsumer(func(bytes int64) error {
+		return sc.ConsumeMemory("apply", bytes)
+	})
+	e.innerRows.SetDiskConsumer(sc.AddSpilledBytes)
+	e.innerIter = nil
+	e.outerRow = nil
+	return nil
+}
+
+func (e *NestedLoopApplyExec) fetchSelectedOuterRow(ctx context.Context, chk *chunk.Chunk) (*chunk.Row, error) {
+	outerIter := chunk.NewIterator4Chunk(e.outerChunk)
+	for {
+		if e.outerChunkCursor >= e.outerChunk.NumRows() {
+			err := Next(ctx, e.outerExec, e.outerChunk)
+			if err != nil {
+				return nil, err
+			}
+			if e.outerChunk.NumRows() == 0 {
+				return nil, nil
+			}
+			e.outerSelected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, outerIter, e.outerSelected)
+			if err != nil {
+				return nil, err
+			}
+			e.outerChunkCursor = 0
+		}
+		outerRow := e.outerChunk.GetRow(e.outerChunkCursor)
+		selected := e.outerSelected[e.outerChunkCursor]
+		e.outerChunkCursor++
+		if selected {
+			return &outerRow, nil
+		} else if e.outer {
+			e.joiner.onMissMatch(outerRow, chk)
+			if chk.IsFull() {
+				return nil, nil
+			}
+		}
+	}
+}
+
+// fetchAllInners executes the inner executor and stores the selected rows in innerRows.
+func (e *NestedLoopApplyExec) fetchAllInners(ctx context.Context) (err error) {
+	err = e.innerExec.Open(ctx)
+	defer func() {
+		if closeErr := e.innerExec.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	if err != nil {
+		return err
+	}
+	if err = e.innerRows.Reset(); err != nil {
+		return err
+	}
+	innerIter := chunk.NewIterator4Chunk(e.innerChunk)
+	for {
+		err = Next(ctx, e.innerExec, e.innerChunk)
+		if err != nil {
+			return err
+		}
+		if e.innerChunk.NumRows() == 0 {
+			return nil
+		}
+		e.innerSelected, err = expression.VectorizedFilter(e.ctx, e.innerFilter, innerIter, e.innerSelected)
+		if err != nil {
+			return err
+		}
+		for row := innerIter.Begin(); row != innerIter.End(); row = innerIter.Next() {
+			if e.innerSelected[row.Idx()] {
+				if _, err = e.innerRows.AppendRow(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// Next implements the Executor interface.
+func (e *NestedLoopApplyExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
+	req.Reset()
+	for {
+		if e.innerIter == nil || e.innerIter.Current() == e.innerIter.End() {
+			if e.outerRow != nil && !e.hasMatch {
+				e.joiner.onMissMatch(*e.outerRow, req)
+			}
+			e.outerRow, err = e.fetchSelectedOuterRow(ctx, req)
+			if e.outerRow == nil || err != nil {
+				return err
+			}
+			e.hasMatch = false
+
+			for _, col := range e.outerSchema {
+				*col.Data = e.outerRow.GetDatum(col.Index, col.RetType)
+			}
+			err = e.fetchAllInners(ctx)
+			if err != nil {
+				return err
+			}
+			e.innerIter = chunk.NewIterator4RowContainer(e.innerRows)
+			e.innerIter.Begin()
+		}
+
+		matched, _, err := e.joiner.tryToMatchInners(*e.outerRow, e.innerIter, req)
+		e.hasMatch = e.hasMatch || matched
+		if err == nil {
+			err = e.innerIter.Error()
+		}
+		if err != nil || req.IsFull() {
+			return err
+		}
+	}
+}
@@ -412,3 +412,31 @@ func (s *testSuiteJoin1) TestInjectProjOnTopN(c *C) {
 		"2",
 	))
 }
+
+func (s *testSuiteJoin1) TestUsingAndNaturalJoin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2, t3")
+	tk.MustExec("create table t1(a int, b int, c int)")
+	tk.MustExec("create table t2(c int, a int, d int)")
+	tk.MustExec("create table t3(a int, e int)")
+	tk.MustExec("insert into t1 values(1, 1, 1), (2, 2, 2)")
+	tk.MustExec("insert into t2 values(1, 1, 10), (3, 3, 30)")
+	tk.MustExec("insert into t3 values(1, 100), (3, 300)")
+
+	tk.MustQuery("select * from t1 join t2 using (a)").Check(testkit.Rows("1 1 1 1 10"))
+	tk.MustQuery("select * from t1 join t2 using (a, c)").Check(testkit.Rows("1 1 1 10"))
+	tk.MustQuery("select * from t1 natural join t2").Check(testkit.Rows("1 1 1 10"))
+	tk.MustQuery("select * from t1 left join t2 using (a) order by a").Check(testkit.Rows("1 1 1 1 10", "2 2 2 <nil> <nil>"))
+	tk.MustQuery("select * from t1 right join t2 using (a) order by a").Check(testkit.Rows("1 1 10 1 1", "3 3 30 <nil> <nil>"))
+	tk.MustQuery("select * from t1 natural left join t2 order by a").Check(testkit.Rows("1 1 1 10", "2 2 2 <nil>"))
+
+	// The eliminated column can still be referenced with its table name.
+	tk.MustQuery("select t1.a, t2.a, a from t1 right join t2 using (a) order by t2.a").Check(testkit.Rows("1 1 1", "<nil> 3 3"))
+	tk.MustQuery("select t2.a, t3.e from t1 join t2 using (a) join t3 using (a)").Check(testkit.Rows("1 100"))
+	tk.MustQuery("select * from t1 join t2 using (a) where t2.a = 1").Check(testkit.Rows("1 1 1 1 10"))
+
+	_, err := tk.Exec("select * from t1 join t2 using (e)")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column 'e' in 'from clause'")
+}
@@ -42,6 +42,7 @@ type memIndexReader struct {
 	outputOffset  []int
 	// belowHandleIndex is the handle's position of the below scan plan.
 	belowHandleIndex int
+	cacheTable       kv.MemBuffer
 }
 
 func buildMemIndexReader(us *UnionScanExec, idxReader *IndexReaderExecutor) *memIndexReader {
@@ -61,6 +62,7 @@ func buildMemIndexReader(us *UnionScanExec, idxReader *IndexReaderExecutor) *mem
 		retFieldTypes:    retTypes(us),
 		outputOffset:     outputOffset,
 		belowHandleIndex: us.belowHandleIndex,
+		cacheTable:       us.cacheTable,
 	}
 }
 
@@ -83,7 +85,7 @@ func (m *memIndexReader) getMemRows() ([][]types.Datum, error) {
 	}
 
 	mutableRow := chunk.MutRowFromTypes(m.retFieldTypes)
-	err := iterTxnMemBuffer(m.ctx, m.kvRanges, func(key, value []byte) error {
+	err := iterTxnMemBuffer(m.ctx, m.cacheTable, m.kvRanges, func(key, value []byte) error {
 		data, err := m.decodeIndexKeyValue(key, value, tps)
 		if err != nil {
 			return err
@@ -140,6 +142,10 @@ type memTableReader struct {
 	retFieldTypes []*types.FieldType
 	colIDs        map[int64]int
 	buffer        allocBuf
+	// schema and virtualColumnIndex are used to evaluate the virtual columns.
+	schema             *expression.Schema
+	virtualColumnIndex []int
+	cacheTable         kv.MemBuffer
 }
 
 type allocBuf struct {
@@ -181,19 +187,33 @@ func buildMemTableReader(us *UnionScanExec, tblReader *TableReaderExecutor) *mem
 			handleBytes: make([]byte, 0, 16),
 			rd:          rd,
 		},
+		schema:             us.schema,
+		virtualColumnIndex: tblReader.virtualColumnIndex,
+		cacheTable:         us.cacheTable,
 	}
 }
 
 // TODO: Try to make memXXXReader lazy, There is no need to decode many rows when parent operator only need 1 row.
 func (m *memTableReader) getMemRows() ([][]types.Datum, error) {
 	mutableRow := chunk.MutRowFromTypes(m.retFieldTypes)
-	err := iterTxnMemBuffer(m.ctx, m.kvRanges, func(key, value []byte) error {
+	err := iterTxnMemBuffer(m.ctx, m.cacheTable, m.kvRanges, func(key, value []byte) error {
 		row, err := m.decodeRecordKeyValue(key, value)
 		if err != nil {
 			return err
 		}
 
 		mutableRow.SetDatums(row...)
+		for _, idx := range m.virtualColumnIndex {
+			datum, err := m.schema.Columns[idx].EvalVirtualColumn(mutableRow.ToRow())
+			if err != nil {
+				return err
+			}
+			row[idx], err = table.CastValue(m.ctx, datum, m.columns[idx])
+			if err != nil {
+				return err
+			}
+			mutableRow.SetDatum(idx, row[idx])
+		}
 		matched, _, err := expression.EvalBool(m.ctx, m.conditions, mutableRow.ToRow())
 		if err != nil || !matched {
 			return err
@@ -253,16 +273,30 @@ func getRowData(
 
 type processKVFunc func(key, value []byte) error
 
-func iterTxnMemBuffer(ctx sessionctx.Context, kvRanges []kv.KeyRange, fn processKVFunc) error {
+// iterTxnMemBuffer iterates the kv pairs in the ranges of the txn memory buffer, and those of the cached
+// table data if cacheTable is not nil.
+func iterTxnMemBuffer(ctx sessionctx.Context, cacheTable kv.MemBuffer, kvRanges []kv.KeyRange, fn processKVFunc) error {
 	txn, err := ctx.Txn(true)
 	if err != nil {
 		return err
 	}
 	for _, rg := range kvRanges {
-		iter, err := txn.GetMemBuffer().Iter(rg.StartKey, rg.EndKey)
+		var iter kv.Iterator
+		iter, err = txn.GetMemBuffer().Iter(rg.StartKey, rg.EndKey)
 		if err != nil {
 			return err
 		}
+		if cacheTable != nil {
+			var cacheIter kv.Iterator
+			cacheIter, err = cacheTable.Iter(rg.StartKey, rg.EndKey)
+			if err != nil {
+				return err
+			}
+			iter, err = kv.NewUnionIter(iter, cacheIter, false)
+			if err != nil {
+				return err
+			}
+		}
 		for ; iter.Valid(); err = iter.Next() {
 			if err != nil {
 				return err
@@ -297,7 +331,7 @@ func (m *memIndexReader) getMemRowsHandle() ([]int64, error) {
 		}
 	}
 	handles := make([]int64, 0, m.addedRowsLen)
-	err := iterTxnMemBuffer(m.ctx, m.kvRanges, func(key, value []byte) error {
+	err := iterTxnMemBuffer(m.ctx, m.cacheTable, m.kvRanges, func(key, value []byte) error {
 		handle, err := tablecodec.DecodeIndexHandle(key, value, len(m.index.Columns), pkTp)
 		if err != nil {
 			return err
@@ -327,6 +361,10 @@ type memIndexLookUpReader struct {
 	retFieldTypes []*types.FieldType
 
 	idxReader *memIndexReader
+
+	// schema and virtualColumnIndex are used to evaluate the virtual columns.
+	schema             *expression.Schema
+	virtualColumnIndex []int
 }
 
 func buildMemIndexLookUpReader(us *UnionScanExec, idxLookUpReader *IndexLookUpExecutor) *memIndexLookUpReader {
@@ -342,6 +380,7 @@ func buildMemIndexLookUpReader(us *UnionScanExec, idxLookUpReader *IndexLookUpEx
 		retFieldTypes:    retTypes(us),
 		outputOffset:     outputOffset,
 		belowHandleIndex: us.belowHandleIndex,
+		cacheTable:       us.cacheTable,
 	}
 
 	return &memIndexLookUpReader{
@@ -353,6 +392,9 @@ func buildMemIndexLookUpReader(us *UnionScanExec, idxLookUpReader *IndexLookUpEx
 		conditions:    us.conditions,
 		retFieldTypes: retTypes(us),
 		idxReader:     memIdxReader,
+
+		schema:             us.schema,
+		virtualColumnIndex: idxLookUpReader.virtualColumnIndex,
 	}
 }
 
@@ -392,6 +434,9 @@ func (m *memIndexLookUpReader) getMemRows() ([][]types.Datum, error) {
 			handleBytes: make([]byte, 0, 16),
 			rd:          rd,
 		},
+		schema:             m.schema,
+		virtualColumnIndex: m.virtualColumnIndex,
+		cacheTable:         m.idxReader.cacheTable,
 	}
 
 	return memTblReader.getMemRows()
@@ -2,6 +2,7 @@ package executor
 
 import (
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser"
 )
 
 var _ = Suite(&pkgTestSuite{})
@@ -38,3 +39,27 @@ func (s *pkgTestSuite) TestMoveInfoSchemaToFront(c *C) {
 		}
 	}
 }
+
+func (s *pkgTestSuite) TestStmtWorkload(c *C) {
+	w := newStmtWorkload(2)
+	record := func(sql, db string) {
+		w.record(parser.DigestHash(sql), sql, db, "", "")
+	}
+	record("select * from t where a = 1", "test")
+	record("select * from t where a = 3", "test1")
+	record("select * from t where a = 2", "test")
+	stmts := w.statements()
+	c.Assert(stmts, HasLen, 2)
+	c.Assert(stmts[0].sql, Equals, "select * from t where a = 2")
+	c.Assert(stmts[0].db, Equals, "test")
+	c.Assert(stmts[0].execCount, Equals, uint64(2))
+	c.Assert(stmts[1].db, Equals, "test1")
+
+	// The least recently executed digest is evicted when the workload is full.
+	record("select * from t where b = 1", "test")
+	stmts = w.statements()
+	c.Assert(stmts, HasLen, 2)
+	c.Assert(stmts[0].sql, Equals, "select * from t where a = 2")
+	c.Assert(stmts[1].sql, Equals, "select * from t where b = 1")
+	c.Assert(stmts[1].execCount, Equals, uint64(1))
+}
@@ -0,0 +1,244 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/planner"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+const (
+	// evolveSpeedupRatio is the min ratio of the execution time of the baseline to the one of
+	// the new plan, the baseline is replaced only if the new plan is much faster.
+	evolveSpeedupRatio = 1.5
+	// evolveExecCount is the number of the executions of each plan in the evolution, the
+	// fastest execution is used to avoid the noises like the cold caches.
+	evolveExecCount = 2
+)
+
+// capturePlanBaseline captures the plan of the SELECT statement executed repeatedly as its baseline.
+func (a *ExecStmt) capturePlanBaseline() {
+	vars := a.Ctx.GetSessionVars()
+	if !vars.CapturePlanBaselines || vars.InRestrictedSQL || vars.StmtCtx.PlanBaseline != nil {
+		return
+	}
+	sel, ok := a.StmtNode.(*ast.SelectStmt)
+	// The statements built without text, such as the ones built by BATCH DML, can't be captured.
+	if !ok || sel.From == nil || a.Text == "" || len(sel.TableHints) > 0 {
+		return
+	}
+	paths := plannercore.PlanAccessPaths(a.Plan)
+	dom := domain.GetDomain(a.Ctx)
+	if len(paths) == 0 || dom == nil {
+		return
+	}
+	dom.PlanBaselineHandle().Capture(&bindinfo.Baseline{
+		DB:          vars.CurrentDB,
+		Digest:      vars.StmtCtx.SQLDigest(),
+		SQL:         a.Text,
+		AccessPaths: paths,
+	})
+}
+
+// EvolvePlanBaselines tests the plans chosen by the optimizer for the statements with
+// baselines. The statements are executed with both plans, and the baseline is replaced if
+// the new plan is evolveSpeedupRatio times faster. The statements are executed in a system
+// session, so the evolution doesn't affect the state of sctx. It returns the number of the
+// evolved baselines.
+func EvolvePlanBaselines(ctx context.Context, sctx sessionctx.Context) (int, error) {
+	dom := domain.GetDomain(sctx)
+	res, err := dom.SysSessionPool().Get()
+	if err != nil {
+		return 0, err
+	}
+	defer dom.SysSessionPool().Put(res)
+	se := res.(sessionctx.Context)
+	vars := se.GetSessionVars()
+	currentDB, useBaselines := vars.CurrentDB, vars.UsePlanBaselines
+	defer func() {
+		vars.CurrentDB, vars.UsePlanBaselines = currentDB, useBaselines
+	}()
+
+	handle := dom.PlanBaselineHandle()
+	evolved := 0
+	for _, baseline := range handle.Baselines() {
+		paths, err := evolvePlanBaseline(ctx, se, baseline)
+		if err != nil {
+			// The statements which can't be executed any more, like the ones reading the dropped tables, are skipped.
+			logutil.Logger(ctx).Debug("skip the plan baseline for evolution", zap.String("sql", baseline.SQL), zap.Error(err))
+			continue
+		}
+		if paths != nil {
+			handle.Evolve(baseline.DB, baseline.Digest, paths)
+			evolved++
+		}
+	}
+	return evolved, nil
+}
+
+// evolvePlanBaseline returns the access paths of the plan chosen by the optimizer if it's
+// much faster than the baseline, otherwise nil is returned.
+func evolvePlanBaseline(ctx context.Context, se sessionctx.Context, baseline *bindinfo.Baseline) ([]bindinfo.TableAccessPath, error) {
+	vars := se.GetSessionVars()
+	vars.CurrentDB = baseline.DB
+	vars.UsePlanBaselines = false
+	paths, err := optimizerAccessPaths(ctx, se, baseline.SQL)
+	if err != nil || len(paths) == 0 || baseline.SameAccessPaths(paths) {
+		return nil, err
+	}
+	newTime, err := bestExecTime(ctx, se, baseline.SQL)
+	if err != nil {
+		return nil, err
+	}
+	vars.UsePlanBaselines = true
+	baselineTime, err := bestExecTime(ctx, se, baseline.SQL)
+	if err != nil {
+		return nil, err
+	}
+	if float64(newTime)*evolveSpeedupRatio > float64(baselineTime) {
+		return nil, nil
+	}
+	return paths, nil
+}
+
+// optimizerAccessPaths returns the access paths of the plan chosen by the optimizer.
+func optimizerAccessPaths(ctx context.Context, se sessionctx.Context, sql string) ([]bindinfo.TableAccessPath, error) {
+	vars := se.GetSessionVars()
+	stmtCtx := vars.StmtCtx
+	defer func() {
+		vars.StmtCtx = stmtCtx
+	}()
+	vars.StmtCtx = &stmtctx.StatementContext{
+		InSelectStmt:      true,
+		OverflowAsWarning: true,
+		TruncateAsWarning: true,
+		IgnoreZeroInDate:  true,
+		TimeZone:          vars.Location(),
+		OriginalSQL:       sql,
+	}
+
+	charset, collation := vars.GetCharsetInfo()
+	node, err := parser.New().ParseOneStmt(sql, charset, collation)
+	if err != nil {
+		return nil, err
+	}
+	is := domain.GetDomain(se).InfoSchema()
+	if err := plannercore.Preprocess(se, node, is); err != nil {
+		return nil, err
+	}
+	p, _, err := planner.Optimize(ctx, se, node, is)
+	if err != nil {
+		return nil, err
+	}
+	return plannercore.PlanAccessPaths(p), nil
+}
+
+// bestExecTime executes the statement evolveExecCount times and returns the shortest execution time.
+func bestExecTime(ctx context.Context, se sessionctx.Context, sql string) (time.Duration, error) {
+	var best time.Duration
+	for i := 0; i < evolveExecCount; i++ {
+		start := time.Now()
+		if err := execAndDrain(ctx, se, sql); err != nil {
+			return 0, err
+		}
+		if d := time.Since(start); i == 0 || d < best {
+			best = d
+		}
+	}
+	return best, nil
+}
+
+func execAndDrain(ctx context.Context, se sessionctx.Context, sql string) error {
+	rss, err := se.(sqlexec.SQLExecutor).Execute(ctx, sql)
+	if err != nil {
+		return err
+	}
+	for _, rs := range rss {
+		req := rs.NewChunk()
+		for {
+			if err = rs.Next(ctx, req); err != nil || req.NumRows() == 0 {
+				break
+			}
+		}
+		if closeErr := rs.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EvolvePlanBaselinesIfEnabled evolves the plan baselines if tidb_evolve_plan_baselines is on,
+// it's called periodically in the background.
+func EvolvePlanBaselinesIfEnabled(ctx context.Context, sctx sessionctx.Context) error {
+	val, err := variable.GetGlobalSystemVar(sctx.GetSessionVars(), variable.TiDBEvolvePlanBaselines)
+	if err != nil || !variable.TiDBOptOn(val) {
+		return err
+	}
+	_, err = EvolvePlanBaselines(ctx, sctx)
+	return errors.Trace(err)
+}
+
+func formatAccessPaths(paths []bindinfo.TableAccessPath) string {
+	strs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		strs = append(strs, fmt.Sprintf("%s.%s(%s)", path.DB, path.Table, path.Index))
+	}
+	return strings.Join(strs, ", ")
+}
+
+// ShowBaselinesExec represents an executor for the `ADMIN SHOW BASELINES` statement.
+type ShowBaselinesExec struct {
+	baseExecutor
+
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *ShowBaselinesExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	for _, baseline := range domain.GetDomain(e.ctx).PlanBaselineHandle().Baselines() {
+		req.AppendString(0, baseline.DB)
+		req.AppendString(1, baseline.SQL)
+		req.AppendString(2, baseline.Digest)
+		req.AppendString(3, formatAccessPaths(baseline.AccessPaths))
+		req.AppendString(4, baseline.Source)
+		req.AppendString(5, baseline.UpdateTime.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
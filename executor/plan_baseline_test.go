@@ -0,0 +1,112 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/planner"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+// testPlanBaselineSuite has its own store, so the baselines of the other suites are not evolved.
+type testPlanBaselineSuite struct {
+	*baseTestSuite
+}
+
+var _ = Suite(&testPlanBaselineSuite{&baseTestSuite{}})
+
+func planAccessPaths(c *C, tk *testkit.TestKit, sql string) []bindinfo.TableAccessPath {
+	stmt, err := parser.New().ParseOneStmt(sql, "", "")
+	c.Assert(err, IsNil)
+	stmt.SetText(sql)
+	is := domain.GetDomain(tk.Se).InfoSchema()
+	c.Assert(plannercore.Preprocess(tk.Se, stmt, is), IsNil)
+	p, _, err := planner.Optimize(context.Background(), tk.Se, stmt, is)
+	c.Assert(err, IsNil)
+	return plannercore.PlanAccessPaths(p)
+}
+
+func (s *testPlanBaselineSuite) TestCaptureAndEvolve(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, c int)")
+	values := make([]string, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		values = append(values, fmt.Sprintf("(%d, %d, %d)", i, i, i))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	tk.MustExec("create table t1(a int, b int, key idx_a(a))")
+
+	// The statements are not captured until the capture is enabled.
+	sql := "select * from t where b = 10"
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	tk.MustQuery("admin show baselines").Check(testkit.Rows())
+
+	tk.MustExec("set @@tidb_capture_plan_baselines = 1")
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	tk.MustQuery("admin show baselines").Check(testkit.Rows())
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	// The statements with hints, the internal statements and the ones without tables are not captured.
+	for i := 0; i < 2; i++ {
+		tk.MustQuery("select /*+ HASH_JOIN(t, t1) */ * from t, t1 where t.a = t1.a and t.b = 10")
+		tk.MustQuery("select 1")
+		tk.MustQuery("select * from t1 x where x.a = 1")
+	}
+	tk.MustQuery("admin show baselines").CheckAt([]int{0, 3, 4}, [][]interface{}{
+		{"test", "test.t()", bindinfo.SourceCapture},
+		{"test", "test.x(idx_a)", bindinfo.SourceCapture},
+	})
+	tk.MustQuery("select @@last_plan_from_baseline").Check(testkit.Rows("0"))
+
+	// The baseline keeps the table scan after the index is created.
+	tk.MustExec("create index idx_b on t(b)")
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	tk.MustQuery("select @@last_plan_from_baseline").Check(testkit.Rows("1"))
+	c.Assert(planAccessPaths(c, tk, "select * from t where b = 20"), DeepEquals, []bindinfo.TableAccessPath{{DB: "test", Table: "t"}})
+	tk.MustExec("set @@tidb_use_plan_baselines = 0")
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	tk.MustQuery("select @@last_plan_from_baseline").Check(testkit.Rows("0"))
+	c.Assert(planAccessPaths(c, tk, "select * from t where b = 20"), DeepEquals, []bindinfo.TableAccessPath{{DB: "test", Table: "t", Index: "idx_b"}})
+	tk.MustExec("set @@tidb_use_plan_baselines = 1")
+
+	// The index lookup is much faster than the table scan, so it replaces the baseline.
+	tk.MustExec("admin evolve baselines")
+	tk.MustQuery("admin show baselines").CheckAt([]int{0, 3, 4}, [][]interface{}{
+		{"test", "test.t(idx_b)", bindinfo.SourceEvolve},
+		{"test", "test.x(idx_a)", bindinfo.SourceCapture},
+	})
+	c.Assert(planAccessPaths(c, tk, "select * from t where b = 20"), DeepEquals, []bindinfo.TableAccessPath{{DB: "test", Table: "t", Index: "idx_b"}})
+	c.Assert(tk.Se.GetSessionVars().CurrentDB, Equals, "test")
+
+	// The index of the baseline is ignored after it's dropped.
+	tk.MustExec("drop index idx_b on t")
+	tk.MustQuery(sql).Check(testkit.Rows("10 10 10"))
+	tk.MustQuery("select @@last_plan_from_baseline").Check(testkit.Rows("1"))
+	c.Assert(planAccessPaths(c, tk, "select * from t where b = 20"), DeepEquals, []bindinfo.TableAccessPath{{DB: "test", Table: "t"}})
+
+	// The baselines are only used by the statements in the same database.
+	tk.MustExec("create database baseline")
+	tk.MustExec("use baseline")
+	tk.MustQuery("select * from test.t where b = 10").Check(testkit.Rows("10 10 10"))
+	tk.MustQuery("select @@last_plan_from_baseline").Check(testkit.Rows("0"))
+}
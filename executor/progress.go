@@ -0,0 +1,143 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// ShowDDLProgressExec represents an admin show DDL progress executor. It shows the progress of the DDL
+// jobs which reorganize the data of the tables, the processed rows are reported by the backfill workers
+// and saved in the jobs periodically.
+type ShowDDLProgressExec struct {
+	baseExecutor
+
+	is   infoschema.InfoSchema
+	jobs []*model.Job
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *ShowDDLProgressExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	statsHandle := domain.GetDomain(e.ctx).StatsHandle()
+	for _, job := range e.jobs {
+		if job.Type != model.ActionAddIndex && job.Type != model.ActionAddPrimaryKey {
+			continue
+		}
+		dbName, tableName := "", ""
+		if dbInfo, ok := e.is.SchemaByID(job.SchemaID); ok {
+			dbName = dbInfo.Name.O
+		}
+		var totalRows int64
+		if tbl, ok := e.is.TableByID(job.TableID); ok {
+			tableName = tbl.Meta().Name.O
+			if statsTbl := statsHandle.GetTableStats(tbl.Meta()); !statsTbl.Pseudo {
+				totalRows = statsTbl.Count
+			}
+		}
+		req.AppendInt64(0, job.ID)
+		req.AppendString(1, dbName)
+		req.AppendString(2, tableName)
+		req.AppendString(3, job.Type.String())
+		req.AppendString(4, job.SchemaState.String())
+		// The snapshot version is allocated when the reorganization starts.
+		if job.SnapshotVer == 0 {
+			req.AppendNull(5)
+			appendProgress(req, 6, 0, totalRows, 0)
+			continue
+		}
+		startTime := oracle.GetTimeFromTS(job.SnapshotVer)
+		req.AppendString(5, startTime.Format("2006-01-02 15:04:05"))
+		appendProgress(req, 6, job.GetRowCount(), totalRows, time.Since(startTime))
+	}
+	return nil
+}
+
+// ShowAnalyzeProgressExec represents an admin show analyze progress executor. It shows the progress of
+// the analyze tasks running on this server.
+type ShowAnalyzeProgressExec struct {
+	baseExecutor
+
+	done bool
+}
+
+// Next implements the Executor Next interface.
+func (e *ShowAnalyzeProgressExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	for _, job := range domain.GetDomain(e.ctx).StatsHandle().AnalyzeJobs() {
+		req.AppendString(0, job.DBName)
+		req.AppendString(1, job.TableName)
+		req.AppendString(2, job.JobInfo)
+		req.AppendString(3, job.StartTime.Format("2006-01-02 15:04:05"))
+		appendProgress(req, 4, job.ProcessedRows(), job.TotalRows, time.Since(job.StartTime))
+	}
+	return nil
+}
+
+// appendProgress appends the processed rows, the total rows, the progress and the ETA of a job from
+// the colIdx-th column, the unknown values are NULL.
+func appendProgress(req *chunk.Chunk, colIdx int, processed, total int64, elapsed time.Duration) {
+	req.AppendInt64(colIdx, processed)
+	if total <= 0 {
+		req.AppendNull(colIdx + 1)
+	} else {
+		req.AppendInt64(colIdx+1, total)
+	}
+	progress, eta := estimateProgress(processed, total, elapsed)
+	if progress < 0 {
+		req.AppendNull(colIdx + 2)
+	} else {
+		req.AppendFloat64(colIdx+2, progress)
+	}
+	if eta < 0 {
+		req.AppendNull(colIdx + 3)
+	} else {
+		req.AppendString(colIdx+3, eta.Round(time.Second).String())
+	}
+}
+
+// estimateProgress returns the percentage of the processed rows and the remaining time of a job, which
+// assumes the rest rows are processed at the average speed so far. A negative value means it's unknown.
+// The total rows come from the stats which may be out of date, so the progress is capped at 100.
+func estimateProgress(processed, total int64, elapsed time.Duration) (float64, time.Duration) {
+	if total <= 0 {
+		return -1, -1
+	}
+	if processed >= total {
+		return 100, 0
+	}
+	progress := float64(processed) * 100 / float64(total)
+	if processed <= 0 {
+		return progress, -1
+	}
+	return progress, time.Duration(float64(elapsed) * float64(total-processed) / float64(processed))
+}
@@ -0,0 +1,44 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"time"
+
+	. "github.com/pingcap/check"
+)
+
+func (s *pkgTestSuite) TestEstimateProgress(c *C) {
+	tests := []struct {
+		processed int64
+		total     int64
+		elapsed   time.Duration
+		progress  float64
+		eta       time.Duration
+	}{
+		// The total is unknown.
+		{100, 0, time.Minute, -1, -1},
+		// Nothing is processed, so the speed is unknown.
+		{0, 1000, time.Minute, 0, -1},
+		{250, 1000, time.Minute, 25, 3 * time.Minute},
+		{500, 1000, 10 * time.Second, 50, 10 * time.Second},
+		// The stats are out of date.
+		{1200, 1000, time.Minute, 100, 0},
+	}
+	for _, t := range tests {
+		progress, eta := estimateProgress(t.processed, t.total, t.elapsed)
+		c.Assert(progress, Equals, t.progress)
+		c.Assert(eta, Equals, t.eta)
+	}
+}
@@ -63,6 +63,8 @@ type ProjectionExec struct {
 	numWorkers  int64
 	workers     []*projectionWorker
 	childResult *chunk.Chunk
+	// workerTokens is the number of tokens acquired from the concurrency governor.
+	workerTokens int
 
 	wg sync.WaitGroup
 
@@ -207,8 +209,10 @@ func (e *ProjectionExec) parallelExecute(ctx context.Context, chk *chunk.Chunk)
 }
 
 func (e *ProjectionExec) prepare(ctx context.Context) {
+	e.workerTokens = globalConcurrencyGovernor.acquire(int(e.numWorkers))
+	numWorkers := int64(e.workerTokens)
 	e.finishCh = make(chan struct{})
-	e.outputCh = make(chan *projectionOutput, e.numWorkers)
+	e.outputCh = make(chan *projectionOutput, numWorkers)
 
 	// Initialize projectionInputFetcher.
 	e.fetcher = projectionInputFetcher{
@@ -216,13 +220,13 @@ func (e *ProjectionExec) prepare(ctx context.Context) {
 		child:          e.children[0],
 		globalFinishCh: e.finishCh,
 		globalOutputCh: e.outputCh,
-		inputCh:        make(chan *projectionInput, e.numWorkers),
-		outputCh:       make(chan *projectionOutput, e.numWorkers),
+		inputCh:        make(chan *projectionInput, numWorkers),
+		outputCh:       make(chan *projectionOutput, numWorkers),
 	}
 
 	// Initialize projectionWorker.
-	e.workers = make([]*projectionWorker, 0, e.numWorkers)
-	for i := int64(0); i < e.numWorkers; i++ {
+	e.workers = make([]*projectionWorker, 0, numWorkers)
+	for i := int64(0); i < numWorkers; i++ {
 		e.workers = append(e.workers, &projectionWorker{
 			proj:            e,
 			sctx:            e.ctx,
@@ -286,6 +290,8 @@ func (e *ProjectionExec) Close() error {
 			e.drainOutputCh(w.outputCh)
 		}
 	}
+	globalConcurrencyGovernor.release(e.workerTokens)
+	e.workerTokens = 0
 	return e.baseExecutor.Close()
 }
 
@@ -0,0 +1,318 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/ranger"
+	"go.uber.org/zap"
+)
+
+// ScatterTableExec represents an admin scatter table executor. It asks PD to scatter the regions of the
+// table or the index over the stores, which breaks up the write hotspots on a few stores.
+type ScatterTableExec struct {
+	baseExecutor
+
+	tblInfo *model.TableInfo
+	index   string
+	done    bool
+}
+
+// Next implements the Executor Next interface.
+func (e *ScatterTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	store, ok := e.ctx.GetStore().(kv.RegionStore)
+	if !ok {
+		return errors.Errorf("the storage %s doesn't support scattering regions", e.ctx.GetStore().Name())
+	}
+	startKey, endKey := getTableKeyRange(e.tblInfo, e.index)
+	regionIDs, err := store.ScatterRegions(ctx, startKey, endKey)
+	if err != nil {
+		return err
+	}
+
+	finished := len(regionIDs)
+	if e.ctx.GetSessionVars().WaitSplitRegionFinish {
+		finished = waitScatterRegionFinish(ctx, e.ctx.GetSessionVars().GetSplitRegionTimeout(), store, regionIDs)
+	}
+	req.AppendInt64(0, int64(len(regionIDs)))
+	if len(regionIDs) == 0 {
+		req.AppendFloat64(1, 1)
+	} else {
+		req.AppendFloat64(1, float64(finished)/float64(len(regionIDs)))
+	}
+	return nil
+}
+
+// waitScatterRegionFinish waits for the regions to be scattered until the timeout, and returns the number
+// of the scattered regions.
+func waitScatterRegionFinish(ctx context.Context, timeout time.Duration, store kv.RegionStore, regionIDs []uint64) int {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	finished := 0
+	for _, regionID := range regionIDs {
+		if err := store.WaitScatterRegionFinish(ctx, regionID); err != nil {
+			logutil.Logger(ctx).Warn("wait scatter region failed", zap.Uint64("regionID", regionID), zap.Error(err))
+			continue
+		}
+		finished++
+	}
+	return finished
+}
+
+// ShowTableRegionsExec represents an admin show table regions or distributions executor. It shows the
+// regions of the table or the index with their approximate rows and size, or the number of the regions
+// and the leaders on each store.
+type ShowTableRegionsExec struct {
+	baseExecutor
+
+	tp      ast.AdminStmtType
+	tblInfo *model.TableInfo
+	index   string
+	done    bool
+}
+
+// Next implements the Executor Next interface.
+func (e *ShowTableRegionsExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+
+	regions, err := getTableRegions(ctx, e.ctx, e.tblInfo, e.index)
+	if err != nil {
+		return err
+	}
+	if e.tp == ast.AdminShowTableDistributions {
+		e.appendDistributions(req, regions)
+		return nil
+	}
+	appendTableRegions(e.ctx, req, e.tblInfo, e.index, regions)
+	return nil
+}
+
+func (e *ShowTableRegionsExec) appendDistributions(req *chunk.Chunk, regions []*kv.RegionInfo) {
+	regionCount := make(map[uint64]int64)
+	leaderCount := make(map[uint64]int64)
+	for _, region := range regions {
+		for _, id := range region.StoreIDs {
+			regionCount[id]++
+		}
+		leaderCount[region.LeaderStoreID]++
+	}
+	storeIDs := make([]uint64, 0, len(regionCount))
+	for id := range regionCount {
+		storeIDs = append(storeIDs, id)
+	}
+	sort.Slice(storeIDs, func(i, j int) bool { return storeIDs[i] < storeIDs[j] })
+	for _, id := range storeIDs {
+		req.AppendInt64(0, int64(id))
+		req.AppendInt64(1, regionCount[id])
+		req.AppendInt64(2, leaderCount[id])
+	}
+}
+
+// getTableRegions returns the regions of the data of the table, or the index if it's not empty.
+func getTableRegions(ctx context.Context, sctx sessionctx.Context, tblInfo *model.TableInfo, index string) ([]*kv.RegionInfo, error) {
+	store, ok := sctx.GetStore().(kv.RegionStore)
+	if !ok {
+		return nil, errors.Errorf("the storage %s doesn't support showing regions", sctx.GetStore().Name())
+	}
+	startKey, endKey := getTableKeyRange(tblInfo, index)
+	return store.GetRegions(ctx, startKey, endKey)
+}
+
+// appendTableRegions appends a row for each region to req, the approximate rows and size of the table or the
+// index in the region are estimated by the statistics because the storage doesn't report them.
+func appendTableRegions(sctx sessionctx.Context, req *chunk.Chunk, tblInfo *model.TableInfo, index string, regions []*kv.RegionInfo) {
+	estimator := newRegionEstimator(sctx, tblInfo, index)
+	for _, region := range regions {
+		storeIDs := make([]string, 0, len(region.StoreIDs))
+		for _, id := range region.StoreIDs {
+			storeIDs = append(storeIDs, strconv.FormatUint(id, 10))
+		}
+		rows := estimator.estimateRows(region)
+		req.AppendInt64(0, int64(region.ID))
+		req.AppendString(1, formatRegionKey(region.StartKey))
+		req.AppendString(2, formatRegionKey(region.EndKey))
+		req.AppendInt64(3, int64(region.LeaderStoreID))
+		req.AppendString(4, strings.Join(storeIDs, ","))
+		req.AppendInt64(5, int64(math.Round(rows)))
+		req.AppendInt64(6, int64(math.Round(rows*estimator.avgRowSize)))
+	}
+}
+
+// regionEstimator estimates the rows of the table or the index in a region by the histogram of the handle or
+// the index, like the planner estimates the rows of a range.
+type regionEstimator struct {
+	sc      *stmtctx.StatementContext
+	stats   *statistics.Table
+	idxInfo *model.IndexInfo
+	// prefix is the prefix of the record keys or the index keys.
+	prefix kv.Key
+	// handleID is the ID of the column whose histogram is used to estimate the rows of the records. The
+	// pseudo estimation is used if the table has no such histogram.
+	handleID   int64
+	avgRowSize float64
+}
+
+func newRegionEstimator(sctx sessionctx.Context, tblInfo *model.TableInfo, index string) *regionEstimator {
+	e := &regionEstimator{
+		sc:       sctx.GetSessionVars().StmtCtx,
+		prefix:   tablecodec.GenTableRecordPrefix(tblInfo.ID),
+		handleID: model.ExtraHandleID,
+	}
+	if statsHandle := domain.GetDomain(sctx).StatsHandle(); statsHandle != nil {
+		e.stats = statsHandle.GetTableStats(tblInfo)
+	} else {
+		e.stats = statistics.PseudoTable(tblInfo)
+	}
+	handleCol := &expression.Column{UniqueID: model.ExtraHandleID, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	if pk := tblInfo.GetPkColInfo(); tblInfo.PKIsHandle && pk != nil {
+		handleCol = &expression.Column{UniqueID: pk.ID, RetType: &pk.FieldType}
+		// The handles of the unsigned primary keys aren't in the order of the values.
+		if !mysql.HasUnsignedFlag(pk.Flag) {
+			e.handleID = pk.ID
+		}
+	}
+	if index != "" {
+		e.idxInfo = tblInfo.FindIndexByName(strings.ToLower(index))
+		e.prefix = tablecodec.EncodeTableIndexPrefix(tblInfo.ID, e.idxInfo.ID)
+		cols := make([]*expression.Column, 0, len(e.idxInfo.Columns)+1)
+		for _, idxCol := range e.idxInfo.Columns {
+			col := tblInfo.Columns[idxCol.Offset]
+			cols = append(cols, &expression.Column{UniqueID: col.ID, RetType: &col.FieldType})
+		}
+		e.avgRowSize = e.stats.GetIndexAvgRowSize(append(cols, handleCol), e.idxInfo.Unique)
+		return e
+	}
+	cols := make([]*expression.Column, 0, len(tblInfo.Columns)+1)
+	for _, col := range tblInfo.Columns {
+		if col.State == model.StatePublic && !(tblInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag)) {
+			cols = append(cols, &expression.Column{UniqueID: col.ID, RetType: &col.FieldType})
+		}
+	}
+	e.avgRowSize = e.stats.GetTableAvgRowSize(append(cols, handleCol))
+	return e
+}
+
+// estimateRows estimates the rows of the table or the index in the region.
+func (e *regionEstimator) estimateRows(region *kv.RegionInfo) float64 {
+	// Clip the region by the key range of the records or the index, the low and high are the rest of the
+	// boundaries after the prefix, which are nil if the region is unbounded on that side in the key range.
+	prefixEnd := e.prefix.PrefixNext()
+	if (len(region.EndKey) > 0 && region.EndKey.Cmp(e.prefix) <= 0) || region.StartKey.Cmp(prefixEnd) >= 0 {
+		return 0
+	}
+	var low, high []byte
+	if region.StartKey.Cmp(e.prefix) > 0 {
+		low = region.StartKey[len(e.prefix):]
+	}
+	if len(region.EndKey) > 0 && region.EndKey.Cmp(prefixEnd) < 0 {
+		high = region.EndKey[len(e.prefix):]
+	}
+	if e.idxInfo != nil {
+		return e.stats.GetRowCountByEncodedIndexRange(e.idxInfo.ID, low, high)
+	}
+
+	ran := &ranger.Range{
+		LowVal:  []types.Datum{types.NewIntDatum(math.MinInt64)},
+		HighVal: []types.Datum{types.NewIntDatum(math.MaxInt64)},
+	}
+	if low != nil {
+		ran.LowVal[0] = types.NewIntDatum(decodeHandleBound(low))
+	}
+	if high != nil {
+		ran.HighVal[0] = types.NewIntDatum(decodeHandleBound(high))
+		ran.HighExclude = true
+	}
+	rows, err := e.stats.GetRowCountByIntColumnRanges(e.sc, e.handleID, []*ranger.Range{ran})
+	if err != nil {
+		// The estimation is only for display, so the rows are unknown rather than failing the statement.
+		logutil.BgLogger().Warn("estimate the rows of the region failed", zap.Uint64("regionID", region.ID), zap.Error(err))
+		return 0
+	}
+	return rows
+}
+
+// decodeHandleBound decodes the handle from the rest of a boundary key after the record prefix. The boundary
+// may be a part of a row key, it's padded by zeros which is the smallest handle with the same prefix.
+func decodeHandleBound(b []byte) int64 {
+	var buf [8]byte
+	copy(buf[:], b)
+	_, handle, err := codec.DecodeInt(buf[:])
+	terror.Log(err)
+	return handle
+}
+
+// getTableKeyRange returns the key range of the data of the table, or the index if it's not empty.
+func getTableKeyRange(tblInfo *model.TableInfo, index string) (kv.Key, kv.Key) {
+	prefix := tablecodec.GenTablePrefix(tblInfo.ID)
+	if index != "" {
+		prefix = tablecodec.EncodeTableIndexPrefix(tblInfo.ID, tblInfo.FindIndexByName(strings.ToLower(index)).ID)
+	}
+	return prefix, prefix.PrefixNext()
+}
+
+// formatRegionKey formats the boundary key of a region like "t_45_r_100" or "t_45_i_1_", the rest of the
+// key which can't be decoded is formatted in hex.
+func formatRegionKey(key kv.Key) string {
+	if len(key) == 0 {
+		return ""
+	}
+	tableID, indexID, isRecordKey, err := tablecodec.DecodeKeyHead(key)
+	if err != nil {
+		tablePrefixLen := len(tablecodec.GenTablePrefix(0))
+		if len(key) >= tablePrefixLen && tablecodec.DecodeTableID(key[:tablePrefixLen]) != 0 {
+			return fmt.Sprintf("t_%d_%X", tablecodec.DecodeTableID(key[:tablePrefixLen]), []byte(key[tablePrefixLen:]))
+		}
+		return fmt.Sprintf("%X", []byte(key))
+	}
+	if isRecordKey {
+		if handle, err := tablecodec.DecodeRowKey(key); err == nil {
+			return fmt.Sprintf("t_%d_r_%d", tableID, handle)
+		}
+		return fmt.Sprintf("t_%d_r", tableID)
+	}
+	return fmt.Sprintf("t_%d_i_%d_", tableID, indexID)
+}
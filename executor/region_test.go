@@ -0,0 +1,88 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+// testRegionSuite has its own store, so splitting the regions doesn't affect the other suites.
+type testRegionSuite struct {
+	*baseTestSuite
+}
+
+var _ = Suite(&testRegionSuite{&baseTestSuite{}})
+
+func (s *testRegionSuite) TestTableRegions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int primary key, b int, key idx_b(b))")
+	for i := 0; i < 8; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t values (%d, %d)", i, i))
+	}
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tblID := tbl.Meta().ID
+	s.cluster.SplitTable(s.mvccStore, tblID, 4)
+
+	cols := []int{1, 2, 3, 4}
+	tk.MustQuery("admin show table t regions").CheckAt(cols, [][]interface{}{
+		{"", fmt.Sprintf("t_%d_r", tblID), "1", "1"},
+		{fmt.Sprintf("t_%d_r", tblID), fmt.Sprintf("t_%d_r_2", tblID), "1", "1"},
+		{fmt.Sprintf("t_%d_r_2", tblID), fmt.Sprintf("t_%d_r_4", tblID), "1", "1"},
+		{fmt.Sprintf("t_%d_r_4", tblID), fmt.Sprintf("t_%d_r_6", tblID), "1", "1"},
+		{fmt.Sprintf("t_%d_r_6", tblID), fmt.Sprintf("t_%d_5F73", tblID), "1", "1"},
+		{fmt.Sprintf("t_%d_5F73", tblID), "", "1", "1"},
+	})
+	// The index is in the region before the rows.
+	tk.MustQuery("admin show table t index idx_b regions").CheckAt(cols, [][]interface{}{
+		{"", fmt.Sprintf("t_%d_r", tblID), "1", "1"},
+	})
+	// The approximate rows and size are estimated by the statistics.
+	tk.MustExec("analyze table t")
+	cols = []int{1, 2, 5, 6}
+	tk.MustQuery("show table t regions").CheckAt(cols, [][]interface{}{
+		{"", fmt.Sprintf("t_%d_r", tblID), "0", "0"},
+		{fmt.Sprintf("t_%d_r", tblID), fmt.Sprintf("t_%d_r_2", tblID), "2", "44"},
+		{fmt.Sprintf("t_%d_r_2", tblID), fmt.Sprintf("t_%d_r_4", tblID), "2", "44"},
+		{fmt.Sprintf("t_%d_r_4", tblID), fmt.Sprintf("t_%d_r_6", tblID), "2", "44"},
+		{fmt.Sprintf("t_%d_r_6", tblID), fmt.Sprintf("t_%d_5F73", tblID), "2", "44"},
+		{fmt.Sprintf("t_%d_5F73", tblID), "", "0", "0"},
+	})
+	tk.MustQuery("show table t index idx_b regions").CheckAt(cols, [][]interface{}{
+		{"", fmt.Sprintf("t_%d_r", tblID), "8", "304"},
+	})
+	tk.MustQuery("show table test.t regions where approximate_rows > 0").CheckAt([]int{1}, [][]interface{}{
+		{fmt.Sprintf("t_%d_r", tblID)},
+		{fmt.Sprintf("t_%d_r_2", tblID)},
+		{fmt.Sprintf("t_%d_r_4", tblID)},
+		{fmt.Sprintf("t_%d_r_6", tblID)},
+	})
+	tk.MustQuery("admin show table t distributions").Check(testkit.Rows("1 6 6"))
+	tk.MustQuery("admin scatter table t").Check(testkit.Rows("6 1"))
+	tk.MustQuery("admin scatter table t index idx_b").Check(testkit.Rows("1 1"))
+	tk.MustExec("set @@tidb_wait_split_region_finish = 0")
+	tk.MustQuery("admin scatter table t").Check(testkit.Rows("6 1"))
+
+	tk.MustGetErrCode("admin show table t index idx_c regions", mysql.ErrKeyDoesNotExist)
+	tk.MustGetErrCode("admin scatter table t1", mysql.ErrNoSuchTable)
+	tk.MustGetErrCode("show table t index idx_c regions", mysql.ErrKeyDoesNotExist)
+	tk.MustGetErrCode("show table t1 regions", mysql.ErrNoSuchTable)
+}
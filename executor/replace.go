@@ -34,7 +34,7 @@ type ReplaceExec struct {
 // Close implements the Executor Close interface.
 func (e *ReplaceExec) Close() error {
 	if e.SelectExec != nil {
-		return e.SelectExec.Close()
+		return Close(e.SelectExec)
 	}
 	return nil
 }
@@ -42,7 +42,7 @@ func (e *ReplaceExec) Close() error {
 // Open implements the Executor Open interface.
 func (e *ReplaceExec) Open(ctx context.Context) error {
 	if e.SelectExec != nil {
-		return e.SelectExec.Open(ctx)
+		return Open(ctx, e.SelectExec)
 	}
 	e.initEvalBuffer()
 	return nil
@@ -52,9 +52,9 @@ func (e *ReplaceExec) Open(ctx context.Context) error {
 // but if the to-be-removed row equals to the to-be-added row, no remove or add things to do.
 func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle int64, r toBeCheckedRow) (bool, error) {
 	newRow := r.row
-	oldRow, err := getOldRow(ctx, e.ctx, txn, r.t, handle)
+	oldRow, err := getOldRow(ctx, e.ctx, txn, r.t, handle, e.GenExprs)
 	if err != nil {
-		logutil.BgLogger().Error("get old row failed when replace",
+		logutil.Logger(ctx).Error("get old row failed when replace",
 			zap.Int64("handle", handle),
 			zap.String("toBeInsertedRow", types.DatumsToStrNoErr(r.row)))
 		if kv.IsErrNotFound(err) {
@@ -0,0 +1,342 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+)
+
+const (
+	// resultCacheCapacity is the max number of the results kept by the result cache.
+	resultCacheCapacity = 128
+	// resultCacheMaxMemory is the max memory usage of a cached result, the larger results are not cached.
+	resultCacheMaxMemory = 1 << 20
+)
+
+// globalResultCache is shared by all the sessions in the server.
+var globalResultCache = newResultCache(resultCacheCapacity)
+
+// resultCache caches the results of the read-only SELECT statements. A result is keyed by
+// the statement text, which determines the digest and the parameters of the statement, the
+// schema version, the statistics versions of the read tables and the bucket of the read
+// timestamp. Every table has a version which is increased when the table is modified by a
+// transaction committed in this server, a cached result is dropped once the version of any
+// table read by it changes. The modifications committed by other servers are not noticed,
+// they become visible when the read timestamps move to the next bucket, so the results may
+// be stale for at most the width of a bucket.
+type resultCache struct {
+	mu            sync.Mutex
+	results       *kvcache.SimpleLRUCache
+	tableVersions map[int64]uint64
+}
+
+type resultCacheKey []byte
+
+// Hash implements kvcache.Key interface.
+func (key resultCacheKey) Hash() []byte {
+	return key
+}
+
+type resultCacheValue struct {
+	fieldTypes []*types.FieldType
+	fields     []*ast.ResultField
+	chunks     []*chunk.Chunk
+	// tableVersions are the versions of the read tables when the statement started.
+	tableVersions map[int64]uint64
+}
+
+func newResultCache(capacity uint) *resultCache {
+	return &resultCache{
+		results:       kvcache.NewSimpleLRUCache(capacity),
+		tableVersions: make(map[int64]uint64),
+	}
+}
+
+// get returns the cached result of the key, the result is dropped if any of its tables is modified.
+func (c *resultCache) get(key resultCacheKey) (*resultCacheValue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.results.Get(key)
+	if !ok {
+		return nil, false
+	}
+	value := v.(*resultCacheValue)
+	for tid, ver := range value.tableVersions {
+		if c.tableVersions[tid] != ver {
+			c.results.Delete(key)
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+func (c *resultCache) put(key resultCacheKey, value *resultCacheValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for tid, ver := range value.tableVersions {
+		if c.tableVersions[tid] != ver {
+			// The table is modified while the statement is running.
+			return
+		}
+	}
+	c.results.Put(key, value)
+}
+
+// versionsOf returns the current versions of the tables.
+func (c *resultCache) versionsOf(tableIDs []int64) map[int64]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	versions := make(map[int64]uint64, len(tableIDs))
+	for _, tid := range tableIDs {
+		versions[tid] = c.tableVersions[tid]
+	}
+	return versions
+}
+
+// invalidate increases the versions of the tables, so the results reading them are dropped.
+func (c *resultCache) invalidate(tableIDs []int64) {
+	if len(tableIDs) == 0 {
+		return
+	}
+	c.mu.Lock()
+	for _, tid := range tableIDs {
+		c.tableVersions[tid]++
+	}
+	c.mu.Unlock()
+}
+
+// flush drops all the cached results.
+func (c *resultCache) flush() {
+	c.mu.Lock()
+	c.results.DeleteAll()
+	c.mu.Unlock()
+}
+
+// InvalidateResultCache drops the cached results which read the tables modified by the
+// transaction. It should be called both before and after the transaction is committed:
+// the first call prevents the statements from using the stale results during the commit,
+// and the second one drops the results read by the statements started during the commit.
+func InvalidateResultCache(tableIDs []int64) {
+	globalResultCache.invalidate(tableIDs)
+}
+
+// ModifiedTableIDs returns the IDs of the tables which are modified in the MemBuffer.
+func ModifiedTableIDs(buf kv.MemBuffer) ([]int64, error) {
+	var tableIDs []int64
+	seekKey := kv.Key(tablecodec.TablePrefix())
+	for {
+		iter, err := buf.Iter(seekKey, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !iter.Valid() || !iter.Key().HasPrefix(tablecodec.TablePrefix()) {
+			iter.Close()
+			return tableIDs, nil
+		}
+		tid := tablecodec.DecodeTableID(iter.Key())
+		iter.Close()
+		tableIDs = append(tableIDs, tid)
+		// Skip the other keys of the table.
+		seekKey = tablecodec.EncodeTablePrefix(tid + 1)
+	}
+}
+
+// resultCacheKeyOf returns the cache key of the statement, ok is false if the result of the
+// statement can't be cached.
+func (a *ExecStmt) resultCacheKeyOf() (key resultCacheKey, tableIDs []int64, ok bool) {
+	vars := a.Ctx.GetSessionVars()
+	// The statements built without text, such as the ones built by BATCH DML, can't be keyed.
+	if !vars.EnableResultCache || vars.InRestrictedSQL || a.Text == "" {
+		return nil, nil, false
+	}
+	sel, isSel := a.StmtNode.(*ast.SelectStmt)
+	if !isSel || (sel.SelectStmtOpts != nil && !sel.SelectStmtOpts.SQLCache) {
+		return nil, nil, false
+	}
+	// The statements in a dirty transaction read their own modifications.
+	txn, err := a.Ctx.Txn(true)
+	if err != nil || !txn.IsReadOnly() {
+		return nil, nil, false
+	}
+	checker := &resultCacheChecker{cacheable: true}
+	sel.Accept(checker)
+	if !checker.cacheable || len(checker.tables) == 0 {
+		return nil, nil, false
+	}
+
+	key = codec.EncodeCompactBytes(nil, hack.Slice(a.Ctx.GetStore().UUID()))
+	key = codec.EncodeCompactBytes(key, hack.Slice(a.Text))
+	key = codec.EncodeCompactBytes(key, hack.Slice(vars.CurrentDB))
+	key = codec.EncodeUint(key, uint64(vars.SQLMode))
+	key = codec.EncodeInt(key, a.InfoSchema.SchemaMetaVersion())
+	statsHandle := domain.GetDomain(a.Ctx).StatsHandle()
+	tableIDs = make([]int64, 0, len(checker.tables))
+	for _, tbl := range checker.tables {
+		tableIDs = append(tableIDs, tbl.ID)
+	}
+	sort.Slice(tableIDs, func(i, j int) bool { return tableIDs[i] < tableIDs[j] })
+	for _, tid := range tableIDs {
+		key = codec.EncodeInt(key, tid)
+		if statsHandle != nil {
+			key = codec.EncodeUint(key, statsHandle.GetTableStats(checker.tables[tid]).Version)
+		}
+	}
+	bucket := oracle.ExtractPhysical(txn.StartTS()) / vars.ResultCacheTSBucket
+	key = codec.EncodeInt(key, bucket)
+	return key, tableIDs, true
+}
+
+// resultCacheChecker collects the tables read by the statement and checks whether its
+// result only depends on the data of these tables.
+type resultCacheChecker struct {
+	tables    map[int64]*model.TableInfo
+	cacheable bool
+}
+
+// Enter implements ast.Visitor interface.
+func (c *resultCacheChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch x := in.(type) {
+	case *ast.TableName:
+		// The memory tables are generated on the fly.
+		if x.TableInfo == nil || util.IsMemOrSysDB(x.Schema.L) {
+			c.cacheable = false
+			break
+		}
+		if c.tables == nil {
+			c.tables = make(map[int64]*model.TableInfo)
+		}
+		c.tables[x.TableInfo.ID] = x.TableInfo
+	case *ast.VariableExpr:
+		c.cacheable = false
+	case *ast.FuncCallExpr:
+		if _, ok := expression.DeterministicFunctions[x.FnName.L]; !ok {
+			c.cacheable = false
+		}
+	}
+	return in, !c.cacheable
+}
+
+// Leave implements ast.Visitor interface.
+func (c *resultCacheChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, c.cacheable
+}
+
+// cachedRecordSet returns the rows of a cached result.
+type cachedRecordSet struct {
+	stmt   *ExecStmt
+	result *resultCacheValue
+	cursor int
+}
+
+// Fields implements the sqlexec.RecordSet interface.
+func (rs *cachedRecordSet) Fields() []*ast.ResultField {
+	return rs.result.fields
+}
+
+// Next implements the sqlexec.RecordSet interface.
+func (rs *cachedRecordSet) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	sessVars := rs.stmt.Ctx.GetSessionVars()
+	if rs.cursor >= len(rs.result.chunks) {
+		sessVars.LastFoundRows = sessVars.StmtCtx.FoundRows()
+		return nil
+	}
+	chk := rs.result.chunks[rs.cursor]
+	rs.cursor++
+	req.Append(chk, 0, chk.NumRows())
+	sessVars.StmtCtx.AddFoundRows(uint64(chk.NumRows()))
+	return nil
+}
+
+// NewChunk implements the sqlexec.RecordSet interface.
+func (rs *cachedRecordSet) NewChunk() *chunk.Chunk {
+	return chunk.New(rs.result.fieldTypes, rs.stmt.Ctx.GetSessionVars().MaxChunkSize, rs.stmt.Ctx.GetSessionVars().MaxChunkSize)
+}
+
+// Close implements the sqlexec.RecordSet interface.
+func (rs *cachedRecordSet) Close() error {
+	rs.stmt.Ctx.GetSessionVars().PrevStmt = FormatSQL(rs.stmt.OriginText())
+	return nil
+}
+
+// resultCachingRecordSet copies the rows returned by the recordSet, and puts them into the
+// result cache when all the rows are read.
+type resultCachingRecordSet struct {
+	*recordSet
+	key    resultCacheKey
+	result *resultCacheValue
+	memory int64
+	// drained indicates whether all the rows are read.
+	drained bool
+}
+
+// Next implements the sqlexec.RecordSet interface.
+func (rs *resultCachingRecordSet) Next(ctx context.Context, req *chunk.Chunk) error {
+	if err := rs.recordSet.Next(ctx, req); err != nil {
+		rs.result = nil
+		return err
+	}
+	if rs.result == nil {
+		return nil
+	}
+	if req.NumRows() == 0 {
+		rs.drained = true
+		return nil
+	}
+	rs.memory += req.MemoryUsage()
+	if rs.memory > resultCacheMaxMemory {
+		rs.result = nil
+		return nil
+	}
+	rs.result.chunks = append(rs.result.chunks, req.CopyConstruct())
+	return nil
+}
+
+// Close implements the sqlexec.RecordSet interface.
+func (rs *resultCachingRecordSet) Close() error {
+	err := rs.recordSet.Close()
+	// The warnings of the statement can't be replayed by the cached result.
+	if err == nil && rs.result != nil && rs.drained && rs.stmt.Ctx.GetSessionVars().StmtCtx.WarningCount() == 0 {
+		rs.result.fields = rs.recordSet.Fields()
+		globalResultCache.put(rs.key, rs.result)
+	}
+	return err
+}
+
+func newResultCachingRecordSet(rs *recordSet, key resultCacheKey, tableVersions map[int64]uint64) *resultCachingRecordSet {
+	return &resultCachingRecordSet{
+		recordSet: rs,
+		key:       key,
+		result: &resultCacheValue{
+			fieldTypes:    retTypes(rs.executor),
+			tableVersions: tableVersions,
+		},
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *testSuite) TestResultCache(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t, t1")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("create table t1(a int)")
+	tk.MustExec("insert into t values(1, 10), (2, 20)")
+	tk.MustExec("insert into t1 values(1)")
+	tk.MustExec("set @@tidb_enable_result_cache = 1")
+	// A large bucket keeps the reads of the test in the same bucket.
+	tk.MustExec("set @@tidb_result_cache_ts_bucket = 3600000")
+
+	checkFromCache := func(sql string, fromCache string, rows ...string) {
+		tk.MustQuery(sql).Check(testkit.Rows(rows...))
+		tk.MustQuery("select @@last_result_from_cache").Check(testkit.Rows(fromCache))
+	}
+	checkFromCache("select * from t order by a", "0", "1 10", "2 20")
+	checkFromCache("select * from t order by a", "1", "1 10", "2 20")
+	// The literals are a part of the key.
+	checkFromCache("select b from t where a = 1", "0", "10")
+	checkFromCache("select b from t where a = 2", "0", "20")
+	checkFromCache("select b from t where a = 1", "1", "10")
+	checkFromCache("select t.b from t join t1 on t.a = t1.a", "0", "10")
+	checkFromCache("select t.b from t join t1 on t.a = t1.a", "1", "10")
+
+	// The modifications of a table drop the results reading it.
+	tk.MustExec("insert into t values(3, 30)")
+	checkFromCache("select * from t order by a", "0", "1 10", "2 20", "3 30")
+	checkFromCache("select t.b from t join t1 on t.a = t1.a", "0", "10")
+	checkFromCache("select * from t order by a", "1", "1 10", "2 20", "3 30")
+	tk.MustExec("insert into t1 values(3)")
+	checkFromCache("select * from t order by a", "1", "1 10", "2 20", "3 30")
+	checkFromCache("select t.b from t join t1 on t.a = t1.a order by t.b", "0", "10", "30")
+	tk.MustExec("delete from t where a = 1")
+	checkFromCache("select * from t order by a", "0", "2 20", "3 30")
+
+	// The statements in a dirty transaction read their own modifications, and the results
+	// are dropped when the transaction is committed.
+	checkFromCache("select * from t order by a", "1", "2 20", "3 30")
+	tk.MustExec("begin")
+	checkFromCache("select * from t order by a", "1", "2 20", "3 30")
+	tk.MustExec("insert into t values(4, 40)")
+	checkFromCache("select * from t order by a", "0", "2 20", "3 30", "4 40")
+	checkFromCache("select * from t order by a", "0", "2 20", "3 30", "4 40")
+	tk.MustExec("commit")
+	checkFromCache("select * from t order by a", "0", "2 20", "3 30", "4 40")
+	checkFromCache("select * from t order by a", "1", "2 20", "3 30", "4 40")
+
+	// The other sessions share the cached results.
+	tk2 := testkit.NewTestKitWithInit(c, s.store)
+	tk2.MustExec("set @@tidb_enable_result_cache = 1")
+	tk2.MustExec("set @@tidb_result_cache_ts_bucket = 3600000")
+	tk2.MustQuery("select * from t order by a").Check(testkit.Rows("2 20", "3 30", "4 40"))
+	tk2.MustQuery("select @@last_result_from_cache").Check(testkit.Rows("1"))
+	tk2.MustExec("delete from t where a = 4")
+	checkFromCache("select * from t order by a", "0", "2 20", "3 30")
+
+	// The schema changes drop the results.
+	checkFromCache("select * from t order by a", "1", "2 20", "3 30")
+	tk.MustExec("alter table t add column c int default 1")
+	checkFromCache("select a from t order by a", "0", "2", "3")
+	checkFromCache("select * from t order by a", "0", "2 20 1", "3 30 1")
+
+	// The statements which are not cached.
+	checkFromCache("select sql_no_cache a from t order by a", "0", "2", "3")
+	checkFromCache("select sql_no_cache a from t order by a", "0", "2", "3")
+	tk.MustExec("set @a = 'x'")
+	checkFromCache("select a, @a from t order by a", "0", "2 x", "3 x")
+	checkFromCache("select a, @a from t order by a", "0", "2 x", "3 x")
+	// Only the deterministic functions are cached, so are the statements calling them.
+	checkFromCache("select ifnull(b, 0) from t order by a", "0", "20", "30")
+	checkFromCache("select ifnull(b, 0) from t order by a", "1", "20", "30")
+	tk.MustExec("set @@block_encryption_mode = 'aes-128-ecb'")
+	checkFromCache("select aes_encrypt('x', 'k') is not null from t order by a", "0", "1", "1")
+	checkFromCache("select aes_encrypt('x', 'k') is not null from t order by a", "0", "1", "1")
+	checkFromCache("select a from t where uuid() is not null order by a", "0", "2", "3")
+	checkFromCache("select a from t where uuid() is not null order by a", "0", "2", "3")
+	checkFromCache("select a from t where unix_timestamp() > 0 order by a", "0", "2", "3")
+	checkFromCache("select a from t where unix_timestamp() > 0 order by a", "0", "2", "3")
+	checkFromCache("select a from t where connection_id() >= 0 order by a", "0", "2", "3")
+	checkFromCache("select a from t where connection_id() >= 0 order by a", "0", "2", "3")
+	checkFromCache("select a from t where uuid_short() > 0 order by a", "0", "2", "3")
+	checkFromCache("select a from t where uuid_short() > 0 order by a", "0", "2", "3")
+	for _, fn := range []string{"user()", "session_user()", "system_user()", "current_user", "current_user()", "current_role()"} {
+		sql := fmt.Sprintf("select a from t where ifnull(%s, '') is not null order by a", fn)
+		checkFromCache(sql, "0", "2", "3")
+		checkFromCache(sql, "0", "2", "3")
+	}
+	checkFromCache("select 1", "0", "1")
+	checkFromCache("select 1", "0", "1")
+	checkFromCache("select count(*) from information_schema.tables where table_name = 't1'", "0", "1")
+	checkFromCache("select count(*) from information_schema.tables where table_name = 't1'", "0", "1")
+	tk.MustExec("set @@tidb_enable_result_cache = 0")
+	checkFromCache("select * from t order by a", "0", "2 20 1", "3 30 1")
+	checkFromCache("select * from t order by a", "0", "2 20 1", "3 30 1")
+}
+
+func (s *testSuite) TestModifiedTableIDs(c *C) {
+	buf := kv.NewMemDbBuffer(kv.DefaultTxnMembufCap)
+	tableIDs, err := executor.ModifiedTableIDs(buf)
+	c.Assert(err, IsNil)
+	c.Assert(tableIDs, HasLen, 0)
+
+	c.Assert(buf.Set(kv.Key("m_meta"), []byte("1")), IsNil)
+	for _, tid := range []int64{3, 100, 5} {
+		c.Assert(buf.Set(tablecodec.EncodeRowKeyWithHandle(tid, 1), []byte("1")), IsNil)
+		c.Assert(buf.Set(tablecodec.EncodeRowKeyWithHandle(tid, 2), []byte("1")), IsNil)
+		c.Assert(buf.Set(tablecodec.EncodeTableIndexPrefix(tid, 1), []byte("1")), IsNil)
+	}
+	tableIDs, err = executor.ModifiedTableIDs(buf)
+	c.Assert(err, IsNil)
+	c.Assert(tableIDs, DeepEquals, []int64{3, 5, 100})
+}
@@ -15,7 +15,6 @@ package executor
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -53,15 +52,8 @@ func (e *SetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
 				return err
 			}
 
-			if value.IsNull() {
-				delete(sessionVars.Users, name)
-			} else {
-				svalue, err1 := value.ToString()
-				if err1 != nil {
-					return err1
-				}
-				sessionVars.Users[name] = fmt.Sprintf("%v", svalue)
-			}
+			// The variable keeps the type of the value, a NULL value removes it.
+			sessionVars.SetUserVar(name, *value.Copy(), v.Expr.GetType())
 			continue
 		}
 
@@ -39,3 +39,37 @@ func (s *testSuite5) TestSelectGlobalVar(c *C) {
 	err = tk.ExecToErr("select @@global.invalid")
 	c.Assert(terror.ErrorEqual(err, variable.ErrUnknownSystemVar), IsTrue, Commentf("err %v", err))
 }
+
+func (s *testSuite5) TestUserVar(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'x'), (2, 'y'), (3, null)")
+
+	tk.MustExec("set @x := 1")
+	tk.MustQuery("select @x + a from t").Check(testkit.Rows("2", "3", "4"))
+	tk.MustQuery("select a from t where a > @x").Check(testkit.Rows("2", "3"))
+	tk.MustQuery("select @undefined").Check(testkit.Rows("<nil>"))
+
+	// The assignments in a statement are seen by the following rows.
+	tk.MustExec("set @c = 0")
+	tk.MustQuery("select @c := @c + 1, a from t").Check(testkit.Rows("1 1", "2 2", "3 3"))
+	tk.MustQuery("select @c").Check(testkit.Rows("3"))
+	tk.MustExec("set @c = 0")
+	tk.MustQuery("select a from t where (@c := @c + 1) <= 2").Check(testkit.Rows("1", "2"))
+
+	// The variable takes the type of the assigned value.
+	tk.MustQuery("select @y := a from t").Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("select @y * 2").Check(testkit.Rows("6"))
+	tk.MustQuery("select @s := b from t where a < 3").Check(testkit.Rows("x", "y"))
+	tk.MustQuery("select a from t where b < @s").Check(testkit.Rows("1"))
+	tk.MustExec("set @u = 18446744073709551615")
+	tk.MustQuery("select @u").Check(testkit.Rows("18446744073709551615"))
+
+	// A NULL value clears the variable.
+	tk.MustQuery("select @n := b from t").Check(testkit.Rows("x", "y", "<nil>"))
+	tk.MustQuery("select @n").Check(testkit.Rows("<nil>"))
+	tk.MustExec("set @x = null")
+	tk.MustQuery("select @x").Check(testkit.Rows("<nil>"))
+}
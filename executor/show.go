@@ -107,6 +107,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowWarnings(false)
 	case ast.ShowErrors:
 		return e.fetchShowWarnings(true)
+	case ast.ShowRegions:
+		return e.fetchShowTableRegions(ctx)
 	}
 	return nil
 }
@@ -243,7 +245,14 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	fmt.Fprintf(buf, "CREATE TABLE %s (\n", escape(tableInfo.Name, sqlMode))
 	var pkCol *model.ColumnInfo
 	var hasAutoIncID bool
-	for i, col := range tableInfo.Cols() {
+	// The hidden columns of the expression indexes are shown as the expressions of the indexes.
+	visibleCols := make([]*model.ColumnInfo, 0, len(tableInfo.Columns))
+	for _, col := range tableInfo.Cols() {
+		if !col.Hidden {
+			visibleCols = append(visibleCols, col)
+		}
+	}
+	for i, col := range visibleCols {
 		fmt.Fprintf(buf, "  %s %s", escape(col.Name, sqlMode), col.GetTypeDesc())
 		if col.Charset != "binary" {
 			if col.Charset != tblCharset {
@@ -268,11 +277,13 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 			// default values are not shown for generated columns in MySQL
 			if !mysql.HasNoDefaultValueFlag(col.Flag) {
 				defaultValue := col.GetDefaultValue()
-				switch defaultValue {
-				case nil:
+				switch {
+				case defaultValue == nil:
 					if !mysql.HasNotNullFlag(col.Flag) {
 						buf.WriteString(" DEFAULT NULL")
 					}
+				case col.DefaultIsExpr:
+					fmt.Fprintf(buf, " DEFAULT (%v)", defaultValue)
 				default:
 					defaultValStr := fmt.Sprintf("%v", defaultValue)
 
@@ -287,7 +298,7 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		if len(col.Comment) > 0 {
 			fmt.Fprintf(buf, " COMMENT '%s'", format.OutputFormat(col.Comment))
 		}
-		if i != len(tableInfo.Cols())-1 {
+		if i != len(visibleCols)-1 {
 			buf.WriteString(",\n")
 		}
 		if tableInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag) {
@@ -322,13 +333,27 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 
 		cols := make([]string, 0, len(idxInfo.Columns))
 		for _, c := range idxInfo.Columns {
+			if col := tableInfo.Columns[c.Offset]; col.Hidden {
+				colInfo := fmt.Sprintf("(%s)", col.GeneratedExprString)
+				if c.Desc {
+					colInfo += " DESC"
+				}
+				cols = append(cols, colInfo)
+				continue
+			}
 			colInfo := escape(c.Name, sqlMode)
 			if c.Length != types.UnspecifiedLength {
 				colInfo = fmt.Sprintf("%s(%s)", colInfo, strconv.Itoa(c.Length))
 			}
+			if c.Desc {
+				colInfo += " DESC"
+			}
 			cols = append(cols, colInfo)
 		}
 		fmt.Fprintf(buf, "(%s)", strings.Join(cols, ","))
+		if idxInfo.Invisible {
+			buf.WriteString(" /*!80000 INVISIBLE */")
+		}
 		if i != len(publicIndices)-1 {
 			buf.WriteString(",\n")
 		}
@@ -374,6 +399,20 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 	if len(tableInfo.Comment) > 0 {
 		fmt.Fprintf(buf, " COMMENT='%s'", format.OutputFormat(tableInfo.Comment))
 	}
+
+	if ttlInfo := tableInfo.TTLInfo; ttlInfo != nil {
+		fmt.Fprintf(buf, " /*T!40200 TTL=%s + INTERVAL %d %s */", escape(ttlInfo.ColumnName, sqlMode), ttlInfo.IntervalValue, ttlInfo.IntervalUnit)
+		if ttlInfo.Enable {
+			buf.WriteString(" /*T!40200 TTL_ENABLE='ON' */")
+		} else {
+			buf.WriteString(" /*T!40200 TTL_ENABLE='OFF' */")
+		}
+	}
+
+	if tableInfo.TableCacheStatusType == model.TableCacheStatusEnable {
+		// This is not a table option, the table needs to be altered to be cached again.
+		buf.WriteString(" /* CACHED ON */")
+	}
 	return nil
 }
 
@@ -442,6 +481,15 @@ func (e *ShowExec) fetchShowWarnings(errOnly bool) error {
 	return nil
 }
 
+func (e *ShowExec) fetchShowTableRegions(ctx context.Context) error {
+	regions, err := getTableRegions(ctx, e.ctx, e.Table.TableInfo, e.IndexName.O)
+	if err != nil {
+		return err
+	}
+	appendTableRegions(e.ctx, e.result, e.Table.TableInfo, e.IndexName.O, regions)
+	return nil
+}
+
 func (e *ShowExec) getTable() (table.Table, error) {
 	if e.Table == nil {
 		return nil, errors.New("table not found")
@@ -16,12 +16,17 @@ package executor
 import (
 	"context"
 
+	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -29,7 +34,7 @@ import (
 
 // SimpleExec represents simple statement executor.
 // For statements do simple execution.
-// includes `UseStmt`,`BeginStmt`, `CommitStmt` and `RollbackStmt`.
+// includes `UseStmt`,`BeginStmt`, `CommitStmt`, `RollbackStmt` and the `AdminStmt` reloading or flushing caches.
 type SimpleExec struct {
 	baseExecutor
 
@@ -53,6 +58,8 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		e.executeCommit(x)
 	case *ast.RollbackStmt:
 		err = e.executeRollback(x)
+	case *ast.AdminStmt:
+		err = e.executeAdmin(ctx, x)
 	}
 	e.done = true
 	return err
@@ -112,3 +119,112 @@ func (e *SimpleExec) executeRollback(s *ast.RollbackStmt) error {
 	}
 	return nil
 }
+
+func (e *SimpleExec) executeAdmin(ctx context.Context, s *ast.AdminStmt) error {
+	switch s.Tp {
+	case ast.AdminReloadStats:
+		return e.executeReloadStats()
+	case ast.AdminReloadPrivileges:
+		return e.executeReloadPrivileges()
+	case ast.AdminFlushPlanCache:
+		// The cache is recreated when it's used next time.
+		e.ctx.GetSessionVars().NonPreparedPlanCache = nil
+	case ast.AdminFlushResultCache:
+		globalResultCache.flush()
+	case ast.AdminEvolveBaselines:
+		_, err := EvolvePlanBaselines(ctx, e.ctx)
+		return err
+	case ast.AdminEnableRowChecksum, ast.AdminDisableRowChecksum:
+		return e.executeToggleRowChecksum(s.Tp == ast.AdminEnableRowChecksum)
+	case ast.AdminRebuildRowChecksum:
+		return e.executeRebuildRowChecksum(s)
+	}
+	return nil
+}
+
+// executeToggleRowChecksum sets both the global and the session value of tidb_enable_row_checksum.
+func (e *SimpleExec) executeToggleRowChecksum(enable bool) error {
+	sessionVars := e.ctx.GetSessionVars()
+	val := variable.BoolToIntStr(enable)
+	if err := sessionVars.GlobalVarsAccessor.SetGlobalSysVar(variable.TiDBEnableRowChecksum, val); err != nil {
+		return err
+	}
+	return variable.SetSessionSystemVar(sessionVars, variable.TiDBEnableRowChecksum, types.NewStringDatum(val))
+}
+
+// executeRebuildRowChecksum re-encodes all the rows of the tables with the row encoder of the session,
+// so the checksums are added to or removed from the rows according to tidb_enable_row_checksum. The
+// rows aren't verified before they're rebuilt.
+func (e *SimpleExec) executeRebuildRowChecksum(s *ast.AdminStmt) error {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	sessVars := e.ctx.GetSessionVars()
+	for _, tn := range s.Tables {
+		tbl, ok := e.is.TableByID(tn.TableInfo.ID)
+		if !ok {
+			return infoschema.ErrTableNotExists.GenWithStackByArgs(tn.Schema.O, tn.Name.O)
+		}
+		colTps := make(map[int64]*types.FieldType, len(tbl.Cols()))
+		for _, col := range tbl.Cols() {
+			colTps[col.ID] = &col.FieldType
+		}
+		// The rows are written after the iteration, so the iterator doesn't see the rows written.
+		var keys []kv.Key
+		var values [][]byte
+		prefix := tbl.RecordPrefix()
+		it, err := txn.Iter(prefix, prefix.PrefixNext())
+		if err != nil {
+			return err
+		}
+		for it.Valid() && it.Key().HasPrefix(prefix) {
+			rowMap, err := tablecodec.DecodeRow(it.Value(), colTps, sessVars.Location())
+			if err != nil {
+				it.Close()
+				return err
+			}
+			colIDs := make([]int64, 0, len(rowMap))
+			row := make([]types.Datum, 0, len(rowMap))
+			for id, d := range rowMap {
+				colIDs = append(colIDs, id)
+				row = append(row, d)
+			}
+			value, err := tablecodec.EncodeRow(sessVars.StmtCtx, row, colIDs, nil, nil, &sessVars.RowEncoder)
+			if err != nil {
+				it.Close()
+				return err
+			}
+			keys = append(keys, it.Key().Clone())
+			values = append(values, value)
+			if err = it.Next(); err != nil {
+				it.Close()
+				return err
+			}
+		}
+		it.Close()
+		for i, key := range keys {
+			if err = txn.Set(key, values[i]); err != nil {
+				return err
+			}
+		}
+		sessVars.StmtCtx.AddAffectedRows(uint64(len(keys)))
+	}
+	return nil
+}
+
+func (e *SimpleExec) executeReloadStats() error {
+	dom := domain.GetDomain(e.ctx)
+	h := dom.StatsHandle()
+	if h == nil {
+		return nil
+	}
+	return h.Reload(dom.InfoSchema())
+}
+
+func (e *SimpleExec) executeReloadPrivileges() error {
+	if r, ok := privilege.GetPrivilegeManager(e.ctx).(privilege.Reloader); ok {
+		return r.Reload()
+	}
+	return nil
+}
@@ -14,11 +14,19 @@
 package executor_test
 
 import (
+	"context"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/util/rowcodec"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -82,3 +90,205 @@ func (s *testSuite3) TestUseDB(c *C) {
 	_, err = tk.Exec("USE ``")
 	c.Assert(terror.ErrorEqual(core.ErrNoDB, err), IsTrue, Commentf("err %v", err))
 }
+
+type reloadPrivManager struct {
+	super    bool
+	reloaded int
+}
+
+func (m *reloadPrivManager) RequestVerification(db, table, column string, priv mysql.PrivilegeType) bool {
+	return priv != mysql.SuperPriv || m.super
+}
+
+func (m *reloadPrivManager) Reload() error {
+	m.reloaded++
+	return nil
+}
+
+func (s *testSuite3) TestAdminReload(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("admin reload stats")
+	// No privilege manager is bound, there is nothing to reload.
+	tk.MustExec("admin reload privileges")
+
+	pm := &reloadPrivManager{super: true}
+	privilege.BindPrivilegeManager(tk.Se, pm)
+	defer privilege.BindPrivilegeManager(tk.Se, nil)
+	tk.MustExec("admin reload privileges")
+	c.Assert(pm.reloaded, Equals, 1)
+	tk.MustExec("admin reload stats")
+
+	pm.super = false
+	_, err := tk.Exec("admin reload privileges")
+	c.Assert(terror.ErrorEqual(err, core.ErrSpecificAccessDenied), IsTrue, Commentf("err %v", err))
+	c.Assert(pm.reloaded, Equals, 1)
+	_, err = tk.Exec("admin reload stats")
+	c.Assert(terror.ErrorEqual(err, core.ErrSpecificAccessDenied), IsTrue, Commentf("err %v", err))
+}
+
+func (s *testSuite3) TestRowChecksum(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b varchar(10), c int)")
+	tk.MustExec("insert into t values (1, 'a', 1)")
+	tbl, err := domain.GetDomain(tk.Se).InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableID := tbl.Meta().ID
+	tk.MustExec("admin enable row checksum")
+	tk.MustQuery("select @@global.tidb_enable_row_checksum, @@tidb_enable_row_checksum").Check(testkit.Rows("1 1"))
+	tk.MustExec("insert into t values (2, 'b', 2)")
+	tk.MustExec("set @@tidb_verify_row_checksum = 1")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 a 1", "2 b 2"))
+
+	hasChecksum := func(h int64) bool {
+		txn, err := s.store.Begin()
+		c.Assert(err, IsNil)
+		defer txn.Rollback()
+		value, err := txn.Get(context.Background(), tablecodec.EncodeRowKeyWithHandle(tableID, h))
+		c.Assert(err, IsNil)
+		return rowcodec.HasChecksum(value)
+	}
+	c.Assert(hasChecksum(1), IsFalse)
+	c.Assert(hasChecksum(2), IsTrue)
+	tk.MustExec("admin rebuild row checksum t")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(2))
+	c.Assert(hasChecksum(1), IsTrue)
+	c.Assert(hasChecksum(2), IsTrue)
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 a 1", "2 b 2"))
+	tk.MustExec("delete from t where a = 1")
+	tk.MustExec("insert into t values (1, 'a', 3)")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 a 3"))
+
+	// Corrupt the row 2 behind the codec.
+	txn, err := s.store.Begin()
+	c.Assert(err, IsNil)
+	key := tablecodec.EncodeRowKeyWithHandle(tableID, 2)
+	value, err := txn.Get(context.Background(), key)
+	c.Assert(err, IsNil)
+	value = append([]byte(nil), value...)
+	value[len(value)-5] ^= 0xff
+	c.Assert(txn.Set(key, value), IsNil)
+	c.Assert(txn.Commit(context.Background()), IsNil)
+	err = tk.QueryToErr("select * from t")
+	c.Assert(err, ErrorMatches, ".*row checksum mismatch.*")
+	_, err = tk.Exec("delete from t where a = 2")
+	c.Assert(err, ErrorMatches, ".*row checksum mismatch.*")
+	tk.MustExec("set @@tidb_verify_row_checksum = 0")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("2"))
+
+	// Rebuilding the rows without checksum strips the checksums.
+	tk.MustExec("admin disable row checksum")
+	tk.MustQuery("select @@global.tidb_enable_row_checksum, @@tidb_enable_row_checksum").Check(testkit.Rows("0 0"))
+	tk.MustExec("admin rebuild row checksum test.t")
+	c.Assert(hasChecksum(1), IsFalse)
+	c.Assert(hasChecksum(2), IsFalse)
+	tk.MustExec("set @@tidb_verify_row_checksum = 1")
+	// The corrupted row is accepted since it has no checksum now.
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "2"))
+}
+
+func (s *testSuite3) TestReadOnly(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values(1)")
+	tk.MustExec("set global read_only = 1")
+	defer tk.MustExec("set global read_only = 0")
+	tk.MustQuery("select @@global.read_only").Check(testkit.Rows("1"))
+	// No privilege manager is bound, the privilege check is skipped.
+	tk.MustExec("insert into t values(2)")
+
+	pm := &reloadPrivManager{}
+	privilege.BindPrivilegeManager(tk.Se, pm)
+	defer privilege.BindPrivilegeManager(tk.Se, nil)
+	writes := []string{
+		"insert into t values(3)",
+		"delete from t",
+		"create table t1(a int)",
+		"drop table t",
+		"truncate table t",
+		"create index idx on t(a)",
+		"analyze table t",
+		"explain insert into t values(3)",
+		"admin reload stats",
+		"admin scatter table t",
+	}
+	for _, sql := range writes {
+		_, err := tk.Exec(sql)
+		c.Assert(terror.ErrorEqual(err, core.ErrOptionPreventsStatement), IsTrue, Commentf("sql: %s, err: %v", sql, err))
+	}
+	_, err := tk.Exec("insert into t values(3)")
+	c.Assert(err.Error(), Matches, ".*--read-only.*")
+	tk.MustExec("begin")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1", "2"))
+	tk.MustExec("commit")
+	tk.MustExec("explain select * from t")
+	tk.MustExec("show tables")
+	tk.MustExec("use test")
+	tk.MustExec("set @a = 1")
+	// The user variables belong to the session, and the ADMIN statements showing the states or flushing
+	// the caches are read-only.
+	tk.MustQuery("select @a := 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @a").Check(testkit.Rows("2"))
+	tk.MustExec("explain select @a := 3")
+	for _, sql := range []string{"admin show ddl", "admin show ddl jobs", "admin show baselines",
+		"admin show table t regions", "admin show table t distributions", "admin show ddl progress",
+		"admin show analyze progress", "admin recommend index", "admin flush plan_cache", "admin flush result_cache"} {
+		// Some of them need the SUPER privilege, but none is prevented by the read-only mode.
+		_, err := tk.Exec(sql)
+		c.Assert(terror.ErrorEqual(err, core.ErrOptionPreventsStatement), IsFalse, Commentf("sql: %s, err: %v", sql, err))
+	}
+	// The internal SQLs are allowed.
+	_, _, err = tk.Se.(sqlexec.RestrictedSQLExecutor).ExecRestrictedSQL("insert into test.t values(3)")
+	c.Assert(err, IsNil)
+
+	// The super users are only restricted by super_read_only.
+	pm.super = true
+	tk.MustExec("insert into t values(4)")
+	tk.MustExec("set global super_read_only = 1")
+	defer tk.MustExec("set global super_read_only = 0")
+	_, err = tk.Exec("insert into t values(5)")
+	c.Assert(terror.ErrorEqual(err, core.ErrOptionPreventsStatement), IsTrue, Commentf("err: %v", err))
+	c.Assert(err.Error(), Matches, ".*--super-read-only.*")
+	privilege.BindPrivilegeManager(tk.Se, nil)
+	_, err = tk.Exec("insert into t values(5)")
+	c.Assert(terror.ErrorEqual(err, core.ErrOptionPreventsStatement), IsTrue, Commentf("err: %v", err))
+	tk.MustExec("set global super_read_only = 0")
+	tk.MustExec("insert into t values(5)")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1", "2", "3", "4", "5"))
+}
+
+func (s *testSuite3) TestAdminFlush(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 10)")
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustExec("set @@tidb_enable_result_cache = 1")
+	// A large bucket keeps the reads of the test in the same bucket.
+	tk.MustExec("set @@tidb_result_cache_ts_bucket = 3600000")
+
+	tk.MustQuery("select b from t where a = 1").Check(testkit.Rows("10"))
+	tk.MustQuery("select b from t where a = 2").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("admin flush plan_cache")
+	tk.MustQuery("select b from t where a = 3").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 10"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 10"))
+	tk.MustQuery("select @@last_result_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("admin flush result_cache")
+	tk.MustQuery("select * from t").Check(testkit.Rows("1 10"))
+	tk.MustQuery("select @@last_result_from_cache").Check(testkit.Rows("0"))
+
+	// Only flushing the result cache, which is shared by all the sessions, requires the SUPER privilege.
+	pm := &reloadPrivManager{}
+	privilege.BindPrivilegeManager(tk.Se, pm)
+	defer privilege.BindPrivilegeManager(tk.Se, nil)
+	tk.MustExec("admin flush plan_cache")
+	_, err := tk.Exec("admin flush result_cache")
+	c.Assert(terror.ErrorEqual(err, core.ErrSpecificAccessDenied), IsTrue, Commentf("err %v", err))
+}
@@ -19,6 +19,7 @@ import (
 	"sort"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/terror"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/util/chunk"
 )
@@ -40,34 +41,60 @@ type SortExec struct {
 	rowChunks *chunk.List
 	// rowPointer store the chunk index and row index for each row.
 	rowPtrs []chunk.RowPtr
+
+	// rowContainer holds the input rows which are not spilled yet.
+	rowContainer *chunk.RowContainer
+	// partitionList holds the sorted runs spilled to the disk when the memory
+	// quota is exceeded, they are merged by multiWayMerge.
+	partitionList []*chunk.RowContainer
+	multiWayMerge *multiWayMerge
 }
 
 // Close implements the Executor Close interface.
 func (e *SortExec) Close() error {
-	return e.children[0].Close()
+	for _, partition := range e.partitionList {
+		terror.Log(partition.Close())
+	}
+	e.partitionList = nil
+	e.multiWayMerge = nil
+	if e.rowContainer != nil {
+		terror.Log(e.rowContainer.Close())
+		e.rowContainer = nil
+	}
+	return Close(e.children[0])
 }
 
 // Open implements the Executor Open interface.
 func (e *SortExec) Open(ctx context.Context) error {
 	e.fetched = false
 	e.Idx = 0
-	return e.children[0].Open(ctx)
+	return Open(ctx, e.children[0])
 }
 
 // Next implements the Executor Next interface.
 func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
 	if !e.fetched {
+		e.initCompareFuncs()
+		e.buildKeyColumns()
 		err := e.fetchRowChunks(ctx)
 		if err != nil {
 			return err
 		}
-		e.initPointers()
-		e.initCompareFuncs()
-		e.buildKeyColumns()
-		sort.Slice(e.rowPtrs, e.keyColumnsLess)
+		if len(e.partitionList) > 0 {
+			err = e.initMultiWayMerge()
+			if err != nil {
+				return err
+			}
+		} else {
+			e.initPointers()
+			sort.Slice(e.rowPtrs, e.keyColumnsLess)
+		}
 		e.fetched = true
 	}
+	if e.multiWayMerge != nil {
+		return e.externalSorting(req)
+	}
 	for !req.IsFull() && e.Idx < len(e.rowPtrs) {
 		rowPtr := e.rowPtrs[e.Idx]
 		req.AppendRow(e.rowChunks.GetRow(rowPtr))
@@ -76,9 +103,22 @@ func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	return nil
 }
 
+// fetchRowChunks reads all the rows from the child. The rows are held by a
+// RowContainer which is sorted and spilled to the disk as a partition once the
+// memory quota is exceeded, then the following rows go to a new container.
 func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 	fields := retTypes(e)
-	e.rowChunks = chunk.NewList(fields, e.initCap, e.maxChunkSize)
+	sc := e.ctx.GetSessionVars().StmtCtx
+	newRowContainer := func() *chunk.RowContainer {
+		rc := chunk.NewRowContainer(fields, e.initCap, e.maxChunkSize)
+		rc.SetMemoryConsumer(func(bytes int64) error {
+			return sc.ConsumeMemory("sort", bytes)
+		})
+		rc.SetDiskConsumer(sc.AddSpilledBytes)
+		rc.SetSortBeforeSpill(e.lessRow)
+		return rc
+	}
+	e.rowContainer = newRowContainer()
 	for {
 		chk := newFirstChunk(e.children[0])
 		err := Next(ctx, e.children[0], chk)
@@ -89,11 +129,121 @@ func (e *SortExec) fetchRowChunks(ctx context.Context) error {
 		if rowCount == 0 {
 			break
 		}
+		if err = e.rowContainer.Add(chk); err != nil {
+			return err
+		}
+		if e.rowContainer.AlreadySpilled() {
+			e.partitionList = append(e.partitionList, e.rowContainer)
+			e.rowContainer = newRowContainer()
+		}
+	}
+	e.rowChunks = chunk.NewList(fields, e.initCap, e.maxChunkSize)
+	if len(e.partitionList) > 0 {
+		// The rows left in memory are sorted and spilled as the last partition.
+		if e.rowContainer.Len() > 0 {
+			if err := e.rowContainer.SpillToDisk(); err != nil {
+				return err
+			}
+			e.partitionList = append(e.partitionList, e.rowContainer)
+			e.rowContainer = nil
+		}
+		return nil
+	}
+	for i := 0; i < e.rowContainer.NumChunks(); i++ {
+		chk, err := e.rowContainer.GetChunk(i)
+		if err != nil {
+			return err
+		}
 		e.rowChunks.Add(chk)
 	}
 	return nil
 }
 
+func (e *SortExec) initMultiWayMerge() error {
+	e.multiWayMerge = &multiWayMerge{lessRowFunction: e.lessRow}
+	for _, partition := range e.partitionList {
+		chk, err := partition.GetChunk(0)
+		if err != nil {
+			return err
+		}
+		e.multiWayMerge.cursors = append(e.multiWayMerge.cursors, &partitionCursor{partition: partition, chk: chk})
+	}
+	heap.Init(e.multiWayMerge)
+	return nil
+}
+
+// externalSorting outputs the rows by merging the sorted partitions in the disk.
+func (e *SortExec) externalSorting(req *chunk.Chunk) error {
+	for !req.IsFull() && e.multiWayMerge.Len() > 0 {
+		cursor := e.multiWayMerge.cursors[0]
+		req.AppendRow(cursor.row())
+		ok, err := cursor.advance()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(e.multiWayMerge, 0)
+		} else {
+			heap.Remove(e.multiWayMerge, 0)
+		}
+	}
+	return nil
+}
+
+// partitionCursor reads a sorted partition chunk by chunk.
+type partitionCursor struct {
+	partition *chunk.RowContainer
+	chk       *chunk.Chunk
+	chkIdx    int
+	rowIdx    int
+}
+
+func (c *partitionCursor) row() chunk.Row {
+	return c.chk.GetRow(c.rowIdx)
+}
+
+// advance moves the cursor to the next row, it returns false if the partition is exhausted.
+func (c *partitionCursor) advance() (ok bool, err error) {
+	c.rowIdx++
+	if c.rowIdx < c.chk.NumRows() {
+		return true, nil
+	}
+	c.chkIdx++
+	if c.chkIdx >= c.partition.NumChunks() {
+		return false, nil
+	}
+	c.rowIdx = 0
+	c.chk, err = c.partition.GetChunk(c.chkIdx)
+	return err == nil, err
+}
+
+// multiWayMerge implements heap.Interface, it's a min heap of the current rows of the partitions.
+type multiWayMerge struct {
+	lessRowFunction func(rowI, rowJ chunk.Row) bool
+	cursors         []*partitionCursor
+}
+
+func (h *multiWayMerge) Less(i, j int) bool {
+	return h.lessRowFunction(h.cursors[i].row(), h.cursors[j].row())
+}
+
+func (h *multiWayMerge) Len() int {
+	return len(h.cursors)
+}
+
+func (h *multiWayMerge) Push(x interface{}) {
+	// Should never be called.
+}
+
+func (h *multiWayMerge) Pop() interface{} {
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return nil
+}
+
+func (h *multiWayMerge) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
 func (e *SortExec) initPointers() {
 	e.rowPtrs = make([]chunk.RowPtr, 0, e.rowChunks.Len())
 	for chkIdx := 0; chkIdx < e.rowChunks.NumChunks(); chkIdx++ {
@@ -16,10 +16,13 @@ package executor
 import (
 	"context"
 	"github.com/pingcap/tidb/distsql"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/ranger"
 	"github.com/pingcap/tipb/go-tipb"
@@ -48,6 +51,12 @@ type TableReaderExecutor struct {
 
 	keepOrder bool
 	desc      bool
+
+	// virtualColumnIndex records the offsets of the virtual columns in the schema, the
+	// virtual columns are evaluated after the rows are read.
+	virtualColumnIndex []int
+	// virtualColumnRetFieldTypes records the types of the virtual columns.
+	virtualColumnRetFieldTypes []*types.FieldType
 }
 
 // Open initialzes necessary variables for using this executor.
@@ -74,7 +83,48 @@ func (e *TableReaderExecutor) Open(ctx context.Context) error {
 // Next fills data into the chunk passed by its caller.
 // The task was actually done by tableReaderHandler.
 func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
-	return e.resultHandler.nextChunk(ctx, req)
+	if err := e.resultHandler.nextChunk(ctx, req); err != nil {
+		return err
+	}
+	return FillVirtualColumnValue(e.virtualColumnRetFieldTypes, e.virtualColumnIndex, e.schema, e.columns, e.ctx, req)
+}
+
+// FillVirtualColumnValue fills the values of the virtual columns in the chunk by evaluating their
+// expressions on the other columns.
+func FillVirtualColumnValue(virtualRetTypes []*types.FieldType, virtualColumnIndex []int,
+	schema *expression.Schema, columns []*model.ColumnInfo, sctx sessionctx.Context, req *chunk.Chunk) error {
+	if len(virtualColumnIndex) == 0 {
+		return nil
+	}
+	virCols := chunk.NewChunkWithCapacity(virtualRetTypes, req.Capacity())
+	iter := chunk.NewIterator4Chunk(req)
+	for i, idx := range virtualColumnIndex {
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			datum, err := schema.Columns[idx].EvalVirtualColumn(row)
+			if err != nil {
+				return err
+			}
+			// The expression may return a different type from the virtual column.
+			castDatum, err := table.CastValue(sctx, datum, columns[idx])
+			if err != nil {
+				return err
+			}
+			virCols.AppendDatum(i, &castDatum)
+		}
+		req.SetCol(idx, virCols.Column(i))
+	}
+	return nil
+}
+
+// buildVirtualColumnInfo returns the offsets and the types of the virtual columns in the schema.
+func buildVirtualColumnInfo(schema *expression.Schema) (virtualColumnIndex []int, virtualColumnRetFieldTypes []*types.FieldType) {
+	for i, col := range schema.Columns {
+		if col.VirtualExpr != nil {
+			virtualColumnIndex = append(virtualColumnIndex, i)
+			virtualColumnRetFieldTypes = append(virtualColumnRetFieldTypes, col.RetType)
+		}
+	}
+	return virtualColumnIndex, virtualColumnRetFieldTypes
 }
 
 // Close implements the Executor Close interface.
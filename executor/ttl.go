@@ -0,0 +1,136 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// RunTTLJobsIfEnabled deletes the expired rows of the TTL tables if tidb_ttl_job_enable is on,
+// it's called periodically in the background. Only the DDL owner runs the TTL jobs, so the
+// TiDB instances don't delete the same rows concurrently.
+func RunTTLJobsIfEnabled(ctx context.Context, sctx sessionctx.Context) error {
+	val, err := variable.GetGlobalSystemVar(sctx.GetSessionVars(), variable.TiDBTTLJobEnable)
+	if err != nil || !variable.TiDBOptOn(val) {
+		return err
+	}
+	if !domain.GetDomain(sctx).DDL().OwnerManager().IsOwner() {
+		return nil
+	}
+	return RunTTLJobs(ctx, sctx, time.Now())
+}
+
+// RunTTLJobs deletes the rows expired at now of all the TTL tables whose TTL_ENABLE is on. The
+// rows are deleted by the statements of tidb_ttl_delete_batch_size rows, each statement runs in
+// its own transaction, and at most tidb_ttl_delete_rate_limit statements are executed per second.
+// A failed table doesn't stop the jobs of the other tables, the first error is returned.
+func RunTTLJobs(ctx context.Context, sctx sessionctx.Context, now time.Time) error {
+	vars := sctx.GetSessionVars()
+	batchSize, err := getGlobalUintSystemVar(vars, variable.TiDBTTLDeleteBatchSize)
+	if err != nil {
+		return err
+	}
+	rateLimit, err := getGlobalUintSystemVar(vars, variable.TiDBTTLDeleteRateLimit)
+	if err != nil {
+		return err
+	}
+	var interval time.Duration
+	if rateLimit > 0 {
+		interval = time.Second / time.Duration(rateLimit)
+	}
+
+	var firstErr error
+	is := domain.GetDomain(sctx).InfoSchema()
+	for _, db := range is.AllSchemas() {
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tblInfo := tbl.Meta()
+			if tblInfo.TTLInfo == nil || !tblInfo.TTLInfo.Enable {
+				continue
+			}
+			deleted, err := runTTLJob(ctx, sctx, db.Name, tblInfo, now, batchSize, interval)
+			if err != nil {
+				logutil.Logger(ctx).Warn("TTL job failed",
+					zap.String("table", db.Name.O+"."+tblInfo.Name.O),
+					zap.Uint64("deletedRows", deleted),
+					zap.Error(err))
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			logutil.Logger(ctx).Info("TTL job finished",
+				zap.String("table", db.Name.O+"."+tblInfo.Name.O),
+				zap.Uint64("deletedRows", deleted))
+		}
+	}
+	return firstErr
+}
+
+// runTTLJob deletes the expired rows of a table batch by batch, until a batch deletes less than
+// batchSize rows. It returns the number of the deleted rows.
+func runTTLJob(ctx context.Context, sctx sessionctx.Context, dbName model.CIStr, tblInfo *model.TableInfo,
+	now time.Time, batchSize uint64, interval time.Duration) (uint64, error) {
+	ttlInfo := tblInfo.TTLInfo
+	ttl, err := ttlInfo.Interval()
+	if err != nil {
+		return 0, err
+	}
+	sql := fmt.Sprintf("DELETE FROM %s.%s WHERE %s < %d LIMIT %d", escapeTTLName(dbName.O),
+		escapeTTLName(tblInfo.Name.O), escapeTTLName(ttlInfo.ColumnName.O), now.Add(-ttl).Unix(), batchSize)
+
+	var deleted uint64
+	for {
+		start := time.Now()
+		if err = execAndDrain(ctx, sctx, sql); err != nil {
+			return deleted, err
+		}
+		affected := sctx.GetSessionVars().StmtCtx.AffectedRows()
+		deleted += affected
+		if affected < batchSize {
+			return deleted, nil
+		}
+		if wait := interval - time.Since(start); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return deleted, errors.Trace(ctx.Err())
+			case <-time.After(wait):
+			}
+		}
+	}
+}
+
+func getGlobalUintSystemVar(vars *variable.SessionVars, name string) (uint64, error) {
+	val, err := variable.GetGlobalSystemVar(vars, name)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(val, 10, 64)
+	return v, errors.Trace(err)
+}
+
+func escapeTTLName(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
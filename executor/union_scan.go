@@ -15,10 +15,13 @@ package executor
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/distsql"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
@@ -92,13 +95,18 @@ type UnionScanExec struct {
 
 	dirty *DirtyTable
 	// usedIndex is the column offsets of the index which Src executor has used.
-	usedIndex  []int
-	desc       bool
-	conditions []expression.Expression
-	columns    []*model.ColumnInfo
-	table      table.Table
+	usedIndex []int
+	// usedIndexDesc records whether each of the usedIndex columns is in descending order in the index.
+	usedIndexDesc []bool
+	desc          bool
+	conditions    []expression.Expression
+	columns       []*model.ColumnInfo
+	table         table.Table
 	// belowHandleIndex is the handle's position of the below scan plan.
 	belowHandleIndex int
+	// cacheTable is the data of the cached table, all the rows are read from it and the child is
+	// not executed if it's not nil.
+	cacheTable kv.MemBuffer
 
 	addedRows           [][]types.Datum
 	cursor4AddRows      int
@@ -111,15 +119,31 @@ type UnionScanExec struct {
 
 // Open implements the Executor Open interface.
 func (us *UnionScanExec) Open(ctx context.Context) error {
-	if err := us.baseExecutor.Open(ctx); err != nil {
-		return err
+	if us.cacheTable == nil {
+		if err := us.baseExecutor.Open(ctx); err != nil {
+			return err
+		}
 	}
 	return us.open(ctx)
 }
 
+// Close implements the Executor Close interface.
+func (us *UnionScanExec) Close() error {
+	if us.cacheTable != nil {
+		return nil
+	}
+	return us.baseExecutor.Close()
+}
+
 func (us *UnionScanExec) open(ctx context.Context) error {
 	var err error
 	reader := us.children[0]
+	if us.cacheTable != nil {
+		// The child is not opened, so the kv ranges are built here.
+		if err = buildKVRangesOfReader(reader); err != nil {
+			return err
+		}
+	}
 	switch x := reader.(type) {
 	case *TableReaderExecutor:
 		us.addedRows, err = buildMemTableReader(us, x).getMemRows()
@@ -129,6 +153,15 @@ func (us *UnionScanExec) open(ctx context.Context) error {
 	case *IndexLookUpExecutor:
 		idxLookup := buildMemIndexLookUpReader(us, x)
 		us.addedRows, err = idxLookup.getMemRows()
+		if err == nil && us.cacheTable != nil {
+			// The rows are read in the order of the handles, but they should be in the order of the index
+			// since all the rows are read from the cache.
+			sort.Sort(us)
+			err = us.sortErr
+			if us.desc {
+				reverseDatumSlice(us.addedRows)
+			}
+		}
 	}
 	if err != nil {
 		return err
@@ -137,6 +170,22 @@ func (us *UnionScanExec) open(ctx context.Context) error {
 	return nil
 }
 
+// buildKVRangesOfReader builds the kv ranges of the reader in the same way as it's opened.
+func buildKVRangesOfReader(reader Executor) error {
+	var err error
+	switch x := reader.(type) {
+	case *TableReaderExecutor:
+		firstPartRanges, secondPartRanges := splitRanges(x.ranges, x.keepOrder, x.desc)
+		x.kvRanges = distsql.TableRangesToKVRanges(getPhysicalTableID(x.table), firstPartRanges)
+		x.kvRanges = append(x.kvRanges, distsql.TableRangesToKVRanges(getPhysicalTableID(x.table), secondPartRanges)...)
+	case *IndexReaderExecutor:
+		x.kvRanges, err = distsql.IndexRangesToKVRangesWithDesc(x.ctx.GetSessionVars().StmtCtx, x.physicalTableID, x.index.ID, x.index.DescColumns(), x.ranges)
+	case *IndexLookUpExecutor:
+		x.kvRanges, err = distsql.IndexRangesToKVRangesWithDesc(x.ctx.GetSessionVars().StmtCtx, getPhysicalTableID(x.table), x.index.ID, x.index.DescColumns(), x.ranges)
+	}
+	return err
+}
+
 // Next implements the Executor Next interface.
 func (us *UnionScanExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.GrowAndReset(us.maxChunkSize)
@@ -196,6 +245,9 @@ func (us *UnionScanExec) getOneRow(ctx context.Context) ([]types.Datum, error) {
 }
 
 func (us *UnionScanExec) getSnapshotRow(ctx context.Context) ([]types.Datum, error) {
+	if us.cacheTable != nil {
+		return nil, nil
+	}
 	if us.cursor4SnapshotRows < len(us.snapshotRows) {
 		return us.snapshotRows[us.cursor4SnapshotRows], nil
 	}
@@ -255,7 +307,7 @@ func (us *UnionScanExec) shouldPickFirstRow(a, b []types.Datum) (bool, error) {
 
 func (us *UnionScanExec) compare(a, b []types.Datum) (int, error) {
 	sc := us.ctx.GetSessionVars().StmtCtx
-	for _, colOff := range us.usedIndex {
+	for i, colOff := range us.usedIndex {
 		aColumn := a[colOff]
 		bColumn := b[colOff]
 		cmp, err := aColumn.CompareDatum(sc, &bColumn)
@@ -263,6 +315,9 @@ func (us *UnionScanExec) compare(a, b []types.Datum) (int, error) {
 			return 0, err
 		}
 		if cmp != 0 {
+			if us.usedIndexDesc[i] {
+				cmp = -cmp
+			}
 			return cmp, nil
 		}
 	}
@@ -479,6 +479,76 @@ func (s *testSuite) TestDelete(c *C) {
 	rows := tk.MustQuery(`SELECT * from delete_test limit 2;`)
 	rows.Check(testkit.Rows("1 hello"))
 	tk.MustExec("commit")
+
+	// Test delete with order by and limit.
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, c int, key ib(b))")
+	tk.MustExec("insert t values (1, 5, 1), (2, 4, 2), (3, 3, 3), (4, 2, 4), (5, 1, 5), (6, 1, 6)")
+	tk.MustExec("delete from t order by b, a desc limit 2")
+	tk.CheckExecResult(2, 0)
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustExec("delete from t where b > 2 order by c desc limit 1")
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "2", "4"))
+	tk.MustExec("delete from t order by b + c limit 1")
+	tk.MustQuery("select a from t").Check(testkit.Rows("2", "4"))
+	tk.MustExec("delete from t order by b limit 0")
+	tk.CheckExecResult(0, 0)
+	_, err := tk.Exec("delete from t order by d limit 1")
+	c.Assert(err.Error(), Equals, "[planner:1054]Unknown column 'd' in 'order clause'")
+	tk.MustExec("drop table t")
+}
+
+func (s *testSuite) TestDeleteWithSubquery(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, s")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("create table s(a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into s values (1, 10), (2, 20)")
+
+	tk.MustExec("delete from t where a = (select max(a) from s)")
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select a from t").Check(testkit.Rows("1", "3"))
+	// Correlated subquery.
+	tk.MustExec("delete from t where b < (select count(*) from s where s.a = t.a) + 1")
+	tk.CheckExecResult(1, 0)
+	tk.MustQuery("select a from t").Check(testkit.Rows("3"))
+	err := tk.ExecToErr("delete from t where a = (select a from s)")
+	c.Assert(err.Error(), Equals, "[executor:1242]Subquery returns more than 1 row")
+	tk.MustQuery("select a from t").Check(testkit.Rows("3"))
+	tk.MustExec("drop table t, s")
+}
+
+func (s *testSuite) TestDeleteInSafeUpdateMode(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key ib(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4), (5, 5, 5)")
+	tk.MustExec("set @@sql_safe_updates = 1")
+
+	errMsg := "[planner:1175]You are using safe update mode and you tried to update a table without a WHERE that uses a KEY column"
+	err := tk.ExecToErr("delete from t")
+	c.Assert(err.Error(), Equals, errMsg)
+	err = tk.ExecToErr("delete from t where c = 1")
+	c.Assert(err.Error(), Equals, errMsg)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("5"))
+
+	tk.MustExec("delete from t where a = 1")
+	tk.CheckExecResult(1, 0)
+	tk.MustExec("delete from t where b = 2 and c = 2")
+	tk.CheckExecResult(1, 0)
+	tk.MustExec("delete from t where c = 3 limit 1")
+	tk.CheckExecResult(1, 0)
+	tk.MustExec("delete from t where 0")
+	tk.CheckExecResult(0, 0)
+	tk.MustQuery("select a from t").Check(testkit.Rows("4", "5"))
+
+	tk.MustExec("set @@sql_safe_updates = 0")
+	tk.MustExec("delete from t")
+	tk.CheckExecResult(2, 0)
+	tk.MustExec("drop table t")
 }
 
 func (s *testSuite4) TestNotNullDefault(c *C) {
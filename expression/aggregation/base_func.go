@@ -17,11 +17,13 @@ import (
 	"bytes"
 	"fmt"
 	log "github.com/sirupsen/logrus"
+	"math"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -102,6 +104,14 @@ func (a *baseFuncDesc) typeInfer(ctx sessionctx.Context) error {
 		a.typeInfer4Avg(ctx)
 	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
 		a.typeInfer4MaxMin(ctx)
+	case ast.AggFuncGroupConcat:
+		a.typeInfer4GroupConcat(ctx)
+	case ast.AggFuncBitAnd, ast.AggFuncBitOr, ast.AggFuncBitXor:
+		a.typeInfer4BitFuncs(ctx)
+	case ast.AggFuncVarPop, ast.AggFuncStddevPop:
+		a.typeInfer4PopFuncs(ctx)
+	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		a.typeInfer4NumberFuncs()
 	default:
 		return errors.Errorf("unsupported agg function: %s", a.Name)
 	}
@@ -146,6 +156,36 @@ func (a *baseFuncDesc) typeInfer4Avg(ctx sessionctx.Context) {
 	types.SetBinChsClnFlag(a.RetTp)
 }
 
+// typeInfer4GroupConcat should returns a "text" or "blob" according to the
+// group_concat_max_len, we return a "varchar" as long as a blob here.
+func (a *baseFuncDesc) typeInfer4GroupConcat(ctx sessionctx.Context) {
+	a.RetTp = types.NewFieldType(mysql.TypeVarString)
+	a.RetTp.Charset, a.RetTp.Collate = charset.GetDefaultCharsetAndCollate()
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxBlobWidth, 0
+}
+
+func (a *baseFuncDesc) typeInfer4BitFuncs(ctx sessionctx.Context) {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+	a.RetTp.Flag |= mysql.UnsignedFlag | mysql.NotNullFlag
+}
+
+// typeInfer4PopFuncs infers the type of the population variance and standard
+// deviation, both of them return a "double".
+func (a *baseFuncDesc) typeInfer4PopFuncs(ctx sessionctx.Context) {
+	a.RetTp = types.NewFieldType(mysql.TypeDouble)
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+// typeInfer4NumberFuncs infers the type of the window functions which number the rows.
+func (a *baseFuncDesc) typeInfer4NumberFuncs() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen = 21
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
 func (a *baseFuncDesc) typeInfer4MaxMin(ctx sessionctx.Context) {
 	a.RetTp = a.Args[0].GetType()
 	if (a.Name == ast.AggFuncMax || a.Name == ast.AggFuncMin) && a.RetTp.Tp != mysql.TypeBit {
@@ -178,8 +218,12 @@ func (a *baseFuncDesc) GetDefaultValue() (v types.Datum) {
 	case ast.AggFuncCount:
 		v = types.NewIntDatum(0)
 	case ast.AggFuncFirstRow, ast.AggFuncAvg, ast.AggFuncSum, ast.AggFuncMax,
-		ast.AggFuncMin:
+		ast.AggFuncMin, ast.AggFuncGroupConcat, ast.AggFuncVarPop, ast.AggFuncStddevPop:
 		v = types.Datum{}
+	case ast.AggFuncBitAnd:
+		v = types.NewUintDatum(uint64(math.MaxUint64))
+	case ast.AggFuncBitOr, ast.AggFuncBitXor:
+		v = types.NewUintDatum(0)
 	}
 	return
 }
@@ -187,8 +231,14 @@ func (a *baseFuncDesc) GetDefaultValue() (v types.Datum) {
 // We do not need to wrap cast upon these functions,
 // since the EvalXXX method called by the arg is determined by the corresponding arg type.
 var noNeedCastAggFuncs = map[string]struct{}{
-	ast.AggFuncCount:    {},
-	ast.AggFuncMax:      {},
-	ast.AggFuncMin:      {},
-	ast.AggFuncFirstRow: {},
+	ast.AggFuncCount:       {},
+	ast.AggFuncMax:         {},
+	ast.AggFuncMin:         {},
+	ast.AggFuncFirstRow:    {},
+	ast.AggFuncGroupConcat: {},
+	ast.AggFuncBitAnd:      {},
+	ast.AggFuncBitOr:       {},
+	ast.AggFuncBitXor:      {},
+	ast.AggFuncVarPop:      {},
+	ast.AggFuncStddevPop:   {},
 }
@@ -50,3 +50,39 @@ func (s *testBaseFuncSuite) TestMaxMin(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(mysql.HasNotNullFlag(desc.RetTp.Flag), check.IsFalse)
 }
+
+func (s *testBaseFuncSuite) TestWindowFuncDesc(c *check.C) {
+	col := &expression.Column{
+		UniqueID: 0,
+		RetType:  types.NewFieldType(mysql.TypeDouble),
+	}
+	desc, err := NewWindowFuncDesc(s.ctx, "RANK", nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(desc.Name, check.Equals, ast.WindowFuncRank)
+	c.Assert(desc.RetTp.Tp, check.Equals, mysql.TypeLonglong)
+	c.Assert(desc.UseFrame(), check.IsFalse)
+	desc.OrderBy = []WindowOrderItem{{Col: col}}
+	desc.Frame = &WindowFrame{Type: Rows, Start: &FrameBound{Type: CurrentRow}, End: &FrameBound{Type: CurrentRow}}
+	c.Assert(desc.EffectiveFrame(), check.IsNil)
+
+	desc, err = NewWindowFuncDesc(s.ctx, ast.AggFuncSum, []expression.Expression{col})
+	c.Assert(err, check.IsNil)
+	c.Assert(desc.RetTp.Tp, check.Equals, mysql.TypeDouble)
+	c.Assert(desc.UseFrame(), check.IsTrue)
+	c.Assert(desc.EffectiveFrame(), check.IsNil)
+	// The default frame of the windows with ORDER BY ends at the peers of the current row.
+	desc.OrderBy = []WindowOrderItem{{Col: col}}
+	frame := desc.EffectiveFrame()
+	c.Assert(frame.Type, check.Equals, Ranges)
+	c.Assert(*frame.Start, check.Equals, FrameBound{Type: Preceding, UnBounded: true})
+	c.Assert(*frame.End, check.Equals, FrameBound{Type: CurrentRow})
+	desc.Frame = &WindowFrame{Type: Rows, Start: &FrameBound{Type: Preceding, Num: 1}, End: &FrameBound{Type: CurrentRow}}
+	c.Assert(desc.EffectiveFrame(), check.Equals, desc.Frame)
+
+	_, err = NewWindowFuncDesc(s.ctx, ast.WindowFuncRowNumber, []expression.Expression{col})
+	c.Assert(err, check.NotNil)
+	_, err = NewWindowFuncDesc(s.ctx, ast.AggFuncCount, nil)
+	c.Assert(err, check.NotNil)
+	_, err = NewWindowFuncDesc(s.ctx, ast.AggFuncFirstRow, []expression.Expression{col})
+	c.Assert(err, check.NotNil)
+}
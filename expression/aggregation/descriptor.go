@@ -26,19 +26,24 @@ type AggFuncDesc struct {
 	baseFuncDesc
 	// Mode represents the execution mode of the aggregation function.
 	Mode AggFunctionMode
+	// HasDistinct represents whether the aggregation function contains distinct attribute.
+	HasDistinct bool
 }
 
 // NewAggFuncDesc creates an aggregation function signature descriptor.
-func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression) (*AggFuncDesc, error) {
+func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression, hasDistinct bool) (*AggFuncDesc, error) {
 	b, err := newBaseFuncDesc(ctx, name, args)
 	if err != nil {
 		return nil, err
 	}
-	return &AggFuncDesc{baseFuncDesc: b}, nil
+	return &AggFuncDesc{baseFuncDesc: b, HasDistinct: hasDistinct}, nil
 }
 
 // Equal checks whether two aggregation function signatures are equal.
 func (a *AggFuncDesc) Equal(ctx sessionctx.Context, other *AggFuncDesc) bool {
+	if a.HasDistinct != other.HasDistinct {
+		return false
+	}
 	return a.baseFuncDesc.equal(ctx, &other.baseFuncDesc)
 }
 
@@ -63,7 +68,8 @@ func (a *AggFuncDesc) Split(ordinal []int) (partialAggDesc, finalAggDesc *AggFun
 		panic("Error happened during AggFuncDesc.Split, the AggFunctionMode is not CompleteMode or FinalMode.")
 	}
 	finalAggDesc = &AggFuncDesc{
-		Mode: FinalMode, // We only support FinalMode now in final phase.
+		Mode:        FinalMode, // We only support FinalMode now in final phase.
+		HasDistinct: a.HasDistinct,
 	}
 	finalAggDesc.Name = a.Name
 	finalAggDesc.RetTp = a.RetTp
@@ -79,6 +85,10 @@ func (a *AggFuncDesc) Split(ordinal []int) (partialAggDesc, finalAggDesc *AggFun
 			RetType: a.RetTp,
 		})
 		finalAggDesc.Args = args
+	case ast.AggFuncGroupConcat:
+		// The final phase merges the partial results in memory and never
+		// evaluates its arguments, the original ones are kept for the separator.
+		finalAggDesc.Args = partialAggDesc.Args
 	default:
 		args := make([]expression.Expression, 0, 1)
 		args = append(args, &expression.Column{
@@ -130,7 +140,8 @@ func (a *AggFuncDesc) EvalNullValueInOuterJoin(ctx sessionctx.Context, schema *e
 	case ast.AggFuncSum, ast.AggFuncMax, ast.AggFuncMin,
 		ast.AggFuncFirstRow:
 		return a.evalNullValueInOuterJoin4Sum(ctx, schema)
-	case ast.AggFuncAvg:
+	case ast.AggFuncAvg, ast.AggFuncGroupConcat, ast.AggFuncBitAnd, ast.AggFuncBitOr,
+		ast.AggFuncBitXor, ast.AggFuncVarPop, ast.AggFuncStddevPop:
 		return types.Datum{}, false
 	default:
 		panic("unsupported agg function")
@@ -0,0 +1,139 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// FrameType is the unit of the bounds of a window frame.
+type FrameType int
+
+const (
+	// Rows means the bounds of the frame are offsets in rows.
+	Rows FrameType = iota
+	// Ranges means the bounds of the frame are offsets of the value of the ORDER BY item.
+	Ranges
+)
+
+// BoundType is the type of a bound of a window frame.
+type BoundType int
+
+const (
+	// Preceding means the bound is before the current row.
+	Preceding BoundType = iota
+	// Following means the bound is after the current row.
+	Following
+	// CurrentRow means the bound is the current row, or its peers for RANGE frames.
+	CurrentRow
+)
+
+// FrameBound is a bound of a window frame.
+type FrameBound struct {
+	Type BoundType
+	// UnBounded means the bound is UNBOUNDED PRECEDING or UNBOUNDED FOLLOWING.
+	UnBounded bool
+	// Num is the offset of the bound. It's a number of rows for ROWS frames,
+	// and the difference from the ORDER BY value of the current row for RANGE frames.
+	Num uint64
+}
+
+// WindowFrame is the frame of a window, the window function is evaluated on the
+// rows between Start and End.
+type WindowFrame struct {
+	Type  FrameType
+	Start *FrameBound
+	End   *FrameBound
+}
+
+// WindowOrderItem is an item of the ORDER BY clause of a window.
+type WindowOrderItem struct {
+	Col  *expression.Column
+	Desc bool
+}
+
+// noFrameWindowFuncs stores the window functions which ignore the frame of the window.
+var noFrameWindowFuncs = map[string]struct{}{
+	ast.WindowFuncRowNumber: {},
+	ast.WindowFuncRank:      {},
+	ast.WindowFuncDenseRank: {},
+}
+
+// WindowFuncDesc describes a window function signature, only used in planner.
+type WindowFuncDesc struct {
+	baseFuncDesc
+	// PartitionBy is the columns of the PARTITION BY clause of the window.
+	PartitionBy []*expression.Column
+	// OrderBy is the items of the ORDER BY clause of the window.
+	OrderBy []WindowOrderItem
+	// Frame is the frame of the window, nil means the frame is not specified.
+	Frame *WindowFrame
+}
+
+// NewWindowFuncDesc creates a window function signature descriptor. The window
+// functions are ROW_NUMBER, RANK, DENSE_RANK and the aggregate functions except FIRSTROW.
+func NewWindowFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression) (*WindowFuncDesc, error) {
+	name = strings.ToLower(name)
+	switch name {
+	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		if len(args) != 0 {
+			return nil, errors.Errorf("incorrect arguments to window function %s", name)
+		}
+	case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncMax, ast.AggFuncMin:
+		if len(args) != 1 {
+			return nil, errors.Errorf("incorrect arguments to window function %s", name)
+		}
+	default:
+		return nil, errors.Errorf("unsupported window function: %s", name)
+	}
+	b, err := newBaseFuncDesc(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	return &WindowFuncDesc{baseFuncDesc: b}, nil
+}
+
+// UseFrame checks whether the function is evaluated on the frame of the window.
+// The ranking functions are always evaluated on the whole partition.
+func (w *WindowFuncDesc) UseFrame() bool {
+	_, ok := noFrameWindowFuncs[w.Name]
+	return !ok
+}
+
+// EffectiveFrame returns the frame the function is evaluated on, nil means the
+// whole partition. If the frame is not specified, it's the whole partition for
+// the windows without ORDER BY, otherwise it's the rows from the start of the
+// partition to the peers of the current row, i.e.
+// "RANGE BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW".
+func (w *WindowFuncDesc) EffectiveFrame() *WindowFrame {
+	if !w.UseFrame() {
+		return nil
+	}
+	if w.Frame != nil {
+		return w.Frame
+	}
+	if len(w.OrderBy) == 0 {
+		return nil
+	}
+	return &WindowFrame{
+		Type:  Ranges,
+		Start: &FrameBound{Type: Preceding, UnBounded: true},
+		End:   &FrameBound{Type: CurrentRow},
+	}
+}
@@ -18,6 +18,7 @@
 //go:generate go run generator/compare_vec.go
 //go:generate go run generator/control_vec.go
 //go:generate go run generator/other_vec.go
+//go:generate go run generator/op_vec.go
 
 package expression
 
@@ -36,7 +37,9 @@ import (
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
@@ -47,6 +50,8 @@ type baseBuiltinFunc struct {
 	ctx          sessionctx.Context
 	tp           *types.FieldType
 	pbCode       tipb.ScalarFuncSig
+	// collation is the collation in which the string arguments are compared.
+	collation string
 
 	childrenVectorizedOnce *sync.Once
 	childrenVectorized     bool
@@ -77,9 +82,10 @@ func newBaseBuiltinFunc(ctx sessionctx.Context, args []Expression) baseBuiltinFu
 		bufAllocator:           newLocalSliceBuffer(len(args)),
 		childrenVectorizedOnce: new(sync.Once),
 
-		args: args,
-		ctx:  ctx,
-		tp:   types.NewFieldType(mysql.TypeUnspecified),
+		args:      args,
+		ctx:       ctx,
+		tp:        types.NewFieldType(mysql.TypeUnspecified),
+		collation: DeriveCollationFromExprs(args...),
 	}
 }
 
@@ -120,6 +126,13 @@ func newBaseBuiltinFuncWithTp(ctx sessionctx.Context, args []Expression, retType
 			Flen:    0,
 			Decimal: types.UnspecifiedLength,
 		}
+	case types.ETJson:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeJSON,
+			Flen:    mysql.MaxBlobWidth,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
 	}
 	if mysql.HasBinaryFlag(fieldType.Flag) {
 		fieldType.Charset, fieldType.Collate = charset.CharsetBin, charset.CollationBin
@@ -130,9 +143,10 @@ func newBaseBuiltinFuncWithTp(ctx sessionctx.Context, args []Expression, retType
 		bufAllocator:           newLocalSliceBuffer(len(args)),
 		childrenVectorizedOnce: new(sync.Once),
 
-		args: args,
-		ctx:  ctx,
-		tp:   fieldType,
+		args:      args,
+		ctx:       ctx,
+		tp:        fieldType,
+		collation: DeriveCollationFromExprs(args...),
 	}
 }
 
@@ -180,6 +194,10 @@ func (b *baseBuiltinFunc) evalString(row chunk.Row) (string, bool, error) {
 	return "", false, errors.Errorf("baseBuiltinFunc.evalString() should never be called, please contact the TiDB team for help")
 }
 
+func (b *baseBuiltinFunc) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	return json.BinaryJSON{}, false, errors.Errorf("baseBuiltinFunc.evalJSON() should never be called, please contact the TiDB team for help")
+}
+
 func (b *baseBuiltinFunc) vectorized() bool {
 	return false
 }
@@ -222,13 +240,22 @@ func (b *baseBuiltinFunc) equal(fun builtinFunc) bool {
 			return false
 		}
 	}
-	return true
+	return b.collation == fun.getCollation()
 }
 
 func (b *baseBuiltinFunc) getCtx() sessionctx.Context {
 	return b.ctx
 }
 
+func (b *baseBuiltinFunc) getCollation() string {
+	return b.collation
+}
+
+// collator returns the collator to compare the string arguments with.
+func (b *baseBuiltinFunc) collator() collate.Collator {
+	return collate.GetCollator(b.collation)
+}
+
 func (b *baseBuiltinFunc) cloneFrom(from *baseBuiltinFunc) {
 	b.args = make([]Expression, 0, len(b.args))
 	for _, arg := range from.args {
@@ -237,6 +264,7 @@ func (b *baseBuiltinFunc) cloneFrom(from *baseBuiltinFunc) {
 	b.ctx = from.ctx
 	b.tp = from.tp
 	b.pbCode = from.pbCode
+	b.collation = from.collation
 	b.bufAllocator = newLocalSliceBuffer(len(b.args))
 	b.childrenVectorizedOnce = new(sync.Once)
 }
@@ -285,6 +313,8 @@ type builtinFunc interface {
 	evalReal(row chunk.Row) (val float64, isNull bool, err error)
 	// evalString evaluates string representation of builtinFunc by given row.
 	evalString(row chunk.Row) (val string, isNull bool, err error)
+	// evalJSON evaluates JSON representation of builtinFunc by given row.
+	evalJSON(row chunk.Row) (val json.BinaryJSON, isNull bool, err error)
 	// getArgs returns the arguments expressions.
 	getArgs() []Expression
 	// equal check if this function equals to another function.
@@ -293,6 +323,8 @@ type builtinFunc interface {
 	getCtx() sessionctx.Context
 	// getRetTp returns the return type of the built-in function.
 	getRetTp() *types.FieldType
+	// getCollation returns the collation in which the string arguments are compared.
+	getCollation() string
 	// setPbCode sets pbCode for signature.
 	setPbCode(tipb.ScalarFuncSig)
 	// PbCode returns PbCode of this signature.
@@ -334,9 +366,38 @@ var funcs = map[string]functionClass{
 	ast.IsNull: &isNullFunctionClass{baseFunctionClass{ast.IsNull, 1, 1}},
 
 	// string functions
-	ast.Length:      &lengthFunctionClass{baseFunctionClass{ast.Length, 1, 1}},
-	ast.OctetLength: &lengthFunctionClass{baseFunctionClass{ast.OctetLength, 1, 1}},
-	ast.Strcmp:      &strcmpFunctionClass{baseFunctionClass{ast.Strcmp, 2, 2}},
+	ast.Length:         &lengthFunctionClass{baseFunctionClass{ast.Length, 1, 1}},
+	ast.OctetLength:    &lengthFunctionClass{baseFunctionClass{ast.OctetLength, 1, 1}},
+	ast.Strcmp:         &strcmpFunctionClass{baseFunctionClass{ast.Strcmp, 2, 2}},
+	ast.Like:           &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
+	ast.Regexp:         &regexpFunctionClass{baseFunctionClass{ast.Regexp, 2, 2}},
+	ast.RegexpLike:     &regexpFunctionClass{baseFunctionClass{ast.RegexpLike, 2, 3}},
+	ast.SubstringIndex: &substringIndexFunctionClass{baseFunctionClass{ast.SubstringIndex, 3, 3}},
+	ast.Lpad:           &lpadFunctionClass{baseFunctionClass{ast.Lpad, 3, 3}},
+	ast.Rpad:           &rpadFunctionClass{baseFunctionClass{ast.Rpad, 3, 3}},
+	ast.Instr:          &instrFunctionClass{baseFunctionClass{ast.Instr, 2, 2}},
+	ast.Replace:        &replaceFunctionClass{baseFunctionClass{ast.Replace, 3, 3}},
+	ast.Repeat:         &repeatFunctionClass{baseFunctionClass{ast.Repeat, 2, 2}},
+
+	// math functions
+	ast.Ln:       &lnFunctionClass{baseFunctionClass{ast.Ln, 1, 1}},
+	ast.Log:      &logFunctionClass{baseFunctionClass{ast.Log, 1, 2}},
+	ast.Log2:     &log2FunctionClass{baseFunctionClass{ast.Log2, 1, 1}},
+	ast.Log10:    &log10FunctionClass{baseFunctionClass{ast.Log10, 1, 1}},
+	ast.Pow:      &powFunctionClass{baseFunctionClass{ast.Pow, 2, 2}},
+	ast.Power:    &powFunctionClass{baseFunctionClass{ast.Power, 2, 2}},
+	ast.Round:    &roundFunctionClass{baseFunctionClass{ast.Round, 1, 2}},
+	ast.Truncate: &truncateFunctionClass{baseFunctionClass{ast.Truncate, 2, 2}},
+	ast.Conv:     &convFunctionClass{baseFunctionClass{ast.Conv, 3, 3}},
+
+	// encryption and hash functions
+	ast.MD5:        &md5FunctionClass{baseFunctionClass{ast.MD5, 1, 1}},
+	ast.SHA1:       &sha1FunctionClass{baseFunctionClass{ast.SHA1, 1, 1}},
+	ast.SHA:        &sha1FunctionClass{baseFunctionClass{ast.SHA, 1, 1}},
+	ast.SHA2:       &sha2FunctionClass{baseFunctionClass{ast.SHA2, 2, 2}},
+	ast.CRC32:      &crc32FunctionClass{baseFunctionClass{ast.CRC32, 1, 1}},
+	ast.AesEncrypt: &aesEncryptFunctionClass{baseFunctionClass{ast.AesEncrypt, 2, 3}},
+	ast.AesDecrypt: &aesDecryptFunctionClass{baseFunctionClass{ast.AesDecrypt, 2, 3}},
 
 	// control functions
 	ast.If:     &ifFunctionClass{baseFunctionClass{ast.If, 3, 3}},
@@ -348,8 +409,12 @@ var funcs = map[string]functionClass{
 	ast.LE:         &compareFunctionClass{baseFunctionClass{ast.LE, 2, 2}, opcode.LE},
 	ast.EQ:         &compareFunctionClass{baseFunctionClass{ast.EQ, 2, 2}, opcode.EQ},
 	ast.NE:         &compareFunctionClass{baseFunctionClass{ast.NE, 2, 2}, opcode.NE},
+	ast.NullEQ:     &compareFunctionClass{baseFunctionClass{ast.NullEQ, 2, 2}, opcode.NullEQ},
 	ast.LT:         &compareFunctionClass{baseFunctionClass{ast.LT, 2, 2}, opcode.LT},
 	ast.GT:         &compareFunctionClass{baseFunctionClass{ast.GT, 2, 2}, opcode.GT},
+	ast.Greatest:   &greatestFunctionClass{baseFunctionClass{ast.Greatest, 2, -1}},
+	ast.Least:      &leastFunctionClass{baseFunctionClass{ast.Least, 2, -1}},
+	ast.Interval:   &intervalFunctionClass{baseFunctionClass{ast.Interval, 2, -1}},
 	ast.Plus:       &arithmeticPlusFunctionClass{baseFunctionClass{ast.Plus, 2, 2}},
 	ast.Minus:      &arithmeticMinusFunctionClass{baseFunctionClass{ast.Minus, 2, 2}},
 	ast.Div:        &arithmeticDivideFunctionClass{baseFunctionClass{ast.Div, 2, 2}},
@@ -360,6 +425,33 @@ var funcs = map[string]functionClass{
 	ast.RowFunc:    &rowFunctionClass{baseFunctionClass{ast.RowFunc, 2, -1}},
 	ast.SetVar:     &setVarFunctionClass{baseFunctionClass{ast.SetVar, 2, 2}},
 	ast.GetVar:     &getVarFunctionClass{baseFunctionClass{ast.GetVar, 1, 1}},
+	ast.Grouping:   &groupingFunctionClass{baseFunctionClass{ast.Grouping, 2, -1}},
+	ast.UUID:       &uuidFunctionClass{baseFunctionClass{ast.UUID, 0, 0}},
+	ast.UUIDShort:  &uuidShortFunctionClass{baseFunctionClass{ast.UUIDShort, 0, 0}},
+	ast.IsUUID:     &isUUIDFunctionClass{baseFunctionClass{ast.IsUUID, 1, 1}},
+	ast.UUIDToBin:  &uuidToBinFunctionClass{baseFunctionClass{ast.UUIDToBin, 1, 2}},
+	ast.BinToUUID:  &binToUUIDFunctionClass{baseFunctionClass{ast.BinToUUID, 1, 2}},
+
+	// information functions
+	ast.Database:     &databaseFunctionClass{baseFunctionClass{ast.Database, 0, 0}},
+	ast.Schema:       &databaseFunctionClass{baseFunctionClass{ast.Schema, 0, 0}},
+	ast.Version:      &versionFunctionClass{baseFunctionClass{ast.Version, 0, 0}},
+	ast.ConnectionID: &connectionIDFunctionClass{baseFunctionClass{ast.ConnectionID, 0, 0}},
+	ast.User:         &userFunctionClass{baseFunctionClass{ast.User, 0, 0}},
+	ast.SessionUser:  &userFunctionClass{baseFunctionClass{ast.SessionUser, 0, 0}},
+	ast.SystemUser:   &userFunctionClass{baseFunctionClass{ast.SystemUser, 0, 0}},
+	ast.CurrentUser:  &currentUserFunctionClass{baseFunctionClass{ast.CurrentUser, 0, 0}},
+	ast.CurrentRole:  &currentRoleFunctionClass{baseFunctionClass{ast.CurrentRole, 0, 0}},
+
+	// time functions
+	ast.UnixTimestamp: &unixTimestampFunctionClass{baseFunctionClass{ast.UnixTimestamp, 0, 0}},
+
+	// json functions
+	ast.JSONType:     &jsonTypeFunctionClass{baseFunctionClass{ast.JSONType, 1, 1}},
+	ast.JSONExtract:  &jsonExtractFunctionClass{baseFunctionClass{ast.JSONExtract, 2, -1}},
+	ast.JSONUnquote:  &jsonUnquoteFunctionClass{baseFunctionClass{ast.JSONUnquote, 1, 1}},
+	ast.JSONSet:      &jsonSetFunctionClass{baseFunctionClass{ast.JSONSet, 3, -1}},
+	ast.JSONContains: &jsonContainsFunctionClass{baseFunctionClass{ast.JSONContains, 2, 3}},
 }
 
 // IsFunctionSupported check if given function name is a builtin sql function.
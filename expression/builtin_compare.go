@@ -15,17 +15,23 @@ package expression
 
 import (
 	"math"
+	"strconv"
 
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tipb/go-tipb"
 )
 
 var (
 	_ functionClass = &compareFunctionClass{}
+	_ functionClass = &greatestFunctionClass{}
+	_ functionClass = &leastFunctionClass{}
+	_ functionClass = &intervalFunctionClass{}
 )
 
 var (
@@ -48,6 +54,21 @@ var (
 	_ builtinFunc = &builtinNEIntSig{}
 	_ builtinFunc = &builtinNERealSig{}
 	_ builtinFunc = &builtinNEStringSig{}
+
+	_ builtinFunc = &builtinNullEQIntSig{}
+	_ builtinFunc = &builtinNullEQRealSig{}
+	_ builtinFunc = &builtinNullEQStringSig{}
+
+	_ builtinFunc = &builtinGreatestIntSig{}
+	_ builtinFunc = &builtinGreatestRealSig{}
+	_ builtinFunc = &builtinGreatestStringSig{}
+
+	_ builtinFunc = &builtinLeastIntSig{}
+	_ builtinFunc = &builtinLeastRealSig{}
+	_ builtinFunc = &builtinLeastStringSig{}
+
+	_ builtinFunc = &builtinIntervalIntSig{}
+	_ builtinFunc = &builtinIntervalRealSig{}
 )
 
 type compareFunctionClass struct {
@@ -133,6 +154,9 @@ func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Ex
 		case opcode.NE:
 			sig = &builtinNEIntSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_NEInt)
+		case opcode.NullEQ:
+			sig = &builtinNullEQIntSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQInt)
 		}
 	case types.ETReal:
 		switch c.op {
@@ -154,6 +178,9 @@ func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Ex
 		case opcode.NE:
 			sig = &builtinNERealSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_NEReal)
+		case opcode.NullEQ:
+			sig = &builtinNullEQRealSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQReal)
 		}
 	case types.ETString:
 		switch c.op {
@@ -175,6 +202,9 @@ func (c *compareFunctionClass) generateCmpSigs(ctx sessionctx.Context, args []Ex
 		case opcode.NE:
 			sig = &builtinNEStringSig{bf}
 			sig.setPbCode(tipb.ScalarFuncSig_NEString)
+		case opcode.NullEQ:
+			sig = &builtinNullEQStringSig{bf}
+			sig.setPbCode(tipb.ScalarFuncSig_NullEQString)
 		}
 	}
 	return
@@ -219,7 +249,7 @@ func (b *builtinLTStringSig) Clone() builtinFunc {
 }
 
 func (b *builtinLTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+	return resOfLT(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
 }
 
 type builtinLEIntSig struct {
@@ -261,7 +291,7 @@ func (b *builtinLEStringSig) Clone() builtinFunc {
 }
 
 func (b *builtinLEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfLE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+	return resOfLE(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
 }
 
 type builtinGTIntSig struct {
@@ -303,7 +333,7 @@ func (b *builtinGTStringSig) Clone() builtinFunc {
 }
 
 func (b *builtinGTStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGT(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+	return resOfGT(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
 }
 
 type builtinGEIntSig struct {
@@ -345,7 +375,7 @@ func (b *builtinGEStringSig) Clone() builtinFunc {
 }
 
 func (b *builtinGEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfGE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+	return resOfGE(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
 }
 
 type builtinEQIntSig struct {
@@ -387,7 +417,7 @@ func (b *builtinEQStringSig) Clone() builtinFunc {
 }
 
 func (b *builtinEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfEQ(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+	return resOfEQ(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
 }
 
 type builtinNEIntSig struct {
@@ -429,7 +459,525 @@ func (b *builtinNEStringSig) Clone() builtinFunc {
 }
 
 func (b *builtinNEStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
-	return resOfNE(CompareString(b.ctx, b.args[0], b.args[1], row, row))
+	return resOfNE(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
+}
+
+type builtinNullEQIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQIntSig) Clone() builtinFunc {
+	newSig := &builtinNullEQIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareInt(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQRealSig) Clone() builtinFunc {
+	newSig := &builtinNullEQRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQRealSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(CompareReal(b.ctx, b.args[0], b.args[1], row, row))
+}
+
+type builtinNullEQStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinNullEQStringSig) Clone() builtinFunc {
+	newSig := &builtinNullEQStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinNullEQStringSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	return resOfNullEQ(compareStringWithCollator(b.ctx, b.args[0], b.args[1], row, row, b.collator()))
+}
+
+// getCmpTp4MinMax gets the EvalType that the args of GREATEST and LEAST will be treated as when
+// comparing. It extends getBaseCmpType to multiple args: they are compared as strings if all of them
+// are strings, as integers if all of them are integers of the same signedness, and as reals otherwise.
+// The NULL constants don't take part, since they make the result NULL anyway.
+func getCmpTp4MinMax(args []Expression) types.EvalType {
+	cmpType, gotFirst, unsigned := types.ETString, false, false
+	for _, arg := range args {
+		ft := arg.GetType()
+		if ft.Tp == mysql.TypeNull {
+			continue
+		}
+		isUnsigned := mysql.HasUnsignedFlag(ft.Flag)
+		if !gotFirst {
+			gotFirst, unsigned = true, isUnsigned
+			cmpType = ft.EvalType()
+			if ft.Hybrid() {
+				cmpType = types.ETInt
+			}
+			continue
+		}
+		cmpType = getBaseCmpType(cmpType, ft.EvalType(), &types.FieldType{Tp: mysql.TypeLonglong}, ft)
+		if cmpType == types.ETInt && unsigned != isUnsigned {
+			// The int64 result can't hold both a negative and a large unsigned value.
+			cmpType = types.ETReal
+		}
+	}
+	if !cmpType.IsStringKind() && cmpType != types.ETInt {
+		cmpType = types.ETReal
+	}
+	return cmpType
+}
+
+// evalArgAsReal evaluates the arg as a real whatever its own type is. The args of the
+// builtin functions are not cast, so those taking the args of mixed types as reals
+// have to convert them themselves.
+func evalArgAsReal(ctx sessionctx.Context, arg Expression, row chunk.Row) (float64, bool, error) {
+	ft := arg.GetType()
+	if ft.EvalType() == types.ETInt || ft.Hybrid() {
+		v, isNull, err := arg.EvalInt(ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		if mysql.HasUnsignedFlag(ft.Flag) {
+			return float64(uint64(v)), false, nil
+		}
+		return float64(v), false, nil
+	}
+	if ft.EvalType() == types.ETReal {
+		return arg.EvalReal(ctx, row)
+	}
+	str, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	v, err := types.StrToFloat(ctx.GetSessionVars().StmtCtx, str)
+	return v, err != nil, err
+}
+
+// evalArgAsInt evaluates the arg as an int whatever its own type is. The args of the
+// other types are evaluated as reals and rounded, the overflowed values are clipped.
+func evalArgAsInt(ctx sessionctx.Context, arg Expression, row chunk.Row) (int64, bool, error) {
+	ft := arg.GetType()
+	if ft.EvalType() == types.ETInt || ft.Hybrid() {
+		return arg.EvalInt(ctx, row)
+	}
+	v, isNull, err := evalArgAsReal(ctx, arg, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return realToClippedInt(v), false, nil
+}
+
+// realToClippedInt rounds v to an int, the overflowed value is clipped to the bounds.
+func realToClippedInt(v float64) int64 {
+	// The overflow error is ignored since the bound is returned with it.
+	res, _ := types.ConvertFloatToInt(v, math.MinInt64, math.MaxInt64, mysql.TypeLonglong)
+	return res
+}
+
+// evalArgAsString evaluates the arg as a string whatever its own type is.
+func evalArgAsString(ctx sessionctx.Context, arg Expression, row chunk.Row) (string, bool, error) {
+	ft := arg.GetType()
+	switch ft.EvalType() {
+	case types.ETInt:
+		v, isNull, err := arg.EvalInt(ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return intToString(v, mysql.HasUnsignedFlag(ft.Flag)), false, nil
+	case types.ETReal:
+		v, isNull, err := arg.EvalReal(ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), false, nil
+	}
+	return arg.EvalString(ctx, row)
+}
+
+func intToString(v int64, unsigned bool) string {
+	if unsigned {
+		return strconv.FormatUint(uint64(v), 10)
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// argEvalTypes returns the EvalTypes of the args, which are converted when evaluated.
+func argEvalTypes(args []Expression) []types.EvalType {
+	argTps := make([]types.EvalType, len(args))
+	for i, arg := range args {
+		argTps[i] = arg.GetType().EvalType()
+	}
+	return argTps
+}
+
+// newBaseBuiltinFunc4MinMax creates the baseBuiltinFunc of GREATEST or LEAST, whose result has the
+// same type as the compared args.
+func newBaseBuiltinFunc4MinMax(ctx sessionctx.Context, args []Expression) (baseBuiltinFunc, types.EvalType) {
+	cmpType := getCmpTp4MinMax(args)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, cmpType, argEvalTypes(args)...)
+	switch cmpType {
+	case types.ETInt:
+		allUnsigned := true
+		bf.tp.Flen = 0
+		for _, arg := range args {
+			if arg.GetType().Tp == mysql.TypeNull {
+				continue
+			}
+			allUnsigned = allUnsigned && mysql.HasUnsignedFlag(arg.GetType().Flag)
+			if arg.GetType().Flen > bf.tp.Flen {
+				bf.tp.Flen = arg.GetType().Flen
+			}
+		}
+		if allUnsigned {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+	case types.ETString:
+		for _, arg := range args {
+			if arg.GetType().Flen > bf.tp.Flen {
+				bf.tp.Flen = arg.GetType().Flen
+			}
+		}
+	}
+	return bf, cmpType
+}
+
+type greatestFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *greatestFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, cmpType := newBaseBuiltinFunc4MinMax(ctx, args)
+	switch cmpType {
+	case types.ETInt:
+		sig = &builtinGreatestIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestInt)
+	case types.ETReal:
+		sig = &builtinGreatestRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestReal)
+	default:
+		sig = &builtinGreatestStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_GreatestString)
+	}
+	return sig, nil
+}
+
+// builtinGreatestIntSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_greatest
+type builtinGreatestIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestIntSig) Clone() builtinFunc {
+	newSig := &builtinGreatestIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGreatestIntSig) evalInt(row chunk.Row) (max int64, isNull bool, err error) {
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || (isUnsigned && uint64(v) > uint64(max)) || (!isUnsigned && v > max) {
+			max = v
+		}
+	}
+	return max, false, nil
+}
+
+type builtinGreatestRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestRealSig) Clone() builtinFunc {
+	newSig := &builtinGreatestRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGreatestRealSig) evalReal(row chunk.Row) (max float64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := evalArgAsReal(b.ctx, arg, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || v > max {
+			max = v
+		}
+	}
+	return max, false, nil
+}
+
+type builtinGreatestStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGreatestStringSig) Clone() builtinFunc {
+	newSig := &builtinGreatestStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGreatestStringSig) evalString(row chunk.Row) (max string, isNull bool, err error) {
+	collator := b.collator()
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+		if i == 0 || collator.Compare(v, max) > 0 {
+			max = v
+		}
+	}
+	return max, false, nil
+}
+
+type leastFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leastFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, cmpType := newBaseBuiltinFunc4MinMax(ctx, args)
+	switch cmpType {
+	case types.ETInt:
+		sig = &builtinLeastIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastInt)
+	case types.ETReal:
+		sig = &builtinLeastRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastReal)
+	default:
+		sig = &builtinLeastStringSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_LeastString)
+	}
+	return sig, nil
+}
+
+// builtinLeastIntSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_least
+type builtinLeastIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastIntSig) Clone() builtinFunc {
+	newSig := &builtinLeastIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLeastIntSig) evalInt(row chunk.Row) (min int64, isNull bool, err error) {
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || (isUnsigned && uint64(v) < uint64(min)) || (!isUnsigned && v < min) {
+			min = v
+		}
+	}
+	return min, false, nil
+}
+
+type builtinLeastRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastRealSig) Clone() builtinFunc {
+	newSig := &builtinLeastRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLeastRealSig) evalReal(row chunk.Row) (min float64, isNull bool, err error) {
+	for i, arg := range b.args {
+		v, isNull, err := evalArgAsReal(b.ctx, arg, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+		if i == 0 || v < min {
+			min = v
+		}
+	}
+	return min, false, nil
+}
+
+type builtinLeastStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeastStringSig) Clone() builtinFunc {
+	newSig := &builtinLeastStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLeastStringSig) evalString(row chunk.Row) (min string, isNull bool, err error) {
+	collator := b.collator()
+	for i, arg := range b.args {
+		v, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+		if i == 0 || collator.Compare(v, min) < 0 {
+			min = v
+		}
+	}
+	return min, false, nil
+}
+
+type intervalFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *intervalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err = c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	allInt, allConst := true, true
+	for i, arg := range args {
+		if arg.GetType().EvalType() != types.ETInt {
+			allInt = false
+		}
+		if _, ok := arg.(*Constant); i > 0 && !ok {
+			allConst = false
+		}
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argEvalTypes(args)...)
+	bf.tp.Flen = 2
+	if allInt {
+		sig = &builtinIntervalIntSig{bf, allConst}
+		sig.setPbCode(tipb.ScalarFuncSig_IntervalInt)
+	} else {
+		sig = &builtinIntervalRealSig{bf, allConst}
+		sig.setPbCode(tipb.ScalarFuncSig_IntervalReal)
+	}
+	return sig, nil
+}
+
+// builtinIntervalIntSig see https://dev.mysql.com/doc/refman/5.7/en/comparison-operators.html#function_interval
+type builtinIntervalIntSig struct {
+	baseBuiltinFunc
+
+	// constThresholds indicates all the thresholds are constants, they are then
+	// required to be ascending and are binary searched.
+	constThresholds bool
+}
+
+func (b *builtinIntervalIntSig) Clone() builtinFunc {
+	newSig := &builtinIntervalIntSig{constThresholds: b.constThresholds}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIntervalIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	target, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil {
+		return 0, true, err
+	}
+	if isNull {
+		return -1, false, nil
+	}
+	isUnsigned0 := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	// less reports whether target is less than the threshold, a NULL threshold is
+	// never greater than the target.
+	less := func(arg Expression) (bool, error) {
+		v, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return false, err
+		}
+		isUnsigned := mysql.HasUnsignedFlag(arg.GetType().Flag)
+		switch {
+		case isUnsigned0 && isUnsigned:
+			return uint64(target) < uint64(v), nil
+		case isUnsigned0 && !isUnsigned:
+			return v >= 0 && uint64(target) < uint64(v), nil
+		case !isUnsigned0 && isUnsigned:
+			return target < 0 || uint64(target) < uint64(v), nil
+		}
+		return target < v, nil
+	}
+	idx, err := searchInterval(b.args[1:], b.constThresholds, less)
+	return int64(idx), err != nil, err
+}
+
+type builtinIntervalRealSig struct {
+	baseBuiltinFunc
+
+	// constThresholds indicates all the thresholds are constants, they are then
+	// required to be ascending and are binary searched.
+	constThresholds bool
+}
+
+func (b *builtinIntervalRealSig) Clone() builtinFunc {
+	newSig := &builtinIntervalRealSig{constThresholds: b.constThresholds}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIntervalRealSig) evalInt(row chunk.Row) (int64, bool, error) {
+	target, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if err != nil {
+		return 0, true, err
+	}
+	if isNull {
+		return -1, false, nil
+	}
+	less := func(arg Expression) (bool, error) {
+		v, isNull, err := evalArgAsReal(b.ctx, arg, row)
+		if isNull || err != nil {
+			return false, err
+		}
+		return target < v, nil
+	}
+	idx, err := searchInterval(b.args[1:], b.constThresholds, less)
+	return int64(idx), err != nil, err
+}
+
+// searchInterval returns the number of thresholds before the first one that
+// the target is less than. The ascending constant thresholds are binary searched,
+// otherwise they are scanned one by one like MySQL does.
+func searchInterval(thresholds []Expression, binary bool, less func(Expression) (bool, error)) (int, error) {
+	if binary {
+		i, j := 0, len(thresholds)
+		for i < j {
+			mid := i + (j-i)/2
+			isLess, err := less(thresholds[mid])
+			if err != nil {
+				return 0, err
+			}
+			if isLess {
+				j = mid
+			} else {
+				i = mid + 1
+			}
+		}
+		return i, nil
+	}
+	for i, threshold := range thresholds {
+		isLess, err := less(threshold)
+		if err != nil {
+			return 0, err
+		}
+		if isLess {
+			return i, nil
+		}
+	}
+	return len(thresholds), nil
 }
 
 func resOfLT(val int64, isNull bool, err error) (int64, bool, error) {
@@ -504,6 +1052,20 @@ func resOfNE(val int64, isNull bool, err error) (int64, bool, error) {
 	return val, false, nil
 }
 
+// resOfNullEQ is different from the others: the NULL values are compared
+// too, see compareNull, so the result is never NULL.
+func resOfNullEQ(val int64, isNull bool, err error) (int64, bool, error) {
+	if err != nil {
+		return 0, true, err
+	}
+	if val == 0 {
+		val = 1
+	} else {
+		val = 0
+	}
+	return val, false, nil
+}
+
 // compareNull compares null values based on the following rules.
 // 1. NULL is considered to be equal to NULL
 // 2. NULL is considered to be smaller than a non-NULL value.
@@ -561,8 +1123,13 @@ func CompareInt(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsR
 	return int64(res), false, nil
 }
 
-// CompareString compares two strings.
+// CompareString compares two strings by their bytes.
 func CompareString(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row) (int64, bool, error) {
+	return compareStringWithCollator(sctx, lhsArg, rhsArg, lhsRow, rhsRow, collate.GetCollator(charset.CollationBin))
+}
+
+// compareStringWithCollator compares two strings with the collator.
+func compareStringWithCollator(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, rhsRow chunk.Row, collator collate.Collator) (int64, bool, error) {
 	arg0, isNull0, err := lhsArg.EvalString(sctx, lhsRow)
 	if err != nil {
 		return 0, true, err
@@ -576,7 +1143,7 @@ func CompareString(sctx sessionctx.Context, lhsArg, rhsArg Expression, lhsRow, r
 	if isNull0 || isNull1 {
 		return compareNull(isNull0, isNull1), true, nil
 	}
-	return int64(types.CompareString(arg0, arg1)), false, nil
+	return int64(collator.Compare(arg0, arg1)), false, nil
 }
 
 // CompareReal compares two float-point values.
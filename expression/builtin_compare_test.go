@@ -14,9 +14,12 @@
 package expression
 
 import (
+	"math"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -34,6 +37,9 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		{stringVal, stringVal, ast.LT, mysql.TypeVarString, 0},
 		{realVal, realVal, ast.LT, mysql.TypeDouble, 0},
 		{uintVal, uintVal, ast.EQ, mysql.TypeLonglong, 1},
+		{intVal, intVal, ast.NullEQ, mysql.TypeLonglong, 1},
+		{stringVal, "12", ast.NullEQ, mysql.TypeVarString, 0},
+		{realVal, realVal, ast.NullEQ, mysql.TypeDouble, 1},
 	}
 
 	for _, t := range tests {
@@ -48,3 +54,109 @@ func (s *testEvaluatorSuite) TestCompare(c *C) {
 		c.Assert(res, Equals, t.expected)
 	}
 }
+
+func (s *testEvaluatorSuite) TestNullEQ(c *C) {
+	tests := []struct {
+		arg0     interface{}
+		arg1     interface{}
+		expected int64
+	}{
+		{1, 1, 1},
+		{1, 2, 0},
+		{1, nil, 0},
+		{nil, "a", 0},
+		{nil, nil, 1},
+		{1.5, nil, 0},
+		{"abc", "abc", 1},
+	}
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.NullEQ, s.primitiveValsToConstants([]interface{}{t.arg0, t.arg1})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsFalse, Commentf("%v <=> %v", t.arg0, t.arg1))
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("%v <=> %v", t.arg0, t.arg1))
+	}
+}
+
+func (s *testEvaluatorSuite) TestGreatestLeast(c *C) {
+	tests := []struct {
+		args     []interface{}
+		retTp    types.EvalType
+		greatest interface{}
+		least    interface{}
+	}{
+		{[]interface{}{1, 3, 2}, types.ETInt, int64(3), int64(1)},
+		{[]interface{}{-1, 0}, types.ETInt, int64(0), int64(-1)},
+		{[]interface{}{uint64(1), uint64(math.MaxUint64)}, types.ETInt, uint64(math.MaxUint64), uint64(1)},
+		{[]interface{}{-1, uint64(math.MaxUint64)}, types.ETReal, float64(math.MaxUint64), float64(-1)},
+		{[]interface{}{1.5, 2, -3}, types.ETReal, float64(2), float64(-3)},
+		{[]interface{}{"a", "c", "b"}, types.ETString, "c", "a"},
+		{[]interface{}{"10", "9"}, types.ETString, "9", "10"},
+		{[]interface{}{"10", 9}, types.ETReal, float64(10), float64(9)},
+		{[]interface{}{"1.5", 1, 2.5}, types.ETReal, float64(2.5), float64(1)},
+		{[]interface{}{1, nil, 3}, types.ETInt, nil, nil},
+		{[]interface{}{nil, nil}, types.ETString, nil, nil},
+	}
+	for _, t := range tests {
+		for _, funcName := range []string{ast.Greatest, ast.Least} {
+			f, err := newFunctionForTest(s.ctx, funcName, s.primitiveValsToConstants(t.args)...)
+			c.Assert(err, IsNil)
+			c.Assert(f.GetType().EvalType(), Equals, t.retTp, Commentf("%s%v", funcName, t.args))
+			d, err := f.Eval(chunk.Row{})
+			c.Assert(err, IsNil)
+			expected := t.greatest
+			if funcName == ast.Least {
+				expected = t.least
+			}
+			c.Assert(d.GetValue(), Equals, expected, Commentf("%s%v", funcName, t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestInterval(c *C) {
+	col0 := &Column{RetType: types.NewFieldType(mysql.TypeLonglong), Index: 0}
+	col1 := &Column{RetType: types.NewFieldType(mysql.TypeLonglong), Index: 1}
+	tests := []struct {
+		args     []interface{}
+		expected int64
+	}{
+		{[]interface{}{23, 1, 15, 17, 30, 44, 200}, 3},
+		{[]interface{}{10, 1, 10, 100, 1000}, 2},
+		{[]interface{}{22, 23, 30, 44, 200}, 0},
+		{[]interface{}{300, 23, 30, 44, 200}, 4},
+		{[]interface{}{nil, 1, 2}, -1},
+		{[]interface{}{-1, uint64(math.MaxUint64)}, 0},
+		{[]interface{}{uint64(math.MaxUint64), -1}, 1},
+		{[]interface{}{1.5, 1, 2}, 1},
+		{[]interface{}{"2.5", 1, 2, 3}, 2},
+		{[]interface{}{2, 1, nil, 3}, 2},
+	}
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.Interval, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("interval%v", t.args))
+	}
+
+	// The non-constant thresholds are scanned one by one, skipping the NULL ones.
+	f, err := newFunctionForTest(s.ctx, ast.Interval, col0, s.primitiveValsToConstants([]interface{}{1})[0], col1)
+	c.Assert(err, IsNil)
+	c.Assert(f.(*ScalarFunction).Function.(*builtinIntervalIntSig).constThresholds, IsFalse)
+	rowTests := []struct {
+		row      []interface{}
+		expected int64
+	}{
+		{[]interface{}{0, 5}, 0},
+		{[]interface{}{3, 5}, 1},
+		{[]interface{}{7, 5}, 2},
+		{[]interface{}{7, nil}, 2},
+		{[]interface{}{nil, 5}, -1},
+	}
+	for _, t := range rowTests {
+		d, err := f.Eval(chunk.MutRowFromDatums(types.MakeDatums(t.row...)).ToRow())
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("row %v", t.row))
+	}
+}
@@ -14,11 +14,130 @@
 package expression
 
 import (
+	"strconv"
+
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
+// vecEvalArgAsReal is the vectorized version of evalArgAsReal.
+func vecEvalArgAsReal(ctx sessionctx.Context, arg Expression, input *chunk.Chunk, result *chunk.Column) error {
+	ft := arg.GetType()
+	if ft.EvalType() == types.ETReal {
+		return arg.VecEvalReal(ctx, input, result)
+	}
+	n := input.NumRows()
+	if ft.EvalType() == types.ETInt || ft.Hybrid() {
+		buf, err := GetColumn(types.ETInt, n)
+		if err != nil {
+			return err
+		}
+		defer PutColumn(buf)
+		if err := arg.VecEvalInt(ctx, input, buf); err != nil {
+			return err
+		}
+		result.ResizeFloat64(n, false)
+		result.MergeNulls(buf)
+		i64s, f64s := buf.Int64s(), result.Float64s()
+		if mysql.HasUnsignedFlag(ft.Flag) {
+			for i := 0; i < n; i++ {
+				f64s[i] = float64(uint64(i64s[i]))
+			}
+			return nil
+		}
+		for i := 0; i < n; i++ {
+			f64s[i] = float64(i64s[i])
+		}
+		return nil
+	}
+
+	buf, err := GetColumn(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer PutColumn(buf)
+	if err := arg.VecEvalString(ctx, input, buf); err != nil {
+		return err
+	}
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	f64s := result.Float64s()
+	sc := ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			continue
+		}
+		if f64s[i], err = types.StrToFloat(sc, buf.GetString(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// vecEvalArgAsInt is the vectorized version of evalArgAsInt.
+func vecEvalArgAsInt(ctx sessionctx.Context, arg Expression, input *chunk.Chunk, result *chunk.Column) error {
+	ft := arg.GetType()
+	if ft.EvalType() == types.ETInt || ft.Hybrid() {
+		return arg.VecEvalInt(ctx, input, result)
+	}
+	n := input.NumRows()
+	buf, err := GetColumn(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer PutColumn(buf)
+	if err := vecEvalArgAsReal(ctx, arg, input, buf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	f64s, i64s := buf.Float64s(), result.Int64s()
+	for i := 0; i < n; i++ {
+		if !buf.IsNull(i) {
+			i64s[i] = realToClippedInt(f64s[i])
+		}
+	}
+	return nil
+}
+
+// vecEvalArgAsString is the vectorized version of evalArgAsString.
+func vecEvalArgAsString(ctx sessionctx.Context, arg Expression, input *chunk.Chunk, result *chunk.Column) error {
+	ft := arg.GetType()
+	evalType := ft.EvalType()
+	if evalType != types.ETInt && evalType != types.ETReal {
+		return arg.VecEvalString(ctx, input, result)
+	}
+	n := input.NumRows()
+	buf, err := GetColumn(evalType, n)
+	if err != nil {
+		return err
+	}
+	defer PutColumn(buf)
+	if evalType == types.ETInt {
+		err = arg.VecEvalInt(ctx, input, buf)
+	} else {
+		err = arg.VecEvalReal(ctx, input, buf)
+	}
+	if err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	unsigned := mysql.HasUnsignedFlag(ft.Flag)
+	for i := 0; i < n; i++ {
+		switch {
+		case buf.IsNull(i):
+			result.AppendNull()
+		case evalType == types.ETInt:
+			result.AppendString(intToString(buf.GetInt64(i), unsigned))
+		default:
+			result.AppendString(strconv.FormatFloat(buf.GetFloat64(i), 'f', -1, 64))
+		}
+	}
+	return nil
+}
+
 func (b *builtinGEIntSig) vectorized() bool {
 	return true
 }
@@ -115,6 +234,45 @@ func (b *builtinNEIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) e
 	return nil
 }
 
+func (b *builtinNullEQIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinNullEQIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	var err error
+	var buf0, buf1 *chunk.Column
+	buf0, err = b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalInt(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err = b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	vecCompareInt(mysql.HasUnsignedFlag(b.args[0].GetType().Flag), mysql.HasUnsignedFlag(b.args[1].GetType().Flag), buf0, buf1, result)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		isNull0 := buf0.IsNull(i)
+		isNull1 := buf1.IsNull(i)
+		if isNull0 || isNull1 {
+			i64s[i] = compareNull(isNull0, isNull1)
+		}
+	}
+	vecResOfEQ(i64s)
+	return nil
+}
+
 func (b *builtinGTIntSig) vectorized() bool {
 	return true
 }
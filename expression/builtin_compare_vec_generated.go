@@ -81,6 +81,7 @@ func (b *builtinLTStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		return err
 	}
 
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
 	i64s := result.Int64s()
@@ -88,7 +89,7 @@ func (b *builtinLTStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		if result.IsNull(i) {
 			continue
 		}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 		if val < 0 {
 			i64s[i] = 1
 		} else {
@@ -163,6 +164,7 @@ func (b *builtinLEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		return err
 	}
 
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
 	i64s := result.Int64s()
@@ -170,7 +172,7 @@ func (b *builtinLEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		if result.IsNull(i) {
 			continue
 		}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 		if val <= 0 {
 			i64s[i] = 1
 		} else {
@@ -245,6 +247,7 @@ func (b *builtinGTStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		return err
 	}
 
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
 	i64s := result.Int64s()
@@ -252,7 +255,7 @@ func (b *builtinGTStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		if result.IsNull(i) {
 			continue
 		}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 		if val > 0 {
 			i64s[i] = 1
 		} else {
@@ -327,6 +330,7 @@ func (b *builtinGEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		return err
 	}
 
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
 	i64s := result.Int64s()
@@ -334,7 +338,7 @@ func (b *builtinGEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		if result.IsNull(i) {
 			continue
 		}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 		if val >= 0 {
 			i64s[i] = 1
 		} else {
@@ -409,6 +413,7 @@ func (b *builtinEQStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		return err
 	}
 
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
 	i64s := result.Int64s()
@@ -416,7 +421,7 @@ func (b *builtinEQStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		if result.IsNull(i) {
 			continue
 		}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 		if val == 0 {
 			i64s[i] = 1
 		} else {
@@ -491,6 +496,7 @@ func (b *builtinNEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		return err
 	}
 
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
 	i64s := result.Int64s()
@@ -498,7 +504,7 @@ func (b *builtinNEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		if result.IsNull(i) {
 			continue
 		}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 		if val != 0 {
 			i64s[i] = 1
 		} else {
@@ -511,3 +517,90 @@ func (b *builtinNEStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 func (b *builtinNEStringSig) vectorized() bool {
 	return true
 }
+
+func (b *builtinNullEQRealSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalReal(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[1].VecEvalReal(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+	arg0 := buf0.Float64s()
+	arg1 := buf1.Float64s()
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		isNull0 := buf0.IsNull(i)
+		isNull1 := buf1.IsNull(i)
+		switch {
+		case isNull0 && isNull1:
+			i64s[i] = 1
+		case isNull0 != isNull1:
+			i64s[i] = 0
+		case types.CompareFloat64(arg0[i], arg1[i]) == 0:
+			i64s[i] = 1
+		default:
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinNullEQRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinNullEQStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+	collator := b.collator()
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		isNull0 := buf0.IsNull(i)
+		isNull1 := buf1.IsNull(i)
+		switch {
+		case isNull0 && isNull1:
+			i64s[i] = 1
+		case isNull0 != isNull1:
+			i64s[i] = 0
+		case collator.Compare(buf0.GetString(i), buf1.GetString(i)) == 0:
+			i64s[i] = 1
+		default:
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinNullEQStringSig) vectorized() bool {
+	return true
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalOnRows calls eval with the input selecting only the rows whose selected[i] equals to value, so the
+// result of eval has one value for each of these rows. The control functions use it to evaluate their
+// branches lazily like the row-based evaluation, the rows which don't choose a branch never produce the
+// errors or the warnings of it.
+func vecEvalOnRows(input *chunk.Chunk, selected []bool, value bool, eval func() error) error {
+	n := input.NumRows()
+	oldSel := input.Sel()
+	sel := allocSelSlice(n)
+	defer deallocateSelSlice(sel)
+	sel = sel[:0]
+	for i := 0; i < n; i++ {
+		if selected[i] != value {
+			continue
+		}
+		if oldSel != nil {
+			sel = append(sel, oldSel[i])
+		} else {
+			sel = append(sel, i)
+		}
+	}
+	switch len(sel) {
+	case 0:
+		return nil
+	case n:
+		return eval()
+	}
+	input.SetSel(sel)
+	defer input.SetSel(oldSel)
+	return eval()
+}
+
+// vecIsTrue returns whether the conditions of the rows are true, a NULL condition isn't true.
+func vecIsTrue(cond *chunk.Column, n int) []bool {
+	conds := cond.Int64s()
+	isTrue := make([]bool, n)
+	for i := 0; i < n; i++ {
+		isTrue[i] = !cond.IsNull(i) && conds[i] != 0
+	}
+	return isTrue
+}
+
+// vecIsNull returns whether the values of the rows are NULL.
+func vecIsNull(col *chunk.Column, n int) []bool {
+	isNull := make([]bool, n)
+	for i := 0; i < n; i++ {
+		isNull[i] = col.IsNull(i)
+	}
+	return isNull
+}
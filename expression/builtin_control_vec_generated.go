@@ -25,22 +25,30 @@ func (b *builtinIfNullIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Colum
 	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
 		return err
 	}
+	isNull := vecIsNull(result, n)
 	buf1, err := b.bufAllocator.get(types.ETInt, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEvalInt(b.ctx, input, buf1); err != nil {
+	err = vecEvalOnRows(input, isNull, true, func() error {
+		return b.args[1].VecEvalInt(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 
 	arg0 := result.Int64s()
 	arg1 := buf1.Int64s()
-	for i := 0; i < n; i++ {
-		if result.IsNull(i) && !buf1.IsNull(i) {
+	for i, j := 0, 0; i < n; i++ {
+		if !isNull[i] {
+			continue
+		}
+		if !buf1.IsNull(j) {
 			result.SetNull(i, false)
-			arg0[i] = arg1[i]
+			arg0[i] = arg1[j]
 		}
+		j++
 	}
 	return nil
 }
@@ -54,22 +62,30 @@ func (b *builtinIfNullRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Col
 	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
 		return err
 	}
+	isNull := vecIsNull(result, n)
 	buf1, err := b.bufAllocator.get(types.ETReal, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEvalReal(b.ctx, input, buf1); err != nil {
+	err = vecEvalOnRows(input, isNull, true, func() error {
+		return b.args[1].VecEvalReal(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 
 	arg0 := result.Float64s()
 	arg1 := buf1.Float64s()
-	for i := 0; i < n; i++ {
-		if result.IsNull(i) && !buf1.IsNull(i) {
+	for i, j := 0, 0; i < n; i++ {
+		if !isNull[i] {
+			continue
+		}
+		if !buf1.IsNull(j) {
 			result.SetNull(i, false)
-			arg0[i] = arg1[i]
+			arg0[i] = arg1[j]
 		}
+		j++
 	}
 	return nil
 }
@@ -88,24 +104,31 @@ func (b *builtinIfNullStringSig) vecEvalString(input *chunk.Chunk, result *chunk
 	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
 		return err
 	}
+	isNull := vecIsNull(buf0, n)
 	buf1, err := b.bufAllocator.get(types.ETString, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
+	err = vecEvalOnRows(input, isNull, true, func() error {
+		return b.args[1].VecEvalString(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 
 	result.ReserveString(n)
-	for i := 0; i < n; i++ {
-		if !buf0.IsNull(i) {
+	for i, j := 0, 0; i < n; i++ {
+		if !isNull[i] {
 			result.AppendString(buf0.GetString(i))
-		} else if !buf1.IsNull(i) {
-			result.AppendString(buf1.GetString(i))
-		} else {
+			continue
+		}
+		if buf1.IsNull(j) {
 			result.AppendNull()
+		} else {
+			result.AppendString(buf1.GetString(j))
 		}
+		j++
 	}
 	return nil
 }
@@ -124,7 +147,16 @@ func (b *builtinIfIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) e
 	if err := b.args[0].VecEvalInt(b.ctx, input, buf0); err != nil {
 		return err
 	}
-	if err := b.args[1].VecEvalInt(b.ctx, input, result); err != nil {
+	isTrue := vecIsTrue(buf0, n)
+	buf1, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	err = vecEvalOnRows(input, isTrue, true, func() error {
+		return b.args[1].VecEvalInt(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 	buf2, err := b.bufAllocator.get(types.ETInt, n)
@@ -132,26 +164,33 @@ func (b *builtinIfIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) e
 		return err
 	}
 	defer b.bufAllocator.put(buf2)
-	if err := b.args[2].VecEvalInt(b.ctx, input, buf2); err != nil {
+	err = vecEvalOnRows(input, isTrue, false, func() error {
+		return b.args[2].VecEvalInt(b.ctx, input, buf2)
+	})
+	if err != nil {
 		return err
 	}
 
-	arg0 := buf0.Int64s()
-	arg2 := buf2.Int64s()
+	result.ResizeInt64(n, false)
 	rs := result.Int64s()
-	for i := 0; i < n; i++ {
-		arg := arg0[i]
-		isNull0 := buf0.IsNull(i)
-		switch {
-		case isNull0 || arg == 0:
-			if buf2.IsNull(i) {
+	arg1 := buf1.Int64s()
+	arg2 := buf2.Int64s()
+	for i, j1, j2 := 0, 0, 0; i < n; i++ {
+		if isTrue[i] {
+			if buf1.IsNull(j1) {
 				result.SetNull(i, true)
 			} else {
-				result.SetNull(i, false)
-				rs[i] = arg2[i]
+				rs[i] = arg1[j1]
 			}
-		case arg != 0:
+			j1++
+			continue
+		}
+		if buf2.IsNull(j2) {
+			result.SetNull(i, true)
+		} else {
+			rs[i] = arg2[j2]
 		}
+		j2++
 	}
 	return nil
 }
@@ -170,7 +209,16 @@ func (b *builtinIfRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column)
 	if err := b.args[0].VecEvalInt(b.ctx, input, buf0); err != nil {
 		return err
 	}
-	if err := b.args[1].VecEvalReal(b.ctx, input, result); err != nil {
+	isTrue := vecIsTrue(buf0, n)
+	buf1, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	err = vecEvalOnRows(input, isTrue, true, func() error {
+		return b.args[1].VecEvalReal(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 	buf2, err := b.bufAllocator.get(types.ETReal, n)
@@ -178,26 +226,33 @@ func (b *builtinIfRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column)
 		return err
 	}
 	defer b.bufAllocator.put(buf2)
-	if err := b.args[2].VecEvalReal(b.ctx, input, buf2); err != nil {
+	err = vecEvalOnRows(input, isTrue, false, func() error {
+		return b.args[2].VecEvalReal(b.ctx, input, buf2)
+	})
+	if err != nil {
 		return err
 	}
 
-	arg0 := buf0.Int64s()
-	arg2 := buf2.Float64s()
+	result.ResizeFloat64(n, false)
 	rs := result.Float64s()
-	for i := 0; i < n; i++ {
-		arg := arg0[i]
-		isNull0 := buf0.IsNull(i)
-		switch {
-		case isNull0 || arg == 0:
-			if buf2.IsNull(i) {
+	arg1 := buf1.Float64s()
+	arg2 := buf2.Float64s()
+	for i, j1, j2 := 0, 0, 0; i < n; i++ {
+		if isTrue[i] {
+			if buf1.IsNull(j1) {
 				result.SetNull(i, true)
 			} else {
-				result.SetNull(i, false)
-				rs[i] = arg2[i]
+				rs[i] = arg1[j1]
 			}
-		case arg != 0:
+			j1++
+			continue
 		}
+		if buf2.IsNull(j2) {
+			result.SetNull(i, true)
+		} else {
+			rs[i] = arg2[j2]
+		}
+		j2++
 	}
 	return nil
 }
@@ -216,12 +271,16 @@ func (b *builtinIfStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Col
 	if err := b.args[0].VecEvalInt(b.ctx, input, buf0); err != nil {
 		return err
 	}
+	isTrue := vecIsTrue(buf0, n)
 	buf1, err := b.bufAllocator.get(types.ETString, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
+	err = vecEvalOnRows(input, isTrue, true, func() error {
+		return b.args[1].VecEvalString(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 	buf2, err := b.bufAllocator.get(types.ETString, n)
@@ -229,29 +288,30 @@ func (b *builtinIfStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Col
 		return err
 	}
 	defer b.bufAllocator.put(buf2)
-	if err := b.args[2].VecEvalString(b.ctx, input, buf2); err != nil {
+	err = vecEvalOnRows(input, isTrue, false, func() error {
+		return b.args[2].VecEvalString(b.ctx, input, buf2)
+	})
+	if err != nil {
 		return err
 	}
 
 	result.ReserveString(n)
-	arg0 := buf0.Int64s()
-	for i := 0; i < n; i++ {
-		arg := arg0[i]
-		isNull0 := buf0.IsNull(i)
-		switch {
-		case isNull0 || arg == 0:
-			if buf2.IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.AppendString(buf2.GetString(i))
-			}
-		case arg != 0:
-			if buf1.IsNull(i) {
+	for i, j1, j2 := 0, 0, 0; i < n; i++ {
+		if isTrue[i] {
+			if buf1.IsNull(j1) {
 				result.AppendNull()
 			} else {
-				result.AppendString(buf1.GetString(i))
+				result.AppendString(buf1.GetString(j1))
 			}
+			j1++
+			continue
+		}
+		if buf2.IsNull(j2) {
+			result.AppendNull()
+		} else {
+			result.AppendString(buf2.GetString(j2))
 		}
+		j2++
 	}
 	return nil
 }
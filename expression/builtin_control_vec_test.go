@@ -0,0 +1,74 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestVectorizedControlLazyEval(c *C) {
+	ft := types.NewFieldType(mysql.TypeDouble)
+	col0 := &Column{Index: 0, RetType: ft}
+	col1 := &Column{Index: 1, RetType: ft}
+	zero := &Constant{Value: types.NewFloat64Datum(0), RetType: ft}
+	one := &Constant{Value: types.NewFloat64Datum(1), RetType: ft}
+
+	input := chunk.New([]*types.FieldType{ft, ft}, 5, 5)
+	for _, v := range []interface{}{0.0, 2.0, 0.0, 4.0, nil} {
+		if v == nil {
+			input.AppendNull(0)
+		} else {
+			input.AppendFloat64(0, v.(float64))
+		}
+		input.AppendFloat64(1, 0)
+	}
+	sc := s.ctx.GetSessionVars().StmtCtx
+
+	// IF(c0 = 0, 0, 1 / c0) never divides by zero.
+	cond, err := newFunctionForTest(s.ctx, ast.EQ, col0, zero)
+	c.Assert(err, IsNil)
+	div, err := newFunctionForTest(s.ctx, ast.Div, one, col0)
+	c.Assert(err, IsNil)
+	ifFunc, err := newFunctionForTest(s.ctx, ast.If, cond, zero, div)
+	c.Assert(err, IsNil)
+	c.Assert(ifFunc.Vectorized(), IsTrue)
+	result := chunk.NewColumn(ft, 5)
+	c.Assert(ifFunc.VecEvalReal(s.ctx, input, result), IsNil)
+	c.Assert(sc.WarningCount(), Equals, uint16(0))
+	c.Assert(result.Float64s()[:4], DeepEquals, []float64{0, 0.5, 0, 0.25})
+	c.Assert(result.IsNull(4), IsTrue)
+
+	// The rows filtered by the selection aren't evaluated either.
+	input.SetSel([]int{1, 3, 4})
+	c.Assert(ifFunc.VecEvalReal(s.ctx, input, result), IsNil)
+	c.Assert(sc.WarningCount(), Equals, uint16(0))
+	c.Assert(result.Float64s()[:2], DeepEquals, []float64{0.5, 0.25})
+	c.Assert(result.IsNull(2), IsTrue)
+	c.Assert(input.Sel(), DeepEquals, []int{1, 3, 4})
+	input.SetSel(nil)
+
+	// IFNULL(c0, 1 / c1) divides by zero only for the NULL c0.
+	div, err = newFunctionForTest(s.ctx, ast.Div, one, col1)
+	c.Assert(err, IsNil)
+	ifNullFunc, err := newFunctionForTest(s.ctx, ast.Ifnull, col0, div)
+	c.Assert(err, IsNil)
+	c.Assert(ifNullFunc.VecEvalReal(s.ctx, input, result), IsNil)
+	c.Assert(sc.WarningCount(), Equals, uint16(1))
+	c.Assert(result.Float64s()[:4], DeepEquals, []float64{0, 2, 0, 4})
+	c.Assert(result.IsNull(4), IsTrue)
+}
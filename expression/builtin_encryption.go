@@ -0,0 +1,459 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/encrypt"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &md5FunctionClass{}
+	_ functionClass = &sha1FunctionClass{}
+	_ functionClass = &sha2FunctionClass{}
+	_ functionClass = &crc32FunctionClass{}
+	_ functionClass = &aesEncryptFunctionClass{}
+	_ functionClass = &aesDecryptFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinMD5Sig{}
+	_ builtinFunc = &builtinSHA1Sig{}
+	_ builtinFunc = &builtinSHA2Sig{}
+	_ builtinFunc = &builtinCRC32Sig{}
+	_ builtinFunc = &builtinAesEncryptSig{}
+	_ builtinFunc = &builtinAesDecryptSig{}
+)
+
+// aesModeAttr indicates the block encryption mode used by AES_ENCRYPT and
+// AES_DECRYPT, it is parsed from the block_encryption_mode variable.
+type aesModeAttr struct {
+	modeName   string
+	keySize    int
+	ivRequired bool
+	padded     bool
+}
+
+// aesModes are the supported modes of block_encryption_mode.
+var aesModes = map[string]*aesModeAttr{
+	"aes-128-ecb":    {"ecb", 16, false, true},
+	"aes-192-ecb":    {"ecb", 24, false, true},
+	"aes-256-ecb":    {"ecb", 32, false, true},
+	"aes-128-cbc":    {"cbc", 16, true, true},
+	"aes-192-cbc":    {"cbc", 24, true, true},
+	"aes-256-cbc":    {"cbc", 32, true, true},
+	"aes-128-ofb":    {"ofb", 16, true, false},
+	"aes-192-ofb":    {"ofb", 24, true, false},
+	"aes-256-ofb":    {"ofb", 32, true, false},
+	"aes-128-cfb128": {"cfb128", 16, true, false},
+	"aes-192-cfb128": {"cfb128", 24, true, false},
+	"aes-256-cfb128": {"cfb128", 32, true, false},
+}
+
+// getAesMode gets the block encryption mode of the session.
+func getAesMode(ctx sessionctx.Context) (*aesModeAttr, error) {
+	modeName, err := variable.GetSessionSystemVar(ctx.GetSessionVars(), variable.BlockEncryptionMode)
+	if err != nil {
+		return nil, err
+	}
+	mode, ok := aesModes[strings.ToLower(modeName)]
+	if !ok {
+		return nil, errors.Errorf("unsupported block encryption mode - %v", modeName)
+	}
+	return mode, nil
+}
+
+// verifyAesArgs checks the initialization vector argument of AES_ENCRYPT and
+// AES_DECRYPT against the block encryption mode.
+func verifyAesArgs(ctx sessionctx.Context, funcName string, args []Expression, mode *aesModeAttr) error {
+	if len(args) == 3 && !mode.ivRequired {
+		// The initialization vector is ignored by the modes without it.
+		ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnOptionIgnored.GenWithStackByArgs("IV"))
+	}
+	if len(args) == 2 && mode.ivRequired {
+		return ErrIncorrectParameterCount.GenWithStackByArgs(funcName)
+	}
+	return nil
+}
+
+type md5FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *md5FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = 32
+	sig := &builtinMD5Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_MD5)
+	return sig, nil
+}
+
+type builtinMD5Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinMD5Sig) Clone() builtinFunc {
+	newSig := &builtinMD5Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinMD5Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_md5
+func (b *builtinMD5Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	sum := md5.Sum([]byte(str))
+	return hex.EncodeToString(sum[:]), false, nil
+}
+
+type sha1FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sha1FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = 40
+	sig := &builtinSHA1Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_SHA1)
+	return sig, nil
+}
+
+type builtinSHA1Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSHA1Sig) Clone() builtinFunc {
+	newSig := &builtinSHA1Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinSHA1Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_sha1
+func (b *builtinSHA1Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	sum := sha1.Sum([]byte(str))
+	return hex.EncodeToString(sum[:]), false, nil
+}
+
+type sha2FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *sha2FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	bf.tp.Flen = 128
+	sig := &builtinSHA2Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_SHA2)
+	return sig, nil
+}
+
+type builtinSHA2Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSHA2Sig) Clone() builtinFunc {
+	newSig := &builtinSHA2Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinSHA2Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_sha2
+func (b *builtinSHA2Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	hashLength, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := sha2(str, hashLength)
+	return res, isNull, nil
+}
+
+// sha2 calculates the SHA-2 hash of str in the lowercase hex format, the result
+// is NULL if hashLength is not a permissible value.
+func sha2(str string, hashLength int64) (string, bool) {
+	var hasher hash.Hash
+	switch hashLength {
+	case 0, 256:
+		hasher = sha256.New()
+	case 224:
+		hasher = sha256.New224()
+	case 384:
+		hasher = sha512.New384()
+	case 512:
+		hasher = sha512.New()
+	default:
+		return "", true
+	}
+	hasher.Write([]byte(str))
+	return hex.EncodeToString(hasher.Sum(nil)), false
+}
+
+type crc32FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *crc32FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString)
+	bf.tp.Flen = 10
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinCRC32Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_CRC32)
+	return sig, nil
+}
+
+type builtinCRC32Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCRC32Sig) Clone() builtinFunc {
+	newSig := &builtinCRC32Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates a builtinCRC32Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_crc32
+func (b *builtinCRC32Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(crc32.ChecksumIEEE([]byte(str))), false, nil
+}
+
+type aesEncryptFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *aesEncryptFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	mode, err := getAesMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAesArgs(ctx, c.funcName, args, mode); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = args[0].GetType().Flen
+	if mode.padded {
+		// The result is padded to a multiple of the block size.
+		bf.tp.Flen = aesBlockSize * (args[0].GetType().Flen/aesBlockSize + 1)
+	}
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinAesEncryptSig{bf, mode}
+	if mode.ivRequired {
+		sig.setPbCode(tipb.ScalarFuncSig_AesEncryptIV)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_AesEncrypt)
+	}
+	return sig, nil
+}
+
+// aesBlockSize is the block size of AES in bytes.
+const aesBlockSize = 16
+
+type builtinAesEncryptSig struct {
+	baseBuiltinFunc
+	*aesModeAttr
+}
+
+func (b *builtinAesEncryptSig) Clone() builtinFunc {
+	newSig := &builtinAesEncryptSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.aesModeAttr = b.aesModeAttr
+	return newSig
+}
+
+// evalString evaluates a builtinAesEncryptSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_aes-encrypt
+func (b *builtinAesEncryptSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	keyStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	var iv string
+	if b.ivRequired {
+		iv, isNull, err = b.args[2].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+	}
+	return b.encrypt(str, keyStr, iv)
+}
+
+func (b *builtinAesEncryptSig) encrypt(str, keyStr, iv string) (string, bool, error) {
+	key := encrypt.DeriveKeyMySQL([]byte(keyStr), b.keySize)
+	var (
+		crypted []byte
+		err     error
+	)
+	switch b.modeName {
+	case "ecb":
+		crypted, err = encrypt.AESEncryptWithECB([]byte(str), key)
+	case "cbc":
+		crypted, err = encrypt.AESEncryptWithCBC([]byte(str), key, []byte(iv))
+	case "ofb":
+		crypted, err = encrypt.AESEncryptWithOFB([]byte(str), key, []byte(iv))
+	case "cfb128":
+		crypted, err = encrypt.AESEncryptWithCFB([]byte(str), key, []byte(iv))
+	}
+	if err == encrypt.ErrInvalidIV {
+		return "", true, errIncorrectArgs.GenWithStackByArgs(ast.AesEncrypt)
+	}
+	if err != nil {
+		return "", true, nil
+	}
+	return string(crypted), false, nil
+}
+
+type aesDecryptFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *aesDecryptFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	mode, err := getAesMode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyAesArgs(ctx, c.funcName, args, mode); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = args[0].GetType().Flen
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinAesDecryptSig{bf, mode}
+	if mode.ivRequired {
+		sig.setPbCode(tipb.ScalarFuncSig_AesDecryptIV)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_AesDecrypt)
+	}
+	return sig, nil
+}
+
+type builtinAesDecryptSig struct {
+	baseBuiltinFunc
+	*aesModeAttr
+}
+
+func (b *builtinAesDecryptSig) Clone() builtinFunc {
+	newSig := &builtinAesDecryptSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.aesModeAttr = b.aesModeAttr
+	return newSig
+}
+
+// evalString evaluates a builtinAesDecryptSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/encryption-functions.html#function_aes-decrypt
+func (b *builtinAesDecryptSig) evalString(row chunk.Row) (string, bool, error) {
+	cryptStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	keyStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	var iv string
+	if b.ivRequired {
+		iv, isNull, err = b.args[2].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+	}
+	return b.decrypt(cryptStr, keyStr, iv)
+}
+
+// decrypt decrypts cryptStr, the result is NULL if cryptStr is not a valid
+// encrypted string, e.g. it is encrypted with another key.
+func (b *builtinAesDecryptSig) decrypt(cryptStr, keyStr, iv string) (string, bool, error) {
+	key := encrypt.DeriveKeyMySQL([]byte(keyStr), b.keySize)
+	var (
+		plainText []byte
+		err       error
+	)
+	switch b.modeName {
+	case "ecb":
+		plainText, err = encrypt.AESDecryptWithECB([]byte(cryptStr), key)
+	case "cbc":
+		plainText, err = encrypt.AESDecryptWithCBC([]byte(cryptStr), key, []byte(iv))
+	case "ofb":
+		plainText, err = encrypt.AESDecryptWithOFB([]byte(cryptStr), key, []byte(iv))
+	case "cfb128":
+		plainText, err = encrypt.AESDecryptWithCFB([]byte(cryptStr), key, []byte(iv))
+	}
+	if err == encrypt.ErrInvalidIV {
+		return "", true, errIncorrectArgs.GenWithStackByArgs(ast.AesDecrypt)
+	}
+	if err != nil {
+		return "", true, nil
+	}
+	return string(plainText), false, nil
+}
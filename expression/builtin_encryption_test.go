@@ -0,0 +1,151 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"encoding/hex"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestHashFunctions(c *C) {
+	cases := []struct {
+		funcName string
+		args     []interface{}
+		isNil    bool
+		res      string
+	}{
+		{ast.MD5, []interface{}{"a"}, false, "0cc175b9c0f1b6a831c399e269772661"},
+		{ast.MD5, []interface{}{""}, false, "d41d8cd98f00b204e9800998ecf8427e"},
+		{ast.MD5, []interface{}{123}, false, "202cb962ac59075b964b07152d234b70"},
+		{ast.MD5, []interface{}{nil}, true, ""},
+		{ast.SHA1, []interface{}{"abc"}, false, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{ast.SHA, []interface{}{"abc"}, false, "a9993e364706816aba3e25717850c26c9cd0d89d"},
+		{ast.SHA1, []interface{}{nil}, true, ""},
+		{ast.SHA2, []interface{}{"abc", 0}, false, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{ast.SHA2, []interface{}{"abc", 256}, false, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{ast.SHA2, []interface{}{"abc", 224}, false, "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7"},
+		{ast.SHA2, []interface{}{"abc", 384}, false, "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7"},
+		{ast.SHA2, []interface{}{"abc", 512}, false, "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+		{ast.SHA2, []interface{}{"abc", 1}, true, ""},
+		{ast.SHA2, []interface{}{"abc", nil}, true, ""},
+		{ast.SHA2, []interface{}{nil, 256}, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%s%v", t.funcName, t.args))
+		} else {
+			c.Assert(d.GetString(), Equals, t.res, Commentf("%s%v", t.funcName, t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestCRC32(c *C) {
+	cases := []struct {
+		args  interface{}
+		isNil bool
+		res   uint64
+	}{
+		{"mysql", false, 2501908538},
+		{"MySQL", false, 3259397556},
+		{"", false, 0},
+		{123, false, 2286445522},
+		{nil, true, 0},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.CRC32, s.primitiveValsToConstants([]interface{}{t.args})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetUint64(), Equals, t.res, Commentf("%v", t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestAesEncryptDecrypt(c *C) {
+	cases := []struct {
+		mode  string
+		args  []interface{}
+		crypt interface{}
+	}{
+		// The results are the same as MySQL.
+		{"aes-128-ecb", []interface{}{"pingcap", "1234567890123456"}, "697bfe9b3f8c2f289dd82c88c7bc95c4"},
+		{"aes-128-ecb", []interface{}{"pingcap", "1234567890123456", "ignored iv"}, "697bfe9b3f8c2f289dd82c88c7bc95c4"},
+		{"aes-128-ecb", []interface{}{nil, "1234567890123456"}, nil},
+		{"aes-128-ecb", []interface{}{"pingcap", nil}, nil},
+		{"aes-128-cbc", []interface{}{"pingcap", "1234567890123456", "1234567890123456"}, "2eca0077c5ea5768a0485aa522774792"},
+		{"aes-128-cbc", []interface{}{"pingcap", "1234567890123456", nil}, nil},
+		{"aes-256-ecb", []interface{}{"pingcap", "1234567890123456"}, nil},
+		{"aes-192-cbc", []interface{}{"pingcap", "key", "1234567890123456"}, nil},
+		{"aes-128-ofb", []interface{}{"pingcap", "1234567890123456", "1234567890123456"}, nil},
+		{"aes-256-cfb128", []interface{}{"pingcap", "1234567890123456", "1234567890123456"}, nil},
+	}
+	for _, t := range cases {
+		err := variable.SetSessionSystemVar(s.ctx.GetSessionVars(), variable.BlockEncryptionMode, types.NewStringDatum(t.mode))
+		c.Assert(err, IsNil)
+		f, err := newFunctionForTest(s.ctx, ast.AesEncrypt, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		crypt, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.args[0] == nil || t.args[1] == nil || (len(t.args) == 3 && t.args[2] == nil && t.mode != "aes-128-ecb") {
+			c.Assert(crypt.IsNull(), IsTrue)
+			continue
+		}
+		if t.crypt != nil {
+			c.Assert(hex.EncodeToString(crypt.GetBytes()), Equals, t.crypt, Commentf("%s%v", t.mode, t.args))
+		}
+
+		// Decrypt the result with the same key and initialization vector.
+		decryptArgs := append([]interface{}{crypt.GetBytes()}, t.args[1:]...)
+		f, err = newFunctionForTest(s.ctx, ast.AesDecrypt, s.primitiveValsToConstants(decryptArgs)...)
+		c.Assert(err, IsNil)
+		plain, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(plain.GetString(), Equals, t.args[0], Commentf("%s%v", t.mode, t.args))
+	}
+
+	// Decrypting with a wrong key returns NULL.
+	err := variable.SetSessionSystemVar(s.ctx.GetSessionVars(), variable.BlockEncryptionMode, types.NewStringDatum("aes-128-ecb"))
+	c.Assert(err, IsNil)
+	crypt, _ := hex.DecodeString("697bfe9b3f8c2f289dd82c88c7bc95c4")
+	f, err := newFunctionForTest(s.ctx, ast.AesDecrypt, s.primitiveValsToConstants([]interface{}{crypt, "wrong key"})...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.IsNull(), IsTrue)
+
+	// The initialization vector is required by the CBC mode and must be a full block.
+	err = variable.SetSessionSystemVar(s.ctx.GetSessionVars(), variable.BlockEncryptionMode, types.NewStringDatum("aes-128-cbc"))
+	c.Assert(err, IsNil)
+	_, err = newFunctionForTest(s.ctx, ast.AesEncrypt, s.primitiveValsToConstants([]interface{}{"pingcap", "key"})...)
+	c.Assert(ErrIncorrectParameterCount.Equal(err), IsTrue)
+	f, err = newFunctionForTest(s.ctx, ast.AesEncrypt, s.primitiveValsToConstants([]interface{}{"pingcap", "key", "short"})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(errIncorrectArgs.Equal(err), IsTrue)
+
+	err = variable.SetSessionSystemVar(s.ctx.GetSessionVars(), variable.BlockEncryptionMode, types.NewStringDatum("aes-128-ecb"))
+	c.Assert(err, IsNil)
+}
@@ -0,0 +1,254 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"hash/crc32"
+
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinMD5Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinMD5Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		sum := md5.Sum(buf.GetBytes(i))
+		result.AppendString(hex.EncodeToString(sum[:]))
+	}
+	return nil
+}
+
+func (b *builtinSHA1Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSHA1Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		sum := sha1.Sum(buf.GetBytes(i))
+		result.AppendString(hex.EncodeToString(sum[:]))
+	}
+	return nil
+}
+
+func (b *builtinSHA2Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSHA2Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	lenBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	hashLengths := lenBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || lenBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull := sha2(strBuf.GetString(i), hashLengths[i])
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinCRC32Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCRC32Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = int64(crc32.ChecksumIEEE(buf.GetBytes(i)))
+	}
+	return nil
+}
+
+// vecEvalAesArgs evaluates the arguments of AES_ENCRYPT and AES_DECRYPT, the
+// initialization vector is only evaluated if the mode requires it.
+func vecEvalAesArgs(b *baseBuiltinFunc, ivRequired bool, input *chunk.Chunk) ([]*chunk.Column, error) {
+	n := input.NumRows()
+	numArgs := 2
+	if ivRequired {
+		numArgs = 3
+	}
+	bufs := make([]*chunk.Column, 0, numArgs)
+	for i := 0; i < numArgs; i++ {
+		buf, err := b.bufAllocator.get(types.ETString, n)
+		if err != nil {
+			putAesArgBufs(b, bufs)
+			return nil, err
+		}
+		bufs = append(bufs, buf)
+		if err := b.args[i].VecEvalString(b.ctx, input, buf); err != nil {
+			putAesArgBufs(b, bufs)
+			return nil, err
+		}
+	}
+	return bufs, nil
+}
+
+func putAesArgBufs(b *baseBuiltinFunc, bufs []*chunk.Column) {
+	for _, buf := range bufs {
+		b.bufAllocator.put(buf)
+	}
+}
+
+func (b *builtinAesEncryptSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAesEncryptSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	bufs, err := vecEvalAesArgs(&b.baseBuiltinFunc, b.ivRequired, input)
+	if err != nil {
+		return err
+	}
+	defer putAesArgBufs(&b.baseBuiltinFunc, bufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isAnyNull(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		var iv string
+		if b.ivRequired {
+			iv = bufs[2].GetString(i)
+		}
+		res, isNull, err := b.encrypt(bufs[0].GetString(i), bufs[1].GetString(i), iv)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinAesDecryptSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAesDecryptSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	bufs, err := vecEvalAesArgs(&b.baseBuiltinFunc, b.ivRequired, input)
+	if err != nil {
+		return err
+	}
+	defer putAesArgBufs(&b.baseBuiltinFunc, bufs)
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isAnyNull(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		var iv string
+		if b.ivRequired {
+			iv = bufs[2].GetString(i)
+		}
+		res, isNull, err := b.decrypt(bufs[0].GetString(i), bufs[1].GetString(i), iv)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func isAnyNull(bufs []*chunk.Column, i int) bool {
+	for _, buf := range bufs {
+		if buf.IsNull(i) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+// sha2LenGener generates the hash lengths of SHA2, including the invalid ones.
+type sha2LenGener struct{}
+
+func (g *sha2LenGener) gen() interface{} {
+	lens := []int64{0, 224, 256, 384, 512, 1}
+	return lens[rand.Intn(len(lens))]
+}
+
+var vecBuiltinEncryptionCases = map[string][]vecExprBenchCase{
+	ast.MD5: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.SHA1: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.SHA2: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt}, geners: []dataGenerator{nil, &sha2LenGener{}}},
+	},
+	ast.CRC32: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.AesEncrypt: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString}, aesModes: "aes-128-ecb"},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}, aesModes: "aes-256-cbc", geners: []dataGenerator{
+			nil, nil, &randLenStrGener{16, 20},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}, aesModes: "aes-192-ofb", geners: []dataGenerator{
+			nil, nil, &randLenStrGener{16, 20},
+		}},
+	},
+	ast.AesDecrypt: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{
+			&selectStringGener{candidates: []string{"", "\x69\x7b\xfe\x9b\x3f\x8c\x2f\x28\x9d\xd8\x2c\x88\xc7\xbc\x95\xc4"}},
+			&selectStringGener{candidates: []string{"1234567890123456", "key"}},
+		}, aesModes: "aes-128-ecb"},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}, aesModes: "aes-128-cfb128", geners: []dataGenerator{
+			nil, nil, &randLenStrGener{16, 20},
+		}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinEncryptionEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinEncryptionCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinEncryptionFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinEncryptionCases)
+}
+
+func BenchmarkVectorizedBuiltinEncryptionEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinEncryptionCases)
+}
+
+func BenchmarkVectorizedBuiltinEncryptionFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinEncryptionCases)
+}
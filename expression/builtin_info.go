@@ -0,0 +1,232 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var (
+	_ functionClass = &databaseFunctionClass{}
+	_ functionClass = &versionFunctionClass{}
+	_ functionClass = &connectionIDFunctionClass{}
+	_ functionClass = &userFunctionClass{}
+	_ functionClass = &currentUserFunctionClass{}
+	_ functionClass = &currentRoleFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinDatabaseSig{}
+	_ builtinFunc = &builtinVersionSig{}
+	_ builtinFunc = &builtinConnectionIDSig{}
+	_ builtinFunc = &builtinUserSig{}
+	_ builtinFunc = &builtinCurrentUserSig{}
+	_ builtinFunc = &builtinCurrentRoleSig{}
+)
+
+type databaseFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *databaseFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	bf.tp.Flen = 64
+	sig := &builtinDatabaseSig{bf}
+	return sig, nil
+}
+
+type builtinDatabaseSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDatabaseSig) Clone() builtinFunc {
+	newSig := &builtinDatabaseSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinDatabaseSig, it returns NULL if there is no default database.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_database
+func (b *builtinDatabaseSig) evalString(_ chunk.Row) (string, bool, error) {
+	currentDB := b.ctx.GetSessionVars().CurrentDB
+	return currentDB, currentDB == "", nil
+}
+
+type versionFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *versionFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	bf.tp.Flen = 64
+	sig := &builtinVersionSig{bf}
+	return sig, nil
+}
+
+type builtinVersionSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinVersionSig) Clone() builtinFunc {
+	newSig := &builtinVersionSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinVersionSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_version
+func (b *builtinVersionSig) evalString(_ chunk.Row) (string, bool, error) {
+	return mysql.ServerVersion, false, nil
+}
+
+type connectionIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *connectionIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+	bf.tp.Flen = 21
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinConnectionIDSig{bf}
+	return sig, nil
+}
+
+type builtinConnectionIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinConnectionIDSig) Clone() builtinFunc {
+	newSig := &builtinConnectionIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinConnectionIDSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_connection-id
+func (b *builtinConnectionIDSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	return int64(b.ctx.GetSessionVars().ConnectionID), false, nil
+}
+
+type userFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *userFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	bf.tp.Flen = 64
+	sig := &builtinUserSig{bf}
+	return sig, nil
+}
+
+type builtinUserSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUserSig) Clone() builtinFunc {
+	newSig := &builtinUserSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUserSig, it returns the user name and host name the client connects
+// with, or NULL for the internal sessions.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_user
+func (b *builtinUserSig) evalString(_ chunk.Row) (string, bool, error) {
+	user := b.ctx.GetSessionVars().User
+	if user == nil {
+		return "", true, nil
+	}
+	return user.String(), false, nil
+}
+
+type currentUserFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentUserFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	bf.tp.Flen = 64
+	sig := &builtinCurrentUserSig{bf}
+	return sig, nil
+}
+
+type builtinCurrentUserSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentUserSig) Clone() builtinFunc {
+	newSig := &builtinCurrentUserSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinCurrentUserSig, it returns the account the session is authenticated as.
+// The login is not matched against the accounts, so the account is the user the client connects with.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_current-user
+func (b *builtinCurrentUserSig) evalString(_ chunk.Row) (string, bool, error) {
+	user := b.ctx.GetSessionVars().User
+	if user == nil {
+		return "", true, nil
+	}
+	return user.String(), false, nil
+}
+
+type currentRoleFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *currentRoleFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	bf.tp.Flen = 64
+	sig := &builtinCurrentRoleSig{bf}
+	return sig, nil
+}
+
+type builtinCurrentRoleSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCurrentRoleSig) Clone() builtinFunc {
+	newSig := &builtinCurrentRoleSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinCurrentRoleSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/information-functions.html#function_current-role
+func (b *builtinCurrentRoleSig) evalString(_ chunk.Row) (string, bool, error) {
+	// Roles are not supported, so no role is ever active.
+	return "NONE", false, nil
+}
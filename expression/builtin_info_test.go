@@ -0,0 +1,87 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestDatabase(c *C) {
+	for _, name := range []string{ast.Database, ast.Schema} {
+		fn, err := funcs[name].getFunction(s.ctx, nil)
+		c.Assert(err, IsNil)
+		s.ctx.GetSessionVars().CurrentDB = ""
+		d, err := evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsTrue)
+
+		s.ctx.GetSessionVars().CurrentDB = "test"
+		d, err = evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetString(), Equals, "test")
+
+		_, err = funcs[name].getFunction(s.ctx, []Expression{newLonglong(1)})
+		c.Assert(err, NotNil)
+	}
+	s.ctx.GetSessionVars().CurrentDB = ""
+}
+
+func (s *testEvaluatorSuite) TestVersion(c *C) {
+	fn, err := funcs[ast.Version].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	d, err := evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetString(), Equals, mysql.ServerVersion)
+}
+
+func (s *testEvaluatorSuite) TestConnectionID(c *C) {
+	fn, err := funcs[ast.ConnectionID].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(mysql.HasUnsignedFlag(fn.getRetTp().Flag), IsTrue)
+	s.ctx.GetSessionVars().ConnectionID = 3
+	d, err := evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetUint64(), Equals, uint64(3))
+	s.ctx.GetSessionVars().ConnectionID = 0
+}
+
+func (s *testEvaluatorSuite) TestUser(c *C) {
+	vars := s.ctx.GetSessionVars()
+	for _, name := range []string{ast.User, ast.SessionUser, ast.SystemUser, ast.CurrentUser} {
+		fn, err := funcs[name].getFunction(s.ctx, nil)
+		c.Assert(err, IsNil)
+		vars.User = nil
+		d, err := evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.IsNull(), IsTrue)
+
+		vars.User = &auth.UserIdentity{Username: "root", Hostname: "localhost"}
+		d, err = evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetString(), Equals, "root@localhost")
+	}
+	vars.User = nil
+}
+
+func (s *testEvaluatorSuite) TestCurrentRole(c *C) {
+	fn, err := funcs[ast.CurrentRole].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	d, err := evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetString(), Equals, "NONE")
+}
@@ -0,0 +1,109 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinDatabaseSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDatabaseSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	currentDB := b.ctx.GetSessionVars().CurrentDB
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if currentDB == "" {
+			result.AppendNull()
+		} else {
+			result.AppendString(currentDB)
+		}
+	}
+	return nil
+}
+
+func (b *builtinVersionSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinVersionSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		result.AppendString(mysql.ServerVersion)
+	}
+	return nil
+}
+
+func (b *builtinConnectionIDSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinConnectionIDSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	connID := int64(b.ctx.GetSessionVars().ConnectionID)
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = connID
+	}
+	return nil
+}
+
+// vecEvalUserIdentity evaluates USER and CURRENT_USER in a vectorized manner.
+func vecEvalUserIdentity(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	user := b.ctx.GetSessionVars().User
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if user == nil {
+			result.AppendNull()
+		} else {
+			result.AppendString(user.String())
+		}
+	}
+	return nil
+}
+
+func (b *builtinUserSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUserSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUserIdentity(&b.baseBuiltinFunc, input, result)
+}
+
+func (b *builtinCurrentUserSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCurrentUserSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUserIdentity(&b.baseBuiltinFunc, input, result)
+}
+
+func (b *builtinCurrentRoleSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCurrentRoleSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		result.AppendString("NONE")
+	}
+	return nil
+}
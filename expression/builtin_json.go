@@ -0,0 +1,380 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &jsonTypeFunctionClass{}
+	_ functionClass = &jsonExtractFunctionClass{}
+	_ functionClass = &jsonUnquoteFunctionClass{}
+	_ functionClass = &jsonSetFunctionClass{}
+	_ functionClass = &jsonContainsFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinJSONTypeSig{}
+	_ builtinFunc = &builtinJSONExtractSig{}
+	_ builtinFunc = &builtinJSONUnquoteSig{}
+	_ builtinFunc = &builtinJSONSetSig{}
+	_ builtinFunc = &builtinJSONContainsSig{}
+)
+
+// getJSONDocArgType returns the eval type of the JSON document argument, which
+// must be either a JSON or a string in JSON text.
+func getJSONDocArgType(funcName string, args []Expression, idx int) (types.EvalType, error) {
+	argTp := args[idx].GetType().EvalType()
+	if argTp != types.ETJson && argTp != types.ETString {
+		return argTp, ErrInvalidTypeForJSON.GenWithStackByArgs(idx+1, funcName)
+	}
+	return argTp, nil
+}
+
+// evalJSONDoc evaluates the JSON document argument, a string is parsed as the JSON text.
+func evalJSONDoc(ctx sessionctx.Context, arg Expression, row chunk.Row) (json.BinaryJSON, bool, error) {
+	if arg.GetType().EvalType() == types.ETJson {
+		return arg.EvalJSON(ctx, row)
+	}
+	s, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return json.BinaryJSON{}, isNull, err
+	}
+	bj, err := json.ParseBinaryFromString(s)
+	return bj, err != nil, err
+}
+
+// evalJSONPathExprs evaluates and parses the JSON path arguments.
+func evalJSONPathExprs(ctx sessionctx.Context, args []Expression, row chunk.Row) ([]json.PathExpression, bool, error) {
+	pathExprs := make([]json.PathExpression, 0, len(args))
+	for _, arg := range args {
+		s, isNull, err := arg.EvalString(ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		pathExpr, err := json.ParseJSONPathExpr(s)
+		if err != nil {
+			return nil, true, err
+		}
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs, false, nil
+}
+
+// evalJSONValue evaluates the argument as a JSON value. Unlike evalJSONDoc, a string
+// is used as a JSON string, and the SQL NULL becomes the JSON null literal.
+func evalJSONValue(ctx sessionctx.Context, arg Expression, row chunk.Row) (json.BinaryJSON, error) {
+	var (
+		value  interface{}
+		isNull bool
+		err    error
+	)
+	switch arg.GetType().EvalType() {
+	case types.ETJson:
+		var bj json.BinaryJSON
+		bj, isNull, err = arg.EvalJSON(ctx, row)
+		if !isNull && err == nil {
+			return bj, nil
+		}
+	case types.ETInt:
+		var i int64
+		i, isNull, err = arg.EvalInt(ctx, row)
+		if mysql.HasUnsignedFlag(arg.GetType().Flag) {
+			value = uint64(i)
+		} else {
+			value = i
+		}
+	case types.ETReal:
+		value, isNull, err = arg.EvalReal(ctx, row)
+	default:
+		value, isNull, err = arg.EvalString(ctx, row)
+	}
+	if err != nil {
+		return json.BinaryJSON{}, err
+	}
+	if isNull {
+		value = nil
+	}
+	return json.CreateBinary(value), nil
+}
+
+type jsonTypeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonTypeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp, err := getJSONDocArgType(c.funcName, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTp)
+	bf.tp.Flen = 51 // Flen of JSON_TYPE is length of UNSIGNED INTEGER.
+	sig := &builtinJSONTypeSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonTypeSig)
+	return sig, nil
+}
+
+type builtinJSONTypeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONTypeSig) Clone() builtinFunc {
+	newSig := &builtinJSONTypeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinJSONTypeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-attribute-functions.html#function_json-type
+func (b *builtinJSONTypeSig) evalString(row chunk.Row) (string, bool, error) {
+	bj, isNull, err := evalJSONDoc(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return bj.Type(), false, nil
+}
+
+type jsonExtractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonExtractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp, err := getJSONDocArgType(c.funcName, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, argTp)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONExtractSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonExtractSig)
+	return sig, nil
+}
+
+type builtinJSONExtractSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONExtractSig) Clone() builtinFunc {
+	newSig := &builtinJSONExtractSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evaluates a builtinJSONExtractSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+func (b *builtinJSONExtractSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	bj, isNull, err := evalJSONDoc(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return bj, isNull, err
+	}
+	pathExprs, isNull, err := evalJSONPathExprs(b.ctx, b.args[1:], row)
+	if isNull || err != nil {
+		return bj, isNull, err
+	}
+	res, found := bj.Extract(pathExprs)
+	if !found {
+		return res, true, nil
+	}
+	return res, false, nil
+}
+
+type jsonUnquoteFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonUnquoteFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp, err := getJSONDocArgType(c.funcName, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTp)
+	bf.tp.Flen = mysql.MaxFieldVarCharLength
+	sig := &builtinJSONUnquoteSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonUnquoteSig)
+	return sig, nil
+}
+
+type builtinJSONUnquoteSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONUnquoteSig) Clone() builtinFunc {
+	newSig := &builtinJSONUnquoteSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinJSONUnquoteSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+func (b *builtinJSONUnquoteSig) evalString(row chunk.Row) (string, bool, error) {
+	if b.args[0].GetType().EvalType() == types.ETJson {
+		bj, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return bj.Unquote(), false, nil
+	}
+	s, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, err := json.UnquoteString(s)
+	return res, err != nil, err
+}
+
+type jsonSetFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonSetFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)%2 != 1 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTp, err := getJSONDocArgType(c.funcName, args, 0)
+	if err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, argTp)
+	for i := 1; i < len(args); i += 2 {
+		argTps = append(argTps, types.ETString, args[i+1].GetType().EvalType())
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	sig := &builtinJSONSetSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonSetSig)
+	return sig, nil
+}
+
+type builtinJSONSetSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONSetSig) Clone() builtinFunc {
+	newSig := &builtinJSONSetSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evaluates a builtinJSONSetSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-set
+func (b *builtinJSONSetSig) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	bj, isNull, err := evalJSONDoc(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return bj, isNull, err
+	}
+	pathArgs := make([]Expression, 0, len(b.args)/2)
+	values := make([]json.BinaryJSON, 0, len(b.args)/2)
+	for i := 1; i < len(b.args); i += 2 {
+		pathArgs = append(pathArgs, b.args[i])
+		value, err := evalJSONValue(b.ctx, b.args[i+1], row)
+		if err != nil {
+			return bj, true, err
+		}
+		values = append(values, value)
+	}
+	pathExprs, isNull, err := evalJSONPathExprs(b.ctx, pathArgs, row)
+	if isNull || err != nil {
+		return bj, isNull, err
+	}
+	res, err := bj.Modify(pathExprs, values, json.ModifySet)
+	return res, err != nil, err
+}
+
+type jsonContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := 0; i < 2; i++ {
+		argTp, err := getJSONDocArgType(c.funcName, args, i)
+		if err != nil {
+			return nil, err
+		}
+		argTps = append(argTps, argTp)
+	}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinJSONContainsSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_JsonContainsSig)
+	return sig, nil
+}
+
+type builtinJSONContainsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONContainsSig) Clone() builtinFunc {
+	newSig := &builtinJSONContainsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates a builtinJSONContainsSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains
+func (b *builtinJSONContainsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	obj, isNull, err := evalJSONDoc(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	target, isNull, err := evalJSONDoc(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if len(b.args) == 3 {
+		pathExprs, isNull, err := evalJSONPathExprs(b.ctx, b.args[2:], row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		if pathExprs[0].ContainsAnyAsterisk() {
+			return 0, true, json.ErrInvalidJSONPathWildcard
+		}
+		var found bool
+		if obj, found = obj.Extract(pathExprs); !found {
+			return 0, true, nil
+		}
+	}
+	if json.ContainsBinary(obj, target) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
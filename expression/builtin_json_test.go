@@ -0,0 +1,205 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func mustParseBinaryJSON(c *C, s string) json.BinaryJSON {
+	bj, err := json.ParseBinaryFromString(s)
+	c.Assert(err, IsNil)
+	return bj
+}
+
+func (s *testEvaluatorSuite) TestJSONType(c *C) {
+	tbl := []struct {
+		input    interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{`3`, `INTEGER`},
+		{`3.0`, `DOUBLE`},
+		{`null`, `NULL`},
+		{`true`, `BOOLEAN`},
+		{`[]`, `ARRAY`},
+		{`{}`, `OBJECT`},
+		{`"3"`, `STRING`},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.JSONType, s.primitiveValsToConstants([]interface{}{t.input})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected))
+	}
+
+	f, err := newFunctionForTest(s.ctx, ast.JSONType, s.primitiveValsToConstants([]interface{}{`{"a": 1`})...)
+	c.Assert(err, IsNil)
+	_, err = f.Eval(chunk.Row{})
+	c.Assert(json.ErrInvalidJSONText.Equal(err), IsTrue)
+
+	_, err = newFunctionForTest(s.ctx, ast.JSONType, s.primitiveValsToConstants([]interface{}{1})...)
+	c.Assert(ErrInvalidTypeForJSON.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONExtract(c *C) {
+	jstr := `{"a": [{"aa": [{"aaa": 1}]}], "aaa": 2}`
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{nil, nil}, nil, true},
+		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$.aaa`}, `[1, 2]`, true},
+		{[]interface{}{jstr, `$.a[0].aa[0].aaa`, `$InvalidPath`}, nil, false},
+		{[]interface{}{jstr, `$.aaa`}, `2`, true},
+		{[]interface{}{jstr, `$.b`}, nil, true},
+		{[]interface{}{jstr, nil}, nil, true},
+		{[]interface{}{mustParseBinaryJSON(c, jstr), `$**.aaa`}, `[2, 1]`, true},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.JSONExtract, s.primitiveValsToConstants(t.input)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if !t.success {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetMysqlJSON().String(), Equals, t.expected)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONUnquote(c *C) {
+	tbl := []struct {
+		input    interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{`a`, `a`},
+		{`"a"`, `a`},
+		{`"a\tb"`, "a\tb"},
+		{`[1, "2"]`, `[1, "2"]`},
+		{mustParseBinaryJSON(c, `"a\"b"`), `a"b`},
+		{mustParseBinaryJSON(c, `{"a": "b"}`), `{"a": "b"}`},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.JSONUnquote, s.primitiveValsToConstants([]interface{}{t.input})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected))
+	}
+}
+
+func (s *testEvaluatorSuite) TestJSONSet(c *C) {
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{nil, `$.a`, 1}, nil, true},
+		{[]interface{}{`{}`, nil, 1}, nil, true},
+		{[]interface{}{`{}`, `$.a`, 1}, `{"a": 1}`, true},
+		{[]interface{}{`{}`, `$.a`, nil}, `{"a": null}`, true},
+		{[]interface{}{`{}`, `$.a`, `x`, `$.b`, 1.5}, `{"a": "x", "b": 1.5}`, true},
+		{[]interface{}{`{"a": 1}`, `$.a`, uint64(18446744073709551615)}, `{"a": 18446744073709551615}`, true},
+		{[]interface{}{`[1]`, `$[1]`, mustParseBinaryJSON(c, `{"b": 2}`)}, `[1, {"b": 2}]`, true},
+		{[]interface{}{`{"a": 1}`, `$.*`, 2}, nil, false},
+		{[]interface{}{`{"a": 1}`, `a`, 2}, nil, false},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.JSONSet, s.primitiveValsToConstants(t.input)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if !t.success {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		if t.expected == nil {
+			c.Assert(d.IsNull(), IsTrue)
+		} else {
+			c.Assert(d.GetMysqlJSON().String(), Equals, t.expected)
+		}
+	}
+
+	_, err := newFunctionForTest(s.ctx, ast.JSONSet, s.primitiveValsToConstants([]interface{}{`{}`, `$.a`, 1, `$.b`})...)
+	c.Assert(ErrIncorrectParameterCount.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONContains(c *C) {
+	jstr := `{"a": [1, "2", {"aa": "bb"}], "b": 3}`
+	tbl := []struct {
+		input    []interface{}
+		expected interface{}
+		success  bool
+	}{
+		{[]interface{}{nil, `1`}, nil, true},
+		{[]interface{}{jstr, nil}, nil, true},
+		{[]interface{}{jstr, `{"b": 3}`}, int64(1), true},
+		{[]interface{}{jstr, `{"b": 4}`}, int64(0), true},
+		{[]interface{}{jstr, `[1, {"aa": "bb"}]`, `$.a`}, int64(1), true},
+		{[]interface{}{jstr, `"2"`, `$.a`}, int64(1), true},
+		{[]interface{}{jstr, `2`, `$.a`}, int64(0), true},
+		{[]interface{}{jstr, `1`, `$.c`}, nil, true},
+		{[]interface{}{mustParseBinaryJSON(c, jstr), `3`, `$.b`}, int64(1), true},
+		{[]interface{}{jstr, `1`, `$.*`}, nil, false},
+		{[]interface{}{jstr, `{"b": 3`}, nil, false},
+	}
+	for _, t := range tbl {
+		f, err := newFunctionForTest(s.ctx, ast.JSONContains, s.primitiveValsToConstants(t.input)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if !t.success {
+			c.Assert(err, NotNil)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.expected))
+	}
+
+	_, err := newFunctionForTest(s.ctx, ast.JSONContains, s.primitiveValsToConstants([]interface{}{`[1]`, 1})...)
+	c.Assert(ErrInvalidTypeForJSON.Equal(err), IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestJSONIsNull(c *C) {
+	for _, t := range []struct {
+		input    interface{}
+		expected int64
+	}{
+		{nil, 1},
+		{mustParseBinaryJSON(c, `null`), 0},
+		{mustParseBinaryJSON(c, `[1]`), 0},
+	} {
+		args := s.primitiveValsToConstants([]interface{}{t.input})
+		args[0].GetType().Tp = mysql.TypeJSON
+		f, err := newFunctionForTest(s.ctx, ast.IsNull, args...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expected)
+	}
+}
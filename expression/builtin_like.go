@@ -0,0 +1,257 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &likeFunctionClass{}
+	_ functionClass = &regexpFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinLikeSig{}
+	_ builtinFunc = &builtinRegexpSig{}
+)
+
+type likeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *likeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := []types.EvalType{types.ETString, types.ETString, types.ETInt}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTp...)
+	bf.tp.Flen = 1
+	// The escape character is not a string argument, so it doesn't affect the collation.
+	bf.collation = DeriveCollationFromExprs(args[:2]...)
+	sig := &builtinLikeSig{baseBuiltinFunc: bf}
+	sig.setPbCode(tipb.ScalarFuncSig_LikeSig)
+	_, patternIsConst := args[1].(*Constant)
+	_, escapeIsConst := args[2].(*Constant)
+	sig.constPattern = patternIsConst && escapeIsConst
+	return sig, nil
+}
+
+type builtinLikeSig struct {
+	baseBuiltinFunc
+
+	// constPattern is true if the pattern and the escape character are both
+	// constants, the pattern is then compiled once and memorized for the
+	// following rows.
+	constPattern     bool
+	memorized        bool
+	memorizedPattern collate.WildcardPattern
+	memorizedIsNull  bool
+	memorizedErr     error
+}
+
+func (b *builtinLikeSig) Clone() builtinFunc {
+	newSig := &builtinLikeSig{constPattern: b.constPattern}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates a builtinLikeSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-comparison-functions.html#operator_like
+func (b *builtinLikeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	valStr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	pattern, isNull, err := b.getPattern(row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if pattern.DoMatch(valStr) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+// getPattern returns the compiled pattern to match the row with, the memorized
+// one is used if the pattern is constant.
+func (b *builtinLikeSig) getPattern(row chunk.Row) (collate.WildcardPattern, bool, error) {
+	if !b.constPattern {
+		return b.compilePattern(row)
+	}
+	if !b.memorized {
+		b.memorizedPattern, b.memorizedIsNull, b.memorizedErr = b.compilePattern(row)
+		b.memorized = true
+	}
+	return b.memorizedPattern, b.memorizedIsNull, b.memorizedErr
+}
+
+func (b *builtinLikeSig) compilePattern(row chunk.Row) (collate.WildcardPattern, bool, error) {
+	patternStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	escape, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	pattern := collate.GetPattern(b.collation)
+	pattern.Compile(patternStr, rune(escape))
+	return pattern, false, nil
+}
+
+type regexpFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for range args {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	sig := &builtinRegexpSig{baseBuiltinFunc: bf}
+	// The match is case sensitive only if either string is a binary string.
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[1].GetType()) {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSig)
+	} else {
+		sig.caseInsensitive = true
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpUTF8Sig)
+	}
+	sig.constPattern = true
+	for _, arg := range args[1:] {
+		if _, ok := arg.(*Constant); !ok {
+			sig.constPattern = false
+		}
+	}
+	return sig, nil
+}
+
+type builtinRegexpSig struct {
+	baseBuiltinFunc
+	caseInsensitive bool
+
+	// constPattern is true if the pattern and the match type are both constants,
+	// the regexp is then compiled once and memorized for the following rows.
+	constPattern    bool
+	memorized       bool
+	memorizedRegexp *regexp.Regexp
+	memorizedIsNull bool
+	memorizedErr    error
+}
+
+func (b *builtinRegexpSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSig{caseInsensitive: b.caseInsensitive, constPattern: b.constPattern}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates a builtinRegexpSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	re, isNull, err := b.getRegexp(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if re.MatchString(expr) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+// getRegexp returns the regexp to match the row with, the memorized one is used
+// if the pattern is constant.
+func (b *builtinRegexpSig) getRegexp(row chunk.Row) (*regexp.Regexp, bool, error) {
+	if !b.constPattern {
+		return b.compileRegexp(row)
+	}
+	if !b.memorized {
+		b.memorizedRegexp, b.memorizedIsNull, b.memorizedErr = b.compileRegexp(row)
+		b.memorized = true
+	}
+	return b.memorizedRegexp, b.memorizedIsNull, b.memorizedErr
+}
+
+func (b *builtinRegexpSig) compileRegexp(row chunk.Row) (*regexp.Regexp, bool, error) {
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	var matchType string
+	if len(b.args) == 3 {
+		matchType, isNull, err = b.args[2].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return nil, true, err
+		}
+	}
+	flags, err := buildRegexpFlags(b.caseInsensitive, matchType)
+	if err != nil {
+		return nil, true, err
+	}
+	re, err := regexp.Compile(flags + pat)
+	if err != nil {
+		return nil, true, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, false, nil
+}
+
+// buildRegexpFlags converts the match type of REGEXP_LIKE to the flags of the go regexp.
+func buildRegexpFlags(caseInsensitive bool, matchType string) (string, error) {
+	var multiLine, dotAll bool
+	for _, c := range matchType {
+		switch c {
+		case 'c':
+			caseInsensitive = false
+		case 'i':
+			caseInsensitive = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Only '\n' is recognized as the line ending by the go regexp.
+		default:
+			return "", ErrRegexp.GenWithStackByArgs(fmt.Sprintf("invalid match type '%c'", c))
+		}
+	}
+	flags := ""
+	if caseInsensitive {
+		flags += "i"
+	}
+	if multiLine {
+		flags += "m"
+	}
+	if dotAll {
+		flags += "s"
+	}
+	if flags == "" {
+		return "", nil
+	}
+	return "(?" + flags + ")", nil
+}
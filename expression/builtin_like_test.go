@@ -0,0 +1,183 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/testutil"
+)
+
+func (s *testEvaluatorSuite) TestLike(c *C) {
+	tests := []struct {
+		input   interface{}
+		pattern interface{}
+		escape  interface{}
+		match   interface{}
+	}{
+		{"a", "", int64('\\'), int64(0)},
+		{"", "", int64('\\'), int64(1)},
+		{"abc", "a%", int64('\\'), int64(1)},
+		{"abc", "_b_", int64('\\'), int64(1)},
+		{"abc", "%d%", int64('\\'), int64(0)},
+		{"abc", "ABC", int64('\\'), int64(0)},
+		{"中文字", "中_字", int64('\\'), int64(1)},
+		{"中文字", "___", int64('\\'), int64(1)},
+		{"中文字", "__", int64('\\'), int64(0)},
+		{"a%c", `a\%c`, int64('\\'), int64(1)},
+		{"abc", `a\%c`, int64('\\'), int64(0)},
+		{"a_c", "a|_c", int64('|'), int64(1)},
+		{"abc", "a|_c", int64('|'), int64(0)},
+		{"a中c", "a中_", int64('中'), int64(0)},
+		{"a_c", "a中_c", int64('中'), int64(1)},
+		{nil, "a", int64('\\'), nil},
+		{"a", nil, int64('\\'), nil},
+		{"a", "a", nil, nil},
+	}
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, ast.Like, s.primitiveValsToConstants([]interface{}{t.input, t.pattern, t.escape})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.match), Commentf("%v like %v escape %v", t.input, t.pattern, t.escape))
+	}
+
+	// The binary string is matched byte by byte.
+	args := s.primitiveValsToConstants([]interface{}{"中文字", "___", int64('\\')})
+	types.SetBinChsClnFlag(args[0].GetType())
+	f, err := newFunctionForTest(s.ctx, ast.Like, args...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, int64(0))
+}
+
+func (s *testEvaluatorSuite) TestLikeMemorization(c *C) {
+	col := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeVarString)}
+	args := s.primitiveValsToConstants([]interface{}{"a%", int64('\\')})
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{col.RetType}, 3)
+	for _, str := range []string{"abc", "bca", "a"} {
+		input.AppendString(0, str)
+	}
+
+	f, err := funcs[ast.Like].getFunction(s.ctx, []Expression{col, args[0], args[1]})
+	c.Assert(err, IsNil)
+	sig := f.(*builtinLikeSig)
+	c.Assert(sig.constPattern, IsTrue)
+	var memorized interface{}
+	for i, expected := range []int64{1, 0, 1} {
+		res, isNull, err := sig.evalInt(input.GetRow(i))
+		c.Assert(err, IsNil)
+		c.Assert(isNull, IsFalse)
+		c.Assert(res, Equals, expected)
+		if i == 0 {
+			memorized = sig.memorizedPattern
+			c.Assert(memorized, NotNil)
+		}
+		c.Assert(sig.memorizedPattern, Equals, memorized)
+	}
+	c.Assert(sig.Clone().(*builtinLikeSig).constPattern, IsTrue)
+}
+
+func (s *testEvaluatorSuite) TestRegexp(c *C) {
+	tests := []struct {
+		funcName string
+		args     []interface{}
+		match    interface{}
+		getErr   bool
+	}{
+		{ast.Regexp, []interface{}{"a", "^$"}, int64(0), false},
+		{ast.Regexp, []interface{}{"a", "a"}, int64(1), false},
+		{ast.Regexp, []interface{}{"abc", "^b"}, int64(0), false},
+		{ast.Regexp, []interface{}{"abc", "B"}, int64(1), false},
+		{ast.Regexp, []interface{}{"abc", "x|c$"}, int64(1), false},
+		{ast.Regexp, []interface{}{nil, "a"}, nil, false},
+		{ast.Regexp, []interface{}{"a", nil}, nil, false},
+		{ast.Regexp, []interface{}{"a", "("}, nil, true},
+		{ast.RegexpLike, []interface{}{"abc", "B"}, int64(1), false},
+		{ast.RegexpLike, []interface{}{"abc", "B", "c"}, int64(0), false},
+		{ast.RegexpLike, []interface{}{"abc", "B", "ci"}, int64(1), false},
+		{ast.RegexpLike, []interface{}{"a\nb", "^b$"}, int64(0), false},
+		{ast.RegexpLike, []interface{}{"a\nb", "^b$", "m"}, int64(1), false},
+		{ast.RegexpLike, []interface{}{"a\nb", "a.b"}, int64(0), false},
+		{ast.RegexpLike, []interface{}{"a\nb", "a.b", "n"}, int64(1), false},
+		{ast.RegexpLike, []interface{}{"abc", "b", nil}, nil, false},
+		{ast.RegexpLike, []interface{}{"abc", "b", "x"}, nil, true},
+	}
+	for _, t := range tests {
+		f, err := newFunctionForTest(s.ctx, t.funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.getErr {
+			c.Assert(err, NotNil, Commentf("%v", t.args))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf("%v", t.args))
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.match), Commentf("%v", t.args))
+	}
+
+	// The match is case sensitive for the binary string.
+	args := s.primitiveValsToConstants([]interface{}{"abc", "B"})
+	types.SetBinChsClnFlag(args[0].GetType())
+	f, err := newFunctionForTest(s.ctx, ast.Regexp, args...)
+	c.Assert(err, IsNil)
+	d, err := f.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, int64(0))
+}
+
+func (s *testEvaluatorSuite) TestRegexpMemorization(c *C) {
+	col := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeVarString)}
+	pattern := s.primitiveValsToConstants([]interface{}{"^a"})[0]
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{col.RetType, col.RetType}, 3)
+	for _, str := range []string{"abc", "bca", "a"} {
+		input.AppendString(0, str)
+		input.AppendString(1, str)
+	}
+
+	// The constant pattern is compiled only once.
+	f, err := funcs[ast.Regexp].getFunction(s.ctx, []Expression{col, pattern})
+	c.Assert(err, IsNil)
+	sig := f.(*builtinRegexpSig)
+	c.Assert(sig.constPattern, IsTrue)
+	var memorized interface{}
+	for i, expected := range []int64{1, 0, 1} {
+		res, isNull, err := sig.evalInt(input.GetRow(i))
+		c.Assert(err, IsNil)
+		c.Assert(isNull, IsFalse)
+		c.Assert(res, Equals, expected)
+		if i == 0 {
+			memorized = sig.memorizedRegexp
+			c.Assert(memorized, NotNil)
+		}
+		c.Assert(sig.memorizedRegexp, Equals, memorized)
+	}
+	c.Assert(sig.Clone().(*builtinRegexpSig).constPattern, IsTrue)
+
+	// The pattern from the column is compiled for each row.
+	f, err = funcs[ast.Regexp].getFunction(s.ctx, []Expression{col, &Column{Index: 1, RetType: col.RetType}})
+	c.Assert(err, IsNil)
+	sig = f.(*builtinRegexpSig)
+	c.Assert(sig.constPattern, IsFalse)
+	for i := 0; i < input.NumRows(); i++ {
+		res, isNull, err := sig.evalInt(input.GetRow(i))
+		c.Assert(err, IsNil)
+		c.Assert(isNull, IsFalse)
+		c.Assert(res, Equals, int64(1))
+	}
+	c.Assert(sig.memorizedRegexp, IsNil)
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+func (b *builtinLikeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+
+	if b.constPattern {
+		return b.vecMatchConstPattern(n, strBuf, result)
+	}
+
+	patternBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(patternBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, patternBuf); err != nil {
+		return err
+	}
+	escapeBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(escapeBuf)
+	if err := b.args[2].VecEvalInt(b.ctx, input, escapeBuf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(strBuf, patternBuf, escapeBuf)
+	i64s := result.Int64s()
+	escapes := escapeBuf.Int64s()
+	pattern := collate.GetPattern(b.collation)
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		pattern.Compile(patternBuf.GetString(i), rune(escapes[i]))
+		i64s[i] = 0
+		if pattern.DoMatch(strBuf.GetString(i)) {
+			i64s[i] = 1
+		}
+	}
+	return nil
+}
+
+// vecMatchConstPattern matches the strings with the constant pattern, which is
+// compiled only once.
+func (b *builtinLikeSig) vecMatchConstPattern(n int, strBuf, result *chunk.Column) error {
+	pattern, isNull, err := b.getPattern(chunk.Row{})
+	if err != nil {
+		return err
+	}
+	result.ResizeInt64(n, isNull)
+	if isNull {
+		return nil
+	}
+	result.MergeNulls(strBuf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = 0
+		if pattern.DoMatch(strBuf.GetString(i)) {
+			i64s[i] = 1
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// likeEscapeGener generates the escape characters of LIKE, including a multi-byte one.
+type likeEscapeGener struct{}
+
+func (g *likeEscapeGener) gen() interface{} {
+	escapes := []int64{'\\', '|', '中'}
+	return escapes[rand.Intn(len(escapes))]
+}
+
+var likePatterns = []string{"", "%", "_", "a%", "%.%", "中_", "%文%", "_._._", `\%`, "|_%", "%中|%"}
+
+var vecBuiltinLikeCases = map[string][]vecExprBenchCase{
+	ast.Like: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&selectStringGener{candidates: likePatterns},
+			&rangeInt64Gener{'\\', '\\' + 1},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&selectStringGener{candidates: likePatterns},
+			&likeEscapeGener{},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{newBinaryStrFieldType(), nil, nil},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: multiByteStrs},
+				&selectStringGener{candidates: likePatterns},
+				&rangeInt64Gener{'\\', '\\' + 1},
+			}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt},
+			geners:    []dataGenerator{&selectStringGener{candidates: multiByteStrs}},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("%文_"), RetType: types.NewFieldType(mysql.TypeVarString)}, {Value: types.NewIntDatum('\\'), RetType: types.NewFieldType(mysql.TypeLonglong)}},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinLikeCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinLikeFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinLikeCases)
+}
+
+func BenchmarkVectorizedBuiltinLikeFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinLikeCases)
+}
@@ -0,0 +1,677 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &lnFunctionClass{}
+	_ functionClass = &logFunctionClass{}
+	_ functionClass = &log2FunctionClass{}
+	_ functionClass = &log10FunctionClass{}
+	_ functionClass = &powFunctionClass{}
+	_ functionClass = &roundFunctionClass{}
+	_ functionClass = &truncateFunctionClass{}
+	_ functionClass = &convFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinLnSig{}
+	_ builtinFunc = &builtinLog1ArgSig{}
+	_ builtinFunc = &builtinLog2ArgsSig{}
+	_ builtinFunc = &builtinLog2Sig{}
+	_ builtinFunc = &builtinLog10Sig{}
+	_ builtinFunc = &builtinPowSig{}
+	_ builtinFunc = &builtinRoundIntSig{}
+	_ builtinFunc = &builtinRoundRealSig{}
+	_ builtinFunc = &builtinRoundWithFracIntSig{}
+	_ builtinFunc = &builtinRoundWithFracRealSig{}
+	_ builtinFunc = &builtinTruncateIntSig{}
+	_ builtinFunc = &builtinTruncateUintSig{}
+	_ builtinFunc = &builtinTruncateRealSig{}
+	_ builtinFunc = &builtinConvSig{}
+)
+
+// evalLogarithm evaluates the logarithm of the first argument of b, the result
+// is NULL with a warning if the argument is not positive.
+func evalLogarithm(b *baseBuiltinFunc, row chunk.Row, log func(float64) float64) (float64, bool, error) {
+	val, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if val <= 0 {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errInvalidArgForLogarithm)
+		return 0, true, nil
+	}
+	return log(val), false, nil
+}
+
+type lnFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lnFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argEvalTypes(args)...)
+	sig := &builtinLnSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Log1Arg)
+	return sig, nil
+}
+
+type builtinLnSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLnSig) Clone() builtinFunc {
+	newSig := &builtinLnSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLnSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_ln
+func (b *builtinLnSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalLogarithm(&b.baseBuiltinFunc, row, math.Log)
+}
+
+type logFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *logFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argEvalTypes(args)...)
+	var sig builtinFunc
+	if len(args) == 1 {
+		sig = &builtinLog1ArgSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Log1Arg)
+	} else {
+		sig = &builtinLog2ArgsSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Log2Args)
+	}
+	return sig, nil
+}
+
+type builtinLog1ArgSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog1ArgSig) Clone() builtinFunc {
+	newSig := &builtinLog1ArgSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog1ArgSig, it returns the natural logarithm of the argument.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
+func (b *builtinLog1ArgSig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalLogarithm(&b.baseBuiltinFunc, row, math.Log)
+}
+
+type builtinLog2ArgsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog2ArgsSig) Clone() builtinFunc {
+	newSig := &builtinLog2ArgsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog2ArgsSig, it returns the logarithm of the second argument
+// to the base of the first argument.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log
+func (b *builtinLog2ArgsSig) evalReal(row chunk.Row) (float64, bool, error) {
+	base, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	val, isNull, err := evalArgAsReal(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return b.log(base, val)
+}
+
+func (b *builtinLog2ArgsSig) log(base, val float64) (float64, bool, error) {
+	if base <= 0 || base == 1 || val <= 0 {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errInvalidArgForLogarithm)
+		return 0, true, nil
+	}
+	return math.Log(val) / math.Log(base), false, nil
+}
+
+type log2FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *log2FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argEvalTypes(args)...)
+	sig := &builtinLog2Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Log2)
+	return sig, nil
+}
+
+type builtinLog2Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog2Sig) Clone() builtinFunc {
+	newSig := &builtinLog2Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog2Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log2
+func (b *builtinLog2Sig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalLogarithm(&b.baseBuiltinFunc, row, math.Log2)
+}
+
+type log10FunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *log10FunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argEvalTypes(args)...)
+	sig := &builtinLog10Sig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Log10)
+	return sig, nil
+}
+
+type builtinLog10Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLog10Sig) Clone() builtinFunc {
+	newSig := &builtinLog10Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinLog10Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_log10
+func (b *builtinLog10Sig) evalReal(row chunk.Row) (float64, bool, error) {
+	return evalLogarithm(&b.baseBuiltinFunc, row, math.Log10)
+}
+
+type powFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *powFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, argEvalTypes(args)...)
+	sig := &builtinPowSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Pow)
+	return sig, nil
+}
+
+type builtinPowSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinPowSig) Clone() builtinFunc {
+	newSig := &builtinPowSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals a builtinPowSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_pow
+func (b *builtinPowSig) evalReal(row chunk.Row) (float64, bool, error) {
+	x, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	y, isNull, err := evalArgAsReal(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return b.pow(x, y)
+}
+
+// pow returns an error if the result is not a finite number, e.g. POW(0, -1) or POW(-8, 1/3).
+func (b *builtinPowSig) pow(x, y float64) (float64, bool, error) {
+	power := math.Pow(x, y)
+	if math.IsInf(power, 0) || math.IsNaN(power) {
+		return 0, false, types.ErrOverflow.GenWithStackByArgs("DOUBLE", fmt.Sprintf("pow(%s, %s)", b.args[0].String(), b.args[1].String()))
+	}
+	return power, false, nil
+}
+
+// pow10s are the powers of 10 which can be held by an uint64.
+var pow10s = [...]uint64{
+	1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10,
+	1e11, 1e12, 1e13, 1e14, 1e15, 1e16, 1e17, 1e18, 1e19,
+}
+
+// roundIntWithFrac rounds val half away from zero to -frac digits before the
+// decimal point, val is returned directly if frac is not negative. It reports
+// whether the result overflows.
+func roundIntWithFrac(val, frac int64, unsigned bool) (int64, bool) {
+	if frac >= 0 {
+		return val, false
+	}
+	abs, negative := uint64(val), !unsigned && val < 0
+	if negative {
+		abs = uint64(-val)
+	}
+	if -frac >= int64(len(pow10s)) {
+		// All the integers are less than a half of 10^20.
+		return 0, false
+	}
+	shift := pow10s[-frac]
+	rem := abs % shift
+	abs -= rem
+	if rem >= shift-rem {
+		if abs > math.MaxUint64-shift {
+			return 0, true
+		}
+		abs += shift
+	}
+	switch {
+	case unsigned:
+		return int64(abs), false
+	case negative:
+		return int64(-abs), abs > -math.MinInt64
+	default:
+		return int64(abs), abs > math.MaxInt64
+	}
+}
+
+// truncateIntWithFrac truncates val to -frac digits before the decimal point,
+// val is returned directly if frac is not negative.
+func truncateIntWithFrac(val, frac int64, unsigned bool) int64 {
+	if frac >= 0 {
+		return val
+	}
+	if -frac >= int64(len(pow10s)) {
+		return 0
+	}
+	shift := pow10s[-frac]
+	if unsigned {
+		return int64(uint64(val) / shift * shift)
+	}
+	if shift > math.MaxInt64 {
+		return 0
+	}
+	return val / int64(shift) * int64(shift)
+}
+
+// roundOverflowErr returns the error for the overflowed result of ROUND(args[0], args[1]).
+func roundOverflowErr(args []Expression) error {
+	tp := "BIGINT"
+	if mysql.HasUnsignedFlag(args[0].GetType().Flag) {
+		tp = "BIGINT UNSIGNED"
+	}
+	return types.ErrOverflow.GenWithStackByArgs(tp, fmt.Sprintf("round(%s, %s)", args[0].String(), args[1].String()))
+}
+
+// getRoundOrTruncateBaseFunc creates the base function of ROUND and TRUNCATE,
+// the result is an integer for the integer argument and a real otherwise.
+func getRoundOrTruncateBaseFunc(ctx sessionctx.Context, args []Expression) baseBuiltinFunc {
+	retTp := args[0].GetType().EvalType()
+	if retTp != types.ETInt {
+		retTp = types.ETReal
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retTp, argEvalTypes(args)...)
+	if retTp == types.ETInt {
+		bf.tp.Flag |= args[0].GetType().Flag & mysql.UnsignedFlag
+	}
+	return bf
+}
+
+type roundFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *roundFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := getRoundOrTruncateBaseFunc(ctx, args)
+	var sig builtinFunc
+	switch isInt := bf.tp.EvalType() == types.ETInt; {
+	case isInt && len(args) == 1:
+		sig = &builtinRoundIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundInt)
+	case isInt:
+		sig = &builtinRoundWithFracIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracInt)
+	case len(args) == 1:
+		sig = &builtinRoundRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundReal)
+	default:
+		sig = &builtinRoundWithFracRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_RoundWithFracReal)
+	}
+	return sig, nil
+}
+
+type builtinRoundIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(int).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinRoundRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(real), it rounds half away from zero.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.RoundFloat(val), false, nil
+}
+
+type builtinRoundWithFracIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(int, frac), the integer is changed only if frac is negative.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := evalArgAsInt(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, overflow := roundIntWithFrac(val, frac, mysql.HasUnsignedFlag(b.args[0].GetType().Flag))
+	if overflow {
+		return 0, false, roundOverflowErr(b.args)
+	}
+	return res, false, nil
+}
+
+type builtinRoundWithFracRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundWithFracRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundWithFracRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(real, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundWithFracRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := evalArgAsInt(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.Round(val, int(frac)), false, nil
+}
+
+type truncateFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *truncateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := getRoundOrTruncateBaseFunc(ctx, args)
+	var sig builtinFunc
+	switch {
+	case bf.tp.EvalType() != types.ETInt:
+		sig = &builtinTruncateRealSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateReal)
+	case mysql.HasUnsignedFlag(bf.tp.Flag):
+		sig = &builtinTruncateUintSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateUint)
+	default:
+		sig = &builtinTruncateIntSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_TruncateInt)
+	}
+	return sig, nil
+}
+
+type builtinTruncateIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateIntSig) Clone() builtinFunc {
+	newSig := &builtinTruncateIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals TRUNCATE(int, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := evalArgAsInt(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return truncateIntWithFrac(val, frac, false), false, nil
+}
+
+type builtinTruncateUintSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateUintSig) Clone() builtinFunc {
+	newSig := &builtinTruncateUintSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals TRUNCATE(uint, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateUintSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := evalArgAsInt(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return truncateIntWithFrac(val, frac, true), false, nil
+}
+
+type builtinTruncateRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinTruncateRealSig) Clone() builtinFunc {
+	newSig := &builtinTruncateRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals TRUNCATE(real, frac).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_truncate
+func (b *builtinTruncateRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := evalArgAsReal(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := evalArgAsInt(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return types.Truncate(val, int(frac)), false, nil
+}
+
+type convFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *convFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argEvalTypes(args)...)
+	bf.tp.Flen = 64
+	sig := &builtinConvSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Conv)
+	return sig, nil
+}
+
+type builtinConvSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinConvSig) Clone() builtinFunc {
+	newSig := &builtinConvSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals CONV(N, from_base, to_base).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_conv
+func (b *builtinConvSig) evalString(row chunk.Row) (string, bool, error) {
+	n, isNull, err := evalArgAsString(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	fromBase, isNull, err := evalArgAsInt(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	toBase, isNull, err := evalArgAsInt(b.ctx, b.args[2], row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := conv(n, fromBase, toBase)
+	return res, isNull, nil
+}
+
+// digitValue returns the value of the digit c in the bases up to 36, or 36 if c is not a digit.
+func digitValue(c byte) int64 {
+	switch {
+	case c >= '0' && c <= '9':
+		return int64(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int64(c-'a') + 10
+	case c >= 'A' && c <= 'Z':
+		return int64(c-'A') + 10
+	}
+	return 36
+}
+
+// conv converts the number n from fromBase to toBase like MySQL, it returns NULL
+// if any base is out of [2, 36]. The number is read as signed if fromBase is
+// negative, and the result is signed if toBase is negative. Only the valid prefix
+// of n is converted, and the overflowed number is clipped to the max value.
+func conv(n string, fromBase, toBase int64) (string, bool) {
+	var signed, signedResult bool
+	if fromBase < 0 {
+		fromBase, signed = -fromBase, true
+	}
+	if toBase < 0 {
+		toBase, signedResult = -toBase, true
+	}
+	if fromBase < 2 || fromBase > 36 || toBase < 2 || toBase > 36 {
+		return "", true
+	}
+
+	n = strings.TrimSpace(n)
+	negative := false
+	if len(n) > 0 && (n[0] == '-' || n[0] == '+') {
+		negative = n[0] == '-'
+		n = n[1:]
+	}
+	end := 0
+	for end < len(n) && digitValue(n[end]) < fromBase {
+		end++
+	}
+	var val uint64
+	if end > 0 {
+		var err error
+		val, err = strconv.ParseUint(n[:end], int(fromBase), 64)
+		if err != nil {
+			// The prefix only contains the valid digits, so the number overflows.
+			val = math.MaxUint64
+		}
+	}
+	if signed {
+		if negative && val > -math.MinInt64 {
+			val = -math.MinInt64
+		}
+		if !negative && val > math.MaxInt64 {
+			val = math.MaxInt64
+		}
+	}
+	if negative {
+		val = -val
+	}
+	if signedResult && int64(val) < 0 {
+		return "-" + strings.ToUpper(strconv.FormatUint(-val, int(toBase))), false
+	}
+	return strings.ToUpper(strconv.FormatUint(val, int(toBase))), false
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestLogarithm(c *C) {
+	cases := []struct {
+		funcName string
+		args     []interface{}
+		isNil    bool
+		warn     bool
+		res      float64
+	}{
+		{ast.Ln, []interface{}{1}, false, false, 0},
+		{ast.Ln, []interface{}{math.E}, false, false, 1},
+		{ast.Ln, []interface{}{0}, true, true, 0},
+		{ast.Ln, []interface{}{-1.5}, true, true, 0},
+		{ast.Ln, []interface{}{nil}, true, false, 0},
+		{ast.Log, []interface{}{math.E}, false, false, 1},
+		{ast.Log, []interface{}{"-1"}, true, true, 0},
+		{ast.Log, []interface{}{2, 8}, false, false, 3},
+		{ast.Log, []interface{}{10, 100}, false, false, 2},
+		{ast.Log, []interface{}{1, 100}, true, true, 0},
+		{ast.Log, []interface{}{0, 100}, true, true, 0},
+		{ast.Log, []interface{}{2, 0}, true, true, 0},
+		{ast.Log, []interface{}{nil, 0}, true, false, 0},
+		{ast.Log2, []interface{}{8}, false, false, 3},
+		{ast.Log2, []interface{}{-8}, true, true, 0},
+		{ast.Log10, []interface{}{100}, false, false, 2},
+		{ast.Log10, []interface{}{0.001}, false, false, -3},
+		{ast.Log10, []interface{}{0}, true, true, 0},
+	}
+	for _, t := range cases {
+		warnCnt := s.ctx.GetSessionVars().StmtCtx.WarningCount()
+		f, err := newFunctionForTest(s.ctx, t.funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%s%v", t.funcName, t.args))
+		} else {
+			c.Assert(d.GetFloat64(), Equals, t.res, Commentf("%s%v", t.funcName, t.args))
+		}
+		if t.warn {
+			c.Assert(s.ctx.GetSessionVars().StmtCtx.WarningCount(), Equals, warnCnt+1, Commentf("%s%v", t.funcName, t.args))
+			warns := s.ctx.GetSessionVars().StmtCtx.GetWarnings()
+			c.Assert(terror.ErrorEqual(warns[len(warns)-1].Err, errInvalidArgForLogarithm), IsTrue)
+		} else {
+			c.Assert(s.ctx.GetSessionVars().StmtCtx.WarningCount(), Equals, warnCnt, Commentf("%s%v", t.funcName, t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestPow(c *C) {
+	cases := []struct {
+		funcName string
+		args     []interface{}
+		isNil    bool
+		res      float64
+	}{
+		{ast.Pow, []interface{}{2, 10}, false, 1024},
+		{ast.Pow, []interface{}{2, -1}, false, 0.5},
+		{ast.Pow, []interface{}{4, 0.5}, false, 2},
+		{ast.Power, []interface{}{-2, 3}, false, -8},
+		{ast.Power, []interface{}{"2", "3"}, false, 8},
+		{ast.Pow, []interface{}{nil, 3}, true, 0},
+		{ast.Pow, []interface{}{2, nil}, true, 0},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, t.funcName, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%s%v", t.funcName, t.args))
+		} else {
+			c.Assert(d.GetFloat64(), Equals, t.res, Commentf("%s%v", t.funcName, t.args))
+		}
+	}
+
+	// The results which are not finite numbers overflow.
+	for _, args := range [][]interface{}{{10, 400}, {0, -1}, {-8, 1.0 / 3}} {
+		f, err := newFunctionForTest(s.ctx, ast.Pow, s.primitiveValsToConstants(args)...)
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(types.ErrOverflow.Equal(err), IsTrue, Commentf("%v err %v", args, err))
+	}
+}
+
+func (s *testEvaluatorSuite) TestRound(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		res   interface{}
+	}{
+		{[]interface{}{2.5}, false, 3.0},
+		{[]interface{}{-2.5}, false, -3.0},
+		{[]interface{}{1.4}, false, 1.0},
+		{[]interface{}{123}, false, int64(123)},
+		{[]interface{}{1.26, 1}, false, 1.3},
+		{[]interface{}{-1.26, 1}, false, -1.3},
+		{[]interface{}{1.5, 10}, false, 1.5},
+		{[]interface{}{1250.5, -2}, false, 1300.0},
+		{[]interface{}{1250.5, -400}, false, 0.0},
+		{[]interface{}{1250, -2}, false, int64(1300)},
+		{[]interface{}{-1250, -2}, false, int64(-1300)},
+		{[]interface{}{1249, -2}, false, int64(1200)},
+		{[]interface{}{1249, 2}, false, int64(1249)},
+		{[]interface{}{1249, -30}, false, int64(0)},
+		{[]interface{}{int64(math.MinInt64), -2}, false, int64(-9223372036854775800)},
+		{[]interface{}{uint64(math.MaxUint64), -18}, false, uint64(18000000000000000000)},
+		{[]interface{}{uint64(math.MaxUint64), -20}, false, uint64(0)},
+		{[]interface{}{"1.5"}, false, 2.0},
+		{[]interface{}{nil}, true, nil},
+		{[]interface{}{1.5, nil}, true, nil},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Round, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil, Commentf("%v", t.args))
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%v", t.args))
+		} else {
+			c.Assert(d.GetValue(), Equals, t.res, Commentf("%v", t.args))
+		}
+	}
+
+	for _, args := range [][]interface{}{{int64(math.MaxInt64), -1}, {int64(math.MinInt64), -1}, {uint64(math.MaxUint64), -1}, {uint64(math.MaxUint64), -19}} {
+		f, err := newFunctionForTest(s.ctx, ast.Round, s.primitiveValsToConstants(args)...)
+		c.Assert(err, IsNil)
+		_, err = f.Eval(chunk.Row{})
+		c.Assert(types.ErrOverflow.Equal(err), IsTrue, Commentf("%v err %v", args, err))
+	}
+}
+
+func (s *testEvaluatorSuite) TestTruncate(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		res   interface{}
+	}{
+		{[]interface{}{1.29, 1}, false, 1.2},
+		{[]interface{}{-1.29, 1}, false, -1.2},
+		{[]interface{}{1.29, 0}, false, 1.0},
+		{[]interface{}{1299.5, -2}, false, 1200.0},
+		{[]interface{}{1299.5, -400}, false, 0.0},
+		{[]interface{}{1299, -2}, false, int64(1200)},
+		{[]interface{}{-1299, -2}, false, int64(-1200)},
+		{[]interface{}{1299, 2}, false, int64(1299)},
+		{[]interface{}{int64(math.MaxInt64), -18}, false, int64(9000000000000000000)},
+		{[]interface{}{int64(math.MaxInt64), -19}, false, int64(0)},
+		{[]interface{}{uint64(math.MaxUint64), -19}, false, uint64(10000000000000000000)},
+		{[]interface{}{uint64(math.MaxUint64), -20}, false, uint64(0)},
+		{[]interface{}{"1.29", "1"}, false, 1.2},
+		{[]interface{}{nil, 1}, true, nil},
+		{[]interface{}{1.29, nil}, true, nil},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Truncate, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil, Commentf("%v", t.args))
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%v", t.args))
+		} else {
+			c.Assert(d.GetValue(), Equals, t.res, Commentf("%v", t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestConv(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		res   string
+	}{
+		{[]interface{}{"a", 16, 2}, false, "1010"},
+		{[]interface{}{"6E", 18, 8}, false, "172"},
+		{[]interface{}{"-17", 10, -18}, false, "-H"},
+		{[]interface{}{"-17", 10, 10}, false, "18446744073709551599"},
+		{[]interface{}{"-17", -10, -10}, false, "-17"},
+		{[]interface{}{10 + 10, 10, 16}, false, "14"},
+		{[]interface{}{"  12abc", 10, 10}, false, "12"},
+		{[]interface{}{"+12", 10, 10}, false, "12"},
+		{[]interface{}{"xyz", 10, 10}, false, "0"},
+		{[]interface{}{"", 10, 10}, false, "0"},
+		{[]interface{}{"FFFFFFFFFFFFFFFFFF", 16, 10}, false, "18446744073709551615"},
+		{[]interface{}{"FFFFFFFFFFFFFFFFFF", -16, 10}, false, "9223372036854775807"},
+		{[]interface{}{"-FFFFFFFFFFFFFFFFFF", -16, -10}, false, "-9223372036854775808"},
+		{[]interface{}{"zz", 36, 10}, false, "1295"},
+		{[]interface{}{"10", 1, 10}, true, ""},
+		{[]interface{}{"10", 10, 37}, true, ""},
+		{[]interface{}{nil, 10, 10}, true, ""},
+		{[]interface{}{"10", nil, 10}, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Conv, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%v", t.args))
+		} else {
+			c.Assert(d.GetString(), Equals, t.res, Commentf("%v", t.args))
+		}
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalLogarithm evaluates the logarithm of the first argument of b on the
+// whole input, the results of the arguments which are not positive are NULL.
+func vecEvalLogarithm(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, log func(float64) float64) error {
+	if err := vecEvalArgAsReal(b.ctx, b.args[0], input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if f64s[i] <= 0 {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(errInvalidArgForLogarithm)
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = log(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinLnSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLnSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLogarithm(&b.baseBuiltinFunc, input, result, math.Log)
+}
+
+func (b *builtinLog1ArgSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog1ArgSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLogarithm(&b.baseBuiltinFunc, input, result, math.Log)
+}
+
+func (b *builtinLog2Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog2Sig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLogarithm(&b.baseBuiltinFunc, input, result, math.Log2)
+}
+
+func (b *builtinLog10Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog10Sig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalLogarithm(&b.baseBuiltinFunc, input, result, math.Log10)
+}
+
+func (b *builtinLog2ArgsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLog2ArgsSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := vecEvalArgAsReal(b.ctx, b.args[0], input, buf); err != nil {
+		return err
+	}
+	if err := vecEvalArgAsReal(b.ctx, b.args[1], input, result); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	bases, f64s := buf.Float64s(), result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, _ := b.log(bases[i], f64s[i])
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinPowSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinPowSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := vecEvalArgAsReal(b.ctx, b.args[0], input, result); err != nil {
+		return err
+	}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETReal, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := vecEvalArgAsReal(b.ctx, b.args[1], input, buf); err != nil {
+		return err
+	}
+
+	result.MergeNulls(buf)
+	x, y := result.Float64s(), buf.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		power, _, err := b.pow(x[i], y[i])
+		if err != nil {
+			return err
+		}
+		x[i] = power
+	}
+	return nil
+}
+
+func (b *builtinRoundIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinRoundRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := vecEvalArgAsReal(b.ctx, b.args[0], input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		f64s[i] = types.RoundFloat(f64s[i])
+	}
+	return nil
+}
+
+// vecEvalIntWithFrac evaluates the integer argument of b into result and the
+// frac argument into the returned column, the nulls of them are merged into
+// result. The caller should put the returned column back to b.bufAllocator.
+func vecEvalIntWithFrac(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column) (*chunk.Column, error) {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return nil, err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := vecEvalArgAsInt(b.ctx, b.args[1], input, buf); err != nil {
+		b.bufAllocator.put(buf)
+		return nil, err
+	}
+	result.MergeNulls(buf)
+	return buf, nil
+}
+
+// vecEvalRealWithFrac is like vecEvalIntWithFrac but evaluates a real argument.
+func vecEvalRealWithFrac(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column) (*chunk.Column, error) {
+	if err := vecEvalArgAsReal(b.ctx, b.args[0], input, result); err != nil {
+		return nil, err
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := vecEvalArgAsInt(b.ctx, b.args[1], input, buf); err != nil {
+		b.bufAllocator.put(buf)
+		return nil, err
+	}
+	result.MergeNulls(buf)
+	return buf, nil
+}
+
+func (b *builtinRoundWithFracIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundWithFracIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := vecEvalIntWithFrac(&b.baseBuiltinFunc, input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	unsigned := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	i64s, fracs := result.Int64s(), buf.Int64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, overflow := roundIntWithFrac(i64s[i], fracs[i], unsigned)
+		if overflow {
+			return roundOverflowErr(b.args)
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinRoundWithFracRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundWithFracRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := vecEvalRealWithFrac(&b.baseBuiltinFunc, input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	f64s, fracs := result.Float64s(), buf.Int64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		f64s[i] = types.Round(f64s[i], int(fracs[i]))
+	}
+	return nil
+}
+
+func (b *builtinTruncateIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTruncateIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := vecEvalIntWithFrac(&b.baseBuiltinFunc, input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	i64s, fracs := result.Int64s(), buf.Int64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = truncateIntWithFrac(i64s[i], fracs[i], false)
+	}
+	return nil
+}
+
+func (b *builtinTruncateUintSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTruncateUintSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := vecEvalIntWithFrac(&b.baseBuiltinFunc, input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	i64s, fracs := result.Int64s(), buf.Int64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = truncateIntWithFrac(i64s[i], fracs[i], true)
+	}
+	return nil
+}
+
+func (b *builtinTruncateRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinTruncateRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := vecEvalRealWithFrac(&b.baseBuiltinFunc, input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+
+	f64s, fracs := result.Float64s(), buf.Int64s()
+	for i := 0; i < input.NumRows(); i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		f64s[i] = types.Truncate(f64s[i], int(fracs[i]))
+	}
+	return nil
+}
+
+func (b *builtinConvSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinConvSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := vecEvalArgAsString(b.ctx, b.args[0], input, strBuf); err != nil {
+		return err
+	}
+	fromBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(fromBuf)
+	if err := vecEvalArgAsInt(b.ctx, b.args[1], input, fromBuf); err != nil {
+		return err
+	}
+	toBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(toBuf)
+	if err := vecEvalArgAsInt(b.ctx, b.args[2], input, toBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	fromBases, toBases := fromBuf.Int64s(), toBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || fromBuf.IsNull(i) || toBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull := conv(strBuf.GetString(i), fromBases[i], toBases[i])
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinMathCases = map[string][]vecExprBenchCase{
+	ast.Ln: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETInt}},
+	},
+	ast.Log: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal, types.ETReal}, geners: []dataGenerator{
+			&rangeRealGener{-1, 10, 0.1}, nil,
+		}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETInt, types.ETString}, geners: []dataGenerator{
+			&rangeInt64Gener{-2, 10}, &numStrGener{rangeInt64Gener{-100, 100}},
+		}},
+	},
+	ast.Log2: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal}},
+	},
+	ast.Log10: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal}},
+	},
+	ast.Pow: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal, types.ETReal}, geners: []dataGenerator{
+			&rangeRealGener{0.1, 10, 0.1}, &rangeRealGener{-10, 10, 0.1},
+		}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETInt, types.ETInt}, geners: []dataGenerator{
+			&rangeInt64Gener{-10, 10}, &rangeInt64Gener{0, 10},
+		}},
+	},
+	ast.Round: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal, types.ETInt}, geners: []dataGenerator{
+			nil, &rangeInt64Gener{-10, 10},
+		}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETReal}, geners: []dataGenerator{
+			&numStrGener{rangeInt64Gener{-1000, 1000}}, &rangeRealGener{-5, 5, 0.1},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETInt}, geners: []dataGenerator{
+			&rangeInt64Gener{math.MinInt32, math.MaxInt32}, &rangeInt64Gener{-12, 3},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETInt},
+			childrenFieldTypes: []*types.FieldType{{Tp: mysql.TypeLonglong, Flag: mysql.UnsignedFlag}},
+			geners:             []dataGenerator{&rangeInt64Gener{0, math.MaxInt32}, &rangeInt64Gener{-12, 3}},
+		},
+	},
+	ast.Truncate: {
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETReal, types.ETInt}, geners: []dataGenerator{
+			nil, &rangeInt64Gener{-10, 10},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETInt}, geners: []dataGenerator{
+			nil, &rangeInt64Gener{-20, 3},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt, types.ETReal},
+			childrenFieldTypes: []*types.FieldType{{Tp: mysql.TypeLonglong, Flag: mysql.UnsignedFlag}},
+			geners:             []dataGenerator{nil, &rangeRealGener{-21, 3, 0.1}},
+		},
+	},
+	ast.Conv: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETInt}, geners: []dataGenerator{
+			nil, &rangeInt64Gener{-40, 40}, &rangeInt64Gener{-40, 40},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETInt, types.ETInt, types.ETInt}, geners: []dataGenerator{
+			nil, &rangeInt64Gener{-10, 10}, &rangeInt64Gener{-36, 36},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETReal, types.ETReal, types.ETInt}, geners: []dataGenerator{
+			nil, &rangeRealGener{-10, 10, 0.1}, &rangeInt64Gener{2, 36},
+		}},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinMathEvalOneVec(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinMathCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinMathFunc(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinMathCases)
+}
+
+func BenchmarkVectorizedBuiltinMathEvalOneVec(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinMathCases)
+}
+
+func BenchmarkVectorizedBuiltinMathFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinMathCases)
+}
@@ -40,6 +40,7 @@ var (
 	_ builtinFunc = &builtinIntIsNullSig{}
 	_ builtinFunc = &builtinRealIsNullSig{}
 	_ builtinFunc = &builtinStringIsNullSig{}
+	_ builtinFunc = &builtinJSONIsNullSig{}
 	_ builtinFunc = &builtinUnaryNotRealSig{}
 	_ builtinFunc = &builtinUnaryNotIntSig{}
 )
@@ -321,6 +322,9 @@ func (c *isNullFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	case types.ETString:
 		sig = &builtinStringIsNullSig{bf}
 		sig.setPbCode(tipb.ScalarFuncSig_StringIsNull)
+	case types.ETJson:
+		sig = &builtinJSONIsNullSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_JsonIsNull)
 	default:
 		panic("unexpected types.EvalType")
 	}
@@ -381,3 +385,18 @@ func (b *builtinStringIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
 	_, isNull, err := b.args[0].EvalString(b.ctx, row)
 	return evalIsNull(isNull, err)
 }
+
+type builtinJSONIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONIsNullSig) Clone() builtinFunc {
+	newSig := &builtinJSONIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinJSONIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	_, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	return evalIsNull(isNull, err)
+}
@@ -85,55 +85,6 @@ func (b *builtinUnaryMinusRealSig) vecEvalReal(input *chunk.Chunk, result *chunk
 	return nil
 }
 
-func (b *builtinIntIsNullSig) vectorized() bool {
-	return true
-}
-
-func (b *builtinIntIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
-	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
-		return err
-	}
-
-	i64s := result.Int64s()
-	for i := 0; i < len(i64s); i++ {
-		if result.IsNull(i) {
-			i64s[i] = 1
-			result.SetNull(i, false)
-		} else {
-			i64s[i] = 0
-		}
-	}
-	return nil
-}
-
-func (b *builtinRealIsNullSig) vectorized() bool {
-	return true
-}
-
-func (b *builtinRealIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
-	numRows := input.NumRows()
-	buf, err := b.bufAllocator.get(types.ETReal, numRows)
-	if err != nil {
-		return err
-	}
-	defer b.bufAllocator.put(buf)
-
-	if err := b.args[0].VecEvalReal(b.ctx, input, buf); err != nil {
-		return err
-	}
-
-	result.ResizeInt64(numRows, false)
-	i64s := result.Int64s()
-	for i := 0; i < numRows; i++ {
-		if buf.IsNull(i) {
-			i64s[i] = 1
-		} else {
-			i64s[i] = 0
-		}
-	}
-	return nil
-}
-
 func (b *builtinUnaryNotRealSig) vectorized() bool {
 	return true
 }
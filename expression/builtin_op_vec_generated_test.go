@@ -0,0 +1,63 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go generate in expression/generator; DO NOT EDIT.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltinOpGeneratedCases = map[string][]vecExprBenchCase{
+	ast.IsNull: {
+		// builtinIntIsNullSig
+		{
+			retEvalType:   types.ETInt,
+			childrenTypes: []types.EvalType{types.ETInt},
+			geners:        []dataGenerator{&defaultGener{eType: types.ETInt, nullRation: 0.5}},
+		},
+		// builtinRealIsNullSig
+		{
+			retEvalType:   types.ETInt,
+			childrenTypes: []types.EvalType{types.ETReal},
+			geners:        []dataGenerator{&defaultGener{eType: types.ETReal, nullRation: 0.5}},
+		},
+		// builtinStringIsNullSig
+		{
+			retEvalType:   types.ETInt,
+			childrenTypes: []types.EvalType{types.ETString},
+			geners:        []dataGenerator{&defaultGener{eType: types.ETString, nullRation: 0.5}},
+		},
+	},
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinOpEvalOneVecGenerated(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltinOpGeneratedCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltinOpFuncGenerated(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltinOpGeneratedCases)
+}
+
+func BenchmarkVectorizedBuiltinOpEvalOneVecGenerated(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltinOpGeneratedCases)
+}
+
+func BenchmarkVectorizedBuiltinOpFuncGenerated(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinOpGeneratedCases)
+}
@@ -44,10 +44,6 @@ var vecBuiltinOpCases = map[string][]vecExprBenchCase{
 			geners:             []dataGenerator{&rangeInt64Gener{0, math.MaxInt64}},
 		},
 	},
-	ast.IsNull: {
-		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETReal}},
-		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETInt}},
-	},
 }
 
 // givenValsGener returns the items sequentially from the slice given at
@@ -14,9 +14,14 @@
 package expression
 
 import (
+	"encoding/hex"
 	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
@@ -31,6 +36,12 @@ var (
 	_ functionClass = &setVarFunctionClass{}
 	_ functionClass = &getVarFunctionClass{}
 	_ functionClass = &valuesFunctionClass{}
+	_ functionClass = &groupingFunctionClass{}
+	_ functionClass = &uuidFunctionClass{}
+	_ functionClass = &uuidShortFunctionClass{}
+	_ functionClass = &isUUIDFunctionClass{}
+	_ functionClass = &uuidToBinFunctionClass{}
+	_ functionClass = &binToUUIDFunctionClass{}
 )
 
 var (
@@ -38,11 +49,21 @@ var (
 	_ builtinFunc = &builtinInStringSig{}
 	_ builtinFunc = &builtinInRealSig{}
 	_ builtinFunc = &builtinRowSig{}
-	_ builtinFunc = &builtinSetVarSig{}
-	_ builtinFunc = &builtinGetVarSig{}
+	_ builtinFunc = &builtinSetStringVarSig{}
+	_ builtinFunc = &builtinSetIntVarSig{}
+	_ builtinFunc = &builtinSetRealVarSig{}
+	_ builtinFunc = &builtinGetStringVarSig{}
+	_ builtinFunc = &builtinGetIntVarSig{}
+	_ builtinFunc = &builtinGetRealVarSig{}
 	_ builtinFunc = &builtinValuesIntSig{}
 	_ builtinFunc = &builtinValuesRealSig{}
 	_ builtinFunc = &builtinValuesStringSig{}
+	_ builtinFunc = &builtinGroupingSig{}
+	_ builtinFunc = &builtinUUIDSig{}
+	_ builtinFunc = &builtinUUIDShortSig{}
+	_ builtinFunc = &builtinIsUUIDSig{}
+	_ builtinFunc = &builtinUUIDToBinSig{}
+	_ builtinFunc = &builtinBinToUUIDSig{}
 )
 
 type inFunctionClass struct {
@@ -138,6 +159,7 @@ func (b *builtinInStringSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if isNull0 || err != nil {
 		return 0, isNull0, err
 	}
+	collator := b.collator()
 	var hasNull bool
 	for _, arg := range b.args[1:] {
 		evaledArg, isNull, err := arg.EvalString(b.ctx, row)
@@ -148,7 +170,7 @@ func (b *builtinInStringSig) evalInt(row chunk.Row) (int64, bool, error) {
 			hasNull = true
 			continue
 		}
-		if arg0 == evaledArg {
+		if collator.Compare(arg0, evaledArg) == 0 {
 			return 1, false, nil
 		}
 	}
@@ -228,38 +250,117 @@ func (c *setVarFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	if err = c.verifyArgs(args); err != nil {
 		return nil, err
 	}
-	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString)
-	bf.tp.Flen = args[1].GetType().Flen
-	// TODO: we should consider the type of the argument, but not take it as string for all situations.
-	sig = &builtinSetVarSig{bf}
-	return sig, err
+	// The variable takes the type of the assigned value, so the expressions reading it later can
+	// be built with the right type.
+	argTp := args[1].GetType()
+	switch argTp.EvalType() {
+	case types.ETInt:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETInt)
+		bf.tp.Flag |= argTp.Flag & mysql.UnsignedFlag
+		sig = &builtinSetIntVarSig{bf}
+	case types.ETReal:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETString, types.ETReal)
+		bf.tp.Flen, bf.tp.Decimal = argTp.Flen, argTp.Decimal
+		sig = &builtinSetRealVarSig{bf}
+	default:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString)
+		bf.tp.Flen = argTp.Flen
+		sig = &builtinSetStringVarSig{bf}
+	}
+	return sig, nil
+}
+
+// setUserVar assigns the value to the user variable, a NULL value removes the variable.
+func setUserVar(ctx sessionctx.Context, name string, d types.Datum, tp *types.FieldType) {
+	ctx.GetSessionVars().SetUserVar(strings.ToLower(name), d, tp)
 }
 
-type builtinSetVarSig struct {
+type builtinSetStringVarSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinSetVarSig) Clone() builtinFunc {
-	newSig := &builtinSetVarSig{}
+func (b *builtinSetStringVarSig) Clone() builtinFunc {
+	newSig := &builtinSetStringVarSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinSetVarSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
-	var varName string
-	sessionVars := b.ctx.GetSessionVars()
-	varName, isNull, err = b.args[0].EvalString(b.ctx, row)
+func (b *builtinSetStringVarSig) evalString(row chunk.Row) (string, bool, error) {
+	varName, isNull, err := b.args[0].EvalString(b.ctx, row)
 	if isNull || err != nil {
 		return "", isNull, err
 	}
-	res, isNull, err = b.args[1].EvalString(b.ctx, row)
-	if isNull || err != nil {
+	res, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if err != nil {
 		return "", isNull, err
 	}
-	varName = strings.ToLower(varName)
-	sessionVars.UsersLock.Lock()
-	sessionVars.Users[varName] = stringutil.Copy(res)
-	sessionVars.UsersLock.Unlock()
+	if isNull {
+		setUserVar(b.ctx, varName, types.Datum{}, b.tp)
+		return "", true, nil
+	}
+	res = stringutil.Copy(res)
+	setUserVar(b.ctx, varName, types.NewStringDatum(res), b.tp)
+	return res, false, nil
+}
+
+type builtinSetIntVarSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSetIntVarSig) Clone() builtinFunc {
+	newSig := &builtinSetIntVarSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinSetIntVarSig) evalInt(row chunk.Row) (int64, bool, error) {
+	varName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if err != nil {
+		return 0, isNull, err
+	}
+	if isNull {
+		setUserVar(b.ctx, varName, types.Datum{}, b.tp)
+		return 0, true, nil
+	}
+	setUserVar(b.ctx, varName, intVarDatum(res, b.tp), b.tp)
+	return res, false, nil
+}
+
+func intVarDatum(val int64, tp *types.FieldType) types.Datum {
+	if mysql.HasUnsignedFlag(tp.Flag) {
+		return types.NewUintDatum(uint64(val))
+	}
+	return types.NewIntDatum(val)
+}
+
+type builtinSetRealVarSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSetRealVarSig) Clone() builtinFunc {
+	newSig := &builtinSetRealVarSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinSetRealVarSig) evalReal(row chunk.Row) (float64, bool, error) {
+	varName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	res, isNull, err := b.args[1].EvalReal(b.ctx, row)
+	if err != nil {
+		return 0, isNull, err
+	}
+	if isNull {
+		setUserVar(b.ctx, varName, types.Datum{}, b.tp)
+		return 0, true, nil
+	}
+	setUserVar(b.ctx, varName, types.NewFloat64Datum(res), b.tp)
 	return res, false, nil
 }
 
@@ -271,36 +372,107 @@ func (c *getVarFunctionClass) getFunction(ctx sessionctx.Context, args []Express
 	if err = c.verifyArgs(args); err != nil {
 		return nil, err
 	}
-	// TODO: we should consider the type of the argument, but not take it as string for all situations.
-	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
-	bf.tp.Flen = mysql.MaxFieldVarCharLength
-	sig = &builtinGetVarSig{bf}
+	// The expression takes the type of the current value of the variable, an undefined variable is
+	// a NULL string.
+	var varTp *types.FieldType
+	if name, ok := args[0].(*Constant); ok && name.DeferredExpr == nil && name.Value.Kind() == types.KindString {
+		varTp, _ = ctx.GetSessionVars().GetUserVarType(strings.ToLower(name.Value.GetString()))
+	}
+	if varTp == nil {
+		varTp = types.NewFieldType(mysql.TypeVarString)
+	}
+	switch varTp.EvalType() {
+	case types.ETInt:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString)
+		bf.tp.Flag |= varTp.Flag & mysql.UnsignedFlag
+		sig = &builtinGetIntVarSig{bf}
+	case types.ETReal:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETString)
+		sig = &builtinGetRealVarSig{bf}
+	default:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+		bf.tp.Flen = mysql.MaxFieldVarCharLength
+		sig = &builtinGetStringVarSig{bf}
+	}
 	return sig, nil
 }
 
-type builtinGetVarSig struct {
+// getUserVar gets the value of the user variable, the value may be assigned with another type
+// after the expression is built, so the caller converts it to its own type.
+func getUserVar(ctx sessionctx.Context, name string) (types.Datum, bool) {
+	return ctx.GetSessionVars().GetUserVar(strings.ToLower(name))
+}
+
+type builtinGetStringVarSig struct {
 	baseBuiltinFunc
 }
 
-func (b *builtinGetVarSig) Clone() builtinFunc {
-	newSig := &builtinGetVarSig{}
+func (b *builtinGetStringVarSig) Clone() builtinFunc {
+	newSig := &builtinGetStringVarSig{}
 	newSig.cloneFrom(&b.baseBuiltinFunc)
 	return newSig
 }
 
-func (b *builtinGetVarSig) evalString(row chunk.Row) (string, bool, error) {
-	sessionVars := b.ctx.GetSessionVars()
+func (b *builtinGetStringVarSig) evalString(row chunk.Row) (string, bool, error) {
 	varName, isNull, err := b.args[0].EvalString(b.ctx, row)
 	if isNull || err != nil {
 		return "", isNull, err
 	}
-	varName = strings.ToLower(varName)
-	sessionVars.UsersLock.RLock()
-	defer sessionVars.UsersLock.RUnlock()
-	if v, ok := sessionVars.Users[varName]; ok {
-		return v, false, nil
+	d, ok := getUserVar(b.ctx, varName)
+	if !ok {
+		return "", true, nil
 	}
-	return "", true, nil
+	res, err := d.ToString()
+	return res, err != nil, err
+}
+
+type builtinGetIntVarSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGetIntVarSig) Clone() builtinFunc {
+	newSig := &builtinGetIntVarSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGetIntVarSig) evalInt(row chunk.Row) (int64, bool, error) {
+	varName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, ok := getUserVar(b.ctx, varName)
+	if !ok {
+		return 0, true, nil
+	}
+	if d.Kind() == types.KindUint64 {
+		return int64(d.GetUint64()), false, nil
+	}
+	res, err := d.ToInt64(b.ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
+}
+
+type builtinGetRealVarSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGetRealVarSig) Clone() builtinFunc {
+	newSig := &builtinGetRealVarSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinGetRealVarSig) evalReal(row chunk.Row) (float64, bool, error) {
+	varName, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	d, ok := getUserVar(b.ctx, varName)
+	if !ok {
+		return 0, true, nil
+	}
+	res, err := d.ToFloat64(b.ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
 }
 
 type valuesFunctionClass struct {
@@ -431,3 +603,324 @@ func (b *builtinValuesStringSig) evalString(_ chunk.Row) (string, bool, error) {
 
 	return row.GetString(b.offset), false, nil
 }
+
+// groupingFunctionClass is the class of the GROUPING function used with ROLLUP.
+// The planner rewrites GROUPING(a, b) to grouping(gid, pos(a), pos(b)), where gid is the
+// grouping ID generated by the Expand operator and pos(x) is the bit of x in gid.
+type groupingFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *groupingFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range args {
+		argTps[i] = types.ETInt
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	if len(args) > 2 {
+		bf.tp.Flen = mysql.MaxIntWidth
+	}
+	bf.tp.Flag |= mysql.NotNullFlag
+	sig := &builtinGroupingSig{bf}
+	return sig, nil
+}
+
+type builtinGroupingSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinGroupingSig) Clone() builtinFunc {
+	newSig := &builtinGroupingSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinGroupingSig.
+// It returns a bitmask whose i-th highest bit is 1 if the i-th argument is rolled up in the row.
+func (b *builtinGroupingSig) evalInt(row chunk.Row) (int64, bool, error) {
+	gid, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	var res int64
+	for _, arg := range b.args[1:] {
+		pos, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res = res<<1 | (gid>>uint64(pos))&1
+	}
+	return res, false, nil
+}
+
+type uuidFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *uuidFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString)
+	bf.tp.Flen = 36
+	sig := &builtinUUIDSig{bf}
+	return sig, nil
+}
+
+type builtinUUIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUUIDSig) Clone() builtinFunc {
+	newSig := &builtinUUIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUUIDSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/miscellaneous-functions.html#function_uuid
+func (b *builtinUUIDSig) evalString(_ chunk.Row) (string, bool, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return "", true, err
+	}
+	return id.String(), false, nil
+}
+
+// uuidShortCounter holds the last value returned by UUID_SHORT. It starts from the startup time of
+// the server in seconds shifted left by 24 bits, the server id part of MySQL is always 0.
+var uuidShortCounter = uint64(time.Now().Unix()) << 24
+
+type uuidShortFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *uuidShortFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+	bf.tp.Flen = 20
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinUUIDShortSig{bf}
+	return sig, nil
+}
+
+type builtinUUIDShortSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUUIDShortSig) Clone() builtinFunc {
+	newSig := &builtinUUIDShortSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinUUIDShortSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_uuid-short
+func (b *builtinUUIDShortSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	return int64(atomic.AddUint64(&uuidShortCounter, 1)), false, nil
+}
+
+// parseUUID parses a string UUID in one of the formats accepted by MySQL: 32 hexadecimal digits,
+// optionally grouped by dashes as 8-4-4-4-12, and the grouped ones optionally enclosed in braces.
+func parseUUID(s string) ([]byte, bool) {
+	if len(s) == 38 && s[0] == '{' && s[37] == '}' {
+		s = s[1:37]
+		if len(s) != 36 {
+			return nil, false
+		}
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return nil, false
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return nil, false
+	}
+	bin, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return bin, true
+}
+
+type isUUIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *isUUIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString)
+	bf.tp.Flen = 1
+	sig := &builtinIsUUIDSig{bf}
+	return sig, nil
+}
+
+type builtinIsUUIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIsUUIDSig) Clone() builtinFunc {
+	newSig := &builtinIsUUIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinIsUUIDSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_is-uuid
+func (b *builtinIsUUIDSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if _, ok := parseUUID(val); ok {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+// getUUIDFuncArgTps returns the argument types of UUID_TO_BIN and BIN_TO_UUID, which take
+// the UUID and an optional swap flag.
+func getUUIDFuncArgTps(args []Expression) []types.EvalType {
+	argTps := []types.EvalType{types.ETString}
+	if len(args) == 2 {
+		argTps = append(argTps, types.ETInt)
+	}
+	return argTps
+}
+
+// evalUUIDSwapFlag evaluates the optional swap flag of UUID_TO_BIN and BIN_TO_UUID, a NULL flag
+// doesn't swap.
+func evalUUIDSwapFlag(ctx sessionctx.Context, args []Expression, row chunk.Row) (bool, error) {
+	if len(args) < 2 {
+		return false, nil
+	}
+	flag, isNull, err := args[1].EvalInt(ctx, row)
+	if isNull || err != nil {
+		return false, err
+	}
+	return flag != 0, nil
+}
+
+// uuidToBin converts a string UUID to its 16 bytes binary form. If swap is true, the time-low
+// and time-high parts are swapped, so the binary values of the time-based UUIDs increase.
+func uuidToBin(s string, swap bool) (string, error) {
+	bin, ok := parseUUID(s)
+	if !ok {
+		return "", errWrongValueForType.GenWithStackByArgs("string", s, ast.UUIDToBin)
+	}
+	if swap {
+		swapped := make([]byte, 0, len(bin))
+		swapped = append(swapped, bin[6:8]...)
+		swapped = append(swapped, bin[4:6]...)
+		swapped = append(swapped, bin[0:4]...)
+		bin = append(swapped, bin[8:]...)
+	}
+	return string(bin), nil
+}
+
+// binToUUID converts the 16 bytes binary form of a UUID to a string UUID, it's the inverse of uuidToBin.
+func binToUUID(bin string, swap bool) (string, error) {
+	if len(bin) != 16 {
+		return "", errWrongValueForType.GenWithStackByArgs("string", bin, ast.BinToUUID)
+	}
+	if swap {
+		bin = bin[4:8] + bin[2:4] + bin[0:2] + bin[8:]
+	}
+	hexStr := hex.EncodeToString([]byte(bin))
+	return hexStr[:8] + "-" + hexStr[8:12] + "-" + hexStr[12:16] + "-" + hexStr[16:20] + "-" + hexStr[20:], nil
+}
+
+type uuidToBinFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *uuidToBinFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, getUUIDFuncArgTps(args)...)
+	bf.tp.Flen = 16
+	types.SetBinChsClnFlag(bf.tp)
+	sig := &builtinUUIDToBinSig{bf}
+	return sig, nil
+}
+
+type builtinUUIDToBinSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUUIDToBinSig) Clone() builtinFunc {
+	newSig := &builtinUUIDToBinSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinUUIDToBinSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_uuid-to-bin
+func (b *builtinUUIDToBinSig) evalString(row chunk.Row) (string, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	swap, err := evalUUIDSwapFlag(b.ctx, b.args, row)
+	if err != nil {
+		return "", true, err
+	}
+	res, err := uuidToBin(val, swap)
+	if err != nil {
+		return "", true, err
+	}
+	return res, false, nil
+}
+
+type binToUUIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *binToUUIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, getUUIDFuncArgTps(args)...)
+	bf.tp.Flen = 36
+	sig := &builtinBinToUUIDSig{bf}
+	return sig, nil
+}
+
+type builtinBinToUUIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBinToUUIDSig) Clone() builtinFunc {
+	newSig := &builtinBinToUUIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals a builtinBinToUUIDSig.
+// See https://dev.mysql.com/doc/refman/8.0/en/miscellaneous-functions.html#function_bin-to-uuid
+func (b *builtinBinToUUIDSig) evalString(row chunk.Row) (string, bool, error) {
+	val, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	swap, err := evalUUIDSwapFlag(b.ctx, b.args, row)
+	if err != nil {
+		return "", true, err
+	}
+	res, err := binToUUID(val, swap)
+	if err != nil {
+		return "", true, err
+	}
+	return res, false, nil
+}
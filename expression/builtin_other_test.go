@@ -14,13 +14,16 @@
 package expression
 
 import (
+	"encoding/hex"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/testutil"
 	"math"
+	"strings"
 )
 
 func (s *testEvaluatorSuite) TestInFunc(c *C) {
@@ -82,7 +85,9 @@ func (s *testEvaluatorSuite) TestSetVar(c *C) {
 			c.Assert(ok, Equals, true)
 			val, ok := tc.res.(string)
 			c.Assert(ok, Equals, true)
-			c.Assert(s.ctx.GetSessionVars().Users[key], Equals, val)
+			d, ok := s.ctx.GetSessionVars().GetUserVar(key)
+			c.Assert(ok, IsTrue)
+			c.Assert(d.GetString(), Equals, val)
 		}
 	}
 }
@@ -99,7 +104,7 @@ func (s *testEvaluatorSuite) TestGetVar(c *C) {
 		{"c", ""},
 	}
 	for _, kv := range sessionVars {
-		s.ctx.GetSessionVars().Users[kv.key] = kv.val
+		s.ctx.GetSessionVars().SetUserVar(kv.key, types.NewStringDatum(kv.val), types.NewFieldType(mysql.TypeVarString))
 	}
 
 	testCases := []struct {
@@ -189,8 +194,189 @@ func (s *testEvaluatorSuite) TestSetVarFromColumn(c *C) {
 	inputChunk.AppendString(0, "b")
 
 	// Check whether the user variable changed.
+	d, ok := s.ctx.GetSessionVars().GetUserVar("a")
+	c.Assert(ok, IsTrue)
+	c.Assert(d.GetString(), Equals, "a")
+}
+
+func (s *testEvaluatorSuite) TestUserVarType(c *C) {
 	sessionVars := s.ctx.GetSessionVars()
-	sessionVars.UsersLock.RLock()
-	defer sessionVars.UsersLock.RUnlock()
-	c.Assert(sessionVars.Users["a"], Equals, "a")
+	sc := sessionVars.StmtCtx
+	name := DatumToConstant(types.NewStringDatum("x"), mysql.TypeString)
+	tests := []struct {
+		val      types.Datum
+		tp       *types.FieldType
+		evalTp   types.EvalType
+		getVar   builtinFunc
+		unsigned bool
+		str      string
+	}{
+		{types.NewIntDatum(-3), types.NewFieldType(mysql.TypeLonglong), types.ETInt, &builtinGetIntVarSig{}, false, "-3"},
+		{types.NewUintDatum(7), &types.FieldType{Tp: mysql.TypeLonglong, Flag: mysql.UnsignedFlag}, types.ETInt, &builtinGetIntVarSig{}, true, "7"},
+		{types.NewFloat64Datum(1.5), types.NewFieldType(mysql.TypeDouble), types.ETReal, &builtinGetRealVarSig{}, false, "1.5"},
+		{types.NewStringDatum("12"), types.NewFieldType(mysql.TypeVarString), types.ETString, &builtinGetStringVarSig{}, false, "12"},
+	}
+	for _, t := range tests {
+		comment := Commentf("%v", t.val)
+		// The assignment keeps the type of the value.
+		setVar, err := NewFunction(s.ctx, ast.SetVar, types.NewFieldType(mysql.TypeUnspecified), name, &Constant{Value: t.val, RetType: t.tp})
+		c.Assert(err, IsNil)
+		c.Assert(setVar.GetType().EvalType(), Equals, t.evalTp, comment)
+		_, err = setVar.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		tp, ok := sessionVars.GetUserVarType("x")
+		c.Assert(ok, IsTrue)
+		c.Assert(tp.EvalType(), Equals, t.evalTp, comment)
+
+		// The read takes the type of the current value.
+		getVar, err := NewFunction(s.ctx, ast.GetVar, types.NewFieldType(mysql.TypeUnspecified), name)
+		c.Assert(err, IsNil)
+		c.Assert(getVar.(*ScalarFunction).Function, FitsTypeOf, t.getVar, comment)
+		c.Assert(mysql.HasUnsignedFlag(getVar.GetType().Flag), Equals, t.unsigned, comment)
+		d, err := getVar.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		cmp, err := d.CompareDatum(sc, &t.val)
+		c.Assert(err, IsNil)
+		c.Assert(cmp, Equals, 0, comment)
+
+		// The variable may be assigned with another type after the read is built.
+		sessionVars.SetUserVar("x", types.NewStringDatum(t.str), types.NewFieldType(mysql.TypeVarString))
+		d, err = getVar.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		cmp, err = d.CompareDatum(sc, &t.val)
+		c.Assert(err, IsNil)
+		c.Assert(cmp, Equals, 0, comment)
+	}
+
+	// A NULL value removes the variable.
+	setVar, err := NewFunction(s.ctx, ast.SetVar, types.NewFieldType(mysql.TypeUnspecified), name, &Constant{Value: types.Datum{}, RetType: types.NewFieldType(mysql.TypeLonglong)})
+	c.Assert(err, IsNil)
+	d, err := setVar.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.IsNull(), IsTrue)
+	_, ok := sessionVars.GetUserVar("x")
+	c.Assert(ok, IsFalse)
+	getVar, err := NewFunction(s.ctx, ast.GetVar, types.NewFieldType(mysql.TypeUnspecified), name)
+	c.Assert(err, IsNil)
+	c.Assert(getVar.(*ScalarFunction).Function, FitsTypeOf, &builtinGetStringVarSig{})
+}
+
+func (s *testEvaluatorSuite) TestUUID(c *C) {
+	fn, err := funcs[ast.UUID].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	uuids := make(map[string]struct{})
+	for i := 0; i < 3; i++ {
+		d, err := evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		parts := strings.Split(d.GetString(), "-")
+		c.Assert(parts, HasLen, 5)
+		for j, l := range []int{8, 4, 4, 4, 12} {
+			c.Assert(parts[j], HasLen, l)
+		}
+		uuids[d.GetString()] = struct{}{}
+	}
+	c.Assert(uuids, HasLen, 3)
+
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}, 3)
+	for i := 0; i < 3; i++ {
+		input.AppendInt64(0, int64(i))
+	}
+	result := chunk.NewColumn(types.NewFieldType(mysql.TypeVarString), 3)
+	c.Assert(fn.vecEvalString(input, result), IsNil)
+	for i := 0; i < 3; i++ {
+		c.Assert(result.GetString(i), HasLen, 36)
+		uuids[result.GetString(i)] = struct{}{}
+	}
+	c.Assert(uuids, HasLen, 6)
+}
+
+func (s *testEvaluatorSuite) TestUUIDShort(c *C) {
+	fn, err := funcs[ast.UUIDShort].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	c.Assert(mysql.HasUnsignedFlag(fn.getRetTp().Flag), IsTrue)
+	d, err := evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err, IsNil)
+	last := d.GetUint64()
+
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}, 3)
+	for i := 0; i < 3; i++ {
+		input.AppendInt64(0, int64(i))
+	}
+	result := chunk.NewColumn(types.NewFieldType(mysql.TypeLonglong), 3)
+	c.Assert(fn.vecEvalInt(input, result), IsNil)
+	// Each row gets a new value.
+	for i := 0; i < 3; i++ {
+		c.Assert(uint64(result.GetInt64(i)), Greater, last)
+		last = uint64(result.GetInt64(i))
+	}
+}
+
+func (s *testEvaluatorSuite) TestIsUUID(c *C) {
+	tbl := []struct {
+		arg interface{}
+		ret interface{}
+	}{
+		{"6ccd780c-baba-1026-9564-5b8c656024db", 1},
+		{"6CCD780C-BABA-1026-9564-5B8C656024DB", 1},
+		{"6ccd780cbaba102695645b8c656024db", 1},
+		{"{6ccd780c-baba-1026-9564-5b8c656024db}", 1},
+		{"{6ccd780cbaba102695645b8c656024db}", 0},
+		{"6ccd780c-baba-1026-9564-5b8c6560", 0},
+		{"6ccd780c_baba_1026_9564_5b8c656024db", 0},
+		{"6ccd780g-baba-1026-9564-5b8c656024db", 0},
+		{"", 0},
+		{nil, nil},
+	}
+	for _, t := range tbl {
+		fn, err := funcs[ast.IsUUID].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(t.arg)))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d, testutil.DatumEquals, types.NewDatum(t.ret), Commentf("%v", t.arg))
+	}
+}
+
+func (s *testEvaluatorSuite) TestUUIDToBinAndBinToUUID(c *C) {
+	tbl := []struct {
+		uuid string
+		swap interface{}
+		bin  string
+	}{
+		{"6ccd780c-baba-1026-9564-5b8c656024db", nil, "6CCD780CBABA102695645B8C656024DB"},
+		{"6ccd780c-baba-1026-9564-5b8c656024db", 0, "6CCD780CBABA102695645B8C656024DB"},
+		{"6ccd780c-baba-1026-9564-5b8c656024db", 1, "1026BABA6CCD780C95645B8C656024DB"},
+	}
+	for _, t := range tbl {
+		args := types.MakeDatums(t.uuid)
+		if t.swap != nil {
+			args = append(args, types.NewDatum(t.swap))
+		}
+		fn, err := funcs[ast.UUIDToBin].getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		d, err := evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(strings.ToUpper(hex.EncodeToString(d.GetBytes())), Equals, t.bin)
+
+		args[0] = d
+		fn, err = funcs[ast.BinToUUID].getFunction(s.ctx, s.datumsToConstants(args))
+		c.Assert(err, IsNil)
+		d, err = evalBuiltinFunc(fn, chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.GetString(), Equals, t.uuid)
+	}
+
+	fn, err := funcs[ast.UUIDToBin].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums(nil)))
+	c.Assert(err, IsNil)
+	d, err := evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.IsNull(), IsTrue)
+
+	fn, err = funcs[ast.UUIDToBin].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("abc")))
+	c.Assert(err, IsNil)
+	_, err = evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err.Error(), Equals, "[expression:1411]Incorrect string value: 'abc' for function uuid_to_bin")
+	fn, err = funcs[ast.BinToUUID].getFunction(s.ctx, s.datumsToConstants(types.MakeDatums("abc")))
+	c.Assert(err, IsNil)
+	_, err = evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err.Error(), Equals, "[expression:1411]Incorrect string value: 'abc' for function bin_to_uuid")
 }
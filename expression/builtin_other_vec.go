@@ -14,8 +14,9 @@
 package expression
 
 import (
-	"strings"
+	"sync/atomic"
 
+	"github.com/google/uuid"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -54,11 +55,11 @@ func (b *builtinValuesStringSig) vecEvalString(input *chunk.Chunk, result *chunk
 	return errors.Errorf("not implemented")
 }
 
-func (b *builtinSetVarSig) vectorized() bool {
+func (b *builtinSetStringVarSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinSetVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+func (b *builtinSetStringVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	buf0, err := b.bufAllocator.get(types.ETString, n)
 	if err != nil {
@@ -76,28 +77,94 @@ func (b *builtinSetVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Colum
 	if err := b.args[1].VecEvalString(b.ctx, input, buf1); err != nil {
 		return err
 	}
+	// The variables are assigned in the order of the rows, so the last row wins.
 	result.ReserveString(n)
-	sessionVars := b.ctx.GetSessionVars()
-	sessionVars.UsersLock.Lock()
-	defer sessionVars.UsersLock.Unlock()
 	for i := 0; i < n; i++ {
-		if buf0.IsNull(i) || buf1.IsNull(i) {
+		if buf0.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		if buf1.IsNull(i) {
+			setUserVar(b.ctx, buf0.GetString(i), types.Datum{}, b.tp)
 			result.AppendNull()
 			continue
 		}
-		varName := strings.ToLower(buf0.GetString(i))
-		res := buf1.GetString(i)
-		sessionVars.Users[varName] = stringutil.Copy(res)
+		res := stringutil.Copy(buf1.GetString(i))
+		setUserVar(b.ctx, buf0.GetString(i), types.NewStringDatum(res), b.tp)
 		result.AppendString(res)
 	}
 	return nil
 }
 
-func (b *builtinGetVarSig) vectorized() bool {
+func (b *builtinSetIntVarSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSetIntVarSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	if err := b.args[1].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if buf0.IsNull(i) {
+			result.SetNull(i, true)
+			continue
+		}
+		if result.IsNull(i) {
+			setUserVar(b.ctx, buf0.GetString(i), types.Datum{}, b.tp)
+			continue
+		}
+		setUserVar(b.ctx, buf0.GetString(i), intVarDatum(i64s[i], b.tp), b.tp)
+	}
+	return nil
+}
+
+func (b *builtinSetRealVarSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSetRealVarSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	if err := b.args[1].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if buf0.IsNull(i) {
+			result.SetNull(i, true)
+			continue
+		}
+		if result.IsNull(i) {
+			setUserVar(b.ctx, buf0.GetString(i), types.Datum{}, b.tp)
+			continue
+		}
+		setUserVar(b.ctx, buf0.GetString(i), types.NewFloat64Datum(f64s[i]), b.tp)
+	}
+	return nil
+}
+
+func (b *builtinGetStringVarSig) vectorized() bool {
 	return true
 }
 
-func (b *builtinGetVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+func (b *builtinGetStringVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
 	n := input.NumRows()
 	buf0, err := b.bufAllocator.get(types.ETString, n)
 	if err != nil {
@@ -108,20 +175,222 @@ func (b *builtinGetVarSig) vecEvalString(input *chunk.Chunk, result *chunk.Colum
 		return err
 	}
 	result.ReserveString(n)
-	sessionVars := b.ctx.GetSessionVars()
-	sessionVars.UsersLock.Lock()
-	defer sessionVars.UsersLock.Unlock()
 	for i := 0; i < n; i++ {
 		if buf0.IsNull(i) {
 			result.AppendNull()
 			continue
 		}
-		varName := strings.ToLower(buf0.GetString(i))
-		if v, ok := sessionVars.Users[varName]; ok {
-			result.AppendString(v)
+		d, ok := getUserVar(b.ctx, buf0.GetString(i))
+		if !ok {
+			result.AppendNull()
+			continue
+		}
+		res, err := d.ToString()
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinGetIntVarSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGetIntVarSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf0)
+	i64s := result.Int64s()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		d, ok := getUserVar(b.ctx, buf0.GetString(i))
+		if !ok {
+			result.SetNull(i, true)
+			continue
+		}
+		if d.Kind() == types.KindUint64 {
+			i64s[i] = int64(d.GetUint64())
 			continue
 		}
-		result.AppendNull()
+		if i64s[i], err = d.ToInt64(sc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinGetRealVarSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinGetRealVarSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf0)
+	f64s := result.Float64s()
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		d, ok := getUserVar(b.ctx, buf0.GetString(i))
+		if !ok {
+			result.SetNull(i, true)
+			continue
+		}
+		if f64s[i], err = d.ToFloat64(sc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinUUIDSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUUIDSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		id, err := uuid.NewUUID()
+		if err != nil {
+			return err
+		}
+		result.AppendString(id.String())
+	}
+	return nil
+}
+
+func (b *builtinUUIDShortSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUUIDShortSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = int64(atomic.AddUint64(&uuidShortCounter, 1))
+	}
+	return nil
+}
+
+func (b *builtinIsUUIDSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIsUUIDSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if _, ok := parseUUID(buf.GetString(i)); ok {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+// vecEvalUUIDSwapFlags evaluates the optional swap flags of UUID_TO_BIN and BIN_TO_UUID, it
+// returns nil if the flag is not given.
+func vecEvalUUIDSwapFlags(b *baseBuiltinFunc, input *chunk.Chunk) (*chunk.Column, error) {
+	if len(b.args) < 2 {
+		return nil, nil
+	}
+	buf, err := b.bufAllocator.get(types.ETInt, input.NumRows())
+	if err != nil {
+		return nil, err
+	}
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		b.bufAllocator.put(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (b *builtinUUIDToBinSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUUIDToBinSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUUIDConversion(&b.baseBuiltinFunc, input, result, uuidToBin)
+}
+
+func (b *builtinBinToUUIDSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBinToUUIDSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalUUIDConversion(&b.baseBuiltinFunc, input, result, binToUUID)
+}
+
+// vecEvalUUIDConversion evaluates UUID_TO_BIN or BIN_TO_UUID in a vectorized manner by convert.
+func vecEvalUUIDConversion(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, convert func(string, bool) (string, error)) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	flagBuf, err := vecEvalUUIDSwapFlags(b, input)
+	if err != nil {
+		return err
+	}
+	if flagBuf != nil {
+		defer b.bufAllocator.put(flagBuf)
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		swap := flagBuf != nil && !flagBuf.IsNull(i) && flagBuf.GetInt64(i) != 0
+		res, err := convert(buf.GetString(i), swap)
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
 	}
 	return nil
 }
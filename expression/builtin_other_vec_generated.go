@@ -116,6 +116,7 @@ func (b *builtinInStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 		r64s[i] = 0
 	}
 	hasNull := make([]bool, n)
+	collator := b.collator()
 	var compareResult int
 
 	for j := 1; j < len(b.args); j++ {
@@ -129,7 +130,7 @@ func (b *builtinInStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column
 			}
 			arg0 := buf0.GetString(i)
 			arg1 := buf1.GetString(i)
-			compareResult = types.CompareString(arg0, arg1)
+			compareResult = collator.Compare(arg0, arg1)
 			if compareResult == 0 {
 				result.SetNull(i, false)
 				r64s[i] = 1
@@ -24,12 +24,33 @@ import (
 var vecBuiltinOtherCases = map[string][]vecExprBenchCase{
 	ast.SetVar: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETInt}},
+		{retEvalType: types.ETReal, childrenTypes: []types.EvalType{types.ETString, types.ETReal}},
 	},
 	ast.GetVar: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
 	},
+	ast.IsUUID: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{uuidStrGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}},
+	},
+	ast.UUIDToBin: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{uuidStrGener}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt}, geners: []dataGenerator{uuidStrGener, &rangeInt64Gener{0, 2}}},
+	},
+	ast.BinToUUID: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&randLenStrGener{16, 17}}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt}, geners: []dataGenerator{&randLenStrGener{16, 17}, &rangeInt64Gener{0, 2}}},
+	},
 }
 
+var uuidStrGener = &selectStringGener{candidates: []string{
+	"6ccd780c-baba-1026-9564-5b8c656024db",
+	"6CCD780CBABA102695645B8C656024DB",
+	"{6ccd780c-baba-1026-9564-5b8c656024db}",
+	"00000000-0000-0000-0000-000000000000",
+}}
+
 func (s *testEvaluatorSuite) TestVectorizedBuiltinOtherFunc(c *C) {
 	testVectorizedBuiltinFunc(c, vecBuiltinOtherCases)
 }
@@ -18,8 +18,16 @@
 package expression
 
 import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tipb/go-tipb"
@@ -28,11 +36,26 @@ import (
 var (
 	_ functionClass = &lengthFunctionClass{}
 	_ functionClass = &strcmpFunctionClass{}
+	_ functionClass = &substringIndexFunctionClass{}
+	_ functionClass = &lpadFunctionClass{}
+	_ functionClass = &rpadFunctionClass{}
+	_ functionClass = &instrFunctionClass{}
+	_ functionClass = &replaceFunctionClass{}
+	_ functionClass = &repeatFunctionClass{}
 )
 
 var (
 	_ builtinFunc = &builtinLengthSig{}
 	_ builtinFunc = &builtinStrcmpSig{}
+	_ builtinFunc = &builtinSubstringIndexSig{}
+	_ builtinFunc = &builtinLpadSig{}
+	_ builtinFunc = &builtinLpadUTF8Sig{}
+	_ builtinFunc = &builtinRpadSig{}
+	_ builtinFunc = &builtinRpadUTF8Sig{}
+	_ builtinFunc = &builtinInstrSig{}
+	_ builtinFunc = &builtinInstrUTF8Sig{}
+	_ builtinFunc = &builtinReplaceSig{}
+	_ builtinFunc = &builtinRepeatSig{}
 )
 
 // SetBinFlagOrBinStr sets resTp to binary string if argTp is a binary string,
@@ -123,6 +146,551 @@ func (b *builtinStrcmpSig) evalInt(row chunk.Row) (int64, bool, error) {
 	if isNull || err != nil {
 		return 0, isNull, err
 	}
-	res := types.CompareString(left, right)
+	res := b.collator().Compare(left, right)
 	return int64(res), false, nil
 }
+
+// getMaxAllowedPacket gets the max_allowed_packet of the session, which limits
+// the length of the result of the padding and repeating functions.
+func getMaxAllowedPacket(ctx sessionctx.Context) (uint64, error) {
+	valStr, err := variable.GetSessionSystemVar(ctx.GetSessionVars(), variable.MaxAllowedPacket)
+	if err != nil {
+		return 0, err
+	}
+	maxAllowedPacket, err := strconv.ParseUint(valStr, 10, 64)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return maxAllowedPacket, nil
+}
+
+type substringIndexFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *substringIndexFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETInt)
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := &builtinSubstringIndexSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_SubstringIndex)
+	return sig, nil
+}
+
+type builtinSubstringIndexSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstringIndexSig) Clone() builtinFunc {
+	newSig := &builtinSubstringIndexSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinSubstringIndexSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substring-index
+func (b *builtinSubstringIndexSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	delim, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	count, isNull, err := b.args[2].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return b.substringIndex(str, delim, count), false, nil
+}
+
+func (b *builtinSubstringIndexSig) substringIndex(str, delim string, count int64) string {
+	if len(delim) == 0 {
+		return ""
+	}
+	// The count larger than MaxInt64 returns the whole string.
+	if count < 0 && mysql.HasUnsignedFlag(b.args[2].GetType().Flag) {
+		return str
+	}
+	strs := strings.Split(str, delim)
+	start, end := int64(0), int64(len(strs))
+	if count > 0 {
+		// Everything to the left of the final delimiter (counting from the left) is returned.
+		if count < end {
+			end = count
+		}
+	} else {
+		// Everything to the right of the final delimiter (counting from the right) is returned.
+		count = -count
+		if count < 0 {
+			// -count overflows MaxInt64, returns the whole string.
+			return str
+		}
+		if count < end {
+			start = end - count
+		}
+	}
+	return strings.Join(strs[start:end], delim)
+}
+
+// getFlen4LpadAndRpad returns the Flen of LPAD and RPAD, which is the length
+// argument if it is a constant.
+func getFlen4LpadAndRpad(ctx sessionctx.Context, arg Expression) int {
+	if constant, ok := arg.(*Constant); ok {
+		length, isNull, err := constant.EvalInt(ctx, chunk.Row{})
+		if err != nil || isNull || length < 0 {
+			return 0
+		}
+		if length > mysql.MaxBlobWidth {
+			return mysql.MaxBlobWidth
+		}
+		return int(length)
+	}
+	return mysql.MaxBlobWidth
+}
+
+type lpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType()) {
+		sig = &builtinLpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Lpad)
+	} else {
+		sig = &builtinLpadUTF8Sig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_LpadUTF8)
+	}
+	return sig, nil
+}
+
+// evalPadArgs evaluates the arguments of LPAD and RPAD.
+func evalPadArgs(b *baseBuiltinFunc, row chunk.Row) (str string, length int64, padStr string, isNull bool, err error) {
+	str, isNull, err = b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	length, isNull, err = b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return
+	}
+	padStr, isNull, err = b.args[2].EvalString(b.ctx, row)
+	return
+}
+
+type builtinLpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadSig) Clone() builtinFunc {
+	newSig := &builtinLpadSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evaluates a builtinLpadSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := b.lpad(str, length, padStr)
+	return res, isNull, nil
+}
+
+func (b *builtinLpadSig) lpad(str string, length int64, padStr string) (string, bool) {
+	if length < 0 {
+		return "", true
+	}
+	if uint64(length) > b.maxAllowedPacket {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(ast.Lpad, b.maxAllowedPacket))
+		return "", true
+	}
+	targetLength, byteLength, padLength := int(length), len(str), len(padStr)
+	if byteLength < targetLength && padLength == 0 {
+		return "", true
+	}
+	if tailLen := targetLength - byteLength; tailLen > 0 {
+		repeatCount := tailLen/padLength + 1
+		str = strings.Repeat(padStr, repeatCount)[:tailLen] + str
+	}
+	return str[:targetLength], false
+}
+
+type builtinLpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinLpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinLpadUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evaluates a builtinLpadUTF8Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinLpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := b.lpad(str, length, padStr)
+	return res, isNull, nil
+}
+
+func (b *builtinLpadUTF8Sig) lpad(str string, length int64, padStr string) (string, bool) {
+	if length < 0 {
+		return "", true
+	}
+	if uint64(length)*uint64(mysql.MaxBytesOfCharacter) > b.maxAllowedPacket {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(ast.Lpad, b.maxAllowedPacket))
+		return "", true
+	}
+	targetLength, runeLength, padLength := int(length), utf8.RuneCountInString(str), utf8.RuneCountInString(padStr)
+	if runeLength < targetLength && padLength == 0 {
+		return "", true
+	}
+	if tailLen := targetLength - runeLength; tailLen > 0 {
+		repeatCount := tailLen/padLength + 1
+		str = string([]rune(strings.Repeat(padStr, repeatCount))[:tailLen]) + str
+	}
+	return string([]rune(str)[:targetLength]), false
+}
+
+type rpadFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rpadFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = getFlen4LpadAndRpad(ctx, args[1])
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	SetBinFlagOrBinStr(args[2].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sig builtinFunc
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[2].GetType()) {
+		sig = &builtinRpadSig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_Rpad)
+	} else {
+		sig = &builtinRpadUTF8Sig{bf, maxAllowedPacket}
+		sig.setPbCode(tipb.ScalarFuncSig_RpadUTF8)
+	}
+	return sig, nil
+}
+
+type builtinRpadSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadSig) Clone() builtinFunc {
+	newSig := &builtinRpadSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evaluates a builtinRpadSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := b.rpad(str, length, padStr)
+	return res, isNull, nil
+}
+
+func (b *builtinRpadSig) rpad(str string, length int64, padStr string) (string, bool) {
+	if length < 0 {
+		return "", true
+	}
+	if uint64(length) > b.maxAllowedPacket {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(ast.Rpad, b.maxAllowedPacket))
+		return "", true
+	}
+	targetLength, byteLength, padLength := int(length), len(str), len(padStr)
+	if byteLength < targetLength && padLength == 0 {
+		return "", true
+	}
+	if tailLen := targetLength - byteLength; tailLen > 0 {
+		repeatCount := tailLen/padLength + 1
+		str = str + strings.Repeat(padStr, repeatCount)
+	}
+	return str[:targetLength], false
+}
+
+type builtinRpadUTF8Sig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRpadUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinRpadUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evaluates a builtinRpadUTF8Sig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_rpad
+func (b *builtinRpadUTF8Sig) evalString(row chunk.Row) (string, bool, error) {
+	str, length, padStr, isNull, err := evalPadArgs(&b.baseBuiltinFunc, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := b.rpad(str, length, padStr)
+	return res, isNull, nil
+}
+
+func (b *builtinRpadUTF8Sig) rpad(str string, length int64, padStr string) (string, bool) {
+	if length < 0 {
+		return "", true
+	}
+	if uint64(length)*uint64(mysql.MaxBytesOfCharacter) > b.maxAllowedPacket {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(ast.Rpad, b.maxAllowedPacket))
+		return "", true
+	}
+	targetLength, runeLength, padLength := int(length), utf8.RuneCountInString(str), utf8.RuneCountInString(padStr)
+	if runeLength < targetLength && padLength == 0 {
+		return "", true
+	}
+	if tailLen := targetLength - runeLength; tailLen > 0 {
+		repeatCount := tailLen/padLength + 1
+		str = str + strings.Repeat(padStr, repeatCount)
+	}
+	return string([]rune(str)[:targetLength]), false
+}
+
+type instrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *instrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETString, types.ETString)
+	bf.tp.Flen = 11
+	var sig builtinFunc
+	if types.IsBinaryStr(args[0].GetType()) || types.IsBinaryStr(args[1].GetType()) {
+		sig = &builtinInstrSig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_Instr)
+	} else {
+		sig = &builtinInstrUTF8Sig{bf}
+		sig.setPbCode(tipb.ScalarFuncSig_InstrUTF8)
+	}
+	return sig, nil
+}
+
+type builtinInstrSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInstrSig) Clone() builtinFunc {
+	newSig := &builtinInstrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates a builtinInstrSig, the position is counted in bytes.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func (b *builtinInstrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	substr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return int64(strings.Index(str, substr) + 1), false, nil
+}
+
+type builtinInstrUTF8Sig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinInstrUTF8Sig) Clone() builtinFunc {
+	newSig := &builtinInstrUTF8Sig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates a builtinInstrUTF8Sig, the position is counted in characters
+// and the match is case insensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_instr
+func (b *builtinInstrUTF8Sig) evalInt(row chunk.Row) (int64, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	substr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return instrUTF8(str, substr), false, nil
+}
+
+func instrUTF8(str, substr string) int64 {
+	str, substr = strings.ToLower(str), strings.ToLower(substr)
+	idx := strings.Index(str, substr)
+	if idx == -1 {
+		return 0
+	}
+	return int64(utf8.RuneCountInString(str[:idx]) + 1)
+}
+
+type replaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *replaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = c.fixLength(args)
+	for _, a := range args {
+		SetBinFlagOrBinStr(a.GetType(), bf.tp)
+	}
+	sig := &builtinReplaceSig{bf}
+	sig.setPbCode(tipb.ScalarFuncSig_Replace)
+	return sig, nil
+}
+
+// fixLength calculates the Flen of the return type, each occurrence of the
+// replaced string may grow to the length of the replacing string.
+func (c *replaceFunctionClass) fixLength(args []Expression) int {
+	charLen := args[0].GetType().Flen
+	oldStrLen := args[1].GetType().Flen
+	diff := args[2].GetType().Flen - oldStrLen
+	if diff > 0 && oldStrLen > 0 {
+		charLen += (charLen / oldStrLen) * diff
+	}
+	return charLen
+}
+
+type builtinReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReplaceSig) Clone() builtinFunc {
+	newSig := &builtinReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evaluates a builtinReplaceSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	oldStr, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	newStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	if oldStr == "" {
+		return str, false, nil
+	}
+	return strings.Replace(str, oldStr, newStr, -1), false, nil
+}
+
+type repeatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *repeatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt)
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	maxAllowedPacket, err := getMaxAllowedPacket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sig := &builtinRepeatSig{bf, maxAllowedPacket}
+	sig.setPbCode(tipb.ScalarFuncSig_Repeat)
+	return sig, nil
+}
+
+type builtinRepeatSig struct {
+	baseBuiltinFunc
+	maxAllowedPacket uint64
+}
+
+func (b *builtinRepeatSig) Clone() builtinFunc {
+	newSig := &builtinRepeatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	newSig.maxAllowedPacket = b.maxAllowedPacket
+	return newSig
+}
+
+// evalString evaluates a builtinRepeatSig.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_repeat
+func (b *builtinRepeatSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	num, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull := b.repeat(str, num)
+	return res, isNull, nil
+}
+
+func (b *builtinRepeatSig) repeat(str string, num int64) (string, bool) {
+	// The count larger than MaxInt64 is regarded as MaxInt32 as well.
+	if num < 0 && mysql.HasUnsignedFlag(b.args[1].GetType().Flag) {
+		num = math.MaxInt32
+	}
+	if num < 1 {
+		return "", false
+	}
+	if num > math.MaxInt32 {
+		num = math.MaxInt32
+	}
+	if uint64(len(str))*uint64(num) > b.maxAllowedPacket {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(ast.Repeat, b.maxAllowedPacket))
+		return "", true
+	}
+	return strings.Repeat(str, int(num)), false
+}
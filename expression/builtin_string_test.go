@@ -98,3 +98,192 @@ func (s *testEvaluatorSuite) TestStrcmp(c *C) {
 		}
 	}
 }
+
+func (s *testEvaluatorSuite) TestSubstringIndex(c *C) {
+	cases := []struct {
+		args   []interface{}
+		isNil  bool
+		getErr bool
+		res    string
+	}{
+		{[]interface{}{"www.pingcap.com", ".", 2}, false, false, "www.pingcap"},
+		{[]interface{}{"www.pingcap.com", ".", -2}, false, false, "pingcap.com"},
+		{[]interface{}{"www.pingcap.com", ".", 0}, false, false, ""},
+		{[]interface{}{"www.pingcap.com", ".", 100}, false, false, "www.pingcap.com"},
+		{[]interface{}{"www.pingcap.com", ".", -100}, false, false, "www.pingcap.com"},
+		{[]interface{}{"www.pingcap.com", "d", 0}, false, false, ""},
+		{[]interface{}{"www.pingcap.com", "d", 1}, false, false, "www.pingcap.com"},
+		{[]interface{}{"www.pingcap.com", "", 1}, false, false, ""},
+		{[]interface{}{"", ".", 1}, false, false, ""},
+		{[]interface{}{"你好，世界，再见", "，", 2}, false, false, "你好，世界"},
+		{[]interface{}{"你好，世界，再见", "，", -1}, false, false, "再见"},
+		{[]interface{}{"www.pingcap.com", ".", uint64(18446744073709551615)}, false, false, "www.pingcap.com"},
+		{[]interface{}{"www.pingcap.com", ".", int64(-9223372036854775808)}, false, false, "www.pingcap.com"},
+		{[]interface{}{nil, ".", 1}, true, false, ""},
+		{[]interface{}{"www.pingcap.com", nil, 1}, true, false, ""},
+		{[]interface{}{"www.pingcap.com", ".", nil}, true, false, ""},
+		{[]interface{}{errors.New("must error"), ".", 1}, false, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.SubstringIndex, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		if t.getErr {
+			c.Assert(err, NotNil)
+		} else {
+			c.Assert(err, IsNil)
+			if t.isNil {
+				c.Assert(d.Kind(), Equals, types.KindNull)
+			} else {
+				c.Assert(d.GetString(), Equals, t.res, Commentf("%v", t.args))
+			}
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestLpadAndRpad(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		lpad  string
+		rpad  string
+	}{
+		{[]interface{}{"hi", 5, "?"}, false, "???hi", "hi???"},
+		{[]interface{}{"hi", 1, "?"}, false, "h", "h"},
+		{[]interface{}{"hi", 0, "?"}, false, "", ""},
+		{[]interface{}{"hi", 6, "ab"}, false, "ababhi", "hiabab"},
+		{[]interface{}{"hi", 5, "ab"}, false, "abahi", "hiaba"},
+		{[]interface{}{"hi", 2, ""}, false, "hi", "hi"},
+		{[]interface{}{"hi", 3, ""}, true, "", ""},
+		{[]interface{}{"hi", -1, "?"}, true, "", ""},
+		{[]interface{}{"你好", 5, "世界"}, false, "世界世你好", "你好世界世"},
+		{[]interface{}{"你好", 1, "世界"}, false, "你", "你"},
+		{[]interface{}{[]byte("hi"), 5, "?"}, false, "???hi", "hi???"},
+		{[]interface{}{[]byte("你好"), 7, "?"}, false, "?你好", "你好?"},
+		{[]interface{}{"你好", 3, []byte("?")}, false, "你", "你"},
+		{[]interface{}{nil, 5, "?"}, true, "", ""},
+		{[]interface{}{"hi", nil, "?"}, true, "", ""},
+		{[]interface{}{"hi", 5, nil}, true, "", ""},
+	}
+	for _, t := range cases {
+		for _, fn := range []struct {
+			name string
+			res  string
+		}{{ast.Lpad, t.lpad}, {ast.Rpad, t.rpad}} {
+			f, err := newFunctionForTest(s.ctx, fn.name, s.primitiveValsToConstants(t.args)...)
+			c.Assert(err, IsNil)
+			d, err := f.Eval(chunk.Row{})
+			c.Assert(err, IsNil)
+			if t.isNil {
+				c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%s%v", fn.name, t.args))
+			} else {
+				c.Assert(d.GetString(), Equals, fn.res, Commentf("%s%v", fn.name, t.args))
+			}
+		}
+	}
+
+	// The result larger than max_allowed_packet is NULL with a warning.
+	for _, name := range []string{ast.Lpad, ast.Rpad} {
+		warnCnt := s.ctx.GetSessionVars().StmtCtx.WarningCount()
+		f, err := newFunctionForTest(s.ctx, name, s.primitiveValsToConstants([]interface{}{"hi", 67108865, "?"})...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		c.Assert(d.Kind(), Equals, types.KindNull)
+		warnings := s.ctx.GetSessionVars().StmtCtx.GetWarnings()
+		c.Assert(warnings, HasLen, int(warnCnt)+1)
+		c.Assert(errWarnAllowedPacketOverflowed.Equal(warnings[warnCnt].Err), IsTrue)
+	}
+}
+
+func (s *testEvaluatorSuite) TestInstr(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		res   int64
+	}{
+		{[]interface{}{"foobarbar", "bar"}, false, 4},
+		{[]interface{}{"xbar", "foobar"}, false, 0},
+		{[]interface{}{"foobar", ""}, false, 1},
+		{[]interface{}{"", "bar"}, false, 0},
+		{[]interface{}{"FooBar", "bar"}, false, 4},
+		{[]interface{}{"中文美好", "美好"}, false, 3},
+		{[]interface{}{"中文美好", "世界"}, false, 0},
+		{[]interface{}{[]byte("FooBar"), "bar"}, false, 0},
+		{[]interface{}{[]byte("FooBar"), "Bar"}, false, 4},
+		{[]interface{}{[]byte("中文美好"), "美好"}, false, 7},
+		{[]interface{}{nil, "bar"}, true, 0},
+		{[]interface{}{"foobar", nil}, true, 0},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Instr, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetInt64(), Equals, t.res, Commentf("%v", t.args))
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestReplace(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		res   string
+		flen  int
+	}{
+		{[]interface{}{"www.mysql.com", "mysql", "pingcap"}, false, "www.pingcap.com", 17},
+		{[]interface{}{"www.mysql.com", "w", 1}, false, "111.mysql.com", 13},
+		{[]interface{}{1234, 2, 55}, false, "15534", 8},
+		{[]interface{}{"www.mysql.com", "", "tidb"}, false, "www.mysql.com", 13},
+		{[]interface{}{"你好世界你好", "你好", "再见"}, false, "再见世界再见", 18},
+		{[]interface{}{nil, "a", "b"}, true, "", 0},
+		{[]interface{}{"a", nil, "b"}, true, "", 1},
+		{[]interface{}{"a", "b", nil}, true, "", 1},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Replace, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		c.Assert(f.GetType().Flen, Equals, t.flen, Commentf("%v", t.args))
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull)
+		} else {
+			c.Assert(d.GetString(), Equals, t.res)
+		}
+	}
+}
+
+func (s *testEvaluatorSuite) TestRepeat(c *C) {
+	cases := []struct {
+		args  []interface{}
+		isNil bool
+		res   string
+	}{
+		{[]interface{}{"a", 3}, false, "aaa"},
+		{[]interface{}{"你好", 2}, false, "你好你好"},
+		{[]interface{}{"a", 0}, false, ""},
+		{[]interface{}{"a", -1}, false, ""},
+		{[]interface{}{"", 5}, false, ""},
+		{[]interface{}{"", uint64(18446744073709551615)}, false, ""},
+		{[]interface{}{"ab", 33554433}, true, ""},
+		{[]interface{}{"a", uint64(18446744073709551615)}, true, ""},
+		{[]interface{}{nil, 1}, true, ""},
+		{[]interface{}{"a", nil}, true, ""},
+	}
+	for _, t := range cases {
+		f, err := newFunctionForTest(s.ctx, ast.Repeat, s.primitiveValsToConstants(t.args)...)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.Row{})
+		c.Assert(err, IsNil)
+		if t.isNil {
+			c.Assert(d.Kind(), Equals, types.KindNull, Commentf("%v", t.args))
+		} else {
+			c.Assert(d.GetString(), Equals, t.res, Commentf("%v", t.args))
+		}
+	}
+}
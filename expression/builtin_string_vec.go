@@ -14,37 +14,12 @@
 package expression
 
 import (
+	"strings"
+
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
-func (b *builtinStringIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
-	n := input.NumRows()
-	buf, err := b.bufAllocator.get(types.ETString, n)
-	if err != nil {
-		return err
-	}
-	defer b.bufAllocator.put(buf)
-	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
-		return err
-	}
-
-	result.ResizeInt64(n, false)
-	i64s := result.Int64s()
-	for i := 0; i < n; i++ {
-		if buf.IsNull(i) {
-			i64s[i] = 1
-		} else {
-			i64s[i] = 0
-		}
-	}
-	return nil
-}
-
-func (b *builtinStringIsNullSig) vectorized() bool {
-	return true
-}
-
 func (b *builtinStrcmpSig) vectorized() bool {
 	return true
 }
@@ -67,6 +42,7 @@ func (b *builtinStrcmpSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 	if err := b.args[1].VecEvalString(b.ctx, input, rightBuf); err != nil {
 		return err
 	}
+	collator := b.collator()
 	result.ResizeInt64(n, false)
 	result.MergeNulls(leftBuf, rightBuf)
 	i64s := result.Int64s()
@@ -75,7 +51,7 @@ func (b *builtinStrcmpSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 		if result.IsNull(i) {
 			continue
 		}
-		i64s[i] = int64(types.CompareString(leftBuf.GetString(i), rightBuf.GetString(i)))
+		i64s[i] = int64(collator.Compare(leftBuf.GetString(i), rightBuf.GetString(i)))
 	}
 	return nil
 }
@@ -110,3 +86,268 @@ func (b *builtinLengthSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column)
 	}
 	return nil
 }
+
+func (b *builtinSubstringIndexSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstringIndexSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	delimBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(delimBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, delimBuf); err != nil {
+		return err
+	}
+	countBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(countBuf)
+	if err := b.args[2].VecEvalInt(b.ctx, input, countBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	counts := countBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || delimBuf.IsNull(i) || countBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(b.substringIndex(strBuf.GetString(i), delimBuf.GetString(i), counts[i]))
+	}
+	return nil
+}
+
+// vecEvalPadArgs evaluates the arguments of LPAD and RPAD into the buffers,
+// which should be put back by the caller.
+func vecEvalPadArgs(b *baseBuiltinFunc, input *chunk.Chunk) (strBuf, lengthBuf, padBuf *chunk.Column, err error) {
+	n := input.NumRows()
+	if strBuf, err = b.bufAllocator.get(types.ETString, n); err != nil {
+		return
+	}
+	if lengthBuf, err = b.bufAllocator.get(types.ETInt, n); err != nil {
+		return
+	}
+	if padBuf, err = b.bufAllocator.get(types.ETString, n); err != nil {
+		return
+	}
+	if err = b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return
+	}
+	if err = b.args[1].VecEvalInt(b.ctx, input, lengthBuf); err != nil {
+		return
+	}
+	err = b.args[2].VecEvalString(b.ctx, input, padBuf)
+	return
+}
+
+// vecEvalPad fills the result of LPAD and RPAD with the pad function.
+func vecEvalPad(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, pad func(string, int64, string) (string, bool)) error {
+	strBuf, lengthBuf, padBuf, err := vecEvalPadArgs(b, input)
+	for _, buf := range []*chunk.Column{strBuf, lengthBuf, padBuf} {
+		if buf != nil {
+			defer b.bufAllocator.put(buf)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	n := input.NumRows()
+	result.ReserveString(n)
+	lengths := lengthBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || lengthBuf.IsNull(i) || padBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull := pad(strBuf.GetString(i), lengths[i], padBuf.GetString(i))
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinLpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, b.lpad)
+}
+
+func (b *builtinLpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, b.lpad)
+}
+
+func (b *builtinRpadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, b.rpad)
+}
+
+func (b *builtinRpadUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRpadUTF8Sig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalPad(&b.baseBuiltinFunc, input, result, b.rpad)
+}
+
+// vecEvalInstr fills the result of INSTR with the instr function.
+func vecEvalInstr(b *baseBuiltinFunc, input *chunk.Chunk, result *chunk.Column, instr func(string, string) int64) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	substrBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(substrBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, substrBuf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	result.MergeNulls(strBuf, substrBuf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = instr(strBuf.GetString(i), substrBuf.GetString(i))
+	}
+	return nil
+}
+
+func (b *builtinInstrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInstrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalInstr(&b.baseBuiltinFunc, input, result, func(str, substr string) int64 {
+		return int64(strings.Index(str, substr) + 1)
+	})
+}
+
+func (b *builtinInstrUTF8Sig) vectorized() bool {
+	return true
+}
+
+func (b *builtinInstrUTF8Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return vecEvalInstr(&b.baseBuiltinFunc, input, result, instrUTF8)
+}
+
+func (b *builtinReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	oldBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(oldBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, oldBuf); err != nil {
+		return err
+	}
+	newBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(newBuf)
+	if err := b.args[2].VecEvalString(b.ctx, input, newBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || oldBuf.IsNull(i) || newBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, oldStr := strBuf.GetString(i), oldBuf.GetString(i)
+		if oldStr == "" {
+			result.AppendString(str)
+			continue
+		}
+		result.AppendString(strings.Replace(str, oldStr, newBuf.GetString(i), -1))
+	}
+	return nil
+}
+
+func (b *builtinRepeatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRepeatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf, err := b.bufAllocator.get(types.ETString, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	numBuf, err := b.bufAllocator.get(types.ETInt, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(numBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, numBuf); err != nil {
+		return err
+	}
+
+	result.ReserveString(n)
+	nums := numBuf.Int64s()
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || numBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull := b.repeat(strBuf.GetString(i), nums[i])
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
@@ -18,9 +18,18 @@ import (
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 )
 
+func newBinaryStrFieldType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeVarString)
+	types.SetBinChsClnFlag(ft)
+	return ft
+}
+
+var multiByteStrs = []string{"", "a", "abc", "a.b.c", "中文", "中.文.字", "A中B文", "..", "文字文字"}
+
 var vecBuiltinStringCases = map[string][]vecExprBenchCase{
 	ast.Length: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString}, geners: []dataGenerator{&defaultGener{0.2, types.ETString}}},
@@ -44,6 +53,68 @@ var vecBuiltinStringCases = map[string][]vecExprBenchCase{
 			},
 		}},
 	},
+	ast.SubstringIndex: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&selectStringGener{candidates: []string{".", "文", "", "a.", ".c"}},
+			&rangeInt64Gener{-4, 4},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt}},
+	},
+	ast.Lpad: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&rangeInt64Gener{-1, 12},
+			&selectStringGener{candidates: []string{"", "?", "中", "ab", "中文字"}},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			childrenFieldTypes: []*types.FieldType{newBinaryStrFieldType(), nil, nil},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: multiByteStrs},
+				&rangeInt64Gener{-1, 12},
+				&selectStringGener{candidates: []string{"", "?", "中", "ab"}},
+			}},
+	},
+	ast.Rpad: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&rangeInt64Gener{-1, 12},
+			&selectStringGener{candidates: []string{"", "?", "中", "ab", "中文字"}},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt, types.ETString},
+			childrenFieldTypes: []*types.FieldType{newBinaryStrFieldType(), nil, nil},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: multiByteStrs},
+				&rangeInt64Gener{-1, 12},
+				&selectStringGener{candidates: []string{"", "?", "中", "ab"}},
+			}},
+	},
+	ast.Instr: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&selectStringGener{candidates: []string{"", "a", "B", "文", "c"}},
+		}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			childrenFieldTypes: []*types.FieldType{newBinaryStrFieldType(), nil},
+			geners: []dataGenerator{
+				&selectStringGener{candidates: multiByteStrs},
+				&selectStringGener{candidates: []string{"", "a", "B", "文", "c"}},
+			}},
+	},
+	ast.Replace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&selectStringGener{candidates: []string{"", ".", "文", "ab"}},
+			&selectStringGener{candidates: []string{"", "-", "字"}},
+		}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString}},
+	},
+	ast.Repeat: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETInt}, geners: []dataGenerator{
+			&selectStringGener{candidates: multiByteStrs},
+			&rangeInt64Gener{-2, 5},
+		}},
+	},
 }
 
 func (s *testEvaluatorSuite) TestVectorizedBuiltinStringEvalOneVec(c *C) {
@@ -66,6 +66,20 @@ func (s *testEvaluatorSuite) TestIsNullFunc(c *C) {
 	c.Assert(v.GetInt64(), Equals, int64(1))
 }
 
+func (s *testEvaluatorSuite) TestDeterministicFunctions(c *C) {
+	for name := range DeterministicFunctions {
+		_, ok := funcs[name]
+		c.Assert(ok, IsTrue, Commentf("function %s", name))
+	}
+	// The functions whose results vary between the calls or depend on the session.
+	for _, name := range []string{ast.UUID, ast.UUIDShort, ast.UnixTimestamp, ast.ConnectionID, ast.User,
+		ast.SessionUser, ast.SystemUser, ast.CurrentUser, ast.CurrentRole, ast.SetVar, ast.GetVar,
+		ast.AesEncrypt, ast.AesDecrypt} {
+		_, ok := DeterministicFunctions[name]
+		c.Assert(ok, IsFalse, Commentf("function %s", name))
+	}
+}
+
 // newFunctionForTest creates a new ScalarFunction using funcName and arguments,
 // it is different from expression.NewFunction which needs an additional retType argument.
 func newFunctionForTest(ctx sessionctx.Context, funcName string, args ...Expression) (Expression, error) {
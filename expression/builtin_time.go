@@ -0,0 +1,61 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var (
+	_ functionClass = &unixTimestampFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinUnixTimestampSig{}
+)
+
+type unixTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unixTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+	bf.tp.Flen = 11
+	bf.tp.Flag |= mysql.UnsignedFlag
+	sig := &builtinUnixTimestampSig{bf}
+	return sig, nil
+}
+
+type builtinUnixTimestampSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals a builtinUnixTimestampSig. The time is taken once per statement,
+// so all the calls in a statement return the same value.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampSig) evalInt(_ chunk.Row) (int64, bool, error) {
+	return b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix(), false, nil
+}
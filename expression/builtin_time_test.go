@@ -0,0 +1,45 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (s *testEvaluatorSuite) TestUnixTimestamp(c *C) {
+	fn, err := funcs[ast.UnixTimestamp].getFunction(s.ctx, nil)
+	c.Assert(err, IsNil)
+	ts := s.ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix()
+	d, err := evalBuiltinFunc(fn, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, ts)
+
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{types.NewFieldType(mysql.TypeLonglong)}, 3)
+	for i := 0; i < 3; i++ {
+		input.AppendInt64(0, int64(i))
+	}
+	result := chunk.NewColumn(types.NewFieldType(mysql.TypeLonglong), 3)
+	c.Assert(fn.vecEvalInt(input, result), IsNil)
+	c.Assert(result.Int64s(), HasLen, 3)
+	for i := 0; i < 3; i++ {
+		c.Assert(result.GetInt64(i), Equals, ts)
+	}
+
+	_, err = funcs[ast.UnixTimestamp].getFunction(s.ctx, []Expression{newLonglong(1)})
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,33 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func (b *builtinUnixTimestampSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	ts := b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix()
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = ts
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+var (
+	_ FunctionClass = &udfFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinUDFSig{}
+)
+
+// FunctionClass is a class of builtin functions which can be registered by
+// RegisterBuiltinFunc. The classes out of this package are created by
+// NewUDFFunctionClass.
+type FunctionClass interface {
+	functionClass
+}
+
+// registeredFuncs records the functions added by RegisterBuiltinFunc, only
+// they can be removed by UnregisterBuiltinFunc.
+var registeredFuncs = make(map[string]struct{})
+
+// RegisterBuiltinFunc adds fc as the builtin function with the name, it returns
+// ErrFunctionAlreadyExists if the name is taken. The builtin functions are read
+// without any lock, so it must be called at init time. The registered functions
+// are not in DeterministicFunctions, so their results are never cached.
+func RegisterBuiltinFunc(name string, fc FunctionClass) error {
+	name = strings.ToLower(name)
+	if _, ok := funcs[name]; ok {
+		return ErrFunctionAlreadyExists.GenWithStackByArgs("FUNCTION", name)
+	}
+	funcs[name] = fc
+	registeredFuncs[name] = struct{}{}
+	return nil
+}
+
+// UnregisterBuiltinFunc removes the function added by RegisterBuiltinFunc, it's
+// used by tests to clean up. The functions built in this package are kept.
+func UnregisterBuiltinFunc(name string) {
+	name = strings.ToLower(name)
+	if _, ok := registeredFuncs[name]; ok {
+		delete(funcs, name)
+		delete(registeredFuncs, name)
+	}
+}
+
+// UDFEvalFunc computes the result of a user defined function from the values of
+// its arguments. A NULL datum means the result is NULL. The calls with constant
+// arguments are folded while planning, so it should be deterministic.
+type UDFEvalFunc func(ctx sessionctx.Context, args []types.Datum) (types.Datum, error)
+
+// NewUDFFunctionClass creates the class of a scalar function defined out of this
+// package. The arguments are passed in their own types, and the result is
+// converted to retType which is one of ETInt, ETReal and ETString. maxArgs is
+// -1 if the count of arguments is unlimited. The function is evaluated row by
+// row in TiDB and never pushed down to the storage.
+func NewUDFFunctionClass(name string, minArgs, maxArgs int, retType types.EvalType, eval UDFEvalFunc) FunctionClass {
+	return &udfFunctionClass{
+		baseFunctionClass: baseFunctionClass{strings.ToLower(name), minArgs, maxArgs},
+		retType:           retType,
+		eval:              eval,
+	}
+}
+
+type udfFunctionClass struct {
+	baseFunctionClass
+	retType types.EvalType
+	eval    UDFEvalFunc
+}
+
+func (c *udfFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	switch c.retType {
+	case types.ETInt, types.ETReal, types.ETString:
+	default:
+		return nil, errors.Errorf("unsupported result type %v of function %s", c.retType, c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for _, arg := range args {
+		argTps = append(argTps, arg.GetType().EvalType())
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, c.retType, argTps...)
+	if c.retType == types.ETString {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	sig := &builtinUDFSig{bf, c.eval}
+	return sig, nil
+}
+
+type builtinUDFSig struct {
+	baseBuiltinFunc
+	eval UDFEvalFunc
+}
+
+func (b *builtinUDFSig) Clone() builtinFunc {
+	newSig := &builtinUDFSig{eval: b.eval}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUDFSig) evalDatum(row chunk.Row) (types.Datum, error) {
+	args := make([]types.Datum, 0, len(b.args))
+	for _, arg := range b.args {
+		d, err := arg.Eval(row)
+		if err != nil {
+			return types.Datum{}, err
+		}
+		args = append(args, d)
+	}
+	return b.eval(b.ctx, args)
+}
+
+// evalInt evals a builtinUDFSig whose result type is ETInt.
+func (b *builtinUDFSig) evalInt(row chunk.Row) (int64, bool, error) {
+	d, err := b.evalDatum(row)
+	if err != nil || d.IsNull() {
+		return 0, true, err
+	}
+	res, err := d.ToInt64(b.ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
+}
+
+// evalReal evals a builtinUDFSig whose result type is ETReal.
+func (b *builtinUDFSig) evalReal(row chunk.Row) (float64, bool, error) {
+	d, err := b.evalDatum(row)
+	if err != nil || d.IsNull() {
+		return 0, true, err
+	}
+	res, err := d.ToFloat64(b.ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
+}
+
+// evalString evals a builtinUDFSig whose result type is ETString.
+func (b *builtinUDFSig) evalString(row chunk.Row) (string, bool, error) {
+	d, err := b.evalDatum(row)
+	if err != nil || d.IsNull() {
+		return "", true, err
+	}
+	res, err := d.ToString()
+	return res, err != nil, err
+}
@@ -0,0 +1,111 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+func testUDFConcat(_ sessionctx.Context, args []types.Datum) (types.Datum, error) {
+	strs := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg.IsNull() {
+			return types.Datum{}, nil
+		}
+		s, err := arg.ToString()
+		if err != nil {
+			return types.Datum{}, err
+		}
+		strs = append(strs, s)
+	}
+	return types.NewStringDatum(strings.Join(strs, "")), nil
+}
+
+// TestRegisterBuiltinFunc runs serially because the functions are registered
+// while the other suites may be reading the function map.
+func (s *testEvaluatorSerialSuites) TestRegisterBuiltinFunc(c *C) {
+	ctx := mock.NewContext()
+	toConstants := func(args ...interface{}) []Expression {
+		constants := make([]Expression, 0, len(args))
+		for _, arg := range args {
+			ft := &types.FieldType{}
+			types.DefaultTypeForValue(arg, ft)
+			constants = append(constants, &Constant{Value: types.NewDatum(arg), RetType: ft})
+		}
+		return constants
+	}
+	c.Assert(RegisterBuiltinFunc("Test_UDF_Concat", NewUDFFunctionClass("test_udf_concat", 1, -1, types.ETString, testUDFConcat)), IsNil)
+	defer UnregisterBuiltinFunc("test_udf_concat")
+	c.Assert(IsFunctionSupported("test_udf_concat"), IsTrue)
+	// The registered functions may not be deterministic, their results are never cached.
+	_, ok := DeterministicFunctions["test_udf_concat"]
+	c.Assert(ok, IsFalse)
+
+	// The names are case insensitive, and the builtin functions can't be replaced.
+	err := RegisterBuiltinFunc("TEST_UDF_CONCAT", NewUDFFunctionClass("test_udf_concat", 1, -1, types.ETString, testUDFConcat))
+	c.Assert(ErrFunctionAlreadyExists.Equal(err), IsTrue, Commentf("err %v", err))
+	err = RegisterBuiltinFunc(ast.Length, NewUDFFunctionClass(ast.Length, 1, 1, types.ETInt, testUDFConcat))
+	c.Assert(ErrFunctionAlreadyExists.Equal(err), IsTrue, Commentf("err %v", err))
+
+	fc := funcs["test_udf_concat"]
+	f, err := fc.getFunction(ctx, toConstants("a", 1, 2.5))
+	c.Assert(err, IsNil)
+	c.Assert(f.getRetTp().EvalType(), Equals, types.ETString)
+	c.Assert(f.PbCode(), Equals, tipb.ScalarFuncSig_Unspecified)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetString(), Equals, "a12.5")
+	f, err = fc.getFunction(ctx, toConstants("a", nil))
+	c.Assert(err, IsNil)
+	d, err = evalBuiltinFunc(f, chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.IsNull(), IsTrue)
+	_, err = fc.getFunction(ctx, nil)
+	c.Assert(ErrIncorrectParameterCount.Equal(err), IsTrue, Commentf("err %v", err))
+
+	// The result is converted to the result type of the function.
+	c.Assert(RegisterBuiltinFunc("test_udf_int", NewUDFFunctionClass("test_udf_int", 1, 3, types.ETInt, testUDFConcat)), IsNil)
+	defer UnregisterBuiltinFunc("test_udf_int")
+	expr, err := NewFunction(ctx, "test_udf_int", types.NewFieldType(mysql.TypeLonglong), toConstants(1, 2)...)
+	c.Assert(err, IsNil)
+	c.Assert(expr.String(), Equals, "12")
+	col := &Column{Index: 0, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	expr, err = NewFunction(ctx, "test_udf_int", types.NewFieldType(mysql.TypeLonglong), col, col)
+	c.Assert(err, IsNil)
+	val, isNull, err := expr.EvalInt(ctx, chunk.MutRowFromDatums(types.MakeDatums(3)).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(isNull, IsFalse)
+	c.Assert(val, Equals, int64(33))
+	cloned := expr.Clone()
+	val, _, err = cloned.EvalInt(ctx, chunk.MutRowFromDatums(types.MakeDatums(4)).ToRow())
+	c.Assert(err, IsNil)
+	c.Assert(val, Equals, int64(44))
+
+	// Only the registered functions can be removed.
+	UnregisterBuiltinFunc("test_udf_int")
+	c.Assert(IsFunctionSupported("test_udf_int"), IsFalse)
+	_, err = NewFunction(ctx, "test_udf_int", types.NewFieldType(mysql.TypeLonglong), col)
+	c.Assert(err, NotNil)
+	UnregisterBuiltinFunc(ast.Length)
+	c.Assert(IsFunctionSupported(ast.Length), IsTrue)
+}
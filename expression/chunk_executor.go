@@ -105,6 +105,10 @@ func evalOneVec(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, out
 		if err := expr.VecEvalString(ctx, input, result); err != nil {
 			return err
 		}
+	case types.ETJson:
+		if err := expr.VecEvalJSON(ctx, input, result); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -123,6 +127,10 @@ func evalOneColumn(ctx sessionctx.Context, expr Expression, iterator *chunk.Iter
 		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
 			err = executeToString(ctx, expr, fieldType, row, output, colID)
 		}
+	case types.ETJson:
+		for row := iterator.Begin(); err == nil && row != iterator.End(); row = iterator.Next() {
+			err = executeToJSON(ctx, expr, fieldType, row, output, colID)
+		}
 	}
 	return err
 }
@@ -135,6 +143,8 @@ func evalOneCell(ctx sessionctx.Context, expr Expression, row chunk.Row, output
 		err = executeToReal(ctx, expr, fieldType, row, output, colID)
 	case types.ETString:
 		err = executeToString(ctx, expr, fieldType, row, output, colID)
+	case types.ETJson:
+		err = executeToJSON(ctx, expr, fieldType, row, output, colID)
 	}
 	return err
 }
@@ -190,6 +200,19 @@ func executeToString(ctx sessionctx.Context, expr Expression, fieldType *types.F
 	return nil
 }
 
+func executeToJSON(ctx sessionctx.Context, expr Expression, fieldType *types.FieldType, row chunk.Row, output *chunk.Chunk, colID int) error {
+	res, isNull, err := expr.EvalJSON(ctx, row)
+	if err != nil {
+		return err
+	}
+	if isNull {
+		output.AppendNull(colID)
+	} else {
+		output.AppendJSON(colID, res)
+	}
+	return nil
+}
+
 // VectorizedFilter applies a list of filters to a Chunk and
 // returns a bool slice, which indicates whether a row is passed the filters.
 // Filters is executed vectorized.
@@ -203,8 +226,10 @@ func VectorizedFilter(ctx sessionctx.Context, filters []Expression, iterator *ch
 // filters, `isNull` indicates whether the result of the filter is null.
 // Filters is executed vectorized.
 func VectorizedFilterConsiderNull(ctx sessionctx.Context, filters []Expression, iterator *chunk.Iterator4Chunk, selected []bool, isNull []bool) ([]bool, []bool, error) {
-	// canVectorized used to check whether all of the filters can be vectorized evaluated
-	canVectorized := true
+	// canVectorized used to check whether all of the filters can be vectorized evaluated, the
+	// filters accessing the user variables are evaluated by row so that the assignments of a row
+	// are seen by the next one.
+	canVectorized := Vectorizable(filters)
 	for _, filter := range filters {
 		if !filter.Vectorized() {
 			canVectorized = false
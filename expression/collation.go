@@ -0,0 +1,74 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"strings"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/types"
+)
+
+// coercibility indicates which collation is used when the expressions in
+// different collations are compared, the lower one takes precedence.
+// See https://dev.mysql.com/doc/refman/5.7/en/charset-collation-coercibility.html
+type coercibility int
+
+const (
+	// coercibilityImplicit is the coercibility of the columns and the functions.
+	coercibilityImplicit coercibility = 2
+	// coercibilityCoercible is the coercibility of the literal strings.
+	coercibilityCoercible coercibility = 4
+	// coercibilityIgnorable is the coercibility of NULL and the non-string expressions.
+	coercibilityIgnorable coercibility = 6
+)
+
+func getCoercibility(expr Expression) coercibility {
+	if expr.GetType().EvalType() != types.ETString {
+		return coercibilityIgnorable
+	}
+	if con, ok := expr.(*Constant); ok {
+		if con.Value.IsNull() {
+			return coercibilityIgnorable
+		}
+		return coercibilityCoercible
+	}
+	return coercibilityImplicit
+}
+
+// DeriveCollationFromExprs derives the collation in which the string expressions
+// are compared. The comparison is binary if any of them is a binary string,
+// otherwise the collation of the expression with the lowest coercibility is used.
+// The expressions of the same coercibility in different collations are compared
+// in the binary collation as well.
+func DeriveCollationFromExprs(exprs ...Expression) string {
+	collation, lowest := "", coercibilityIgnorable
+	for _, expr := range exprs {
+		c := getCoercibility(expr)
+		if c == coercibilityIgnorable {
+			continue
+		}
+		tp := expr.GetType()
+		if types.IsBinaryStr(tp) {
+			return charset.CollationBin
+		}
+		switch {
+		case c < lowest:
+			collation, lowest = tp.Collate, c
+		case c == lowest && !strings.EqualFold(collation, tp.Collate):
+			collation = charset.CollationBin
+		}
+	}
+	return collation
+}
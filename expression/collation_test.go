@@ -0,0 +1,87 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+func newStringFieldTypeWithCollation(collation string) *types.FieldType {
+	tp := types.NewFieldType(mysql.TypeVarchar)
+	tp.Charset, tp.Collate = charset.CharsetUTF8MB4, collation
+	return tp
+}
+
+func (s *testEvaluatorSuite) TestDeriveCollationFromExprs(c *C) {
+	generalCI := newColumnWithType(0, newStringFieldTypeWithCollation("utf8mb4_general_ci"))
+	unicodeCI := newColumnWithType(1, newStringFieldTypeWithCollation("utf8mb4_unicode_ci"))
+	bin := newColumnWithType(2, newStringFieldTypeWithCollation(charset.CollationUTF8MB4))
+	binaryStr := newColumnWithType(3, types.NewFieldType(mysql.TypeVarchar))
+	types.SetBinChsClnFlag(binaryStr.RetType)
+	str := &Constant{Value: types.NewStringDatum("a"), RetType: newStringFieldTypeWithCollation("utf8mb4_unicode_ci")}
+	null := &Constant{Value: types.NewDatum(nil), RetType: newStringFieldTypeWithCollation("utf8mb4_unicode_ci")}
+
+	tests := []struct {
+		exprs    []Expression
+		expected string
+	}{
+		{[]Expression{generalCI, generalCI}, "utf8mb4_general_ci"},
+		{[]Expression{generalCI, str}, "utf8mb4_general_ci"},
+		{[]Expression{str, unicodeCI}, "utf8mb4_unicode_ci"},
+		{[]Expression{generalCI, null}, "utf8mb4_general_ci"},
+		{[]Expression{generalCI, newLonglong(1)}, "utf8mb4_general_ci"},
+		{[]Expression{generalCI, unicodeCI}, charset.CollationBin},
+		{[]Expression{generalCI, bin}, charset.CollationBin},
+		{[]Expression{generalCI, binaryStr}, charset.CollationBin},
+		{[]Expression{str, binaryStr}, charset.CollationBin},
+		{[]Expression{newLonglong(1), newLonglong(2)}, ""},
+	}
+	for _, t := range tests {
+		c.Assert(DeriveCollationFromExprs(t.exprs...), Equals, t.expected)
+	}
+}
+
+func (s *testEvaluatorSuite) TestCompareWithCollation(c *C) {
+	col := newColumnWithType(0, newStringFieldTypeWithCollation("utf8mb4_general_ci"))
+	col.Index = 0
+	tests := []struct {
+		funcName string
+		val      string
+		arg      string
+		expected int64
+	}{
+		{ast.EQ, "ABC", "abc", 1},
+		{ast.EQ, "Àbc ", "abc", 1},
+		{ast.EQ, "abd", "abc", 0},
+		{ast.NE, "ABC", "abc", 0},
+		{ast.LT, "abc", "ABD", 1},
+		{ast.GT, "abc", "ABD", 0},
+		{ast.In, "ABC", "abc", 1},
+		{ast.Strcmp, "ABC", "abc", 0},
+		{ast.Strcmp, "abd", "ABC", 1},
+	}
+	for _, t := range tests {
+		arg := &Constant{Value: types.NewStringDatum(t.arg), RetType: newStringFieldTypeWithCollation(charset.CollationUTF8MB4)}
+		f, err := newFunctionForTest(s.ctx, t.funcName, col, arg)
+		c.Assert(err, IsNil)
+		d, err := f.Eval(chunk.MutRowFromDatums(types.MakeDatums(t.val)).ToRow())
+		c.Assert(err, IsNil)
+		c.Assert(d.GetInt64(), Equals, t.expected, Commentf("%s(%q, %q)", t.funcName, t.val, t.arg))
+	}
+}
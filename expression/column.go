@@ -23,10 +23,125 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 )
 
+// CorrelatedColumn stands for a column in a correlated sub query.
+type CorrelatedColumn struct {
+	Column
+
+	// Data is the value of the column in the current row of the outer query, it is set by the Apply operator.
+	Data *types.Datum
+}
+
+// Clone implements Expression interface.
+func (col *CorrelatedColumn) Clone() Expression {
+	return col
+}
+
+// VecEvalInt evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETInt, input, result)
+}
+
+// VecEvalReal evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETReal, input, result)
+}
+
+// VecEvalString evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETString, input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *CorrelatedColumn) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, col, types.ETJson, input, result)
+}
+
+// Eval implements Expression interface.
+func (col *CorrelatedColumn) Eval(row chunk.Row) (types.Datum, error) {
+	return *col.Data, nil
+}
+
+// EvalInt returns int representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	if col.Data.IsNull() {
+		return 0, true, nil
+	}
+	if col.GetType().Hybrid() || col.Data.Kind() == types.KindString {
+		res, err := col.Data.ToInt64(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
+	return col.Data.GetInt64(), false, nil
+}
+
+// EvalReal returns real representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	if col.Data.IsNull() {
+		return 0, true, nil
+	}
+	if col.Data.Kind() != types.KindFloat64 && col.Data.Kind() != types.KindFloat32 {
+		res, err := col.Data.ToFloat64(ctx.GetSessionVars().StmtCtx)
+		return res, err != nil, err
+	}
+	return col.Data.GetFloat64(), false, nil
+}
+
+// EvalString returns string representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	if col.Data.IsNull() {
+		return "", true, nil
+	}
+	res, err := col.Data.ToString()
+	return res, err != nil, err
+}
+
+// EvalJSON returns JSON representation of CorrelatedColumn.
+func (col *CorrelatedColumn) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	if col.Data.IsNull() {
+		return json.BinaryJSON{}, true, nil
+	}
+	return col.Data.GetMysqlJSON(), false, nil
+}
+
+// Equal implements Expression interface.
+func (col *CorrelatedColumn) Equal(ctx sessionctx.Context, expr Expression) bool {
+	if cc, ok := expr.(*CorrelatedColumn); ok {
+		return col.Column.Equal(ctx, &cc.Column)
+	}
+	return false
+}
+
+// IsCorrelated implements Expression interface.
+func (col *CorrelatedColumn) IsCorrelated() bool {
+	return true
+}
+
+// ConstItem implements Expression interface.
+func (col *CorrelatedColumn) ConstItem() bool {
+	return false
+}
+
+// Decorrelate implements Expression interface.
+func (col *CorrelatedColumn) Decorrelate(schema *Schema) Expression {
+	if !schema.Contains(&col.Column) {
+		return col
+	}
+	return &col.Column
+}
+
+// ResolveIndices implements Expression interface.
+func (col *CorrelatedColumn) ResolveIndices(_ *Schema) (Expression, error) {
+	return col, nil
+}
+
+func (col *CorrelatedColumn) resolveIndices(_ *Schema) error {
+	return nil
+}
+
 // Column represents a column.
 type Column struct {
 	RetType *types.FieldType
@@ -41,7 +156,14 @@ type Column struct {
 
 	hashcode []byte
 
+	// VirtualExpr is the expression of a virtual generated column, the readers evaluate
+	// it to fill the column because its value isn't stored in the row.
+	VirtualExpr Expression
+
 	OrigName string
+	// IsHidden indicates that the column is a hidden column of an expression index, which
+	// isn't expanded by the wildcard.
+	IsHidden bool
 }
 
 // Equal implements Expression interface.
@@ -109,7 +231,7 @@ func (col *Column) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, resul
 
 // VecEvalString evaluates this expression in a vectorized manner.
 func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
-	if col.RetType.Hybrid() || ctx.GetSessionVars().StmtCtx.PadCharToFullLength {
+	if col.RetType.Hybrid() || col.RetType.Tp == mysql.TypeJSON || ctx.GetSessionVars().StmtCtx.PadCharToFullLength {
 		it := chunk.NewIterator4Chunk(input)
 		result.ReserveString(input.NumRows())
 		for row := it.Begin(); row != it.End(); row = it.Next() {
@@ -129,6 +251,12 @@ func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return nil
 }
 
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
 const columnPrefix = "Column#"
 
 // String implements Stringer interface.
@@ -196,6 +324,11 @@ func (col *Column) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bo
 		return res, err != nil, err
 	}
 
+	// The JSON value is used as its text in the string context.
+	if col.GetType().Tp == mysql.TypeJSON {
+		return row.GetJSON(col.Index).String(), false, nil
+	}
+
 	val := row.GetString(col.Index)
 	if ctx.GetSessionVars().StmtCtx.PadCharToFullLength && col.GetType().Tp == mysql.TypeString {
 		valLen := len([]rune(val))
@@ -206,12 +339,25 @@ func (col *Column) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bo
 	return val, false, nil
 }
 
+// EvalJSON returns JSON representation of Column.
+func (col *Column) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	if row.IsNull(col.Index) {
+		return json.BinaryJSON{}, true, nil
+	}
+	return row.GetJSON(col.Index), false, nil
+}
+
 // Clone implements Expression interface.
 func (col *Column) Clone() Expression {
 	newCol := *col
 	return &newCol
 }
 
+// EvalVirtualColumn evals the virtual column.
+func (col *Column) EvalVirtualColumn(row chunk.Row) (types.Datum, error) {
+	return col.VirtualExpr.Eval(row)
+}
+
 // IsCorrelated implements Expression interface.
 func (col *Column) IsCorrelated() bool {
 	return false
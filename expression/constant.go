@@ -15,12 +15,14 @@ package expression
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 )
@@ -47,9 +49,22 @@ var (
 
 // Constant stands for a constant value.
 type Constant struct {
-	Value    types.Datum
-	RetType  *types.FieldType
-	hashcode []byte
+	Value   types.Datum
+	RetType *types.FieldType
+	// DeferredExpr holds the expression the constant is folded from when the expression
+	// is stable within a statement but differs between statements, e.g. UNIX_TIMESTAMP().
+	// Value is the result at folding time; evaluating the constant evaluates DeferredExpr
+	// lazily and caches the result for the statement.
+	DeferredExpr Expression
+	hashcode     []byte
+	// deferredVal caches a *deferredValue evaluated from DeferredExpr.
+	deferredVal atomic.Value
+}
+
+// deferredValue is the value of a deferred constant in a statement.
+type deferredValue struct {
+	sc    *stmtctx.StatementContext
+	value types.Datum
 }
 
 // String implements fmt.Stringer interface.
@@ -87,41 +102,93 @@ func (c *Constant) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, res
 	return genVecFromConstExpr(ctx, c, types.ETString, input, result)
 }
 
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return genVecFromConstExpr(ctx, c, types.ETJson, input, result)
+}
+
+// getLazyDatum evaluates the deferred expression of the constant, the result is
+// cached so it's only evaluated once in a statement.
+func (c *Constant) getLazyDatum() (types.Datum, error) {
+	sf, ok := c.DeferredExpr.(*ScalarFunction)
+	if !ok {
+		return c.DeferredExpr.Eval(chunk.Row{})
+	}
+	sc := sf.GetCtx().GetSessionVars().StmtCtx
+	if v, ok := c.deferredVal.Load().(*deferredValue); ok && v.sc == sc {
+		return v.value, nil
+	}
+	d, err := sf.Eval(chunk.Row{})
+	if err != nil {
+		return d, err
+	}
+	c.deferredVal.Store(&deferredValue{sc: sc, value: d})
+	return d, nil
+}
+
 // Eval implements Expression interface.
 func (c *Constant) Eval(_ chunk.Row) (types.Datum, error) {
+	if c.DeferredExpr != nil {
+		return c.getLazyDatum()
+	}
 	return c.Value, nil
 }
 
 // EvalInt returns int representation of Constant.
 func (c *Constant) EvalInt(ctx sessionctx.Context, _ chunk.Row) (int64, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+	dt, err := c.Eval(chunk.Row{})
+	if err != nil {
+		return 0, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return 0, true, nil
 	}
-	if c.GetType().Hybrid() || c.Value.Kind() == types.KindString {
-		res, err := c.Value.ToInt64(ctx.GetSessionVars().StmtCtx)
+	if c.GetType().Hybrid() || dt.Kind() == types.KindString {
+		res, err := dt.ToInt64(ctx.GetSessionVars().StmtCtx)
 		return res, err != nil, err
 	}
-	return c.Value.GetInt64(), false, nil
+	return dt.GetInt64(), false, nil
 }
 
 // EvalReal returns real representation of Constant.
 func (c *Constant) EvalReal(ctx sessionctx.Context, _ chunk.Row) (float64, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+	dt, err := c.Eval(chunk.Row{})
+	if err != nil {
+		return 0, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return 0, true, nil
 	}
-	if c.GetType().Hybrid() || c.Value.Kind() == types.KindString {
-		res, err := c.Value.ToFloat64(ctx.GetSessionVars().StmtCtx)
+	if c.GetType().Hybrid() || dt.Kind() == types.KindString {
+		res, err := dt.ToFloat64(ctx.GetSessionVars().StmtCtx)
 		return res, err != nil, err
 	}
-	return c.Value.GetFloat64(), false, nil
+	return dt.GetFloat64(), false, nil
 }
 
 // EvalString returns string representation of Constant.
 func (c *Constant) EvalString(ctx sessionctx.Context, _ chunk.Row) (string, bool, error) {
-	if c.GetType().Tp == mysql.TypeNull || c.Value.IsNull() {
+	dt, err := c.Eval(chunk.Row{})
+	if err != nil {
+		return "", true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
 		return "", true, nil
 	}
-	res, err := c.Value.ToString()
+	res, err := dt.ToString()
+	return res, err != nil, err
+}
+
+// EvalJSON returns JSON representation of Constant.
+func (c *Constant) EvalJSON(ctx sessionctx.Context, _ chunk.Row) (json.BinaryJSON, bool, error) {
+	dt, err := c.Eval(chunk.Row{})
+	if err != nil {
+		return json.BinaryJSON{}, true, err
+	}
+	if c.GetType().Tp == mysql.TypeNull || dt.IsNull() {
+		return json.BinaryJSON{}, true, nil
+	}
+	res, err := dt.ToMysqlJSON()
 	return res, err != nil, err
 }
 
@@ -131,6 +198,9 @@ func (c *Constant) Equal(ctx sessionctx.Context, b Expression) bool {
 	if !ok {
 		return false
 	}
+	if c.DeferredExpr != nil || y.DeferredExpr != nil {
+		return c.DeferredExpr != nil && y.DeferredExpr != nil && c.DeferredExpr.Equal(ctx, y.DeferredExpr)
+	}
 	_, err1 := y.Eval(chunk.Row{})
 	_, err2 := c.Eval(chunk.Row{})
 	if err1 != nil || err2 != nil {
@@ -163,6 +233,10 @@ func (c *Constant) HashCode(sc *stmtctx.StatementContext) []byte {
 	if len(c.hashcode) > 0 {
 		return c.hashcode
 	}
+	if c.DeferredExpr != nil {
+		c.hashcode = c.DeferredExpr.HashCode(sc)
+		return c.hashcode
+	}
 	_, err := c.Eval(chunk.Row{})
 	if err != nil {
 		terror.Log(err)
@@ -175,6 +249,15 @@ func (c *Constant) HashCode(sc *stmtctx.StatementContext) []byte {
 	return c.hashcode
 }
 
+// SetValue binds a new value to the constant. It's used by the plan cache to
+// reuse a plan whose constants are the parameters of the statement, or are
+// deferred ones which should be refreshed for the statement.
+func (c *Constant) SetValue(d types.Datum, tp *types.FieldType) {
+	c.Value = d
+	c.RetType = tp
+	c.hashcode = nil
+}
+
 // ResolveIndices implements Expression interface.
 func (c *Constant) ResolveIndices(_ *Schema) (Expression, error) {
 	return c, nil
@@ -14,12 +14,15 @@
 package expression
 
 import (
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
 )
 
-// FoldConstant does constant folding optimization on an expression excluding deferred ones.
+// FoldConstant does constant folding optimization on an expression. Functions in
+// DeferredFunctions, and functions with deferred constant arguments, are folded to
+// constants that keep the origin expression as DeferredExpr.
 func FoldConstant(expr Expression) Expression {
 	return foldConstant(expr)
 }
@@ -36,17 +39,21 @@ func foldConstant(expr Expression) Expression {
 		argIsConst := make([]bool, len(args))
 		hasNullArg := false
 		allConstArg := true
+		_, isDeferred := DeferredFunctions[x.FuncName.L]
 		for i := 0; i < len(args); i++ {
 			switch x := args[i].(type) {
 			case *Constant:
 				argIsConst[i] = true
 				hasNullArg = hasNullArg || x.Value.IsNull()
+				isDeferred = isDeferred || x.DeferredExpr != nil
 			default:
 				allConstArg = false
 			}
 		}
 		if !allConstArg {
-			if !hasNullArg || !sc.InNullRejectCheck {
+			// The NULL argument of NullEQ doesn't make the result NULL or false,
+			// it depends on the other argument.
+			if !hasNullArg || !sc.InNullRejectCheck || x.FuncName.L == ast.NullEQ {
 				return expr
 			}
 			constArgs := make([]Expression, len(args))
@@ -73,11 +80,24 @@ func foldConstant(expr Expression) Expression {
 			}
 			return expr
 		}
+		// The warnings produced by folding are kept in the statement context, so they appear
+		// in SHOW WARNINGS just like they're produced at runtime.
+		_, warnCnt := sc.NumErrorWarnings()
 		value, err := x.Eval(chunk.Row{})
 		if err != nil {
+			// The expression isn't folded and will be evaluated again at runtime, which
+			// produces the warnings again.
+			sc.TruncateWarnings(warnCnt)
 			logutil.BgLogger().Debug("fold expression to constant", zap.String("expression", x.ExplainInfo()), zap.Error(err))
 			return expr
 		}
+		if isDeferred {
+			con := &Constant{Value: value, RetType: x.RetType, DeferredExpr: x}
+			// Cache the value for the statement, the deferred expression is not evaluated again
+			// and its warnings are not produced twice.
+			con.deferredVal.Store(&deferredValue{sc: sc, value: value})
+			return con
+		}
 		return &Constant{Value: value, RetType: x.RetType}
 	}
 	return expr
@@ -20,6 +20,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/disjointset"
 	"github.com/pingcap/tidb/util/logutil"
 	"go.uber.org/zap"
@@ -66,7 +67,8 @@ func (s *basePropConstSolver) tryToUpdateEQList(col *Column, con *Constant) (boo
 // validEqualCond checks if the cond is an expression like [column eq constant].
 func validEqualCond(cond Expression) (*Column, *Constant) {
 	if eq, ok := cond.(*ScalarFunction); ok {
-		if eq.FuncName.L != ast.EQ {
+		// The column equal to the constant in a case insensitive collation can't be replaced by it.
+		if eq.FuncName.L != ast.EQ || !collate.IsBinCollation(eq.Function.getCollation()) {
 			return nil, nil
 		}
 		if col, colOk := eq.GetArgs()[0].(*Column); colOk {
@@ -188,7 +190,7 @@ func (s *propConstSolver) propagateColumnEQ() {
 	visited := make([]bool, len(s.conditions))
 	s.unionSet = disjointset.NewIntSet(len(s.columns))
 	for i := range s.conditions {
-		if fun, ok := s.conditions[i].(*ScalarFunction); ok && fun.FuncName.L == ast.EQ {
+		if fun, ok := s.conditions[i].(*ScalarFunction); ok && fun.FuncName.L == ast.EQ && collate.IsBinCollation(fun.Function.getCollation()) {
 			lCol, lOk := fun.GetArgs()[0].(*Column)
 			rCol, rOk := fun.GetArgs()[1].(*Column)
 			if lOk && rOk {
@@ -419,7 +421,7 @@ func (s *propOuterJoinConstSolver) colsFromOuterAndInner(col1, col2 *Column) (*C
 // `outerCol1 = outerCol2` or `innerCol1 = innerCol2`, they do not help deriving new inner table conditions
 // which can be pushed down to children plan nodes, so we do not pick them.
 func (s *propOuterJoinConstSolver) validColEqualCond(cond Expression) (*Column, *Column) {
-	if fun, ok := cond.(*ScalarFunction); ok && fun.FuncName.L == ast.EQ {
+	if fun, ok := cond.(*ScalarFunction); ok && fun.FuncName.L == ast.EQ && collate.IsBinCollation(fun.Function.getCollation()) {
 		lCol, lOk := fun.GetArgs()[0].(*Column)
 		rCol, rOk := fun.GetArgs()[1].(*Column)
 		if lOk && rOk {
@@ -14,10 +14,14 @@
 package expression
 
 import (
+	"fmt"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/mock"
 	"sort"
 	"strings"
@@ -141,6 +145,31 @@ func (*testExpressionSuite) TestConstantPropagation(c *C) {
 			},
 			result: "eq(Column#0, Column#1), le(Column#0, plus(Column#0, 1)), le(Column#0, plus(Column#1, 1)), le(Column#1, plus(Column#1, 1))",
 		},
+		{
+			solver: []PropagateConstantSolver{newPropConstSolver()},
+			conditions: []Expression{
+				newFunction(ast.EQ, newColumn(0), newLonglong(1)),
+				newFunction(ast.GT, newColumn(0), newColumn(1)),
+			},
+			result: "eq(Column#0, 1), gt(1, Column#1)",
+		},
+		{
+			solver: []PropagateConstantSolver{newPropConstSolver()},
+			conditions: []Expression{
+				newFunction(ast.EQ, newColumn(0), newLonglong(1)),
+				newFunction(ast.GT, newFunction(ast.Plus, newColumn(0), newLonglong(1)), newColumn(1)),
+				newFunction(ast.In, newColumn(2), newColumn(0), newLonglong(5)),
+			},
+			result: "eq(Column#0, 1), gt(2, Column#1), in(Column#2, 1, 5)",
+		},
+		{
+			solver: []PropagateConstantSolver{newPropConstSolver()},
+			conditions: []Expression{
+				newFunction(ast.EQ, newColumn(0), newLonglong(1)),
+				newFunction(ast.GT, newColumn(0), newLonglong(2)),
+			},
+			result: "0",
+		},
 	}
 	for _, tt := range tests {
 		for _, solver := range tt.solver {
@@ -187,3 +216,39 @@ func (*testExpressionSuite) TestConstantFolding(c *C) {
 		c.Assert(newConds.String(), Equals, tt.result, Commentf("different for expr %s", tt.condition))
 	}
 }
+
+func (*testExpressionSuite) TestDeferredExprFolding(c *C) {
+	ctx := mock.NewContext()
+	typeLong := types.NewFieldType(mysql.TypeLonglong)
+	now := NewFunctionInternal(ctx, ast.UnixTimestamp, typeLong)
+	nowConst, ok := now.(*Constant)
+	c.Assert(ok, IsTrue)
+	c.Assert(nowConst.DeferredExpr, NotNil)
+
+	// The functions with deferred arguments are deferred as well.
+	plus := NewFunctionInternal(ctx, ast.Plus, typeLong, now, newLonglong(1))
+	plusConst, ok := plus.(*Constant)
+	c.Assert(ok, IsTrue)
+	c.Assert(plusConst.DeferredExpr, NotNil)
+	c.Assert(plusConst.Value.GetInt64(), Equals, nowConst.Value.GetInt64()+1)
+	lt := NewFunctionInternal(ctx, ast.LT, typeLong, newColumn(0), plus)
+	c.Assert(lt.String(), Equals, fmt.Sprintf("lt(Column#0, %d)", plusConst.Value.GetInt64()))
+
+	// The deferred constants are evaluated once in a statement.
+	sc := ctx.GetSessionVars().StmtCtx
+	d, err := plusConst.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, sc.GetNowTsCached().Unix()+1)
+	c.Assert(plusConst.deferredVal.Load().(*deferredValue).sc, Equals, sc)
+	newSc := &stmtctx.StatementContext{}
+	ctx.GetSessionVars().StmtCtx = newSc
+	d, err = plusConst.Eval(chunk.Row{})
+	c.Assert(err, IsNil)
+	c.Assert(d.GetInt64(), Equals, newSc.GetNowTsCached().Unix()+1)
+	c.Assert(plusConst.deferredVal.Load().(*deferredValue).sc, Equals, newSc)
+
+	// The deferred constants are not equal to the normal ones with the same value.
+	c.Assert(plusConst.Equal(ctx, newLonglong(d.GetInt64())), IsFalse)
+	c.Assert(plusConst.Equal(ctx, NewFunctionInternal(ctx, ast.Plus, typeLong, now, newLonglong(1))), IsTrue)
+	c.Assert(plusConst.HashCode(newSc), DeepEquals, plusConst.DeferredExpr.HashCode(newSc))
+}
@@ -79,6 +79,12 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 		f = &builtinNERealSig{base}
 	case tipb.ScalarFuncSig_NEString:
 		f = &builtinNEStringSig{base}
+	case tipb.ScalarFuncSig_NullEQInt:
+		f = &builtinNullEQIntSig{base}
+	case tipb.ScalarFuncSig_NullEQReal:
+		f = &builtinNullEQRealSig{base}
+	case tipb.ScalarFuncSig_NullEQString:
+		f = &builtinNullEQStringSig{base}
 	case tipb.ScalarFuncSig_PlusReal:
 		f = &builtinArithmeticPlusRealSig{base}
 	case tipb.ScalarFuncSig_PlusInt:
@@ -111,12 +117,14 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 		f = &builtinRealIsNullSig{base}
 	case tipb.ScalarFuncSig_StringIsNull:
 		f = &builtinStringIsNullSig{base}
+	case tipb.ScalarFuncSig_JsonIsNull:
+		f = &builtinJSONIsNullSig{base}
 	case tipb.ScalarFuncSig_IntIsNull:
 		f = &builtinIntIsNullSig{base}
 	case tipb.ScalarFuncSig_GetVar:
-		f = &builtinGetVarSig{base}
+		f = &builtinGetStringVarSig{base}
 	case tipb.ScalarFuncSig_SetVar:
-		f = &builtinSetVarSig{base}
+		f = &builtinSetStringVarSig{base}
 	case tipb.ScalarFuncSig_InInt:
 		f = &builtinInIntSig{base}
 	case tipb.ScalarFuncSig_InReal:
@@ -139,6 +147,12 @@ func getSignatureByPB(ctx sessionctx.Context, sigCode tipb.ScalarFuncSig, tp *ti
 		f = &builtinLengthSig{base}
 	case tipb.ScalarFuncSig_Strcmp:
 		f = &builtinStrcmpSig{base}
+	case tipb.ScalarFuncSig_LikeSig:
+		// The escape character is not a string argument, so it doesn't affect the collation.
+		base.collation = DeriveCollationFromExprs(args[:2]...)
+		_, patternIsConst := args[1].(*Constant)
+		_, escapeIsConst := args[2].(*Constant)
+		f = &builtinLikeSig{baseBuiltinFunc: base, constPattern: patternIsConst && escapeIsConst}
 
 	default:
 		e = errFunctionNotExists.GenWithStackByArgs("FUNCTION", sigCode)
@@ -29,10 +29,19 @@ var (
 	ErrCutValueGroupConcat     = terror.ClassExpression.New(mysql.ErrCutValueGroupConcat, mysql.MySQLErrName[mysql.ErrCutValueGroupConcat])
 	ErrFunctionsNoopImpl       = terror.ClassExpression.New(mysql.ErrNotSupportedYet, "function %s has only noop implementation in tidb now, use tidb_enable_noop_functions to enable these functions")
 	ErrIncorrectType           = terror.ClassExpression.New(mysql.ErrIncorrectType, mysql.MySQLErrName[mysql.ErrIncorrectType])
+	ErrInvalidTypeForJSON      = terror.ClassExpression.New(mysql.ErrInvalidTypeForJSON, mysql.MySQLErrName[mysql.ErrInvalidTypeForJSON])
+	ErrFunctionAlreadyExists   = terror.ClassExpression.New(mysql.ErrSpAlreadyExists, mysql.MySQLErrName[mysql.ErrSpAlreadyExists])
 
 	// All the un-exported errors are defined here:
 	errFunctionNotExists = terror.ClassExpression.New(mysql.ErrSpDoesNotExist, mysql.MySQLErrName[mysql.ErrSpDoesNotExist])
 	errNonUniq           = terror.ClassExpression.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	errIncorrectArgs     = terror.ClassExpression.New(mysql.ErrWrongArguments, mysql.MySQLErrName[mysql.ErrWrongArguments])
+	errWrongValueForType = terror.ClassExpression.New(mysql.ErrWrongValueForType, mysql.MySQLErrName[mysql.ErrWrongValueForType])
+
+	// All the un-exported warnings are defined here:
+	errWarnAllowedPacketOverflowed = terror.ClassExpression.New(mysql.ErrWarnAllowedPacketOverflowed, mysql.MySQLErrName[mysql.ErrWarnAllowedPacketOverflowed])
+	errWarnOptionIgnored           = terror.ClassExpression.New(mysql.WarnOptionIgnored, mysql.MySQLErrName[mysql.WarnOptionIgnored])
+	errInvalidArgForLogarithm      = terror.ClassExpression.New(mysql.ErrInvalidArgumentForLogarithm, mysql.MySQLErrName[mysql.ErrInvalidArgumentForLogarithm])
 )
 
 func init() {
@@ -40,6 +49,7 @@ func init() {
 		mysql.ErrWrongParamcountToNativeFct:        mysql.ErrWrongParamcountToNativeFct,
 		mysql.ErrDivisionByZero:                    mysql.ErrDivisionByZero,
 		mysql.ErrSpDoesNotExist:                    mysql.ErrSpDoesNotExist,
+		mysql.ErrSpAlreadyExists:                   mysql.ErrSpAlreadyExists,
 		mysql.ErrNotSupportedYet:                   mysql.ErrNotSupportedYet,
 		mysql.ErrZlibZData:                         mysql.ErrZlibZData,
 		mysql.ErrZlibZBuf:                          mysql.ErrZlibZBuf,
@@ -57,6 +67,9 @@ func init() {
 		mysql.ErrBadField:                          mysql.ErrBadField,
 		mysql.ErrNonUniq:                           mysql.ErrNonUniq,
 		mysql.ErrIncorrectType:                     mysql.ErrIncorrectType,
+		mysql.ErrInvalidTypeForJSON:                mysql.ErrInvalidTypeForJSON,
+		mysql.ErrWrongValueForType:                 mysql.ErrWrongValueForType,
+		mysql.ErrInvalidArgumentForLogarithm:       mysql.ErrInvalidArgumentForLogarithm,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassExpression] = expressionMySQLErrCodes
 }
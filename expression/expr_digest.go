@@ -0,0 +1,87 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// exprGenericSymbol is the normalized text of a constant.
+const exprGenericSymbol = "?"
+
+// NormalizeExpr returns the canonical text of an expression tree, the structurally
+// identical expressions have the same canonical text. Unlike HashCode, the constants
+// are parameterized as '?' and the names are lower-cased, e.g. both `eq(test.t.A, 1)`
+// and `eq(test.t.a, 2)` are normalized to `eq(test.t.a, ?)`. The columns without a
+// name are identified by their offsets in the input, e.g. `#0`.
+func NormalizeExpr(expr Expression) string {
+	var builder strings.Builder
+	normalizeExpr(&builder, expr)
+	return builder.String()
+}
+
+// ExprDigest returns the digest of the normalized expression.
+func ExprDigest(expr Expression) string {
+	_, digest := NormalizeExprDigest(expr)
+	return digest
+}
+
+// NormalizeExprDigest combines NormalizeExpr and ExprDigest into one method.
+func NormalizeExprDigest(expr Expression) (normalized, digest string) {
+	normalized = NormalizeExpr(expr)
+	hash := sha256.Sum256([]byte(normalized))
+	return normalized, hex.EncodeToString(hash[:])
+}
+
+func normalizeExpr(builder *strings.Builder, expr Expression) {
+	switch x := expr.(type) {
+	case *Constant:
+		// The deferred constants are re-evaluated when the plan is reused, so
+		// they are identified by the functions they come from.
+		if x.DeferredExpr != nil {
+			normalizeExpr(builder, x.DeferredExpr)
+			return
+		}
+		builder.WriteString(exprGenericSymbol)
+	case *CorrelatedColumn:
+		builder.WriteString("cor:")
+		normalizeColumn(builder, &x.Column)
+	case *Column:
+		normalizeColumn(builder, x)
+	case *ScalarFunction:
+		builder.WriteString(x.FuncName.L)
+		builder.WriteByte('(')
+		for i, arg := range x.GetArgs() {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+			normalizeExpr(builder, arg)
+		}
+		builder.WriteByte(')')
+	default:
+		builder.WriteString(strings.ToLower(expr.String()))
+	}
+}
+
+func normalizeColumn(builder *strings.Builder, col *Column) {
+	if col.OrigName != "" {
+		builder.WriteString(strings.ToLower(col.OrigName))
+		return
+	}
+	builder.WriteByte('#')
+	builder.WriteString(strconv.Itoa(col.Index))
+}
@@ -0,0 +1,62 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+func (*testExpressionSuite) TestNormalizeExprDigest(c *C) {
+	ctx := mock.NewContext()
+	sc := ctx.GetSessionVars().StmtCtx
+	typeLong := types.NewFieldType(mysql.TypeLonglong)
+	colA := &Column{UniqueID: 1, RetType: typeLong, OrigName: "test.t.A"}
+	colB := &Column{UniqueID: 2, RetType: typeLong, OrigName: "test.t.b"}
+	colA2 := &Column{UniqueID: 3, RetType: typeLong, OrigName: "test.t.a"}
+
+	// The constants are parameterized and the names are case-folded.
+	expr1 := newFunction(ast.LogicAnd, newFunction(ast.EQ, colA, newLonglong(1)), newFunction(ast.GT, colB, newLonglong(10)))
+	expr2 := newFunction(ast.LogicAnd, newFunction(ast.EQ, colA2, newLonglong(2)), newFunction(ast.GT, colB, newLonglong(20)))
+	normalized1, digest1 := NormalizeExprDigest(expr1)
+	normalized2, digest2 := NormalizeExprDigest(expr2)
+	c.Assert(normalized1, Equals, "and(eq(test.t.a, ?), gt(test.t.b, ?))")
+	c.Assert(normalized2, Equals, normalized1)
+	c.Assert(digest2, Equals, digest1)
+	c.Assert(ExprDigest(expr1), Equals, digest1)
+	c.Assert(digest1, HasLen, 64)
+	// HashCode tells the different constants and columns apart.
+	c.Assert(string(expr1.HashCode(sc)), Not(Equals), string(expr2.HashCode(sc)))
+
+	// The structurally different expressions have different digests.
+	expr3 := newFunction(ast.LogicAnd, newFunction(ast.GT, colB, newLonglong(10)), newFunction(ast.EQ, colA, newLonglong(1)))
+	c.Assert(NormalizeExpr(expr3), Equals, "and(gt(test.t.b, ?), eq(test.t.a, ?))")
+	c.Assert(ExprDigest(expr3), Not(Equals), digest1)
+	expr4 := newFunction(ast.EQ, colA, colB)
+	c.Assert(NormalizeExpr(expr4), Equals, "eq(test.t.a, test.t.b)")
+
+	// The unnamed columns are identified by their offsets, and the correlated
+	// columns are marked.
+	unnamed := &Column{UniqueID: 4, Index: 2, RetType: typeLong}
+	corCol := &CorrelatedColumn{Column: *colB}
+	c.Assert(NormalizeExpr(newFunction(ast.Plus, unnamed, corCol)), Equals, "plus(#2, cor:test.t.b)")
+
+	// The deferred constants are identified by the functions they come from.
+	now := NewFunctionInternal(ctx, ast.UnixTimestamp, typeLong)
+	c.Assert(now.(*Constant).DeferredExpr, NotNil)
+	c.Assert(NormalizeExpr(newFunction(ast.LT, colA, now)), Equals, "lt(test.t.a, unix_timestamp())")
+}
@@ -85,6 +85,23 @@ func NewPBConverter(client kv.Client, sc *stmtctx.StatementContext) PbConverter
 	return PbConverter{client: client, sc: sc}
 }
 
+// CanExprsPushDown returns true if all the expressions can be pushed down to the coprocessor.
+func CanExprsPushDown(sc *stmtctx.StatementContext, exprs []Expression, client kv.Client) bool {
+	pc := PbConverter{client: client, sc: sc}
+	for _, expr := range exprs {
+		if !pc.CanBePushedDown(expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// CanBePushedDown returns true if the expression can be converted to TiPB and
+// evaluated by the coprocessor.
+func (pc PbConverter) CanBePushedDown(expr Expression) bool {
+	return pc.ExprToPB(expr) != nil
+}
+
 // ExprToPB converts Expression to TiPB.
 func (pc PbConverter) ExprToPB(expr Expression) *tipb.Expr {
 	switch x := expr.(type) {
@@ -290,6 +307,7 @@ func (pc PbConverter) canFuncBePushed(sf *ScalarFunction) bool {
 		ast.LE,
 		ast.EQ,
 		ast.NE,
+		ast.NullEQ,
 		ast.GE,
 		ast.GT,
 		ast.In,
@@ -300,13 +318,16 @@ func (pc PbConverter) canFuncBePushed(sf *ScalarFunction) bool {
 		ast.Minus,
 		ast.Mul,
 		ast.Div,
+		ast.UnaryMinus,
 
 		// control flow functions.
 		ast.If,
 		ast.Ifnull,
 
 		// string functions.
-		ast.Length:
+		ast.Length,
+		ast.Strcmp,
+		ast.Like:
 		return true
 	}
 	return false
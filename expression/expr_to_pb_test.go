@@ -0,0 +1,75 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+func (s *testEvaluatorSuite) TestExprToPB(c *C) {
+	sc := s.ctx.GetSessionVars().StmtCtx
+	client := new(mock.Client)
+	pc := NewPBConverter(client, sc)
+	tps := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar)}
+	intCol, strCol := newColumnWithType(0, tps[0]), newColumnWithType(1, tps[1])
+	intCol.Index, strCol.Index = 0, 1
+	row := chunk.MutRowFromDatums(types.MakeDatums(3, "abc")).ToRow()
+
+	tests := []struct {
+		funcName string
+		args     []Expression
+		pushed   bool
+	}{
+		{ast.GT, []Expression{intCol, newLonglong(1)}, true},
+		{ast.Plus, []Expression{intCol, newLonglong(1)}, true},
+		{ast.UnaryMinus, []Expression{intCol}, true},
+		{ast.Length, []Expression{strCol}, true},
+		{ast.Strcmp, []Expression{strCol, &Constant{Value: types.NewStringDatum("abd"), RetType: tps[1]}}, true},
+		{ast.Like, []Expression{strCol, &Constant{Value: types.NewStringDatum("a_c%"), RetType: tps[1]}, newLonglong(int64('\\'))}, true},
+		{ast.In, []Expression{intCol, newLonglong(1), newLonglong(3)}, true},
+		{ast.NullEQ, []Expression{intCol, &Constant{Value: types.Datum{}, RetType: tps[0]}}, true},
+		{ast.Repeat, []Expression{strCol, newLonglong(2)}, false},
+	}
+	exprs := make([]Expression, 0, len(tests))
+	for _, t := range tests {
+		expr, err := NewFunction(s.ctx, t.funcName, types.NewFieldType(mysql.TypeUnspecified), t.args...)
+		c.Assert(err, IsNil)
+		exprs = append(exprs, expr)
+		comment := Commentf("%s", expr)
+		c.Assert(pc.CanBePushedDown(expr), Equals, t.pushed, comment)
+		if !t.pushed {
+			c.Assert(pc.ExprToPB(expr), IsNil, comment)
+			continue
+		}
+		// The expression decoded by the coprocessor is evaluated to the same result.
+		pbExpr := pc.ExprToPB(expr)
+		c.Assert(pbExpr, NotNil, comment)
+		decoded, err := PBToExpr(pbExpr, tps, sc)
+		c.Assert(err, IsNil, comment)
+		expected, err := expr.Eval(row)
+		c.Assert(err, IsNil, comment)
+		d, err := decoded.Eval(row)
+		c.Assert(err, IsNil, comment)
+		cmp, err := d.CompareDatum(sc, &expected)
+		c.Assert(err, IsNil, comment)
+		c.Assert(cmp, Equals, 0, comment)
+	}
+	c.Assert(CanExprsPushDown(sc, exprs[:len(exprs)-1], client), IsTrue)
+	c.Assert(CanExprsPushDown(sc, exprs, client), IsFalse)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 )
 
@@ -39,6 +40,10 @@ const (
 // EvalAstExpr evaluates ast expression directly.
 var EvalAstExpr func(sctx sessionctx.Context, expr ast.ExprNode) (types.Datum, error)
 
+// RewriteAstExpr rewrites ast expression directly, the columns in the expression are resolved
+// by the names of the schema.
+var RewriteAstExpr func(sctx sessionctx.Context, expr ast.ExprNode, schema *Schema, names types.NameSlice) (Expression, error)
+
 // VecExpr contains all vectorized evaluation methods.
 type VecExpr interface {
 	// Vectorized returns if this expression supports vectorized evaluation.
@@ -52,6 +57,9 @@ type VecExpr interface {
 
 	// VecEvalString evaluates this expression in a vectorized manner.
 	VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// VecEvalJSON evaluates this expression in a vectorized manner.
+	VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
 }
 
 // Expression represents all scalar expression in SQL.
@@ -72,6 +80,9 @@ type Expression interface {
 	// EvalString returns the string representation of expression.
 	EvalString(ctx sessionctx.Context, row chunk.Row) (val string, isNull bool, err error)
 
+	// EvalJSON returns the JSON representation of expression.
+	EvalJSON(ctx sessionctx.Context, row chunk.Row) (val json.BinaryJSON, isNull bool, err error)
+
 	// GetType gets the type that the expression returns.
 	GetType() *types.FieldType
 
@@ -321,6 +332,8 @@ func toBool(sc *stmtctx.StatementContext, eType types.EvalType, buf *chunk.Colum
 				}
 			}
 		}
+	case types.ETJson:
+		return errors.Errorf("cannot convert type json.BinaryJSON to bool")
 	}
 	return errors.Trace(err)
 }
@@ -334,6 +347,8 @@ func VecEval(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result
 		err = expr.VecEvalReal(ctx, input, result)
 	case types.ETString:
 		err = expr.VecEvalString(ctx, input, result)
+	case types.ETJson:
+		err = expr.VecEvalJSON(ctx, input, result)
 	default:
 		err = errors.New(fmt.Sprintf("invalid eval type %v", expr.GetType().EvalType()))
 	}
@@ -435,6 +450,87 @@ func SplitDNFItems(onExpr Expression) []Expression {
 	return splitNormalFormItems(onExpr, ast.LogicOr)
 }
 
+// distributeNormalForm expands expr into a list of terms, where the terms are connected by outerOp and the
+// items inside a term are connected by innerOp, by distributing innerOp over outerOp.
+// The second return value is false if the number of terms would exceed limit.
+func distributeNormalForm(ctx sessionctx.Context, expr Expression, outerOp, innerOp string, limit int) ([][]Expression, bool) {
+	sf, ok := expr.(*ScalarFunction)
+	if !ok || (sf.FuncName.L != outerOp && sf.FuncName.L != innerOp) {
+		return [][]Expression{{expr}}, true
+	}
+	var terms [][]Expression
+	for i, arg := range sf.GetArgs() {
+		argTerms, ok := distributeNormalForm(ctx, arg, outerOp, innerOp, limit)
+		if !ok {
+			return nil, false
+		}
+		if sf.FuncName.L == outerOp || i == 0 {
+			if len(terms)+len(argTerms) > limit {
+				return nil, false
+			}
+			terms = append(terms, argTerms...)
+			continue
+		}
+		if len(terms)*len(argTerms) > limit {
+			return nil, false
+		}
+		newTerms := make([][]Expression, 0, len(terms)*len(argTerms))
+		for _, term := range terms {
+			for _, argTerm := range argTerms {
+				newTerm := make([]Expression, 0, len(term)+len(argTerm))
+				newTerm = append(newTerm, term...)
+				for _, item := range argTerm {
+					if !containsEqualExpr(ctx, newTerm, item) {
+						newTerm = append(newTerm, item)
+					}
+				}
+				newTerms = append(newTerms, newTerm)
+			}
+		}
+		terms = newTerms
+	}
+	return terms, true
+}
+
+func containsEqualExpr(ctx sessionctx.Context, exprs []Expression, e Expression) bool {
+	for _, expr := range exprs {
+		if expr.Equal(ctx, e) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToDNF converts expr to DNF(disjunctive normal form) and returns the DNF items, e.g.
+// "(a or b) and c" is converted to ["a and c", "b and c"]. Since the conversion may blow up exponentially,
+// it gives up and returns false if the number of DNF items would exceed limit.
+func ToDNF(ctx sessionctx.Context, expr Expression, limit int) ([]Expression, bool) {
+	terms, ok := distributeNormalForm(ctx, expr, ast.LogicOr, ast.LogicAnd, limit)
+	if !ok {
+		return nil, false
+	}
+	items := make([]Expression, 0, len(terms))
+	for _, term := range terms {
+		items = append(items, ComposeCNFCondition(ctx, term...))
+	}
+	return items, true
+}
+
+// ToCNF converts expr to CNF(conjunctive normal form) and returns the CNF items, e.g.
+// "(a and b) or c" is converted to ["a or c", "b or c"]. Since the conversion may blow up exponentially,
+// it gives up and returns false if the number of CNF items would exceed limit.
+func ToCNF(ctx sessionctx.Context, expr Expression, limit int) ([]Expression, bool) {
+	terms, ok := distributeNormalForm(ctx, expr, ast.LogicAnd, ast.LogicOr, limit)
+	if !ok {
+		return nil, false
+	}
+	items := make([]Expression, 0, len(terms))
+	for _, term := range terms {
+		items = append(items, ComposeDNFCondition(ctx, term...))
+	}
+	return items, true
+}
+
 // EvaluateExprWithNull sets columns in schema as null and calculate the final result of the scalar function.
 // If the Expression is a non-constant value, it means the result is unknown.
 func EvaluateExprWithNull(ctx sessionctx.Context, schema *Schema, expr Expression) Expression {
@@ -454,6 +550,58 @@ func EvaluateExprWithNull(ctx sessionctx.Context, schema *Schema, expr Expressio
 	return expr
 }
 
+// IsNullRejecting checks whether a condition is null-rejecting, that is it filters out the rows whose columns
+// in the schema are all NULL, e.g. the NULL-extended rows of the inner side of an outer join.
+// A condition would be null-rejecting in one of following cases:
+// If it is a predicate containing a reference to an inner table that evaluates to UNKNOWN or FALSE when one of its arguments is NULL.
+// If it is a conjunction containing a null-rejecting condition as a conjunct.
+// If it is a disjunction of null-rejecting conditions.
+func IsNullRejecting(ctx sessionctx.Context, schema *Schema, expr Expression) bool {
+	return isNullRejecting(ctx, schema, PushDownNot(ctx, expr))
+}
+
+func isNullRejecting(ctx sessionctx.Context, schema *Schema, expr Expression) bool {
+	// The AND and OR functions are not folded to constants if some of their arguments are not constants.
+	if sf, ok := expr.(*ScalarFunction); ok {
+		switch sf.FuncName.L {
+		case ast.LogicAnd:
+			for _, item := range SplitCNFItems(sf) {
+				if isNullRejecting(ctx, schema, item) {
+					return true
+				}
+			}
+		case ast.LogicOr:
+			rejecting := true
+			for _, item := range SplitDNFItems(sf) {
+				if !isNullRejecting(ctx, schema, item) {
+					rejecting = false
+					break
+				}
+			}
+			if rejecting {
+				return true
+			}
+		}
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	// The warnings of the evaluation with NULL columns never appear at runtime, drop them.
+	_, warnCnt := sc.NumErrorWarnings()
+	sc.InNullRejectCheck = true
+	result := EvaluateExprWithNull(ctx, schema, expr)
+	sc.InNullRejectCheck = false
+	sc.TruncateWarnings(warnCnt)
+	x, ok := result.(*Constant)
+	if !ok {
+		return false
+	}
+	if x.Value.IsNull() {
+		return true
+	} else if isTrue, err := x.Value.ToBool(sc); err == nil && isTrue == 0 {
+		return true
+	}
+	return false
+}
+
 // TableInfo2SchemaAndNames converts the TableInfo to the schema and name slice.
 func TableInfo2SchemaAndNames(ctx sessionctx.Context, dbName model.CIStr, tbl *model.TableInfo) (*Schema, []*types.FieldName) {
 	cols, names := ColumnInfos2ColumnsAndNames(ctx, dbName, tbl.Name, tbl.Columns)
@@ -502,23 +650,25 @@ func TableInfo2SchemaAndNames(ctx sessionctx.Context, dbName model.CIStr, tbl *m
 func ColumnInfos2ColumnsAndNames(ctx sessionctx.Context, dbName, tblName model.CIStr, colInfos []*model.ColumnInfo) ([]*Column, types.NameSlice) {
 	columns := make([]*Column, 0, len(colInfos))
 	names := make([]*types.FieldName, 0, len(colInfos))
-	for i, col := range colInfos {
+	for _, col := range colInfos {
 		if col.State != model.StatePublic {
 			continue
 		}
-		names = append(names, &types.FieldName{
+		name := &types.FieldName{
 			OrigTblName: tblName,
 			OrigColName: col.Name,
 			DBName:      dbName,
 			TblName:     tblName,
 			ColName:     col.Name,
-		})
+		}
+		names = append(names, name)
 		newCol := &Column{
 			RetType:  &col.FieldType,
 			ID:       col.ID,
 			UniqueID: ctx.GetSessionVars().AllocPlanColumnID(),
 			Index:    col.Offset,
-			OrigName: names[i].String(),
+			OrigName: name.String(),
+			IsHidden: col.Hidden,
 		}
 		columns = append(columns, newCol)
 	}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
@@ -41,3 +42,29 @@ func (s *testEvaluatorSuite) TestConstant(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(res, DeepEquals, []byte{0x22, 0x30, 0x22})
 }
+
+func (s *testEvaluatorSuite) TestIsNullRejecting(c *C) {
+	// col0 and col1 are in the schema, e.g. they are from the inner side of an outer join.
+	col0, col1, col2 := newColumn(0), newColumn(1), newColumn(2)
+	schema := NewSchema(col0, col1)
+	nullConst := &Constant{Value: types.Datum{}, RetType: types.NewFieldType(mysql.TypeNull)}
+	tests := []struct {
+		expr      Expression
+		rejecting bool
+	}{
+		{newFunction(ast.EQ, col0, newLonglong(1)), true},
+		{newFunction(ast.GT, col0, col2), true},
+		{newFunction(ast.EQ, col2, newLonglong(1)), false},
+		{newFunction(ast.IsNull, col0), false},
+		{newFunction(ast.UnaryNot, newFunction(ast.IsNull, col0)), true},
+		{newFunction(ast.NullEQ, col0, newLonglong(1)), true},
+		{newFunction(ast.NullEQ, col0, nullConst), false},
+		{newFunction(ast.LogicAnd, newFunction(ast.EQ, col0, newLonglong(1)), newFunction(ast.EQ, col2, newLonglong(1))), true},
+		{newFunction(ast.LogicOr, newFunction(ast.EQ, col0, newLonglong(1)), newFunction(ast.EQ, col2, newLonglong(1))), false},
+		{newFunction(ast.LogicOr, newFunction(ast.EQ, col0, newLonglong(1)), newFunction(ast.LT, col1, newLonglong(1))), true},
+		{newFunction(ast.UnaryNot, newFunction(ast.LogicOr, newFunction(ast.IsNull, col0), newFunction(ast.EQ, col2, newLonglong(1)))), true},
+	}
+	for _, t := range tests {
+		c.Assert(IsNullRejecting(s.ctx, schema, t.expr), Equals, t.rejecting, Commentf("%s", t.expr))
+	}
+}
@@ -19,9 +19,101 @@ import (
 
 // unFoldableFunctions stores functions which can not be folded duration constant folding stage.
 var unFoldableFunctions = map[string]struct{}{
-	ast.RowFunc: {},
-	ast.SetVar:  {},
-	ast.GetVar:  {},
+	ast.RowFunc:   {},
+	ast.SetVar:    {},
+	ast.GetVar:    {},
+	ast.UUID:      {},
+	ast.UUIDShort: {},
+}
+
+// DeterministicFunctions stores functions whose results only depend on their arguments and the current
+// database, which is a part of the result cache key. Only the statements calling these functions can be
+// served by the result cache, so a function added to funcs is not cached until it's listed here.
+var DeterministicFunctions = map[string]struct{}{
+	// common functions
+	ast.Ifnull: {},
+	ast.If:     {},
+	ast.IsNull: {},
+
+	// compare functions
+	ast.GE:       {},
+	ast.LE:       {},
+	ast.EQ:       {},
+	ast.NE:       {},
+	ast.NullEQ:   {},
+	ast.LT:       {},
+	ast.GT:       {},
+	ast.Greatest: {},
+	ast.Least:    {},
+	ast.Interval: {},
+	ast.In:       {},
+
+	// arithmetical and logic functions
+	ast.Plus:       {},
+	ast.Minus:      {},
+	ast.Div:        {},
+	ast.Mul:        {},
+	ast.UnaryNot:   {},
+	ast.UnaryMinus: {},
+	ast.LogicAnd:   {},
+	ast.LogicOr:    {},
+
+	// math functions
+	ast.Ln:       {},
+	ast.Log:      {},
+	ast.Log2:     {},
+	ast.Log10:    {},
+	ast.Pow:      {},
+	ast.Power:    {},
+	ast.Round:    {},
+	ast.Truncate: {},
+	ast.Conv:     {},
+
+	// string functions
+	ast.Length:         {},
+	ast.OctetLength:    {},
+	ast.Strcmp:         {},
+	ast.Like:           {},
+	ast.Regexp:         {},
+	ast.RegexpLike:     {},
+	ast.SubstringIndex: {},
+	ast.Lpad:           {},
+	ast.Rpad:           {},
+	ast.Instr:          {},
+	ast.Replace:        {},
+	ast.Repeat:         {},
+
+	// encryption functions, AES_ENCRYPT and AES_DECRYPT depend on the block_encryption_mode variable
+	ast.MD5:   {},
+	ast.SHA1:  {},
+	ast.SHA:   {},
+	ast.SHA2:  {},
+	ast.CRC32: {},
+
+	// miscellaneous functions
+	ast.RowFunc:   {},
+	ast.Grouping:  {},
+	ast.IsUUID:    {},
+	ast.UUIDToBin: {},
+	ast.BinToUUID: {},
+
+	// information functions
+	ast.Database: {},
+	ast.Schema:   {},
+	ast.Version:  {},
+
+	// json functions
+	ast.JSONType:     {},
+	ast.JSONExtract:  {},
+	ast.JSONUnquote:  {},
+	ast.JSONSet:      {},
+	ast.JSONContains: {},
+}
+
+// DeferredFunctions stores functions which are foldable but should be deferred as well when plan cache is enabled.
+// Note that, these functions must be foldable at first place, i.e, they are not in `unFoldableFunctions`.
+var DeferredFunctions = map[string]struct{}{
+	ast.UnixTimestamp: {},
 }
 
 // inequalFunctions stores functions which cannot be propagated from column equal condition.
@@ -32,6 +124,35 @@ var inequalFunctions = map[string]struct{}{
 // mutableEffectsFunctions stores functions which are mutable or have side effects, specifically,
 // we cannot remove them from filter even if they have duplicates.
 var mutableEffectsFunctions = map[string]struct{}{
-	ast.SetVar: {},
-	ast.GetVar: {},
+	ast.SetVar:    {},
+	ast.GetVar:    {},
+	ast.UUID:      {},
+	ast.UUIDShort: {},
+}
+
+// evalCostFactors stores the per-row evaluation cost of the functions which are much more expensive
+// than the others, whose cost is 1. It's used by EvalCost to order the conditions and cost the projections.
+var evalCostFactors = map[string]float64{
+	ast.Like:           5,
+	ast.Regexp:         20,
+	ast.RegexpLike:     20,
+	ast.SubstringIndex: 3,
+	ast.Lpad:           3,
+	ast.Rpad:           3,
+	ast.Instr:          3,
+	ast.Replace:        3,
+	ast.Repeat:         3,
+	ast.MD5:            10,
+	ast.SHA1:           10,
+	ast.SHA:            10,
+	ast.SHA2:           10,
+	ast.CRC32:          5,
+	ast.AesEncrypt:     20,
+	ast.AesDecrypt:     20,
+	ast.UUID:           5,
+	ast.JSONType:       5,
+	ast.JSONExtract:    10,
+	ast.JSONUnquote:    5,
+	ast.JSONSet:        10,
+	ast.JSONContains:   10,
 }
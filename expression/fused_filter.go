@@ -0,0 +1,314 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+)
+
+type fusedCmpOp int
+
+const (
+	fusedLT fusedCmpOp = iota
+	fusedLE
+	fusedGT
+	fusedGE
+	fusedEQ
+	fusedNE
+)
+
+// reverse returns the op which has the same result after the arguments are swapped.
+func (op fusedCmpOp) reverse() fusedCmpOp {
+	switch op {
+	case fusedLT:
+		return fusedGT
+	case fusedLE:
+		return fusedGE
+	case fusedGT:
+		return fusedLT
+	case fusedGE:
+		return fusedLE
+	}
+	return op
+}
+
+// pass returns whether the comparison result `cmp` satisfies the op.
+func (op fusedCmpOp) pass(cmp int) bool {
+	switch op {
+	case fusedLT:
+		return cmp < 0
+	case fusedLE:
+		return cmp <= 0
+	case fusedGT:
+		return cmp > 0
+	case fusedGE:
+		return cmp >= 0
+	case fusedEQ:
+		return cmp == 0
+	}
+	return cmp != 0
+}
+
+// fusedCond is a comparison between a column and a constant.
+type fusedCond struct {
+	op     fusedCmpOp
+	tp     types.EvalType
+	colIdx int
+	isNull bool
+	i64    int64
+	f64    float64
+	str    string
+}
+
+// FusedFilter evaluates a list of filters of the shape `col CMP const` directly on
+// the columns of a chunk. It avoids the buffers and the generic evaluation of the
+// scalar functions, which are the main costs of the hot and simple filters.
+type FusedFilter struct {
+	conds []fusedCond
+}
+
+// NewFusedFilter returns a FusedFilter for the filters, nil is returned if any of the
+// filters is not a comparison between a column and a constant.
+func NewFusedFilter(ctx sessionctx.Context, filters []Expression) *FusedFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+	conds := make([]fusedCond, 0, len(filters))
+	for _, filter := range filters {
+		cond, ok := newFusedCond(ctx, filter)
+		if !ok {
+			return nil
+		}
+		conds = append(conds, cond)
+	}
+	return &FusedFilter{conds: conds}
+}
+
+func newFusedCond(ctx sessionctx.Context, filter Expression) (cond fusedCond, ok bool) {
+	sf, ok := filter.(*ScalarFunction)
+	if !ok {
+		return cond, false
+	}
+	cond.op, cond.tp, ok = fusedCmpOpOf(sf.Function)
+	if !ok {
+		return cond, false
+	}
+	args := sf.GetArgs()
+	col, isCol := args[0].(*Column)
+	con, isCon := args[1].(*Constant)
+	if !isCol || !isCon {
+		col, isCol = args[1].(*Column)
+		con, isCon = args[0].(*Constant)
+		if !isCol || !isCon {
+			return cond, false
+		}
+		cond.op = cond.op.reverse()
+	}
+	// The unsigned integers are compared in a different way.
+	if mysql.HasUnsignedFlag(col.GetType().Flag) || mysql.HasUnsignedFlag(con.GetType().Flag) {
+		return cond, false
+	}
+	if col.GetType().EvalType() != cond.tp || con.GetType().EvalType() != cond.tp || col.GetType().Hybrid() {
+		return cond, false
+	}
+	// The strings are compared by their bytes in the fused filters.
+	if cond.tp == types.ETString && !collate.IsBinCollation(sf.Function.getCollation()) {
+		return cond, false
+	}
+	// The values of these columns are not read from the chunk directly, see the VecEval functions of Column.
+	if col.GetType().Tp == mysql.TypeFloat || (cond.tp == types.ETString && ctx.GetSessionVars().StmtCtx.PadCharToFullLength) {
+		return cond, false
+	}
+	cond.colIdx = col.Index
+	if con.Value.IsNull() {
+		cond.isNull = true
+		return cond, true
+	}
+	switch cond.tp {
+	case types.ETInt:
+		if con.Value.Kind() != types.KindInt64 {
+			return cond, false
+		}
+		cond.i64 = con.Value.GetInt64()
+	case types.ETReal:
+		if con.Value.Kind() != types.KindFloat64 {
+			return cond, false
+		}
+		cond.f64 = con.Value.GetFloat64()
+	case types.ETString:
+		if con.Value.Kind() != types.KindString && con.Value.Kind() != types.KindBytes {
+			return cond, false
+		}
+		cond.str = con.Value.GetString()
+	}
+	return cond, true
+}
+
+func fusedCmpOpOf(f builtinFunc) (fusedCmpOp, types.EvalType, bool) {
+	switch f.(type) {
+	case *builtinLTIntSig:
+		return fusedLT, types.ETInt, true
+	case *builtinLTRealSig:
+		return fusedLT, types.ETReal, true
+	case *builtinLTStringSig:
+		return fusedLT, types.ETString, true
+	case *builtinLEIntSig:
+		return fusedLE, types.ETInt, true
+	case *builtinLERealSig:
+		return fusedLE, types.ETReal, true
+	case *builtinLEStringSig:
+		return fusedLE, types.ETString, true
+	case *builtinGTIntSig:
+		return fusedGT, types.ETInt, true
+	case *builtinGTRealSig:
+		return fusedGT, types.ETReal, true
+	case *builtinGTStringSig:
+		return fusedGT, types.ETString, true
+	case *builtinGEIntSig:
+		return fusedGE, types.ETInt, true
+	case *builtinGERealSig:
+		return fusedGE, types.ETReal, true
+	case *builtinGEStringSig:
+		return fusedGE, types.ETString, true
+	case *builtinEQIntSig:
+		return fusedEQ, types.ETInt, true
+	case *builtinEQRealSig:
+		return fusedEQ, types.ETReal, true
+	case *builtinEQStringSig:
+		return fusedEQ, types.ETString, true
+	case *builtinNEIntSig:
+		return fusedNE, types.ETInt, true
+	case *builtinNERealSig:
+		return fusedNE, types.ETReal, true
+	case *builtinNEStringSig:
+		return fusedNE, types.ETString, true
+	}
+	return 0, types.ETInt, false
+}
+
+// Filter applies the filters to the chunk and returns a bool slice, which indicates
+// whether a row passes the filters. It has the same result as VectorizedFilter.
+func (f *FusedFilter) Filter(input *chunk.Chunk, selected []bool) []bool {
+	sel := input.Sel()
+	input.SetSel(nil)
+	n := input.NumRows()
+	input.SetSel(sel)
+	selected = selected[:0]
+	if sel != nil {
+		for i := 0; i < n; i++ {
+			selected = append(selected, false)
+		}
+		for _, i := range sel {
+			selected[i] = true
+		}
+	} else {
+		for i := 0; i < n; i++ {
+			selected = append(selected, true)
+		}
+	}
+	for i := range f.conds {
+		f.conds[i].filter(input.Column(f.conds[i].colIdx), selected)
+	}
+	return selected
+}
+
+func (c *fusedCond) filter(col *chunk.Column, selected []bool) {
+	if c.isNull {
+		// Comparing with NULL always returns NULL.
+		for i := range selected {
+			selected[i] = false
+		}
+		return
+	}
+	switch c.tp {
+	case types.ETInt:
+		c.filterInt(col, selected)
+	case types.ETReal:
+		c.filterReal(col, selected)
+	case types.ETString:
+		c.filterString(col, selected)
+	}
+}
+
+func (c *fusedCond) filterInt(col *chunk.Column, selected []bool) {
+	i64s, v := col.Int64s(), c.i64
+	switch c.op {
+	case fusedLT:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && i64s[i] < v
+		}
+	case fusedLE:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && i64s[i] <= v
+		}
+	case fusedGT:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && i64s[i] > v
+		}
+	case fusedGE:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && i64s[i] >= v
+		}
+	case fusedEQ:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && i64s[i] == v
+		}
+	case fusedNE:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && i64s[i] != v
+		}
+	}
+}
+
+func (c *fusedCond) filterReal(col *chunk.Column, selected []bool) {
+	f64s, v := col.Float64s(), c.f64
+	switch c.op {
+	case fusedLT:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && f64s[i] < v
+		}
+	case fusedLE:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && f64s[i] <= v
+		}
+	case fusedGT:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && f64s[i] > v
+		}
+	case fusedGE:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && f64s[i] >= v
+		}
+	case fusedEQ:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && f64s[i] == v
+		}
+	case fusedNE:
+		for i := range selected {
+			selected[i] = selected[i] && !col.IsNull(i) && f64s[i] != v
+		}
+	}
+}
+
+func (c *fusedCond) filterString(col *chunk.Column, selected []bool) {
+	for i := range selected {
+		if selected[i] {
+			selected[i] = !col.IsNull(i) && c.op.pass(types.CompareString(col.GetString(i), c.str))
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+func (s *testEvaluatorSuite) TestFusedFilter(c *C) {
+	fts := []*types.FieldType{
+		types.NewFieldType(mysql.TypeLonglong),
+		types.NewFieldType(mysql.TypeDouble),
+		types.NewFieldType(mysql.TypeVarString),
+	}
+	cols := make([]*Column, len(fts))
+	for i, ft := range fts {
+		cols[i] = &Column{Index: i, RetType: ft}
+	}
+	chk := chunk.NewChunkWithCapacity(fts, 1024)
+	for i := 0; i < 1024; i++ {
+		if rand.Intn(10) == 0 {
+			chk.AppendNull(0)
+		} else {
+			chk.AppendInt64(0, rand.Int63n(100))
+		}
+		if rand.Intn(10) == 0 {
+			chk.AppendNull(1)
+		} else {
+			chk.AppendFloat64(1, float64(rand.Intn(100))/10)
+		}
+		if rand.Intn(10) == 0 {
+			chk.AppendNull(2)
+		} else {
+			chk.AppendString(2, fmt.Sprintf("%02d", rand.Intn(100)))
+		}
+	}
+
+	cons := []*Constant{
+		{Value: types.NewIntDatum(50), RetType: fts[0]},
+		{Value: types.NewFloat64Datum(5), RetType: fts[1]},
+		{Value: types.NewStringDatum("50"), RetType: fts[2]},
+	}
+	ops := []string{ast.LT, ast.LE, ast.GT, ast.GE, ast.EQ, ast.NE}
+	for _, op := range ops {
+		for i := range cols {
+			f1, err := newFunctionForTest(s.ctx, op, cols[i], cons[i])
+			c.Assert(err, IsNil)
+			f2, err := newFunctionForTest(s.ctx, op, cons[(i+1)%3], cols[(i+1)%3])
+			c.Assert(err, IsNil)
+			filters := []Expression{f1, f2}
+			fused := NewFusedFilter(s.ctx, filters)
+			c.Assert(fused, NotNil)
+
+			expected, err := VectorizedFilter(s.ctx, filters, chunk.NewIterator4Chunk(chk), nil)
+			c.Assert(err, IsNil)
+			c.Assert(fused.Filter(chk, nil), DeepEquals, expected, Commentf("%v", filters))
+
+			chk.SetSel([]int{1, 3, 5, 100, 1000})
+			expected, err = VectorizedFilter(s.ctx, filters, chunk.NewIterator4Chunk(chk), nil)
+			c.Assert(err, IsNil)
+			c.Assert(fused.Filter(chk, nil), DeepEquals, expected, Commentf("%v", filters))
+			chk.SetSel(nil)
+		}
+	}
+
+	// Comparing with NULL filters out all the rows.
+	f, err := newFunctionForTest(s.ctx, ast.EQ, cols[0], &Constant{Value: types.NewDatum(nil), RetType: fts[0]})
+	c.Assert(err, IsNil)
+	fused := NewFusedFilter(s.ctx, []Expression{f})
+	c.Assert(fused, NotNil)
+	for _, selected := range fused.Filter(chk, nil) {
+		c.Assert(selected, IsFalse)
+	}
+
+	// The filters which are not comparisons between columns and constants are not fused.
+	f, err = newFunctionForTest(s.ctx, ast.LT, cols[0], cols[0])
+	c.Assert(err, IsNil)
+	c.Assert(NewFusedFilter(s.ctx, []Expression{f}), IsNil)
+	f, err = newFunctionForTest(s.ctx, ast.LT, cols[0], cons[1])
+	c.Assert(err, IsNil)
+	c.Assert(NewFusedFilter(s.ctx, []Expression{f}), IsNil)
+	c.Assert(NewFusedFilter(s.ctx, []Expression{cols[0]}), IsNil)
+}
+
+func BenchmarkFusedFilter(b *testing.B) {
+	ctx := mock.NewContext()
+	fts := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeDouble)}
+	chk := chunk.NewChunkWithCapacity(fts, 1024)
+	for i := 0; i < 1024; i++ {
+		chk.AppendInt64(0, rand.Int63n(100))
+		chk.AppendFloat64(1, rand.Float64()*10)
+	}
+	f1 := NewFunctionInternal(ctx, ast.GT, types.NewFieldType(mysql.TypeLonglong),
+		&Column{Index: 0, RetType: fts[0]}, &Constant{Value: types.NewIntDatum(50), RetType: fts[0]})
+	f2 := NewFunctionInternal(ctx, ast.LT, types.NewFieldType(mysql.TypeLonglong),
+		&Column{Index: 1, RetType: fts[1]}, &Constant{Value: types.NewFloat64Datum(5), RetType: fts[1]})
+	filters := []Expression{f1, f2}
+	selected := make([]bool, 0, 1024)
+	it := chunk.NewIterator4Chunk(chk)
+
+	b.Run("Vec", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var err error
+			selected, err = VectorizedFilter(ctx, filters, it, selected)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	fused := NewFusedFilter(ctx, filters)
+	b.Run("Fused", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			selected = fused.Filter(chk, selected)
+		}
+	})
+}
@@ -76,6 +76,9 @@ func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vecEvalInt(in
 {{ if .type.Fixed }}
 	arg0 := buf0.{{ .type.TypeNameInColumn }}s()
 	arg1 := buf1.{{ .type.TypeNameInColumn }}s()
+{{- end }}
+{{- if eq .type.ETName "String" }}
+	collator := b.collator()
 {{- end }}
 	result.ResizeInt64(n, false)
 	result.MergeNulls(buf0, buf1)
@@ -87,7 +90,7 @@ func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vecEvalInt(in
 {{- if eq .type.ETName "Real" }}
 		val := types.CompareFloat64(arg0[i], arg1[i])
 {{- else }}
-		val := types.CompareString(buf0.GetString(i), buf1.GetString(i))
+		val := collator.Compare(buf0.GetString(i), buf1.GetString(i))
 {{- end }}
 		if val {{ .compare.Operator }} 0 {
 			i64s[i] = 1
@@ -103,6 +106,61 @@ func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vectorized()
 }
 `))
 
+var builtinNullEQCompareVecTpl = template.Must(template.New("").Parse(`
+func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0, err := b.bufAllocator.get(types.ET{{ .type.ETName }}, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf0)
+	if err := b.args[0].VecEval{{ .type.TypeName }}(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1, err := b.bufAllocator.get(types.ET{{ .type.ETName }}, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf1)
+	if err := b.args[1].VecEval{{ .type.TypeName }}(b.ctx, input, buf1); err != nil {
+		return err
+	}
+
+{{ if .type.Fixed }}
+	arg0 := buf0.{{ .type.TypeNameInColumn }}s()
+	arg1 := buf1.{{ .type.TypeNameInColumn }}s()
+{{- end }}
+{{- if eq .type.ETName "String" }}
+	collator := b.collator()
+{{- end }}
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		isNull0 := buf0.IsNull(i)
+		isNull1 := buf1.IsNull(i)
+		switch {
+		case isNull0 && isNull1:
+			i64s[i] = 1
+		case isNull0 != isNull1:
+			i64s[i] = 0
+{{- if eq .type.ETName "Real" }}
+		case types.CompareFloat64(arg0[i], arg1[i]) == 0:
+{{- else }}
+		case collator.Compare(buf0.GetString(i), buf1.GetString(i)) == 0:
+{{- end }}
+			i64s[i] = 1
+		default:
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtin{{ .compare.CompareName }}{{ .type.TypeName }}Sig) vectorized() bool {
+	return true
+}
+`))
+
 const builtinCompareVecTestHeader = `import (
 	"testing"
 
@@ -156,6 +214,7 @@ var comparesMap = []CompareContext{
 	{CompareName: "GE", Operator: ">="},
 	{CompareName: "EQ", Operator: "=="},
 	{CompareName: "NE", Operator: "!="},
+	{CompareName: "NullEQ", Operator: "<=>"},
 }
 
 var typesMap = []TypeContext{
@@ -178,7 +237,11 @@ func generateDotGo(fileName string, compares []CompareContext, types []TypeConte
 			if typeCtx.TypeName == TypeInt.TypeName {
 				continue
 			}
-			err := builtinCompareVecTpl.Execute(w, ctx)
+			tpl := builtinCompareVecTpl
+			if compareCtx.CompareName == "NullEQ" {
+				tpl = builtinNullEQCompareVecTpl
+			}
+			err := tpl.Execute(w, ctx)
 			if err != nil {
 				return err
 			}
@@ -58,24 +58,8 @@ func (b *builtinIfNull{{ .TypeName }}Sig) vecEval{{ .TypeName }}(input *chunk.Ch
 	if err := b.args[0].VecEval{{ .TypeName }}(b.ctx, input, result); err != nil {
 		return err
 	}
-	buf1, err := b.bufAllocator.get(types.ET{{ .ETName }}, n)
-	if err != nil {
-		return err
-	}
-	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEval{{ .TypeName }}(b.ctx, input, buf1); err != nil {
-		return err
-	}
-
-	arg0 := result.{{ .TypeNameInColumn }}s()
-	arg1 := buf1.{{ .TypeNameInColumn }}s()
-	for i := 0; i < n; i++ {
-		if result.IsNull(i) && !buf1.IsNull(i) {
-			result.SetNull(i, false)
-			arg0[i] = arg1[i]
-		}
-	}
-	{{ else }}
+	isNull := vecIsNull(result, n)
+	{{- else }}
 	buf0, err := b.bufAllocator.get(types.ET{{ .ETName }}, n)
 	if err != nil {
 		return err
@@ -84,24 +68,46 @@ func (b *builtinIfNull{{ .TypeName }}Sig) vecEval{{ .TypeName }}(input *chunk.Ch
 	if err := b.args[0].VecEval{{ .TypeName }}(b.ctx, input, buf0); err != nil {
 		return err
 	}
+	isNull := vecIsNull(buf0, n)
+	{{- end }}
 	buf1, err := b.bufAllocator.get(types.ET{{ .ETName }}, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEval{{ .TypeName }}(b.ctx, input, buf1); err != nil {
+	err = vecEvalOnRows(input, isNull, true, func() error {
+		return b.args[1].VecEval{{ .TypeName }}(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
 
+	{{ if .Fixed -}}
+	arg0 := result.{{ .TypeNameInColumn }}s()
+	arg1 := buf1.{{ .TypeNameInColumn }}s()
+	for i, j := 0, 0; i < n; i++ {
+		if !isNull[i] {
+			continue
+		}
+		if !buf1.IsNull(j) {
+			result.SetNull(i, false)
+			arg0[i] = arg1[j]
+		}
+		j++
+	}
+	{{ else -}}
 	result.Reserve{{ .TypeNameInColumn }}(n)
-	for i := 0; i < n; i++ {
-		if !buf0.IsNull(i) {
+	for i, j := 0, 0; i < n; i++ {
+		if !isNull[i] {
 			result.Append{{ .TypeNameInColumn }}(buf0.Get{{ .TypeNameInColumn }}(i))
-		} else if !buf1.IsNull(i) {
-			result.Append{{ .TypeNameInColumn }}(buf1.Get{{ .TypeNameInColumn }}(i))
-		} else {
+			continue
+		}
+		if buf1.IsNull(j) {
 			result.AppendNull()
+		} else {
+			result.Append{{ .TypeNameInColumn }}(buf1.Get{{ .TypeNameInColumn }}(j))
 		}
+		j++
 	}
 	{{ end -}}
 	return nil
@@ -126,68 +132,72 @@ func (b *builtinIf{{ .TypeName }}Sig) vecEval{{ .TypeName }}(input *chunk.Chunk,
 	if err := b.args[0].VecEvalInt(b.ctx, input, buf0); err != nil {
 		return err
 	}
-
-{{- if .Fixed }}
-	if err := b.args[1].VecEval{{ .TypeName }}(b.ctx, input, result); err != nil {
-		return err
-	}
-{{- else }}
+	isTrue := vecIsTrue(buf0, n)
 	buf1, err := b.bufAllocator.get(types.ET{{ .ETName }}, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf1)
-	if err := b.args[1].VecEval{{ .TypeName }}(b.ctx, input, buf1); err != nil {
+	err = vecEvalOnRows(input, isTrue, true, func() error {
+		return b.args[1].VecEval{{ .TypeName }}(b.ctx, input, buf1)
+	})
+	if err != nil {
 		return err
 	}
-{{- end }}
 	buf2, err := b.bufAllocator.get(types.ET{{ .ETName }}, n)
 	if err != nil {
 		return err
 	}
 	defer b.bufAllocator.put(buf2)
-	if err := b.args[2].VecEval{{ .TypeName }}(b.ctx, input, buf2); err != nil {
+	err = vecEvalOnRows(input, isTrue, false, func() error {
+		return b.args[2].VecEval{{ .TypeName }}(b.ctx, input, buf2)
+	})
+	if err != nil {
 		return err
 	}
 
-{{ if not .Fixed }}
-	result.Reserve{{ .TypeNameInColumn }}(n)
-{{- end }}
-	arg0 := buf0.Int64s()
-{{- if .Fixed }}
-	arg2 := buf2.{{ .TypeNameInColumn }}s()
+{{ if .Fixed }}
+	result.Resize{{ .TypeNameInColumn }}(n, false)
 	rs := result.{{ .TypeNameInColumn }}s()
-{{- end }}
-	for i := 0; i < n; i++ {
-		arg := arg0[i]
-		isNull0 := buf0.IsNull(i)
-		switch {
-		case isNull0 || arg == 0:
-{{- if .Fixed }}
-			if buf2.IsNull(i) {
+	arg1 := buf1.{{ .TypeNameInColumn }}s()
+	arg2 := buf2.{{ .TypeNameInColumn }}s()
+	for i, j1, j2 := 0, 0, 0; i < n; i++ {
+		if isTrue[i] {
+			if buf1.IsNull(j1) {
 				result.SetNull(i, true)
 			} else {
-				result.SetNull(i, false)
-				rs[i] = arg2[i]
+				rs[i] = arg1[j1]
 			}
+			j1++
+			continue
+		}
+		if buf2.IsNull(j2) {
+			result.SetNull(i, true)
+		} else {
+			rs[i] = arg2[j2]
+		}
+		j2++
+	}
 {{- else }}
-			if buf2.IsNull(i) {
-				result.AppendNull()
-			} else {
-				result.Append{{ .TypeNameInColumn }}(buf2.Get{{ .TypeNameInColumn }}(i))
-			}
-{{- end }}
-		case arg != 0:
-{{- if .Fixed }}
-{{- else }}
-			if buf1.IsNull(i) {
+	result.Reserve{{ .TypeNameInColumn }}(n)
+	for i, j1, j2 := 0, 0, 0; i < n; i++ {
+		if isTrue[i] {
+			if buf1.IsNull(j1) {
 				result.AppendNull()
 			} else {
-				result.Append{{ .TypeNameInColumn }}(buf1.Get{{ .TypeNameInColumn }}(i))
+				result.Append{{ .TypeNameInColumn }}(buf1.Get{{ .TypeNameInColumn }}(j1))
 			}
-{{- end }}
+			j1++
+			continue
+		}
+		if buf2.IsNull(j2) {
+			result.AppendNull()
+		} else {
+			result.Append{{ .TypeNameInColumn }}(buf2.Get{{ .TypeNameInColumn }}(j2))
 		}
+		j2++
 	}
+{{- end }}
 	return nil
 }
 
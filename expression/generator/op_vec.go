@@ -0,0 +1,237 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"text/template"
+
+	. "github.com/pingcap/tidb/expression/generator/helper"
+)
+
+const header = `// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go generate in expression/generator; DO NOT EDIT.
+
+package expression
+`
+
+const newLine = "\n"
+
+const builtinOpImports = `import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+`
+
+var builtinIsNullTmpl = template.Must(template.New("builtinIsNullTmpl").Parse(`
+{{ range . }}
+func (b *{{ .SigName }}) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+{{- if eq .Input.TypeName "Int" }}
+	// The result column has the same type as the argument, so evaluate it in place.
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+
+	i64s := result.Int64s()
+	for i := 0; i < len(i64s); i++ {
+		if result.IsNull(i) {
+			i64s[i] = 1
+			result.SetNull(i, false)
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+{{- else }}
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get(types.ET{{ .Input.ETName }}, n)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEval{{ .Input.TypeName }}(b.ctx, input, buf); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+{{- end }}
+}
+
+func (b *{{ .SigName }}) vectorized() bool {
+	return true
+}
+{{ end }}
+`))
+
+var testFile = template.Must(template.New("testFile").Parse(`// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by go generate in expression/generator; DO NOT EDIT.
+
+package expression
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/types"
+)
+
+var vecBuiltin{{ .Category }}GeneratedCases = map[string][]vecExprBenchCase{
+{{- range .Functions }}
+	ast.{{ .FuncName }}: {
+	{{- range .Sigs }}
+		// {{ .SigName }}
+		{
+			retEvalType:   types.ET{{ .Output.ETName }},
+			childrenTypes: []types.EvalType{types.ET{{ .Input.ETName }}},
+			geners:        []dataGenerator{&defaultGener{eType: types.ET{{ .Input.ETName }}, nullRation: 0.5}},
+		},
+	{{- end }}
+	},
+{{- end }}
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltin{{ .Category }}EvalOneVecGenerated(c *C) {
+	testVectorizedEvalOneVec(c, vecBuiltin{{ .Category }}GeneratedCases)
+}
+
+func (s *testEvaluatorSuite) TestVectorizedBuiltin{{ .Category }}FuncGenerated(c *C) {
+	testVectorizedBuiltinFunc(c, vecBuiltin{{ .Category }}GeneratedCases)
+}
+
+func BenchmarkVectorizedBuiltin{{ .Category }}EvalOneVecGenerated(b *testing.B) {
+	benchmarkVectorizedEvalOneVec(b, vecBuiltin{{ .Category }}GeneratedCases)
+}
+
+func BenchmarkVectorizedBuiltin{{ .Category }}FuncGenerated(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltin{{ .Category }}GeneratedCases)
+}
+`))
+
+type sig struct {
+	SigName       string
+	Input, Output TypeContext
+}
+
+// isNullSigsTmpl is the spec of the vectorized IS NULL functions. To vectorize the IS NULL of
+// another type, add its signature here and run "go generate" under the expression package.
+var isNullSigsTmpl = []sig{
+	{SigName: "builtinIntIsNullSig", Input: TypeInt, Output: TypeInt},
+	{SigName: "builtinRealIsNullSig", Input: TypeReal, Output: TypeInt},
+	{SigName: "builtinStringIsNullSig", Input: TypeString, Output: TypeInt},
+}
+
+type function struct {
+	FuncName string
+	Sigs     []sig
+}
+
+var tmplVal = struct {
+	Category  string
+	Functions []function
+}{
+	Category: "Op",
+	Functions: []function{
+		{FuncName: "IsNull", Sigs: isNullSigsTmpl},
+	},
+}
+
+func generateDotGo(fileName string) error {
+	w := new(bytes.Buffer)
+	w.WriteString(header)
+	w.WriteString(newLine)
+	w.WriteString(builtinOpImports)
+	err := builtinIsNullTmpl.Execute(w, isNullSigsTmpl)
+	if err != nil {
+		return err
+	}
+	data, err := format.Source(w.Bytes())
+	if err != nil {
+		log.Println("[Warn]", fileName+": gofmt failed", err)
+		data = w.Bytes() // write original data for debugging
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
+
+func generateTestDotGo(fileName string) error {
+	w := new(bytes.Buffer)
+	err := testFile.Execute(w, tmplVal)
+	if err != nil {
+		return err
+	}
+	data, err := format.Source(w.Bytes())
+	if err != nil {
+		log.Println("[Warn]", fileName+": gofmt failed", err)
+		data = w.Bytes() // write original data for debugging
+	}
+	return ioutil.WriteFile(fileName, data, 0644)
+}
+
+// generateOneFile generate one xxx.go file and the associated xxx_test.go file.
+func generateOneFile(fileNamePrefix string) (err error) {
+	err = generateDotGo(fileNamePrefix + ".go")
+	if err != nil {
+		return
+	}
+	err = generateTestDotGo(fileNamePrefix + "_test.go")
+	return
+}
+
+func main() {
+	var err error
+	outputDir := "."
+	err = generateOneFile(filepath.Join(outputDir, "builtin_op_vec_generated"))
+	if err != nil {
+		log.Fatalln("generateOneFile", err)
+	}
+}
@@ -94,6 +94,8 @@ var builtinInTmpl = template.Must(template.New("builtinInTmpl").Parse(`
 					compareResult = 0
 				}
 		}
+	{{- else if eq .Input.TypeName "String" -}}
+		compareResult = collator.Compare(arg0, arg1)
 	{{- else -}}
 		compareResult = types.Compare{{ .Input.TypeNameInColumn }}(arg0, arg1)
 	{{- end -}}
@@ -118,6 +120,9 @@ func (b *{{.SigName}}) vecEvalInt(input *chunk.Chunk, result *chunk.Column) erro
 	{{- if $InputInt }}
 		isUnsigned0 := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
 	{{- end }}
+	{{- if $InputString }}
+		collator := b.collator()
+	{{- end }}
 	var compareResult int
 
 	for j := 1; j < len(b.args); j++ {
@@ -21,20 +21,38 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/mock"
 	"github.com/pingcap/tidb/util/testkit"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 var _ = Suite(&testIntegrationSuite{})
 var _ = Suite(&testIntegrationSuite2{})
 
+func init() {
+	// The functions must be registered before the sessions are created.
+	err := expression.RegisterBuiltinFunc("test_udf_repeat", expression.NewUDFFunctionClass("test_udf_repeat", 2, 2, types.ETString,
+		func(_ sessionctx.Context, args []types.Datum) (types.Datum, error) {
+			if args[0].IsNull() || args[1].IsNull() {
+				return types.Datum{}, nil
+			}
+			return types.NewStringDatum(strings.Repeat(args[0].GetString(), int(args[1].GetInt64()))), nil
+		}))
+	if err != nil {
+		panic(err)
+	}
+}
+
 type testIntegrationSuite struct {
 	store kv.Storage
 	dom   *domain.Domain
@@ -111,7 +129,7 @@ func (s *testIntegrationSuite) TestFilterExtractFromDNF(c *C) {
 		},
 		{
 			exprStr: "(a = 1 and a = 1) or a = 1 or b = 1",
-			result:  "[or(or(and(eq(test.t.a, 1), eq(test.t.a, 1)), eq(test.t.a, 1)), eq(test.t.b, 1))]",
+			result:  "[or(and(eq(test.t.a, 1), eq(test.t.a, 1)), or(eq(test.t.a, 1), eq(test.t.b, 1)))]",
 		},
 		{
 			exprStr: "(a = 1 and b = 2) or (a = 1 and b = 3) or (a = 1 and b = 4)",
@@ -196,6 +214,26 @@ func (s *testIntegrationSuite) TestUnknowHintIgnore(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (s *testIntegrationSuite) TestRegisteredBuiltinFunc(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a varchar(10), b int)")
+	tk.MustExec("insert into t values ('ab', 2), ('c', 3), (null, 1)")
+	tk.MustQuery("select test_udf_repeat(a, b) from t order by b").Check(testkit.Rows("<nil>", "abab", "ccc"))
+	// The function is evaluated in TiDB instead of the storage.
+	tk.MustQuery("explain select a from t where test_udf_repeat(a, b) = 'ccc'").Check(testkit.Rows(
+		"Projection_4 8000.00 root test.t.a",
+		"└─Selection_5 8000.00 root eq(test_udf_repeat(test.t.a, test.t.b), \"ccc\")",
+		"  └─TableReader_7 10000.00 root data:TableScan_6",
+		"    └─TableScan_6 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo"))
+	tk.MustQuery("select a from t where test_udf_repeat(a, b) = 'ccc'").Check(testkit.Rows("c"))
+	_, err := tk.Exec("select test_udf_repeat(a) from t")
+	c.Assert(expression.ErrIncorrectParameterCount.Equal(err), IsTrue, Commentf("err %v", err))
+}
+
 func (s *testIntegrationSuite) TestForeignKeyVar(c *C) {
 
 	tk := testkit.NewTestKit(c, s.store)
@@ -298,3 +336,131 @@ func (s *testIntegrationSuite) TestDecodetoChunkReuse(c *C) {
 	c.Assert(count, Equals, 200)
 	rs.Close()
 }
+
+func (s *testIntegrationSuite) TestGreatestLeastInterval(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b double, c varchar(10), d int unsigned)")
+	tk.MustExec("insert into t values (1, 1.5, '2', 3), (5, -2.5, '10', null), (null, 0, '10', 4)")
+	tk.MustQuery("select greatest(a, b), least(a, b), greatest(c, b), least(a, c), greatest(c, '9'), greatest(a, d) from t").Check(testkit.Rows(
+		"1.5 1 2 1 9 3",
+		"5 -2.5 10 5 9 <nil>",
+		"<nil> <nil> 10 <nil> 9 <nil>",
+	))
+	tk.MustQuery("select interval(a, 0, 2, 4), interval(b, a, d), interval(a, 1, 5) from t").Check(testkit.Rows(
+		"1 1 1",
+		"3 0 2",
+		"-1 1 -1",
+	))
+	tk.MustQuery("select a from t where greatest(a, b) > 1 order by a").Check(testkit.Rows("1", "5"))
+	tk.MustExec("drop table t")
+}
+
+func (s *testIntegrationSuite) TestMathBuiltin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	defer s.cleanEnv(c)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b double, c varchar(20), d bigint unsigned)")
+	tk.MustExec("insert into t values (8, 2.5, '255', 18446744073709551615), (-1, -0.5, 'ff', 1250), (null, null, null, null)")
+
+	// The arguments out of the domain of the logarithms get NULL and a warning.
+	tk.MustQuery("select ln(a), log(a), log2(a), log10(a), log(2, a) from t").Check(testkit.Rows(
+		"2.0794415416798357 2.0794415416798357 3 0.9030899869919435 3",
+		"<nil> <nil> <nil> <nil> <nil>",
+		"<nil> <nil> <nil> <nil> <nil>",
+	))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 3020 Invalid argument for logarithm",
+		"Warning 3020 Invalid argument for logarithm",
+		"Warning 3020 Invalid argument for logarithm",
+		"Warning 3020 Invalid argument for logarithm",
+		"Warning 3020 Invalid argument for logarithm",
+	))
+	tk.MustQuery("select ln(0), log(1, 2), log(100) / log(10)").Check(testkit.Rows("<nil> <nil> 2"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 3020 Invalid argument for logarithm",
+		"Warning 3020 Invalid argument for logarithm",
+	))
+
+	tk.MustQuery("select pow(a, 2), power(b, a), pow(2, 0.5) from t").Check(testkit.Rows(
+		"64 1525.87890625 1.4142135623730951",
+		"1 -2 1.4142135623730951",
+		"<nil> <nil> 1.4142135623730951",
+	))
+	tk.MustQuery("select round(b), round(b, 1), round(a, -1), round(d, -2), truncate(b, 0), truncate(d, -2) from t").Check(testkit.Rows(
+		"3 2.5 10 18446744073709551600 2 18446744073709551600",
+		"-1 -0.5 0 1300 -0 1200",
+		"<nil> <nil> <nil> <nil> <nil> <nil>",
+	))
+	tk.MustQuery("select round(1.55, 1), round(-2.5), round(123, -1), truncate(1.999, 2)").Check(testkit.Rows("1.6 -3 120 1.99"))
+	tk.MustQuery("select conv(c, 16, 10), conv(a, 10, 2), conv(a, 10, -2), conv(b, 10, 16), conv('z', 36, 10) from t").Check(testkit.Rows(
+		"597 1000 1000 2 35",
+		"255 1111111111111111111111111111111111111111111111111111111111111111 -1 0 35",
+		"<nil> <nil> <nil> <nil> 35",
+	))
+
+	err := tk.QueryToErr("select pow(a, 400) from t")
+	c.Assert(err.Error(), Equals, "[types:1690]DOUBLE value is out of range in 'pow(test.t.a, 400)'")
+	err = tk.QueryToErr("select round(d, -3) from t")
+	c.Assert(err.Error(), Equals, "[types:1690]BIGINT UNSIGNED value is out of range in 'round(test.t.d, -3)'")
+	_, err = tk.Exec("select truncate(b) from t")
+	c.Assert(expression.ErrIncorrectParameterCount.Equal(err), IsTrue, Commentf("err %v", err))
+	tk.MustExec("drop table t")
+}
+
+func (s *testIntegrationSuite) TestFoldConstantWarnings(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values (1)")
+
+	tk.MustQuery("select 'abc' + null").Check(testkit.Rows("<nil>"))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect FLOAT value: 'abc'"))
+	tk.MustQuery("select a from t where a = 1 and '1x' + 1 = 2").Check(testkit.Rows())
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1292 Truncated incorrect FLOAT value: '1x'"))
+	// The warnings of the null-rejecting check are dropped, the condition is evaluated once
+	// for each side of the join at runtime.
+	tk.MustQuery("select t1.a from t t1 left join t t2 on t1.a = t2.a where '1x' + t2.a > 0").Check(testkit.Rows("1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 1292 Truncated incorrect FLOAT value: '1x'",
+		"Warning 1292 Truncated incorrect FLOAT value: '1x'"))
+	tk.MustExec("drop table t")
+}
+
+func (s *testIntegrationSuite) TestInfoBuiltin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustQuery("select database(), schema()").Check(testkit.Rows("<nil> <nil>"))
+	tk.MustExec("use test")
+	tk.MustQuery("select database(), schema()").Check(testkit.Rows("test test"))
+	tk.MustQuery("select version()").Check(testkit.Rows(mysql.ServerVersion))
+	tk.Se.GetSessionVars().ConnectionID = 5
+	tk.MustQuery("select connection_id()").Check(testkit.Rows("5"))
+	_, err := tk.Exec("select version(1)")
+	c.Assert(err, NotNil)
+
+	tk.MustQuery("select user(), current_user, current_user(), current_role()").Check(testkit.Rows("<nil> <nil> <nil> NONE"))
+	tk.Se.GetSessionVars().User = &auth.UserIdentity{Username: "root", Hostname: "localhost"}
+	tk.MustQuery("select user(), session_user(), system_user(), current_user()").Check(testkit.Rows("root@localhost root@localhost root@localhost root@localhost"))
+}
+
+func (s *testIntegrationSuite) TestUUIDBuiltin(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("insert into t values (1), (2), (3)")
+
+	// UUID and UUID_SHORT are evaluated for each row.
+	tk.MustQuery("select count(distinct u), count(distinct s) from (select uuid() u, uuid_short() s from t) x").Check(testkit.Rows("3 3"))
+	tk.MustQuery("select is_uuid(uuid()), is_uuid('6ccd780c-baba-1026-9564-5b8c656024db'), is_uuid('abc'), is_uuid(null)").Check(testkit.Rows("1 1 0 <nil>"))
+	tk.MustQuery("select length(uuid_to_bin('6ccd780c-baba-1026-9564-5b8c656024db', 1))").Check(testkit.Rows("16"))
+	tk.MustQuery("select bin_to_uuid(uuid_to_bin('6ccd780c-baba-1026-9564-5b8c656024db', 1), 1), bin_to_uuid(uuid_to_bin('{6CCD780C-BABA-1026-9564-5B8C656024DB}'))").Check(
+		testkit.Rows("6ccd780c-baba-1026-9564-5b8c656024db 6ccd780c-baba-1026-9564-5b8c656024db"))
+	err := tk.QueryToErr("select uuid_to_bin('abc')")
+	c.Assert(err.Error(), Equals, "[expression:1411]Incorrect string value: 'abc' for function uuid_to_bin")
+	tk.MustExec("drop table t")
+}
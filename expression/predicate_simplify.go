@@ -0,0 +1,142 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// constBool is the truth value of a constant in a boolean context.
+type constBool int
+
+const (
+	notConst constBool = iota
+	constTrue
+	constFalse
+	constNull
+)
+
+// getConstBool returns the truth value of expr if it's a constant. The constants that are deferred
+// to be evaluated later are not regarded as constants.
+func getConstBool(ctx sessionctx.Context, expr Expression) constBool {
+	con, ok := expr.(*Constant)
+	if !ok || con.DeferredExpr != nil {
+		return notConst
+	}
+	if con.Value.IsNull() {
+		return constNull
+	}
+	isTrue, err := con.Value.ToBool(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return notConst
+	}
+	if isTrue == 0 {
+		return constFalse
+	}
+	return constTrue
+}
+
+// SimplifyPredicates simplifies the CNF conditions of a filter with the three-valued logic, e.g.
+// `x and false` is false, `x or true` is true and `not(not(x))` is x. Since a filter rejects the
+// rows whose conditions are NULL or false, NULL is treated as false out of the NOT functions.
+// The conditions become a single false constant if the filter rejects all the rows, and the
+// duplicate conditions and the true constants are removed.
+func SimplifyPredicates(ctx sessionctx.Context, conditions []Expression) []Expression {
+	res := make([]Expression, 0, len(conditions))
+	for _, cond := range conditions {
+		for _, item := range SplitCNFItems(simplifyBoolExpr(ctx, cond, true)) {
+			switch getConstBool(ctx, item) {
+			case constTrue:
+				continue
+			case constFalse, constNull:
+				return []Expression{Zero.Clone()}
+			}
+			res = append(res, item)
+		}
+	}
+	return RemoveDupExprs(ctx, res)
+}
+
+// simplifyBoolExpr simplifies the logic functions in an expression evaluated as a boolean value.
+// inFilter indicates whether NULL acts the same as false for the expression.
+func simplifyBoolExpr(ctx sessionctx.Context, expr Expression, inFilter bool) Expression {
+	f, ok := expr.(*ScalarFunction)
+	if !ok {
+		return expr
+	}
+	switch f.FuncName.L {
+	case ast.LogicAnd:
+		return simplifyLogicOp(ctx, f, FlattenCNFConditions(f), inFilter, constFalse, constTrue)
+	case ast.LogicOr:
+		return simplifyLogicOp(ctx, f, FlattenDNFConditions(f), inFilter, constTrue, constFalse)
+	case ast.UnaryNot:
+		// NOT(NULL) is NULL while NOT(false) is true, the argument can't treat NULL as false.
+		arg := simplifyBoolExpr(ctx, f.GetArgs()[0], false)
+		if inner, ok := arg.(*ScalarFunction); ok && inner.FuncName.L == ast.UnaryNot {
+			return simplifyBoolExpr(ctx, inner.GetArgs()[0], inFilter)
+		}
+		if arg == f.GetArgs()[0] {
+			return expr
+		}
+		return NewFunctionInternal(ctx, ast.UnaryNot, f.RetType, arg)
+	}
+	return expr
+}
+
+// simplifyLogicOp simplifies the flattened items of an AND or OR function. The absorbing constant
+// decides the result of the function, the identity constant doesn't affect the result.
+func simplifyLogicOp(ctx sessionctx.Context, f *ScalarFunction, items []Expression, inFilter bool, absorbing, identity constBool) Expression {
+	changed := false
+	hasAbsorbing := false
+	hasMutable := false
+	newItems := make([]Expression, 0, len(items))
+	for _, item := range items {
+		newItem := simplifyBoolExpr(ctx, item, inFilter)
+		changed = changed || newItem != item
+		val := getConstBool(ctx, newItem)
+		if val == constNull && inFilter {
+			val = constFalse
+		}
+		switch val {
+		case absorbing:
+			hasAbsorbing = true
+		case identity:
+			changed = true
+			continue
+		}
+		hasMutable = hasMutable || IsMutableEffectsExpr(newItem)
+		newItems = append(newItems, newItem)
+	}
+	// The items with side effects must still be evaluated.
+	if hasAbsorbing && !hasMutable {
+		if absorbing == constTrue {
+			return One.Clone()
+		}
+		return Zero.Clone()
+	}
+	if dedupItems := RemoveDupExprs(ctx, newItems); len(dedupItems) < len(newItems) {
+		newItems, changed = dedupItems, true
+	}
+	if !changed {
+		return f
+	}
+	if len(newItems) == 0 {
+		if identity == constTrue {
+			return One.Clone()
+		}
+		return Zero.Clone()
+	}
+	return composeConditionWithBinaryOp(ctx, newItems, f.FuncName.L)
+}
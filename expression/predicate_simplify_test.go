@@ -0,0 +1,59 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+
+	"github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/util/mock"
+)
+
+func (s *testUtilSuite) TestSimplifyPredicates(c *check.C) {
+	ctx := mock.NewContext()
+	a, b := newColumn(1), newColumn(2)
+	gtA := newFunction(ast.GT, a, One)
+	ltB := newFunction(ast.LT, b, One)
+	setVar := newFunction(ast.SetVar, &Constant{Value: newLonglong(0).Value, RetType: newIntFieldType()}, a)
+	tests := []struct {
+		conds  []Expression
+		result string
+	}{
+		// x and false, NULL and x
+		{[]Expression{gtA, newFunction(ast.LogicAnd, ltB, Zero)}, "[0]"},
+		{[]Expression{gtA, newFunction(ast.LogicAnd, ltB, Null)}, "[0]"},
+		{[]Expression{gtA, Null}, "[0]"},
+		// x or true, x and true, x or false, x or NULL
+		{[]Expression{gtA, newFunction(ast.LogicOr, ltB, One)}, "[gt(Column#1, 1)]"},
+		{[]Expression{newFunction(ast.LogicOr, newFunction(ast.LogicAnd, gtA, One), ltB)}, "[or(gt(Column#1, 1), lt(Column#2, 1))]"},
+		{[]Expression{newFunction(ast.LogicOr, gtA, Zero)}, "[gt(Column#1, 1)]"},
+		{[]Expression{newFunction(ast.LogicOr, gtA, Null)}, "[gt(Column#1, 1)]"},
+		// NOT(x or NULL) is not x, NULL doesn't act as false under NOT.
+		{[]Expression{newFunction(ast.UnaryNot, newFunction(ast.LogicOr, gtA, Null))}, "[not(or(gt(Column#1, 1), <nil>))]"},
+		{[]Expression{newFunction(ast.UnaryNot, newFunction(ast.LogicAnd, gtA, Zero))}, "[]"},
+		{[]Expression{newFunction(ast.UnaryNot, newFunction(ast.UnaryNot, gtA))}, "[gt(Column#1, 1)]"},
+		{[]Expression{newFunction(ast.UnaryNot, newFunction(ast.UnaryNot, newFunction(ast.LogicOr, gtA, Null)))}, "[gt(Column#1, 1)]"},
+		// duplicate conjuncts and disjuncts
+		{[]Expression{gtA, ltB, gtA}, "[gt(Column#1, 1) lt(Column#2, 1)]"},
+		{[]Expression{newFunction(ast.LogicAnd, gtA, gtA), ltB}, "[gt(Column#1, 1) lt(Column#2, 1)]"},
+		{[]Expression{newFunction(ast.LogicOr, gtA, newFunction(ast.LogicOr, ltB, gtA))}, "[or(gt(Column#1, 1), lt(Column#2, 1))]"},
+		// The functions with side effects are kept.
+		{[]Expression{newFunction(ast.LogicOr, setVar, One)}, "[or(setvar(0, Column#1), 1)]"},
+	}
+	for _, t := range tests {
+		res := SimplifyPredicates(ctx, t.conds)
+		c.Assert(fmt.Sprintf("%v", res), check.Equals, t.result, check.Commentf("%v", t.conds))
+	}
+}
@@ -16,6 +16,7 @@ package expression
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/model"
@@ -24,8 +25,10 @@ import (
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 )
 
@@ -54,6 +57,11 @@ func (sf *ScalarFunction) VecEvalString(ctx sessionctx.Context, input *chunk.Chu
 	return sf.Function.vecEvalString(input, result)
 }
 
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	return sf.Function.vecEvalJSON(input, result)
+}
+
 // VecEvalDecimal evaluates this expression in a vectorized manner.
 func (sf *ScalarFunction) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
 	return sf.Function.vecEvalDecimal(input, result)
@@ -236,6 +244,8 @@ func (sf *ScalarFunction) Eval(row chunk.Row) (d types.Datum, err error) {
 		res, isNull, err = sf.EvalReal(sf.GetCtx(), row)
 	case types.ETString:
 		res, isNull, err = sf.EvalString(sf.GetCtx(), row)
+	case types.ETJson:
+		res, isNull, err = sf.EvalJSON(sf.GetCtx(), row)
 	}
 
 	if isNull || err != nil {
@@ -258,9 +268,22 @@ func (sf *ScalarFunction) EvalReal(ctx sessionctx.Context, row chunk.Row) (float
 
 // EvalString implements Expression interface.
 func (sf *ScalarFunction) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	// The JSON result is used as its text in the string context.
+	if sf.GetType().EvalType() == types.ETJson {
+		j, isNull, err := sf.Function.evalJSON(row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return j.String(), false, nil
+	}
 	return sf.Function.evalString(row)
 }
 
+// EvalJSON implements Expression interface.
+func (sf *ScalarFunction) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	return sf.Function.evalJSON(row)
+}
+
 // HashCode implements Expression interface.
 func (sf *ScalarFunction) HashCode(sc *stmtctx.StatementContext) []byte {
 	if len(sf.hashcode) > 0 {
@@ -271,6 +294,10 @@ func (sf *ScalarFunction) HashCode(sc *stmtctx.StatementContext) []byte {
 	for _, arg := range sf.GetArgs() {
 		sf.hashcode = append(sf.hashcode, arg.HashCode(sc)...)
 	}
+	// The same function is different if the arguments are compared in different collations.
+	if collation := sf.Function.getCollation(); !collate.IsBinCollation(collation) {
+		sf.hashcode = codec.EncodeCompactBytes(sf.hashcode, hack.Slice(strings.ToLower(collation)))
+	}
 	return sf.hashcode
 }
 
@@ -14,6 +14,7 @@
 package expression
 
 import (
+	"sort"
 	"strconv"
 
 	"github.com/pingcap/errors"
@@ -118,6 +119,19 @@ func extractColumns(result []*Column, expr Expression, filter func(*Column) bool
 	return result
 }
 
+// ExtractCorColumns extracts correlated column from given expression.
+func ExtractCorColumns(expr Expression) (cols []*CorrelatedColumn) {
+	switch v := expr.(type) {
+	case *CorrelatedColumn:
+		return []*CorrelatedColumn{v}
+	case *ScalarFunction:
+		for _, arg := range v.GetArgs() {
+			cols = append(cols, ExtractCorColumns(arg)...)
+		}
+	}
+	return
+}
+
 // ExtractColumnSet extracts the different values of `UniqueId` for columns in expressions.
 func ExtractColumnSet(exprs []Expression) *intsets.Sparse {
 	set := &intsets.Sparse{}
@@ -243,6 +257,33 @@ func PushDownNot(ctx sessionctx.Context, expr Expression) Expression {
 	return newExpr
 }
 
+// NormalizeConditions rewrites the CNF conditions into the canonical forms which the range builder and the
+// constant propagation expect. The `not` functions are pushed down to the leaves, e.g. `not(a between 1 and 3)`
+// becomes `a < 1 or a > 3`, and the nested `and`/`or` functions are flattened, so every returned item is not a
+// conjunction and its disjunctions are composed into balanced trees.
+func NormalizeConditions(ctx sessionctx.Context, conditions []Expression) []Expression {
+	ret := make([]Expression, 0, len(conditions))
+	for _, cond := range conditions {
+		for _, item := range SplitCNFItems(PushDownNot(ctx, cond)) {
+			ret = append(ret, flattenLogicOp(ctx, item))
+		}
+	}
+	return ret
+}
+
+// flattenLogicOp flattens the nested `and`/`or` functions in the expression and composes their items again.
+func flattenLogicOp(ctx sessionctx.Context, expr Expression) Expression {
+	f, ok := expr.(*ScalarFunction)
+	if !ok || (f.FuncName.L != ast.LogicAnd && f.FuncName.L != ast.LogicOr) {
+		return expr
+	}
+	items := splitNormalFormItems(f, f.FuncName.L)
+	for i, item := range items {
+		items[i] = flattenLogicOp(ctx, item)
+	}
+	return composeConditionWithBinaryOp(ctx, items, f.FuncName.L)
+}
+
 // Contains tests if `exprs` contains `e`.
 func Contains(exprs []Expression, e Expression) bool {
 	for _, expr := range exprs {
@@ -472,6 +513,19 @@ func IsMutableEffectsExpr(expr Expression) bool {
 	return false
 }
 
+// ContainVirtualColumn checks if the expressions contain a virtual column, which can't be pushed
+// down to the storage because it's not stored.
+func ContainVirtualColumn(exprs []Expression) bool {
+	for _, expr := range exprs {
+		for _, col := range ExtractColumns(expr) {
+			if col.VirtualExpr != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // RemoveDupExprs removes identical exprs. Not that if expr contains functions which
 // are mutable or have side effects, we cannot remove it even if it has duplicates.
 func RemoveDupExprs(ctx sessionctx.Context, exprs []Expression) []Expression {
@@ -510,3 +564,37 @@ func GetUint64FromConstant(expr Expression) (uint64, bool, bool) {
 	}
 	return 0, false, false
 }
+
+// EvalCost estimates the cost to evaluate the expression on a row. The columns and constants are
+// free, a function costs its factor in evalCostFactors or 1 if it's not listed there, plus the cost
+// of its arguments. IN costs a comparison for each of its candidates.
+func EvalCost(expr Expression) float64 {
+	sf, ok := expr.(*ScalarFunction)
+	if !ok {
+		return 0
+	}
+	args := sf.GetArgs()
+	cost, ok := evalCostFactors[sf.FuncName.L]
+	if !ok {
+		cost = 1
+	}
+	if sf.FuncName.L == ast.In {
+		cost = float64(len(args) - 1)
+	}
+	for _, arg := range args {
+		cost += EvalCost(arg)
+	}
+	return cost
+}
+
+// SortByEvalCost sorts the conjuncts so the cheap ones are evaluated first, the ones with the same
+// cost keep their order. The cheap conditions may filter out the row before the expensive ones.
+func SortByEvalCost(conds []Expression) {
+	costs := make(map[Expression]float64, len(conds))
+	for _, cond := range conds {
+		costs[cond] = EvalCost(cond)
+	}
+	sort.SliceStable(conds, func(i, j int) bool {
+		return costs[conds[i]] < costs[conds[j]]
+	})
+}
@@ -14,6 +14,7 @@
 package expression
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -129,6 +130,75 @@ func (s *testUtilSuite) TestPushDownNot(c *check.C) {
 	c.Assert(notFunc.Equal(ctx, notFuncCopy), check.IsTrue)
 }
 
+func (s *testUtilSuite) TestNormalizeConditions(c *check.C) {
+	ctx := mock.NewContext()
+	col := &Column{Index: 1, RetType: types.NewFieldType(mysql.TypeLonglong)}
+	two := newLonglong(2)
+	three := newLonglong(3)
+	// !(a>=1&&a<=3) && !(a!=2||a=3)
+	between := newFunction(ast.LogicAnd, newFunction(ast.GE, col, One), newFunction(ast.LE, col, three))
+	notBetween := newFunction(ast.UnaryNot, between)
+	notOr := newFunction(ast.UnaryNot, newFunction(ast.LogicOr, newFunction(ast.NE, col, two), newFunction(ast.EQ, col, three)))
+	ret := NormalizeConditions(ctx, []Expression{newFunction(ast.LogicAnd, notBetween, notOr)})
+	// [a<1||a>3, a=2, a!=3]
+	c.Assert(ret, check.HasLen, 3)
+	c.Assert(ret[0].Equal(ctx, newFunction(ast.LogicOr, newFunction(ast.LT, col, One), newFunction(ast.GT, col, three))), check.IsTrue)
+	c.Assert(ret[1].Equal(ctx, newFunction(ast.EQ, col, two)), check.IsTrue)
+	c.Assert(ret[2].Equal(ctx, newFunction(ast.NE, col, three)), check.IsTrue)
+
+	// ((a=1||a=2)||a=3)||(a>3&&(a<1&&a!=2)) => (a=1||a=2)||(a=3||(a>3&&(a<1&&a!=2))) with balanced trees.
+	eq1, eq2, eq3 := newFunction(ast.EQ, col, One), newFunction(ast.EQ, col, two), newFunction(ast.EQ, col, three)
+	gt, lt, ne := newFunction(ast.GT, col, three), newFunction(ast.LT, col, One), newFunction(ast.NE, col, two)
+	nested := newFunction(ast.LogicOr,
+		newFunction(ast.LogicOr, newFunction(ast.LogicOr, eq1, eq2), eq3),
+		newFunction(ast.LogicAnd, gt, newFunction(ast.LogicAnd, lt, ne)))
+	ret = NormalizeConditions(ctx, []Expression{nested})
+	c.Assert(ret, check.HasLen, 1)
+	expected := newFunction(ast.LogicOr,
+		newFunction(ast.LogicOr, eq1, eq2),
+		newFunction(ast.LogicOr, eq3, newFunction(ast.LogicAnd, gt, newFunction(ast.LogicAnd, lt, ne))))
+	c.Assert(ret[0].Equal(ctx, expected), check.IsTrue)
+	c.Assert(FlattenDNFConditions(ret[0].(*ScalarFunction)), check.HasLen, 4)
+}
+
+func (s *testUtilSuite) TestToCNFAndDNF(c *check.C) {
+	ctx := mock.NewContext()
+	a, b, cc := newColumn(0), newColumn(1), newColumn(2)
+	eqA1, eqA2 := newFunction(ast.EQ, a, One), newFunction(ast.EQ, a, newLonglong(2))
+	eqB3, eqC4 := newFunction(ast.EQ, b, newLonglong(3)), newFunction(ast.EQ, cc, newLonglong(4))
+
+	// (a=1 or a=2) and b=3 => [a=1 and b=3, a=2 and b=3]
+	items, ok := ToDNF(ctx, newFunction(ast.LogicAnd, newFunction(ast.LogicOr, eqA1, eqA2), eqB3), 8)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(fmt.Sprint(items), check.Equals, "[and(eq(Column#0, 1), eq(Column#1, 3)) and(eq(Column#0, 2), eq(Column#1, 3))]")
+	// (a=1 or b=3) and (a=1 or c=4) => [a=1, a=1 and c=4, b=3 and a=1, b=3 and c=4]
+	items, ok = ToDNF(ctx, newFunction(ast.LogicAnd, newFunction(ast.LogicOr, eqA1, eqB3), newFunction(ast.LogicOr, eqA1, eqC4)), 8)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(fmt.Sprint(items), check.Equals, "[eq(Column#0, 1) and(eq(Column#0, 1), eq(Column#2, 4)) and(eq(Column#1, 3), eq(Column#0, 1)) and(eq(Column#1, 3), eq(Column#2, 4))]")
+	// (a=1 and b=3) or c=4 => [a=1 or c=4, b=3 or c=4]
+	items, ok = ToCNF(ctx, newFunction(ast.LogicOr, newFunction(ast.LogicAnd, eqA1, eqB3), eqC4), 8)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(fmt.Sprint(items), check.Equals, "[or(eq(Column#0, 1), eq(Column#2, 4)) or(eq(Column#1, 3), eq(Column#2, 4))]")
+	// A non-logic expression is its own normal form.
+	items, ok = ToCNF(ctx, eqA1, 1)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(items, check.HasLen, 1)
+
+	// (a=1 or a=2) and (b=3 or c=4) and (a=2 or c=4) expands to 8 DNF items, which exceeds the limit.
+	expr := newFunction(ast.LogicAnd,
+		newFunction(ast.LogicAnd, newFunction(ast.LogicOr, eqA1, eqA2), newFunction(ast.LogicOr, eqB3, eqC4)),
+		newFunction(ast.LogicOr, eqA2, eqC4))
+	_, ok = ToDNF(ctx, expr, 7)
+	c.Assert(ok, check.IsFalse)
+	items, ok = ToDNF(ctx, expr, 8)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(items, check.HasLen, 8)
+	// Its CNF form only has 3 items.
+	items, ok = ToCNF(ctx, expr, 3)
+	c.Assert(ok, check.IsTrue)
+	c.Assert(items, check.HasLen, 3)
+}
+
 func (s *testUtilSuite) TestFilter(c *check.C) {
 	conditions := []Expression{
 		newFunction(ast.EQ, newColumn(0), newColumn(1)),
@@ -193,6 +263,27 @@ func (s *testUtilSuite) TestHashGroupKey(c *check.C) {
 	}
 }
 
+func (s *testUtilSuite) TestEvalCost(c *check.C) {
+	col := newColumn(1)
+	strCol := newColumnWithType(2, newStringFieldType())
+	eq := newFunction(ast.EQ, col, One)
+	in := newFunction(ast.In, col, One, newLonglong(2), newLonglong(3))
+	md5Eq := newFunction(ast.EQ, newFunction(ast.MD5, strCol), strCol)
+	or := newFunction(ast.LogicOr, eq, md5Eq)
+	c.Assert(EvalCost(col), check.Equals, float64(0))
+	c.Assert(EvalCost(One), check.Equals, float64(0))
+	c.Assert(EvalCost(eq), check.Equals, float64(1))
+	c.Assert(EvalCost(in), check.Equals, float64(3))
+	c.Assert(EvalCost(md5Eq), check.Equals, float64(11))
+	c.Assert(EvalCost(or), check.Equals, float64(13))
+
+	// The conditions with the same cost keep their order.
+	eq2 := newFunction(ast.GT, col, One)
+	conds := []Expression{or, md5Eq, eq, in, eq2}
+	SortByEvalCost(conds)
+	c.Assert(conds, check.DeepEquals, []Expression{eq, eq2, in, md5Eq, or})
+}
+
 func isLogicOrFunction(e Expression) bool {
 	if f, ok := e.(*ScalarFunction); ok {
 		return f.FuncName.L == ast.LogicOr
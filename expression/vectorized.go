@@ -73,6 +73,21 @@ func genVecFromConstExpr(ctx sessionctx.Context, expr Expression, targetType typ
 				result.AppendString(v)
 			}
 		}
+	case types.ETJson:
+		result.ReserveJSON(n)
+		v, isNull, err := expr.EvalJSON(ctx, chunk.Row{})
+		if err != nil {
+			return err
+		}
+		if isNull {
+			for i := 0; i < n; i++ {
+				result.AppendNull()
+			}
+		} else {
+			for i := 0; i < n; i++ {
+				result.AppendJSON(v)
+			}
+		}
 	default:
 		return errors.Errorf("unsupported Constant type for vectorized evaluation")
 	}
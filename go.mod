@@ -14,6 +14,7 @@ require (
 	github.com/google/uuid v1.1.1
 	github.com/gorilla/mux v1.6.2
 	github.com/ngaut/pools v0.0.0-20180318154953-b7bc8c42aac7
+	github.com/opentracing/opentracing-go v1.0.2
 	github.com/pingcap-incubator/tinykv v0.0.0-20200514052412-e01d729bd45c
 	github.com/pingcap/check v0.0.0-20200212061837-5e12011dc712
 	github.com/pingcap/errors v0.11.5-0.20190809092503-95897b64e011
@@ -27,10 +27,12 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/store/tikv/oracle"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/txninfo"
 )
 
 const (
@@ -65,6 +67,7 @@ const (
 	tableOptimizerTrace                     = "OPTIMIZER_TRACE"
 	tableTableSpaces                        = "TABLESPACES"
 	tableCollationCharacterSetApplicability = "COLLATION_CHARACTER_SET_APPLICABILITY"
+	tableTiDBTrx                            = "TIDB_TRX"
 )
 
 var tableIDMap = map[string]int64{
@@ -99,6 +102,7 @@ var tableIDMap = map[string]int64{
 	tableOptimizerTrace:                     autoid.InformationSchemaDBID + 30,
 	tableTableSpaces:                        autoid.InformationSchemaDBID + 31,
 	tableCollationCharacterSetApplicability: autoid.InformationSchemaDBID + 32,
+	tableTiDBTrx:                            autoid.InformationSchemaDBID + 33,
 }
 
 type columnInfo struct {
@@ -233,6 +237,7 @@ var statisticsCols = []columnInfo{
 	{"INDEX_TYPE", mysql.TypeVarchar, 16, 0, nil, nil},
 	{"COMMENT", mysql.TypeVarchar, 16, 0, nil, nil},
 	{"INDEX_COMMENT", mysql.TypeVarchar, 1024, 0, nil, nil},
+	{"IS_VISIBLE", mysql.TypeVarchar, 3, 0, nil, nil},
 }
 
 var profilingCols = []columnInfo{
@@ -533,6 +538,18 @@ var tableCollationCharacterSetApplicabilityCols = []columnInfo{
 	{"CHARACTER_SET_NAME", mysql.TypeVarchar, 32, mysql.NotNullFlag, nil, nil},
 }
 
+var tableTiDBTrxCols = []columnInfo{
+	{"ID", mysql.TypeLonglong, 21, mysql.PriKeyFlag | mysql.NotNullFlag | mysql.UnsignedFlag, nil, nil},
+	{"START_TIME", mysql.TypeVarchar, 64, 0, nil, nil},
+	{"DURATION", mysql.TypeDouble, 22, 0, nil, nil},
+	{"CURRENT_SQL", mysql.TypeBlob, types.UnspecifiedLength, 0, nil, nil},
+	{"LEN", mysql.TypeLonglong, 21, 0, nil, nil},
+	{"SIZE", mysql.TypeLonglong, 21, 0, nil, nil},
+	{"SESSION_ID", mysql.TypeLonglong, 21, mysql.UnsignedFlag, nil, nil},
+	{"USER", mysql.TypeVarchar, 16, 0, nil, nil},
+	{"DB", mysql.TypeVarchar, 64, 0, nil, nil},
+}
+
 func dataForCharacterSets() (records [][]types.Datum) {
 
 	charsets := charset.GetSupportedCharsets()
@@ -600,6 +617,39 @@ func dataForSessionVar(ctx sessionctx.Context) (records [][]types.Datum, err err
 	return
 }
 
+func dataForOptimizerTrace(ctx sessionctx.Context) (records [][]types.Datum) {
+	info := ctx.GetSessionVars().LastOptimizerTrace
+	if info == nil {
+		return nil
+	}
+	records = append(records, types.MakeDatums(info.Query, info.Trace, 0, 0))
+	return
+}
+
+func dataForTiDBTrx() (records [][]types.Datum) {
+	now := time.Now()
+	for _, info := range txninfo.GlobalRegistry.Snapshot() {
+		startTime := oracle.GetTimeFromTS(info.StartTS)
+		// The current statement of an idle transaction is NULL.
+		var currentSQL interface{}
+		if info.CurrentSQL != "" {
+			currentSQL = info.CurrentSQL
+		}
+		records = append(records, types.MakeDatums(
+			info.StartTS,
+			startTime.Format("2006-01-02 15:04:05.000"),
+			now.Sub(startTime).Seconds(),
+			currentSQL,
+			info.Len,
+			info.Size,
+			info.ConnectionID,
+			info.Username,
+			info.CurrentDB,
+		))
+	}
+	return records
+}
+
 func dataForUserPrivileges(ctx sessionctx.Context) [][]types.Datum {
 	return [][]types.Datum{}
 }
@@ -1031,6 +1081,7 @@ func dataForStatisticsInTable(schema *model.DBInfo, table *model.TableInfo) [][]
 					"BTREE",       // INDEX_TYPE
 					"",            // COMMENT
 					"",            // INDEX_COMMENT
+					"YES",         // IS_VISIBLE
 				)
 				rows = append(rows, record)
 			}
@@ -1045,12 +1096,20 @@ func dataForStatisticsInTable(schema *model.DBInfo, table *model.TableInfo) [][]
 		if index.Unique {
 			nonUnique = "0"
 		}
+		visible := "YES"
+		if index.Invisible {
+			visible = "NO"
+		}
 		for i, key := range index.Columns {
 			col := nameToCol[key.Name.L]
 			nullable := "YES"
 			if mysql.HasNotNullFlag(col.Flag) {
 				nullable = ""
 			}
+			collation := "A"
+			if key.Desc {
+				collation = "D"
+			}
 			record := types.MakeDatums(
 				catalogVal,    // TABLE_CATALOG
 				schema.Name.O, // TABLE_SCHEMA
@@ -1060,7 +1119,7 @@ func dataForStatisticsInTable(schema *model.DBInfo, table *model.TableInfo) [][]
 				index.Name.O,  // INDEX_NAME
 				i+1,           // SEQ_IN_INDEX
 				key.Name.O,    // COLUMN_NAME
-				"A",           // COLLATION
+				collation,     // COLLATION
 				nil,           // CARDINALITY
 				nil,           // SUB_PART
 				nil,           // PACKED
@@ -1068,6 +1127,7 @@ func dataForStatisticsInTable(schema *model.DBInfo, table *model.TableInfo) [][]
 				"BTREE",       // INDEX_TYPE
 				"",            // COMMENT
 				"",            // INDEX_COMMENT
+				visible,       // IS_VISIBLE
 			)
 			rows = append(rows, record)
 		}
@@ -1262,6 +1322,7 @@ var tableNameToColumns = map[string][]columnInfo{
 	tableOptimizerTrace:                     tableOptimizerTraceCols,
 	tableTableSpaces:                        tableTableSpacesCols,
 	tableCollationCharacterSetApplicability: tableCollationCharacterSetApplicabilityCols,
+	tableTiDBTrx:                            tableTiDBTrxCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocator, meta *model.TableInfo) (table.Table, error) {
@@ -1340,9 +1401,12 @@ func (it *infoschemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 	case tableGlobalVariables:
 	case tableSessionStatus:
 	case tableOptimizerTrace:
+		fullRows = dataForOptimizerTrace(ctx)
 	case tableTableSpaces:
 	case tableCollationCharacterSetApplicability:
 		fullRows = dataForCollationCharacterSetApplicability()
+	case tableTiDBTrx:
+		fullRows = dataForTiDBTrx()
 	}
 	if err != nil {
 		return nil, err
@@ -1398,6 +1462,11 @@ func (it *infoschemaTable) Cols() []*table.Column {
 	return it.cols
 }
 
+// VisibleCols implements table.Table VisibleCols interface.
+func (it *infoschemaTable) VisibleCols() []*table.Column {
+	return it.cols
+}
+
 // WritableCols implements table.Table WritableCols interface.
 func (it *infoschemaTable) WritableCols() []*table.Column {
 	return it.cols
@@ -1520,6 +1589,11 @@ func (vt *VirtualTable) Cols() []*table.Column {
 	return nil
 }
 
+// VisibleCols implements table.Table VisibleCols interface.
+func (vt *VirtualTable) VisibleCols() []*table.Column {
+	return nil
+}
+
 // WritableCols implements table.Table WritableCols interface.
 func (vt *VirtualTable) WritableCols() []*table.Column {
 	return nil
@@ -14,6 +14,8 @@
 package infoschema_test
 
 import (
+	"fmt"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/infoschema"
@@ -55,6 +57,29 @@ func (s *testTableSuite) TestColumnStatistics(c *C) {
 	tk.MustQuery("select * from information_schema.column_statistics").Check(testkit.Rows())
 }
 
+func (s *testTableSuite) TestTiDBTrx(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("create table trx_t (a int)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into trx_t values (1), (2)")
+	txn, err := tk.Se.Txn(true)
+	c.Assert(err, IsNil)
+	startTS := txn.StartTS()
+
+	tk1 := testkit.NewTestKit(c, s.store)
+	tk1.MustQuery(fmt.Sprintf("select session_id, db, current_sql, len > 0, duration >= 0 from information_schema.tidb_trx where id = %d", startTS)).Check(
+		testkit.Rows(fmt.Sprintf("%d test <nil> 1 1", tk.Se.GetSessionVars().ConnectionID)))
+	// The running statement of the querying session is shown while its record set is read.
+	tk1.MustExec("begin")
+	tk1.MustQuery("select current_sql from information_schema.tidb_trx where current_sql is not null").Check(
+		testkit.Rows("select current_sql from information_schema.tidb_trx where current_sql is not null"))
+	tk1.MustExec("rollback")
+
+	tk.MustExec("commit")
+	tk1.MustQuery(fmt.Sprintf("select count(*) from information_schema.tidb_trx where id = %d", startTS)).Check(testkit.Rows("0"))
+}
+
 func (s *testTableSuite) TestReloadDropDatabase(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("create database test_dbs")
@@ -72,3 +97,26 @@ func (s *testTableSuite) TestReloadDropDatabase(c *C) {
 	_, ok := is.TableByID(t2.Meta().ID)
 	c.Assert(ok, IsFalse)
 }
+
+func (s *testTableSuite) TestSchemaMetadataTables(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("create database test_meta")
+	tk.MustExec("use test_meta")
+	tk.MustExec("create table t (id int primary key, name varchar(20), age int, key idx_age(age))")
+
+	tk.MustQuery("select table_schema, table_name, table_type from information_schema.tables where table_schema = 'test_meta'").Check(
+		testkit.Rows("test_meta t BASE TABLE"))
+	tk.MustQuery("select count(*) from information_schema.tables where table_schema = 'test_meta'").Check(testkit.Rows("1"))
+	tk.MustQuery("select column_name, data_type, column_key from information_schema.columns " +
+		"where table_schema = 'test_meta' and table_name = 't' order by ordinal_position").Check(
+		testkit.Rows("id int PRI", "name varchar ", "age int MUL"))
+	tk.MustQuery("select index_name, column_name, non_unique from information_schema.statistics " +
+		"where table_schema = 'test_meta' and table_name = 't' order by index_name").Check(
+		testkit.Rows("PRIMARY id 0", "idx_age age 1"))
+	tk.MustQuery("select constraint_name, column_name from information_schema.key_column_usage " +
+		"where table_schema = 'test_meta' and table_name = 't'").Check(testkit.Rows("PRIMARY id"))
+	tk.MustQuery("select t.table_name, k.column_name from information_schema.tables t " +
+		"join information_schema.key_column_usage k on t.table_schema = k.table_schema and t.table_name = k.table_name " +
+		"where t.table_schema = 'test_meta'").Check(testkit.Rows("t id"))
+	tk.MustExec("drop database test_meta")
+}
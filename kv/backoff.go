@@ -0,0 +1,39 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import "context"
+
+// BackoffReporter is called when a KV request backs off before it's retried.
+// typ is the backoff type, attempts is the number of the backoffs of the type
+// so far, sleepMS is how long it slept and err is the reason of the retry.
+type BackoffReporter func(typ string, attempts int, sleepMS int, err error)
+
+type backoffReporterKeyType struct{}
+
+var backoffReporterKey = backoffReporterKeyType{}
+
+// WithBackoffReporter returns a copy of ctx, the backoffs of the KV requests
+// sent with the returned context are reported to reporter. The reporter may be
+// called concurrently.
+func WithBackoffReporter(ctx context.Context, reporter BackoffReporter) context.Context {
+	return context.WithValue(ctx, backoffReporterKey, reporter)
+}
+
+// ReportBackoff reports a backoff to the BackoffReporter carried by ctx, if any.
+func ReportBackoff(ctx context.Context, typ string, attempts int, sleepMS int, err error) {
+	if reporter, ok := ctx.Value(backoffReporterKey).(BackoffReporter); ok {
+		reporter(typ, attempts, sleepMS, err)
+	}
+}
@@ -45,6 +45,8 @@ var (
 	// ErrWriteConflict is the error when the commit meets an write conflict error.
 	ErrWriteConflict = terror.ClassKV.New(mysql.ErrWriteConflict,
 		mysql.MySQLErrName[mysql.ErrWriteConflict]+" "+TxnRetryableMark)
+	// ErrRetryAfterBackoff is the warning of a KV request which is retried after it backs off.
+	ErrRetryAfterBackoff = terror.ClassKV.New(mysql.ErrUnknown, "KV request is retried after %s backoff #%d of %dms: %v")
 )
 
 func init() {
@@ -60,6 +62,7 @@ func init() {
 		mysql.ErrInvalidTxn:          mysql.ErrInvalidTxn,
 		mysql.ErrEntryTooLarge:       mysql.ErrEntryTooLarge,
 		mysql.ErrNotImplemented:      mysql.ErrNotImplemented,
+		mysql.ErrUnknown:             mysql.ErrUnknown,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassKV] = kvMySQLErrCodes
 }
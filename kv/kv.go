@@ -47,6 +47,9 @@ const (
 	SnapshotTS
 	// Set replica read
 	ReplicaRead
+	// CommitTSUpperBoundCheck is a func(commitTS uint64) bool, the transaction fails to commit if
+	// it returns false for the commit ts.
+	CommitTSUpperBoundCheck
 )
 
 // Priority value for transaction priority.
@@ -233,6 +236,11 @@ type Request struct {
 	SyncLog bool
 	// ReplicaRead is used for reading data from replicas, only follower is supported at this time.
 	ReplicaRead ReplicaReadType
+	// Paging indicates whether the coprocessor results are returned in pages. The size of the first
+	// page is MinPagingSize, and it grows for the following pages until it reaches MaxPagingSize.
+	Paging        bool
+	MinPagingSize uint64
+	MaxPagingSize uint64
 }
 
 // ResultSubset represents a result subset from a single storage unit.
@@ -297,6 +305,37 @@ type Storage interface {
 	ShowStatus(ctx context.Context, key string) (interface{}, error)
 }
 
+// Ingester is implemented by the storages which can write key-value pairs in bulk without a transaction.
+type Ingester interface {
+	// Ingest writes the pairs, which must be sorted by key and have no duplicated keys, and returns the commit version.
+	// The pairs are not written atomically, the caller must make sure nobody else accesses the keys.
+	Ingest(ctx context.Context, keys, values [][]byte) (uint64, error)
+}
+
+// RegionInfo is the info of a region of the storage.
+type RegionInfo struct {
+	ID       uint64
+	StartKey Key
+	EndKey   Key
+	// LeaderStoreID is the ID of the store where the leader of the region is.
+	LeaderStoreID uint64
+	// StoreIDs are the IDs of the stores where the peers of the region are.
+	StoreIDs []uint64
+}
+
+// RegionStore is implemented by the storages whose data is divided into regions, the regions can be
+// scattered over the stores of the storage to break up the hotspots.
+type RegionStore interface {
+	// GetRegions returns the regions overlapping the key range [startKey, endKey) in the order of the keys.
+	// An empty endKey means the range is unbounded.
+	GetRegions(ctx context.Context, startKey, endKey Key) ([]*RegionInfo, error)
+	// ScatterRegions scatters the regions overlapping the key range [startKey, endKey) over the stores,
+	// and returns the IDs of the regions. The regions are scattered asynchronously.
+	ScatterRegions(ctx context.Context, startKey, endKey Key) ([]uint64, error)
+	// WaitScatterRegionFinish waits until the region is scattered or ctx is done.
+	WaitScatterRegionFinish(ctx context.Context, regionID uint64) error
+}
+
 // FnKeyCmp is the function for iterator the keys
 type FnKeyCmp func(key Key) bool
 
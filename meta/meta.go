@@ -50,6 +50,7 @@ var (
 //		TID:1 -> int64
 //		TID:2 -> int64
 //	}
+//	CachedTableLock:1 -> cached table lock []byte
 //
 
 var (
@@ -62,6 +63,7 @@ var (
 	mTableIDPrefix    = "TID"
 	mBootstrapKey     = []byte("BootstrapKey")
 	mSchemaDiffPrefix = "Diff"
+	mCachedTableLock  = "CachedTableLock"
 )
 
 var (
@@ -808,6 +810,57 @@ func (m *Meta) SetSchemaDiff(diff *model.SchemaDiff) error {
 	return errors.Trace(err)
 }
 
+// CachedTableLockType is the type of the lock of a cached table.
+type CachedTableLockType int
+
+// CachedTableLockType values.
+const (
+	// CachedTableLockNone means the table is not locked.
+	CachedTableLockNone CachedTableLockType = iota
+	// CachedTableLockRead means the table can't be written until the lease, the TiDB instances
+	// can serve the reads from their caches until then.
+	CachedTableLockRead
+	// CachedTableLockIntend means a writer is waiting for the read lock to expire, the read
+	// lock can't be renewed.
+	CachedTableLockIntend
+	// CachedTableLockWrite means the table is being written until the lease, it can't be cached.
+	CachedTableLockWrite
+)
+
+// CachedTableLock is the lock of a cached table. The lease is a timestamp.
+type CachedTableLock struct {
+	Type  CachedTableLockType `json:"type"`
+	Lease uint64              `json:"lease"`
+}
+
+func (m *Meta) cachedTableLockKey(tableID int64) []byte {
+	return []byte(fmt.Sprintf("%s:%d", mCachedTableLock, tableID))
+}
+
+// GetCachedTableLock gets the lock of a cached table.
+func (m *Meta) GetCachedTableLock(tableID int64) (*CachedTableLock, error) {
+	data, err := m.txn.Get(m.cachedTableLockKey(tableID))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	lock := &CachedTableLock{}
+	if len(data) == 0 {
+		return lock, nil
+	}
+	err = json.Unmarshal(data, lock)
+	return lock, errors.Trace(err)
+}
+
+// SetCachedTableLock sets the lock of a cached table.
+func (m *Meta) SetCachedTableLock(tableID int64, lock *CachedTableLock) error {
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = m.txn.Set(m.cachedTableLockKey(tableID), data)
+	return errors.Trace(err)
+}
+
 func init() {
 	metaMySQLErrCodes := map[terror.ErrCode]uint16{
 		mysql.ErrDBCreateExists: mysql.ErrDBCreateExists,
@@ -297,6 +297,31 @@ func (s *testSuite) TestSnapshot(c *C) {
 	c.Assert(err.Error(), Equals, "[structure:8220]write on snapshot")
 }
 
+func (s *testSuite) TestCachedTableLock(c *C) {
+	defer testleak.AfterTest(c)()
+	store, err := mockstore.NewMockTikvStore()
+	c.Assert(err, IsNil)
+	defer store.Close()
+
+	txn, err := store.Begin()
+	c.Assert(err, IsNil)
+	defer txn.Rollback()
+
+	t := meta.NewMeta(txn)
+	lock, err := t.GetCachedTableLock(1)
+	c.Assert(err, IsNil)
+	c.Assert(lock, DeepEquals, &meta.CachedTableLock{Type: meta.CachedTableLockNone})
+
+	err = t.SetCachedTableLock(1, &meta.CachedTableLock{Type: meta.CachedTableLockRead, Lease: 100})
+	c.Assert(err, IsNil)
+	lock, err = t.GetCachedTableLock(1)
+	c.Assert(err, IsNil)
+	c.Assert(lock, DeepEquals, &meta.CachedTableLock{Type: meta.CachedTableLockRead, Lease: 100})
+	lock, err = t.GetCachedTableLock(2)
+	c.Assert(err, IsNil)
+	c.Assert(lock.Type, Equals, meta.CachedTableLockNone)
+}
+
 func (s *testSuite) TestDDL(c *C) {
 	defer testleak.AfterTest(c)()
 	store, err := mockstore.NewMockTikvStore()
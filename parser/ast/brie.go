@@ -0,0 +1,55 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ast
+
+var _ StmtNode = &BRIEStmt{}
+
+// BRIEKind is the kind of a BRIE (Backup and Restore Integrated Engine) statement.
+type BRIEKind uint8
+
+// BRIE statement kinds.
+const (
+	BRIEKindBackup BRIEKind = iota
+	BRIEKindRestore
+)
+
+// String implements fmt.Stringer interface.
+func (kind BRIEKind) String() string {
+	switch kind {
+	case BRIEKindBackup:
+		return "BACKUP"
+	case BRIEKindRestore:
+		return "RESTORE"
+	default:
+		return ""
+	}
+}
+
+// BRIEStmt is a statement for backup or restore databases.
+// BACKUP DATABASE db1, db2 TO 'path'
+// RESTORE DATABASE db1, db2 FROM 'path'
+type BRIEStmt struct {
+	stmtNode
+
+	Kind    BRIEKind
+	Schemas []string
+	// Storage is the directory the backup files are written to or read from.
+	Storage string
+}
+
+// Accept implements Node Accept interface.
+func (n *BRIEStmt) Accept(v Visitor) (Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
@@ -104,6 +104,8 @@ type IndexPartSpecification struct {
 	Column *ColumnName
 	Length int
 	Expr   ExprNode
+	// Desc is true if the index part is sorted in descending order.
+	Desc bool
 }
 
 // Accept implements Node Accept interface.
@@ -351,6 +353,7 @@ type CreateTableStmt struct {
 	ReferTable  *TableName
 	Cols        []*ColumnDef
 	Constraints []*Constraint
+	Options     []*TableOption
 }
 
 // Accept implements Node Accept interface.
@@ -499,6 +502,27 @@ func (n *DropIndexStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// TableOptionType is the type for TableOption
+type TableOptionType int
+
+// TableOption types.
+const (
+	TableOptionNone TableOptionType = iota
+	TableOptionTTL
+	TableOptionTTLEnable
+)
+
+// TableOption is used for parsing table option from SQL.
+type TableOption struct {
+	Tp TableOptionType
+	// ColumnName, UintValue and StrValue are the column, the interval value and the interval unit
+	// of the TTL option, e.g. TTL = created_at + INTERVAL 1 DAY.
+	ColumnName *ColumnName
+	UintValue  uint64
+	StrValue   string
+	BoolValue  bool
+}
+
 // AlterTableType is the type for AlterTableSpec.
 type AlterTableType int
 
@@ -532,6 +556,9 @@ const (
 	AlterTableImportTablespace
 	AlterTableDiscardTablespace
 	AlterTableIndexInvisible
+	AlterTableRemoveTTL
+	AlterTableCache
+	AlterTableNoCache
 	// TODO: Add more actions
 	AlterTableOrderByColumns
 )
@@ -564,6 +591,7 @@ type AlterTableSpec struct {
 	WithValidation bool
 	Num            uint64
 	Visibility     IndexVisibility
+	Options        []*TableOption
 }
 
 // Accept implements Node Accept interface.
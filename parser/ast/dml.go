@@ -64,10 +64,10 @@ type Join struct {
 	Tp JoinType
 	// On represents join on condition.
 	On *OnCondition
-	// // Using represents join using clause.
-	// Using []*ColumnName
-	// // NaturalJoin represents join is natural join
-	// NaturalJoin bool
+	// Using represents join using clause.
+	Using []*ColumnName
+	// NaturalJoin represents join is natural join
+	NaturalJoin bool
 }
 
 // Accept implements Node Accept interface.
@@ -328,6 +328,8 @@ func (n *ByItem) Accept(v Visitor) (Node, bool) {
 type GroupByClause struct {
 	node
 	Items []*ByItem
+	// Rollup indicates the super-aggregate rows are generated by WITH ROLLUP.
+	Rollup bool
 }
 
 // Accept implements Node Accept interface.
@@ -540,6 +542,7 @@ type InsertStmt struct {
 	dmlNode
 
 	IsReplace bool
+	IgnoreErr bool
 	Table     *TableRefsClause
 	Columns   []*ColumnName
 	Lists     [][]ExprNode
@@ -596,6 +599,63 @@ func (n *InsertStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// ImportIntoStmt is a statement to load files into an empty table in bulk.
+// IMPORT INTO t FROM '/path/to/*.csv'
+type ImportIntoStmt struct {
+	stmtNode
+
+	Table *TableName
+	// Path is a file path or a glob pattern of the CSV files to import.
+	Path string
+}
+
+// Accept implements Node Accept interface.
+func (n *ImportIntoStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*ImportIntoStmt)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	return v.Leave(n)
+}
+
+// BatchDMLStmt is a statement to split a DML statement into batches by the ranges of a shard column,
+// every batch is executed in its own transaction.
+// BATCH ON id LIMIT 1000 DELETE FROM t WHERE ...
+type BatchDMLStmt struct {
+	stmtNode
+
+	ShardColumn *ColumnName
+	// Limit is the approximate number of rows of a batch.
+	Limit   uint64
+	DMLStmt *DeleteStmt
+}
+
+// Accept implements Node Accept interface.
+func (n *BatchDMLStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*BatchDMLStmt)
+	node, ok := n.ShardColumn.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.ShardColumn = node.(*ColumnName)
+	node, ok = n.DMLStmt.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.DMLStmt = node.(*DeleteStmt)
+	return v.Leave(n)
+}
+
 // DeleteStmt is a statement to delete rows from table.
 // See https://dev.mysql.com/doc/refman/5.7/en/delete.html
 type DeleteStmt struct {
@@ -695,6 +755,7 @@ const (
 	ShowProcessList
 	ShowCreateDatabase
 	ShowErrors
+	ShowRegions
 )
 
 // ShowStmt is a statement to provide information about databases, tables, columns and so on.
@@ -704,10 +765,10 @@ type ShowStmt struct {
 
 	Tp          ShowStmtType // Databases/Tables/Columns/....
 	DBName      string
-	Table       *TableName  // Used for showing columns.
+	Table       *TableName  // Used for showing columns and regions.
 	Column      *ColumnName // Used for `desc table column`.
-	IndexName   model.CIStr
-	Flag        int // Some flag parsed from sql, such as FULL.
+	IndexName   model.CIStr // Used for showing the regions of an index.
+	Flag        int         // Some flag parsed from sql, such as FULL.
 	Full        bool
 	IfNotExists bool // Used for `show create database if not exists`
 	Extended    bool // Used for `show extended columns from ...`
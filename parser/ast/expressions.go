@@ -16,6 +16,7 @@ package ast
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/tidb/parser/model"
@@ -30,7 +31,11 @@ var (
 	_ ExprNode = &IsNullExpr{}
 	_ ExprNode = &ParenthesesExpr{}
 	_ ExprNode = &PatternInExpr{}
+	_ ExprNode = &PatternLikeExpr{}
+	_ ExprNode = &PatternRegexpExpr{}
+	_ ExprNode = &PositionExpr{}
 	_ ExprNode = &RowExpr{}
+	_ ExprNode = &SubqueryExpr{}
 	_ ExprNode = &UnaryOperationExpr{}
 	_ ExprNode = &ValuesExpr{}
 	_ ExprNode = &VariableExpr{}
@@ -306,6 +311,95 @@ func (n *PatternInExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// PatternLikeExpr is the expression for like operator, e.g, expr like "%123%"
+type PatternLikeExpr struct {
+	exprNode
+	// Expr is the expression to be checked.
+	Expr ExprNode
+	// Pattern is the like expression.
+	Pattern ExprNode
+	// Not is true, the expression is "not like".
+	Not bool
+	// Escape is the escape character of the pattern, it is the backslash by default.
+	Escape rune
+}
+
+// Format the ExprNode into a Writer.
+func (n *PatternLikeExpr) Format(w io.Writer) {
+	n.Expr.Format(w)
+	if n.Not {
+		fmt.Fprint(w, " NOT LIKE ")
+	} else {
+		fmt.Fprint(w, " LIKE ")
+	}
+	n.Pattern.Format(w)
+	if n.Escape != '\\' {
+		fmt.Fprint(w, " ESCAPE ", strconv.Quote(string(n.Escape)))
+	}
+}
+
+// Accept implements Node Accept interface.
+func (n *PatternLikeExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*PatternLikeExpr)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	node, ok = n.Pattern.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Pattern = node.(ExprNode)
+	return v.Leave(n)
+}
+
+// PatternRegexpExpr is the pattern expression for pattern match, like "expr regexp pattern".
+type PatternRegexpExpr struct {
+	exprNode
+	// Expr is the expression to be checked.
+	Expr ExprNode
+	// Pattern is the expression for pattern.
+	Pattern ExprNode
+	// Not is true, the expression is "not regexp".
+	Not bool
+}
+
+// Format the ExprNode into a Writer.
+func (n *PatternRegexpExpr) Format(w io.Writer) {
+	n.Expr.Format(w)
+	if n.Not {
+		fmt.Fprint(w, " NOT REGEXP ")
+	} else {
+		fmt.Fprint(w, " REGEXP ")
+	}
+	n.Pattern.Format(w)
+}
+
+// Accept implements Node Accept interface.
+func (n *PatternRegexpExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*PatternRegexpExpr)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	node, ok = n.Pattern.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Pattern = node.(ExprNode)
+	return v.Leave(n)
+}
+
 // IsNullExpr is the expression for null check.
 type IsNullExpr struct {
 	exprNode
@@ -371,6 +465,29 @@ func (n *ParenthesesExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// PositionExpr is the expression for the position of a select field in order by and group by,
+// e.g. "2" in "select a, b from t order by 2". The position starts from 1.
+type PositionExpr struct {
+	exprNode
+	// N is the position.
+	N int
+}
+
+// Format the ExprNode into a Writer.
+func (n *PositionExpr) Format(w io.Writer) {
+	fmt.Fprintf(w, "%d", n.N)
+}
+
+// Accept implements Node Accept interface.
+func (n *PositionExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*PositionExpr)
+	return v.Leave(n)
+}
+
 // RowExpr is the expression for row constructor.
 // See https://dev.mysql.com/doc/refman/5.7/en/row-subqueries.html
 type RowExpr struct {
@@ -401,6 +518,36 @@ func (n *RowExpr) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SubqueryExpr represents a scalar subquery.
+// See https://dev.mysql.com/doc/refman/5.7/en/scalar-subqueries.html
+type SubqueryExpr struct {
+	exprNode
+	// Query is the query SelectNode.
+	Query ResultSetNode
+	// Correlated indicates whether the subquery references the columns of the outer query.
+	Correlated bool
+}
+
+// Format the ExprNode into a Writer.
+func (n *SubqueryExpr) Format(w io.Writer) {
+	panic("Not implemented")
+}
+
+// Accept implements Node Accept interface.
+func (n *SubqueryExpr) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SubqueryExpr)
+	node, ok := n.Query.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Query = node.(ResultSetNode)
+	return v.Leave(n)
+}
+
 // UnaryOperationExpr is the expression for unary operator.
 type UnaryOperationExpr struct {
 	exprNode
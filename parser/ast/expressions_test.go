@@ -76,6 +76,7 @@ func (tc *testExpressionsSuite) TestExpresionsVisitorCover(c *C) {
 			{&DefaultExpr{Name: &ColumnName{}}, 0, 0},
 			{&IsNullExpr{Expr: ce}, 1, 1},
 			{&ParenthesesExpr{Expr: ce}, 1, 1},
+			{&PositionExpr{N: 1}, 0, 0},
 			{&RowExpr{Values: []ExprNode{ce, ce}}, 2, 2},
 			{&UnaryOperationExpr{V: ce}, 1, 1},
 			{NewValueExpr(0), 0, 0},
@@ -53,6 +53,8 @@ func (f *flagSetter) Leave(in Node) (Node, bool) {
 		f.patternIn(x)
 	case *RowExpr:
 		f.row(x)
+	case *SubqueryExpr:
+		x.SetFlag(FlagHasSubquery)
 	case *UnaryOperationExpr:
 		x.SetFlag(x.V.GetFlag())
 	case *ValuesExpr:
@@ -27,31 +27,89 @@ var (
 
 // List scalar function names.
 const (
-	IsNull      = "isnull"
-	Length      = "length"
-	Strcmp      = "strcmp"
-	OctetLength = "octet_length"
-	If          = "if"
-	Ifnull      = "ifnull"
-	LogicAnd    = "and"
-	LogicOr     = "or"
-	GE          = "ge"
-	LE          = "le"
-	EQ          = "eq"
-	NE          = "ne"
-	LT          = "lt"
-	GT          = "gt"
-	Plus        = "plus"
-	Minus       = "minus"
-	Div         = "div"
-	Mul         = "mul"
-	UnaryNot    = "not"
-	UnaryMinus  = "unaryminus"
-	In          = "in"
-	RowFunc     = "row"
-	SetVar      = "setvar"
-	GetVar      = "getvar"
-	Values      = "values"
+	IsNull         = "isnull"
+	Length         = "length"
+	Strcmp         = "strcmp"
+	OctetLength    = "octet_length"
+	SubstringIndex = "substring_index"
+	Lpad           = "lpad"
+	Rpad           = "rpad"
+	Instr          = "instr"
+	Replace        = "replace"
+	Repeat         = "repeat"
+	MD5            = "md5"
+	SHA1           = "sha1"
+	SHA            = "sha"
+	SHA2           = "sha2"
+	CRC32          = "crc32"
+	AesEncrypt     = "aes_encrypt"
+	AesDecrypt     = "aes_decrypt"
+	Like           = "like"
+	Regexp         = "regexp"
+	RegexpLike     = "regexp_like"
+	If             = "if"
+	Ifnull         = "ifnull"
+	LogicAnd       = "and"
+	LogicOr        = "or"
+	GE             = "ge"
+	LE             = "le"
+	EQ             = "eq"
+	NE             = "ne"
+	NullEQ         = "nulleq"
+	LT             = "lt"
+	GT             = "gt"
+	Greatest       = "greatest"
+	Least          = "least"
+	Interval       = "interval"
+	Plus           = "plus"
+	Minus          = "minus"
+	Div            = "div"
+	Mul            = "mul"
+	UnaryNot       = "not"
+	UnaryMinus     = "unaryminus"
+	In             = "in"
+	RowFunc        = "row"
+	SetVar         = "setvar"
+	GetVar         = "getvar"
+	Values         = "values"
+	Grouping       = "grouping"
+	UUID           = "uuid"
+	UUIDShort      = "uuid_short"
+	IsUUID         = "is_uuid"
+	UUIDToBin      = "uuid_to_bin"
+	BinToUUID      = "bin_to_uuid"
+
+	// math functions
+	Ln       = "ln"
+	Log      = "log"
+	Log2     = "log2"
+	Log10    = "log10"
+	Pow      = "pow"
+	Power    = "power"
+	Round    = "round"
+	Truncate = "truncate"
+	Conv     = "conv"
+
+	// information functions
+	Database     = "database"
+	Schema       = "schema"
+	Version      = "version"
+	ConnectionID = "connection_id"
+	User         = "user"
+	SessionUser  = "session_user"
+	SystemUser   = "system_user"
+	CurrentUser  = "current_user"
+	CurrentRole  = "current_role"
+
+	// time functions
+	UnixTimestamp = "unix_timestamp"
+
+	// json functions
+	JSONType     = "json_type"
+	JSONExtract  = "json_extract"
+	JSONUnquote  = "json_unquote"
+	JSONSet      = "json_set"
+	JSONContains = "json_contains"
 )
 
 // FuncCallExpr is for function expression.
@@ -105,6 +163,27 @@ const (
 	AggFuncMax = "max"
 	// AggFuncMin is the name of min function.
 	AggFuncMin = "min"
+	// AggFuncGroupConcat is the name of group_concat function.
+	AggFuncGroupConcat = "group_concat"
+	// AggFuncBitOr is the name of bit_or function.
+	AggFuncBitOr = "bit_or"
+	// AggFuncBitXor is the name of bit_xor function.
+	AggFuncBitXor = "bit_xor"
+	// AggFuncBitAnd is the name of bit_and function.
+	AggFuncBitAnd = "bit_and"
+	// AggFuncVarPop is the name of var_pop function.
+	AggFuncVarPop = "var_pop"
+	// AggFuncStddevPop is the name of stddev_pop function.
+	AggFuncStddevPop = "stddev_pop"
+)
+
+const (
+	// WindowFuncRowNumber is the name of row_number function.
+	WindowFuncRowNumber = "row_number"
+	// WindowFuncRank is the name of rank function.
+	WindowFuncRank = "rank"
+	// WindowFuncDenseRank is the name of dense_rank function.
+	WindowFuncDenseRank = "dense_rank"
 )
 
 // AggregateFuncExpr represents aggregate function expression.
@@ -114,6 +193,10 @@ type AggregateFuncExpr struct {
 	F string
 	// Args is the function args.
 	Args []ExprNode
+	// Distinct is true, function hence only aggregate distinct values.
+	// For example, column c1 values are "1", "2", "2",  "sum(c1)" is "5",
+	// but "sum(distinct c1)" is "3".
+	Distinct bool
 }
 
 // Format the ExprNode into a Writer.
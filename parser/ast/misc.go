@@ -67,6 +67,10 @@ type ExplainStmt struct {
 
 	Stmt   StmtNode
 	Format string
+	// HypoIndexes are the hypothetical indexes considered by the planner as if they existed.
+	HypoIndexes []*HypoIndexDef
+	// OptimizerTrace indicates whether to record the decisions of the optimizer.
+	OptimizerTrace bool
 }
 
 // Accept implements Node Accept interface.
@@ -76,6 +80,13 @@ func (n *ExplainStmt) Accept(v Visitor) (Node, bool) {
 		return v.Leave(newNode)
 	}
 	n = newNode.(*ExplainStmt)
+	for i, val := range n.HypoIndexes {
+		node, ok := val.Accept(v)
+		if !ok {
+			return n, false
+		}
+		n.HypoIndexes[i] = node.(*HypoIndexDef)
+	}
 	node, ok := n.Stmt.Accept(v)
 	if !ok {
 		return n, false
@@ -84,6 +95,31 @@ func (n *ExplainStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// HypoIndexDef is the definition of a hypothetical index in the explain statement, like
+// `EXPLAIN WITH HYPO INDEX idx ON t(a) SELECT ...`.
+type HypoIndexDef struct {
+	node
+
+	Name                    string
+	Table                   *TableName
+	IndexPartSpecifications []*IndexPartSpecification
+}
+
+// Accept implements Node Accept interface.
+func (n *HypoIndexDef) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*HypoIndexDef)
+	node, ok := n.Table.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Table = node.(*TableName)
+	return v.Leave(n)
+}
+
 // BeginStmt is a statement to start a new transaction.
 // See https://dev.mysql.com/doc/refman/5.7/en/commit.html
 type BeginStmt struct {
@@ -204,8 +240,33 @@ type AdminStmtType int
 const (
 	AdminShowDDL = iota + 1
 	AdminShowDDLJobs
+	AdminReloadStats
+	AdminReloadPrivileges
+	AdminExport
+	AdminRecommendIndex
+	AdminShowBaselines
+	AdminEvolveBaselines
+	AdminScatterTable
+	AdminShowTableRegions
+	AdminShowTableDistributions
+	AdminShowDDLProgress
+	AdminShowAnalyzeProgress
+	AdminEnableRowChecksum
+	AdminDisableRowChecksum
+	AdminRebuildRowChecksum
+	AdminFlushPlanCache
+	AdminFlushResultCache
 )
 
+// ExportOption is used for parsing admin export statement.
+type ExportOption struct {
+	Schemas []string
+	// Storage is the directory the exported files are written to.
+	Storage string
+	// Format is the format of the data files, "sql" or "csv". Empty means "sql".
+	Format string
+}
+
 // AdminStmt is the struct for Admin statement.
 type AdminStmt struct {
 	stmtNode
@@ -214,6 +275,9 @@ type AdminStmt struct {
 	Tables    []*TableName
 	JobNumber int64
 	Where     ExprNode
+	Export    *ExportOption
+	// Index is the index of the table whose regions are scattered or shown, empty means the whole table.
+	Index string
 }
 
 // Accept implements Node Accept interface.
@@ -0,0 +1,105 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+)
+
+// Normalize normalizes the input SQL text: literals are replaced by '?',
+// lists of literals are folded into '...', keywords and identifiers are
+// lower-cased, and comments and blanks are reduced to a single space.
+// e.g. `SELECT * FROM t WHERE a IN (1, 2, 3) AND b = 'x'` is normalized to
+// `select * from t where a in ( ... ) and b = ?`.
+func Normalize(sql string) string {
+	d := &sqlDigester{lexer: NewScanner("")}
+	return d.normalize(sql)
+}
+
+// DigestHash returns the digest of the normalized SQL text.
+func DigestHash(sql string) string {
+	_, digest := NormalizeDigest(sql)
+	return digest
+}
+
+// NormalizeDigest combines Normalize and DigestHash into one method.
+func NormalizeDigest(sql string) (normalized, digest string) {
+	normalized = Normalize(sql)
+	hash := sha256.Sum256([]byte(normalized))
+	return normalized, hex.EncodeToString(hash[:])
+}
+
+const (
+	// genericSymbol is the normalized text of a literal.
+	genericSymbol = "?"
+	// genericSymbolList is the normalized text of a list of literals.
+	genericSymbolList = "..."
+)
+
+type sqlDigester struct {
+	lexer  *Scanner
+	tokens []string
+}
+
+func (d *sqlDigester) normalize(sql string) string {
+	d.lexer.reset(sql)
+	for {
+		tok, pos, lit := d.lexer.scan()
+		if tok == 0 || tok == invalid || pos.Offset == len(sql) {
+			break
+		}
+		if tok == unicode.ReplacementChar && d.lexer.r.eof() {
+			break
+		}
+		if d.isLit(tok) {
+			d.appendLit()
+			continue
+		}
+		d.tokens = append(d.tokens, strings.ToLower(lit))
+	}
+	d.lexer.reset("")
+
+	var buf bytes.Buffer
+	for i, token := range d.tokens {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(token)
+	}
+	d.tokens = d.tokens[:0]
+	return buf.String()
+}
+
+// appendLit appends a literal, and folds `?, ?` into `...`.
+func (d *sqlDigester) appendLit() {
+	n := len(d.tokens)
+	if n >= 2 && d.tokens[n-1] == "," && (d.tokens[n-2] == genericSymbol || d.tokens[n-2] == genericSymbolList) {
+		d.tokens = d.tokens[:n-1]
+		d.tokens[n-2] = genericSymbolList
+		return
+	}
+	d.tokens = append(d.tokens, genericSymbol)
+}
+
+func (d *sqlDigester) isLit(tok int) bool {
+	switch tok {
+	case intLit, floatLit, decLit, stringLit, hexLit, bitLit:
+		return true
+	}
+	return false
+}
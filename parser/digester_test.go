@@ -0,0 +1,49 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import (
+	. "github.com/pingcap/check"
+)
+
+var _ = Suite(&testSQLDigestSuite{})
+
+type testSQLDigestSuite struct {
+}
+
+func (s *testSQLDigestSuite) TestNormalize(c *C) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"SELECT 1", "select ?"},
+		{"select * from t where a = 1 and b = 'x'", "select * from t where a = ? and b = ?"},
+		{"select * from `T` where a in (1, 2, 3)", "select * from t where a in ( ... )"},
+		{"insert into t values (1, 0x1f, b'01', 1.5)", "insert into t values ( ... )"},
+		{"select a /* comment */ from t   -- comment\n", "select a from t"},
+		{"SELECT a FROM t WHERE a > 10.5e3", "select a from t where a > ?"},
+	}
+	for _, test := range tests {
+		normalized := Normalize(test.input)
+		c.Assert(normalized, Equals, test.expect, Commentf("%s", test.input))
+	}
+}
+
+func (s *testSQLDigestSuite) TestNormalizeDigest(c *C) {
+	normalized, digest := NormalizeDigest("select * from t where a = 1")
+	c.Assert(normalized, Equals, "select * from t where a = ?")
+	c.Assert(digest, Equals, DigestHash("SELECT * FROM t WHERE a = 2"))
+	c.Assert(digest, Not(Equals), DigestHash("select * from t where b = 1"))
+	c.Assert(digest, HasLen, 64)
+}
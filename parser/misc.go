@@ -27,6 +27,7 @@ type CommentCodeVersion int
 const (
 	CommentCodeNoVersion  CommentCodeVersion = iota
 	CommentCodeAutoRandom CommentCodeVersion = 40000
+	CommentCodeTTL        CommentCodeVersion = 40200
 
 	CommentCodeCurrentVersion
 )
@@ -185,6 +186,9 @@ var tokenMap = map[string]int{
 	"AUTO_RANDOM":              autoRandom,
 	"AVG":                      avg,
 	"AVG_ROW_LENGTH":           avgRowLength,
+	"BACKUP":                   backup,
+	"BASELINES":                baselines,
+	"BATCH":                    batch,
 	"BEGIN":                    begin,
 	"BETWEEN":                  between,
 	"BIGINT":                   bigIntType,
@@ -281,6 +285,7 @@ var tokenMap = map[string]int{
 	"DISCARD":                  discard,
 	"DISK":                     disk,
 	"DISTINCT":                 distinct,
+	"DISTRIBUTIONS":            distributions,
 	"DISTINCTROW":              distinct,
 	"DIV":                      div,
 	"DO":                       do,
@@ -314,6 +319,7 @@ var tokenMap = map[string]int{
 	"EXPANSION":                expansion,
 	"EXPIRE":                   expire,
 	"EXPLAIN":                  explain,
+	"EXPORT":                   export,
 	"EXTENDED":                 extended,
 	"EXTRACT":                  extract,
 	"FALSE":                    falseKwd,
@@ -348,6 +354,7 @@ var tokenMap = map[string]int{
 	"HISTORY":                  history,
 	"HOSTS":                    hosts,
 	"HOUR":                     hour,
+	"HYPO":                     hypo,
 	"HOUR_MICROSECOND":         hourMicrosecond,
 	"HOUR_MINUTE":              hourMinute,
 	"HOUR_SECOND":              hourSecond,
@@ -479,6 +486,7 @@ var tokenMap = map[string]int{
 	"ONLY":                     only,
 	"OPTIMISTIC":               optimistic,
 	"OPTIMIZE":                 optimize,
+	"OPTIMIZER":                optimizer,
 	"OPTION":                   option,
 	"OPTIONALLY":               optionally,
 	"OR":                       or,
@@ -495,6 +503,7 @@ var tokenMap = map[string]int{
 	"PESSIMISTIC":              pessimistic,
 	"PER_TABLE":                per_table,
 	"PER_DB":                   per_db,
+	"PLAN_CACHE":               planCache,
 	"PLUGINS":                  plugins,
 	"POSITION":                 position,
 	"PRECEDING":                preceding,
@@ -507,6 +516,7 @@ var tokenMap = map[string]int{
 	"PROCESSLIST":              processlist,
 	"PROFILE":                  profile,
 	"PROFILES":                 profiles,
+	"PROGRESS":                 progress,
 	"PUMP":                     pump,
 	"QB_NAME":                  hintQBName,
 	"QUARTER":                  quarter,
@@ -524,6 +534,7 @@ var tokenMap = map[string]int{
 	"READ_FROM_STORAGE":        hintReadFromStorage,
 	"REAL":                     realType,
 	"RECENT":                   recent,
+	"RECOMMEND":                recommend,
 	"REDUNDANT":                redundant,
 	"REFERENCES":               references,
 	"REGEXP":                   regexpKwd,
@@ -538,9 +549,11 @@ var tokenMap = map[string]int{
 	"REPEATABLE":               repeatable,
 	"REPLACE":                  replace,
 	"RESPECT":                  respect,
+	"RESULT_CACHE":             resultCache,
 	"REPLICA":                  replica,
 	"REPLICATION":              replication,
 	"REQUIRE":                  require,
+	"RESTORE":                  restore,
 	"RESTRICT":                 restrict,
 	"REVERSE":                  reverse,
 	"REVOKE":                   revoke,
@@ -548,6 +561,7 @@ var tokenMap = map[string]int{
 	"RLIKE":                    rlike,
 	"ROLE":                     role,
 	"ROLLBACK":                 rollback,
+	"ROLLUP":                   rollup,
 	"ROUTINE":                  routine,
 	"ROW":                      row,
 	"ROW_COUNT":                rowCount,
@@ -557,6 +571,7 @@ var tokenMap = map[string]int{
 	"SWAP_JOIN_INPUTS":         hintSJI,
 	"SCHEMA":                   database,
 	"SCHEMAS":                  databases,
+	"SCATTER":                  scatter,
 	"SECOND":                   second,
 	"SECONDARY_ENGINE":         secondaryEngine,
 	"SECONDARY_LOAD":           secondaryLoad,
@@ -677,6 +692,8 @@ var tokenMap = map[string]int{
 	"TRIM":                     trim,
 	"TRUE":                     trueKwd,
 	"TRUNCATE":                 truncate,
+	"TTL":                      ttl,
+	"TTL_ENABLE":               ttlEnable,
 	"TYPE":                     tp,
 	"UNBOUNDED":                unbounded,
 	"UNCOMMITTED":              uncommitted,
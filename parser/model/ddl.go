@@ -63,6 +63,11 @@ const (
 	ActionUpdateTiFlashReplicaStatus    ActionType = 31
 	ActionAddPrimaryKey                 ActionType = 32
 	ActionDropPrimaryKey                ActionType = 33
+	ActionAlterIndexVisibility          ActionType = 34
+	ActionAlterTTLInfo                  ActionType = 35
+	ActionAlterTTLRemove                ActionType = 36
+	ActionAlterCacheTable               ActionType = 37
+	ActionAlterNoCacheTable             ActionType = 38
 )
 
 const (
@@ -105,6 +110,11 @@ var actionMap = map[ActionType]string{
 	ActionUpdateTiFlashReplicaStatus:    "update tiflash replica status",
 	ActionAddPrimaryKey:                 AddPrimaryKeyStr,
 	ActionDropPrimaryKey:                "drop primary key",
+	ActionAlterIndexVisibility:          "alter index visibility",
+	ActionAlterTTLInfo:                  "alter table ttl",
+	ActionAlterTTLRemove:                "alter table no_ttl",
+	ActionAlterCacheTable:               "alter table cache",
+	ActionAlterNoCacheTable:             "alter table nocache",
 }
 
 // String return current ddl action in string
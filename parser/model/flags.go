@@ -44,4 +44,6 @@ const (
 	FlagInUnionStmt = 1 << 9
 	// FlagInLoadDataStmt indicates if this is a LOAD DATA statement.
 	FlagInLoadDataStmt = 1 << 10
+	// FlagVerifyRowChecksum indicates if the checksums stored in the rows should be verified when they're read.
+	FlagVerifyRowChecksum = 1 << 11
 )
@@ -90,6 +90,12 @@ type ColumnInfo struct {
 	Comment            string      `json:"comment"`
 	// A hidden column is used internally(expression index) and are not accessible by users.
 	Hidden bool `json:"hidden"`
+	// GeneratedExprString is the expression of a virtual generated column, whose value is
+	// evaluated from the other columns of the row and is never stored in the row.
+	GeneratedExprString string `json:"generated_expr_string"`
+	// DefaultIsExpr is true if DefaultValue is the string of an expression, which is
+	// evaluated whenever the default value is used.
+	DefaultIsExpr bool `json:"default_is_expr"`
 	// Version means the version of the column info.
 	// Version = 0: For OriginDefaultValue and DefaultValue of timestamp column will stores the default time in system time zone.
 	//              That is a bug if multiple TiDB servers in different system time zone.
@@ -104,6 +110,11 @@ func (c *ColumnInfo) Clone() *ColumnInfo {
 	return &nc
 }
 
+// IsGenerated checks whether the column is a virtual generated column.
+func (c *ColumnInfo) IsGenerated() bool {
+	return len(c.GeneratedExprString) != 0
+}
+
 // SetDefaultValue sets the default value.
 func (c *ColumnInfo) SetDefaultValue(value interface{}) error {
 	c.DefaultValue = value
@@ -224,6 +235,69 @@ type TableInfo struct {
 
 	// TiFlashReplica means the TiFlash replica info.
 	TiFlashReplica *TiFlashReplicaInfo `json:"tiflash_replica"`
+
+	// TTLInfo is the TTL config of the table, it's nil if the table has no TTL.
+	TTLInfo *TTLInfo `json:"ttl_info"`
+
+	// TableCacheStatusType indicates whether the table is cached in memory.
+	TableCacheStatusType TableCacheStatusType `json:"cache_table_status"`
+}
+
+// TableCacheStatusType is the cache status of a table.
+type TableCacheStatusType int
+
+// TableCacheStatusType values.
+const (
+	TableCacheStatusDisable TableCacheStatusType = iota
+	TableCacheStatusEnable
+)
+
+// String implements fmt.Stringer interface.
+func (t TableCacheStatusType) String() string {
+	switch t {
+	case TableCacheStatusDisable:
+		return "disable"
+	case TableCacheStatusEnable:
+		return "enable"
+	default:
+		return ""
+	}
+}
+
+// TTLInfo is the TTL config of a table. The rows whose TTL column value plus the interval
+// is earlier than now are expired. The TTL column stores the Unix timestamp in seconds.
+type TTLInfo struct {
+	ColumnName    CIStr  `json:"column"`
+	IntervalValue uint64 `json:"interval_value"`
+	// IntervalUnit is one of SECOND, MINUTE, HOUR, DAY and WEEK.
+	IntervalUnit string `json:"interval_unit"`
+	Enable       bool   `json:"enable"`
+}
+
+// Clone clones TTLInfo.
+func (t *TTLInfo) Clone() *TTLInfo {
+	cloned := *t
+	return &cloned
+}
+
+// Interval returns the TTL interval.
+func (t *TTLInfo) Interval() (time.Duration, error) {
+	var unit time.Duration
+	switch strings.ToUpper(t.IntervalUnit) {
+	case "SECOND":
+		unit = time.Second
+	case "MINUTE":
+		unit = time.Minute
+	case "HOUR":
+		unit = time.Hour
+	case "DAY":
+		unit = 24 * time.Hour
+	case "WEEK":
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, errors.Errorf("unknown TTL interval unit %s", t.IntervalUnit)
+	}
+	return time.Duration(t.IntervalValue) * unit, nil
 }
 
 // TableLockInfo provides meta data describing a table lock.
@@ -351,6 +425,10 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	if t.TTLInfo != nil {
+		nt.TTLInfo = t.TTLInfo.Clone()
+	}
+
 	return &nt
 }
 
@@ -468,6 +546,8 @@ type IndexColumn struct {
 	// for indexing;
 	// UnspecifedLength if not using prefix indexing
 	Length int `json:"length"`
+	// Desc is true if the column is sorted in descending order in the index.
+	Desc bool `json:"desc"`
 }
 
 // Clone clones IndexColumn.
@@ -505,15 +585,16 @@ const (
 // It corresponds to the statement `CREATE INDEX Name ON Table (Column);`
 // See https://dev.mysql.com/doc/refman/5.7/en/create-index.html
 type IndexInfo struct {
-	ID      int64          `json:"id"`
-	Name    CIStr          `json:"idx_name"`   // Index name.
-	Table   CIStr          `json:"tbl_name"`   // Table name.
-	Columns []*IndexColumn `json:"idx_cols"`   // Index columns.
-	Unique  bool           `json:"is_unique"`  // Whether the index is unique.
-	Primary bool           `json:"is_primary"` // Whether the index is primary key.
-	State   SchemaState    `json:"state"`
-	Comment string         `json:"comment"`    // Comment
-	Tp      IndexType      `json:"index_type"` // Index type: Btree, Hash or Rtree
+	ID        int64          `json:"id"`
+	Name      CIStr          `json:"idx_name"`   // Index name.
+	Table     CIStr          `json:"tbl_name"`   // Table name.
+	Columns   []*IndexColumn `json:"idx_cols"`   // Index columns.
+	Unique    bool           `json:"is_unique"`  // Whether the index is unique.
+	Primary   bool           `json:"is_primary"` // Whether the index is primary key.
+	State     SchemaState    `json:"state"`
+	Comment   string         `json:"comment"`      // Comment
+	Tp        IndexType      `json:"index_type"`   // Index type: Btree, Hash or Rtree
+	Invisible bool           `json:"is_invisible"` // Whether the index is ignored by the optimizer.
 }
 
 // Clone clones IndexInfo.
@@ -536,6 +617,22 @@ func (index *IndexInfo) HasPrefixIndex() bool {
 	return false
 }
 
+// DescColumns returns whether each column of this index is sorted in descending order.
+// It returns nil if all the columns are in ascending order.
+func (index *IndexInfo) DescColumns() []bool {
+	var descs []bool
+	for i, ic := range index.Columns {
+		if !ic.Desc {
+			continue
+		}
+		if descs == nil {
+			descs = make([]bool, len(index.Columns))
+		}
+		descs[i] = true
+	}
+	return descs
+}
+
 // FKInfo provides meta data describing a foreign key constraint.
 type FKInfo struct {
 	ID       int64       `json:"id"`
@@ -688,6 +785,12 @@ func collationToProto(c string) int32 {
 	if v == mysql.BinaryDefaultCollationID {
 		return int32(mysql.BinaryDefaultCollationID)
 	}
+	// The case insensitive collations are kept for the coprocessor to compare
+	// the strings in them.
+	switch lc := strings.ToLower(c); lc {
+	case "utf8_general_ci", "utf8mb4_general_ci", "utf8_unicode_ci", "utf8mb4_unicode_ci":
+		return int32(mysql.CollationNames[lc])
+	}
 	// We only support binary and utf8_bin collation.
 	// Setting other collations to utf8_bin for old data compatibility.
 	// For the data created when we didn't enforce utf8_bin collation in create table.
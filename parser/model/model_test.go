@@ -128,6 +128,22 @@ func (*testModelSuite) TestModelBasic(c *C) {
 	c.Assert(no, Equals, false)
 }
 
+func (*testModelSuite) TestTTLInfo(c *C) {
+	ttlInfo := &TTLInfo{ColumnName: NewCIStr("created_at"), IntervalValue: 2, IntervalUnit: "DAY", Enable: true}
+	interval, err := ttlInfo.Interval()
+	c.Assert(err, IsNil)
+	c.Assert(interval, Equals, 48*time.Hour)
+
+	tblInfo := &TableInfo{TTLInfo: ttlInfo}
+	cloned := tblInfo.Clone()
+	cloned.TTLInfo.Enable = false
+	c.Assert(tblInfo.TTLInfo.Enable, IsTrue)
+
+	ttlInfo.IntervalUnit = "MONTH"
+	_, err = ttlInfo.Interval()
+	c.Assert(err, NotNil)
+}
+
 func (*testModelSuite) TestJobStartTime(c *C) {
 	job := &Job{
 		ID:         123,
@@ -884,6 +884,7 @@ const (
 	ErrErrorLast                                                    = 1863
 	ErrMaxExecTimeExceeded                                          = 1907
 	ErrInvalidFieldSize                                             = 3013
+	ErrInvalidArgumentForLogarithm                                  = 3020
 	ErrIncorrectType                                                = 3064
 	ErrInvalidJSONData                                              = 3069
 	ErrGeneratedColumnFunctionIsNotAllowed                          = 3102
@@ -904,6 +905,7 @@ const (
 	ErrUserAlreadyExists                                            = 3163
 	ErrInvalidJSONPathArrayCell                                     = 3165
 	ErrInvalidEncryptionOption                                      = 3184
+	ErrPKIndexCantBeInvisible                                       = 3522
 	ErrRoleNotGranted                                               = 3530
 	ErrLockAcquireFailAndNoWaitSet                                  = 3572
 	ErrWindowNoSuchWindow                                           = 3579
@@ -927,6 +929,7 @@ const (
 	ErrWindowNoGroupOrderUnused                                     = 3597
 	ErrWindowExplainJson                                            = 3598
 	ErrWindowFunctionIgnoresFrame                                   = 3599
+	ErrFieldInGroupingNotGroupBy                                    = 3600
 	ErrDataTruncatedFunctionalIndex                                 = 3751
 	ErrDataOutOfRangeFunctionalIndex                                = 3752
 	ErrFunctionalIndexOnJsonOrGeometryFunction                      = 3753
@@ -939,6 +942,9 @@ const (
 	ErrSpatialFunctionalIndex                                       = 3760
 	ErrWrongKeyColumnFunctionalIndex                                = 3761
 	ErrFunctionalIndexOnField                                       = 3762
+	ErrDefValGeneratedFunctionIsNotAllowed                          = 3771
+	ErrDefValGeneratedVariables                                     = 3774
+	ErrDefValGeneratedRowValue                                      = 3776
 	ErrFKIncompatibleColumns                                        = 3780
 	ErrFunctionalIndexRowValueIsNotAllowed                          = 3800
 	ErrDependentByFunctionalIndex                                   = 3837
@@ -981,6 +987,12 @@ const (
 	ErrPrivilegeCheckFail                  = 8121
 	ErrInvalidWildCard                     = 8122
 	ErrMixOfGroupFuncAndFieldsIncompatible = 8123
+	ErrBatchDMLUnsupported                 = 8124
+	ErrBatchDMLJobFailed                   = 8125
+	ErrUnsupportedColumnInTTLConfig        = 8126
+	ErrTTLColumnCannotDrop                 = 8127
+	ErrSetTTLOptionForNonTTLTable          = 8128
+	ErrOptOnCacheTable                     = 8129
 	ErrUnsupportedReloadPlugin             = 8018
 	ErrUnsupportedReloadPluginVar          = 8019
 	ErrTableLocked                         = 8020
@@ -888,6 +888,7 @@ var MySQLErrName = map[uint16]string{
 	ErrDependentByGeneratedColumn:                            "Column '%s' has a generated column dependency.",
 	ErrGeneratedColumnRefAutoInc:                             "Generated column '%s' cannot refer to auto-increment column.",
 	ErrInvalidFieldSize:                                      "Invalid size for column '%s'.",
+	ErrInvalidArgumentForLogarithm:                           "Invalid argument for logarithm",
 	ErrIncorrectType:                                         "Incorrect type for argument %s in function %s.",
 	ErrInvalidJSONData:                                       "Invalid JSON data provided to function %s: %s",
 	ErrInvalidJSONText:                                       "Invalid JSON text: %-.192s",
@@ -921,6 +922,8 @@ var MySQLErrName = map[uint16]string{
 	ErrWindowNoGroupOrderUnused:                              "ASC or DESC with GROUP BY isn't allowed with window functions; put ASC or DESC in ORDER BY",
 	ErrWindowExplainJson:                                     "To get information about window functions use EXPLAIN FORMAT=JSON",
 	ErrWindowFunctionIgnoresFrame:                            "Window function '%s' ignores the frame clause of window '%s' and aggregates over the whole partition",
+	ErrFieldInGroupingNotGroupBy:                             "Argument #%d of GROUPING function is not in GROUP BY",
+	ErrPKIndexCantBeInvisible:                                "A primary key index cannot be invisible",
 	ErrRoleNotGranted:                                        "%s is is not granted to %s",
 	ErrMaxExecTimeExceeded:                                   "Query execution was interrupted, max_execution_time exceeded.",
 	ErrLockAcquireFailAndNoWaitSet:                           "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.",
@@ -936,6 +939,9 @@ var MySQLErrName = map[uint16]string{
 	ErrSpatialFunctionalIndex:                                "Spatial functional index is not supported",
 	ErrWrongKeyColumnFunctionalIndex:                         "The used storage engine cannot index the expression '%s'",
 	ErrFunctionalIndexOnField:                                "Functional index on a column is not supported. Consider using a regular index instead",
+	ErrDefValGeneratedFunctionIsNotAllowed:                   "Default value expression of column '%s' contains a disallowed function.",
+	ErrDefValGeneratedVariables:                              "Default value expression of column '%s' cannot refer user or system variables.",
+	ErrDefValGeneratedRowValue:                               "Default value expression of column '%s' cannot refer to a row value.",
 	ErrFKIncompatibleColumns:                                 "Referencing column '%s' in foreign key constraint '%s' are incompatible",
 	ErrFunctionalIndexRowValueIsNotAllowed:                   "Expression of functional index '%s' cannot refer to a row value",
 	ErrDependentByFunctionalIndex:                            "Column '%s' has a functional index dependency and cannot be dropped or renamed",
@@ -1045,6 +1051,12 @@ var MySQLErrName = map[uint16]string{
 	ErrPrivilegeCheckFail:                  "privilege check fail", // this error message should begin lowercased to be compatible with the test
 	ErrInvalidWildCard:                     "Wildcard fields without any table name appears in wrong place",
 	ErrMixOfGroupFuncAndFieldsIncompatible: "In aggregated query without GROUP BY, expression #%d of SELECT list contains nonaggregated column '%s'; this is incompatible with sql_mode=only_full_group_by",
+	ErrBatchDMLUnsupported:                 "Can't run batch DML, %s",
+	ErrBatchDMLJobFailed:                   "Batch DML job %d of %d on %s failed, %d jobs affecting %d rows have finished, run the statement again to resume: %s",
+	ErrUnsupportedColumnInTTLConfig:        "Field '%-.192s' is of a not supported type for TTL config, expect an integer column storing the Unix timestamp in seconds",
+	ErrTTLColumnCannotDrop:                 "Cannot drop column '%-.192s': needed in TTL config",
+	ErrSetTTLOptionForNonTTLTable:          "Cannot set %s on a table without TTL config",
+	ErrOptOnCacheTable:                     "'%s' is unsupported on cache tables.",
 
 	// TiKV/PD errors.
 	ErrPDServerTimeout:    "PD server timeout",
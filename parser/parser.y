@@ -27,6 +27,7 @@ package parser
 
 import (
 	"strings"
+	"unicode/utf8"
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/ast"
@@ -276,6 +277,9 @@ import (
 	autoRandom	"AUTO_RANDOM"
 	avgRowLength	"AVG_ROW_LENGTH"
 	avg		"AVG"
+	backup		"BACKUP"
+	baselines	"BASELINES"
+	batch		"BATCH"
 	begin		"BEGIN"
 	binlog		"BINLOG"
 	bitType		"BIT"
@@ -319,6 +323,7 @@ import (
 	disable		"DISABLE"
 	discard		"DISCARD"
 	disk		"DISK"
+	distributions	"DISTRIBUTIONS"
 	do		"DO"
 	duplicate	"DUPLICATE"
 	dynamic		"DYNAMIC"
@@ -337,6 +342,7 @@ import (
 	execute		"EXECUTE"
 	expansion	"EXPANSION"
 	expire		"EXPIRE"
+	export		"EXPORT"
 	extended	"EXTENDED"
 	faultsSym	"FAULTS"
 	fields		"FIELDS"
@@ -352,6 +358,7 @@ import (
 	history		"HISTORY"
 	hosts		"HOSTS"
 	hour		"HOUR"
+	hypo		"HYPO"
 	identified	"IDENTIFIED"
 	importKwd	"IMPORT"
 	insertMethod 	"INSERT_METHOD"
@@ -406,12 +413,14 @@ import (
 	nulls		"NULLS"
 	offset		"OFFSET"
 	only		"ONLY"
+	optimizer	"OPTIMIZER"
 	pageSym		"PAGE"
 	password	"PASSWORD"
 	partial		"PARTIAL"
 	partitioning	"PARTITIONING"
 	partitions	"PARTITIONS"
 	pipesAsOr
+	planCache	"PLAN_CACHE"
 	plugins		"PLUGINS"
 	preceding	"PRECEDING"
 	prepare		"PREPARE"
@@ -420,6 +429,7 @@ import (
 	processlist	"PROCESSLIST"
 	profile		"PROFILE"
 	profiles	"PROFILES"
+	progress	"PROGRESS"
 	per_table	"PER_TABLE"
 	per_db		"PER_DB"
 	quarter		"QUARTER"
@@ -427,6 +437,7 @@ import (
 	queries		"QUERIES"
 	quick		"QUICK"
 	rebuild 	"REBUILD"
+	recommend	"RECOMMEND"
 	recover 	"RECOVER"
 	redundant	"REDUNDANT"
 	reload		"RELOAD"
@@ -435,15 +446,19 @@ import (
 	repair		"REPAIR"
 	repeatable	"REPEATABLE"
 	respect		"RESPECT"
+	resultCache	"RESULT_CACHE"
+	restore		"RESTORE"
 	replica		"REPLICA"
 	replication	"REPLICATION"
 	reverse		"REVERSE"
 	role		"ROLE"
 	rollback	"ROLLBACK"
+	rollup		"ROLLUP"
 	routine		"ROUTINE"
 	rowCount	"ROW_COUNT"
 	rowFormat	"ROW_FORMAT"
 	rtree		"RTREE"
+	scatter		"SCATTER"
 	second		"SECOND"
 	secondaryEngine	"SECONDARY_ENGINE"
 	secondaryLoad	"SECONDARY_LOAD"
@@ -504,6 +519,8 @@ import (
 	transaction	"TRANSACTION"
 	triggers	"TRIGGERS"
 	truncate	"TRUNCATE"
+	ttl		"TTL"
+	ttlEnable	"TTL_ENABLE"
 	tp             	"TYPE"
 	unbounded	"UNBOUNDED"
 	uncommitted	"UNCOMMITTED"
@@ -696,6 +713,7 @@ import (
 	SimpleExpr			"simple expression"
 	SimpleIdent			"Simple Identifier expression"
 	SumExpr				"aggregate functions"
+	SubSelect			"sub-select"
 	FunctionCallGeneric		"Function call with Identifier"
 	FunctionCallKeyword		"Function call with keyword as function name"
 	FunctionCallNonKeyword		"Function call with nonkeyword as function name"
@@ -715,6 +733,8 @@ import (
 	AlterTableStmt			"Alter table statement"
 	AnalyzeTableStmt		"Analyze table statement"
 	BeginTransactionStmt		"BEGIN TRANSACTION statement"
+	BRIEStmt			"BACKUP or RESTORE statement"
+	BatchDMLStmt			"BATCH ON ... LIMIT ... DML statement"
 
 	CommitStmt			"COMMIT statement"
 	CreateTableStmt			"CREATE TABLE statement"
@@ -727,6 +747,7 @@ import (
 	EmptyStmt			"empty statement"
 	ExplainStmt			"EXPLAIN statement"
 	ExplainableStmt			"explainable statement"
+	ImportIntoStmt			"IMPORT INTO statement"
 	InsertIntoStmt			"INSERT INTO statement"
 	SelectStmt			"SELECT statement"
 	ReplaceIntoStmt			"REPLACE INTO statement"
@@ -738,6 +759,7 @@ import (
 	UseStmt				"USE statement"
 
 %type   <item>
+	AdminIndexOpt			"Optional index of admin table statement"
 	AlterTableSpec			"Alter table specification"
 	AlterTableSpecList		"Alter table specification list"
 	AlterTableSpecListOpt		"Alter table specification list optional"
@@ -769,12 +791,15 @@ import (
 	DatabaseOptionList		"CREATE Database specification list"
 	DatabaseOptionListOpt		"CREATE Database specification list opt"
 	DBName				"Database Name"
+	DBNameList			"Database Name list"
 	DistinctOpt			"Explicit distinct option"
 	DefaultFalseDistinctOpt		"Distinct option which defaults to false"
 	DefaultTrueDistinctOpt		"Distinct option which defaults to true"
 	EqOpt				"= or empty"
 	EscapedTableRef 		"escaped table reference"
 	ExplainFormatType		"explain format type"
+	ExplainWithOpt			"Optional WITH clause of explain"
+	ExportFormatOpt			"export format option"
 	ExpressionList			"expression list"
 	ExpressionListOpt		"expression list opt"
 	FuncDatetimePrecListOpt	        "Function datetime precision list opt"
@@ -787,6 +812,7 @@ import (
 	FuncDatetimePrec		"Function datetime precision"
 	GlobalScope			"The scope of variable"
 	GroupByClause			"GROUP BY clause"
+	WithRollupClause		"WITH ROLLUP clause"
 	HavingClause			"HAVING clause"
 	IfExists			"If Exists"
 	IfNotExists			"If Not Exists"
@@ -808,6 +834,8 @@ import (
 	IndexPartSpecification		"Index column name or expression"
 	IndexPartSpecificationList	"List of index column name or expression"
 	IndexPartSpecificationListOpt   "Optional list of index column name or expression"
+	HypoIndexDef			"Hypothetical index definition"
+	ExplainWithItemList		"List of explain WITH items"
 	InsertValues			"Rest part of INSERT/REPLACE INTO statement"
 	JoinTable 			"join table"
 	JoinType			"join type"
@@ -825,6 +853,7 @@ import (
 	OrderByOptional			"Optional ORDER BY clause optional"
 	ByList				"BY list"
 	QuickOptional			"QUICK or empty"
+	IgnoreOptional			"IGNORE or empty"
 	QueryBlockOpt			"Query block identifier optional"
 	PriorityOpt			"Statement priority option"
 	OptGConcatSeparator		"optional GROUP_CONCAT SEPARATOR"
@@ -856,6 +885,10 @@ import (
 	TableElement			"table definition element"
 	TableElementList		"table definition element list"
 	TableElementListOpt		"table definition element list optional"
+	TableOption			"create table option"
+	TableOptionList			"create table option list"
+	TableOptionListOpt		"create table option list opt"
+	TTLIntervalUnit			"time unit of the TTL interval"
 	TableFactor 			"table factor"
 	TableName			"Table name"
 	TableNameOptWild		"Table name with optional wildcard"
@@ -880,6 +913,8 @@ import (
 	BetweenOrNotOp		"Between predicate"
 	IsOrNotOp		"Is predicate"
 	InOrNotOp		"In predicate"
+	LikeOrNotOp		"Like predicate"
+	RegexpOrNotOp		"Regexp predicate"
 
 	NumericType		"Numeric types"
 	IntegerType		"Integer Types types"
@@ -943,6 +978,7 @@ import (
 	ShowIndexKwd		"Show index/indexs/key keyword"
 	DistinctKwd		"DISTINCT/DISTINCTROW keyword"
 	FromOrIn		"From or In"
+	RegexpSym		"REGEXP or RLIKE"
 	OptTable		"Optional table keyword"
 	OptInteger		"Optional Integer keyword"
 	CharsetKw		"charset or charater set"
@@ -1001,7 +1037,7 @@ import (
 %left 	andand and
 %left 	between
 %precedence	lowerThanEq
-%left 	eq ge le neq neqSynonym '>' '<' is like in
+%left 	eq ge le neq neqSynonym '>' '<' is like in regexpKwd rlike
 %left 	'|'
 %left 	'&'
 %left 	rsh lsh
@@ -1053,7 +1089,26 @@ LocationLabelList:
 
 
 AlterTableSpec:
-	"ADD" ColumnKeywordOpt IfNotExists ColumnDef
+	TableOption
+	{
+		$$ = &ast.AlterTableSpec{
+			Tp:      ast.AlterTableOption,
+			Options: []*ast.TableOption{$1.(*ast.TableOption)},
+		}
+	}
+|	"REMOVE" "TTL"
+	{
+		$$ = &ast.AlterTableSpec{Tp: ast.AlterTableRemoveTTL}
+	}
+|	"CACHE"
+	{
+		$$ = &ast.AlterTableSpec{Tp: ast.AlterTableCache}
+	}
+|	"NOCACHE"
+	{
+		$$ = &ast.AlterTableSpec{Tp: ast.AlterTableNoCache}
+	}
+|	"ADD" ColumnKeywordOpt IfNotExists ColumnDef
 	{
 		$$ = &ast.AlterTableSpec{
 			IfNotExists: 	$3.(bool),
@@ -1178,7 +1233,7 @@ AlterTableSpec:
 	}
 |	"ALTER" ColumnKeywordOpt ColumnName "SET" "DEFAULT" '(' Expression ')'
 	{
-		option := &ast.ColumnOption{Expr: $7}
+		option := &ast.ColumnOption{Expr: &ast.ParenthesesExpr{Expr: $7}}
 		colDef := &ast.ColumnDef{
 			Name: 	 $3.(*ast.ColumnName),
 			Options: []*ast.ColumnOption{option},
@@ -1553,6 +1608,11 @@ ColumnOption:
 	{
 		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionDefaultValue, Expr: $2}
 	}
+|	"DEFAULT" '(' Expression ')'
+	{
+		// An expression default, which is evaluated whenever the default value is used.
+		$$ = &ast.ColumnOption{Tp: ast.ColumnOptionDefaultValue, Expr: &ast.ParenthesesExpr{Expr: $3}}
+	}
 |	"SERIAL" "DEFAULT" "VALUE"
 	{
 		$$ = []*ast.ColumnOption{{Tp: ast.ColumnOptionNotNull}, {Tp: ast.ColumnOptionAutoIncrement}, {Tp: ast.ColumnOptionUniqKey}}
@@ -1895,12 +1955,11 @@ IndexPartSpecificationList:
 IndexPartSpecification:
 	ColumnName OptFieldLen Order
 	{
-		// Order is parsed but just ignored as MySQL did.
-		$$ = &ast.IndexPartSpecification{Column: $1.(*ast.ColumnName), Length: $2.(int)}
+		$$ = &ast.IndexPartSpecification{Column: $1.(*ast.ColumnName), Length: $2.(int), Desc: $3.(bool)}
 	}
 |	'(' Expression ')' Order
 	{
-		$$ = &ast.IndexPartSpecification{Expr: $2}
+		$$ = &ast.IndexPartSpecification{Expr: $2, Desc: $4.(bool)}
 	}
 
 IndexKeyTypeOpt:
@@ -1947,6 +2006,16 @@ DBName:
 		$$ = $1
 	}
 
+DBNameList:
+	DBName
+	{
+		$$ = []string{$1.(string)}
+	}
+|	DBNameList ',' DBName
+	{
+		$$ = append($1.([]string), $3.(string))
+	}
+
 DatabaseOption:
 	DefaultKwdOpt CharsetKw EqOpt CharsetName
 	{
@@ -1994,12 +2063,13 @@ DatabaseOptionList:
  *******************************************************************/
 
 CreateTableStmt:
-	"CREATE" OptTemporary "TABLE" IfNotExists TableName TableElementListOpt AsOpt
+	"CREATE" OptTemporary "TABLE" IfNotExists TableName TableElementListOpt TableOptionListOpt AsOpt
 	{
 		stmt := $6.(*ast.CreateTableStmt)
 		stmt.Table = $5.(*ast.TableName)
 		stmt.IfNotExists = $4.(bool)
 		stmt.IsTemporary = $2.(bool)
+		stmt.Options = $7.([]*ast.TableOption)
 		$$ = stmt
 	}
 |	"CREATE" OptTemporary "TABLE" IfNotExists TableName LikeTableWithOrWithoutParen
@@ -2012,6 +2082,68 @@ CreateTableStmt:
 		}
 	}
 
+TableOptionListOpt:
+	%prec lowerThanCreateTableSelect
+	{
+		$$ = []*ast.TableOption{}
+	}
+|	TableOptionList %prec lowerThanComma
+
+TableOptionList:
+	TableOption
+	{
+		$$ = []*ast.TableOption{$1.(*ast.TableOption)}
+	}
+|	TableOptionList TableOption
+	{
+		$$ = append($1.([]*ast.TableOption), $2.(*ast.TableOption))
+	}
+|	TableOptionList ',' TableOption
+	{
+		$$ = append($1.([]*ast.TableOption), $3.(*ast.TableOption))
+	}
+
+TableOption:
+	"TTL" EqOpt ColumnName '+' "INTERVAL" LengthNum TTLIntervalUnit
+	{
+		$$ = &ast.TableOption{
+			Tp:         ast.TableOptionTTL,
+			ColumnName: $3.(*ast.ColumnName),
+			UintValue:  $6.(uint64),
+			StrValue:   $7.(string),
+		}
+	}
+|	"TTL_ENABLE" EqOpt stringLit
+	{
+		if !strings.EqualFold($3, "ON") && !strings.EqualFold($3, "OFF") {
+			yylex.AppendError(yylex.Errorf("TTL_ENABLE should be 'ON' or 'OFF'"))
+			return 1
+		}
+		$$ = &ast.TableOption{Tp: ast.TableOptionTTLEnable, BoolValue: strings.EqualFold($3, "ON")}
+	}
+
+TTLIntervalUnit:
+	"SECOND"
+	{
+		$$ = "SECOND"
+	}
+|	"MINUTE"
+	{
+		$$ = "MINUTE"
+	}
+|	"HOUR"
+	{
+		$$ = "HOUR"
+	}
+|	"DAY"
+	{
+		$$ = "DAY"
+	}
+|	"WEEK"
+	{
+		$$ = "WEEK"
+	}
+
 DefaultKwdOpt:
 	%prec lowerThanCharsetKwd
 	{}
@@ -2063,6 +2195,23 @@ DeleteFromStmt:
 		$$ = x
 	}
 
+/*******************************************************************
+ *
+ *  Batch DML Statement
+ *
+ *  BATCH ON id LIMIT 1000 DELETE FROM t WHERE ...
+ *
+ *******************************************************************/
+BatchDMLStmt:
+	"BATCH" "ON" ColumnName "LIMIT" LengthNum DeleteFromStmt
+	{
+		$$ = &ast.BatchDMLStmt{
+			ShardColumn: $3.(*ast.ColumnName),
+			Limit:       $5.(uint64),
+			DMLStmt:     $6.(*ast.DeleteStmt),
+		}
+	}
+
 DatabaseSym:
 "DATABASE"
 
@@ -2129,25 +2278,66 @@ ExplainSym:
 "EXPLAIN" | "DESCRIBE" | "DESC"
 
 ExplainStmt:
-	ExplainSym ExplainableStmt
+	ExplainSym ExplainWithOpt ExplainableStmt
 	{
-		$$ = &ast.ExplainStmt{
-			Stmt:	$2,
-			Format: "row",
-		}
+		x := $2.(*ast.ExplainStmt)
+		x.Stmt = $3
+		x.Format = "row"
+		$$ = x
 	}
-|	ExplainSym "FORMAT" "=" stringLit ExplainableStmt
+|	ExplainSym "FORMAT" "=" stringLit ExplainWithOpt ExplainableStmt
 	{
-		$$ = &ast.ExplainStmt{
-			Stmt:	$5,
-			Format: $4,
-		}
+		x := $5.(*ast.ExplainStmt)
+		x.Stmt = $6
+		x.Format = $4
+		$$ = x
+	}
+|	ExplainSym "FORMAT" "=" ExplainFormatType ExplainWithOpt ExplainableStmt
+	{
+		x := $5.(*ast.ExplainStmt)
+		x.Stmt = $6
+		x.Format = $4.(string)
+		$$ = x
 	}
-|	ExplainSym "FORMAT" "=" ExplainFormatType ExplainableStmt
+
+ExplainWithOpt:
+	{
+		$$ = &ast.ExplainStmt{}
+	}
+|	"WITH" ExplainWithItemList
+	{
+		$$ = $2
+	}
+
+ExplainWithItemList:
+	HypoIndexDef
 	{
-		$$ = &ast.ExplainStmt{
-			Stmt:	$5,
-			Format: $4.(string),
+		$$ = &ast.ExplainStmt{HypoIndexes: []*ast.HypoIndexDef{$1.(*ast.HypoIndexDef)}}
+	}
+|	"OPTIMIZER" "TRACE"
+	{
+		$$ = &ast.ExplainStmt{OptimizerTrace: true}
+	}
+|	ExplainWithItemList ',' HypoIndexDef
+	{
+		x := $1.(*ast.ExplainStmt)
+		x.HypoIndexes = append(x.HypoIndexes, $3.(*ast.HypoIndexDef))
+		$$ = x
+	}
+|	ExplainWithItemList ',' "OPTIMIZER" "TRACE"
+	{
+		x := $1.(*ast.ExplainStmt)
+		x.OptimizerTrace = true
+		$$ = x
+	}
+
+HypoIndexDef:
+	"HYPO" "INDEX" Identifier "ON" TableName '(' IndexPartSpecificationList ')'
+	{
+		$$ = &ast.HypoIndexDef{
+			Name:  $3,
+			Table: $5.(*ast.TableName),
+			IndexPartSpecifications: $7.([]*ast.IndexPartSpecification),
 		}
 	}
 
@@ -2331,6 +2521,30 @@ InOrNotOp:
 		$$ = false
 	}
 
+LikeOrNotOp:
+	"LIKE"
+	{
+		$$ = true
+	}
+|	"NOT" "LIKE"
+	{
+		$$ = false
+	}
+
+RegexpOrNotOp:
+	RegexpSym
+	{
+		$$ = true
+	}
+|	"NOT" RegexpSym
+	{
+		$$ = false
+	}
+
+RegexpSym:
+	"REGEXP"
+|	"RLIKE"
+
 AnyOrAll:
 	"ANY"
 	{
@@ -2359,6 +2573,27 @@ PredicateExpr:
 			Not:	!$2.(bool),
 		}
 	}
+|	BitExpr LikeOrNotOp SimpleExpr LikeEscapeOpt
+	{
+		escape := $4.(string)
+		if utf8.RuneCountInString(escape) > 1 {
+			yylex.AppendError(ErrWrongArguments.GenWithStackByArgs("ESCAPE"))
+			return 1
+		} else if len(escape) == 0 {
+			escape = "\\"
+		}
+		r, _ := utf8.DecodeRuneInString(escape)
+		$$ = &ast.PatternLikeExpr{
+			Expr:		$1,
+			Pattern:	$3,
+			Not:		!$2.(bool),
+			Escape:		r,
+		}
+	}
+|	BitExpr RegexpOrNotOp SimpleExpr
+	{
+		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
+	}
 |	BitExpr
 
 LikeEscapeOpt:
@@ -2453,9 +2688,18 @@ FieldList:
 	}
 
 GroupByClause:
-	"GROUP" "BY" ByList
+	"GROUP" "BY" ByList WithRollupClause
+	{
+		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem), Rollup: $4.(bool)}
+	}
+
+WithRollupClause:
 	{
-		$$ = &ast.GroupByClause{Items: $3.([]*ast.ByItem)}
+		$$ = false
+	}
+|	"WITH" "ROLLUP"
+	{
+		$$ = true
 	}
 
 HavingClause:
@@ -2639,23 +2883,23 @@ identifier | UnReservedKeyword | NotKeywordToken | TiDBKeyword
 UnReservedKeyword:
  "ACTION" | "ADVISE" |"ASCII" | "AUTO_INCREMENT" | "AFTER" | "ALWAYS" | "AVG" | "BEGIN" | "BIT" | "BOOL" | "BOOLEAN" | "BTREE" | "BYTE" | "CAPTURE" |"CLEANUP" | "CHARSET"
 | "COLUMNS" | "COMMIT" | "COMPACT" | "COMPRESSED" | "CONSISTENT" | "CURRENT" | "DATA" | "DATE" %prec lowerThanStringLitToken| "DATETIME" | "DAY" | "DEALLOCATE" | "DO" | "DUPLICATE"
-| "DYNAMIC" | "ENCRYPTION" | "END" | "ENFORCED" | "ENGINE" | "ENGINES" | "ENUM" | "ERRORS" | "ESCAPE" | "EVOLVE" | "EXECUTE" | "EXTENDED" | "FIELDS" | "FIRST" | "FIXED" | "FLUSH" | "FOLLOWING" | "FORMAT" | "FULL" |"GLOBAL"
+| "DISTRIBUTIONS" | "DYNAMIC" | "ENCRYPTION" | "END" | "ENFORCED" | "ENGINE" | "ENGINES" | "ENUM" | "ERRORS" | "ESCAPE" | "EVOLVE" | "EXECUTE" | "EXPORT" | "EXTENDED" | "FIELDS" | "FIRST" | "FIXED" | "FLUSH" | "FOLLOWING" | "FORMAT" | "FULL" |"GLOBAL"
 | "HASH" | "HOUR" | "INSERT_METHOD" | "LESS" | "LOCAL" | "LAST" | "NAMES" | "OFFSET" | "PASSWORD" %prec lowerThanEq | "PREPARE" | "QUICK" | "REBUILD" | "REDUNDANT" | "REORGANIZE"
-| "ROLE" |"ROLLBACK" | "SESSION" | "SIGNED" | "SHUTDOWN" | "SNAPSHOT" | "START" | "STATUS" | "OPEN"| "SUBPARTITIONS" | "SUBPARTITION" | "TABLES" | "TABLESPACE" | "TEXT" | "THAN" | "TIME" %prec lowerThanStringLitToken
-| "TIMESTAMP" %prec lowerThanStringLitToken | "TRACE" | "TRANSACTION" | "TRUNCATE" | "UNBOUNDED" | "UNKNOWN" | "VALUE" | "WARNINGS" | "YEAR" | "MODE"  | "WEEK"  | "ANY" | "SOME" | "USER" | "IDENTIFIED"
+| "ROLE" |"ROLLBACK" | "ROLLUP" | "SCATTER" | "SESSION" | "SIGNED" | "SHUTDOWN" | "SNAPSHOT" | "START" | "STATUS" | "OPEN"| "SUBPARTITIONS" | "SUBPARTITION" | "TABLES" | "TABLESPACE" | "TEXT" | "THAN" | "TIME" %prec lowerThanStringLitToken
+| "TIMESTAMP" %prec lowerThanStringLitToken | "TRACE" | "TRANSACTION" | "TRUNCATE" | "TTL" | "TTL_ENABLE" | "UNBOUNDED" | "UNKNOWN" | "VALUE" | "WARNINGS" | "YEAR" | "MODE"  | "WEEK"  | "ANY" | "SOME" | "USER" | "IDENTIFIED"
 | "COLLATION" | "COMMENT" | "AVG_ROW_LENGTH" | "CONNECTION" | "CHECKSUM" | "COMPRESSION" | "KEY_BLOCK_SIZE" | "MASTER" | "MAX_ROWS"
 | "MIN_ROWS" | "NATIONAL" | "NCHAR" | "ROW_FORMAT" | "QUARTER" | "GRANTS" | "TRIGGERS" | "DELAY_KEY_WRITE" | "ISOLATION" | "JSON"
-| "REPEATABLE" | "RESPECT" | "COMMITTED" | "UNCOMMITTED" | "ONLY" | "SERIAL" | "SERIALIZABLE" | "LEVEL" | "VARIABLES" | "SQL_CACHE" | "INDEXES" | "PROCESSLIST"
+| "REPEATABLE" | "RESPECT" | "BACKUP" | "BASELINES" | "BATCH" | "RESTORE" | "COMMITTED" | "UNCOMMITTED" | "ONLY" | "SERIAL" | "SERIALIZABLE" | "LEVEL" | "VARIABLES" | "SQL_CACHE" | "INDEXES" | "PROCESSLIST"
 | "SQL_NO_CACHE" | "DISABLE"  | "ENABLE" | "REVERSE" | "PRIVILEGES" | "NO" | "BINLOG" | "FUNCTION" | "VIEW" | "BINDING" | "BINDINGS" | "MODIFY" | "EVENTS" | "PARTITIONS"
-| "NONE" | "NULLS" | "SUPER" | "EXCLUSIVE" | "STATS_PERSISTENT" | "STATS_AUTO_RECALC" | "ROW_COUNT" | "COALESCE" | "MONTH" | "PROCESS" | "PROFILE" | "PROFILES"
+| "NONE" | "NULLS" | "SUPER" | "EXCLUSIVE" | "STATS_PERSISTENT" | "STATS_AUTO_RECALC" | "ROW_COUNT" | "COALESCE" | "MONTH" | "PROCESS" | "PROFILE" | "PROFILES" | "PROGRESS"
 | "MICROSECOND" | "MINUTE" | "PLUGINS" | "PRECEDING" | "QUERY" | "QUERIES" | "SECOND" | "SEPARATOR" | "SHARE" | "SHARED" | "SLOW" | "MAX_CONNECTIONS_PER_HOUR" | "MAX_QUERIES_PER_HOUR" | "MAX_UPDATES_PER_HOUR"
 | "MAX_USER_CONNECTIONS" | "REPLICATION" | "CLIENT" | "SLAVE" | "RELOAD" | "TEMPORARY" | "ROUTINE" | "EVENT" | "ALGORITHM" | "DEFINER" | "INVOKER" | "MERGE" | "TEMPTABLE" | "UNDEFINED" | "SECURITY" | "CASCADED"
-| "RECOVER" | "CIPHER" | "SUBJECT" | "ISSUER" | "X509" | "NEVER" | "EXPIRE" | "ACCOUNT" | "INCREMENTAL" | "CPU" | "MEMORY" | "BLOCK" | "IO" | "CONTEXT" | "SWITCHES" | "PAGE" | "FAULTS" | "IPC" | "SWAPS" | "SOURCE"
+| "RECOVER" | "RECOMMEND" | "CIPHER" | "SUBJECT" | "ISSUER" | "X509" | "NEVER" | "EXPIRE" | "ACCOUNT" | "INCREMENTAL" | "CPU" | "MEMORY" | "BLOCK" | "IO" | "CONTEXT" | "SWITCHES" | "PAGE" | "FAULTS" | "IPC" | "SWAPS" | "SOURCE"
 | "TRADITIONAL" | "SQL_BUFFER_RESULT" | "DIRECTORY" | "HISTORY" | "LIST" | "NODEGROUP" | "SYSTEM_TIME" | "PARTIAL" | "SIMPLE" | "REMOVE" | "PARTITIONING" | "STORAGE" | "DISK" | "STATS_SAMPLE_PAGES" | "SECONDARY_ENGINE" | "SECONDARY_LOAD" | "SECONDARY_UNLOAD" | "VALIDATION"
 | "WITHOUT" | "RTREE" | "EXCHANGE" | "COLUMN_FORMAT" | "REPAIR" | "IMPORT" | "DISCARD" | "TABLE_CHECKSUM" | "UNICODE" | "AUTO_RANDOM"
 | "SQL_TSI_DAY" | "SQL_TSI_HOUR" | "SQL_TSI_MINUTE" | "SQL_TSI_MONTH" | "SQL_TSI_QUARTER" | "SQL_TSI_SECOND" |
 "SQL_TSI_WEEK" | "SQL_TSI_YEAR" | "INVISIBLE" | "VISIBLE" | "TYPE" | "NOWAIT" | "REPLICA" | "LOCATION" | "LABELS"
-| "LOGS" | "HOSTS" | "AGAINST" | "EXPANSION" | "INCREMENT" | "MINVALUE" | "NOMAXVALUE" | "NOMINVALUE" | "NOCACHE" | "CACHE" | "CYCLE" | "NOCYCLE" | "NOORDER" | "SEQUENCE" | "MAX_MINUTES" | "MAX_IDXNUM" | "PER_TABLE" | "PER_DB"
+| "LOGS" | "HOSTS" | "HYPO" | "OPTIMIZER" | "AGAINST" | "EXPANSION" | "INCREMENT" | "MINVALUE" | "NOMAXVALUE" | "NOMINVALUE" | "NOCACHE" | "CACHE" | "CYCLE" | "NOCYCLE" | "NOORDER" | "SEQUENCE" | "MAX_MINUTES" | "MAX_IDXNUM" | "PER_TABLE" | "PER_DB" | "PLAN_CACHE" | "RESULT_CACHE"
 
 TiDBKeyword:
  "ADMIN" | "AGG_TO_COP" |"BUCKETS" | "BUILTINS" | "CANCEL" | "CMSKETCH" | "DDL" | "DEPTH" | "DRAINER" | "JOBS" | "JOB" | "NODE_ID" | "NODE_STATE" | "PUMP" | "SAMPLES" | "STATS" | "STATS_META" | "STATS_HISTOGRAMS" | "STATS_BUCKETS" | "STATS_HEALTHY" | "TIDB"
@@ -2675,12 +2919,13 @@ NotKeywordToken:
  *
  **********************************************************************************/
 InsertIntoStmt:
-	"INSERT" PriorityOpt IntoOpt TableName InsertValues
+	"INSERT" PriorityOpt IgnoreOptional IntoOpt TableName InsertValues
 	{
-		x := $5.(*ast.InsertStmt)
+		x := $6.(*ast.InsertStmt)
 		x.Priority = $2.(mysql.PriorityEnum)
+		x.IgnoreErr = $3.(bool)
 		// Wraps many layers here so that it can be processed the same way as select statement.
-		ts := &ast.TableSource{Source: $4.(*ast.TableName)}
+		ts := &ast.TableSource{Source: $5.(*ast.TableName)}
 		x.Table = &ast.TableRefsClause{TableRefs: &ast.Join{Left: ts}}
 		$$ = x
 	}
@@ -2901,7 +3146,13 @@ ByList:
 ByItem:
 	Expression Order
 	{
-		$$ = &ast.ByItem{Expr: $1, Desc: $2.(bool)}
+		expr := $1
+		if valueExpr, ok := expr.(ast.ValueExpr); ok {
+			if position, ok := valueExpr.GetValue().(int64); ok {
+				expr = &ast.PositionExpr{N: int(position)}
+			}
+		}
+		$$ = &ast.ByItem{Expr: expr, Desc: $2.(bool)}
 	}
 
 Order:
@@ -3053,6 +3304,7 @@ SimpleExpr:
 		values := append($2.([]ast.ExprNode), $4)
 		$$ = &ast.RowExpr{Values: values}
 	}
+|	SubSelect
 |	"ROW" '(' ExpressionList ',' Expression ')'
 	{
 		values := append($3.([]ast.ExprNode), $5)
@@ -3247,36 +3499,70 @@ FunctionNameDateArithMultiForms:
 |	builtinSubDate
 
 SumExpr:
-	"AVG" '(' Expression ')'
+	"AVG" '(' DefaultFalseDistinctOpt Expression ')'
 	{
-		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
 	}
 |	builtinCount '(' Expression ')'
 	{
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
 	}
+|	builtinCount '(' "ALL" Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}}
+	}
+|	builtinCount '(' DistinctKwd ExpressionList ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: $4.([]ast.ExprNode), Distinct: true}
+	}
 |	builtinCount '(' '*' ')'
 	{
 		args := []ast.ExprNode{ast.NewValueExpr(1)}
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: args,}
 	}
-|	builtinMax '(' Expression ')'
+|	builtinMax '(' DefaultFalseDistinctOpt Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+	}
+|	builtinMin '(' DefaultFalseDistinctOpt Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+	}
+|	builtinSum '(' DefaultFalseDistinctOpt Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$4}, Distinct: $3.(bool)}
+	}
+|	builtinBitAnd '(' Expression ')'
 	{
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
 	}
-|	builtinMin '(' Expression ')'
+|	builtinBitOr '(' Expression ')'
 	{
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
 	}
-|	builtinSum '(' Expression ')'
+|	builtinBitXor '(' Expression ')'
 	{
 		$$ = &ast.AggregateFuncExpr{F: $1, Args: []ast.ExprNode{$3}}
 	}
+|	builtinVarPop '(' Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: ast.AggFuncVarPop, Args: []ast.ExprNode{$3}}
+	}
+|	builtinStddevPop '(' Expression ')'
+	{
+		$$ = &ast.AggregateFuncExpr{F: ast.AggFuncStddevPop, Args: []ast.ExprNode{$3}}
+	}
+|	builtinGroupConcat '(' DefaultFalseDistinctOpt ExpressionList OptGConcatSeparator ')'
+	{
+		args := $4.([]ast.ExprNode)
+		args = append(args, $5.(ast.ExprNode))
+		$$ = &ast.AggregateFuncExpr{F: $1, Args: args, Distinct: $3.(bool)}
+	}
 
 OptGConcatSeparator:
-        {
-            	$$ = ast.NewValueExpr(",")
-        }
+	{
+		$$ = ast.NewValueExpr(",")
+	}
 | "SEPARATOR" stringLit
 	{
 		$$ = ast.NewValueExpr($2)
@@ -3524,6 +3810,15 @@ QuickOptional:
 		$$ = true
 	}
 
+IgnoreOptional:
+	{
+		$$ = false
+	}
+|	"IGNORE"
+	{
+		$$ = true
+	}
+
 RollbackStmt:
 	"ROLLBACK"
 	{
@@ -3681,6 +3976,15 @@ TableRef:
 		$$ = $1
 	}
 
+SubSelect:
+	'(' SelectStmt ')'
+	{
+		st := $2.(*ast.SelectStmt)
+		endOffset := parser.endOffset(&yyS[yypt])
+		parser.setLastSelectFieldText(st, endOffset)
+		$$ = &ast.SubqueryExpr{Query: st}
+	}
+
 TableFactor:
 	TableName TableAsNameOpt IndexHintListOpt
 	{
@@ -3815,16 +4119,16 @@ JoinTable:
 		on := &ast.OnCondition{Expr: $5}
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $3.(ast.ResultSetNode), Tp: ast.CrossJoin, On: on}
 	}
-/* |	TableRef CrossOpt TableRef "USING" '(' ColumnNameList ')'
+|	TableRef CrossOpt TableRef "USING" '(' ColumnNameList ')'
 	{
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $3.(ast.ResultSetNode), Tp: ast.CrossJoin, Using: $6.([]*ast.ColumnName)}
-	} */
+	}
 |	TableRef JoinType OuterOpt "JOIN" TableRef "ON" Expression
 	{
 		on := &ast.OnCondition{Expr: $7}
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $5.(ast.ResultSetNode), Tp: $2.(ast.JoinType), On: on}
 	}
-/* |	TableRef JoinType OuterOpt "JOIN" TableRef "USING" '(' ColumnNameList ')'
+|	TableRef JoinType OuterOpt "JOIN" TableRef "USING" '(' ColumnNameList ')'
 	{
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $5.(ast.ResultSetNode), Tp: $2.(ast.JoinType), Using: $8.([]*ast.ColumnName)}
 	}
@@ -3835,7 +4139,7 @@ JoinTable:
 |	TableRef "NATURAL" JoinType OuterOpt "JOIN" TableRef
 	{
 		$$ = &ast.Join{Left: $1.(ast.ResultSetNode), Right: $6.(ast.ResultSetNode), Tp: $3.(ast.JoinType), NaturalJoin: true}
-	} */
+	}
 
 JoinType:
 	"LEFT"
@@ -4416,6 +4720,150 @@ AdminStmt:
 		}
 		$$ = stmt
 	}
+|	"ADMIN" "SHOW" "DDL" "PROGRESS"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminShowDDLProgress}
+	}
+|	"ADMIN" "SHOW" "ANALYZE" "PROGRESS"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminShowAnalyzeProgress}
+	}
+|	"ADMIN" "RELOAD" "STATS"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminReloadStats}
+	}
+|	"ADMIN" "RELOAD" "PRIVILEGES"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminReloadPrivileges}
+	}
+|	"ADMIN" "FLUSH" "PLAN_CACHE"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminFlushPlanCache}
+	}
+|	"ADMIN" "FLUSH" "RESULT_CACHE"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminFlushResultCache}
+	}
+|	"ADMIN" "RECOMMEND" "INDEX"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminRecommendIndex}
+	}
+|	"ADMIN" "SHOW" "BASELINES"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminShowBaselines}
+	}
+|	"ADMIN" "EVOLVE" "BASELINES"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminEvolveBaselines}
+	}
+|	"ADMIN" "SCATTER" "TABLE" TableName AdminIndexOpt
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminScatterTable,
+			Tables: []*ast.TableName{$4.(*ast.TableName)},
+			Index:  $5.(string),
+		}
+	}
+|	"ADMIN" "SHOW" "TABLE" TableName AdminIndexOpt "REGIONS"
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminShowTableRegions,
+			Tables: []*ast.TableName{$4.(*ast.TableName)},
+			Index:  $5.(string),
+		}
+	}
+|	"ADMIN" "SHOW" "TABLE" TableName AdminIndexOpt "DISTRIBUTIONS"
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminShowTableDistributions,
+			Tables: []*ast.TableName{$4.(*ast.TableName)},
+			Index:  $5.(string),
+		}
+	}
+|	"ADMIN" "ENABLE" "ROW" "CHECKSUM"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminEnableRowChecksum}
+	}
+|	"ADMIN" "DISABLE" "ROW" "CHECKSUM"
+	{
+		$$ = &ast.AdminStmt{Tp: ast.AdminDisableRowChecksum}
+	}
+|	"ADMIN" "REBUILD" "ROW" "CHECKSUM" TableNameList
+	{
+		$$ = &ast.AdminStmt{
+			Tp:     ast.AdminRebuildRowChecksum,
+			Tables: $5.([]*ast.TableName),
+		}
+	}
+|	"ADMIN" "EXPORT" "DATABASE" DBNameList "TO" stringLit ExportFormatOpt
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminExport,
+			Export: &ast.ExportOption{
+				Schemas: $4.([]string),
+				Storage: $6,
+				Format:  $7.(string),
+			},
+		}
+	}
+
+AdminIndexOpt:
+	{
+		$$ = ""
+	}
+|	"INDEX" Identifier
+	{
+		$$ = $2
+	}
+
+ExportFormatOpt:
+	{
+		$$ = ""
+	}
+|	"FORMAT" eq stringLit
+	{
+		$$ = $3
+	}
+
+/*******************************************************************
+ *
+ *  Import Statement
+ *
+ *	IMPORT INTO t FROM '/path/to/*.csv'
+ *******************************************************************/
+ImportIntoStmt:
+	"IMPORT" "INTO" TableName "FROM" stringLit
+	{
+		$$ = &ast.ImportIntoStmt{
+			Table: $3.(*ast.TableName),
+			Path:  $5,
+		}
+	}
+
+/*******************************************************************
+ *
+ *  Backup / Restore Statements
+ *
+ *	BACKUP DATABASE db1, db2 TO 'path'
+ *	RESTORE DATABASE db1, db2 FROM 'path'
+ *******************************************************************/
+BRIEStmt:
+	"BACKUP" "DATABASE" DBNameList "TO" stringLit
+	{
+		$$ = &ast.BRIEStmt{
+			Kind:    ast.BRIEKindBackup,
+			Schemas: $3.([]string),
+			Storage: $5,
+		}
+	}
+|	"RESTORE" "DATABASE" DBNameList "FROM" stringLit
+	{
+		$$ = &ast.BRIEStmt{
+			Kind:    ast.BRIEKindRestore,
+			Schemas: $3.([]string),
+			Storage: $5,
+		}
+	}
 
 /****************************Show Statement*******************************/
 ShowStmt:
@@ -4449,6 +4897,18 @@ ShowStmt:
 			Full:	$2.(bool),
 		}
 	}
+|	"SHOW" "TABLE" TableName AdminIndexOpt "REGIONS" WhereClauseOptional
+	{
+		stmt := &ast.ShowStmt{
+			Tp:		ast.ShowRegions,
+			Table:		$3.(*ast.TableName),
+			IndexName:	model.NewCIStr($4.(string)),
+		}
+		if $6 != nil {
+			stmt.Where = $6.(ast.ExprNode)
+		}
+		$$ = stmt
+	}
 
 ShowIndexKwd:
 	"INDEX"
@@ -4550,6 +5010,8 @@ Statement:
 |	AlterTableStmt
 |	AnalyzeTableStmt
 |	BeginTransactionStmt
+|	BRIEStmt
+|	BatchDMLStmt
 |	CommitStmt
 |	DeleteFromStmt
 |	ExplainStmt
@@ -4559,6 +5021,7 @@ Statement:
 |	DropDatabaseStmt
 |	DropIndexStmt
 |	DropTableStmt
+|	ImportIntoStmt
 |	InsertIntoStmt
 |	RollbackStmt
 |	ReplaceIntoStmt
@@ -311,6 +311,10 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"INSERT INTO foo VALUES (bar(5678))", true, "INSERT INTO `foo` VALUES (BAR(5678))"},
 		// 20
 		{"INSERT INTO foo VALUES ()", true, "INSERT INTO `foo` VALUES ()"},
+		{"INSERT IGNORE INTO foo VALUES (1)", true, "INSERT IGNORE INTO `foo` VALUES (1)"},
+		{"INSERT LOW_PRIORITY IGNORE foo SELECT * FROM bar", true, "INSERT LOW_PRIORITY IGNORE INTO `foo` SELECT * FROM `bar`"},
+		{"INSERT INTO IGNORE foo VALUES (1)", false, ""},
+		{"REPLACE IGNORE INTO foo VALUES (1)", false, ""},
 		{"SELECT * FROM t", true, "SELECT * FROM `t`"},
 		{"SELECT * FROM t AS u", true, "SELECT * FROM `t` AS `u`"},
 		// 25
@@ -374,6 +378,24 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"select * from t1 join t2 left join t3 on t2.id = t3.id", true, "SELECT * FROM (`t1` JOIN `t2`) LEFT JOIN `t3` ON `t2`.`id`=`t3`.`id`"},
 		{"select * from t1 right join t2 on t1.id = t2.id left join t3 on t3.id = t2.id", true, "SELECT * FROM (`t1` RIGHT JOIN `t2` ON `t1`.`id`=`t2`.`id`) LEFT JOIN `t3` ON `t3`.`id`=`t2`.`id`"},
 		{"select * from t1 right join t2 on t1.id = t2.id left join t3", false, ""},
+		{"select * from t1 join t2 using (a)", true, "SELECT * FROM `t1` JOIN `t2` USING (`a`)"},
+		{"select * from t1 left join t2 using (a, b) right join t3 using (b)", true, "SELECT * FROM (`t1` LEFT JOIN `t2` USING (`a`,`b`)) RIGHT JOIN `t3` USING (`b`)"},
+		{"select * from t1 natural join t2", true, "SELECT * FROM `t1` NATURAL JOIN `t2`"},
+		{"select * from t1 natural left join t2", true, "SELECT * FROM `t1` NATURAL LEFT JOIN `t2`"},
+		{"select * from t1 natural right outer join t2", true, "SELECT * FROM `t1` NATURAL RIGHT JOIN `t2`"},
+		{"select * from t1 join t2 using ()", false, ""},
+
+		// scalar subquery
+		{"select (select 1) from t", true, ""},
+		{"select a, (select count(*) from s where s.a = t.a) from t", true, ""},
+		{"select * from t where a = (select max(a) from s) and b > 1", true, ""},
+		{"select (select a from s limit 1) + 1 from t order by (select 1)", true, ""},
+		{"select (select) from t", false, ""},
+
+		// order by and group by position
+		{"select a, b from t order by 2 desc, 1", true, ""},
+		{"select a, count(*) from t group by 1", true, ""},
+		{"select a from t order by 1.5, -1, (1)", true, ""},
 
 		// delete statement
 		// single table syntax
@@ -392,12 +414,72 @@ func (s *testParserSuite) TestDMLStmt(c *C) {
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id limit 10;", false, ""},
 		{"DELETE t1, t2 FROM t1 INNER JOIN t2 INNER JOIN t3 WHERE t1.id=t2.id AND t2.id=t3.id order by t1.id;", false, ""},
 
+		// batch dml statement
+		{"batch on id limit 1000 delete from t where a > 1", true, ""},
+		{"BATCH ON t1.id LIMIT 10 DELETE FROM t1 AS w", true, ""},
+		{"batch on id limit 10 select * from t", false, ""},
+		{"batch on id delete from t", false, ""},
+		{"batch limit 10 delete from t", false, ""},
+		{"create table batch (batch int)", true, "CREATE TABLE `batch` (`batch` INT)"},
+
 		// for admin
 		{"admin show ddl;", true, "ADMIN SHOW DDL"},
 		{"admin show ddl jobs;", true, "ADMIN SHOW DDL JOBS"},
 		{"admin show ddl jobs where id > 0;", true, "ADMIN SHOW DDL JOBS WHERE `id`>0"},
 		{"admin show ddl jobs 20 where id=0;", true, "ADMIN SHOW DDL JOBS 20 WHERE `id`=0"},
 		{"admin show ddl jobs -1;", false, ""},
+		{"admin show ddl progress;", true, ""},
+		{"admin show analyze progress;", true, ""},
+		{"admin show analyze;", false, ""},
+		{"create table progress (progress int)", true, "CREATE TABLE `progress` (`progress` INT)"},
+		{"admin reload stats;", true, "ADMIN RELOAD STATS"},
+		{"admin reload privileges;", true, "ADMIN RELOAD PRIVILEGES"},
+		{"admin reload;", false, ""},
+		{"admin flush plan_cache;", true, ""},
+		{"admin flush result_cache;", true, ""},
+		{"admin flush;", false, ""},
+		{"create table plan_cache (result_cache int)", true, "CREATE TABLE `plan_cache` (`result_cache` INT)"},
+		{"admin recommend index;", true, ""},
+		{"admin recommend;", false, ""},
+		{"admin show baselines;", true, ""},
+		{"admin evolve baselines;", true, ""},
+		{"admin evolve;", false, ""},
+		{"admin export database a to '/tmp/export';", true, ""},
+		{"admin export database a, b to '/tmp/export' format = 'csv';", true, ""},
+		{"admin export database a to '/tmp/export' format 'csv';", false, ""},
+		{"admin export a to '/tmp/export';", false, ""},
+		{"admin scatter table t;", true, ""},
+		{"admin scatter table test.t index idx;", true, ""},
+		{"admin scatter t;", false, ""},
+		{"admin scatter table t index;", false, ""},
+		{"admin show table t regions;", true, ""},
+		{"admin show table test.t index idx regions;", true, ""},
+		{"admin show table t distributions;", true, ""},
+		{"admin show table t index idx distributions;", true, ""},
+		{"admin show table t;", false, ""},
+		{"create table scatter (distributions int);", true, ""},
+		{"show table t regions;", true, ""},
+		{"show table test.t index idx regions where leader_store_id = 1;", true, ""},
+		{"show table t index regions;", false, ""},
+		{"show table t;", false, ""},
+		{"admin enable row checksum;", true, ""},
+		{"admin disable row checksum;", true, ""},
+		{"admin rebuild row checksum t;", true, ""},
+		{"admin rebuild row checksum t1, test.t2;", true, ""},
+		{"admin rebuild row checksum;", false, ""},
+		{"admin enable checksum;", false, ""},
+
+		// for import
+		{"import into t from '/tmp/data/*.csv';", true, ""},
+		{"import into test.t from '/tmp/t.csv';", true, ""},
+		{"import into t;", false, ""},
+
+		// for backup and restore
+		{"backup database a to 'local:///tmp/backup';", true, ""},
+		{"backup database a, b to '/tmp/backup';", true, ""},
+		{"restore database a, b from '/tmp/backup';", true, ""},
+		{"backup database to '/tmp/backup';", false, ""},
+		{"restore database a to '/tmp/backup';", false, ""},
 
 		// for insert ... set
 		{"INSERT INTO t SET a=1,b=2", true, "INSERT INTO `t` SET `a`=1,`b`=2"},
@@ -490,6 +572,16 @@ func (s *testParserSuite) TestExpression(c *C) {
 		{"select n'string'", true, "SELECT _UTF8'string'"},
 		// for comparison
 		{"select 1 <=> 0, 1 <=> null, 1 = null", true, "SELECT 1<=>0,1<=>NULL,1=NULL"},
+		// for like
+		{"select a like 'b%', a not like '_b' from t", true, "SELECT a LIKE 'b%',a NOT LIKE '_b' FROM t"},
+		{"select a like 'b|%' escape '|', a like 'b' escape '' from t", true, "SELECT a LIKE 'b|%' ESCAPE '|',a LIKE 'b' FROM t"},
+		{"select a like 'b中%' escape '中' from t", true, "SELECT a LIKE 'b中%' ESCAPE '中' FROM t"},
+		{"select a like 'b' escape '||' from t", false, ""},
+		{"select a like from t", false, ""},
+		// for regexp
+		{"select a regexp 'b', a rlike 'b', a not regexp 'b', a not rlike 'b' from t", true, "SELECT a REGEXP 'b',a REGEXP 'b',a NOT REGEXP 'b',a NOT REGEXP 'b' FROM t"},
+		{"select 1 + 1 regexp 2 and 1", true, "SELECT 1+1 REGEXP 2 AND 1"},
+		{"select a regexp from t", false, ""},
 		// for date literal
 		{"select date'1989-09-10'", true, "SELECT DATE '1989-09-10'"},
 		{"select date 19890910", false, ""},
@@ -863,6 +955,19 @@ func (s *testParserSuite) TestBuiltin(c *C) {
 		{`select count(c1) from t;`, true, "SELECT COUNT(`c1`) FROM `t`"},
 		{`select count(*) from t;`, true, "SELECT COUNT(1) FROM `t`"},
 		{`select count(c1, c2) from t;`, false, ""},
+		{`select count(distinct c1, c2), count(all c1) from t;`, true, ""},
+		{`select count(distinctrow c1) from t;`, true, ""},
+		{`select sum(distinct c1), avg(distinct c1), max(distinct c1), min(all c1) from t;`, true, ""},
+		{`select sum(distinct c1, c2) from t;`, false, ""},
+		{`select count(distinct *) from t;`, false, ""},
+		{`select bit_and(c1), bit_or(c1), bit_xor(c1) from t;`, true, ""},
+		{`select bit_and(c1, c2) from t;`, false, ""},
+		{`select bit_or(distinct c1) from t;`, false, ""},
+		{`select var_pop(c1), variance(c1), stddev_pop(c1), stddev(c1), std(c1) from t;`, true, ""},
+		{`select var_pop(c1, c2) from t;`, false, ""},
+		{`select group_concat(c1), group_concat(distinct c1, c2 separator '|') from t;`, true, ""},
+		{`select group_concat(c1 separator) from t;`, false, ""},
+		{`select group_concat() from t;`, false, ""},
 	}
 	s.RunTest(c, table)
 }
@@ -1006,6 +1111,10 @@ func (s *testParserSuite) TestDDL(c *C) {
 		{"create table t (a timestamp default now())", true, "CREATE TABLE `t` (`a` TIMESTAMP DEFAULT CURRENT_TIMESTAMP())"},
 		{"create table t (a timestamp default now() on update now)", false, ""},
 		{"create table t (a timestamp default now() on update now())", true, "CREATE TABLE `t` (`a` TIMESTAMP DEFAULT CURRENT_TIMESTAMP() ON UPDATE CURRENT_TIMESTAMP())"},
+		{"create table t (a varchar(36) default (uuid()))", true, "CREATE TABLE `t` (`a` VARCHAR(36) DEFAULT (UUID()))"},
+		{"create table t (a int default (1 + 2) not null)", true, "CREATE TABLE `t` (`a` INT DEFAULT (1+2) NOT NULL)"},
+		{"create table t (a int default (1 + 2)", false, ""},
+		{"create table t (a int default 1 + 2)", false, ""},
 		{"ALTER TABLE t ADD COLUMN (a SMALLINT UNSIGNED)", true, "ALTER TABLE `t` ADD COLUMN (`a` SMALLINT UNSIGNED)"},
 		{"ALTER TABLE t.* ADD COLUMN (a SMALLINT UNSIGNED)", false, ""},
 		{"ALTER TABLE t ADD COLUMN IF NOT EXISTS (a SMALLINT UNSIGNED)", true, "ALTER TABLE `t` ADD COLUMN IF NOT EXISTS (`a` SMALLINT UNSIGNED)"},
@@ -1057,6 +1166,24 @@ func (s *testParserSuite) TestDDL(c *C) {
 		{"ALTER TABLE t ALTER INDEX a INVISIBLE", true, "ALTER TABLE `t` ALTER INDEX `a` INVISIBLE"},
 		{"ALTER TABLE t ALTER INDEX a VISIBLE", true, "ALTER TABLE `t` ALTER INDEX `a` VISIBLE"},
 
+		// For TTL table options
+		{"CREATE TABLE t (a INT, t BIGINT) TTL = t + INTERVAL 1 DAY", true, ""},
+		{"CREATE TABLE t (a INT, t BIGINT) TTL t + INTERVAL 30 MINUTE TTL_ENABLE = 'OFF'", true, ""},
+		{"CREATE TABLE t (a INT, t BIGINT) TTL = t + INTERVAL 1 WEEK, TTL_ENABLE = 'on'", true, ""},
+		{"CREATE TABLE t (a INT, t BIGINT) TTL = t + INTERVAL 1 MONTH", false, ""},
+		{"CREATE TABLE t (a INT, t BIGINT) TTL = t + INTERVAL -1 DAY", false, ""},
+		{"CREATE TABLE t (a INT, t BIGINT) TTL_ENABLE = 'yes'", false, ""},
+		{"ALTER TABLE t TTL = t + INTERVAL 10 SECOND", true, ""},
+		{"ALTER TABLE t TTL_ENABLE = 'ON'", true, ""},
+		{"ALTER TABLE t REMOVE TTL", true, ""},
+		{"CREATE TABLE ttl (ttl INT, ttl_enable INT)", true, ""},
+
+		// For cached tables
+		{"ALTER TABLE t CACHE", true, ""},
+		{"ALTER TABLE t NOCACHE", true, ""},
+		{"ALTER TABLE t CACHE NOCACHE", false, ""},
+		{"CREATE TABLE cache (cache INT, nocache INT)", true, ""},
+
 		{"ALTER TABLE t DROP FOREIGN KEY a", true, "ALTER TABLE `t` DROP FOREIGN KEY `a`"},
 		{"ALTER TABLE t DROP FOREIGN KEY IF EXISTS a", true, "ALTER TABLE `t` DROP FOREIGN KEY IF EXISTS `a`"},
 		{"ALTER TABLE t DROP COLUMN a CASCADE", true, "ALTER TABLE `t` DROP COLUMN `a`"},
@@ -1254,6 +1381,42 @@ func (s *testParserSuite) TestDDL(c *C) {
 	s.RunTest(c, table)
 }
 
+func (s *testParserSuite) TestTTLTableOption(c *C) {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt("create table t (a int, t bigint) ttl = t + interval 3 hour ttl_enable = 'off'", "", "")
+	c.Assert(err, IsNil)
+	options := stmt.(*ast.CreateTableStmt).Options
+	c.Assert(options, HasLen, 2)
+	c.Assert(options[0].Tp, Equals, ast.TableOptionTTL)
+	c.Assert(options[0].ColumnName.Name.L, Equals, "t")
+	c.Assert(options[0].UintValue, Equals, uint64(3))
+	c.Assert(options[0].StrValue, Equals, "HOUR")
+	c.Assert(options[1].Tp, Equals, ast.TableOptionTTLEnable)
+	c.Assert(options[1].BoolValue, IsFalse)
+
+	stmt, err = p.ParseOneStmt("alter table t remove ttl", "", "")
+	c.Assert(err, IsNil)
+	specs := stmt.(*ast.AlterTableStmt).Specs
+	c.Assert(specs, HasLen, 1)
+	c.Assert(specs[0].Tp, Equals, ast.AlterTableRemoveTTL)
+
+	stmt, err = p.ParseOneStmt("alter table t ttl_enable = 'ON'", "", "")
+	c.Assert(err, IsNil)
+	specs = stmt.(*ast.AlterTableStmt).Specs
+	c.Assert(specs[0].Tp, Equals, ast.AlterTableOption)
+	c.Assert(specs[0].Options[0].BoolValue, IsTrue)
+}
+
+func (s *testParserSuite) TestAlterTableCache(c *C) {
+	p := parser.New()
+	stmt, err := p.ParseOneStmt("alter table t cache", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.AlterTableStmt).Specs[0].Tp, Equals, ast.AlterTableCache)
+	stmt, err = p.ParseOneStmt("alter table t nocache", "", "")
+	c.Assert(err, IsNil)
+	c.Assert(stmt.(*ast.AlterTableStmt).Specs[0].Tp, Equals, ast.AlterTableNoCache)
+}
+
 func (s *testParserSuite) TestType(c *C) {
 	table := []testCase{
 		// for time fsp
@@ -1307,6 +1470,10 @@ func (s *testParserSuite) TestSQLResult(c *C) {
 	table := []testCase{
 		{`select SQL_BIG_RESULT c1 from t group by c1`, true, "SELECT SQL_BIG_RESULT `c1` FROM `t` GROUP BY `c1`"},
 		{`select SQL_SMALL_RESULT c1 from t group by c1`, true, "SELECT SQL_SMALL_RESULT `c1` FROM `t` GROUP BY `c1`"},
+		{`select c1, c2, sum(c3), grouping(c1, c2) from t group by c1, c2 with rollup`, true, ""},
+		{`select c1 from t group by c1 with rollup having grouping(c1) = 0 order by c1`, true, ""},
+		{`select c1 from t group by c1 rollup`, false, ""},
+		{`select rollup from t`, true, ""},
 		{`select SQL_BUFFER_RESULT * from t`, true, "SELECT SQL_BUFFER_RESULT * FROM `t`"},
 		{`select sql_small_result sql_big_result sql_buffer_result 1`, true, "SELECT SQL_SMALL_RESULT SQL_BIG_RESULT SQL_BUFFER_RESULT 1"},
 	}
@@ -1364,6 +1531,17 @@ func (s *testParserSuite) TestExplain(c *C) {
 		{"EXPLAIN SELECT 1", true, "EXPLAIN FORMAT = 'row' SELECT 1"},
 		{"EXPLAIN FORMAT = JSON SELECT 1", true, "EXPLAIN FORMAT = 'json' SELECT 1"},
 		{"EXPLAIN FORMAT = 'hint' SELECT 1", true, "EXPLAIN FORMAT = 'hint' SELECT 1"},
+		{"explain with hypo index idx on t(a) select * from t where a = 1", true, ""},
+		{"explain format = 'row' with hypo index idx on test.t(a, b(10)), hypo index idx1 on t1(c) select * from t", true, ""},
+		{"explain with hypo index idx on t(a) delete from t where a = 1", true, ""},
+		{"explain with hypo index on t(a) select * from t", false, ""},
+		{"explain with index idx on t(a) select * from t", false, ""},
+		{"explain with hypo index idx on t(a)", false, ""},
+		{"explain with optimizer trace select * from t where a = 1", true, ""},
+		{"explain format = 'row' with hypo index idx on t(a), optimizer trace select * from t", true, ""},
+		{"explain with optimizer trace, hypo index idx on t(a) select * from t", true, ""},
+		{"explain with optimizer select * from t", false, ""},
+		{"create table optimizer (optimizer int)", true, "CREATE TABLE `optimizer` (`optimizer` INT)"},
 	}
 	s.RunTest(c, table)
 }
@@ -179,7 +179,7 @@ func (r *ImplIndexScan) OnImplement(expr *memo.GroupExpr, reqProp *property.Phys
 	is := logicalScan.GetPhysicalIndexScan(expr.Group.Prop.Schema, expr.Group.Prop.Stats.ScaleByExpectCnt(reqProp.ExpectedCnt))
 	if !reqProp.IsEmpty() {
 		is.KeepOrder = true
-		if reqProp.Items[0].Desc {
+		if logicalScan.IsScanDesc(reqProp) {
 			is.Desc = true
 		}
 	}
@@ -179,6 +179,10 @@ func (r *PushSelDownIndexScan) OnTransform(old *memo.ExprIter) (newExprs []*memo
 	if err != nil {
 		return nil, false, false, err
 	}
+	if is.Index.DescColumns() != nil && !ranger.IsPrefixPointRanges(is.SCtx().GetSessionVars().StmtCtx, res.Ranges) {
+		// The ranges can't be converted to the key ranges of the index with descending columns.
+		return nil, false, false, nil
+	}
 	if len(res.AccessConds) == len(is.AccessConds) {
 		// There is no condition can be pushed down as range,
 		// or the pushed down conditions are the same with before.
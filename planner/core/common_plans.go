@@ -57,6 +57,8 @@ type Insert struct {
 	Columns       []*ast.ColumnName
 	Lists         [][]expression.Expression
 	SetList       []*expression.Assignment
+	// GenExprs are the expressions of the generated columns, in the order of the columns in the table.
+	GenExprs []expression.Expression
 
 	IsReplace bool
 
@@ -201,6 +203,9 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, indent string, isLast
 	case *PhysicalIndexLookUpReader:
 		err = e.explainPlanInRowFormat(x.indexPlan, "cop", childIndent, false)
 		err = e.explainPlanInRowFormat(x.tablePlan, "cop", childIndent, true)
+	case *PhysicalTableLookUpReader:
+		err = e.explainPlanInRowFormat(x.filterPlan, "cop", childIndent, false)
+		err = e.explainPlanInRowFormat(x.tablePlan, "cop", childIndent, true)
 	case *Insert:
 		if x.SelectPlan != nil {
 			err = e.explainPlanInRowFormat(x.SelectPlan, "root", childIndent, true)
@@ -332,6 +337,11 @@ func (e *Explain) prepareTaskDot(p PhysicalPlan, taskTp string, buffer *bytes.Bu
 			pipelines = append(pipelines, fmt.Sprintf("\"%s\" -> \"%s\"\n", copPlan.ExplainID(), copPlan.indexPlan.ExplainID()))
 			copTasks = append(copTasks, copPlan.tablePlan)
 			copTasks = append(copTasks, copPlan.indexPlan)
+		case *PhysicalTableLookUpReader:
+			pipelines = append(pipelines, fmt.Sprintf("\"%s\" -> \"%s\"\n", copPlan.ExplainID(), copPlan.tablePlan.ExplainID()))
+			pipelines = append(pipelines, fmt.Sprintf("\"%s\" -> \"%s\"\n", copPlan.ExplainID(), copPlan.filterPlan.ExplainID()))
+			copTasks = append(copTasks, copPlan.tablePlan)
+			copTasks = append(copTasks, copPlan.filterPlan)
 		}
 	}
 	buffer.WriteString("}\n")
@@ -61,6 +61,12 @@ var (
 	ErrCartesianProductUnsupported     = terror.ClassOptimizer.New(mysql.ErrCartesianProductUnsupported, mysql.MySQLErrName[mysql.ErrCartesianProductUnsupported])
 	ErrStmtNotFound                    = terror.ClassOptimizer.New(mysql.ErrPreparedStmtNotFound, mysql.MySQLErrName[mysql.ErrPreparedStmtNotFound])
 	ErrAmbiguous                       = terror.ClassOptimizer.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	ErrFieldInGroupingNotGroupBy       = terror.ClassOptimizer.New(mysql.ErrFieldInGroupingNotGroupBy, mysql.MySQLErrName[mysql.ErrFieldInGroupingNotGroupBy])
+	ErrKeyColumnDoesNotExist           = terror.ClassOptimizer.New(mysql.ErrKeyColumnDoesNotExits, mysql.MySQLErrName[mysql.ErrKeyColumnDoesNotExits])
+	ErrDupKeyName                      = terror.ClassOptimizer.New(mysql.ErrDupKeyName, mysql.MySQLErrName[mysql.ErrDupKeyName])
+	ErrWrongSubKey                     = terror.ClassOptimizer.New(mysql.ErrWrongSubKey, mysql.MySQLErrName[mysql.ErrWrongSubKey])
+	ErrOptionPreventsStatement         = terror.ClassOptimizer.New(mysql.ErrOptionPreventsStatement, mysql.MySQLErrName[mysql.ErrOptionPreventsStatement])
+	ErrUpdateWithoutKeyInSafeMode      = terror.ClassOptimizer.New(mysql.ErrUpdateWithoutKeyInSafeMode, mysql.MySQLErrName[mysql.ErrUpdateWithoutKeyInSafeMode])
 	// Since we cannot know if user loggined with a password, use message of ErrAccessDeniedNoPassword instead
 	ErrAccessDenied = terror.ClassOptimizer.New(mysql.ErrAccessDenied, mysql.MySQLErrName[mysql.ErrAccessDeniedNoPassword])
 )
@@ -110,6 +116,12 @@ func init() {
 		mysql.ErrNonuniqTable:                        mysql.ErrNonuniqTable,
 		mysql.ErrTooBigPrecision:                     mysql.ErrTooBigPrecision,
 		mysql.ErrInvalidWildCard:                     mysql.ErrInvalidWildCard,
+		mysql.ErrFieldInGroupingNotGroupBy:           mysql.ErrFieldInGroupingNotGroupBy,
+		mysql.ErrKeyColumnDoesNotExits:               mysql.ErrKeyColumnDoesNotExits,
+		mysql.ErrDupKeyName:                          mysql.ErrDupKeyName,
+		mysql.ErrWrongSubKey:                         mysql.ErrWrongSubKey,
+		mysql.ErrOptionPreventsStatement:             mysql.ErrOptionPreventsStatement,
+		mysql.ErrUpdateWithoutKeyInSafeMode:          mysql.ErrUpdateWithoutKeyInSafeMode,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassOptimizer] = mysqlErrCodeMap
 }
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/set"
 )
 
@@ -279,6 +280,11 @@ func (p *LogicalJoin) getHashJoin(prop *property.PhysicalProperty, innerIdx int)
 // If the hint is not matched, it will get other candidates.
 // If the hint is not figured, we will pick all candidates.
 func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// The merge join relies on the keys sorted by their bytes, so the keys compared
+	// in a case insensitive collation can only be joined by the hash join.
+	if p.hasNonBinCollationEqualCond() {
+		return p.getHashJoins(prop)
+	}
 	mergeJoins := p.getMergeJoin(prop)
 	if (p.preferJoinType & preferMergeJoin) > 0 {
 		return mergeJoins
@@ -294,6 +300,15 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []Ph
 	return joins
 }
 
+func (p *LogicalJoin) hasNonBinCollationEqualCond() bool {
+	for _, cond := range p.EqualConditions {
+		if !collate.IsBinCollation(expression.DeriveCollationFromExprs(cond.GetArgs()...)) {
+			return true
+		}
+	}
+	return false
+}
+
 // TryToGetChildProp will check if this sort property can be pushed or not.
 // When a sort column will be replaced by scalar function, we refuse it.
 // When a sort column will be replaced by a constant, we just remove it.
@@ -325,6 +340,19 @@ func (p *LogicalProjection) exhaustPhysicalPlans(prop *property.PhysicalProperty
 	return []PhysicalPlan{proj}
 }
 
+func (p *LogicalExpand) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// The output of Expand is not ordered by any column.
+	if !prop.IsEmpty() {
+		return nil
+	}
+	childProp := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	expand := PhysicalExpand{
+		LevelExprs: p.LevelExprs,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	expand.SetSchema(p.schema)
+	return []PhysicalPlan{expand}
+}
+
 func (lt *LogicalTopN) getPhysTopN() []PhysicalPlan {
 	ret := make([]PhysicalPlan, 0, 3)
 	for _, tp := range wholeTaskTypes {
@@ -424,6 +452,34 @@ func (p *LogicalLimit) exhaustPhysicalPlans(prop *property.PhysicalProperty) []P
 	return ret
 }
 
+func (la *LogicalApply) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	// The apply keeps the order of the outer rows, so only the property on the outer schema can be passed.
+	if !prop.AllColsFromSchema(la.children[0].Schema()) {
+		return nil
+	}
+	join := la.getHashJoin(prop, 1)
+	// The inner rows are not hashed, so the equal conditions are evaluated as other conditions.
+	join.OtherConditions = append(expression.ScalarFuncs2Exprs(join.EqualConditions), join.OtherConditions...)
+	join.EqualConditions, join.LeftJoinKeys, join.RightJoinKeys = nil, nil, nil
+	apply := PhysicalApply{
+		PhysicalHashJoin: *join,
+		OuterSchema:      la.CorCols,
+	}.Init(la.ctx,
+		la.stats.ScaleByExpectCnt(prop.ExpectedCnt),
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64, Items: prop.Items},
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	apply.SetSchema(la.schema)
+	return []PhysicalPlan{apply}
+}
+
+func (p *LogicalMaxOneRow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	mor := PhysicalMaxOneRow{}.Init(p.ctx, p.stats, &property.PhysicalProperty{ExpectedCnt: 2})
+	return []PhysicalPlan{mor}
+}
+
 func (ls *LogicalSort) getPhysicalSort(prop *property.PhysicalProperty) *PhysicalSort {
 	ps := PhysicalSort{ByItems: ls.ByItems}.Init(ls.ctx, ls.stats.ScaleByExpectCnt(prop.ExpectedCnt), &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
 	return ps
@@ -125,9 +125,10 @@ func (p *PhysicalTableReader) ExplainNormalizedInfo() string {
 	return p.ExplainInfo()
 }
 
-// ExplainInfo implements Plan interface.
+// ExplainInfo implements Plan interface. The index reader is only built when the index
+// covers all the needed columns, so it's marked as a covering index without the table lookup.
 func (p *PhysicalIndexReader) ExplainInfo() string {
-	return "index:" + p.indexPlan.ExplainID().String()
+	return "index:" + p.indexPlan.ExplainID().String() + ", covering index"
 }
 
 // ExplainNormalizedInfo implements Plan interface.
@@ -140,6 +141,11 @@ func (p *PhysicalIndexLookUpReader) ExplainInfo() string {
 	return ""
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalTableLookUpReader) ExplainInfo() string {
+	return ""
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalUnionScan) ExplainInfo() string {
 	return string(expression.SortedExplainExpressionList(p.Conditions))
@@ -165,6 +171,11 @@ func (p *PhysicalProjection) ExplainNormalizedInfo() string {
 	return string(expression.SortedExplainNormalizedExpressionList(p.Exprs))
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalExpand) ExplainInfo() string {
+	return explainLevelExprs(p.LevelExprs, p.schema)
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalTableDual) ExplainInfo() string {
 	return fmt.Sprintf("rows:%v", p.RowCount)
@@ -356,6 +367,24 @@ func (p *LogicalProjection) ExplainInfo() string {
 	return expression.ExplainExpressionList(p.Exprs, p.schema)
 }
 
+// ExplainInfo implements Plan interface.
+func (p *LogicalExpand) ExplainInfo() string {
+	return explainLevelExprs(p.LevelExprs, p.schema)
+}
+
+func explainLevelExprs(levelExprs [][]expression.Expression, schema *expression.Schema) string {
+	buffer := bytes.NewBufferString("level-projection:")
+	for i, exprs := range levelExprs {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString("[")
+		buffer.WriteString(expression.ExplainExpressionList(exprs, schema))
+		buffer.WriteString("]")
+	}
+	return buffer.String()
+}
+
 // ExplainInfo implements Plan interface.
 func (p *LogicalSelection) ExplainInfo() string {
 	return string(expression.SortedExplainExpressionList(p.Conditions))
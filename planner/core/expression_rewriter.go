@@ -15,6 +15,7 @@ package core
 
 import (
 	"context"
+	"strconv"
 	"strings"
 
 	"github.com/pingcap/errors"
@@ -43,13 +44,38 @@ func evalAstExpr(sctx sessionctx.Context, expr ast.ExprNode) (types.Datum, error
 	}
 	b := NewPlanBuilder(sctx, is)
 	fakePlan := LogicalTableDual{}.Init(sctx)
-	newExpr, _, err := b.rewrite(context.TODO(), expr, fakePlan, nil, true)
+	newExpr, np, err := b.rewrite(context.TODO(), expr, fakePlan, nil, true)
 	if err != nil {
 		return types.Datum{}, err
 	}
+	if np != fakePlan {
+		return types.Datum{}, errors.New("subqueries can't be evaluated directly")
+	}
 	return newExpr.Eval(chunk.Row{})
 }
 
+// rewriteAstExpr rewrites ast expression directly, the columns are resolved in the schema.
+func rewriteAstExpr(sctx sessionctx.Context, expr ast.ExprNode, schema *expression.Schema, names types.NameSlice) (expression.Expression, error) {
+	var is infoschema.InfoSchema
+	if sctx.GetSessionVars().TxnCtx.InfoSchema != nil {
+		is = sctx.GetSessionVars().TxnCtx.InfoSchema.(infoschema.InfoSchema)
+	}
+	b := NewPlanBuilder(sctx, is)
+	fakePlan := LogicalTableDual{}.Init(sctx)
+	if schema != nil {
+		fakePlan.schema = schema
+		fakePlan.names = names
+	}
+	newExpr, np, err := b.rewrite(context.TODO(), expr, fakePlan, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if np != fakePlan {
+		return nil, errors.New("subqueries can't be rewritten directly")
+	}
+	return newExpr, nil
+}
+
 // rewrite function rewrites ast expr to expression.Expression.
 // aggMapper maps ast.AggregateFuncExpr to the columns offset in p's output schema.
 // asScalar means whether this expression must be treated as a scalar expression.
@@ -187,7 +213,7 @@ func (er *expressionRewriter) constructBinaryOpFunction(l expression.Expression,
 		return nil, expression.ErrOperandColumns.GenWithStackByArgs(lLen)
 	}
 	switch op {
-	case ast.EQ, ast.NE:
+	case ast.EQ, ast.NE, ast.NullEQ:
 		funcs := make([]expression.Expression, lLen)
 		for i := 0; i < lLen; i++ {
 			var err error
@@ -231,6 +257,10 @@ func (er *expressionRewriter) constructBinaryOpFunction(l expression.Expression,
 func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	switch v := inNode.(type) {
 	case *ast.AggregateFuncExpr:
+		if corCol, ok := er.b.correlatedAggMapper[v]; ok {
+			er.ctxStackAppend(corCol, types.EmptyName)
+			return inNode, true
+		}
 		index, ok := -1, false
 		if er.aggrMap != nil {
 			index, ok = er.aggrMap[v]
@@ -283,6 +313,16 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 		col := schema.Columns[idx]
 		er.ctxStackAppend(expression.NewValuesFunc(er.sctx, col.Index, col.RetType), types.EmptyName)
 		return inNode, true
+	case *ast.SubqueryExpr:
+		return er.handleScalarSubquery(v)
+	case *ast.PositionExpr:
+		// The position refers to the output column of the projection built from the select fields.
+		if v.N < 1 || v.N > er.schema.Len() {
+			er.err = ErrUnknownColumn.GenWithStackByArgs(strconv.Itoa(v.N), clauseMsg[er.b.curClause])
+			return inNode, true
+		}
+		er.ctxStackAppend(er.schema.Columns[v.N-1], er.names[v.N-1])
+		return inNode, true
 	case *ast.FuncCallExpr:
 	default:
 		er.asScalar = true
@@ -290,6 +330,61 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	return inNode, false
 }
 
+func (er *expressionRewriter) buildSubquery(subq *ast.SubqueryExpr) (LogicalPlan, error) {
+	er.b.outerSchemas = append(er.b.outerSchemas, er.schema)
+	er.b.outerNames = append(er.b.outerNames, er.names)
+	defer func() {
+		er.b.outerSchemas = er.b.outerSchemas[0 : len(er.b.outerSchemas)-1]
+		er.b.outerNames = er.b.outerNames[0 : len(er.b.outerNames)-1]
+	}()
+	// The clause of the outer query is restored after building the subquery.
+	defer func(clause clauseCode) { er.b.curClause = clause }(er.b.curClause)
+
+	np, err := er.b.buildResultSetNode(er.ctx, subq.Query)
+	if err != nil {
+		return nil, err
+	}
+	// Pop the handle map generated by the subquery.
+	er.b.handleHelper.popMap()
+	return np, nil
+}
+
+// handleScalarSubquery builds the scalar subquery, which must return no more than one row.
+// If the subquery is correlated, it's joined with the outer plan by an apply, otherwise by a left outer join.
+func (er *expressionRewriter) handleScalarSubquery(v *ast.SubqueryExpr) (ast.Node, bool) {
+	if er.p == nil {
+		er.err = ErrUnsupportedType.GenWithStack("Unsupported subquery here")
+		return v, true
+	}
+	np, err := er.buildSubquery(v)
+	if err != nil {
+		er.err = err
+		return v, true
+	}
+	np = er.b.buildMaxOneRow(np)
+	if len(ExtractCorColumnsBySchema4LogicalPlan(np, er.p.Schema())) > 0 {
+		v.Correlated = true
+		er.p = er.b.buildApplyWithJoinType(er.p, np, LeftOuterJoin)
+	} else {
+		er.p = er.b.buildJoinWithSubquery(er.p, np)
+	}
+	if np.Schema().Len() > 1 {
+		newCols := make([]expression.Expression, 0, np.Schema().Len())
+		for _, col := range np.Schema().Columns {
+			newCols = append(newCols, col)
+		}
+		expr, err := er.newFunction(ast.RowFunc, newCols[0].GetType(), newCols...)
+		if err != nil {
+			er.err = err
+			return v, true
+		}
+		er.ctxStackAppend(expr, types.EmptyName)
+		return v, true
+	}
+	er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], np.OutputNames()[0])
+	return v, true
+}
+
 // Leave implements Visitor interface.
 func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok bool) {
 	if er.err != nil {
@@ -300,9 +395,12 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		inNode = er.preprocess(inNode)
 	}
 	switch v := inNode.(type) {
-	case *ast.AggregateFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ValuesExpr:
+	case *ast.AggregateFuncExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ValuesExpr, *ast.SubqueryExpr, *ast.PositionExpr:
 	case *driver.ValueExpr:
 		value := &expression.Constant{Value: v.Datum, RetType: &v.Type}
+		if er.b.planCacheStmt != nil {
+			er.b.planCacheStmt.addParamConst(v, value)
+		}
 		er.ctxStackAppend(value, types.EmptyName)
 	case *ast.VariableExpr:
 		er.rewriteVariable(v)
@@ -322,6 +420,10 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 		er.inToExpression(len(v.List), v.Not, &v.Type)
 	case *ast.IsNullExpr:
 		er.isNullToExpression(v)
+	case *ast.PatternLikeExpr:
+		er.likeToScalarFunc(v)
+	case *ast.PatternRegexpExpr:
+		er.regexpToScalarFunc(v)
 	case *ast.DefaultExpr:
 		er.evalDefaultExpr(v)
 	default:
@@ -352,9 +454,9 @@ func (er *expressionRewriter) rewriteVariable(v *ast.VariableExpr) {
 			er.ctxNameStk[stkLen-1] = types.EmptyName
 			return
 		}
+		// The type is decided by the current value of the variable.
 		f, err := er.newFunction(ast.GetVar,
-			// TODO: Here is wrong, the sessionVars should store a name -> Datum map. Will fix it later.
-			types.NewFieldType(mysql.TypeString),
+			types.NewFieldType(mysql.TypeUnspecified),
 			expression.DatumToConstant(types.NewStringDatum(name), mysql.TypeString))
 		if err != nil {
 			er.err = err
@@ -469,6 +571,29 @@ func (er *expressionRewriter) isNullToExpression(v *ast.IsNullExpr) {
 	er.ctxStackAppend(function, types.EmptyName)
 }
 
+func (er *expressionRewriter) likeToScalarFunc(v *ast.PatternLikeExpr) {
+	l := len(er.ctxStack)
+	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[l-2:]...)
+	if er.err != nil {
+		return
+	}
+	escape := &expression.Constant{Value: types.NewIntDatum(int64(v.Escape)), RetType: types.NewFieldType(mysql.TypeLonglong)}
+	function := er.notToExpression(v.Not, ast.Like, &v.Type, er.ctxStack[l-2], er.ctxStack[l-1], escape)
+	er.ctxStackPop(2)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
+func (er *expressionRewriter) regexpToScalarFunc(v *ast.PatternRegexpExpr) {
+	l := len(er.ctxStack)
+	er.err = expression.CheckArgsNotMultiColumnRow(er.ctxStack[l-2:]...)
+	if er.err != nil {
+		return
+	}
+	function := er.notToExpression(v.Not, ast.Regexp, &v.Type, er.ctxStack[l-2], er.ctxStack[l-1])
+	er.ctxStackPop(2)
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 // inToExpression converts in expression to a scalar function. The argument lLen means the length of in list.
 // The argument not means if the expression is not in. The tp stands for the expression type, which is always bool.
 // a in (b, c, d) will be rewritten as `(a = b) or (a = c) or (a = d)`.
@@ -599,11 +724,50 @@ func (er *expressionRewriter) rewriteFuncCall(v *ast.FuncCallExpr) bool {
 		}
 
 		return false
+	case ast.Grouping:
+		er.rewriteGrouping(v)
+		return true
 	default:
 		return false
 	}
 }
 
+// rewriteGrouping rewrites GROUPING(a, b) to grouping(gid, pos(a), pos(b)), where gid is the
+// grouping ID of ROLLUP and pos(x) is the bit of x in it.
+func (er *expressionRewriter) rewriteGrouping(v *ast.FuncCallExpr) {
+	stackLen := len(er.ctxStack)
+	args := er.ctxStack[stackLen-len(v.Args):]
+	gidIdx := -1
+	if er.b.rollupGID != nil {
+		gidIdx = er.schema.ColumnIndex(er.b.rollupGID)
+	}
+	if gidIdx < 0 {
+		er.err = ErrInvalidGroupFuncUse.GenWithStackByArgs()
+		return
+	}
+	newArgs := make([]expression.Expression, 0, len(args)+1)
+	newArgs = append(newArgs, er.schema.Columns[gidIdx])
+	for i, arg := range args {
+		col, ok := arg.(*expression.Column)
+		var pos int
+		if ok {
+			pos, ok = er.b.rollupGroupingCols[col.UniqueID]
+		}
+		if !ok {
+			er.err = ErrFieldInGroupingNotGroupBy.GenWithStackByArgs(i + 1)
+			return
+		}
+		newArgs = append(newArgs, &expression.Constant{Value: types.NewIntDatum(int64(pos)), RetType: types.NewFieldType(mysql.TypeLonglong)})
+	}
+	function, err := er.newFunction(ast.Grouping, types.NewFieldType(mysql.TypeLonglong), newArgs...)
+	if err != nil {
+		er.err = err
+		return
+	}
+	er.ctxStackPop(len(v.Args))
+	er.ctxStackAppend(function, types.EmptyName)
+}
+
 func (er *expressionRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
 	stackLen := len(er.ctxStack)
 	args := er.ctxStack[stackLen-len(v.Args):]
@@ -633,12 +797,52 @@ func (er *expressionRewriter) toColumn(v *ast.ColumnName) {
 		er.ctxStackAppend(column, er.names[idx])
 		return
 	}
+	// The column may be eliminated by the USING clause or natural join.
+	if name, column, err := findColFromNaturalUsingJoin(er.p, v); err != nil {
+		er.err = ErrAmbiguous.GenWithStackByArgs(v.Name, clauseMsg[fieldList])
+		return
+	} else if column != nil {
+		er.ctxStackAppend(column, name)
+		return
+	}
+	// The column may refer to an outer query block, it's a correlated column then.
+	for i := len(er.b.outerSchemas) - 1; i >= 0; i-- {
+		outerSchema, outerName := er.b.outerSchemas[i], er.b.outerNames[i]
+		idx, err = expression.FindFieldName(outerName, v)
+		if err != nil {
+			er.err = ErrAmbiguous.GenWithStackByArgs(v.Name, clauseMsg[fieldList])
+			return
+		}
+		if idx >= 0 {
+			column := outerSchema.Columns[idx]
+			er.ctxStackAppend(&expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, outerName[idx])
+			return
+		}
+	}
 	if er.b.curClause == globalOrderByClause {
 		er.b.curClause = orderByClause
 	}
 	er.err = ErrUnknownColumn.GenWithStackByArgs(v.String(), clauseMsg[er.b.curClause])
 }
 
+func findColFromNaturalUsingJoin(p LogicalPlan, col *ast.ColumnName) (name *types.FieldName, column *expression.Column, err error) {
+	switch x := p.(type) {
+	case *LogicalLimit, *LogicalSelection, *LogicalTopN, *LogicalSort:
+		return findColFromNaturalUsingJoin(p.Children()[0], col)
+	case *LogicalJoin:
+		if x.redundantSchema != nil {
+			idx, err := expression.FindFieldName(x.redundantNames, col)
+			if err != nil {
+				return nil, nil, err
+			}
+			if idx >= 0 {
+				return x.redundantNames[idx], x.redundantSchema.Columns[idx], nil
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
 func (er *expressionRewriter) evalDefaultExpr(v *ast.DefaultExpr) {
 	stkLen := len(er.ctxStack)
 	name := er.ctxNameStk[stkLen-1]
@@ -14,6 +14,7 @@
 package core
 
 import (
+	"fmt"
 	"math"
 
 	"github.com/pingcap/tidb/expression"
@@ -24,6 +25,7 @@ import (
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/tracing"
 	"golang.org/x/tools/container/intsets"
 )
 
@@ -272,13 +274,12 @@ func (ds *DataSource) getTableCandidate(path *util.AccessPath, prop *property.Ph
 
 func (ds *DataSource) getIndexCandidate(path *util.AccessPath, prop *property.PhysicalProperty, isSingleScan bool) *candidatePath {
 	candidate := &candidatePath{path: path}
-	all, _ := prop.AllSameOrder()
-	// When the prop is empty or `all` is false, `isMatchProp` is better to be `false` because
+	// When the prop is empty, `isMatchProp` is better to be `false` because
 	// it needs not to keep order for index scan.
-	if !prop.IsEmpty() && all {
+	if !prop.IsEmpty() {
 		for i, col := range path.IdxCols {
 			if col.Equal(nil, prop.Items[0].Col) {
-				candidate.isMatchProp = matchIndicesProp(path.IdxCols[i:], path.IdxColLens[i:], prop.Items)
+				candidate.isMatchProp = matchIndicesProp(path.IdxCols[i:], path.IdxColLens[i:], indexColDescs(path.Index, i), prop.Items)
 				break
 			} else if i >= path.EqCondCount {
 				break
@@ -292,9 +293,15 @@ func (ds *DataSource) getIndexCandidate(path *util.AccessPath, prop *property.Ph
 
 // skylinePruning prunes access paths according to different factors. An access path can be pruned only if
 // there exists a path that is not worse than it at all factors and there is at least one better factor.
-func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candidatePath {
+// The pruned paths are recorded into dsTrace if it's not nil.
+func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty, dsTrace *tracing.DataSourceTrace) []*candidatePath {
 	candidates := make([]*candidatePath, 0, 4)
 	for _, path := range ds.possibleAccessPaths {
+		var pathTrace *tracing.CandidatePathTrace
+		if dsTrace != nil {
+			pathTrace = newCandidatePathTrace(path)
+			dsTrace.AppendCandidate(pathTrace)
+		}
 		// if we already know the range of the scan is empty, just return a TableDual
 		if len(path.Ranges) == 0 {
 			return []*candidatePath{{path: path}}
@@ -312,6 +319,10 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 				// 4. The needed columns are all covered by index columns(and handleCol).
 				currentCandidate = ds.getIndexCandidate(path, prop, coveredByIdx)
 			} else {
+				if pathTrace != nil {
+					pathTrace.Pruned = true
+					pathTrace.Reason = "no access condition, no required order and not covering"
+				}
 				continue
 			}
 		}
@@ -324,9 +335,18 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 			result := compareCandidates(candidates[i], currentCandidate)
 			if result == 1 {
 				pruned = true
+				if pathTrace != nil {
+					pathTrace.Pruned = true
+					pathTrace.Reason = fmt.Sprintf("dominated by %s", pathName(candidates[i].path))
+				}
 				// We can break here because the current candidate cannot prune others anymore.
 				break
 			} else if result == -1 {
+				if dsTrace != nil {
+					prunedTrace := dsTrace.Candidate(pathName(candidates[i].path))
+					prunedTrace.Pruned = true
+					prunedTrace.Reason = fmt.Sprintf("dominated by %s", pathName(path))
+				}
 				candidates = append(candidates[:i], candidates[i+1:]...)
 			}
 		}
@@ -337,6 +357,59 @@ func (ds *DataSource) skylinePruning(prop *property.PhysicalProperty) []*candida
 	return candidates
 }
 
+// pathName returns the name of the access path used in the optimizer trace.
+func pathName(path *util.AccessPath) string {
+	if path.IsTablePath {
+		return "PRIMARY_KEY"
+	}
+	return path.Index.Name.O
+}
+
+func newCandidatePathTrace(path *util.AccessPath) *tracing.CandidatePathTrace {
+	pathTrace := &tracing.CandidatePathTrace{
+		Path:        pathName(path),
+		AccessConds: make([]string, 0, len(path.AccessConds)),
+		Ranges:      make([]string, 0, len(path.Ranges)),
+		RowCount:    path.CountAfterAccess,
+	}
+	for _, cond := range path.AccessConds {
+		pathTrace.AccessConds = append(pathTrace.AccessConds, cond.String())
+	}
+	for _, ran := range path.Ranges {
+		pathTrace.Ranges = append(pathTrace.Ranges, ran.String())
+	}
+	return pathTrace
+}
+
+// appendTrace starts recording the access paths of the DataSource under the property, it returns nil
+// if the optimizer trace is not required.
+func (ds *DataSource) appendTrace(prop *property.PhysicalProperty) *tracing.DataSourceTrace {
+	tracer := ds.ctx.GetSessionVars().StmtCtx.OptimTracer
+	if tracer == nil {
+		return nil
+	}
+	tblName := ds.tableInfo.Name.O
+	if ds.TableAsName != nil && ds.TableAsName.O != "" {
+		tblName = ds.TableAsName.O
+	}
+	return tracer.AppendDataSource(tblName, prop.String())
+}
+
+// recordTaskCost records the cost of the task built from the candidate path, and marks it pruned if
+// the task cannot satisfy the required property.
+func recordTaskCost(dsTrace *tracing.DataSourceTrace, path *util.AccessPath, t task) {
+	if dsTrace == nil {
+		return
+	}
+	pathTrace := dsTrace.Candidate(pathName(path))
+	if t.invalid() {
+		pathTrace.Pruned = true
+		pathTrace.Reason = "cannot satisfy the required property"
+		return
+	}
+	pathTrace.Cost = t.cost()
+}
+
 // findBestTask implements the PhysicalPlan interface.
 // It will enumerate all the available indices and choose a plan with least cost.
 func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err error) {
@@ -379,12 +452,16 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 	}
 
 	t = invalidTask
-	candidates := ds.skylinePruning(prop)
+	dsTrace := ds.appendTrace(prop)
+	candidates := ds.skylinePruning(prop, dsTrace)
 
 	for _, candidate := range candidates {
 		path := candidate.path
 		// if we already know the range of the scan is empty, just return a TableDual
 		if len(path.Ranges) == 0 {
+			if dsTrace != nil {
+				dsTrace.Chosen = "TableDual"
+			}
 			dual := PhysicalTableDual{}.Init(ds.ctx, ds.stats)
 			dual.SetSchema(ds.schema)
 			return &rootTask{
@@ -396,8 +473,12 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 			if err != nil {
 				return nil, err
 			}
+			recordTaskCost(dsTrace, path, tblTask)
 			if tblTask.cost() < t.cost() {
 				t = tblTask
+				if dsTrace != nil {
+					dsTrace.Chosen = pathName(path)
+				}
 			}
 			continue
 		}
@@ -405,8 +486,12 @@ func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err
 		if err != nil {
 			return nil, err
 		}
+		recordTaskCost(dsTrace, path, idxTask)
 		if idxTask.cost() < t.cost() {
 			t = idxTask
+			if dsTrace != nil {
+				dsTrace.Chosen = pathName(path)
+			}
 		}
 	}
 
@@ -479,6 +564,7 @@ func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty, candid
 			TableAsName: ds.TableAsName,
 		}.Init(ds.ctx)
 		ts.SetSchema(ds.schema.Clone())
+		ts.Columns = ExpandVirtualColumn(ts.Columns, ts.schema, ts.Table.Columns)
 		cop.tablePlan = ts
 	}
 	cop.cst = cost
@@ -575,6 +661,7 @@ func (is *PhysicalIndexScan) addPushedDownSelection(copTask *copTask, p *DataSou
 		indexSel.SetChildren(is)
 		copTask.indexPlan = indexSel
 	}
+	tableConds, copTask.rootTaskConds = splitSelCondsWithVirtualColumn(tableConds)
 	if len(tableConds) > 0 {
 		copTask.finishIndexPlan()
 		copTask.cst += copTask.count() * sessVars.CopCPUFactor
@@ -584,18 +671,48 @@ func (is *PhysicalIndexScan) addPushedDownSelection(copTask *copTask, p *DataSou
 	}
 }
 
-func matchIndicesProp(idxCols []*expression.Column, colLens []int, propItems []property.Item) bool {
+func matchIndicesProp(idxCols []*expression.Column, colLens []int, colDescs []bool, propItems []property.Item) bool {
 	if len(idxCols) < len(propItems) {
 		return false
 	}
+	// The index is scanned in a single direction, so every item must be either in the same
+	// order as its index column, or all of them must be in the reverse order.
+	reverse := propItems[0].Desc != isDescAt(colDescs, 0)
 	for i, item := range propItems {
 		if colLens[i] != types.UnspecifiedLength || !item.Col.Equal(nil, idxCols[i]) {
 			return false
 		}
+		if (item.Desc != isDescAt(colDescs, i)) != reverse {
+			return false
+		}
 	}
 	return true
 }
 
+// indexColDescs returns whether each of the index columns from offset is in descending order.
+func indexColDescs(idx *model.IndexInfo, offset int) []bool {
+	descs := idx.DescColumns()
+	if offset >= len(descs) {
+		return nil
+	}
+	return descs[offset:]
+}
+
+func isDescAt(descs []bool, i int) bool {
+	return i < len(descs) && descs[i]
+}
+
+// isIndexScanDesc checks whether the index needs to be scanned in descending order to
+// satisfy the property, which is already matched by the index columns.
+func isIndexScanDesc(idxCols []*expression.Column, idx *model.IndexInfo, prop *property.PhysicalProperty) bool {
+	for i, col := range idxCols {
+		if col.Equal(nil, prop.Items[0].Col) {
+			return prop.Items[0].Desc != isDescAt(idx.DescColumns(), i)
+		}
+	}
+	return prop.Items[0].Desc
+}
+
 func splitIndexFilterConditions(conditions []expression.Expression, indexColumns []*expression.Column, idxColLens []int,
 	table *model.TableInfo) (indexConds, tableConds []expression.Expression) {
 	var indexConditions, tableConditions []expression.Expression
@@ -677,6 +794,7 @@ func (ds *DataSource) convertToTableScan(prop *property.PhysicalProperty, candid
 func (ts *PhysicalTableScan) addPushedDownSelection(copTask *copTask, stats *property.StatsInfo) {
 	// Add filter condition to table plan now.
 	sessVars := ts.ctx.GetSessionVars()
+	ts.filterCondition, copTask.rootTaskConds = splitSelCondsWithVirtualColumn(ts.filterCondition)
 	if len(ts.filterCondition) > 0 {
 		copTask.cst += copTask.count() * sessVars.CopCPUFactor
 		sel := PhysicalSelection{Conditions: ts.filterCondition}.Init(ts.ctx, stats)
@@ -685,6 +803,51 @@ func (ts *PhysicalTableScan) addPushedDownSelection(copTask *copTask, stats *pro
 	}
 }
 
+// ExpandVirtualColumn adds the columns which the virtual columns depend on to the columns and the
+// schema of the table scan, the virtual columns are evaluated on them after the rows are read.
+func ExpandVirtualColumn(columns []*model.ColumnInfo, schema *expression.Schema, colsInfo []*model.ColumnInfo) []*model.ColumnInfo {
+	copyColumn := make([]*model.ColumnInfo, len(columns))
+	copy(copyColumn, columns)
+	// The extra handle column is kept at the end of the columns.
+	var extraColumn *expression.Column
+	var extraColumnModel *model.ColumnInfo
+	if schema.Columns[len(schema.Columns)-1].ID == model.ExtraHandleID {
+		extraColumn = schema.Columns[len(schema.Columns)-1]
+		extraColumnModel = copyColumn[len(copyColumn)-1]
+		schema.Columns = schema.Columns[:len(schema.Columns)-1]
+		copyColumn = copyColumn[:len(copyColumn)-1]
+	}
+	for _, col := range schema.Columns {
+		if col.VirtualExpr == nil {
+			continue
+		}
+		for _, baseCol := range expression.ExtractColumns(col.VirtualExpr) {
+			if !schema.Contains(baseCol) {
+				schema.Columns = append(schema.Columns, baseCol)
+				copyColumn = append(copyColumn, findColumnInfoByID(colsInfo, baseCol.ID))
+			}
+		}
+	}
+	if extraColumn != nil {
+		schema.Columns = append(schema.Columns, extraColumn)
+		copyColumn = append(copyColumn, extraColumnModel)
+	}
+	return copyColumn
+}
+
+// splitSelCondsWithVirtualColumn splits out the conditions on the virtual columns, they are
+// evaluated by TiDB since the virtual columns are not stored.
+func splitSelCondsWithVirtualColumn(conds []expression.Expression) (pushedConds, virtualConds []expression.Expression) {
+	for _, cond := range conds {
+		if expression.ContainVirtualColumn([]expression.Expression{cond}) {
+			virtualConds = append(virtualConds, cond)
+		} else {
+			pushedConds = append(pushedConds, cond)
+		}
+	}
+	return pushedConds, virtualConds
+}
+
 func (ds *DataSource) getOriginalPhysicalTableScan(prop *property.PhysicalProperty, path *util.AccessPath, isMatchProp bool) (*PhysicalTableScan, float64, float64) {
 	ts := PhysicalTableScan{
 		Table:           ds.tableInfo,
@@ -696,6 +859,7 @@ func (ds *DataSource) getOriginalPhysicalTableScan(prop *property.PhysicalProper
 		filterCondition: path.TableFilters,
 	}.Init(ds.ctx)
 	ts.SetSchema(ds.schema.Clone())
+	ts.Columns = ExpandVirtualColumn(ts.Columns, ts.schema, ts.Table.Columns)
 	rowCount := path.CountAfterAccess
 	// Only use expectedCnt when it's smaller than the count we calculated.
 	// e.g. IndexScan(count1)->After Filter(count2). The `ds.stats.RowCount` is count2. count1 is the one we need to calculate
@@ -752,7 +916,7 @@ func (ds *DataSource) getOriginalPhysicalIndexScan(prop *property.PhysicalProper
 	sessVars := ds.ctx.GetSessionVars()
 	cost := rowCount * rowSize * sessVars.ScanFactor
 	if isMatchProp {
-		if prop.Items[0].Desc {
+		if isIndexScanDesc(path.IdxCols, idx, prop) {
 			is.Desc = true
 			cost = rowCount * rowSize * sessVars.DescScanFactor
 		}
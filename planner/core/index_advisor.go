@@ -0,0 +1,295 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// maxIndexCandidateCols is the max number of the columns of an index candidate.
+const maxIndexCandidateCols = 3
+
+// IndexCandidate is a hypothetical index which may speed up a statement.
+type IndexCandidate struct {
+	DBName  model.CIStr
+	Table   *model.TableInfo
+	Columns []*model.ColumnInfo
+}
+
+// Name returns the name of the index candidate, like `idx_a_b`.
+func (c *IndexCandidate) Name() string {
+	names := make([]string, 0, len(c.Columns)+1)
+	names = append(names, "idx")
+	for _, col := range c.Columns {
+		names = append(names, col.Name.L)
+	}
+	return strings.Join(names, "_")
+}
+
+// ColumnNames returns the comma separated column names of the index candidate.
+func (c *IndexCandidate) ColumnNames() string {
+	names := make([]string, 0, len(c.Columns))
+	for _, col := range c.Columns {
+		names = append(names, col.Name.O)
+	}
+	return strings.Join(names, ",")
+}
+
+// HypoIndexInfo returns the info of the hypothetical index for the candidate.
+func (c *IndexCandidate) HypoIndexInfo() *model.IndexInfo {
+	return NewHypoIndexInfo(c.Table, model.NewCIStr(c.Name()), c.Columns)
+}
+
+// NewHypoIndexInfo builds the info of a hypothetical index on the columns of the table.
+func NewHypoIndexInfo(tblInfo *model.TableInfo, name model.CIStr, cols []*model.ColumnInfo) *model.IndexInfo {
+	idxCols := make([]*model.IndexColumn, 0, len(cols))
+	for _, col := range cols {
+		idxCols = append(idxCols, &model.IndexColumn{
+			Name:   col.Name,
+			Offset: col.Offset,
+			Length: types.UnspecifiedLength,
+		})
+	}
+	return &model.IndexInfo{
+		// The hypothetical indexes have no statistics, any ID which is not used by the real indexes works.
+		ID:      tblInfo.MaxIndexID + 1,
+		Name:    name,
+		Table:   tblInfo.Name,
+		Columns: idxCols,
+		State:   model.StatePublic,
+		Tp:      model.IndexTypeBtree,
+	}
+}
+
+// ExtractIndexCandidates returns the indexes which may speed up the logical plan: the indexes on
+// the columns compared with constants by the filters, and the indexes on the join keys, which
+// provide the orders for the merge joins. The candidates which are prefixes of the existing
+// indexes are skipped.
+func ExtractIndexCandidates(ctx context.Context, flag uint64, logic LogicalPlan) ([]*IndexCandidate, error) {
+	// The filters are pushed down to the data sources by the logical optimization.
+	logic, err := logicalOptimize(ctx, flag, logic)
+	if err != nil {
+		return nil, err
+	}
+	e := &indexCandidateExtractor{sources: make(map[*DataSource]*indexSourceCols)}
+	e.extract(logic)
+	var candidates []*IndexCandidate
+	for _, source := range e.order {
+		candidates = append(candidates, source.candidates()...)
+	}
+	return candidates, nil
+}
+
+// indexSourceCols are the columns of a data source which may be used by the indexes.
+type indexSourceCols struct {
+	ds *DataSource
+	// eqCols are compared by the equal conditions or the join keys.
+	eqCols []*model.ColumnInfo
+	// rangeCols are compared by the range conditions.
+	rangeCols []*model.ColumnInfo
+}
+
+type indexCandidateExtractor struct {
+	dataSources []*DataSource
+	sources     map[*DataSource]*indexSourceCols
+	// order keeps the candidates in the order of the data sources.
+	order []*indexSourceCols
+}
+
+func (e *indexCandidateExtractor) extract(p LogicalPlan) {
+	for _, child := range p.Children() {
+		e.extract(child)
+	}
+	switch x := p.(type) {
+	case *DataSource:
+		e.dataSources = append(e.dataSources, x)
+		for _, cond := range x.pushedDownConds {
+			e.addFilter(x, cond)
+		}
+	case *LogicalJoin:
+		for _, cond := range x.EqualConditions {
+			for _, arg := range cond.GetArgs() {
+				if col, ok := arg.(*expression.Column); ok {
+					e.addColumn(col, true)
+				}
+			}
+		}
+	}
+}
+
+func (e *indexCandidateExtractor) addFilter(ds *DataSource, cond expression.Expression) {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok {
+		return
+	}
+	args := sf.GetArgs()
+	switch sf.FuncName.L {
+	case ast.EQ:
+		if isIndexConstant(args[1]) {
+			e.addSourceColumn(ds, args[0], true)
+		} else if isIndexConstant(args[0]) {
+			e.addSourceColumn(ds, args[1], true)
+		}
+	case ast.In:
+		for _, arg := range args[1:] {
+			if !isIndexConstant(arg) {
+				return
+			}
+		}
+		e.addSourceColumn(ds, args[0], true)
+	case ast.LT, ast.LE, ast.GT, ast.GE:
+		if isIndexConstant(args[1]) {
+			e.addSourceColumn(ds, args[0], false)
+		} else if isIndexConstant(args[0]) {
+			e.addSourceColumn(ds, args[1], false)
+		}
+	}
+}
+
+// isIndexConstant checks whether the expression is a constant when the index is accessed, the
+// correlated columns are constants for the inner side of an apply.
+func isIndexConstant(expr expression.Expression) bool {
+	switch expr.(type) {
+	case *expression.Constant, *expression.CorrelatedColumn:
+		return true
+	}
+	return false
+}
+
+func (e *indexCandidateExtractor) addSourceColumn(ds *DataSource, expr expression.Expression, isEq bool) {
+	col, ok := expr.(*expression.Column)
+	if !ok {
+		return
+	}
+	for i, schemaCol := range ds.schema.Columns {
+		if schemaCol.UniqueID == col.UniqueID {
+			e.addColumnInfo(ds, ds.Columns[i], isEq)
+			return
+		}
+	}
+}
+
+// addColumn adds a join key, which may come from any of the data sources.
+func (e *indexCandidateExtractor) addColumn(col *expression.Column, isEq bool) {
+	for _, ds := range e.dataSources {
+		e.addSourceColumn(ds, col, isEq)
+	}
+}
+
+func (e *indexCandidateExtractor) addColumnInfo(ds *DataSource, colInfo *model.ColumnInfo, isEq bool) {
+	// The handle is the fastest way to access the rows, and the blob columns can only be indexed by prefixes.
+	if colInfo.ID == model.ExtraHandleID || (ds.tableInfo.PKIsHandle && mysql.HasPriKeyFlag(colInfo.Flag)) ||
+		types.IsTypeBlob(colInfo.Tp) {
+		return
+	}
+	source, ok := e.sources[ds]
+	if !ok {
+		source = &indexSourceCols{ds: ds}
+		e.sources[ds] = source
+		e.order = append(e.order, source)
+	}
+	if isEq {
+		source.eqCols = appendColumnInfo(source.eqCols, colInfo)
+	} else {
+		source.rangeCols = appendColumnInfo(source.rangeCols, colInfo)
+	}
+}
+
+func appendColumnInfo(cols []*model.ColumnInfo, col *model.ColumnInfo) []*model.ColumnInfo {
+	if containsColumnInfo(cols, col) {
+		return cols
+	}
+	return append(cols, col)
+}
+
+// candidates returns the single column indexes on all the columns, and the multi-column index
+// which has the equal columns followed by a range column.
+func (s *indexSourceCols) candidates() []*IndexCandidate {
+	var colsList [][]*model.ColumnInfo
+	for _, col := range s.eqCols {
+		colsList = append(colsList, []*model.ColumnInfo{col})
+	}
+	for _, col := range s.rangeCols {
+		if !containsColumnInfo(s.eqCols, col) {
+			colsList = append(colsList, []*model.ColumnInfo{col})
+		}
+	}
+	var multiCols []*model.ColumnInfo
+	for _, col := range s.eqCols {
+		if len(multiCols) < maxIndexCandidateCols {
+			multiCols = append(multiCols, col)
+		}
+	}
+	for _, col := range s.rangeCols {
+		if !containsColumnInfo(multiCols, col) {
+			if len(multiCols) == maxIndexCandidateCols {
+				multiCols = multiCols[:maxIndexCandidateCols-1]
+			}
+			multiCols = append(multiCols, col)
+			break
+		}
+	}
+	if len(multiCols) > 1 {
+		colsList = append(colsList, multiCols)
+	}
+
+	candidates := make([]*IndexCandidate, 0, len(colsList))
+	for _, cols := range colsList {
+		if s.coveredByIndex(cols) {
+			continue
+		}
+		candidates = append(candidates, &IndexCandidate{
+			DBName:  s.ds.DBName,
+			Table:   s.ds.tableInfo,
+			Columns: cols,
+		})
+	}
+	return candidates
+}
+
+// coveredByIndex checks whether the columns are a prefix of an existing index.
+func (s *indexSourceCols) coveredByIndex(cols []*model.ColumnInfo) bool {
+	for _, idx := range s.ds.tableInfo.Indices {
+		if idx.State != model.StatePublic || len(idx.Columns) < len(cols) {
+			continue
+		}
+		covered := true
+		for i, col := range cols {
+			if idx.Columns[i].Offset != col.Offset || idx.Columns[i].Length != types.UnspecifiedLength {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			return true
+		}
+	}
+	return false
+}
+
+func containsColumnInfo(cols []*model.ColumnInfo, col *model.ColumnInfo) bool {
+	for _, c := range cols {
+		if c.ID == col.ID {
+			return true
+		}
+	}
+	return false
+}
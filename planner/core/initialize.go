@@ -23,6 +23,8 @@ const (
 	TypeSel = "Selection"
 	// TypeProj is the type of Projection.
 	TypeProj = "Projection"
+	// TypeExpand is the type of Expand.
+	TypeExpand = "Expand"
 	// TypeAgg is the type of Aggregation.
 	TypeAgg = "Aggregation"
 	// TypeHashAgg is the type of HashAgg.
@@ -63,6 +65,8 @@ const (
 	TypeDelete = "Delete"
 	// TypeIndexLookUp is the type of IndexLookUp.
 	TypeIndexLookUp = "IndexLookUp"
+	// TypeTableLookUp is the type of TableLookUp.
+	TypeTableLookUp = "TableLookUp"
 	// TypeTableReader is the type of TableReader.
 	TypeTableReader = "TableReader"
 	// TypeIndexReader is the type of IndexReader.
@@ -85,6 +89,18 @@ func (p LogicalJoin) Init(ctx sessionctx.Context) *LogicalJoin {
 	return &p
 }
 
+// Init initializes LogicalApply.
+func (la LogicalApply) Init(ctx sessionctx.Context) *LogicalApply {
+	la.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeApply, &la)
+	return &la
+}
+
+// Init initializes LogicalMaxOneRow.
+func (p LogicalMaxOneRow) Init(ctx sessionctx.Context) *LogicalMaxOneRow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeMaxOneRow, &p)
+	return &p
+}
+
 // Init initializes DataSource.
 func (ds DataSource) Init(ctx sessionctx.Context) *DataSource {
 	ds.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeTableScan, &ds)
@@ -143,6 +159,20 @@ func (p PhysicalProjection) Init(ctx sessionctx.Context, stats *property.StatsIn
 	return &p
 }
 
+// Init initializes LogicalExpand.
+func (p LogicalExpand) Init(ctx sessionctx.Context) *LogicalExpand {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeExpand, &p)
+	return &p
+}
+
+// Init initializes PhysicalExpand.
+func (p PhysicalExpand) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalExpand {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeExpand, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes LogicalSort.
 func (ls LogicalSort) Init(ctx sessionctx.Context) *LogicalSort {
 	ls.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeSort, &ls)
@@ -192,6 +222,22 @@ func (p PhysicalLimit) Init(ctx sessionctx.Context, stats *property.StatsInfo, p
 	return &p
 }
 
+// Init initializes PhysicalApply.
+func (p PhysicalApply) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalApply {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeApply, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// Init initializes PhysicalMaxOneRow.
+func (p PhysicalMaxOneRow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalMaxOneRow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeMaxOneRow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
 // Init initializes LogicalTableDual.
 func (p LogicalTableDual) Init(ctx sessionctx.Context) *LogicalTableDual {
 	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeDual, &p)
@@ -321,12 +367,22 @@ func (p PhysicalIndexLookUpReader) Init(ctx sessionctx.Context) *PhysicalIndexLo
 	return &p
 }
 
+// Init initializes PhysicalTableLookUpReader.
+func (p PhysicalTableLookUpReader) Init(ctx sessionctx.Context) *PhysicalTableLookUpReader {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeTableLookUp, &p)
+	p.FilterPlans = flattenPushDownPlan(p.filterPlan)
+	p.TablePlans = flattenPushDownPlan(p.tablePlan)
+	p.schema = p.tablePlan.Schema()
+	return &p
+}
+
 // Init initializes PhysicalTableReader.
 func (p PhysicalTableReader) Init(ctx sessionctx.Context) *PhysicalTableReader {
 	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeTableReader, &p)
 	if p.tablePlan != nil {
 		p.TablePlans = flattenPushDownPlan(p.tablePlan)
 		p.schema = p.tablePlan.Schema()
+		p.OutputColumns = p.schema.Clone().Columns
 	}
 	return &p
 }
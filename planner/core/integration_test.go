@@ -14,12 +14,19 @@
 package core_test
 
 import (
+	"encoding/json"
+	"strconv"
+	"time"
+
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testutil"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 var _ = Suite(&testIntegrationSuite{})
@@ -88,3 +95,573 @@ func (s *testIntegrationSuite) TestPushLimitDownIndexLookUpReader(c *C) {
 		tk.MustQuery(tt).Check(testkit.Rows(output[i].Plan...))
 	}
 }
+
+type deniedTablePrivManager struct {
+	table string
+}
+
+func (m *deniedTablePrivManager) RequestVerification(db, table, column string, priv mysql.PrivilegeType) bool {
+	return table != m.table
+}
+
+func (s *testIntegrationSuite) TestPrivilegeCheck(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int)")
+	tk.MustExec("create table t2 (a int)")
+
+	tk.Se.GetSessionVars().User = &auth.UserIdentity{Username: "u1", Hostname: "%"}
+	privilege.BindPrivilegeManager(tk.Se, &deniedTablePrivManager{table: "t2"})
+	tk.MustQuery("select * from t1").Check(testkit.Rows())
+	_, err := tk.Exec("select * from t1 join t2 on t1.a = t2.a")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Equals, "[planner:1142]SELECT command denied to user 'u1'@'%' for table 't2'")
+	tk.MustGetErrCode("insert into t2 values (1)", mysql.ErrTableaccessDenied)
+	tk.MustGetErrCode("delete from t2", mysql.ErrTableaccessDenied)
+	tk.MustGetErrCode("drop table t2", mysql.ErrTableaccessDenied)
+	tk.MustGetErrCode("explain select * from t2", mysql.ErrTableaccessDenied)
+
+	privilege.BindPrivilegeManager(tk.Se, nil)
+	tk.MustExec("insert into t2 values (1)")
+}
+
+func (s *testIntegrationSuite) TestNonPreparedPlanCache(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b varchar(20), c double, d int, key idx_b(b), key idx_d_c(d, c))")
+	tk.MustExec("insert into t values (1, 'x', 1.5, 10), (2, 'y', 2.5, 20), (3, 'z', 3.5, 30), (4, 'x', 4.5, 40)")
+
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 x 1.5 10"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 y 2.5 20"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tests := []struct {
+		sql       string
+		res       []string
+		fromCache bool
+	}{
+		{"select * from t where a = 1", []string{"1 x 1.5 10"}, false},
+		{"select * from t where a = 2", []string{"2 y 2.5 20"}, true},
+		{"select * from t where a = 5", nil, true},
+		{"select a from t where a > 1 and a < 4 order by a", []string{"2", "3"}, false},
+		{"select a from t where a > 2 and a < 5 order by a", []string{"3", "4"}, true},
+		{"select a from t where a != 2 order by a", []string{"1", "3", "4"}, false},
+		{"select a from t where a != 3 order by a", []string{"1", "2", "4"}, true},
+		{"select a, b from t where b = 'x' order by a", []string{"1 x", "4 x"}, false},
+		{"select a, b from t where b = 'y' order by a", []string{"2 y"}, true},
+		{"select a from t where d = 20 and c > 1.0", []string{"2"}, false},
+		{"select a from t where d = 40 and c > 4.0", []string{"4"}, true},
+		{"select a from t where d = 40 and c > 5.0", nil, true},
+		{"select a from t where c > 2.0 order by a limit 1", []string{"2"}, false},
+		{"select a from t where c > 3.0 order by a limit 1", []string{"3"}, true},
+		// The values of LIMIT are not parameters.
+		{"select a from t where c > 3.0 order by a limit 2", []string{"3", "4"}, false},
+		// The literals of different types share nothing.
+		{"select a from t where a = '1'", []string{"1"}, false},
+		{"select a from t where a = '2'", []string{"2"}, false},
+		// The column compared by "=" can't appear in other comparisons.
+		{"select a from t where a = 1 and a > 0", []string{"1"}, false},
+		{"select a from t where a = 1 and a > 0", []string{"1"}, false},
+		{"select a from t where a + 1 = 2", []string{"1"}, false},
+		{"select a from t where a + 1 = 2", []string{"1"}, false},
+	}
+	for _, tt := range tests {
+		tk.MustQuery(tt.sql).Check(testkit.Rows(tt.res...))
+		fromCache := "0"
+		if tt.fromCache {
+			fromCache = "1"
+		}
+		tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows(fromCache))
+	}
+
+	// The field names of the result are kept.
+	rs, err := tk.Exec("select A from t where a = 3")
+	c.Assert(err, IsNil)
+	c.Assert(rs.Fields()[0].ColumnAsName.O, Equals, "A")
+	c.Assert(rs.Close(), IsNil)
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The plans built in a dirty transaction are not cached.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (5, 'w', 5.5, 50)")
+	tk.MustQuery("select * from t where a = 5").Check(testkit.Rows("5 w 5.5 50"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustExec("rollback")
+	tk.MustQuery("select * from t where a = 5").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The cached plans are invalidated by the schema changes.
+	tk.MustExec("alter table t add column e int")
+	tk.MustQuery("select * from t where a = 3").Check(testkit.Rows("3 z 3.5 30 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows("4 x 4.5 40 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The cached plans are invalidated by the new statistics.
+	tk.MustExec("analyze table t")
+	tk.MustQuery("select * from t where a = 3").Check(testkit.Rows("3 z 3.5 30 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows("4 x 4.5 40 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 0")
+	tk.MustQuery("select * from t where a = 4").Check(testkit.Rows("4 x 4.5 40 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	_, err = tk.Exec("set @@last_plan_from_cache = 1")
+	c.Assert(err, NotNil)
+	_, err = tk.Exec("set @@tidb_non_prepared_plan_cache_size = 0")
+	c.Assert(err, NotNil)
+}
+
+func (s *testIntegrationSuite) TestNonPreparedPlanCachePrivilege(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t1")
+	tk.MustExec("create table t1 (a int primary key)")
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.Se.GetSessionVars().User = &auth.UserIdentity{Username: "u1", Hostname: "%"}
+	privilege.BindPrivilegeManager(tk.Se, &deniedTablePrivManager{table: "t2"})
+	tk.MustQuery("select * from t1 where a = 1").Check(testkit.Rows())
+	tk.MustQuery("select * from t1 where a = 2").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The privileges are checked again when the cached plan is reused.
+	privilege.BindPrivilegeManager(tk.Se, &deniedTablePrivManager{table: "t1"})
+	tk.MustGetErrCode("select * from t1 where a = 3", mysql.ErrTableaccessDenied)
+	privilege.BindPrivilegeManager(tk.Se, nil)
+}
+
+func (s *testIntegrationSuite) TestNonPreparedPlanCacheDeferredExpr(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustExec("insert into t values (1, 1), (unix_timestamp() + 1, 2)")
+	future := tk.MustQuery("select a from t where b = 2").Rows()[0][0].(string)
+	ts, err := strconv.ParseInt(future, 10, 64)
+	c.Assert(err, IsNil)
+
+	tk.MustQuery("select b from t where a < unix_timestamp() and a > 0")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select b from t where a < unix_timestamp() and a > 0")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// UNIX_TIMESTAMP() is evaluated again when the cached plan is reused.
+	for time.Now().Unix() <= ts {
+		time.Sleep(100 * time.Millisecond)
+	}
+	tk.MustQuery("select b from t where a < unix_timestamp() and a > 0").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The deferred functions are only parameterized when they are compared with columns directly.
+	tk.MustQuery("select b from t where a < unix_timestamp() - 1000000 and a > 0").Check(testkit.Rows("1"))
+	tk.MustQuery("select b from t where a < unix_timestamp() - 1000000 and a > 0").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+}
+
+func (s *testIntegrationSuite) TestLateMaterialization(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(20), d int, e double)")
+	tk.MustExec("insert into t values (1, 10, 'x', 100, 1.5), (2, 20, 'y', 200, 2.5), (3, 30, 'z', 300, 3.5), (4, 20, 'w', 400, 4.5)")
+	tk.MustExec("create table t2(a int, b int, c varchar(20), d int, e double)")
+	tk.MustExec("insert into t2 select * from t")
+	tk.MustExec("set @@tidb_opt_enable_late_materialization = 1")
+
+	var input []string
+	var output []struct {
+		SQL  string
+		Plan []string
+	}
+	s.testData.GetTestCases(c, &input, &output)
+	for i, tt := range input {
+		s.testData.OnRecord(func() {
+			output[i].SQL = tt
+			output[i].Plan = s.testData.ConvertRowsToStrings(tk.MustQuery(tt).Rows())
+		})
+		tk.MustQuery(tt).Check(testkit.Rows(output[i].Plan...))
+	}
+
+	tk.MustQuery("select * from t where b = 20").Sort().Check(testkit.Rows("2 20 y 200 2.5", "4 20 w 400 4.5"))
+	tk.MustQuery("select c, e from t2 where b = 20 and d > 300").Check(testkit.Rows("w 4.5"))
+	tk.MustQuery("select a from t2 where b = 30").Check(testkit.Rows("3"))
+
+	// The rows in the transaction buffer are still read by the union scan.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (5, 20, 'v', 500, 5.5)")
+	tk.MustQuery("select a from t where b = 20").Sort().Check(testkit.Rows("2", "4", "5"))
+	tk.MustExec("rollback")
+
+	tk.MustExec("set @@tidb_opt_enable_late_materialization = 0")
+	tk.MustQuery("explain select * from t where b = 20").Check(testkit.Rows(
+		"TableReader_7 10.00 root data:Selection_6",
+		"└─Selection_6 10.00 cop eq(test.t.b, 20)",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+}
+
+func (s *testIntegrationSuite) TestExplainHypoIndex(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(20), d int, key idx_d(d))")
+	tk.MustExec("create table t2(a int, b int)")
+	tk.MustQuery("explain with hypo index idx_b on t(b) select * from t where b = 20").Check(testkit.Rows(
+		"IndexLookUp_10 10.00 root ",
+		"├─IndexScan_8 10.00 cop table:t, index:b, range:[20,20], keep order:false, stats:pseudo",
+		"└─TableScan_9 10.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	// The hypothetical indexes only affect the explained statement.
+	tk.MustQuery("explain select * from t where b = 20").Check(testkit.Rows(
+		"TableReader_7 10.00 root data:Selection_6",
+		"└─Selection_6 10.00 cop eq(test.t.b, 20)",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("explain format = 'row' with hypo index idx_b on t(b), hypo index idx_b_c on t(b, c) select b, c from t where b = 20 and c > 'x'").Check(testkit.Rows(
+		"IndexReader_6 3.33 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 3.33 cop table:t, index:b, c, range:(20 \"x\",20 +inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("explain with hypo index idx_c on t(c(2)) select c from t where c = 'xyz'").Check(testkit.Rows(
+		"IndexLookUp_11 10.00 root ",
+		"├─IndexScan_8 10.00 cop table:t, index:c, range:[\"xy\",\"xy\"], keep order:false, stats:pseudo",
+		"└─Selection_10 10.00 cop eq(test.t.c, \"xyz\")",
+		"  └─TableScan_9 10.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("explain with hypo index idx_b on t2(b) delete from t2 where b = 1").Check(testkit.Rows(
+		"Delete_4 N/A root N/A",
+		"└─IndexLookUp_11 10.00 root ",
+		"  ├─IndexScan_9 10.00 cop table:t2, index:b, range:[1,1], keep order:false, stats:pseudo",
+		"  └─TableScan_10 10.00 cop table:t2, keep order:false, stats:pseudo",
+	))
+
+	tk.MustGetErrCode("explain with hypo index idx_d on t(b) select * from t", mysql.ErrDupKeyName)
+	tk.MustGetErrCode("explain with hypo index idx_b on t(b), hypo index idx_b on t(c) select * from t", mysql.ErrDupKeyName)
+	tk.MustGetErrCode("explain with hypo index idx_e on t(e) select * from t", mysql.ErrKeyColumnDoesNotExits)
+	tk.MustGetErrCode("explain with hypo index idx_b on t(b(2)) select * from t", mysql.ErrWrongSubKey)
+	tk.MustGetErrCode("explain with hypo index idx_b on t((b + 1)) select * from t", mysql.ErrNotSupportedYet)
+	tk.MustGetErrCode("explain with hypo index idx_b on t3(b) select * from t", mysql.ErrNoSuchTable)
+}
+
+func (s *testIntegrationSuite) TestExplainWithOptimizerTrace(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c varchar(20), d int, key idx_d(d))")
+	tk.MustQuery("select * from information_schema.optimizer_trace").Check(testkit.Rows())
+
+	sql := "explain with optimizer trace, hypo index idx_b on t(b) select * from t where b = 20"
+	tk.MustQuery(sql).Check(testkit.Rows(
+		"IndexLookUp_10 10.00 root ",
+		"├─IndexScan_8 10.00 cop table:t, index:b, range:[20,20], keep order:false, stats:pseudo",
+		"└─TableScan_9 10.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	rows := tk.MustQuery("select query, trace from information_schema.optimizer_trace").Rows()
+	c.Assert(rows, HasLen, 1)
+	c.Assert(rows[0][0], Equals, sql)
+	trace := &tracing.OptimizeTracer{}
+	c.Assert(json.Unmarshal([]byte(rows[0][1].(string)), trace), IsNil)
+
+	pushedDown := false
+	for _, rule := range trace.Logical {
+		if rule.Rule == "predicate_push_down" {
+			pushedDown = true
+			c.Assert(rule.Before, Equals, "DataScan(t)->Sel([eq(test.t.b, 20)])->Projection")
+			c.Assert(rule.After, Equals, "DataScan(t)->Projection")
+		}
+	}
+	c.Assert(pushedDown, IsTrue)
+	c.Assert(trace.Physical, Not(HasLen), 0)
+	ds := trace.Physical[0]
+	c.Assert(ds.Table, Equals, "t")
+	c.Assert(ds.Chosen, Equals, "idx_b")
+	c.Assert(ds.Candidate("PRIMARY_KEY").Pruned, IsFalse)
+	c.Assert(ds.Candidate("idx_d").Pruned, IsTrue)
+	c.Assert(ds.Candidate("idx_d").Reason, Equals, "no access condition, no required order and not covering")
+	idxB := ds.Candidate("idx_b")
+	c.Assert(idxB.Pruned, IsFalse)
+	c.Assert(idxB.AccessConds, DeepEquals, []string{"eq(test.t.b, 20)"})
+	c.Assert(idxB.Ranges, DeepEquals, []string{"[20,20]"})
+	c.Assert(idxB.Cost < ds.Candidate("PRIMARY_KEY").Cost, IsTrue)
+	c.Assert(trace.FinalPlan, Equals, "IndexLookUp(Index(t.idx_b)[[20,20]], Table(t))")
+
+	// The trace is kept until another statement is explained with optimizer trace.
+	tk.MustQuery("explain select * from t where a = 1")
+	tk.MustQuery("select query from information_schema.optimizer_trace").Check(testkit.Rows(sql))
+}
+
+func (s *testIntegrationSuite) TestNormalizeConditions(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key idx_b(b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 1, 3), (3, 2, 1), (4, 4, 2)")
+
+	// The conjunction under the `not` is split, so the range of idx_b can be built from `b = 1`.
+	tk.MustQuery("explain select * from t where not (b != 1 or c > 2) and a = b").Check(testkit.Rows(
+		"IndexLookUp_12 2.66 root ",
+		"├─Selection_10 8.00 cop eq(test.t.a, test.t.b)",
+		"│ └─IndexScan_8 10.00 cop table:t, index:b, range:[1,1], keep order:false, stats:pseudo",
+		"└─Selection_11 2.66 cop le(test.t.c, 2)",
+		"  └─TableScan_9 8.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select * from t where not (b != 1 or c > 2) and a = b").Check(testkit.Rows("1 1 1"))
+	tk.MustQuery("explain select * from t where not (b between 1 and 3 and c = 1)").Check(testkit.Rows(
+		"TableReader_7 8000.00 root data:Selection_6",
+		"└─Selection_6 8000.00 cop or(lt(test.t.b, 1), or(gt(test.t.b, 3), ne(test.t.c, 1)))",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where not (b between 1 and 3 and c = 1)").Sort().Check(testkit.Rows("2", "4"))
+}
+
+func (s *testIntegrationSuite) TestLikeRange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, t2")
+	tk.MustExec("create table t(a int primary key, b varchar(20), c char(10), d varchar(20) collate utf8mb4_general_ci, key idx_b(b), key idx_c(c), key idx_d(d))")
+	tk.MustExec("insert into t values (1, 'abc', 'ab', 'abc'), (2, 'abd', 'ab c', 'ABD'), (3, 'ab', 'b', 'b'), (4, 'b%', 'ab ', 'x'), (5, 'a', 'abx', 'y')")
+
+	tk.MustQuery("explain select * from t where b like 'ab%'").Check(testkit.Rows(
+		"IndexLookUp_10 250.00 root ",
+		"├─IndexScan_8 250.00 cop table:t, index:b, range:[\"ab\",\"ac\"), keep order:false, stats:pseudo",
+		"└─TableScan_9 250.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where b like 'ab%'").Sort().Check(testkit.Rows("1", "2", "3"))
+	// The rest of the pattern is matched by the filter.
+	tk.MustQuery("explain select * from t where b like 'ab_'").Check(testkit.Rows(
+		"IndexLookUp_11 250.00 root ",
+		"├─Selection_10 250.00 cop like(test.t.b, \"ab_\", 92)",
+		"│ └─IndexScan_8 250.00 cop table:t, index:b, range:(\"ab\",\"ac\"), keep order:false, stats:pseudo",
+		"└─TableScan_9 250.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where b like 'ab_'").Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select a from t where b like 'ab%c'").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where b like 'b\\%'").Check(testkit.Rows("4"))
+	tk.MustQuery("select a from t where b like 'ab%' or b like 'b%'").Sort().Check(testkit.Rows("1", "2", "3", "4"))
+	// The trailing spaces of the CHAR values are trimmed.
+	tk.MustQuery("explain select * from t where c like 'ab %'").Check(testkit.Rows(
+		"IndexLookUp_11 250.00 root ",
+		"├─Selection_10 250.00 cop like(test.t.c, \"ab %\", 92)",
+		"│ └─IndexScan_8 250.00 cop table:t, index:c, range:[\"ab\",\"ac\"), keep order:false, stats:pseudo",
+		"└─TableScan_9 250.00 cop table:t, keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where c like 'ab %'").Check(testkit.Rows("2"))
+	tk.MustQuery("select a from t where c like 'ab%'").Sort().Check(testkit.Rows("1", "2", "4", "5"))
+	// The ranges can't be built for the case insensitive collations or the patterns starting with wildcards.
+	tk.MustQuery("explain select * from t where d like 'ab%'").Check(testkit.Rows(
+		"TableReader_7 8000.00 root data:Selection_6",
+		"└─Selection_6 8000.00 cop like(test.t.d, \"ab%\", 92)",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where d like 'ab%'").Sort().Check(testkit.Rows("1", "2"))
+	tk.MustQuery("explain select * from t where b like '%b'").Check(testkit.Rows(
+		"TableReader_7 8000.00 root data:Selection_6",
+		"└─Selection_6 8000.00 cop like(test.t.b, \"%b\", 92)",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+
+	// The rows are estimated by the histograms.
+	tk.MustExec("create table t2(a int primary key, b varchar(20))")
+	tk.MustExec("insert into t2 values (1, 'abc'), (2, 'abd'), (3, 'ab'), (4, 'b'), (5, 'a'), (6, 'bcd'), (7, 'x'), (8, 'abx')")
+	tk.MustExec("analyze table t2")
+	tk.MustQuery("explain select * from t2 where b like 'ab%'").Check(testkit.Rows(
+		"TableReader_7 4.00 root data:Selection_6",
+		"└─Selection_6 4.00 cop like(test.t2.b, \"ab%\", 92)",
+		"  └─TableScan_5 8.00 cop table:t2, range:[-inf,+inf], keep order:false",
+	))
+	tk.MustQuery("explain select * from t2 where b like 'b%'").Check(testkit.Rows(
+		"TableReader_7 2.00 root data:Selection_6",
+		"└─Selection_6 2.00 cop like(test.t2.b, \"b%\", 92)",
+		"  └─TableScan_5 8.00 cop table:t2, range:[-inf,+inf], keep order:false",
+	))
+}
+
+func (s *testIntegrationSuite) TestNullRange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(id int primary key, a int, b int, key idx_ab(a, b))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 1, 2), (3, null, 1), (4, null, 2), (5, 2, null), (6, null, null), (7, 3, 3), (8, 4, 4)")
+
+	tk.MustQuery("explain select * from t where a <=> null").Check(testkit.Rows(
+		"IndexReader_6 10.00 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 10.00 cop table:t, index:a, b, range:[NULL,NULL], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select id from t where a <=> null").Sort().Check(testkit.Rows("3", "4", "6"))
+	tk.MustQuery("select id from t where 1 <=> a").Sort().Check(testkit.Rows("1", "2"))
+	// The ranges of the following index columns are appended to the NULL points.
+	tk.MustQuery("explain select * from t where a is null and b = 2").Check(testkit.Rows(
+		"IndexReader_6 0.10 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 0.10 cop table:t, index:a, b, range:[NULL 2,NULL 2], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select id from t where a is null and b = 2").Sort().Check(testkit.Rows("4"))
+	tk.MustQuery("explain select * from t where a <=> null and b > 1").Check(testkit.Rows(
+		"IndexReader_6 33.33 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 33.33 cop table:t, index:a, b, range:(NULL 1,NULL +inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select id from t where a <=> null and b > 1").Sort().Check(testkit.Rows("4"))
+	tk.MustQuery("select id from t where a <=> null and b <=> null").Sort().Check(testkit.Rows("6"))
+	tk.MustQuery("select id from t where a <=> 1 or a <=> null").Sort().Check(testkit.Rows("1", "2", "3", "4", "6"))
+	tk.MustQuery("select id from t where a is null and a = 1").Sort().Check(testkit.Rows())
+	tk.MustQuery("select id from t where not (a <=> 1)").Sort().Check(testkit.Rows("3", "4", "5", "6", "7", "8"))
+
+	tk.MustExec("analyze table t")
+	tk.MustQuery("explain select * from t where a <=> null").Check(testkit.Rows(
+		"IndexReader_6 3.00 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 3.00 cop table:t, index:a, b, range:[NULL,NULL], keep order:false",
+	))
+	tk.MustQuery("explain select * from t use index() where b <=> null").Check(testkit.Rows(
+		"TableReader_7 2.00 root data:Selection_6",
+		"└─Selection_6 2.00 cop nulleq(test.t.b, NULL)",
+		"  └─TableScan_5 8.00 cop table:t, range:[-inf,+inf], keep order:false",
+	))
+}
+
+func (s *testIntegrationSuite) TestRangeMaxCount(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, key idx_abc(a, b, c))")
+	tk.MustExec("insert into t values (1, 3, 1), (1, 4, 2), (2, 3, 3), (2, 5, 4), (3, 3, 5)")
+
+	tk.MustQuery("explain select * from t where a in (1, 2) and b in (3, 4) and c > 1").Check(testkit.Rows(
+		"IndexReader_6 1.33 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 1.33 cop table:t, index:a, b, c, range:(1 3 1,1 3 +inf], (1 4 1,1 4 +inf], (2 3 1,2 3 +inf], (2 4 1,2 4 +inf], keep order:false, stats:pseudo",
+	))
+	tk.MustExec("set @@tidb_opt_range_max_count = 3")
+	// The in conditions that would expand the ranges over the limit, and the conditions of the following
+	// columns, are evaluated as filters.
+	tk.MustQuery("explain select * from t where a in (1, 2) and b in (3, 4) and c > 1").Check(testkit.Rows(
+		"IndexReader_7 0.01 root index:Selection_6, covering index",
+		"└─Selection_6 0.01 cop gt(test.t.c, 1), in(test.t.b, 3, 4)",
+		"  └─IndexScan_5 20.00 cop table:t, index:a, b, c, range:[1,1], [2,2], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select * from t where a in (1, 2) and b in (3, 4) and c > 1").Sort().Check(testkit.Rows("1 4 2", "2 3 3"))
+	tk.MustQuery("explain select * from t where a = 1 and b in (3, 4) and c in (1, 2)").Check(testkit.Rows(
+		"IndexReader_7 0.00 root index:Selection_6, covering index",
+		"└─Selection_6 0.00 cop in(test.t.c, 1, 2)",
+		"  └─IndexScan_5 0.20 cop table:t, index:a, b, c, range:[1 3,1 3], [1 4,1 4], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select * from t where a = 1 and b in (3, 4) and c in (1, 2)").Sort().Check(testkit.Rows("1 3 1", "1 4 2"))
+	// The first column and the equal conditions don't expand the ranges.
+	tk.MustQuery("explain select * from t where a in (1, 2, 3, 4) and b = 3").Check(testkit.Rows(
+		"IndexReader_6 0.40 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 0.40 cop table:t, index:a, b, c, range:[1 3,1 3], [2 3,2 3], [3 3,3 3], [4 3,4 3], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select * from t where a in (1, 2, 3, 4) and b = 3").Sort().Check(testkit.Rows("1 3 1", "2 3 3", "3 3 5"))
+
+	tk.MustExec("set @@tidb_opt_range_max_count = 0")
+	tk.MustQuery("explain select * from t where a in (1, 2) and b in (3, 4) and c in (1, 2)").Check(testkit.Rows(
+		"IndexReader_6 0.01 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 0.01 cop table:t, index:a, b, c, range:[1 3 1,1 3 1], [1 3 2,1 3 2], [1 4 1,1 4 1], [1 4 2,1 4 2], [2 3 1,2 3 1], [2 3 2,2 3 2], [2 4 1,2 4 1], [2 4 2,2 4 2], keep order:false, stats:pseudo",
+	))
+	tk.MustExec("set @@tidb_opt_range_max_count = -1")
+	tk.MustQuery("select @@tidb_opt_range_max_count").Check(testkit.Rows("0"))
+}
+
+func (s *testIntegrationSuite) TestCopOutputColumns(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b int, c int, d int, key idx_bcd(b, c, d))")
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 2, 2, 2), (3, 3, 3, 3)")
+
+	// The columns only used by the filters are not returned by the coprocessor.
+	tk.MustQuery("select d, a from t where b > 1 and c < 3").Check(testkit.Rows("2 2"))
+	tk.MustQuery("select d + 1 from t use index(idx_bcd) where b > 1 and c < 3").Check(testkit.Rows("3"))
+	tk.MustQuery("select 1 from t where b > 1").Check(testkit.Rows("1", "1"))
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (4, 4, 1, 4)")
+	tk.MustQuery("select d, a from t where b > 1 and c < 3").Sort().Check(testkit.Rows("2 2", "4 4"))
+	tk.MustQuery("select d from t use index(idx_bcd) where b > 1 and c < 3").Sort().Check(testkit.Rows("2", "4"))
+	tk.MustExec("rollback")
+}
+
+func (s *testIntegrationSuite) TestCoveringIndexScan(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, d int, key idx_bc(b, c))")
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 1, 2, 2), (3, 2, 3, 3)")
+
+	// The index and the handle cover all the needed columns, so the table is not looked up.
+	tk.MustQuery("explain select b, c from t where b = 1").Check(testkit.Rows(
+		"IndexReader_6 10.00 root index:IndexScan_5, covering index",
+		"└─IndexScan_5 10.00 cop table:t, index:b, c, range:[1,1], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("explain select a, c from t use index(idx_bc) where c > 1").Check(testkit.Rows(
+		"IndexReader_7 3333.33 root index:Selection_6, covering index",
+		"└─Selection_6 3333.33 cop gt(test.t.c, 1)",
+		"  └─IndexScan_5 10000.00 cop table:t, index:b, c, range:[NULL,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a, c from t use index(idx_bc) where c > 1").Sort().Check(testkit.Rows("2 2", "3 3"))
+	tk.MustQuery("explain select d from t where b = 1").Check(testkit.Rows(
+		"Projection_4 10.00 root test.t.d",
+		"└─IndexLookUp_10 10.00 root ",
+		"  ├─IndexScan_8 10.00 cop table:t, index:b, c, range:[1,1], keep order:false, stats:pseudo",
+		"  └─TableScan_9 10.00 cop table:t, keep order:false, stats:pseudo",
+	))
+}
+
+func (s *testIntegrationSuite) TestMaxOneRowElimination(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, s")
+	tk.MustExec("create table t(a int primary key, b int)")
+	tk.MustExec("create table s(a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into s values (1, 10), (2, 20), (4, 10)")
+
+	// The primary key is fixed by the correlated column, so the subquery returns no more than one row.
+	tk.MustQuery("select a, (select b from s where s.a = t.a) from t").Sort().Check(testkit.Rows("1 10", "2 20", "3 <nil>"))
+	rows := tk.MustQuery("explain select a, (select b from s where s.a = t.a) from t").Rows()
+	for _, row := range rows {
+		c.Assert(row[0].(string), Not(Matches), ".*MaxOneRow.*")
+	}
+	tk.MustQuery("select distinct b from s where a = 1").Check(testkit.Rows("10"))
+	tk.MustQuery("select b, count(*) from s where a = 4 group by b").Check(testkit.Rows("10 1"))
+	tk.MustQuery("select b, count(*) from s where a = 3 group by b").Check(testkit.Rows())
+
+	// The subquery may return more than one row.
+	err := tk.QueryToErr("select a, (select a from s where s.b = 10) from t")
+	c.Assert(err, NotNil)
+	c.Assert(err.Error(), Matches, ".*Subquery returns more than 1 row.*")
+}
+
+func (s *testIntegrationSuite) TestSimplifyPredicates(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, NULL, NULL)")
+
+	tk.MustQuery("explain select * from t where b > 1 or 1").Check(testkit.Rows(
+		"TableReader_6 10000.00 root data:TableScan_5",
+		"└─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where b > 1 or 1").Sort().Check(testkit.Rows("1", "2", "3"))
+	tk.MustQuery("explain select * from t where b > 1 and (c > 1 or null) and b > 1").Check(testkit.Rows(
+		"TableReader_7 1111.11 root data:Selection_6",
+		"└─Selection_6 1111.11 cop gt(test.t.b, 1), gt(test.t.c, 1)",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where b > 1 and (c > 1 or null) and b > 1").Check(testkit.Rows("2"))
+	tk.MustQuery("explain select * from t where not (b > 1 and 0) and (c = 1 or c = 1)").Check(testkit.Rows(
+		"TableReader_7 10.00 root data:Selection_6",
+		"└─Selection_6 10.00 cop eq(test.t.c, 1)",
+		"  └─TableScan_5 10000.00 cop table:t, range:[-inf,+inf], keep order:false, stats:pseudo",
+	))
+	tk.MustQuery("select a from t where not (b > 1 and 0) and (c = 1 or c = 1)").Check(testkit.Rows("1"))
+	tk.MustQuery("explain select * from t where b > 1 and (c < 1 and null)").Check(testkit.Rows(
+		"TableDual_6 0.00 root rows:0",
+	))
+	// NULL doesn't act as false under NOT.
+	tk.MustQuery("select a from t where not (b > 1 or null)").Check(testkit.Rows())
+	tk.MustQuery("select a from t where not (b > 1 and null)").Check(testkit.Rows("1"))
+}
@@ -19,6 +19,7 @@ import (
 	"math"
 	"math/bits"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -63,7 +64,7 @@ func (la *LogicalAggregation) collectGroupByColumns() {
 	}
 }
 
-func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr, gbyItems []expression.Expression) (LogicalPlan, map[int]int, error) {
+func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr, gbyItems []expression.Expression, rollup bool) (LogicalPlan, map[int]int, error) {
 	b.optFlag = b.optFlag | flagBuildKeyInfo
 	b.optFlag = b.optFlag | flagPushDownAgg
 	// We may apply aggregation eliminate optimization.
@@ -91,7 +92,7 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 			p = np
 			newArgList = append(newArgList, newArg)
 		}
-		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgList)
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgList, aggFunc.Distinct)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -114,8 +115,15 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 			names = append(names, types.EmptyName)
 		}
 	}
+	if rollup {
+		var err error
+		p, gbyItems, err = b.buildExpand(p, gbyItems)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 	for i, col := range p.Schema().Columns {
-		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col})
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -133,6 +141,75 @@ func (b *PlanBuilder) buildAggregation(ctx context.Context, p LogicalPlan, aggFu
 	return plan4Agg, aggIndexMap, nil
 }
 
+// buildExpand builds the LogicalExpand of ROLLUP under the aggregation, and returns it with the
+// new group-by items, which are the nullable copies of the group-by columns and the grouping ID.
+// The copies take over the names of the group-by columns, so the references to them above the
+// aggregation are NULL in the super-aggregate rows, while the aggregate functions built before
+// still read the original columns.
+func (b *PlanBuilder) buildExpand(p LogicalPlan, gbyItems []expression.Expression) (LogicalPlan, []expression.Expression, error) {
+	gbyCols := make([]*expression.Column, 0, len(gbyItems))
+	for _, item := range gbyItems {
+		col, ok := item.(*expression.Column)
+		if !ok {
+			return nil, nil, ErrNotSupportedYet.GenWithStackByArgs("ROLLUP on GROUP BY expressions")
+		}
+		gbyCols = append(gbyCols, col)
+	}
+	childCols := p.Schema().Columns
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(childCols)+len(gbyCols)+1)...)
+	schema.Append(childCols...)
+	names := make(types.NameSlice, 0, len(childCols)+len(gbyCols)+1)
+	names = append(names, p.OutputNames()...)
+	b.rollupGroupingCols = make(map[int64]int, len(gbyCols))
+	for i, col := range gbyCols {
+		tp := col.RetType.Clone()
+		tp.Flag &^= mysql.NotNullFlag
+		newCol := &expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  tp,
+		}
+		schema.Append(newCol)
+		name := types.EmptyName
+		if idx := p.Schema().ColumnIndex(col); idx >= 0 {
+			name, names[idx] = names[idx], types.EmptyName
+		}
+		names = append(names, name)
+		b.rollupGroupingCols[newCol.UniqueID] = len(gbyCols) - 1 - i
+	}
+	gid := &expression.Column{
+		UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+		RetType:  types.NewFieldType(mysql.TypeLonglong),
+	}
+	gid.RetType.Flag |= mysql.NotNullFlag
+	schema.Append(gid)
+	names = append(names, types.EmptyName)
+	b.rollupGID = gid
+
+	// The i-th level replaces the last i group-by columns by NULL.
+	expand := LogicalExpand{LevelExprs: make([][]expression.Expression, len(gbyCols)+1)}.Init(b.ctx)
+	for level := range expand.LevelExprs {
+		exprs := make([]expression.Expression, 0, schema.Len())
+		for _, col := range childCols {
+			exprs = append(exprs, col.Clone())
+		}
+		var mask int64
+		for i, col := range gbyCols {
+			if i < len(gbyCols)-level {
+				exprs = append(exprs, col.Clone())
+				continue
+			}
+			exprs = append(exprs, &expression.Constant{Value: types.Datum{}, RetType: schema.Columns[len(childCols)+i].RetType})
+			mask |= 1 << uint(len(gbyCols)-1-i)
+		}
+		exprs = append(exprs, &expression.Constant{Value: types.NewIntDatum(mask), RetType: gid.RetType})
+		expand.LevelExprs[level] = exprs
+	}
+	expand.SetChildren(p)
+	expand.SetSchema(schema)
+	expand.names = names
+	return expand, expression.Column2Exprs(schema.Columns[len(childCols):]), nil
+}
+
 func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSetNode) (p LogicalPlan, err error) {
 	switch x := node.(type) {
 	case *ast.Join:
@@ -237,13 +314,13 @@ func (p *LogicalJoin) ExtractOnCondition(
 				}
 				if leftCol != nil && rightCol != nil {
 					if deriveLeft {
-						if isNullRejected(ctx, leftSchema, expr) && !mysql.HasNotNullFlag(leftCol.RetType.Flag) {
+						if expression.IsNullRejecting(ctx, leftSchema, expr) && !mysql.HasNotNullFlag(leftCol.RetType.Flag) {
 							notNullExpr := expression.BuildNotNullExpr(ctx, leftCol)
 							leftCond = append(leftCond, notNullExpr)
 						}
 					}
 					if deriveRight {
-						if isNullRejected(ctx, rightSchema, expr) && !mysql.HasNotNullFlag(rightCol.RetType.Flag) {
+						if expression.IsNullRejecting(ctx, rightSchema, expr) && !mysql.HasNotNullFlag(rightCol.RetType.Flag) {
 							notNullExpr := expression.BuildNotNullExpr(ctx, rightCol)
 							rightCond = append(rightCond, notNullExpr)
 						}
@@ -381,6 +458,26 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	copy(joinPlan.names, leftPlan.OutputNames())
 	copy(joinPlan.names[leftPlan.Schema().Len():], rightPlan.OutputNames())
 
+	// Merge sub join's redundantSchema into this join plan. When handle query like
+	// select t2.a from (t1 join t2 using (a)) join t3 using (a);
+	// we can simply search in the top level join plan to find redundant column.
+	var (
+		lRedundantSchema, rRedundantSchema *expression.Schema
+		lRedundantNames, rRedundantNames   types.NameSlice
+	)
+	if left, ok := leftPlan.(*LogicalJoin); ok && left.redundantSchema != nil {
+		lRedundantSchema = left.redundantSchema
+		lRedundantNames = left.redundantNames
+	}
+	if right, ok := rightPlan.(*LogicalJoin); ok && right.redundantSchema != nil {
+		rRedundantSchema = right.redundantSchema
+		rRedundantNames = right.redundantNames
+	}
+	joinPlan.redundantSchema = expression.MergeSchema(lRedundantSchema, rRedundantSchema)
+	joinPlan.redundantNames = make([]*types.FieldName, len(lRedundantNames)+len(rRedundantNames))
+	copy(joinPlan.redundantNames, lRedundantNames)
+	copy(joinPlan.redundantNames[len(lRedundantNames):], rRedundantNames)
+
 	// Set join type.
 	switch joinNode.Tp {
 	case ast.LeftJoin:
@@ -401,7 +498,17 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	// Set preferred join algorithm if some join hints is specified by user.
 	joinPlan.setPreferredJoinType(b.TableHints())
 
-	if joinNode.On != nil {
+	if joinNode.NaturalJoin {
+		err = b.buildNaturalJoin(joinPlan, leftPlan, rightPlan, joinNode)
+		if err != nil {
+			return nil, err
+		}
+	} else if joinNode.Using != nil {
+		err = b.buildUsingClause(joinPlan, leftPlan, rightPlan, joinNode)
+		if err != nil {
+			return nil, err
+		}
+	} else if joinNode.On != nil {
 		b.curClause = onClause
 		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, joinPlan, nil, false)
 		if err != nil {
@@ -421,6 +528,155 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	return joinPlan, nil
 }
 
+// buildApplyWithJoinType builds an apply plan, which evaluates innerPlan for every row of outerPlan.
+// Note that tp can only be LeftOuterJoin or InnerJoin.
+func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, tp JoinType) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo
+	ap := LogicalApply{LogicalJoin: LogicalJoin{JoinType: tp}}.Init(b.ctx)
+	ap.SetChildren(outerPlan, innerPlan)
+	ap.SetSchema(expression.MergeSchema(outerPlan.Schema(), innerPlan.Schema()))
+	ap.names = make([]*types.FieldName, outerPlan.Schema().Len()+innerPlan.Schema().Len())
+	copy(ap.names, outerPlan.OutputNames())
+	// The columns of the inner plan can not be referred by name.
+	for i := outerPlan.Schema().Len(); i < ap.Schema().Len(); i++ {
+		ap.names[i] = types.EmptyName
+	}
+	if tp == LeftOuterJoin {
+		resetNotNullFlag(ap.schema, outerPlan.Schema().Len(), ap.schema.Len())
+	}
+	ap.CorCols = ExtractCorColumnsBySchema4LogicalPlan(innerPlan, outerPlan.Schema())
+	return ap
+}
+
+// buildJoinWithSubquery joins the plan of an uncorrelated scalar subquery to the outer plan,
+// the subquery is evaluated only once.
+func (b *PlanBuilder) buildJoinWithSubquery(outerPlan, innerPlan LogicalPlan) LogicalPlan {
+	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo
+	joinPlan := LogicalJoin{JoinType: LeftOuterJoin}.Init(b.ctx)
+	joinPlan.SetChildren(outerPlan, innerPlan)
+	joinPlan.SetSchema(expression.MergeSchema(outerPlan.Schema(), innerPlan.Schema()))
+	joinPlan.names = make([]*types.FieldName, outerPlan.Schema().Len()+innerPlan.Schema().Len())
+	copy(joinPlan.names, outerPlan.OutputNames())
+	for i := outerPlan.Schema().Len(); i < joinPlan.Schema().Len(); i++ {
+		joinPlan.names[i] = types.EmptyName
+	}
+	resetNotNullFlag(joinPlan.schema, outerPlan.Schema().Len(), joinPlan.schema.Len())
+	return joinPlan
+}
+
+func (b *PlanBuilder) buildMaxOneRow(p LogicalPlan) LogicalPlan {
+	b.optFlag = b.optFlag | flagBuildKeyInfo | flagEliminateMaxOneRow
+	maxOneRow := LogicalMaxOneRow{}.Init(b.ctx)
+	maxOneRow.SetChildren(p)
+	return maxOneRow
+}
+
+// buildUsingClause eliminate the redundant columns and ordering columns based
+// on the "USING" clause.
+//
+// According to the standard SQL, the coalesced common columns come first in the order
+// they appear in "leftPlan", then the rest columns of "leftPlan", then the rest columns of "rightPlan".
+func (b *PlanBuilder) buildUsingClause(p *LogicalJoin, leftPlan, rightPlan LogicalPlan, join *ast.Join) error {
+	filter := make(map[string]bool, len(join.Using))
+	for _, col := range join.Using {
+		filter[col.Name.L] = true
+	}
+	return b.coalesceCommonColumns(p, leftPlan, rightPlan, join.Tp, filter)
+}
+
+// buildNaturalJoin builds natural join output schema. It finds out all the common columns
+// then using the same mechanism as buildUsingClause to eliminate redundant columns and build join conditions.
+func (b *PlanBuilder) buildNaturalJoin(p *LogicalJoin, leftPlan, rightPlan LogicalPlan, join *ast.Join) error {
+	return b.coalesceCommonColumns(p, leftPlan, rightPlan, join.Tp, nil)
+}
+
+// coalesceCommonColumns is used by buildUsingClause and buildNaturalJoin. The filter is used by buildUsingClause.
+// The common column of the outer side is kept in the output schema, so a right join keeps the column of the right plan.
+func (b *PlanBuilder) coalesceCommonColumns(p *LogicalJoin, leftPlan, rightPlan LogicalPlan, joinTp ast.JoinType, filter map[string]bool) error {
+	lsc := leftPlan.Schema().Clone()
+	rsc := rightPlan.Schema().Clone()
+	if joinTp == ast.LeftJoin {
+		resetNotNullFlag(rsc, 0, rsc.Len())
+	} else if joinTp == ast.RightJoin {
+		resetNotNullFlag(lsc, 0, lsc.Len())
+	}
+	lColumns, rColumns := lsc.Columns, rsc.Columns
+	lNames, rNames := leftPlan.OutputNames().Shallow(), rightPlan.OutputNames().Shallow()
+	if joinTp == ast.RightJoin {
+		lNames, rNames = rNames, lNames
+		lColumns, rColumns = rsc.Columns, lsc.Columns
+	}
+
+	// Find out all the common columns and put them ahead.
+	commonLen := 0
+	for i, lName := range lNames {
+		for j := commonLen; j < len(rNames); j++ {
+			if lName.ColName.L != rNames[j].ColName.L {
+				continue
+			}
+
+			if len(filter) > 0 {
+				if !filter[lName.ColName.L] {
+					break
+				}
+				// Mark this column exist.
+				filter[lName.ColName.L] = false
+			}
+
+			col := lColumns[i]
+			copy(lColumns[commonLen+1:i+1], lColumns[commonLen:i])
+			lColumns[commonLen] = col
+
+			name := lNames[i]
+			copy(lNames[commonLen+1:i+1], lNames[commonLen:i])
+			lNames[commonLen] = name
+
+			col = rColumns[j]
+			copy(rColumns[commonLen+1:j+1], rColumns[commonLen:j])
+			rColumns[commonLen] = col
+
+			name = rNames[j]
+			copy(rNames[commonLen+1:j+1], rNames[commonLen:j])
+			rNames[commonLen] = name
+
+			commonLen++
+			break
+		}
+	}
+
+	if len(filter) > 0 && len(filter) != commonLen {
+		for col, notExist := range filter {
+			if notExist {
+				return ErrUnknownColumn.GenWithStackByArgs(col, "from clause")
+			}
+		}
+	}
+
+	schemaCols := make([]*expression.Column, len(lColumns)+len(rColumns)-commonLen)
+	copy(schemaCols[:len(lColumns)], lColumns)
+	copy(schemaCols[len(lColumns):], rColumns[commonLen:])
+	names := make(types.NameSlice, len(schemaCols))
+	copy(names, lNames)
+	copy(names[len(lNames):], rNames[commonLen:])
+
+	conds := make([]expression.Expression, 0, commonLen)
+	for i := 0; i < commonLen; i++ {
+		lc, rc := lsc.Columns[i], rsc.Columns[i]
+		cond, err := expression.NewFunction(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lc, rc)
+		if err != nil {
+			return err
+		}
+		conds = append(conds, cond)
+	}
+
+	p.SetSchema(expression.NewSchema(schemaCols...))
+	p.names = names
+	p.redundantSchema = expression.MergeSchema(p.redundantSchema, expression.NewSchema(rColumns[:commonLen]...))
+	p.redundantNames = append(p.redundantNames.Shallow(), rNames[:commonLen]...)
+	p.attachOnConds(conds)
+	return nil
+}
+
 func (b *PlanBuilder) buildSelection(ctx context.Context, p LogicalPlan, where ast.ExprNode, AggMapper map[*ast.AggregateFuncExpr]int) (LogicalPlan, error) {
 	b.optFlag = b.optFlag | flagPredicatePushDown
 	if b.curClause != havingClause {
@@ -439,7 +695,8 @@ func (b *PlanBuilder) buildSelection(ctx context.Context, p LogicalPlan, where a
 		if expr == nil {
 			continue
 		}
-		cnfItems := expression.SplitCNFItems(expr)
+		// Normalize the conditions so the later rules, e.g. the constant propagation, see the canonical forms.
+		cnfItems := expression.NormalizeConditions(b.ctx, []expression.Expression{expr})
 		for _, item := range cnfItems {
 			if con, ok := item.(*expression.Constant); ok {
 				ret, _, err := expression.EvalBool(b.ctx, expression.CNFExprs{con}, chunk.Row{})
@@ -537,8 +794,13 @@ func (b *PlanBuilder) buildProjectionField(ctx context.Context, p LogicalPlan, f
 	col, isCol := expr.(*expression.Column)
 	if colNameField, ok := innerNode.(*ast.ColumnNameExpr); ok && isCol {
 		// Field is a column reference.
-		idx := p.Schema().ColumnIndex(col)
-		name := p.OutputNames()[idx]
+		var name *types.FieldName
+		// The column may be the one eliminated by the USING clause or natural join.
+		if idx := p.Schema().ColumnIndex(col); idx != -1 {
+			name = p.OutputNames()[idx]
+		} else {
+			name, _, _ = findColFromNaturalUsingJoin(p, colNameField.Name)
+		}
 		colName, origColName, tblName, origTblName, dbName = b.buildProjectionFieldNameFromColumns(field, colNameField, name)
 	} else if field.AsName.L != "" {
 		// Field has alias.
@@ -610,7 +872,7 @@ func (b *PlanBuilder) buildDistinct(child LogicalPlan, length int) (*LogicalAggr
 	}.Init(b.ctx)
 	plan4Agg.collectGroupByColumns()
 	for _, col := range child.Schema().Columns {
-		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col})
+		aggDesc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
 		if err != nil {
 			return nil, err
 		}
@@ -807,6 +1069,9 @@ type havingAndOrderbyExprResolver struct {
 	colMapper    map[*ast.ColumnNameExpr]int
 	gbyItems     []*ast.ByItem
 	curClause    clauseCode
+	// fieldsLen is the number of the select fields which are not auxiliary,
+	// the positions in order by can only refer to them.
+	fieldsLen int
 }
 
 // Enter implements Visitor interface.
@@ -814,7 +1079,11 @@ func (a *havingAndOrderbyExprResolver) Enter(n ast.Node) (node ast.Node, skipChi
 	switch n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggFunc = true
-	case *ast.ColumnNameExpr, *ast.ColumnName:
+	case *ast.SubqueryExpr:
+		// The columns in the subquery are resolved when building it.
+		a.inExpr = true
+		return n, true
+	case *ast.ColumnNameExpr, *ast.ColumnName, *ast.PositionExpr:
 	default:
 		a.inExpr = true
 	}
@@ -906,9 +1175,23 @@ func (a *havingAndOrderbyExprResolver) Leave(n ast.Node) (node ast.Node, ok bool
 			return node, false
 		}
 		if a.inAggFunc {
-			return a.selectFields[index].Expr, true
+			// An alias of an aggregate function can not be referenced in another aggregate function,
+			// e.g. `select sum(b) as s from t having max(s) > 1`.
+			ret := a.selectFields[index].Expr
+			extractor := &AggregateFuncExtractor{}
+			ret.Accept(extractor)
+			if len(extractor.AggFuncs) != 0 {
+				a.err = ErrIllegalReference.GenWithStackByArgs(v.Name.OrigColName(), "reference to group function")
+				return node, false
+			}
+			return ret, true
 		}
 		a.colMapper[v] = index
+	case *ast.PositionExpr:
+		if v.N < 1 || v.N > a.fieldsLen {
+			a.err = ErrUnknownColumn.GenWithStackByArgs(strconv.Itoa(v.N), clauseMsg[a.curClause])
+			return node, false
+		}
 	}
 	return n, true
 }
@@ -923,6 +1206,7 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 		selectFields: sel.Fields.Fields,
 		aggMapper:    make(map[*ast.AggregateFuncExpr]int),
 		colMapper:    b.colMapper,
+		fieldsLen:    len(sel.Fields.Fields),
 	}
 	if sel.GroupBy != nil {
 		extractor.gbyItems = sel.GroupBy.Items
@@ -956,7 +1240,7 @@ func (b *PlanBuilder) resolveHavingAndOrderBy(sel *ast.SelectStmt, p LogicalPlan
 }
 
 func (b *PlanBuilder) extractAggFuncs(fields []*ast.SelectField) ([]*ast.AggregateFuncExpr, map[*ast.AggregateFuncExpr]int) {
-	extractor := &AggregateFuncExtractor{}
+	extractor := &AggregateFuncExtractor{skipAggMap: b.correlatedAggMapper}
 	for _, f := range fields {
 		n, _ := f.Expr.Accept(extractor)
 		f.Expr = n.(ast.ExprNode)
@@ -970,6 +1254,123 @@ func (b *PlanBuilder) extractAggFuncs(fields []*ast.SelectField) ([]*ast.Aggrega
 	return aggList, totalAggMapper
 }
 
+// resolveCorrelatedAggregates finds the aggregate functions in the select fields and where clause of the subqueries inside fields,
+// whose arguments only refer to the columns of the outer plan p. According to the SQL standard,
+// these aggregate functions are evaluated in the outer query, e.g. count(t.a) in
+// "select (select count(t.a) from s limit 1) from t" is the count of the rows of t.
+func (b *PlanBuilder) resolveCorrelatedAggregates(ctx context.Context, fields []*ast.SelectField, p LogicalPlan) ([]*ast.AggregateFuncExpr, error) {
+	resolver := &correlatedAggregateResolver{
+		ctx:        ctx,
+		b:          b,
+		outerNames: p.OutputNames(),
+	}
+	for _, field := range fields {
+		if field.Expr == nil || field.Expr.GetFlag()&ast.FlagHasSubquery == 0 {
+			continue
+		}
+		field.Expr.Accept(resolver)
+		if resolver.err != nil {
+			return nil, resolver.err
+		}
+	}
+	return resolver.aggFuncs, nil
+}
+
+// correlatedAggregateResolver visits the subqueries in an expression and collects the
+// aggregate functions which should be evaluated in the outer query.
+type correlatedAggregateResolver struct {
+	ctx        context.Context
+	b          *PlanBuilder
+	outerNames types.NameSlice
+	aggFuncs   []*ast.AggregateFuncExpr
+	err        error
+}
+
+// Enter implements Visitor interface.
+func (r *correlatedAggregateResolver) Enter(n ast.Node) (ast.Node, bool) {
+	if subq, ok := n.(*ast.SubqueryExpr); ok {
+		if sel, ok := subq.Query.(*ast.SelectStmt); ok {
+			r.err = r.resolveSelect(sel)
+		}
+		// The aggregate functions in the nested subqueries are not considered.
+		return n, true
+	}
+	return n, r.err != nil
+}
+
+// Leave implements Visitor interface.
+func (r *correlatedAggregateResolver) Leave(n ast.Node) (ast.Node, bool) {
+	return n, r.err == nil
+}
+
+func (r *correlatedAggregateResolver) resolveSelect(sel *ast.SelectStmt) error {
+	var innerNames types.NameSlice
+	if sel.From != nil {
+		p, err := r.b.buildResultSetNode(r.ctx, sel.From.TableRefs)
+		if err != nil {
+			return err
+		}
+		r.b.handleHelper.popMap()
+		innerNames = p.OutputNames()
+	}
+	extractor := &AggregateFuncExtractor{}
+	for _, field := range sel.Fields.Fields {
+		if field.Expr != nil {
+			field.Expr.Accept(extractor)
+		}
+	}
+	if sel.Where != nil {
+		sel.Where.Accept(extractor)
+	}
+	for _, aggFunc := range extractor.AggFuncs {
+		collector := &columnNameCollector{}
+		for _, arg := range aggFunc.Args {
+			arg.Accept(collector)
+		}
+		if len(collector.cols) == 0 {
+			continue
+		}
+		isOuter := true
+		for _, col := range collector.cols {
+			innerIdx, err := expression.FindFieldName(innerNames, col.Name)
+			if err != nil || innerIdx >= 0 {
+				isOuter = false
+				break
+			}
+			outerIdx, err := expression.FindFieldName(r.outerNames, col.Name)
+			if err != nil || outerIdx < 0 {
+				isOuter = false
+				break
+			}
+		}
+		if isOuter {
+			r.aggFuncs = append(r.aggFuncs, aggFunc)
+		}
+	}
+	return nil
+}
+
+// columnNameCollector collects the column names in an expression, the nested subqueries are skipped.
+type columnNameCollector struct {
+	cols []*ast.ColumnNameExpr
+}
+
+// Enter implements Visitor interface.
+func (c *columnNameCollector) Enter(n ast.Node) (ast.Node, bool) {
+	switch v := n.(type) {
+	case *ast.SubqueryExpr:
+		return n, true
+	case *ast.ColumnNameExpr:
+		c.cols = append(c.cols, v)
+	}
+	return n, false
+}
+
+// Leave implements Visitor interface.
+func (c *columnNameCollector) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
 // gbyResolver resolves group by items from select fields.
 type gbyResolver struct {
 	ctx    sessionctx.Context
@@ -985,7 +1386,10 @@ type gbyResolver struct {
 func (g *gbyResolver) Enter(inNode ast.Node) (ast.Node, bool) {
 	g.exprDepth++
 	switch inNode.(type) {
-	case *driver.ValueExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ColumnName:
+	case *ast.SubqueryExpr:
+		g.inExpr = true
+		return inNode, true
+	case *driver.ValueExpr, *ast.ColumnNameExpr, *ast.ParenthesesExpr, *ast.ColumnName, *ast.PositionExpr:
 	default:
 		g.inExpr = true
 	}
@@ -1018,6 +1422,18 @@ func (g *gbyResolver) Leave(inNode ast.Node) (ast.Node, bool) {
 			g.err = err
 			return inNode, false
 		}
+	case *ast.PositionExpr:
+		if v.N < 1 || v.N > len(g.fields) {
+			g.err = ErrUnknownColumn.GenWithStackByArgs(strconv.Itoa(v.N), clauseMsg[groupByClause])
+			return inNode, false
+		}
+		field := g.fields[v.N-1]
+		field.Expr.Accept(extractor)
+		if len(extractor.AggFuncs) != 0 {
+			g.err = ErrWrongGroupField.GenWithStackByArgs(field.Text())
+			return inNode, false
+		}
+		return field.Expr, true
 	case *ast.ValuesExpr:
 		if v.Column == nil {
 			g.err = ErrUnknownColumn.GenWithStackByArgs("", "VALUES() function")
@@ -1071,7 +1487,7 @@ func (b *PlanBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectFi
 			col := p.Schema().Columns[i]
 			if (dbName.L == "" || dbName.L == name.DBName.L) &&
 				(tblName.L == "" || tblName.L == name.TblName.L) &&
-				col.ID != model.ExtraHandleID {
+				col.ID != model.ExtraHandleID && !col.IsHidden {
 				findTblNameInSchema = true
 				colName := &ast.ColumnNameExpr{
 					Name: &ast.ColumnName{
@@ -1183,6 +1599,12 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 		b.popTableHints()
 	}()
 
+	oldGroupingCols, oldGID := b.rollupGroupingCols, b.rollupGID
+	b.rollupGroupingCols, b.rollupGID = nil, nil
+	defer func() {
+		b.rollupGroupingCols, b.rollupGID = oldGroupingCols, oldGID
+	}()
+
 	if sel.SelectStmtOpts != nil {
 		origin := b.inStraightJoin
 		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin
@@ -1235,17 +1657,30 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	b.handleHelper.popMap()
 	b.handleHelper.pushMap(nil)
 
-	hasAgg := b.detectSelectAgg(sel)
+	corAggFuncs, err := b.resolveCorrelatedAggregates(ctx, sel.Fields.Fields, p)
+	if err != nil {
+		return nil, err
+	}
+	hasAgg := b.detectSelectAgg(sel) || len(corAggFuncs) > 0
 	if hasAgg {
 		aggFuncs, totalMap = b.extractAggFuncs(sel.Fields.Fields)
+		ownAggLen := len(aggFuncs)
+		aggFuncs = append(aggFuncs, corAggFuncs...)
 		var aggIndexMap map[int]int
-		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, gbyCols)
+		p, aggIndexMap, err = b.buildAggregation(ctx, p, aggFuncs, gbyCols, sel.GroupBy != nil && sel.GroupBy.Rollup)
 		if err != nil {
 			return nil, err
 		}
 		for k, v := range totalMap {
 			totalMap[k] = aggIndexMap[v]
 		}
+		for i, aggFunc := range corAggFuncs {
+			col := p.Schema().Columns[aggIndexMap[ownAggLen+i]]
+			if b.correlatedAggMapper == nil {
+				b.correlatedAggMapper = make(map[*ast.AggregateFuncExpr]*expression.CorrelatedColumn)
+			}
+			b.correlatedAggMapper[aggFunc] = &expression.CorrelatedColumn{Column: *col, Data: new(types.Datum)}
+		}
 	}
 
 	var oldLen int
@@ -1253,6 +1688,14 @@ func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p L
 	if err != nil {
 		return nil, err
 	}
+	if b.rollupGID != nil {
+		// Keep the grouping ID for the GROUPING() in the HAVING and ORDER BY clauses.
+		proj := p.(*LogicalProjection)
+		gid := b.rollupGID.Clone().(*expression.Column)
+		proj.Exprs = append(proj.Exprs, gid)
+		proj.schema.Append(gid)
+		proj.names = append(proj.names, types.EmptyName)
+	}
 
 	if sel.Having != nil {
 		b.curClause = havingClause
@@ -1360,6 +1803,11 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	}
 
 	tableInfo := tbl.Meta()
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.Username, user.Hostname, tableInfo.Name.O)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, dbName.L, tableInfo.Name.L, "", authErr)
 
 	if tbl.Type().IsVirtualTable() {
 		return b.buildMemTable(ctx, dbName, tableInfo)
@@ -1404,6 +1852,7 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 			ID:       col.ID,
 			RetType:  &col.FieldType,
 			OrigName: names[i].String(),
+			IsHidden: col.Hidden,
 		}
 
 		if tableInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag) {
@@ -1437,6 +1886,19 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 	ds.SetSchema(schema)
 	ds.names = names
 
+	// The virtual generated columns are evaluated by the expressions on the other columns.
+	for i, col := range columns {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, _, err := b.rewrite(ctx, col.GeneratedExpr, ds, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		schema.Columns[i].VirtualExpr = expr
+		b.optFlag |= flagGcSubstitute
+	}
+
 	// Init FullIdxCols, FullIdxColLens for accessPaths.
 	for _, path := range ds.possibleAccessPaths {
 		if !path.IsTablePath {
@@ -1448,12 +1910,13 @@ func (b *PlanBuilder) buildDataSource(ctx context.Context, tn *ast.TableName, as
 
 	// If this SQL is executed in a non-readonly transaction, we need a
 	// "UnionScan" operator to read the modifications of former SQLs, which is
-	// buffered in tidb-server memory.
+	// buffered in tidb-server memory. The cached tables also need it to read
+	// the data cached in tidb-server memory.
 	txn, err := b.ctx.Txn(false)
 	if err != nil {
 		return nil, err
 	}
-	if txn.Valid() && !txn.IsReadOnly() {
+	if (txn.Valid() && !txn.IsReadOnly()) || tableInfo.TableCacheStatusType == model.TableCacheStatusEnable {
 		us := LogicalUnionScan{handleCol: handleCol}.Init(b.ctx)
 		us.SetChildren(ds)
 		result = us
@@ -1644,11 +2107,52 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 	tblID2table := make(map[int64]table.Table)
 	for id := range tblID2Handle {
 		tblID2table[id], _ = b.is.TableByID(id)
+		tblInfo := tblID2table[id].Meta()
+		var dbName string
+		if dbInfo, ok := b.is.SchemaByTable(tblInfo); ok {
+			dbName = dbInfo.Name.L
+		}
+		var authErr error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("DELETE", user.Username, user.Hostname, tblInfo.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DeletePriv, dbName, tblInfo.Name.L, "", authErr)
+	}
+	if b.ctx.GetSessionVars().SQLSafeUpdates && delete.Limit == nil && !usesKeyForTables(del.SelectPlan, tblID2table) {
+		return nil, ErrUpdateWithoutKeyInSafeMode
 	}
 	del.TblColPosInfos, err = buildColumns2Handle(del.names, tblID2Handle, tblID2table, false)
 	return del, err
 }
 
+// usesKeyForTables checks whether every scan on the tables in tblID2table is
+// restricted by a range built from the primary key or an index, which is what
+// the safe update mode requires.
+func usesKeyForTables(p PhysicalPlan, tblID2table map[int64]table.Table) bool {
+	switch x := p.(type) {
+	case *PhysicalTableScan:
+		_, ok := tblID2table[x.Table.ID]
+		return !ok || len(x.AccessCondition) > 0
+	case *PhysicalIndexScan:
+		_, ok := tblID2table[x.Table.ID]
+		return !ok || len(x.AccessCondition) > 0
+	case *PhysicalTableReader:
+		return usesKeyForTables(x.tablePlan, tblID2table)
+	case *PhysicalIndexReader:
+		return usesKeyForTables(x.indexPlan, tblID2table)
+	case *PhysicalIndexLookUpReader:
+		return usesKeyForTables(x.indexPlan, tblID2table)
+	case *PhysicalTableLookUpReader:
+		return usesKeyForTables(x.filterPlan, tblID2table)
+	}
+	for _, child := range p.Children() {
+		if !usesKeyForTables(child, tblID2table) {
+			return false
+		}
+	}
+	return true
+}
+
 func resolveIndicesForTblID2Handle(tblID2Handle map[int64][]*expression.Column, schema *expression.Schema) (map[int64][]*expression.Column, error) {
 	newMap := make(map[int64][]*expression.Column, len(tblID2Handle))
 	for i, cols := range tblID2Handle {
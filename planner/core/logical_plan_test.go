@@ -16,6 +16,7 @@ package core
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
@@ -23,7 +24,9 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/sessionctx"
@@ -459,6 +462,18 @@ func (s *testPlanSuite) TestValidate(c *C) {
 			sql: "select a from t having sum(avg(a))",
 			err: ErrInvalidGroupFuncUse,
 		},
+		{
+			sql: "select a, sum(b) as s from t group by a having max(s) > 1",
+			err: ErrIllegalReference,
+		},
+		{
+			sql: "select a, sum(b) as s from t group by a order by max(s)",
+			err: ErrIllegalReference,
+		},
+		{
+			sql: "select a as x, b + 1 as y from t group by a, b having x > 1 and sum(y) > 1",
+			err: nil,
+		},
 	}
 
 	ctx := context.Background()
@@ -554,6 +569,30 @@ func (s *testPlanSuite) TestAggPrune(c *C) {
 	}
 }
 
+func (s *testPlanSuite) TestMaxOneRowEliminator(c *C) {
+	defer testleak.AfterTest(c)()
+	var input, output []string
+	s.testData.GetTestCases(c, &input, &output)
+
+	ctx := context.Background()
+	for i, tt := range input {
+		comment := Commentf("for %s", tt)
+		stmt, err := s.ParseOneStmt(tt, "", "")
+		c.Assert(err, IsNil, comment)
+
+		p, _, err := BuildLogicalPlan(ctx, s.ctx, stmt, s.is)
+		c.Assert(err, IsNil)
+
+		p, err = logicalOptimize(context.TODO(), flagPredicatePushDown|flagPrunColumns|flagBuildKeyInfo|flagEliminateMaxOneRow|flagEliminateAgg|flagEliminateProjection, p.(LogicalPlan))
+		c.Assert(err, IsNil)
+		planString := ToString(p)
+		s.testData.OnRecord(func() {
+			output[i] = planString
+		})
+		c.Assert(planString, Equals, output[i], comment)
+	}
+}
+
 func (s *testPlanSuite) TestTopNPushDown(c *C) {
 	defer func() {
 		testleak.AfterTest(c)()
@@ -750,7 +789,7 @@ func (s *testPlanSuite) TestSkylinePruning(c *C) {
 				lp = lp.Children()[0]
 			}
 		}
-		paths := ds.skylinePruning(byItemsToProperty(byItems))
+		paths := ds.skylinePruning(byItemsToProperty(byItems), nil)
 		c.Assert(pathsName(paths), Equals, tt.result, comment)
 	}
 }
@@ -780,3 +819,201 @@ func (s *testPlanSuite) TestUpdateEQCond(c *C) {
 		c.Assert(ToString(p), Equals, tt.best, comment)
 	}
 }
+
+func (s *testPlanSuite) TestVisitInfo(c *C) {
+	defer testleak.AfterTest(c)()
+	tests := []struct {
+		sql string
+		ans []visitInfo
+	}{
+		{
+			sql: "insert into t (a) values (1)",
+			ans: []visitInfo{
+				{mysql.InsertPriv, "test", "t", "", nil},
+			},
+		},
+		{
+			sql: "delete from t where a = 1",
+			ans: []visitInfo{
+				{mysql.SelectPriv, "test", "t", "", nil},
+				{mysql.DeletePriv, "test", "t", "", nil},
+			},
+		},
+		{
+			sql: "select * from t join t2 on t.a = t2.a",
+			ans: []visitInfo{
+				{mysql.SelectPriv, "test", "t", "", nil},
+				{mysql.SelectPriv, "test", "t2", "", nil},
+			},
+		},
+		{
+			sql: "insert into t (a) select a from t2",
+			ans: []visitInfo{
+				{mysql.InsertPriv, "test", "t", "", nil},
+				{mysql.SelectPriv, "test", "t2", "", nil},
+			},
+		},
+		{
+			sql: "create database test_db",
+			ans: []visitInfo{
+				{mysql.CreatePriv, "test_db", "", "", nil},
+			},
+		},
+		{
+			sql: "drop database test_db",
+			ans: []visitInfo{
+				{mysql.DropPriv, "test_db", "", "", nil},
+			},
+		},
+		{
+			sql: "create table t3 (a int)",
+			ans: []visitInfo{
+				{mysql.CreatePriv, "test", "t3", "", nil},
+			},
+		},
+		{
+			sql: "drop table t, t2",
+			ans: []visitInfo{
+				{mysql.DropPriv, "test", "t", "", nil},
+				{mysql.DropPriv, "test", "t2", "", nil},
+			},
+		},
+		{
+			sql: "create index idx_b on t (b)",
+			ans: []visitInfo{
+				{mysql.IndexPriv, "test", "t", "", nil},
+			},
+		},
+		{
+			sql: "alter table t add column z int",
+			ans: []visitInfo{
+				{mysql.AlterPriv, "test", "t", "", nil},
+			},
+		},
+		{
+			sql: "truncate table t",
+			ans: []visitInfo{
+				{mysql.DropPriv, "test", "t", "", nil},
+			},
+		},
+		{
+			sql: "analyze table t",
+			ans: []visitInfo{
+				{mysql.InsertPriv, "test", "t", "", nil},
+				{mysql.SelectPriv, "test", "t", "", nil},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		comment := Commentf("for %s", tt.sql)
+		stmt, err := s.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		Preprocess(s.ctx, stmt, s.is)
+		builder := NewPlanBuilder(MockContext(), s.is)
+		_, err = builder.Build(context.TODO(), stmt)
+		c.Assert(err, IsNil, comment)
+
+		checkVisitInfo(c, builder.visitInfo, tt.ans, comment)
+	}
+}
+
+type visitInfoArray []visitInfo
+
+func (v visitInfoArray) Len() int {
+	return len(v)
+}
+
+func (v visitInfoArray) Less(i, j int) bool {
+	if v[i].privilege < v[j].privilege {
+		return true
+	}
+	if v[i].privilege > v[j].privilege {
+		return false
+	}
+	if v[i].db < v[j].db {
+		return true
+	}
+	if v[i].db > v[j].db {
+		return false
+	}
+	if v[i].table < v[j].table {
+		return true
+	}
+	if v[i].table > v[j].table {
+		return false
+	}
+	return v[i].column < v[j].column
+}
+
+func (v visitInfoArray) Swap(i, j int) {
+	v[i], v[j] = v[j], v[i]
+}
+
+func unique(v []visitInfo) []visitInfo {
+	repeat := 0
+	for i := 1; i < len(v); i++ {
+		if v[i] == v[i-1] {
+			repeat++
+		} else {
+			v[i-repeat] = v[i]
+		}
+	}
+	return v[:len(v)-repeat]
+}
+
+func checkVisitInfo(c *C, v1, v2 []visitInfo, comment CommentInterface) {
+	sort.Sort(visitInfoArray(v1))
+	sort.Sort(visitInfoArray(v2))
+	v1 = unique(v1)
+	v2 = unique(v2)
+
+	c.Assert(len(v1), Equals, len(v2), comment)
+	for i := 0; i < len(v1); i++ {
+		c.Assert(v1[i], Equals, v2[i], comment)
+	}
+}
+
+type mockPrivilegeManager struct {
+	denied map[string]mysql.PrivilegeType
+}
+
+func (m *mockPrivilegeManager) RequestVerification(db, table, column string, priv mysql.PrivilegeType) bool {
+	return m.denied[db+"."+table]&priv == 0
+}
+
+func (s *testPlanSuite) TestCheckPrivilege(c *C) {
+	defer testleak.AfterTest(c)()
+	ctx := MockContext()
+	ctx.GetSessionVars().User = &auth.UserIdentity{Username: "u1", Hostname: "localhost"}
+	pm := &mockPrivilegeManager{denied: map[string]mysql.PrivilegeType{
+		"test.t2": mysql.SelectPriv,
+		"test.":   mysql.DropPriv,
+	}}
+	tests := []struct {
+		sql string
+		err string
+	}{
+		{"select * from t", ""},
+		{"insert into t (a) values (1)", ""},
+		{"select * from t join t2 on t.a = t2.a", "[planner:1142]SELECT command denied to user 'u1'@'localhost' for table 't2'"},
+		{"insert into t (a) select a from t2", "[planner:1142]SELECT command denied to user 'u1'@'localhost' for table 't2'"},
+		{"drop database test", "[planner:1044]Access denied for user 'u1'@'localhost' to database 'test'"},
+	}
+	for _, tt := range tests {
+		comment := Commentf("for %s", tt.sql)
+		stmt, err := s.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		Preprocess(ctx, stmt, s.is)
+		builder := NewPlanBuilder(ctx, s.is)
+		_, err = builder.Build(context.TODO(), stmt)
+		c.Assert(err, IsNil, comment)
+		err = CheckPrivilege(pm, builder.GetVisitInfo())
+		if tt.err == "" {
+			c.Assert(err, IsNil, comment)
+		} else {
+			c.Assert(err, NotNil, comment)
+			c.Assert(err.Error(), Equals, tt.err, comment)
+		}
+	}
+}
@@ -110,6 +110,11 @@ type LogicalJoin struct {
 
 	// equalCondOutCnt indicates the estimated count of joined rows after evaluating `EqualConditions`.
 	equalCondOutCnt float64
+
+	// redundantSchema contains columns which are eliminated in join.
+	// For select * from a join b using (c); a.c will in output schema, and b.c will in redundantSchema.
+	redundantSchema *expression.Schema
+	redundantNames  types.NameSlice
 }
 
 func (p *LogicalJoin) attachOnConds(onConds []expression.Expression) {
@@ -120,6 +125,49 @@ func (p *LogicalJoin) attachOnConds(onConds []expression.Expression) {
 	p.OtherConditions = append(other, p.OtherConditions...)
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJoin) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.EqualConditions)+len(p.LeftConditions)+len(p.RightConditions)+len(p.OtherConditions))
+	for _, fun := range p.EqualConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.LeftConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.RightConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	for _, fun := range p.OtherConditions {
+		corCols = append(corCols, expression.ExtractCorColumns(fun)...)
+	}
+	return corCols
+}
+
+// LogicalApply gets one row from the outer executor and gets all the rows from the inner executor, which
+// are evaluated with the correlated columns set to the values of the outer row.
+type LogicalApply struct {
+	LogicalJoin
+
+	// CorCols are the correlated columns of the inner plan which refer to the outer plan.
+	CorCols []*expression.CorrelatedColumn
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (la *LogicalApply) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := la.LogicalJoin.ExtractCorrelatedCols()
+	for i := len(corCols) - 1; i >= 0; i-- {
+		if la.children[0].Schema().Contains(&corCols[i].Column) {
+			corCols = append(corCols[:i], corCols[i+1:]...)
+		}
+	}
+	return corCols
+}
+
+// LogicalMaxOneRow checks if a query returns no more than one row.
+type LogicalMaxOneRow struct {
+	baseLogicalPlan
+}
+
 // LogicalProjection represents a select fields plan.
 type LogicalProjection struct {
 	logicalSchemaProducer
@@ -127,6 +175,39 @@ type LogicalProjection struct {
 	Exprs []expression.Expression
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalProjection) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.Exprs))
+	for _, expr := range p.Exprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
+// LogicalExpand replicates every input row once for each grouping set of ROLLUP.
+// For "group by a, b with rollup", the rows of level 0 keep a and b, the rows of
+// level 1 replace b by NULL, and the rows of level 2 replace both of them by NULL.
+// The nullable copies of the group-by columns and the grouping ID, which marks the
+// replaced columns, are appended to the output, so the aggregation above can group
+// by them while the aggregate functions still read the original columns.
+type LogicalExpand struct {
+	logicalSchemaProducer
+
+	// LevelExprs are the output expressions of each level.
+	LevelExprs [][]expression.Expression
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalExpand) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0)
+	for _, exprs := range p.LevelExprs {
+		for _, expr := range exprs {
+			corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+		}
+	}
+	return corCols
+}
+
 // LogicalAggregation represents an aggregate plan.
 type LogicalAggregation struct {
 	logicalSchemaProducer
@@ -140,6 +221,20 @@ type LogicalAggregation struct {
 	inputCount         float64 // inputCount is the input count of this plan.
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (la *LogicalAggregation) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(la.GroupByItems))
+	for _, expr := range la.GroupByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	for _, fun := range la.AggFuncs {
+		for _, arg := range fun.Args {
+			corCols = append(corCols, expression.ExtractCorColumns(arg)...)
+		}
+	}
+	return corCols
+}
+
 // IsPartialModeAgg returns if all of the AggFuncs are partialMode.
 func (la *LogicalAggregation) IsPartialModeAgg() bool {
 	// Since all of the AggFunc share the same AggMode, we only need to check the first one.
@@ -166,6 +261,15 @@ type LogicalSelection struct {
 	Conditions []expression.Expression
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalSelection) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(p.Conditions))
+	for _, cond := range p.Conditions {
+		corCols = append(corCols, expression.ExtractCorColumns(cond)...)
+	}
+	return corCols
+}
+
 // LogicalTableDual represents a dual table plan.
 type LogicalTableDual struct {
 	logicalSchemaProducer
@@ -224,6 +328,15 @@ type DataSource struct {
 	TblColHists *statistics.HistColl
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (ds *DataSource) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(ds.pushedDownConds))
+	for _, expr := range ds.pushedDownConds {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	return corCols
+}
+
 // TiKVSingleGather is a leaf logical operator of TiDB layer to gather
 // tuples from TiKV regions.
 type TiKVSingleGather struct {
@@ -269,12 +382,9 @@ func (p *LogicalIndexScan) MatchIndexProp(prop *property.PhysicalProperty) (matc
 	if prop.IsEmpty() {
 		return true
 	}
-	if all, _ := prop.AllSameOrder(); !all {
-		return false
-	}
 	for i, col := range p.IdxCols {
 		if col.Equal(nil, prop.Items[0].Col) {
-			return matchIndicesProp(p.IdxCols[i:], p.IdxColLens[i:], prop.Items)
+			return matchIndicesProp(p.IdxCols[i:], p.IdxColLens[i:], indexColDescs(p.Index, i), prop.Items)
 		} else if i >= p.EqCondCount {
 			break
 		}
@@ -282,6 +392,12 @@ func (p *LogicalIndexScan) MatchIndexProp(prop *property.PhysicalProperty) (matc
 	return false
 }
 
+// IsScanDesc checks whether the indexScan needs to be scanned in descending order to
+// match the required property.
+func (p *LogicalIndexScan) IsScanDesc(prop *property.PhysicalProperty) bool {
+	return isIndexScanDesc(p.IdxCols, p.Index, prop)
+}
+
 // getTablePath finds the TablePath from a group of accessPaths.
 func getTablePath(paths []*util.AccessPath) *util.AccessPath {
 	for _, path := range paths {
@@ -412,12 +528,22 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 		if err != nil {
 			return err
 		}
+		if path.Index.DescColumns() != nil && !ranger.IsPrefixPointRanges(sc, res.Ranges) {
+			// A range that varies on more than one column can't be converted to a key range of
+			// an index with descending columns, so we scan the whole index and filter the rows.
+			path.TableFilters = conds
+			return nil
+		}
 		path.Ranges = res.Ranges
 		path.AccessConds = res.AccessConds
 		path.TableFilters = res.RemainedConds
 		path.EqCondCount = res.EqCondCount
 		path.EqOrInCondCount = res.EqOrInCount
 		path.IsDNFCond = res.IsDNFCond
+		if path.IsHypothetical {
+			path.CountAfterAccess = ds.hypoIndexRowCount(path.AccessConds)
+			return nil
+		}
 		path.CountAfterAccess, err = ds.tableStats.HistColl.GetRowCountByIndexRanges(sc, path.Index.ID, path.Ranges)
 		if err != nil {
 			return err
@@ -428,6 +554,21 @@ func (ds *DataSource) fillIndexPath(path *util.AccessPath, conds []expression.Ex
 	return nil
 }
 
+// hypoIndexRowCount estimates the row count after the access conditions of a hypothetical
+// index, which has no statistics, by the statistics of the columns.
+func (ds *DataSource) hypoIndexRowCount(accessConds []expression.Expression) float64 {
+	count := float64(ds.statisticTable.Count)
+	if len(accessConds) == 0 {
+		return count
+	}
+	selectivity, err := ds.tableStats.HistColl.Selectivity(ds.ctx, accessConds, nil)
+	if err != nil {
+		logutil.BgLogger().Debug("calculate selectivity failed, use selection factor", zap.Error(err))
+		selectivity = selectionFactor
+	}
+	return count * selectivity
+}
+
 // deriveIndexPathStats will fulfill the information that the AccessPath need.
 // And it will check whether this index is full matched by point query. We will use this check to
 // determine whether we remove other paths or not.
@@ -551,6 +692,15 @@ type LogicalSort struct {
 	ByItems []*ByItems
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (ls *LogicalSort) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(ls.ByItems))
+	for _, item := range ls.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // LogicalTopN represents a top-n plan.
 type LogicalTopN struct {
 	baseLogicalPlan
@@ -560,6 +710,15 @@ type LogicalTopN struct {
 	Count   uint64
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (lt *LogicalTopN) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	corCols := make([]*expression.CorrelatedColumn, 0, len(lt.ByItems))
+	for _, item := range lt.ByItems {
+		corCols = append(corCols, expression.ExtractCorColumns(item.Expr)...)
+	}
+	return corCols
+}
+
 // isLimit checks if TopN is a limit plan.
 func (lt *LogicalTopN) isLimit() bool {
 	return len(lt.ByItems) == 0
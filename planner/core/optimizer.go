@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/planner/property"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 )
@@ -29,8 +30,10 @@ import (
 var OptimizeAstNode func(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (Plan, types.NameSlice, error)
 
 const (
-	flagPrunColumns uint64 = 1 << iota
+	flagGcSubstitute uint64 = 1 << iota
+	flagPrunColumns
 	flagBuildKeyInfo
+	flagEliminateMaxOneRow
 	flagEliminateAgg
 	flagEliminateProjection
 	flagMaxMinEliminate
@@ -42,8 +45,10 @@ const (
 )
 
 var optRuleList = []logicalOptRule{
+	&gcSubstituter{},
 	&columnPruner{},
 	&buildKeySolver{},
+	&maxOneRowEliminator{},
 	&aggregationEliminator{},
 	&projectionEliminator{},
 	&maxMinEliminator{},
@@ -72,28 +77,61 @@ func BuildLogicalPlan(ctx context.Context, sctx sessionctx.Context, node ast.Nod
 	return p, p.OutputNames(), err
 }
 
+// CheckPrivilege checks the privilege for a user.
+func CheckPrivilege(pm privilege.Manager, vs []visitInfo) error {
+	for _, v := range vs {
+		if !pm.RequestVerification(v.db, v.table, v.column, v.privilege) {
+			if v.err == nil {
+				return ErrPrivilegeCheckFail
+			}
+			return v.err
+		}
+	}
+	return nil
+}
+
 // DoOptimize optimizes a logical plan to a physical plan.
 func DoOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (PhysicalPlan, error) {
+	finalPlan, _, err := DoOptimizeWithCost(ctx, flag, logic)
+	return finalPlan, err
+}
+
+// DoOptimizeWithCost optimizes a logical plan to a physical plan, and returns the estimated
+// cost of the physical plan.
+func DoOptimizeWithCost(ctx context.Context, flag uint64, logic LogicalPlan) (PhysicalPlan, float64, error) {
 	logic, err := logicalOptimize(ctx, flag, logic)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	physical, err := physicalOptimize(logic)
+	physical, cost, err := physicalOptimize(logic)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	finalPlan := postOptimize(physical)
-	return finalPlan, nil
+	finalPlan, err := postOptimize(physical)
+	if err != nil {
+		return nil, 0, err
+	}
+	if tracer := finalPlan.SCtx().GetSessionVars().StmtCtx.OptimTracer; tracer != nil {
+		tracer.FinalPlan = ToString(finalPlan)
+		tracer.Cost = cost
+	}
+	return finalPlan, cost, nil
 }
 
-func postOptimize(plan PhysicalPlan) PhysicalPlan {
+func postOptimize(plan PhysicalPlan) (PhysicalPlan, error) {
 	plan = eliminatePhysicalProjection(plan)
+	plan = applyLateMaterialization(plan)
+	if err := pruneCopOutputColumns(plan); err != nil {
+		return nil, err
+	}
+	orderSelectionConditions(plan)
 	plan = injectExtraProjection(plan)
-	return plan
+	return plan, nil
 }
 
 func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (LogicalPlan, error) {
 	var err error
+	tracer := logic.SCtx().GetSessionVars().StmtCtx.OptimTracer
 	for i, rule := range optRuleList {
 		// The order of flags is same as the order of optRule in the list.
 		// We use a bitmask to record which opt rules should be used. If the i-th bit is 1, it means we should
@@ -101,17 +139,24 @@ func logicalOptimize(ctx context.Context, flag uint64, logic LogicalPlan) (Logic
 		if flag&(1<<uint(i)) == 0 {
 			continue
 		}
+		var before string
+		if tracer != nil {
+			before = ToString(logic)
+		}
 		logic, err = rule.optimize(ctx, logic)
 		if err != nil {
 			return nil, err
 		}
+		if tracer != nil {
+			tracer.AppendLogicalRule(rule.name(), before, ToString(logic))
+		}
 	}
 	return logic, err
 }
 
-func physicalOptimize(logic LogicalPlan) (PhysicalPlan, error) {
+func physicalOptimize(logic LogicalPlan) (PhysicalPlan, float64, error) {
 	if _, err := logic.recursiveDeriveStats(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	preparePossibleProperties(logic)
@@ -123,16 +168,17 @@ func physicalOptimize(logic LogicalPlan) (PhysicalPlan, error) {
 
 	t, err := logic.findBestTask(prop)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if t.invalid() {
-		return nil, ErrInternal.GenWithStackByArgs("Can't find a proper physical plan for this query")
+		return nil, 0, ErrInternal.GenWithStackByArgs("Can't find a proper physical plan for this query")
 	}
 
 	err = t.plan().ResolveIndices()
-	return t.plan(), err
+	return t.plan(), t.cost(), err
 }
 
 func init() {
 	expression.EvalAstExpr = evalAstExpr
+	expression.RewriteAstExpr = rewriteAstExpr
 }
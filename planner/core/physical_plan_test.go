@@ -17,6 +17,7 @@ import (
 	"context"
 
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser"
@@ -443,3 +444,104 @@ func (s *testPlanSuite) TestIndexHint(c *C) {
 		}
 	}
 }
+
+func (s *testPlanSuite) TestCopOutputColumns(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	se, err := session.CreateSession4Test(store)
+	c.Assert(err, IsNil)
+	_, err = se.Execute(context.Background(), "use test")
+	c.Assert(err, IsNil)
+	tests := []struct {
+		sql     string
+		best    string
+		offsets []int
+	}{
+		{
+			sql:     "select b, c from t where d > 1",
+			best:    "TableReader(Table(t)->Sel([gt(test.t.d, 1)]))->Projection",
+			offsets: []int{0, 1},
+		},
+		{
+			sql:     "select d from t where b > 1",
+			best:    "TableReader(Table(t)->Sel([gt(test.t.b, 1)]))->Projection",
+			offsets: []int{1},
+		},
+		{
+			sql:     "select e from t where c = 1 and d > 1",
+			best:    "IndexReader(Index(t.c_d_e)[(1 1,1 +inf]])->Projection",
+			offsets: []int{2},
+		},
+		// At least one column is returned.
+		{
+			sql:     "select 1 from t where b > 1",
+			best:    "TableReader(Table(t)->Sel([gt(test.t.b, 1)]))->Projection",
+			offsets: []int{0},
+		},
+	}
+	for _, tt := range tests {
+		comment := Commentf("for %s", tt.sql)
+		stmt, err := s.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		c.Assert(se.NewTxn(context.Background()), IsNil)
+		p, _, err := planner.Optimize(context.TODO(), se, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		c.Assert(core.ToString(p), Equals, tt.best, comment)
+		var outputCols []*expression.Column
+		switch reader := p.(core.PhysicalPlan).Children()[0].(type) {
+		case *core.PhysicalTableReader:
+			outputCols = reader.OutputColumns
+		case *core.PhysicalIndexReader:
+			outputCols = reader.OutputColumns
+		}
+		offsets := make([]int, 0, len(outputCols))
+		for _, col := range outputCols {
+			offsets = append(offsets, col.Index)
+		}
+		c.Assert(offsets, DeepEquals, tt.offsets, comment)
+	}
+}
+
+func (s *testPlanSuite) TestSelectionConditionOrder(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	defer func() {
+		dom.Close()
+		store.Close()
+	}()
+	se, err := session.CreateSession4Test(store)
+	c.Assert(err, IsNil)
+	_, err = se.Execute(context.Background(), "use test")
+	c.Assert(err, IsNil)
+	tests := []struct {
+		sql  string
+		best string
+	}{
+		{
+			sql:  "select * from t where b in (1, 2, 3) and d > 1",
+			best: "TableReader(Table(t)->Sel([gt(test.t.d, 1) in(test.t.b, 1, 2, 3)]))",
+		},
+		{
+			sql:  "select * from t where md5(c_str) = 'a' and b in (1, 2, 3) and d > 1",
+			best: "TableReader(Table(t)->Sel([gt(test.t.d, 1) in(test.t.b, 1, 2, 3)]))->Sel([eq(md5(test.t.c_str), a)])",
+		},
+		{
+			sql:  "select * from t where d_str like '%a' and b > 1 and b < 5",
+			best: "TableReader(Table(t)->Sel([gt(test.t.b, 1) lt(test.t.b, 5) like(test.t.d_str, %a, 92)]))",
+		},
+	}
+	for _, tt := range tests {
+		comment := Commentf("for %s", tt.sql)
+		stmt, err := s.ParseOneStmt(tt.sql, "", "")
+		c.Assert(err, IsNil, comment)
+		p, _, err := planner.Optimize(context.TODO(), se, stmt, s.is)
+		c.Assert(err, IsNil, comment)
+		c.Assert(core.ToString(p), Equals, tt.best, comment)
+	}
+}
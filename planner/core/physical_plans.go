@@ -36,6 +36,7 @@ var (
 	_ PhysicalPlan = &PhysicalTableReader{}
 	_ PhysicalPlan = &PhysicalIndexReader{}
 	_ PhysicalPlan = &PhysicalIndexLookUpReader{}
+	_ PhysicalPlan = &PhysicalTableLookUpReader{}
 	_ PhysicalPlan = &PhysicalHashAgg{}
 	_ PhysicalPlan = &PhysicalHashJoin{}
 	_ PhysicalPlan = &PhysicalMergeJoin{}
@@ -49,6 +50,9 @@ type PhysicalTableReader struct {
 	// TablePlans flats the tablePlan to construct executor pb.
 	TablePlans []PhysicalPlan
 	tablePlan  PhysicalPlan
+
+	// OutputColumns represents the columns that table reader should return.
+	OutputColumns []*expression.Column
 }
 
 // GetPhysicalTableReader returns PhysicalTableReader for logical TiKVSingleGather.
@@ -73,6 +77,7 @@ func (sg *TiKVSingleGather) GetPhysicalIndexReader(schema *expression.Schema, st
 func (p *PhysicalTableReader) SetChildren(children ...PhysicalPlan) {
 	p.tablePlan = children[0]
 	p.TablePlans = flattenPushDownPlan(p.tablePlan)
+	p.OutputColumns = p.tablePlan.Schema().Clone().Columns
 }
 
 // PhysicalIndexReader is the index reader in tidb.
@@ -122,6 +127,23 @@ type PhysicalIndexLookUpReader struct {
 	ExtraHandleCol *expression.Column
 }
 
+// PhysicalTableLookUpReader is the table reader with late materialization. It scans the
+// columns used by the filters and the handle first, then reads the other columns of the
+// qualified rows by their handles.
+type PhysicalTableLookUpReader struct {
+	physicalSchemaProducer
+
+	// FilterPlans flats the filterPlan to construct executor pb.
+	FilterPlans []PhysicalPlan
+	// TablePlans flats the tablePlan to construct executor pb.
+	TablePlans []PhysicalPlan
+	filterPlan PhysicalPlan
+	tablePlan  PhysicalPlan
+
+	// HandleCol is the handle column in the schema of filterPlan.
+	HandleCol *expression.Column
+}
+
 // PhysicalIndexScan represents an index scan plan.
 type PhysicalIndexScan struct {
 	physicalSchemaProducer
@@ -189,6 +211,13 @@ type PhysicalProjection struct {
 	Exprs []expression.Expression
 }
 
+// PhysicalExpand is the physical operator of LogicalExpand.
+type PhysicalExpand struct {
+	physicalSchemaProducer
+
+	LevelExprs [][]expression.Expression
+}
+
 // PhysicalTopN is the physical operator of topN.
 type PhysicalTopN struct {
 	basePhysicalPlan
@@ -243,6 +272,15 @@ func NewPhysicalHashJoin(p *LogicalJoin, innerIdx int, newStats *property.StatsI
 	return hashJoin
 }
 
+// PhysicalApply represents apply plan, only used for subquery.
+// For every outer row, the correlated columns in OuterSchema are set to the values of the row,
+// then the inner plan is executed and joined with the row.
+type PhysicalApply struct {
+	PhysicalHashJoin
+
+	OuterSchema []*expression.CorrelatedColumn
+}
+
 // PhysicalMergeJoin represents merge join implementation of LogicalJoin.
 type PhysicalMergeJoin struct {
 	basePhysicalJoin
@@ -250,6 +288,11 @@ type PhysicalMergeJoin struct {
 	CompareFuncs []expression.CompareFunc
 }
 
+// PhysicalMaxOneRow is the physical operator of maxOneRow.
+type PhysicalMaxOneRow struct {
+	basePhysicalPlan
+}
+
 // PhysicalLimit is the physical operator of Limit.
 type PhysicalLimit struct {
 	basePhysicalPlan
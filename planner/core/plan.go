@@ -98,6 +98,10 @@ type LogicalPlan interface {
 	// and `childSchema` instead.
 	BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema)
 
+	// MaxOneRow means whether this operator returns no more than one row. It's collected
+	// together with the unique keys by the build_keys rule.
+	MaxOneRow() bool
+
 	// pushDownTopN will push down the topN or limit operator during logical optimization.
 	pushDownTopN(topN *LogicalTopN) LogicalPlan
 
@@ -124,6 +128,9 @@ type LogicalPlan interface {
 
 	// SetChild sets the ith child for the plan.
 	SetChild(i int, child LogicalPlan)
+
+	// ExtractCorrelatedCols extracts correlated columns inside the LogicalPlan.
+	ExtractCorrelatedCols() []*expression.CorrelatedColumn
 }
 
 // PhysicalPlan is a tree of the physical operators.
@@ -165,9 +172,15 @@ type PhysicalPlan interface {
 type baseLogicalPlan struct {
 	basePlan
 
-	taskMap  map[string]task
-	self     LogicalPlan
-	children []LogicalPlan
+	taskMap   map[string]task
+	self      LogicalPlan
+	children  []LogicalPlan
+	maxOneRow bool
+}
+
+// MaxOneRow implements LogicalPlan MaxOneRow interface.
+func (p *baseLogicalPlan) MaxOneRow() bool {
+	return p.maxOneRow
 }
 
 // ExplainInfo implements Plan interface.
@@ -209,11 +222,17 @@ func (p *baseLogicalPlan) storeTask(prop *property.PhysicalProperty, task task)
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *baseLogicalPlan) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
+	childMaxOneRow := make([]bool, len(p.children))
+	for i, child := range p.children {
+		childMaxOneRow[i] = child.MaxOneRow()
+	}
+	p.maxOneRow = hasMaxOneRow(p.self, childMaxOneRow)
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *logicalSchemaProducer) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	selfSchema.Keys = nil
+	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
 }
 
 func newBasePlan(ctx sessionctx.Context, tp string) basePlan {
@@ -241,6 +260,11 @@ func newBasePhysicalPlan(ctx sessionctx.Context, tp string, self PhysicalPlan) b
 	}
 }
 
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *baseLogicalPlan) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return nil
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *baseLogicalPlan) PruneColumns(parentUsedCols []*expression.Column) error {
 	if len(p.children) == 0 {
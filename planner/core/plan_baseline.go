@@ -0,0 +1,117 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/planner/util"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// MatchPlanBaseline returns the plan baseline of the statement, nil is returned if the
+// statement has no baseline or the baselines are disabled. The statements with optimizer
+// hints don't use the baselines, their hints take precedence.
+func MatchPlanBaseline(sctx sessionctx.Context, node ast.Node) *bindinfo.Baseline {
+	vars := sctx.GetSessionVars()
+	if !vars.UsePlanBaselines || vars.StmtCtx.InExplainStmt {
+		return nil
+	}
+	sel, ok := node.(*ast.SelectStmt)
+	if !ok || sel.Text() == "" || len(sel.TableHints) > 0 {
+		return nil
+	}
+	dom := domain.GetDomain(sctx)
+	if dom == nil {
+		return nil
+	}
+	// The statement context keeps the digest of the statement being executed, it's reused unless
+	// the node is another statement, such as a subquery or a statement planned internally.
+	digest := vars.StmtCtx.SQLDigest()
+	if sel.Text() != vars.StmtCtx.OriginalSQL {
+		digest = parser.DigestHash(sel.Text())
+	}
+	return dom.PlanBaselineHandle().Get(vars.CurrentDB, digest)
+}
+
+// PlanAccessPaths returns the access paths of the tables read by the physical plan.
+func PlanAccessPaths(p Plan) []bindinfo.TableAccessPath {
+	var paths []bindinfo.TableAccessPath
+	var collect func(p PhysicalPlan)
+	collect = func(p PhysicalPlan) {
+		var scan PhysicalPlan
+		switch x := p.(type) {
+		case *PhysicalTableReader:
+			scan = x.TablePlans[0]
+		case *PhysicalIndexReader:
+			scan = x.IndexPlans[0]
+		case *PhysicalIndexLookUpReader:
+			scan = x.IndexPlans[0]
+		case *PhysicalTableLookUpReader:
+			scan = x.FilterPlans[0]
+		}
+		switch x := scan.(type) {
+		case *PhysicalTableScan:
+			paths = append(paths, newTableAccessPath(x.DBName, x.Table, x.TableAsName, nil))
+			return
+		case *PhysicalIndexScan:
+			paths = append(paths, newTableAccessPath(x.DBName, x.Table, x.TableAsName, x.Index))
+			return
+		}
+		for _, child := range p.Children() {
+			collect(child)
+		}
+	}
+	if physicalPlan, ok := p.(PhysicalPlan); ok {
+		collect(physicalPlan)
+	}
+	return paths
+}
+
+func newTableAccessPath(dbName model.CIStr, tblInfo *model.TableInfo, asName *model.CIStr, idx *model.IndexInfo) bindinfo.TableAccessPath {
+	path := bindinfo.TableAccessPath{DB: dbName.L, Table: tblInfo.Name.L}
+	if asName != nil && asName.L != "" {
+		path.Table = asName.L
+	}
+	if idx != nil {
+		path.Index = idx.Name.L
+	}
+	return path
+}
+
+// planBaselineHint returns the index hint which forces the access path of the table in the
+// plan baseline, nil is returned if the baseline doesn't contain the table or the index of
+// the access path doesn't exist any more.
+func (b *PlanBuilder) planBaselineHint(paths []*util.AccessPath, tblInfo *model.TableInfo, dbName, tblName model.CIStr) *ast.IndexHint {
+	baseline := b.ctx.GetSessionVars().StmtCtx.PlanBaseline
+	if baseline == nil {
+		return nil
+	}
+	path, ok := baseline.AccessPathOf(dbName.L, tblName.L)
+	if !ok {
+		return nil
+	}
+	hint := &ast.IndexHint{HintType: ast.HintUse, HintScope: ast.HintForScan}
+	if path.Index != "" {
+		idxName := model.NewCIStr(path.Index)
+		if getPathByIndexName(paths, idxName, tblInfo) == nil {
+			return nil
+		}
+		hint.IndexNames = []model.CIStr{idxName}
+	}
+	return hint
+}
@@ -0,0 +1,477 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/ranger"
+)
+
+// PlanCacheStmt is an ad-hoc statement which can use the non-prepared plan cache.
+// The literals compared with the columns in its WHERE clause are its parameters,
+// the statements which only differ in these parameters share the same cached plan.
+type PlanCacheStmt struct {
+	key    planCacheKey
+	params []*driver.ValueExpr
+	// paramConsts records the constants created for every parameter while building the plan.
+	paramConsts [][]*expression.Constant
+	paramIdx    map[*driver.ValueExpr]int
+}
+
+type planCacheKey []byte
+
+// Hash implements kvcache.Key interface.
+func (key planCacheKey) Hash() []byte {
+	return key
+}
+
+type planCacheValue struct {
+	plan        PhysicalPlan
+	names       types.NameSlice
+	visitInfo   []visitInfo
+	paramConsts [][]*expression.Constant
+	// deferredConsts are the constants folded from the functions in expression.DeferredFunctions,
+	// they are re-evaluated when the plan is reused by another statement.
+	deferredConsts []*expression.Constant
+}
+
+// NewPlanCacheStmt parameterizes the statement for the non-prepared plan cache. It returns
+// nil if the plan cache is disabled or the statement is not simple enough to be cached.
+// Only the single table SELECT statements whose WHERE clauses are conjunctions of
+// comparisons between columns and literals of the same type, or between integer columns
+// and deferred functions like UNIX_TIMESTAMP(), are cached, so that the plans do not
+// depend on the values of the literals except for the ranges of the scans.
+func NewPlanCacheStmt(sctx sessionctx.Context, is infoschema.InfoSchema, node ast.Node) *PlanCacheStmt {
+	vars := sctx.GetSessionVars()
+	if !vars.EnableNonPreparedPlanCache || vars.EnableCascadesPlanner || vars.StmtCtx.InExplainStmt {
+		return nil
+	}
+	sel, ok := node.(*ast.SelectStmt)
+	if !ok || sel.Text() == "" {
+		return nil
+	}
+	// The plans built in a dirty transaction read the buffered modifications by UnionScan.
+	txn, err := sctx.Txn(false)
+	if err != nil || (txn.Valid() && !txn.IsReadOnly()) {
+		return nil
+	}
+	tblInfo, ok := planCacheTable(sctx, is, sel)
+	if !ok {
+		return nil
+	}
+	for _, field := range sel.Fields.Fields {
+		if field.WildCard != nil {
+			continue
+		}
+		if _, ok := field.Expr.(*ast.ColumnNameExpr); !ok {
+			return nil
+		}
+	}
+	if sel.OrderBy != nil {
+		for _, item := range sel.OrderBy.Items {
+			if _, ok := item.Expr.(*ast.ColumnNameExpr); !ok {
+				return nil
+			}
+		}
+	}
+	stmt := &PlanCacheStmt{paramIdx: make(map[*driver.ValueExpr]int)}
+	if sel.Where != nil && !stmt.extractParams(tblInfo, sel.Where) {
+		return nil
+	}
+	var count, offset uint64
+	if sel.Limit != nil {
+		if count, offset, err = extractLimitCountOffset(sctx, sel.Limit); err != nil {
+			return nil
+		}
+	}
+
+	// The normalized text replaces all the literals with "?", the parameter types, the
+	// values of the LIMIT clause and the names of the fields distinguish the statements
+	// sharing the same normalized text.
+	key := codec.EncodeCompactBytes(nil, hack.Slice(parser.Normalize(sel.Text())))
+	for _, field := range sel.Fields.Fields {
+		key = codec.EncodeCompactBytes(key, hack.Slice(field.Text()))
+		key = codec.EncodeCompactBytes(key, hack.Slice(field.AsName.O))
+	}
+	for _, param := range stmt.params {
+		key = append(key, param.Kind())
+	}
+	key = codec.EncodeUint(key, count)
+	key = codec.EncodeUint(key, offset)
+	key = codec.EncodeCompactBytes(key, hack.Slice(vars.CurrentDB))
+	key = codec.EncodeInt(key, is.SchemaMetaVersion())
+	key = codec.EncodeUint(key, uint64(vars.SQLMode))
+	// The plans chosen with the old statistics are not reused once the table is analyzed.
+	if statsHandle := domain.GetDomain(sctx).StatsHandle(); statsHandle != nil {
+		key = codec.EncodeUint(key, statsHandle.GetTableStats(tblInfo).Version)
+	}
+	stmt.key = key
+	stmt.paramConsts = make([][]*expression.Constant, len(stmt.params))
+	return stmt
+}
+
+// planCacheTable returns the table read by the statement if it reads a single table.
+func planCacheTable(sctx sessionctx.Context, is infoschema.InfoSchema, sel *ast.SelectStmt) (*model.TableInfo, bool) {
+	if sel.From == nil || sel.From.TableRefs.Right != nil || sel.GroupBy != nil || sel.Having != nil ||
+		len(sel.TableHints) > 0 {
+		return nil, false
+	}
+	ts, ok := sel.From.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, false
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return nil, false
+	}
+	dbName := tn.Schema
+	if dbName.L == "" {
+		dbName = model.NewCIStr(sctx.GetSessionVars().CurrentDB)
+	}
+	tbl, err := is.TableByName(dbName, tn.Name)
+	if err != nil {
+		return nil, false
+	}
+	return tbl.Meta(), true
+}
+
+// extractParams checks the WHERE clause is a conjunction of comparisons between columns
+// and literals, and collects the literals as the parameters. The columns compared by "="
+// can't appear in other comparisons, otherwise the constant propagation makes the plan
+// depend on the values of the literals.
+func (s *PlanCacheStmt) extractParams(tblInfo *model.TableInfo, where ast.ExprNode) bool {
+	var conds []ast.ExprNode
+	var splitConds func(expr ast.ExprNode)
+	splitConds = func(expr ast.ExprNode) {
+		if binOp, ok := expr.(*ast.BinaryOperationExpr); ok && binOp.Op == opcode.LogicAnd {
+			splitConds(binOp.L)
+			splitConds(binOp.R)
+			return
+		}
+		conds = append(conds, expr)
+	}
+	splitConds(where)
+
+	colCnt := make(map[string]int)
+	eqCols := make(map[string]struct{})
+	for _, cond := range conds {
+		binOp, ok := cond.(*ast.BinaryOperationExpr)
+		if !ok {
+			return false
+		}
+		switch binOp.Op {
+		case opcode.EQ, opcode.NE, opcode.LT, opcode.LE, opcode.GT, opcode.GE:
+		default:
+			return false
+		}
+		colExpr, ok := binOp.L.(*ast.ColumnNameExpr)
+		if !ok {
+			return false
+		}
+		col := model.FindColumnInfo(tblInfo.Columns, colExpr.Name.Name.L)
+		if col == nil {
+			return false
+		}
+		switch x := binOp.R.(type) {
+		case *driver.ValueExpr:
+			if !planCacheParamMatches(col, x) {
+				return false
+			}
+			s.paramIdx[x] = len(s.params)
+			s.params = append(s.params, x)
+		case *ast.FuncCallExpr:
+			if !planCacheDeferredMatches(col, x) {
+				return false
+			}
+		default:
+			return false
+		}
+		colCnt[col.Name.L]++
+		if binOp.Op == opcode.EQ {
+			eqCols[col.Name.L] = struct{}{}
+		}
+	}
+	for col := range eqCols {
+		if colCnt[col] > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// planCacheParamMatches checks whether the literal has the same type as the column, so
+// that no cast is added to the comparison and the ranges can be rebuilt for any value.
+func planCacheParamMatches(col *model.ColumnInfo, param *driver.ValueExpr) bool {
+	switch col.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		return param.Kind() == types.KindInt64 && !mysql.HasUnsignedFlag(col.Flag)
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return param.Kind() == types.KindFloat64
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+		return param.Kind() == types.KindString
+	}
+	return false
+}
+
+// planCacheDeferredMatches checks whether the function is a deferred function without
+// arguments compared with a signed integer column. The deferred functions return integers,
+// the constants folded from them are refreshed when the plan is reused.
+func planCacheDeferredMatches(col *model.ColumnInfo, fn *ast.FuncCallExpr) bool {
+	if _, ok := expression.DeferredFunctions[fn.FnName.L]; !ok || len(fn.Args) > 0 {
+		return false
+	}
+	switch col.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		return !mysql.HasUnsignedFlag(col.Flag)
+	}
+	return false
+}
+
+// addParamConst records the constant created for the literal if it's a parameter.
+func (s *PlanCacheStmt) addParamConst(v *driver.ValueExpr, c *expression.Constant) {
+	if idx, ok := s.paramIdx[v]; ok {
+		s.paramConsts[idx] = append(s.paramConsts[idx], c)
+	}
+}
+
+func getPlanCache(sctx sessionctx.Context) *kvcache.SimpleLRUCache {
+	vars := sctx.GetSessionVars()
+	if vars.NonPreparedPlanCache == nil {
+		vars.NonPreparedPlanCache = kvcache.NewSimpleLRUCache(uint(vars.NonPreparedPlanCacheSize))
+	}
+	return vars.NonPreparedPlanCache
+}
+
+// GetPlan returns the cached plan of the statement, the parameters of the statement are
+// bound to the plan, the deferred constants are re-evaluated for the statement and the
+// ranges of the scans are rebuilt. The bool value is false if
+// the plan is not found in the cache.
+func (s *PlanCacheStmt) GetPlan(sctx sessionctx.Context) (Plan, types.NameSlice, bool, error) {
+	value, ok := getPlanCache(sctx).Get(s.key)
+	if !ok {
+		return nil, nil, false, nil
+	}
+	cached := value.(*planCacheValue)
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		if err := CheckPrivilege(pm, cached.visitInfo); err != nil {
+			return nil, nil, false, err
+		}
+	}
+	for i, consts := range cached.paramConsts {
+		for _, c := range consts {
+			c.SetValue(s.params[i].Datum, &s.params[i].Type)
+		}
+	}
+	for _, c := range cached.deferredConsts {
+		d, err := c.Eval(chunk.Row{})
+		if err != nil {
+			return nil, nil, false, err
+		}
+		c.SetValue(d, c.RetType)
+	}
+	if err := rebuildRanges(sctx, cached.plan); err != nil {
+		return nil, nil, false, err
+	}
+	sctx.GetSessionVars().FoundInPlanCache = true
+	return cached.plan, cached.names, true, nil
+}
+
+// Put puts the plan built for the statement into the plan cache if the parameters of
+// the statement only affect the constants of the plan and the ranges of its scans.
+func (s *PlanCacheStmt) Put(sctx sessionctx.Context, p Plan, names types.NameSlice, vs []visitInfo) {
+	physicalPlan, ok := p.(PhysicalPlan)
+	if !ok {
+		return
+	}
+	consts := make(map[*expression.Constant]struct{})
+	if !collectPlanCacheConsts(physicalPlan, false, consts) {
+		return
+	}
+	paramConsts := make(map[*expression.Constant]struct{}, len(consts))
+	for _, cs := range s.paramConsts {
+		found := false
+		for _, c := range cs {
+			if _, ok := consts[c]; ok {
+				found = true
+			}
+			paramConsts[c] = struct{}{}
+		}
+		// The parameter is folded or eliminated by the optimizer.
+		if !found {
+			return
+		}
+	}
+	var deferredConsts []*expression.Constant
+	for c := range consts {
+		if c.DeferredExpr != nil {
+			deferredConsts = append(deferredConsts, c)
+			continue
+		}
+		if _, ok := paramConsts[c]; !ok {
+			return
+		}
+	}
+	getPlanCache(sctx).Put(s.key, &planCacheValue{
+		plan:           physicalPlan,
+		names:          names,
+		visitInfo:      vs,
+		paramConsts:    s.paramConsts,
+		deferredConsts: deferredConsts,
+	})
+}
+
+// collectPlanCacheConsts collects the constants in the plan. It returns false if the
+// plan contains an operator which is not supported by the plan cache.
+func collectPlanCacheConsts(p PhysicalPlan, inCop bool, consts map[*expression.Constant]struct{}) bool {
+	var exprs []expression.Expression
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		return collectPlanCacheCopConsts(x.TablePlans, consts)
+	case *PhysicalIndexReader:
+		return collectPlanCacheCopConsts(x.IndexPlans, consts)
+	case *PhysicalIndexLookUpReader:
+		return collectPlanCacheCopConsts(x.IndexPlans, consts) && collectPlanCacheCopConsts(x.TablePlans, consts)
+	case *PhysicalTableScan:
+		exprs = append(exprs, x.AccessCondition...)
+		exprs = append(exprs, x.filterCondition...)
+	case *PhysicalIndexScan:
+		// The prefix index may need to filter the rows again depending on the length of the values.
+		for _, l := range x.IdxColLens {
+			if l != types.UnspecifiedLength {
+				return false
+			}
+		}
+		exprs = x.AccessCondition
+	case *PhysicalSelection:
+		// The filters evaluated in TiDB may be evaluated after the parameters are bound to another statement.
+		if !inCop {
+			return false
+		}
+		exprs = x.Conditions
+	case *PhysicalProjection:
+		exprs = x.Exprs
+	case *PhysicalLimit:
+	case *PhysicalTopN:
+		for _, item := range x.ByItems {
+			exprs = append(exprs, item.Expr)
+		}
+	case *PhysicalSort:
+		for _, item := range x.ByItems {
+			exprs = append(exprs, item.Expr)
+		}
+	default:
+		return false
+	}
+	for _, expr := range exprs {
+		if !collectExprConsts(expr, consts) {
+			return false
+		}
+	}
+	if inCop {
+		return true
+	}
+	for _, child := range p.Children() {
+		if !collectPlanCacheConsts(child, false, consts) {
+			return false
+		}
+	}
+	return true
+}
+
+func collectPlanCacheCopConsts(plans []PhysicalPlan, consts map[*expression.Constant]struct{}) bool {
+	for _, p := range plans {
+		if !collectPlanCacheConsts(p, true, consts) {
+			return false
+		}
+	}
+	return true
+}
+
+func collectExprConsts(expr expression.Expression, consts map[*expression.Constant]struct{}) bool {
+	switch x := expr.(type) {
+	case *expression.Constant:
+		consts[x] = struct{}{}
+	case *expression.ScalarFunction:
+		for _, arg := range x.GetArgs() {
+			if !collectExprConsts(arg, consts) {
+				return false
+			}
+		}
+	case *expression.Column:
+	default:
+		return false
+	}
+	return true
+}
+
+// rebuildRanges rebuilds the ranges of the scans after new parameters are bound to the plan.
+func rebuildRanges(sctx sessionctx.Context, p PhysicalPlan) error {
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		return rebuildCopRanges(sctx, x.TablePlans)
+	case *PhysicalIndexReader:
+		return rebuildCopRanges(sctx, x.IndexPlans)
+	case *PhysicalIndexLookUpReader:
+		return rebuildCopRanges(sctx, x.IndexPlans)
+	}
+	for _, child := range p.Children() {
+		if err := rebuildRanges(sctx, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rebuildCopRanges(sctx sessionctx.Context, plans []PhysicalPlan) error {
+	var err error
+	for _, p := range plans {
+		switch x := p.(type) {
+		case *PhysicalTableScan:
+			if len(x.AccessCondition) == 0 {
+				continue
+			}
+			pkCol := expression.ExtractColumns(x.AccessCondition[0])[0]
+			x.Ranges, err = ranger.BuildTableRange(x.AccessCondition, sctx.GetSessionVars().StmtCtx, pkCol.RetType)
+			if err != nil {
+				return err
+			}
+		case *PhysicalIndexScan:
+			if len(x.AccessCondition) == 0 {
+				continue
+			}
+			res, err := ranger.DetachCondAndBuildRangeForIndex(sctx, x.AccessCondition, x.IdxCols, x.IdxColLens)
+			if err != nil {
+				return err
+			}
+			x.Ranges = res.Ranges
+		}
+	}
+	return nil
+}
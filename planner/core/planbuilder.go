@@ -29,9 +29,11 @@ import (
 	"github.com/pingcap/tidb/parser/opcode"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/tracing"
 )
 
 type tableHintInfo struct {
@@ -152,11 +154,20 @@ var clauseMsg = map[clauseCode]string{
 	globalOrderByClause: "global ORDER clause",
 }
 
+type visitInfo struct {
+	privilege mysql.PrivilegeType
+	db        string
+	table     string
+	column    string
+	err       error
+}
+
 // PlanBuilder builds Plan from an ast.Node.
 // It just builds the ast node straightforwardly.
 type PlanBuilder struct {
-	ctx sessionctx.Context
-	is  infoschema.InfoSchema
+	ctx       sessionctx.Context
+	is        infoschema.InfoSchema
+	visitInfo []visitInfo
 	// colMapper stores the column that must be pre-resolved.
 	colMapper map[*ast.ColumnNameExpr]int
 
@@ -184,6 +195,23 @@ type PlanBuilder struct {
 	//   If it's a join, we pop its children's out then merge them and push the new map to stack.
 	//   If we meet a subquery, it's clearly that it's a independent problem so we just pop one map out when we finish building the subquery.
 	handleHelper *handleColHelper
+
+	// rollupGroupingCols maps the nullable copies of the group-by columns of ROLLUP to
+	// their bits in the grouping ID rollupGID. They are used to rewrite GROUPING().
+	rollupGroupingCols map[int64]int
+	rollupGID          *expression.Column
+
+	// outerSchemas and outerNames are the schemas and names of the outer query blocks, the innermost is the last one.
+	// They are used to resolve the correlated columns in subqueries.
+	outerSchemas []*expression.Schema
+	outerNames   [][]*types.FieldName
+	// correlatedAggMapper stores the aggregate functions in subqueries which are evaluated in the outer query,
+	// and maps them to the correlated columns that refer to their results.
+	correlatedAggMapper map[*ast.AggregateFuncExpr]*expression.CorrelatedColumn
+
+	// planCacheStmt records the constants created for the parameters of the statement
+	// if its plan is going to be put into the non-prepared plan cache.
+	planCacheStmt *PlanCacheStmt
 }
 
 type handleColHelper struct {
@@ -224,6 +252,16 @@ func (hch *handleColHelper) tailMap() map[int64][]*expression.Column {
 	return hch.id2HandleMapStack[hch.stackTail-1]
 }
 
+// GetVisitInfo gets the visitInfo of the PlanBuilder.
+func (b *PlanBuilder) GetVisitInfo() []visitInfo {
+	return b.visitInfo
+}
+
+// SetPlanCacheStmt sets the statement whose plan is going to be put into the plan cache.
+func (b *PlanBuilder) SetPlanCacheStmt(stmt *PlanCacheStmt) {
+	b.planCacheStmt = stmt
+}
+
 // GetOptFlag gets the optFlag of the PlanBuilder.
 func (b *PlanBuilder) GetOptFlag() uint64 {
 	return b.optFlag
@@ -259,7 +297,7 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		return b.buildSet(ctx, x)
 	case *ast.AnalyzeTableStmt:
 		return b.buildAnalyze(x)
-	case *ast.UseStmt, *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt:
+	case *ast.UseStmt, *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.BRIEStmt, *ast.ImportIntoStmt:
 		return b.buildSimple(node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -281,11 +319,17 @@ func (b *PlanBuilder) buildSet(ctx context.Context, v *ast.SetStmt) (Plan, error
 				vars.Value = ast.NewValueExpr(cn.Name.Name.O)
 			}
 			mockTablePlan := LogicalTableDual{}.Init(b.ctx)
-			var err error
-			assign.Expr, _, err = b.rewrite(ctx, vars.Value, mockTablePlan, nil, true)
+			var (
+				np  LogicalPlan
+				err error
+			)
+			assign.Expr, np, err = b.rewrite(ctx, vars.Value, mockTablePlan, nil, true)
 			if err != nil {
 				return nil, err
 			}
+			if np != mockTablePlan {
+				return nil, errors.New("SET doesn't support subqueries yet")
+			}
 		} else {
 			assign.IsDefault = true
 		}
@@ -300,7 +344,7 @@ func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 		return true
 	}
 	for _, f := range sel.Fields.Fields {
-		if ast.HasAggFlag(f.Expr) {
+		if ast.HasAggFlag(f.Expr) && b.hasOwnAggFunc(f.Expr) {
 			return true
 		}
 	}
@@ -319,6 +363,16 @@ func (b *PlanBuilder) detectSelectAgg(sel *ast.SelectStmt) bool {
 	return false
 }
 
+// hasOwnAggFunc checks whether the expression has aggregate functions which are not evaluated in the outer query.
+func (b *PlanBuilder) hasOwnAggFunc(expr ast.ExprNode) bool {
+	if len(b.correlatedAggMapper) == 0 {
+		return true
+	}
+	extractor := &AggregateFuncExtractor{skipAggMap: b.correlatedAggMapper}
+	expr.Accept(extractor)
+	return len(extractor.AggFuncs) > 0
+}
+
 func getPathByIndexName(paths []*util.AccessPath, idxName model.CIStr, tblInfo *model.TableInfo) *util.AccessPath {
 	var tablePath *util.AccessPath
 	for _, path := range paths {
@@ -345,10 +399,14 @@ func (b *PlanBuilder) getPossibleAccessPaths(indexHints []*ast.IndexHint, tbl ta
 	publicPaths := make([]*util.AccessPath, 0, len(tblInfo.Indices)+2)
 	publicPaths = append(publicPaths, &util.AccessPath{IsTablePath: true})
 	for _, index := range tblInfo.Indices {
-		if index.State == model.StatePublic {
+		// The invisible indexes are ignored by the optimizer, as if they don't exist.
+		if index.State == model.StatePublic && !index.Invisible {
 			publicPaths = append(publicPaths, &util.AccessPath{Index: index})
 		}
 	}
+	for _, index := range b.ctx.GetSessionVars().StmtCtx.HypoIndexes[tblInfo.ID] {
+		publicPaths = append(publicPaths, &util.AccessPath{Index: index, IsHypothetical: true})
+	}
 
 	hasScanHint, hasUseOrForce := false, false
 	available := make([]*util.AccessPath, 0, len(publicPaths))
@@ -363,6 +421,9 @@ func (b *PlanBuilder) getPossibleAccessPaths(indexHints []*ast.IndexHint, tbl ta
 			}
 		}
 	}
+	if hint := b.planBaselineHint(publicPaths, tblInfo, dbName, tblName); hint != nil {
+		indexHints = append(indexHints, hint)
+	}
 
 	for i, hint := range indexHints {
 		if hint.HintScope != ast.HintForScan {
@@ -453,9 +514,58 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 				return nil, err
 			}
 		}
+	case ast.AdminReloadStats, ast.AdminReloadPrivileges, ast.AdminFlushPlanCache, ast.AdminFlushResultCache:
+		ret = &Simple{Statement: as}
+	case ast.AdminExport:
+		p := &Simple{Statement: as}
+		p.setSchemaAndNames(buildExportFields())
+		ret = p
+	case ast.AdminRecommendIndex:
+		p := &Simple{Statement: as}
+		p.setSchemaAndNames(buildRecommendIndexFields())
+		ret = p
+	case ast.AdminShowBaselines:
+		p := &Simple{Statement: as}
+		p.setSchemaAndNames(buildShowBaselinesFields())
+		ret = p
+	case ast.AdminEvolveBaselines, ast.AdminEnableRowChecksum, ast.AdminDisableRowChecksum, ast.AdminRebuildRowChecksum:
+		ret = &Simple{Statement: as}
+	case ast.AdminShowDDLProgress:
+		p := &Simple{Statement: as}
+		p.setSchemaAndNames(buildShowDDLProgressFields())
+		ret = p
+	case ast.AdminShowAnalyzeProgress:
+		p := &Simple{Statement: as}
+		p.setSchemaAndNames(buildShowAnalyzeProgressFields())
+		ret = p
+	case ast.AdminScatterTable, ast.AdminShowTableRegions, ast.AdminShowTableDistributions:
+		tblInfo := as.Tables[0].TableInfo
+		if as.Index != "" && tblInfo.FindIndexByName(strings.ToLower(as.Index)) == nil {
+			return nil, ErrKeyDoesNotExist.GenWithStackByArgs(as.Index, tblInfo.Name.O)
+		}
+		p := &Simple{Statement: as}
+		switch as.Tp {
+		case ast.AdminScatterTable:
+			p.setSchemaAndNames(buildScatterTableFields())
+		case ast.AdminShowTableRegions:
+			p.setSchemaAndNames(buildShowTableRegionsFields())
+		default:
+			p.setSchemaAndNames(buildShowTableDistributionsFields())
+		}
+		ret = p
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.AdminStmt(%T) for buildAdmin", as)
 	}
+
+	// Reloading the caches affects the whole server, exporting reads all the data, the index
+	// recommendations and the plan baselines are based on the statements of all the users, and
+	// the regions are shared by all the tables of the cluster. The progress of the DDL and the
+	// analyze jobs reveals the tables of all the users. The row checksums are toggled globally.
+	// Only the plan cache belongs to the session, so any user can flush it.
+	if _, ok := ret.(*Simple); ok && as.Tp != ast.AdminFlushPlanCache {
+		err = ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+	}
 	return ret, nil
 }
 
@@ -479,7 +589,15 @@ func getColsInfo(tn *ast.TableName) (indicesInfo []*model.IndexInfo, colsInfo []
 
 func (b *PlanBuilder) buildAnalyze(as *ast.AnalyzeTableStmt) (Plan, error) {
 	p := &Analyze{}
+	user := b.ctx.GetSessionVars().User
 	for _, tbl := range as.TableNames {
+		var insertErr, selectErr error
+		if user != nil {
+			insertErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.Username, user.Hostname, tbl.Name.O)
+			selectErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.Username, user.Hostname, tbl.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tbl.Schema.L, tbl.Name.L, "", insertErr)
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, tbl.Schema.L, tbl.Name.L, "", selectErr)
 		idxInfo, colInfo, pkInfo := getColsInfo(tbl)
 		for _, idx := range idxInfo {
 			info := analyzeInfo{DBName: tbl.Schema.O, TableName: tbl.Name.O, PhysicalTableID: tbl.TableInfo.ID}
@@ -514,6 +632,65 @@ func buildShowDDLFields() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildExportFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(4)
+	schema.Append(buildColumnWithName("", "Destination", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "Tables", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "Rows", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "SnapshotTS", mysql.TypeLonglong, 4))
+	return schema.col2Schema(), schema.names
+}
+
+func buildRecommendIndexFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(7)
+	schema.Append(buildColumnWithName("", "Database", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Table", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Index_name", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Index_columns", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "Est_benefit", mysql.TypeDouble, 22))
+	schema.Append(buildColumnWithName("", "Digest_count", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "Create_statement", mysql.TypeVarchar, 512))
+	return schema.col2Schema(), schema.names
+}
+
+func buildShowBaselinesFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(6)
+	schema.Append(buildColumnWithName("", "Database", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Statement", mysql.TypeVarchar, 4096))
+	schema.Append(buildColumnWithName("", "Digest", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "Access_paths", mysql.TypeVarchar, 1024))
+	schema.Append(buildColumnWithName("", "Source", mysql.TypeVarchar, 16))
+	schema.Append(buildColumnWithName("", "Update_time", mysql.TypeVarchar, 64))
+	return schema.col2Schema(), schema.names
+}
+
+func buildScatterTableFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(2)
+	schema.Append(buildColumnWithName("", "TOTAL_SCATTER_REGION", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "SCATTER_FINISH_RATIO", mysql.TypeDouble, 8))
+	return schema.col2Schema(), schema.names
+}
+
+func buildShowTableRegionsFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(7)
+	schema.Append(buildColumnWithName("", "REGION_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "START_KEY", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "END_KEY", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "LEADER_STORE_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "PEER_STORE_IDS", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "APPROXIMATE_ROWS", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "APPROXIMATE_SIZE", mysql.TypeLonglong, 4))
+	return schema.col2Schema(), schema.names
+}
+
+func buildShowTableDistributionsFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(3)
+	schema.Append(buildColumnWithName("", "STORE_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "REGION_COUNT", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "LEADER_COUNT", mysql.TypeLonglong, 4))
+	return schema.col2Schema(), schema.names
+}
+
 func buildShowDDLJobsFields() (*expression.Schema, types.NameSlice) {
 	schema := newColumnsWithNames(11)
 	schema.Append(buildColumnWithName("", "JOB_ID", mysql.TypeLonglong, 4))
@@ -530,6 +707,34 @@ func buildShowDDLJobsFields() (*expression.Schema, types.NameSlice) {
 	return schema.col2Schema(), schema.names
 }
 
+func buildShowDDLProgressFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(10)
+	schema.Append(buildColumnWithName("", "JOB_ID", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "DB_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "TABLE_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "JOB_TYPE", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "SCHEMA_STATE", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "START_TIME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "PROCESSED_ROWS", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "TOTAL_ROWS", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "PROGRESS", mysql.TypeDouble, 8))
+	schema.Append(buildColumnWithName("", "ETA", mysql.TypeVarchar, 64))
+	return schema.col2Schema(), schema.names
+}
+
+func buildShowAnalyzeProgressFields() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(8)
+	schema.Append(buildColumnWithName("", "DB_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "TABLE_NAME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "JOB_INFO", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "START_TIME", mysql.TypeVarchar, 64))
+	schema.Append(buildColumnWithName("", "PROCESSED_ROWS", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "TOTAL_ROWS", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "PROGRESS", mysql.TypeDouble, 8))
+	schema.Append(buildColumnWithName("", "ETA", mysql.TypeVarchar, 64))
+	return schema.col2Schema(), schema.names
+}
+
 func buildColumnWithName(tableName, name string, tp byte, size int) (*expression.Column, *types.FieldName) {
 	cs, cl := types.DefaultCharsetForType(tp)
 	flag := mysql.UnsignedFlag
@@ -611,6 +816,14 @@ func (b *PlanBuilder) buildShow(ctx context.Context, show *ast.ShowStmt) (Plan,
 		if p.DBName == "" {
 			return nil, ErrNoDB
 		}
+	case ast.ShowRegions:
+		tblInfo := show.Table.TableInfo
+		if show.IndexName.L != "" && tblInfo.FindIndexByName(show.IndexName.L) == nil {
+			return nil, ErrKeyDoesNotExist.GenWithStackByArgs(show.IndexName.O, tblInfo.Name.O)
+		}
+		// Like admin show table regions, the regions are shared by all the tables of the cluster.
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
 	}
 	schema, names := buildShowSchema(show)
 	p.SetSchema(schema)
@@ -654,10 +867,28 @@ func (b *PlanBuilder) buildSimple(node ast.StmtNode) (Plan, error) {
 		if raw.DBName == "" {
 			return nil, ErrNoDB
 		}
+	case *ast.BRIEStmt:
+		p.setSchemaAndNames(buildBRIESchema())
+		err := ErrSpecificAccessDenied.GenWithStackByArgs("SUPER")
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SuperPriv, "", "", "", err)
+	case *ast.ImportIntoStmt:
+		var authErr error
+		if user := b.ctx.GetSessionVars().User; user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.Username, user.Hostname, raw.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, raw.Table.Schema.L, raw.Table.Name.L, "", authErr)
 	}
 	return p, nil
 }
 
+func buildBRIESchema() (*expression.Schema, types.NameSlice) {
+	schema := newColumnsWithNames(3)
+	schema.Append(buildColumnWithName("", "Destination", mysql.TypeVarchar, 256))
+	schema.Append(buildColumnWithName("", "Size", mysql.TypeLonglong, 4))
+	schema.Append(buildColumnWithName("", "BackupTS", mysql.TypeLonglong, 4))
+	return schema.col2Schema(), schema.names
+}
+
 func (b *PlanBuilder) getDefaultValue(col *table.Column) (*expression.Constant, error) {
 	value, err := table.GetColDefaultValue(b.ctx, col.ToInfo())
 	if err != nil {
@@ -692,6 +923,12 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		return nil, errors.Errorf("Can't get table %s.", tableInfo.Name.O)
 	}
 
+	var authErr error
+	if user := b.ctx.GetSessionVars().User; user != nil {
+		authErr = ErrTableaccessDenied.GenWithStackByArgs("INSERT", user.Username, user.Hostname, tableInfo.Name.O)
+	}
+	b.visitInfo = appendVisitInfo(b.visitInfo, mysql.InsertPriv, tn.Schema.L, tableInfo.Name.L, "", authErr)
+
 	insertPlan := Insert{
 		Table:         tableInPlan,
 		Columns:       insert.Columns,
@@ -735,6 +972,17 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		}
 	}
 
+	for _, col := range tableInPlan.Cols() {
+		if !col.IsGenerated() {
+			continue
+		}
+		expr, _, err := b.rewrite(ctx, col.GeneratedExpr, mockTablePlan, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		insertPlan.GenExprs = append(insertPlan.GenExprs, expr)
+	}
+
 	err := insertPlan.ResolveIndices()
 	return insertPlan, err
 }
@@ -748,7 +996,7 @@ func (b *PlanBuilder) getAffectCols(insertStmt *ast.InsertStmt, insertPlan *Inse
 		for _, col := range insertStmt.Columns {
 			colName = append(colName, col.Name.O)
 		}
-		affectedValuesCols, err = table.FindCols(insertPlan.Table.Cols(), colName, insertPlan.Table.Meta().PKIsHandle)
+		affectedValuesCols, err = table.FindCols(insertPlan.Table.VisibleCols(), colName, insertPlan.Table.Meta().PKIsHandle)
 		if err != nil {
 			return nil, err
 		}
@@ -757,7 +1005,7 @@ func (b *PlanBuilder) getAffectCols(insertStmt *ast.InsertStmt, insertPlan *Inse
 		// This branch is for the following scenarios:
 		// 1. `INSERT INTO tbl_name {VALUES | VALUE} (value_list) [, (value_list)] ...`,
 		// 2. `INSERT INTO tbl_name SELECT ...`.
-		affectedValuesCols = insertPlan.Table.Cols()
+		affectedValuesCols = insertPlan.Table.VisibleCols()
 	}
 	return affectedValuesCols, nil
 }
@@ -783,10 +1031,13 @@ func (b *PlanBuilder) buildSetValuesOfInsert(ctx context.Context, insert *ast.In
 		if defaultExpr != nil {
 			defaultExpr.Name = assign.Column
 		}
-		expr, _, err := b.rewriteWithPreprocess(ctx, assign.Expr, mockTablePlan, nil, true, checkRefColumn)
+		expr, np, err := b.rewriteWithPreprocess(ctx, assign.Expr, mockTablePlan, nil, true, checkRefColumn)
 		if err != nil {
 			return err
 		}
+		if np != mockTablePlan {
+			return errors.New("INSERT doesn't support subqueries in assignments yet")
+		}
 		if insertPlan.AllAssignmentsAreConstant {
 			_, isConstant := expr.(*expression.Constant)
 			insertPlan.AllAssignmentsAreConstant = isConstant
@@ -845,7 +1096,11 @@ func (b *PlanBuilder) buildValuesListOfInsert(ctx context.Context, insert *ast.I
 					RetType: &x.Type,
 				}
 			default:
-				expr, _, err = b.rewriteWithPreprocess(ctx, valueItem, mockTablePlan, nil, true, checkRefColumn)
+				var np LogicalPlan
+				expr, np, err = b.rewriteWithPreprocess(ctx, valueItem, mockTablePlan, nil, true, checkRefColumn)
+				if err == nil && np != mockTablePlan {
+					err = errors.New("INSERT doesn't support subqueries in values yet")
+				}
 			}
 			if err != nil {
 				return err
@@ -907,6 +1162,58 @@ func (b *PlanBuilder) buildSelectPlanOfInsert(ctx context.Context, insert *ast.I
 }
 
 func (b *PlanBuilder) buildDDL(ctx context.Context, node ast.DDLNode) (Plan, error) {
+	var authErr error
+	user := b.ctx.GetSessionVars().User
+	switch v := node.(type) {
+	case *ast.AlterTableStmt:
+		if user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("ALTER", user.Username, user.Hostname, v.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.AlterPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	case *ast.CreateDatabaseStmt:
+		if user != nil {
+			authErr = ErrDBaccessDenied.GenWithStackByArgs(user.Username, user.Hostname, v.Name)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, strings.ToLower(v.Name), "", "", authErr)
+	case *ast.CreateIndexStmt:
+		if user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("INDEX", user.Username, user.Hostname, v.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.IndexPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	case *ast.CreateTableStmt:
+		if user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("CREATE", user.Username, user.Hostname, v.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.CreatePriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+		if v.ReferTable != nil {
+			if user != nil {
+				authErr = ErrTableaccessDenied.GenWithStackByArgs("SELECT", user.Username, user.Hostname, v.ReferTable.Name.O)
+			}
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.SelectPriv, v.ReferTable.Schema.L, v.ReferTable.Name.L, "", authErr)
+		}
+	case *ast.DropDatabaseStmt:
+		if user != nil {
+			authErr = ErrDBaccessDenied.GenWithStackByArgs(user.Username, user.Hostname, v.Name)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, strings.ToLower(v.Name), "", "", authErr)
+	case *ast.DropIndexStmt:
+		if user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("INDEX", user.Username, user.Hostname, v.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.IndexPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	case *ast.DropTableStmt:
+		for _, tableVal := range v.Tables {
+			if user != nil {
+				authErr = ErrTableaccessDenied.GenWithStackByArgs("DROP", user.Username, user.Hostname, tableVal.Name.O)
+			}
+			b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, tableVal.Schema.L, tableVal.Name.L, "", authErr)
+		}
+	case *ast.TruncateTableStmt:
+		if user != nil {
+			authErr = ErrTableaccessDenied.GenWithStackByArgs("DROP", user.Username, user.Hostname, v.Table.Name.O)
+		}
+		b.visitInfo = appendVisitInfo(b.visitInfo, mysql.DropPriv, v.Table.Schema.L, v.Table.Name.L, "", authErr)
+	}
 	p := &DDL{Statement: node}
 	return p, nil
 }
@@ -925,14 +1232,75 @@ func (b *PlanBuilder) buildExplain(ctx context.Context, explain *ast.ExplainStmt
 	if show, ok := explain.Stmt.(*ast.ShowStmt); ok {
 		return b.buildShow(ctx, show)
 	}
+	if len(explain.HypoIndexes) > 0 {
+		hypoIndexes, err := buildHypoIndexes(explain.HypoIndexes)
+		if err != nil {
+			return nil, err
+		}
+		// The explained plan is never executed, so it can use the hypothetical indexes.
+		b.ctx.GetSessionVars().StmtCtx.HypoIndexes = hypoIndexes
+	}
+	sessVars := b.ctx.GetSessionVars()
+	if explain.OptimizerTrace {
+		sessVars.StmtCtx.OptimTracer = &tracing.OptimizeTracer{}
+	}
 	targetPlan, _, err := OptimizeAstNode(ctx, b.ctx, explain.Stmt, b.is)
 	if err != nil {
 		return nil, err
 	}
+	if explain.OptimizerTrace {
+		trace, err := sessVars.StmtCtx.OptimTracer.JSON()
+		if err != nil {
+			return nil, err
+		}
+		sessVars.LastOptimizerTrace = &variable.OptimizerTraceInfo{
+			Query: explain.Text(),
+			Trace: trace,
+		}
+	}
 
 	return b.buildExplainPlan(targetPlan, explain.Format, explain.Stmt)
 }
 
+// buildHypoIndexes builds the infos of the hypothetical indexes, keyed by the table IDs.
+func buildHypoIndexes(defs []*ast.HypoIndexDef) (map[int64][]*model.IndexInfo, error) {
+	hypoIndexes := make(map[int64][]*model.IndexInfo, len(defs))
+	for _, def := range defs {
+		tblInfo := def.Table.TableInfo
+		idxName := model.NewCIStr(def.Name)
+		if tblInfo.FindIndexByName(idxName.L) != nil {
+			return nil, ErrDupKeyName.GenWithStackByArgs(def.Name)
+		}
+		for _, idx := range hypoIndexes[tblInfo.ID] {
+			if idx.Name.L == idxName.L {
+				return nil, ErrDupKeyName.GenWithStackByArgs(def.Name)
+			}
+		}
+		cols := make([]*model.ColumnInfo, 0, len(def.IndexPartSpecifications))
+		for _, part := range def.IndexPartSpecifications {
+			if part.Expr != nil {
+				return nil, ErrNotSupportedYet.GenWithStackByArgs("hypothetical index on expressions")
+			}
+			col := model.FindColumnInfo(tblInfo.Columns, part.Column.Name.L)
+			if col == nil {
+				return nil, ErrKeyColumnDoesNotExist.GenWithStackByArgs(part.Column.Name.O)
+			}
+			if part.Length != types.UnspecifiedLength && !types.IsTypePrefixable(col.Tp) {
+				return nil, ErrWrongSubKey
+			}
+			cols = append(cols, col)
+		}
+		idx := NewHypoIndexInfo(tblInfo, idxName, cols)
+		// The hypothetical indexes of a table need different IDs.
+		idx.ID += int64(len(hypoIndexes[tblInfo.ID]))
+		for i, part := range def.IndexPartSpecifications {
+			idx.Columns[i].Length = part.Length
+		}
+		hypoIndexes[tblInfo.ID] = append(hypoIndexes[tblInfo.ID], idx)
+	}
+	return hypoIndexes, nil
+}
+
 func buildShowWarningsSchema() (*expression.Schema, types.NameSlice) {
 	tblName := "WARNINGS"
 	schema := newColumnsWithNames(3)
@@ -949,6 +1317,8 @@ func buildShowSchema(s *ast.ShowStmt) (schema *expression.Schema, outputNames []
 	switch s.Tp {
 	case ast.ShowWarnings, ast.ShowErrors:
 		return buildShowWarningsSchema()
+	case ast.ShowRegions:
+		return buildShowTableRegionsFields()
 	case ast.ShowDatabases:
 		names = []string{"Database"}
 	case ast.ShowTables:
@@ -982,3 +1352,13 @@ func buildShowSchema(s *ast.ShowStmt) (schema *expression.Schema, outputNames []
 	}
 	return
 }
+
+func appendVisitInfo(vi []visitInfo, priv mysql.PrivilegeType, db, tbl, col string, err error) []visitInfo {
+	return append(vi, visitInfo{
+		privilege: priv,
+		db:        db,
+		table:     tbl,
+		column:    col,
+		err:       err,
+	})
+}
@@ -38,6 +38,23 @@ func (p *PhysicalProjection) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalExpand) ResolveIndices() (err error) {
+	err = p.physicalSchemaProducer.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for _, exprs := range p.LevelExprs {
+		for i, expr := range exprs {
+			exprs[i], err = expr.ResolveIndices(p.children[0].Schema())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return
+}
+
 // refine4NeighbourProj refines the index for p.Exprs whose type is *Column when
 // there is two neighbouring Projections.
 // This function is introduced because that different childProj.Expr may refer
@@ -113,6 +130,22 @@ func (p *PhysicalHashJoin) ResolveIndices() (err error) {
 	return
 }
 
+// ResolveIndices implements Plan interface.
+func (p *PhysicalApply) ResolveIndices() (err error) {
+	err = p.PhysicalHashJoin.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for i, col := range p.OuterSchema {
+		newCol, err := col.Column.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		p.OuterSchema[i].Column = *newCol.(*expression.Column)
+	}
+	return
+}
+
 // ResolveIndices implements Plan interface.
 func (p *PhysicalMergeJoin) ResolveIndices() (err error) {
 	err = p.physicalSchemaProducer.ResolveIndices()
@@ -178,7 +211,33 @@ func (p *PhysicalUnionScan) ResolveIndices() (err error) {
 
 // ResolveIndices implements Plan interface.
 func (p *PhysicalTableReader) ResolveIndices() error {
-	return p.tablePlan.ResolveIndices()
+	err := p.tablePlan.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	for i, col := range p.OutputColumns {
+		newCol, err := col.ResolveIndices(p.tablePlan.Schema())
+		if err != nil {
+			return err
+		}
+		p.OutputColumns[i] = newCol.(*expression.Column)
+	}
+	return resolveIndicesForVirtualColumn(p.schema.Columns, p.schema)
+}
+
+// resolveIndicesForVirtualColumn resolves the expressions of the virtual columns, they are
+// evaluated on the rows read by the readers.
+func resolveIndicesForVirtualColumn(result []*expression.Column, schema *expression.Schema) error {
+	for _, col := range result {
+		if col.VirtualExpr != nil {
+			newExpr, err := col.VirtualExpr.ResolveIndices(schema)
+			if err != nil {
+				return err
+			}
+			col.VirtualExpr = newExpr
+		}
+	}
+	return nil
 }
 
 // ResolveIndices implements Plan interface.
@@ -218,6 +277,24 @@ func (p *PhysicalIndexLookUpReader) ResolveIndices() (err error) {
 		}
 		p.ExtraHandleCol = newCol.(*expression.Column)
 	}
+	return resolveIndicesForVirtualColumn(p.tablePlan.Schema().Columns, p.tablePlan.Schema())
+}
+
+// ResolveIndices implements Plan interface.
+func (p *PhysicalTableLookUpReader) ResolveIndices() (err error) {
+	err = p.tablePlan.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	err = p.filterPlan.ResolveIndices()
+	if err != nil {
+		return err
+	}
+	newCol, err := p.HandleCol.ResolveIndices(p.filterPlan.Schema())
+	if err != nil {
+		return err
+	}
+	p.HandleCol = newCol.(*expression.Column)
 	return
 }
 
@@ -306,6 +383,12 @@ func (p *Insert) ResolveIndices() (err error) {
 			return err
 		}
 	}
+	for i, expr := range p.GenExprs {
+		p.GenExprs[i], err = expr.ResolveIndices(p.tableSchema)
+		if err != nil {
+			return err
+		}
+	}
 	return
 }
 
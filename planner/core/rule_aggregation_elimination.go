@@ -30,11 +30,27 @@ type aggregationEliminator struct {
 type aggregationEliminateChecker struct {
 }
 
+// rewritableAggFuncs stores the aggregate functions which can be rewritten to
+// expressions by rewriteExpr when every group contains only one row.
+var rewritableAggFuncs = map[string]struct{}{
+	ast.AggFuncCount:    {},
+	ast.AggFuncSum:      {},
+	ast.AggFuncAvg:      {},
+	ast.AggFuncFirstRow: {},
+	ast.AggFuncMax:      {},
+	ast.AggFuncMin:      {},
+}
+
 // tryToEliminateAggregation will eliminate aggregation grouped by unique key.
 // e.g. select min(b) from t group by a. If a is a unique key, then this sql is equal to `select b from t group by a`.
 // For count(expr), sum(expr), avg(expr), count(distinct expr, [expr...]) we may need to rewrite the expr. Details are shown below.
 // If we can eliminate agg successful, we return a projection. Else we return a nil pointer.
 func (a *aggregationEliminateChecker) tryToEliminateAggregation(agg *LogicalAggregation) *LogicalProjection {
+	for _, fun := range agg.AggFuncs {
+		if _, ok := rewritableAggFuncs[fun.Name]; !ok {
+			return nil
+		}
+	}
 	schemaByGroupby := expression.NewSchema(agg.groupByCols...)
 	coveredByUniqueKey := false
 	for _, key := range agg.children[0].Schema().Keys {
@@ -43,6 +59,11 @@ func (a *aggregationEliminateChecker) tryToEliminateAggregation(agg *LogicalAggr
 			break
 		}
 	}
+	// Every group contains only one row if the child returns no more than one row. But the aggregation
+	// without group by items returns a row even if the child returns none, so it can't be removed.
+	if !coveredByUniqueKey && len(agg.GroupByItems) > 0 && agg.children[0].MaxOneRow() {
+		coveredByUniqueKey = true
+	}
 	if coveredByUniqueKey {
 		// GroupByCols has unique key, so this aggregation can be removed.
 		proj := a.convertAggToProj(agg)
@@ -35,8 +35,10 @@ func (a *aggregationPushDownSolver) isDecomposable(fun *aggregation.AggFuncDesc)
 	case ast.AggFuncAvg:
 		// TODO: Support avg push down.
 		return false
-	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow, ast.AggFuncSum, ast.AggFuncCount:
+	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
 		return true
+	case ast.AggFuncSum, ast.AggFuncCount:
+		return !fun.HasDistinct
 	default:
 		return false
 	}
@@ -253,7 +255,7 @@ func (a *aggregationPushDownSolver) makeNewAgg(ctx sessionctx.Context, aggFuncs
 		newAggFuncDescs = append(newAggFuncDescs, newFuncs...)
 	}
 	for _, gbyCol := range gbyCols {
-		firstRow, err := aggregation.NewAggFuncDesc(agg.ctx, ast.AggFuncFirstRow, []expression.Expression{gbyCol})
+		firstRow, err := aggregation.NewAggFuncDesc(agg.ctx, ast.AggFuncFirstRow, []expression.Expression{gbyCol}, false)
 		if err != nil {
 			return nil, err
 		}
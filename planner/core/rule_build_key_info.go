@@ -15,7 +15,9 @@ package core
 
 import (
 	"context"
+
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 )
@@ -68,9 +70,78 @@ func (la *LogicalAggregation) BuildKeyInfo(selfSchema *expression.Schema, childS
 	}
 }
 
+// hasMaxOneRow returns whether the plan returns no more than one row, given whether its children do.
+// The children are unknown in the cascades planner, then only the plans returning no more than one
+// row by themselves are considered.
+func hasMaxOneRow(p LogicalPlan, childMaxOneRow []bool) bool {
+	switch x := p.(type) {
+	case *LogicalMaxOneRow:
+		return true
+	case *LogicalTableDual:
+		return x.RowCount <= 1
+	case *LogicalLimit:
+		if x.Count <= 1 {
+			return true
+		}
+	case *LogicalTopN:
+		if x.Count <= 1 {
+			return true
+		}
+	case *LogicalAggregation:
+		// The aggregation without group by items returns exactly one row.
+		if len(x.GroupByItems) == 0 {
+			return true
+		}
+	}
+	if len(childMaxOneRow) == 0 {
+		return false
+	}
+	switch p.(type) {
+	case *LogicalLimit, *LogicalTopN, *LogicalAggregation, *LogicalSelection, *LogicalProjection, *LogicalSort:
+		return childMaxOneRow[0]
+	case *LogicalJoin, *LogicalApply:
+		// Each row of one side is joined with no more than one row of the other side.
+		return childMaxOneRow[0] && childMaxOneRow[1]
+	}
+	return false
+}
+
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
 func (p *LogicalSelection) BuildKeyInfo(selfSchema *expression.Schema, childSchema []*expression.Schema) {
 	p.baseLogicalPlan.BuildKeyInfo(selfSchema, childSchema)
+	if !p.maxOneRow {
+		p.maxOneRow = isKeyFixedByConds(p.Conditions, childSchema[0])
+	}
+}
+
+// isKeyFixedByConds checks whether all the columns of a unique key are equal to constants or correlated columns
+// in the conditions, e.g. `a = 1 and b = 2` for the key (a, b). No more than one row satisfies the conditions then.
+func isKeyFixedByConds(conds []expression.Expression, schema *expression.Schema) bool {
+	fixedCols := make([]*expression.Column, 0, len(conds))
+	for _, cond := range conds {
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.EQ {
+			continue
+		}
+		args := sf.GetArgs()
+		for i, arg := range args {
+			col, ok := arg.(*expression.Column)
+			if !ok {
+				continue
+			}
+			switch args[1-i].(type) {
+			case *expression.Constant, *expression.CorrelatedColumn:
+				fixedCols = append(fixedCols, col)
+			}
+		}
+	}
+	fixedSchema := expression.NewSchema(fixedCols...)
+	for _, key := range schema.Keys {
+		if fixedSchema.ColumnsIndices(key) != nil {
+			return true
+		}
+	}
+	return false
 }
 
 // BuildKeyInfo implements LogicalPlan BuildKeyInfo interface.
@@ -85,6 +85,27 @@ func (p *LogicalProjection) PruneColumns(parentUsedCols []*expression.Column) er
 	return child.PruneColumns(selfUsedCols)
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalExpand) PruneColumns(parentUsedCols []*expression.Column) error {
+	child := p.children[0]
+	used := getUsedList(parentUsedCols, p.schema)
+
+	for i := len(used) - 1; i >= 0; i-- {
+		if used[i] {
+			continue
+		}
+		p.schema.Columns = append(p.schema.Columns[:i], p.schema.Columns[i+1:]...)
+		for j, exprs := range p.LevelExprs {
+			p.LevelExprs[j] = append(exprs[:i], exprs[i+1:]...)
+		}
+	}
+	selfUsedCols := make([]*expression.Column, 0, p.schema.Len())
+	for _, exprs := range p.LevelExprs {
+		selfUsedCols = expression.ExtractColumnsFromExpressions(selfUsedCols, exprs, nil)
+	}
+	return child.PruneColumns(selfUsedCols)
+}
+
 // PruneColumns implements LogicalPlan interface.
 func (p *LogicalSelection) PruneColumns(parentUsedCols []*expression.Column) error {
 	child := p.children[0]
@@ -109,7 +130,7 @@ func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column)
 	}
 	if len(la.AggFuncs) == 0 {
 		// If all the aggregate functions are pruned, we should add an aggregate function to keep the correctness.
-		one, err := aggregation.NewAggFuncDesc(la.ctx, ast.AggFuncFirstRow, []expression.Expression{expression.One})
+		one, err := aggregation.NewAggFuncDesc(la.ctx, ast.AggFuncFirstRow, []expression.Expression{expression.One}, false)
 		if err != nil {
 			return err
 		}
@@ -261,6 +282,29 @@ func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column) error {
 	return nil
 }
 
+// PruneColumns implements LogicalPlan interface.
+func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error {
+	leftCols, rightCols := la.extractUsedCols(parentUsedCols)
+
+	err := la.children[1].PruneColumns(rightCols)
+	if err != nil {
+		return err
+	}
+
+	la.CorCols = ExtractCorColumnsBySchema4LogicalPlan(la.children[1], la.children[0].Schema())
+	for _, col := range la.CorCols {
+		leftCols = append(leftCols, &col.Column)
+	}
+
+	err = la.children[0].PruneColumns(leftCols)
+	if err != nil {
+		return err
+	}
+
+	la.mergeSchema()
+	return nil
+}
+
 func (*columnPruner) name() string {
 	return "column_prune"
 }
@@ -0,0 +1,47 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/expression"
+)
+
+// orderSelectionConditions sorts the conditions of the selections by their evaluation cost,
+// including the ones pushed down to the coprocessor, so the cheap conditions are evaluated
+// first and the rows filtered out by them don't pay for the expensive ones.
+func orderSelectionConditions(plan PhysicalPlan) {
+	for _, child := range plan.Children() {
+		orderSelectionConditions(child)
+	}
+	var copPlans []PhysicalPlan
+	switch p := plan.(type) {
+	case *PhysicalSelection:
+		expression.SortByEvalCost(p.Conditions)
+	case *PhysicalTableReader:
+		copPlans = p.TablePlans
+	case *PhysicalIndexReader:
+		copPlans = p.IndexPlans
+	case *PhysicalIndexLookUpReader:
+		copPlans = append(copPlans, p.IndexPlans...)
+		copPlans = append(copPlans, p.TablePlans...)
+	case *PhysicalTableLookUpReader:
+		copPlans = append(copPlans, p.FilterPlans...)
+		copPlans = append(copPlans, p.TablePlans...)
+	}
+	for _, copPlan := range copPlans {
+		if sel, ok := copPlan.(*PhysicalSelection); ok {
+			expression.SortByEvalCost(sel.Conditions)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/pingcap/tidb/expression"
+)
+
+// pruneCopOutputColumns prunes the columns returned by the readers which are only used by the
+// operators pushed down to the coprocessor, e.g. the filters. The columns are read by the
+// coprocessor anyway, but they don't have to be encoded and sent back only to be discarded by
+// the projections above the readers.
+func pruneCopOutputColumns(plan PhysicalPlan) error {
+	for _, child := range plan.Children() {
+		if err := pruneCopOutputColumns(child); err != nil {
+			return err
+		}
+	}
+	proj, ok := plan.(*PhysicalProjection)
+	if !ok {
+		return nil
+	}
+	usedCols := expression.ExtractColumnsFromExpressions(nil, proj.Exprs, nil)
+	var pruned bool
+	switch reader := proj.children[0].(type) {
+	case *PhysicalTableReader:
+		if cols := prunedOutputColumns(reader.schema, usedCols); cols != nil {
+			reader.schema = expression.NewSchema(cols...)
+			reader.OutputColumns = reader.schema.Clone().Columns
+			pruned = true
+		}
+	case *PhysicalIndexReader:
+		if cols := prunedOutputColumns(reader.schema, usedCols); cols != nil {
+			reader.schema = expression.NewSchema(cols...)
+			reader.OutputColumns = reader.schema.Clone().Columns
+			pruned = true
+		}
+	}
+	if !pruned {
+		return nil
+	}
+	// The indices of the columns are changed, the reader is resolved by the projection as well.
+	return proj.ResolveIndices()
+}
+
+// prunedOutputColumns returns the columns of the reader schema which are used, or nil if
+// nothing can be pruned.
+func prunedOutputColumns(schema *expression.Schema, usedCols []*expression.Column) []*expression.Column {
+	cols := make([]*expression.Column, 0, schema.Len())
+	for _, col := range schema.Columns {
+		// The virtual columns are evaluated on the other columns read by the readers.
+		if col.VirtualExpr != nil {
+			return nil
+		}
+		for _, used := range usedCols {
+			if col.UniqueID == used.UniqueID {
+				cols = append(cols, col)
+				break
+			}
+		}
+	}
+	// For SQL like `select 1 from t`, tikv's response will be empty if no column is returned.
+	if len(cols) == 0 || len(cols) == schema.Len() {
+		return nil
+	}
+	return cols
+}
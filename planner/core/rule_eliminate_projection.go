@@ -94,6 +94,7 @@ func eliminatePhysicalProjection(p PhysicalPlan) PhysicalPlan {
 		oldCol.Index = newCols[i].Index
 		oldCol.ID = newCols[i].ID
 		oldCol.UniqueID = newCols[i].UniqueID
+		oldCol.VirtualExpr = newCols[i].VirtualExpr
 		newRoot.Schema().Columns[i] = oldCol
 	}
 	return newRoot
@@ -122,6 +123,8 @@ func (pe *projectionEliminator) eliminate(p LogicalPlan, replace map[string]*exp
 	switch x := p.(type) {
 	case *LogicalJoin:
 		x.schema = buildLogicalJoinSchema(x.JoinType, x)
+	case *LogicalApply:
+		x.SetSchema(buildLogicalJoinSchema(x.JoinType, x))
 	default:
 		for _, dst := range p.Schema().Columns {
 			resolveColumnAndReplace(dst, replace)
@@ -178,6 +181,16 @@ func (p *LogicalJoin) replaceExprColumns(replace map[string]*expression.Column)
 	}
 }
 
+func (la *LogicalApply) replaceExprColumns(replace map[string]*expression.Column) {
+	la.LogicalJoin.replaceExprColumns(replace)
+	for _, coCol := range la.CorCols {
+		dst := replace[string(coCol.Column.HashCode(nil))]
+		if dst != nil {
+			coCol.Column = *dst
+		}
+	}
+}
+
 func (p *LogicalProjection) replaceExprColumns(replace map[string]*expression.Column) {
 	for _, expr := range p.Exprs {
 		ResolveExprAndReplace(expr, replace)
@@ -202,6 +215,14 @@ func (p *LogicalSelection) replaceExprColumns(replace map[string]*expression.Col
 	}
 }
 
+func (p *LogicalExpand) replaceExprColumns(replace map[string]*expression.Column) {
+	for _, exprs := range p.LevelExprs {
+		for _, expr := range exprs {
+			ResolveExprAndReplace(expr, replace)
+		}
+	}
+}
+
 func (ls *LogicalSort) replaceExprColumns(replace map[string]*expression.Column) {
 	for _, byItem := range ls.ByItems {
 		ResolveExprAndReplace(byItem.Expr, replace)
@@ -0,0 +1,120 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/sessionctx"
+)
+
+// gcSubstituter substitutes the expressions of the expression indexes with their hidden columns,
+// so the indexes can be used to read the rows. For the index on `lower(name)`, the filter
+// `lower(name) = 'a'` is rewritten to `_V$_idx_0 = 'a'`.
+type gcSubstituter struct {
+}
+
+// exprColumnMap maps the virtual expressions to the indexed hidden columns.
+type exprColumnMap map[expression.Expression]*expression.Column
+
+func (gc *gcSubstituter) optimize(ctx context.Context, lp LogicalPlan) (LogicalPlan, error) {
+	exprToColumn := make(exprColumnMap)
+	collectGenerateColumn(lp, exprToColumn)
+	if len(exprToColumn) == 0 {
+		return lp, nil
+	}
+	gc.substitute(lp, exprToColumn)
+	return lp, nil
+}
+
+// collectGenerateColumn collects the indexed virtual columns of the data sources.
+func collectGenerateColumn(lp LogicalPlan, exprToColumn exprColumnMap) {
+	for _, child := range lp.Children() {
+		collectGenerateColumn(child, exprToColumn)
+	}
+	ds, ok := lp.(*DataSource)
+	if !ok {
+		return
+	}
+	for _, idx := range ds.tableInfo.Indices {
+		if idx.Invisible {
+			continue
+		}
+		for _, idxCol := range idx.Columns {
+			colInfo := ds.tableInfo.Columns[idxCol.Offset]
+			if !colInfo.IsGenerated() {
+				continue
+			}
+			col := expression.ColInfo2Col(ds.schema.Columns, colInfo)
+			if col != nil && col.VirtualExpr != nil && col.GetType().Equal(col.VirtualExpr.GetType()) {
+				exprToColumn[col.VirtualExpr] = col
+			}
+		}
+	}
+}
+
+// substituteExpr returns the hidden column if the expression equals to its virtual expression
+// and the column can be read from the schema, otherwise the expression itself is returned.
+func substituteExpr(sctx sessionctx.Context, expr expression.Expression, schema *expression.Schema, exprToColumn exprColumnMap) expression.Expression {
+	for candidateExpr, col := range exprToColumn {
+		if expr.Equal(sctx, candidateExpr) && schema.Contains(col) {
+			return col
+		}
+	}
+	return expr
+}
+
+func (gc *gcSubstituter) substitute(lp LogicalPlan, exprToColumn exprColumnMap) {
+	sctx := lp.SCtx()
+	switch x := lp.(type) {
+	case *LogicalSelection:
+		for i, cond := range x.Conditions {
+			sf, ok := cond.(*expression.ScalarFunction)
+			if !ok {
+				continue
+			}
+			switch sf.FuncName.L {
+			case ast.EQ, ast.NE, ast.LT, ast.LE, ast.GT, ast.GE, ast.In, ast.IsNull:
+			default:
+				continue
+			}
+			args := make([]expression.Expression, 0, len(sf.GetArgs()))
+			changed := false
+			for _, arg := range sf.GetArgs() {
+				newArg := arg
+				if !arg.ConstItem() {
+					newArg = substituteExpr(sctx, arg, x.Schema(), exprToColumn)
+				}
+				changed = changed || newArg != arg
+				args = append(args, newArg)
+			}
+			if changed {
+				x.Conditions[i] = expression.NewFunctionInternal(sctx, sf.FuncName.L, sf.GetType(), args...)
+			}
+		}
+	case *LogicalProjection:
+		for i := range x.Exprs {
+			x.Exprs[i] = substituteExpr(sctx, x.Exprs[i], x.children[0].Schema(), exprToColumn)
+		}
+	}
+	for _, child := range lp.Children() {
+		gc.substitute(child, exprToColumn)
+	}
+}
+
+func (*gcSubstituter) name() string {
+	return "generate_column_substitute"
+}
@@ -0,0 +1,182 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+// lateMaterializationSelectivity is the max selectivity of the filters for which the
+// late materialization is used. Reading the qualified rows by handles is much slower than
+// scanning them, so it only pays off when few rows are left after the filters.
+const lateMaterializationSelectivity = 0.1
+
+// applyLateMaterialization converts the table readers whose pushed down filters are
+// selective and only use a few columns to the two-phase table look up readers: the first
+// phase scans the columns used by the filters and the handle, evaluates the filters and
+// returns the handles of the qualified rows, the second phase reads all the columns of
+// these rows by their handles.
+func applyLateMaterialization(plan PhysicalPlan) PhysicalPlan {
+	if !plan.SCtx().GetSessionVars().EnableLateMaterialization {
+		return plan
+	}
+	return lateMaterialize(plan)
+}
+
+func lateMaterialize(plan PhysicalPlan) PhysicalPlan {
+	switch p := plan.(type) {
+	case *PhysicalUnionScan:
+		// UnionScan needs to know how the rows of its child are read.
+		return plan
+	case *PhysicalTableReader:
+		if lookUp := convertToTableLookUp(p); lookUp != nil {
+			return lookUp
+		}
+		return plan
+	}
+	for i, child := range plan.Children() {
+		plan.Children()[i] = lateMaterialize(child)
+	}
+	return plan
+}
+
+func convertToTableLookUp(reader *PhysicalTableReader) *PhysicalTableLookUpReader {
+	if len(reader.TablePlans) != 2 {
+		return nil
+	}
+	ts, ok := reader.TablePlans[0].(*PhysicalTableScan)
+	if !ok || ts.KeepOrder {
+		return nil
+	}
+	// The virtual columns are only evaluated by the table readers.
+	for _, col := range ts.schema.Columns {
+		if col.VirtualExpr != nil {
+			return nil
+		}
+	}
+	sel, ok := reader.TablePlans[1].(*PhysicalSelection)
+	if !ok {
+		return nil
+	}
+	conds := make([]expression.Expression, 0, len(sel.Conditions))
+	for _, cond := range sel.Conditions {
+		if len(expression.ExtractCorColumns(cond)) > 0 {
+			return nil
+		}
+		conds = append(conds, cond.Clone())
+	}
+	if ts.stats == nil || sel.stats == nil || ts.stats.RowCount <= 0 ||
+		sel.stats.RowCount/ts.stats.RowCount > lateMaterializationSelectivity {
+		return nil
+	}
+
+	// Collect the columns used by the filters and the handle column.
+	var filterCols []*expression.Column
+	var filterColInfos []*model.ColumnInfo
+	usedCols := expression.ExtractColumnsFromExpressions(nil, conds, nil)
+	for i, col := range ts.schema.Columns {
+		for _, used := range usedCols {
+			if col.UniqueID == used.UniqueID {
+				filterCols = append(filterCols, col)
+				filterColInfos = append(filterColInfos, ts.Columns[i])
+				break
+			}
+		}
+	}
+	handleCol, handleColInfo := tableScanHandleCol(ts)
+	found := false
+	for _, col := range filterCols {
+		if col.UniqueID == handleCol.UniqueID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		filterCols = append(filterCols, handleCol)
+		filterColInfos = append(filterColInfos, handleColInfo)
+	}
+	// Only the scans which read much more columns than the filters are worth two phases.
+	if len(filterCols)*2 > len(ts.Columns) {
+		return nil
+	}
+
+	ctx := reader.SCtx()
+	filterScan := PhysicalTableScan{
+		Table:           ts.Table,
+		Columns:         filterColInfos,
+		DBName:          ts.DBName,
+		Ranges:          ts.Ranges,
+		AccessCondition: ts.AccessCondition,
+		TableAsName:     ts.TableAsName,
+		Desc:            ts.Desc,
+	}.Init(ctx)
+	filterScan.SetSchema(expression.NewSchema(filterCols...))
+	filterScan.stats = ts.stats
+	filterSel := PhysicalSelection{Conditions: conds}.Init(ctx, sel.stats)
+	filterSel.SetChildren(filterScan)
+
+	tableScan := PhysicalTableScan{
+		Table:       ts.Table,
+		Columns:     ts.Columns,
+		DBName:      ts.DBName,
+		TableAsName: ts.TableAsName,
+	}.Init(ctx)
+	tableScan.SetSchema(ts.schema)
+	tableScan.stats = sel.stats
+
+	lookUp := PhysicalTableLookUpReader{
+		filterPlan: filterSel,
+		tablePlan:  tableScan,
+		HandleCol:  handleCol,
+	}.Init(ctx)
+	lookUp.stats = reader.stats
+	if err := lookUp.ResolveIndices(); err != nil {
+		return nil
+	}
+	return lookUp
+}
+
+// tableScanHandleCol returns the handle column of the table scan, a new column is
+// created if the handle is not read by the scan.
+func tableScanHandleCol(ts *PhysicalTableScan) (*expression.Column, *model.ColumnInfo) {
+	if ts.Table.PKIsHandle {
+		if pkColInfo := ts.Table.GetPkColInfo(); pkColInfo != nil {
+			if col := expression.ColInfo2Col(ts.schema.Columns, pkColInfo); col != nil {
+				return col, pkColInfo
+			}
+			return &expression.Column{
+				RetType:  &pkColInfo.FieldType,
+				UniqueID: ts.ctx.GetSessionVars().AllocPlanColumnID(),
+				ID:       pkColInfo.ID,
+				OrigName: fmt.Sprintf("%v.%v.%v", ts.DBName, ts.Table.Name, pkColInfo.Name),
+			}, pkColInfo
+		}
+	}
+	for _, col := range ts.schema.Columns {
+		if col.ID == model.ExtraHandleID {
+			return col, model.NewExtraHandleColInfo()
+		}
+	}
+	return &expression.Column{
+		RetType:  types.NewFieldType(mysql.TypeLonglong),
+		UniqueID: ts.ctx.GetSessionVars().AllocPlanColumnID(),
+		ID:       model.ExtraHandleID,
+		OrigName: fmt.Sprintf("%v.%v.%v", ts.DBName, ts.Table.Name, model.ExtraHandleName),
+	}, model.NewExtraHandleColInfo()
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+)
+
+// maxOneRowEliminator removes the MaxOneRow whose child is known to return no more than one row,
+// e.g. the scalar subquery `(select b from t where t.pk = 1)`. It relies on the MaxOneRow property
+// collected by the build_keys rule.
+type maxOneRowEliminator struct{}
+
+func (s *maxOneRowEliminator) optimize(ctx context.Context, p LogicalPlan) (LogicalPlan, error) {
+	return eliminateMaxOneRow(p), nil
+}
+
+func eliminateMaxOneRow(p LogicalPlan) LogicalPlan {
+	for i, child := range p.Children() {
+		p.SetChild(i, eliminateMaxOneRow(child))
+	}
+	if mor, ok := p.(*LogicalMaxOneRow); ok && mor.children[0].MaxOneRow() {
+		return mor.children[0]
+	}
+	return p
+}
+
+func (*maxOneRowEliminator) name() string {
+	return "max_one_row_eliminate"
+}
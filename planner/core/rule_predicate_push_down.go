@@ -18,7 +18,6 @@ import (
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/mysql"
-	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -35,6 +34,11 @@ func addSelection(p LogicalPlan, child LogicalPlan, conditions []expression.Expr
 		return
 	}
 	conditions = expression.PropagateConstant(p.SCtx(), conditions)
+	conditions = expression.SimplifyPredicates(p.SCtx(), conditions)
+	if len(conditions) == 0 {
+		p.Children()[chIdx] = child
+		return
+	}
 	// Return table dual when filter is constant false or null.
 	dual := Conds2TableDual(child, conditions)
 	if dual != nil {
@@ -72,11 +76,20 @@ func splitSetGetVarFunc(filters []expression.Expression) ([]expression.Expressio
 
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	// Simplify the conditions before pushing them down, so the dead predicates are not pushed.
+	p.Conditions = expression.SimplifyPredicates(p.ctx, p.Conditions)
+	if dual := Conds2TableDual(p, p.Conditions); dual != nil {
+		return nil, dual
+	}
 	canBePushDown, canNotBePushDown := splitSetGetVarFunc(p.Conditions)
 	retConditions, child := p.children[0].PredicatePushDown(append(canBePushDown, predicates...))
 	retConditions = append(retConditions, canNotBePushDown...)
 	if len(retConditions) > 0 {
 		p.Conditions = expression.PropagateConstant(p.ctx, retConditions)
+		p.Conditions = expression.SimplifyPredicates(p.ctx, p.Conditions)
+		if len(p.Conditions) == 0 {
+			return nil, child
+		}
 		// Return table dual when filter is constant false or null.
 		dual := Conds2TableDual(p, p.Conditions)
 		if dual != nil {
@@ -295,7 +308,7 @@ func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
 	// then simplify embedding outer join.
 	canBeSimplified := false
 	for _, expr := range predicates {
-		isOk := isNullRejected(p.ctx, innerTable.Schema(), expr)
+		isOk := expression.IsNullRejecting(p.ctx, innerTable.Schema(), expr)
 		if isOk {
 			canBeSimplified = true
 			break
@@ -306,29 +319,6 @@ func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
 	}
 }
 
-// isNullRejected check whether a condition is null-rejected
-// A condition would be null-rejected in one of following cases:
-// If it is a predicate containing a reference to an inner table that evaluates to UNKNOWN or FALSE when one of its arguments is NULL.
-// If it is a conjunction containing a null-rejected condition as a conjunct.
-// If it is a disjunction of null-rejected conditions.
-func isNullRejected(ctx sessionctx.Context, schema *expression.Schema, expr expression.Expression) bool {
-	expr = expression.PushDownNot(nil, expr)
-	sc := ctx.GetSessionVars().StmtCtx
-	sc.InNullRejectCheck = true
-	result := expression.EvaluateExprWithNull(ctx, schema, expr)
-	sc.InNullRejectCheck = false
-	x, ok := result.(*expression.Constant)
-	if !ok {
-		return false
-	}
-	if x.Value.IsNull() {
-		return true
-	} else if isTrue, err := x.Value.ToBool(sc); err == nil && isTrue == 0 {
-		return true
-	}
-	return false
-}
-
 // PredicatePushDown implements LogicalPlan PredicatePushDown interface.
 func (p *LogicalProjection) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
 	canBePushed := make([]expression.Expression, 0, len(predicates))
@@ -401,6 +391,21 @@ func (p *LogicalLimit) PredicatePushDown(predicates []expression.Expression) ([]
 	return predicates, p
 }
 
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalMaxOneRow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	// MaxOneRow forbids any condition to push down.
+	p.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, p
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalExpand) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	// The conditions on the nullable copies of the group-by columns and the grouping ID
+	// can not be pushed down, since they are different in every level.
+	p.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, p
+}
+
 // deriveOtherConditions given a LogicalJoin, check the OtherConditions to see if we can derive more
 // conditions for left/right child pushdown.
 func deriveOtherConditions(p *LogicalJoin, deriveLeft bool, deriveRight bool) (leftCond []expression.Expression,
@@ -449,7 +454,7 @@ func deriveNotNullExpr(expr expression.Expression, schema *expression.Schema) ex
 	if childCol == nil {
 		childCol = schema.RetrieveColumn(arg1)
 	}
-	if isNullRejected(ctx, schema, expr) && !mysql.HasNotNullFlag(childCol.RetType.Flag) {
+	if expression.IsNullRejecting(ctx, schema, expr) && !mysql.HasNotNullFlag(childCol.RetType.Flag) {
 		return expression.BuildNotNullExpr(ctx, childCol)
 	}
 	return nil
@@ -281,6 +281,23 @@ func (p *LogicalLimit) DeriveStats(childStats []*property.StatsInfo, selfSchema
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalMaxOneRow) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	p.stats = getSingletonStats(selfSchema.Len())
+	return p.stats, nil
+}
+
+func getSingletonStats(length int) *property.StatsInfo {
+	ret := &property.StatsInfo{
+		RowCount:    1.0,
+		Cardinality: make([]float64, length),
+	}
+	for i := 0; i < length; i++ {
+		ret.Cardinality[i] = 1
+	}
+	return ret
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (lt *LogicalTopN) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	lt.stats = deriveLimitStats(childStats[0], float64(lt.Count))
@@ -317,6 +334,25 @@ func (p *LogicalProjection) DeriveStats(childStats []*property.StatsInfo, selfSc
 	return p.stats, nil
 }
 
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalExpand) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	p.stats = &property.StatsInfo{
+		RowCount:    childProfile.RowCount * float64(len(p.LevelExprs)),
+		Cardinality: make([]float64, selfSchema.Len()),
+	}
+	for i := range p.stats.Cardinality {
+		cols := expression.ExtractColumns(p.LevelExprs[0][i])
+		if len(cols) == 0 {
+			// The grouping ID is different in every level.
+			p.stats.Cardinality[i] = float64(len(p.LevelExprs))
+			continue
+		}
+		p.stats.Cardinality[i] = getCardinality(cols, childSchema[0], childProfile)
+	}
+	return p.stats, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (la *LogicalAggregation) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema) (*property.StatsInfo, error) {
 	childProfile := childStats[0]
@@ -89,6 +89,22 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		}
 	case *LogicalLimit, *PhysicalLimit:
 		str = "Limit"
+	case *LogicalApply:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "Apply{" + strings.Join(children, "->") + "}"
+	case *PhysicalApply:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		str = "Apply{" + strings.Join(children, "->") + "}"
+	case *LogicalMaxOneRow, *PhysicalMaxOneRow:
+		str = "MaxOneRow"
 	case *ShowDDL:
 		str = "ShowDDL"
 	case *LogicalShow, *PhysicalShow:
@@ -121,6 +137,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = fmt.Sprintf("Sel(%s)", x.Conditions)
 	case *LogicalProjection, *PhysicalProjection:
 		str = "Projection"
+	case *LogicalExpand, *PhysicalExpand:
+		str = "Expand"
 	case *LogicalTopN:
 		str = fmt.Sprintf("TopN(%v,%d,%d)", x.ByItems, x.Offset, x.Count)
 	case *PhysicalTopN:
@@ -144,6 +162,8 @@ func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
 		str = fmt.Sprintf("IndexReader(%s)", ToString(x.indexPlan))
 	case *PhysicalIndexLookUpReader:
 		str = fmt.Sprintf("IndexLookUp(%s, %s)", ToString(x.indexPlan), ToString(x.tablePlan))
+	case *PhysicalTableLookUpReader:
+		str = fmt.Sprintf("TableLookUp(%s, %s)", ToString(x.filterPlan), ToString(x.tablePlan))
 	case *PhysicalUnionScan:
 		str = fmt.Sprintf("UnionScan(%s)", x.Conditions)
 	case *Analyze:
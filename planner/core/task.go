@@ -142,6 +142,36 @@ func (p *basePhysicalPlan) attach2Task(tasks ...task) task {
 	return attachPlan2Task(p.self, t)
 }
 
+// GetCost computes the cost of apply operator.
+func (p *PhysicalApply) GetCost(lCount, rCount, lCost, rCost float64) float64 {
+	var cpuCost float64
+	sessVars := p.ctx.GetSessionVars()
+	if len(p.LeftConditions) > 0 {
+		cpuCost += lCount * sessVars.CPUFactor
+		lCount *= selectionFactor
+	}
+	if len(p.RightConditions) > 0 {
+		cpuCost += lCount * rCount * sessVars.CPUFactor
+		rCount *= selectionFactor
+	}
+	if len(p.OtherConditions) > 0 {
+		cpuCost += lCount * rCount * sessVars.CPUFactor
+	}
+	// The inner plan is executed once for every outer row.
+	return cpuCost + lCost + lCount*rCost
+}
+
+func (p *PhysicalApply) attach2Task(tasks ...task) task {
+	lTask := finishCopTask(p.ctx, tasks[0].copy())
+	rTask := finishCopTask(p.ctx, tasks[1].copy())
+	p.SetChildren(lTask.plan(), rTask.plan())
+	p.schema = BuildPhysicalJoinSchema(p.JoinType, p)
+	return &rootTask{
+		p:   p,
+		cst: p.GetCost(lTask.count(), rTask.count(), lTask.cost(), rTask.cost()),
+	}
+}
+
 // GetCost computes cost of hash join operator itself.
 func (p *PhysicalHashJoin) GetCost(lCnt, rCnt float64) float64 {
 	buildCnt, probeCnt := lCnt, rCnt
@@ -394,7 +424,8 @@ func (p *PhysicalLimit) attach2Task(tasks ...task) task {
 	if cop, ok := t.(*copTask); ok {
 		// For double read which requires order being kept, the limit cannot be pushed down to the table side,
 		// because handles would be reordered before being sent to table scan.
-		if !cop.keepOrder || !cop.indexPlanFinished || cop.indexPlan == nil {
+		// The limit can't be pushed down below the conditions on the virtual columns either.
+		if (!cop.keepOrder || !cop.indexPlanFinished || cop.indexPlan == nil) && len(cop.rootTaskConds) == 0 {
 			// When limit is pushed down, we should remove its offset.
 			newCount := p.Offset + p.Count
 			childProfile := cop.plan().statsInfo()
@@ -438,8 +469,10 @@ func (p *PhysicalTopN) canPushDown() bool {
 	for _, item := range p.ByItems {
 		exprs = append(exprs, item.Expr)
 	}
-	_, _, remained := expression.ExpressionsToPB(p.ctx.GetSessionVars().StmtCtx, exprs, p.ctx.GetClient())
-	return len(remained) == 0
+	if expression.ContainVirtualColumn(exprs) {
+		return false
+	}
+	return expression.CanExprsPushDown(p.ctx.GetSessionVars().StmtCtx, exprs, p.ctx.GetClient())
 }
 
 func (p *PhysicalTopN) allColsFromSchema(schema *expression.Schema) bool {
@@ -491,7 +524,7 @@ func (p *PhysicalTopN) getPushedDownTopN(childPlan PhysicalPlan) *PhysicalTopN {
 func (p *PhysicalTopN) attach2Task(tasks ...task) task {
 	t := tasks[0].copy()
 	inputCount := t.count()
-	if copTask, ok := t.(*copTask); ok && p.canPushDown() {
+	if copTask, ok := t.(*copTask); ok && len(copTask.rootTaskConds) == 0 && p.canPushDown() {
 		// If all columns in topN are from index plan, we push it to index plan, otherwise we finish the index plan and
 		// push it to table plan.
 		var pushedDownTopN *PhysicalTopN
@@ -514,7 +547,13 @@ func (p *PhysicalTopN) attach2Task(tasks ...task) task {
 // GetCost computes the cost of projection operator itself.
 func (p *PhysicalProjection) GetCost(count float64) float64 {
 	sessVars := p.ctx.GetSessionVars()
-	cpuCost := count * sessVars.CPUFactor
+	// Projecting a row costs a CPUFactor, plus a CPUFactor for each unit of the evaluation
+	// cost of the expressions. The columns are free to evaluate.
+	var evalCost float64
+	for _, expr := range p.Exprs {
+		evalCost += expression.EvalCost(expr)
+	}
+	cpuCost := count * (1 + evalCost) * sessVars.CPUFactor
 	concurrency := float64(sessVars.ProjectionConcurrency)
 	if concurrency <= 0 {
 		return cpuCost
@@ -535,6 +574,14 @@ func (p *PhysicalProjection) attach2Task(tasks ...task) task {
 	return t
 }
 
+func (p *PhysicalExpand) attach2Task(tasks ...task) task {
+	sessVars := p.ctx.GetSessionVars()
+	t := finishCopTask(p.ctx, tasks[0].copy())
+	t = attachPlan2Task(p, t)
+	t.addCost(t.count() * float64(len(p.LevelExprs)) * sessVars.CPUFactor)
+	return t
+}
+
 func (sel *PhysicalSelection) attach2Task(tasks ...task) task {
 	sessVars := sel.ctx.GetSessionVars()
 	t := finishCopTask(sel.ctx, tasks[0].copy())
@@ -549,16 +596,23 @@ func CheckAggCanPushCop(sctx sessionctx.Context, aggFuncs []*aggregation.AggFunc
 	sc := sctx.GetSessionVars().StmtCtx
 	client := sctx.GetClient()
 	for _, aggFunc := range aggFuncs {
+		// The coprocessor can not deduplicate the arguments across regions.
+		if aggFunc.HasDistinct {
+			return false
+		}
+		if expression.ContainVirtualColumn(aggFunc.Args) {
+			return false
+		}
 		pb := aggregation.AggFuncToPBExpr(sc, client, aggFunc)
 		if pb == nil {
 			return false
 		}
 	}
-	_, _, remained := expression.ExpressionsToPB(sc, groupByItems, client)
-	if len(remained) > 0 {
+	// The virtual columns are evaluated by TiDB after they are read.
+	if expression.ContainVirtualColumn(groupByItems) {
 		return false
 	}
-	return true
+	return expression.CanExprsPushDown(sc, groupByItems, client)
 }
 
 // BuildFinalModeAggregation splits either LogicalAggregation or PhysicalAggregation to finalAgg and partial1Agg,
@@ -696,6 +750,10 @@ func (p *PhysicalHashAgg) cpuCostDivisor() (float64, float64) {
 
 func (p *PhysicalHashAgg) attach2Task(tasks ...task) task {
 	t := tasks[0].copy()
+	if cop, ok := t.(*copTask); ok && len(cop.rootTaskConds) > 0 {
+		// The aggregation can't be pushed down below the conditions on the virtual columns.
+		t = finishCopTask(p.ctx, cop)
+	}
 	inputRows := t.count()
 	if cop, ok := t.(*copTask); ok {
 		partialAgg, finalAgg := p.newPartialAggregate()
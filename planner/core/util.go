@@ -25,6 +25,9 @@ type AggregateFuncExtractor struct {
 	inAggregateFuncExpr bool
 	// AggFuncs is the collected AggregateFuncExprs.
 	AggFuncs []*ast.AggregateFuncExpr
+	// skipAggMap stores the correlated aggregate functions which are evaluated in the outer query,
+	// they are not collected.
+	skipAggMap map[*ast.AggregateFuncExpr]*expression.CorrelatedColumn
 }
 
 // Enter implements Visitor interface.
@@ -43,7 +46,9 @@ func (a *AggregateFuncExtractor) Leave(n ast.Node) (ast.Node, bool) {
 	switch v := n.(type) {
 	case *ast.AggregateFuncExpr:
 		a.inAggregateFuncExpr = false
-		a.AggFuncs = append(a.AggFuncs, v)
+		if _, ok := a.skipAggMap[v]; !ok {
+			a.AggFuncs = append(a.AggFuncs, v)
+		}
 	}
 	return n, true
 }
@@ -149,3 +154,44 @@ func buildLogicalJoinSchema(joinType JoinType, join LogicalPlan) *expression.Sch
 func BuildPhysicalJoinSchema(joinType JoinType, join PhysicalPlan) *expression.Schema {
 	return expression.MergeSchema(join.Children()[0].Schema(), join.Children()[1].Schema())
 }
+
+// ExtractCorrelatedCols4LogicalPlan recursively extracts all of the correlated columns
+// from a plan tree by calling LogicalPlan.ExtractCorrelatedCols.
+func ExtractCorrelatedCols4LogicalPlan(p LogicalPlan) []*expression.CorrelatedColumn {
+	corCols := p.ExtractCorrelatedCols()
+	for _, child := range p.Children() {
+		corCols = append(corCols, ExtractCorrelatedCols4LogicalPlan(child)...)
+	}
+	return corCols
+}
+
+// ExtractCorColumnsBySchema4LogicalPlan only extracts the correlated columns of p that match the specified schema.
+// The correlated columns which refer to the same column share the same Data, so setting the
+// returned columns sets all of them. e.g. If the correlated columns from p are {t1.a, t2.a, t1.a},
+// and the schema is {t2.a, t2.b}, then the result is {t2.a}.
+func ExtractCorColumnsBySchema4LogicalPlan(p LogicalPlan, schema *expression.Schema) []*expression.CorrelatedColumn {
+	corCols := ExtractCorrelatedCols4LogicalPlan(p)
+	resultCorCols := make([]*expression.CorrelatedColumn, schema.Len())
+	for _, corCol := range corCols {
+		idx := schema.ColumnIndex(&corCol.Column)
+		if idx == -1 {
+			continue
+		}
+		if resultCorCols[idx] == nil {
+			resultCorCols[idx] = &expression.CorrelatedColumn{
+				Column: *schema.Columns[idx],
+				Data:   new(types.Datum),
+			}
+		}
+		corCol.Data = resultCorCols[idx].Data
+	}
+	// Shrink slice. e.g. [col1, nil, col2, nil] will be changed to [col1, col2].
+	length := 0
+	for _, col := range resultCorCols {
+		if col != nil {
+			resultCorCols[length] = col
+			length++
+		}
+	}
+	return resultCorCols[:length]
+}
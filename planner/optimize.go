@@ -16,28 +16,68 @@ package planner
 import (
 	"context"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/cascades"
 	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 )
 
 // Optimize does optimization and creates a Plan.
 // The node must be prepared first.
 func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, error) {
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan("planner.Optimize", opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+	}
+	if err := checkReadOnly(sctx, node); err != nil {
+		return nil, nil, err
+	}
 	sctx.PrepareTxnFuture(ctx)
 
+	// The statements planned with their baselines don't use the plan cache, so the evolved
+	// baselines take effect immediately.
+	sessVars := sctx.GetSessionVars()
+	sessVars.StmtCtx.PlanBaseline = plannercore.MatchPlanBaseline(sctx, node)
+	sessVars.FoundInPlanBaseline = sessVars.StmtCtx.PlanBaseline != nil
+
+	// Try to reuse the cached plan of the statements which only differ in literals.
+	var cacheStmt *plannercore.PlanCacheStmt
+	if sessVars.StmtCtx.PlanBaseline == nil {
+		cacheStmt = plannercore.NewPlanCacheStmt(sctx, is, node)
+	}
+	if cacheStmt != nil {
+		p, names, ok, err := cacheStmt.GetPlan(sctx)
+		if err != nil || ok {
+			return p, names, err
+		}
+	}
+
 	// build logical plan
 	sctx.GetSessionVars().PlanID = 0
 	sctx.GetSessionVars().PlanColumnID = 0
 	builder := plannercore.NewPlanBuilder(sctx, is)
+	builder.SetPlanCacheStmt(cacheStmt)
 	p, err := builder.Build(ctx, node)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Check privilege. Maybe it's better to move this to the Preprocess, but
+	// we need the table information to check privilege, which is collected
+	// into the visitInfo in the logical plan builder.
+	if pm := privilege.GetPrivilegeManager(sctx); pm != nil {
+		if err := plannercore.CheckPrivilege(pm, builder.GetVisitInfo()); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	names := p.OutputNames()
 
 	// Handle the non-logical plan statement.
@@ -52,9 +92,60 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 		return finalPlan, names, err
 	}
 	finalPlan, err := plannercore.DoOptimize(ctx, builder.GetOptFlag(), logic)
+	if err == nil && cacheStmt != nil {
+		cacheStmt.Put(sctx, finalPlan, names, builder.GetVisitInfo())
+	}
 	return finalPlan, names, err
 }
 
+// checkReadOnly rejects the statements which write data or change the schema
+// when the server is in the read-only mode. The internal SQLs are always
+// allowed, and the users with the SUPER privilege are only restricted by
+// super_read_only.
+func checkReadOnly(sctx sessionctx.Context, node ast.Node) error {
+	sessVars := sctx.GetSessionVars()
+	if sessVars.InRestrictedSQL {
+		return nil
+	}
+	option := "--super-read-only"
+	if !variable.GetSuperReadOnly() {
+		if !variable.GetReadOnly() {
+			return nil
+		}
+		pm := privilege.GetPrivilegeManager(sctx)
+		if pm == nil || pm.RequestVerification("", "", "", mysql.SuperPriv) {
+			return nil
+		}
+		option = "--read-only"
+	}
+	if isReadOnlyStmt(node) {
+		return nil
+	}
+	return plannercore.ErrOptionPreventsStatement.GenWithStackByArgs(option)
+}
+
+// isReadOnlyStmt returns whether the statement is allowed in the read-only mode. The
+// SELECT statements are allowed even if they assign the user variables, since the
+// variables belong to the session.
+func isReadOnlyStmt(node ast.Node) bool {
+	switch st := node.(type) {
+	case *ast.SelectStmt, *ast.SetStmt, *ast.UseStmt, *ast.ShowStmt, *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt:
+		return true
+	case *ast.ExplainStmt:
+		return isReadOnlyStmt(st.Stmt)
+	case *ast.BRIEStmt:
+		return st.Kind == ast.BRIEKindBackup
+	case *ast.AdminStmt:
+		switch st.Tp {
+		case ast.AdminShowDDL, ast.AdminShowDDLJobs, ast.AdminShowBaselines, ast.AdminShowTableRegions,
+			ast.AdminShowTableDistributions, ast.AdminShowDDLProgress, ast.AdminShowAnalyzeProgress,
+			ast.AdminRecommendIndex, ast.AdminFlushPlanCache, ast.AdminFlushResultCache:
+			return true
+		}
+	}
+	return false
+}
+
 func init() {
 	plannercore.OptimizeAstNode = Optimize
 }
@@ -43,6 +43,8 @@ type AccessPath struct {
 	IsTablePath bool
 	// Forced means this path is generated by `use/force index()`.
 	Forced bool
+	// IsHypothetical means the index of this path doesn't exist, it's only used to estimate the plans.
+	IsHypothetical bool
 
 	IsDNFCond bool
 }
@@ -45,13 +45,16 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -102,6 +105,13 @@ type clientConn struct {
 	status       int32             // dispatching/reading/shutdown/waitshutdown
 	lastCode     uint16            // last error code
 	collation    uint8             // collation used by client, may be different from the collation used by database.
+
+	mu struct {
+		sync.Mutex
+		// cancelFunc cancels the context of the dispatching command, so its in-flight requests
+		// to the store are aborted when the connection is killed.
+		cancelFunc context.CancelFunc
+	}
 }
 
 func (cc *clientConn) String() string {
@@ -223,6 +233,27 @@ func (cc *clientConn) writePacket(data []byte) error {
 	return cc.pkt.writePacket(data)
 }
 
+// writeRowPacket writes a row packet dumped into data, the large values left
+// out of data are written from where they are to avoid copying them.
+func (cc *clientConn) writeRowPacket(data []byte, large []largeValue) error {
+	if len(large) == 0 {
+		return cc.writePacket(data)
+	}
+	failpoint.Inject("FakeClientConn", func() {
+		if cc.pkt == nil {
+			failpoint.Return(nil)
+		}
+	})
+	segs := make([][]byte, 0, 2*len(large)+1)
+	start := 4
+	for _, v := range large {
+		segs = append(segs, data[start:v.offset], v.value)
+		start = v.offset
+	}
+	segs = append(segs, data[start:])
+	return cc.pkt.writeSegments(segs...)
+}
+
 // getSessionVarsWaitTimeout get session variable wait_timeout
 func (cc *clientConn) getSessionVarsWaitTimeout(ctx context.Context) uint64 {
 	valStr, exists := cc.ctx.GetSessionVars().GetSystemVar(variable.WaitTimeout)
@@ -509,6 +540,11 @@ func (cc *clientConn) openSessionAndDoAuth() error {
 	if err != nil {
 		return err
 	}
+	host, err := cc.PeerHost("NO")
+	if err != nil {
+		return err
+	}
+	cc.ctx.GetSessionVars().User = &auth.UserIdentity{Username: cc.user, Hostname: host}
 	if cc.dbname != "" {
 		err = cc.useDB(context.Background(), cc.dbname)
 		if err != nil {
@@ -665,6 +701,26 @@ func errStrForLog(err error) string {
 // It also gets a token from server which is used to limit the concurrently handling clients.
 // The most frequently used command is ComQuery.
 func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
+	// Only start a root span when a tracer is registered, the spans of the whole
+	// command are derived from it and sent to the registered tracer. The server
+	// doesn't bundle a tracer or an exporter, the embedders register an opentracing
+	// tracer, such as the OpenTelemetry bridge, to export the spans.
+	if _, isNoop := opentracing.GlobalTracer().(opentracing.NoopTracer); !isNoop {
+		span := opentracing.StartSpan("server.dispatch")
+		defer span.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span)
+	}
+	ctx, cancelFunc := context.WithCancel(ctx)
+	cc.mu.Lock()
+	cc.mu.cancelFunc = cancelFunc
+	cc.mu.Unlock()
+	defer func() {
+		cc.mu.Lock()
+		cc.mu.cancelFunc = nil
+		cc.mu.Unlock()
+		cancelFunc()
+	}()
+
 	cc.lastPacket = data
 	cmd := data[0]
 	data = data[1:]
@@ -930,6 +986,7 @@ func (cc *clientConn) writeColumnInfo(columns []*ColumnInfo, serverStatus uint16
 // serverStatus, a flag bit represents server information
 func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool, serverStatus uint16) error {
 	data := cc.alloc.AllocWithLen(4, 1024)
+	var large []largeValue
 	req := rs.NewChunk()
 	gotColumnInfo := false
 	for {
@@ -953,16 +1010,16 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 			break
 		}
 		for i := 0; i < rowCount; i++ {
-			data = data[0:4]
+			data, large = data[0:4], large[:0]
 			if binary {
-				data, err = dumpBinaryRow(data, rs.Columns(), req.GetRow(i))
+				data, err = dumpBinaryRow(data, rs.Columns(), req.GetRow(i), &large)
 			} else {
-				data, err = dumpTextRow(data, rs.Columns(), req.GetRow(i))
+				data, err = dumpTextRow(data, rs.Columns(), req.GetRow(i), &large)
 			}
 			if err != nil {
 				return err
 			}
-			if err = cc.writePacket(data); err != nil {
+			if err = cc.writeRowPacket(data, large); err != nil {
 				return err
 			}
 		}
@@ -1016,14 +1073,15 @@ func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet
 	rs.StoreFetchedRows(fetchedRows)
 
 	data := cc.alloc.AllocWithLen(4, 1024)
+	var large []largeValue
 	var err error
 	for _, row := range curRows {
-		data = data[0:4]
-		data, err = dumpBinaryRow(data, rs.Columns(), row)
+		data, large = data[0:4], large[:0]
+		data, err = dumpBinaryRow(data, rs.Columns(), row, &large)
 		if err != nil {
 			return err
 		}
-		if err = cc.writePacket(data); err != nil {
+		if err = cc.writeRowPacket(data, large); err != nil {
 			return err
 		}
 	}
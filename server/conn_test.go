@@ -239,6 +239,33 @@ func (ts *ConnTestSuite) TestShutDown(c *C) {
 	c.Assert(rs.closed, Equals, int32(1))
 }
 
+type blockingTiDBCtx struct {
+	mockTiDBCtx
+	started chan struct{}
+}
+
+func (c *blockingTiDBCtx) SetCommandValue(command byte) {}
+
+func (c *blockingTiDBCtx) Execute(ctx context.Context, sql string) ([]ResultSet, error) {
+	close(c.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (ts *ConnTestSuite) TestKillConnCancelsCommand(c *C) {
+	tidbCtx := &blockingTiDBCtx{started: make(chan struct{})}
+	cc := &clientConn{ctx: tidbCtx}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- cc.dispatch(context.Background(), append([]byte{mysql.ComQuery}, "select 1"...))
+	}()
+	<-tidbCtx.started
+	// The context of the dispatching command is cancelled, so its requests are aborted.
+	killConn(cc)
+	c.Assert(<-errCh, Equals, context.Canceled)
+	c.Assert(cc.mu.cancelFunc, IsNil)
+}
+
 func (ts *ConnTestSuite) TestShutdownOrNotify(c *C) {
 	c.Parallel()
 	se, err := session.CreateSession4Test(ts.store)
@@ -0,0 +1,85 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/logutil"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// logLevelParam is the form key to change the log level.
+	logLevelParam = "log_level"
+)
+
+// settingsHandler is the handler for list and change the server settings.
+type settingsHandler struct{}
+
+// settings is the response of settingsHandler.
+type settings struct {
+	LogLevel string `json:"log_level"`
+}
+
+// ServeHTTP handles request of list and change the server settings.
+// A POST request with the form `log_level=debug` changes the log level at runtime.
+func (h settingsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		if err := req.ParseForm(); err != nil {
+			writeError(w, err)
+			return
+		}
+		if levelStr := req.Form.Get(logLevelParam); levelStr != "" {
+			if err := logutil.SetLevel(levelStr); err != nil {
+				writeError(w, err)
+				return
+			}
+			l, err := log.ParseLevel(levelStr)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			log.SetLevel(l)
+			config.GetGlobalConfig().Log.Level = levelStr
+		}
+	} else if req.Method != http.MethodGet {
+		writeError(w, errors.Errorf("method %s is not allowed", req.Method))
+		return
+	}
+	writeData(w, settings{LogLevel: logutil.GetLevel()})
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	_, err = w.Write([]byte(err.Error()))
+	terror.Log(errors.Trace(err))
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	js, err := json.MarshalIndent(data, "", " ")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	// write response
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(js)
+	terror.Log(errors.Trace(err))
+}
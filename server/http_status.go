@@ -22,6 +22,7 @@ import (
 	"net/http/pprof"
 
 	"github.com/gorilla/mux"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/soheilhy/cmux"
@@ -41,6 +42,12 @@ func (s *Server) startHTTPServer() {
 		addr = fmt.Sprintf("%s:%d", s.cfg.Status.StatusHost, defaultStatusPort)
 	}
 
+	router.Handle("/settings", settingsHandler{}).Name("Settings")
+
+	// HTTP path for activating and inspecting failpoints, e.g.
+	// `curl -X PUT -d 'return(true)' http://{status}/fail/{failpoint-path}`.
+	router.PathPrefix("/fail/").Handler(http.StripPrefix("/fail", &failpoint.HttpHandler{}))
+
 	serverMux := http.NewServeMux()
 	serverMux.Handle("/", router)
 
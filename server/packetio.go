@@ -111,20 +111,28 @@ func (p *packetIO) readPacket() ([]byte, error) {
 		return data, nil
 	}
 
-	// handle multi-packet
+	// handle multi-packet, the packets are collected first so the payload is
+	// allocated only once no matter how large it is.
+	packets := [][]byte{data}
+	length := len(data)
 	for {
 		buf, err := p.readOnePacket()
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
 
-		data = append(data, buf...)
+		packets = append(packets, buf)
+		length += len(buf)
 
 		if len(buf) < mysql.MaxPayloadLen {
 			break
 		}
 	}
 
+	data = make([]byte, 0, length)
+	for _, buf := range packets {
+		data = append(data, buf...)
+	}
 	return data, nil
 }
 
@@ -166,6 +174,65 @@ func (p *packetIO) writePacket(data []byte) error {
 	}
 }
 
+// writeSegments writes a packet whose payload is the concatenation of segs.
+// Unlike writePacket, the payload doesn't need to be contiguous, so large
+// values can be written from where they are without being copied. The payload
+// is split into packets the same way as writePacket does.
+func (p *packetIO) writeSegments(segs ...[]byte) error {
+	length := 0
+	for _, seg := range segs {
+		length += len(seg)
+	}
+
+	var header [4]byte
+	segIdx, segOff := 0, 0
+	for {
+		size := length
+		if size > mysql.MaxPayloadLen {
+			size = mysql.MaxPayloadLen
+		}
+		header[0] = byte(size)
+		header[1] = byte(size >> 8)
+		header[2] = byte(size >> 16)
+		header[3] = p.sequence
+		if err := p.write(header[:]); err != nil {
+			return err
+		}
+		p.sequence++
+		length -= size
+
+		for remain := size; remain > 0; {
+			seg := segs[segIdx][segOff:]
+			if len(seg) > remain {
+				seg = seg[:remain]
+			}
+			if err := p.write(seg); err != nil {
+				return err
+			}
+			remain -= len(seg)
+			segOff += len(seg)
+			if segOff == len(segs[segIdx]) {
+				segIdx, segOff = segIdx+1, 0
+			}
+		}
+
+		// A payload of exactly mysql.MaxPayloadLen bytes is followed by an empty packet.
+		if size < mysql.MaxPayloadLen {
+			return nil
+		}
+	}
+}
+
+func (p *packetIO) write(data []byte) error {
+	if n, err := p.bufWriter.Write(data); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	} else if n != len(data) {
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	return nil
+}
+
 func (p *packetIO) flush() error {
 	err := p.bufWriter.Flush()
 	if err != nil {
@@ -53,6 +53,25 @@ func (s *PacketIOTestSuite) TestWrite(c *C) {
 	c.Assert(res[3], Equals, byte(0))
 }
 
+func (s *PacketIOTestSuite) TestWriteSegments(c *C) {
+	for _, size := range []int{0, 3, mysql.MaxPayloadLen, mysql.MaxPayloadLen + 5} {
+		payload := make([]byte, size)
+		for i := range payload {
+			payload[i] = byte(i)
+		}
+		var expected, got bytes.Buffer
+		pkt := &packetIO{bufWriter: bufio.NewWriter(&expected)}
+		c.Assert(pkt.writePacket(append(make([]byte, 4), payload...)), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+
+		pkt = &packetIO{bufWriter: bufio.NewWriter(&got)}
+		mid := size / 3
+		c.Assert(pkt.writeSegments(payload[:mid], nil, payload[mid:size-mid], payload[size-mid:]), IsNil)
+		c.Assert(pkt.flush(), IsNil)
+		c.Assert(bytes.Equal(got.Bytes(), expected.Bytes()), IsTrue, Commentf("size %d", size))
+	}
+}
+
 func (s *PacketIOTestSuite) TestRead(c *C) {
 	var inBuffer bytes.Buffer
 	_, err := inBuffer.Write([]byte{0x01, 0x00, 0x00, 0x00, 0x01})
@@ -244,6 +244,12 @@ func (s *Server) onConn(conn *clientConn) {
 func killConn(conn *clientConn) {
 	sessVars := conn.ctx.GetSessionVars()
 	atomic.CompareAndSwapUint32(&sessVars.Killed, 0, 1)
+	conn.mu.Lock()
+	cancelFunc := conn.mu.cancelFunc
+	conn.mu.Unlock()
+	if cancelFunc != nil {
+		cancelFunc()
+	}
 }
 
 // KillAllConnections kills all connections when server is not gracefully shutdown.
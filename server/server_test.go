@@ -19,6 +19,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -148,6 +149,36 @@ func runTestResultFieldTableIsNull(c *C) {
 	})
 }
 
+func runTestLargeValue(c *C) {
+	// Prepared statements aren't supported, so the parameters are interpolated
+	// by the client.
+	runTestsOnNewDB(c, func(config *mysql.Config) {
+		config.InterpolateParams = true
+		config.MaxAllowedPacket = 1 << 26
+	}, "LargeValue", func(dbt *DBTest) {
+		// The values are larger than mysql.MaxPayloadLen, so the packets are split.
+		size := 1<<24 + 10
+		var length int
+		var str string
+		err := dbt.db.QueryRow("select length(?)", strings.Repeat("a", size)).Scan(&length)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(length, Equals, size)
+
+		err = dbt.db.QueryRow(fmt.Sprintf("select repeat('a', %d), 1", size)).Scan(&str, &length)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(str == strings.Repeat("a", size), IsTrue)
+		dbt.Assert(length, Equals, 1)
+
+		dbt.mustExec("create table test (id int, b longblob)")
+		_, err = dbt.db.Exec("insert into test values (?, ?)", 1, strings.Repeat("c", 1<<20))
+		dbt.Assert(err, IsNil)
+		err = dbt.db.QueryRow("select b, id from test").Scan(&str, &length)
+		dbt.Assert(err, IsNil)
+		dbt.Assert(str == strings.Repeat("c", 1<<20), IsTrue)
+		dbt.Assert(length, Equals, 1)
+	})
+}
+
 const retryTime = 100
 
 func waitUntilServerOnline(statusPort uint) {
@@ -16,13 +16,21 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	tmysql "github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/util/logutil"
 )
 
 type TidbTestSuite struct {
@@ -82,6 +90,11 @@ func (ts *TidbTestSuite) TestResultFieldTableIsNull(c *C) {
 	runTestResultFieldTableIsNull(c)
 }
 
+func (ts *TidbTestSuite) TestLargeValue(c *C) {
+	c.Parallel()
+	runTestLargeValue(c)
+}
+
 func (ts *TidbTestSuite) TestShowTablesFlen(c *C) {
 	qctx, err := ts.tidbdrv.OpenCtx(uint64(0), 0, uint8(tmysql.DefaultCollationID), "test", nil)
 	c.Assert(err, IsNil)
@@ -116,3 +129,62 @@ func (ts *TidbTestSuite) TestNullFlag(c *C) {
 	expectFlag := uint16(tmysql.NotNullFlag | tmysql.BinaryFlag)
 	c.Assert(dumpFlag(cols[0].Type, cols[0].Flag), Equals, expectFlag)
 }
+
+func (ts *TidbTestSuite) TestFailpointHTTP(c *C) {
+	fpName := "github.com/pingcap/tidb/server/mockFailpointHTTP"
+	url := fmt.Sprintf("http://127.0.0.1:10090/fail/%s", fpName)
+
+	req, err := http.NewRequest(http.MethodPut, url, strings.NewReader(`return(true)`))
+	c.Assert(err, IsNil)
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusNoContent)
+	resp.Body.Close()
+	status, err := failpoint.Status(fpName)
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, "return(true)")
+
+	resp, err = http.Get(url)
+	c.Assert(err, IsNil)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(strings.TrimSpace(string(body)), Equals, "return(true)")
+
+	req, err = http.NewRequest(http.MethodDelete, url, nil)
+	c.Assert(err, IsNil)
+	resp, err = http.DefaultClient.Do(req)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusNoContent)
+	resp.Body.Close()
+	_, err = failpoint.Status(fpName)
+	c.Assert(err, NotNil)
+}
+
+func (ts *TidbTestSuite) TestSettingsLogLevel(c *C) {
+	settingsURL := "http://127.0.0.1:10090/settings"
+	oldLevel := logutil.GetLevel()
+	defer func() {
+		c.Assert(logutil.SetLevel(oldLevel), IsNil)
+	}()
+
+	resp, err := http.PostForm(settingsURL, url.Values{"log_level": {"warn"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	resp.Body.Close()
+	c.Assert(string(body), Matches, `(?s).*"log_level": "warn".*`)
+	c.Assert(logutil.GetLevel(), Equals, "warn")
+
+	resp, err = http.Get(settingsURL)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	resp.Body.Close()
+
+	resp, err = http.PostForm(settingsURL, url.Values{"log_level": {"whatever"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+	resp.Body.Close()
+	c.Assert(logutil.GetLevel(), Equals, "warn")
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/hack"
 	"io"
 	"math"
 	"net/http"
@@ -134,6 +135,27 @@ func dumpLengthEncodedString(buffer []byte, bytes []byte) []byte {
 	return buffer
 }
 
+// largeValueSize is the length from which a string value isn't copied into the
+// row buffer, but written to the connection directly from where it is.
+const largeValueSize = defaultWriterSize
+
+// largeValue is a value left out of a row buffer, it belongs at offset of the buffer.
+type largeValue struct {
+	offset int
+	value  []byte
+}
+
+// dumpLengthEncodedValue is like dumpLengthEncodedString, but if large isn't nil
+// and value is large, only its length is appended and the value is recorded in large.
+func dumpLengthEncodedValue(buffer []byte, value []byte, large *[]largeValue) []byte {
+	if large == nil || len(value) < largeValueSize {
+		return dumpLengthEncodedString(buffer, value)
+	}
+	buffer = dumpLengthEncodedInt(buffer, uint64(len(value)))
+	*large = append(*large, largeValue{offset: len(buffer), value: value})
+	return buffer
+}
+
 func dumpUint16(buffer []byte, n uint16) []byte {
 	buffer = append(buffer, byte(n))
 	buffer = append(buffer, byte(n>>8))
@@ -168,7 +190,9 @@ func init() {
 	}
 }
 
-func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte, error) {
+// dumpBinaryRow dumps row into buffer in the binary protocol. If large isn't nil,
+// the large string values are recorded in it instead of being copied into buffer.
+func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row, large *[]largeValue) ([]byte, error) {
 	buffer = append(buffer, mysql.OKHeader)
 	nullBitmapOff := len(buffer)
 	numBytes4Null := (len(columns) + 7 + 2) / 8
@@ -197,7 +221,9 @@ func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte,
 			buffer = dumpUint64(buffer, math.Float64bits(row.GetFloat64(i)))
 		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
 			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
-			buffer = dumpLengthEncodedString(buffer, row.GetBytes(i))
+			buffer = dumpLengthEncodedValue(buffer, row.GetBytes(i), large)
+		case mysql.TypeJSON:
+			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetJSON(i).String()))
 		default:
 			return nil, errInvalidType.GenWithStack("invalid type %v", columns[i].Type)
 		}
@@ -205,7 +231,9 @@ func dumpBinaryRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte,
 	return buffer, nil
 }
 
-func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte, error) {
+// dumpTextRow dumps row into buffer in the text protocol. If large isn't nil,
+// the large string values are recorded in it instead of being copied into buffer.
+func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row, large *[]largeValue) ([]byte, error) {
 	tmp := make([]byte, 0, 20)
 	for i, col := range columns {
 		if row.IsNull(i) {
@@ -248,7 +276,9 @@ func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row) ([]byte, e
 			buffer = dumpLengthEncodedString(buffer, tmp)
 		case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar, mysql.TypeBit,
 			mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob, mysql.TypeBlob:
-			buffer = dumpLengthEncodedString(buffer, row.GetBytes(i))
+			buffer = dumpLengthEncodedValue(buffer, row.GetBytes(i), large)
+		case mysql.TypeJSON:
+			buffer = dumpLengthEncodedString(buffer, hack.Slice(row.GetJSON(i).String()))
 		default:
 			return nil, errInvalidType.GenWithStack("invalid type %v", columns[i].Type)
 		}
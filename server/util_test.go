@@ -22,6 +22,7 @@ import (
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -66,68 +67,74 @@ func (s *testUtilSuite) TestDumpTextValue(c *C) {
 
 	null := types.NewIntDatum(0)
 	null.SetNull()
-	bs, err := dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{null}).ToRow())
+	bs, err := dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{null}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	_, isNull, _, err := parseLengthEncodedBytes(bs)
 	c.Assert(err, IsNil)
 	c.Assert(isNull, IsTrue)
 
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewIntDatum(10)}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewIntDatum(10)}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "10")
 
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewUintDatum(11)}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewUintDatum(11)}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "11")
 
 	columns[0].Flag = columns[0].Flag | uint16(mysql.UnsignedFlag)
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewUintDatum(11)}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewUintDatum(11)}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "11")
 
 	columns[0].Type = mysql.TypeFloat
 	columns[0].Decimal = 1
 	f32 := types.NewFloat32Datum(1.2)
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f32}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f32}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "1.2")
 
 	columns[0].Decimal = 2
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f32}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f32}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "1.20")
 
 	f64 := types.NewFloat64Datum(2.2)
 	columns[0].Type = mysql.TypeDouble
 	columns[0].Decimal = 1
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f64}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f64}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "2.2")
 
 	columns[0].Decimal = 2
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f64}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{f64}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "2.20")
 
 	columns[0].Type = mysql.TypeBlob
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewBytesDatum([]byte("foo"))}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewBytesDatum([]byte("foo"))}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "foo")
 
 	columns[0].Type = mysql.TypeVarchar
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewStringDatum("bar")}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{types.NewStringDatum("bar")}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "bar")
 
+	columns[0].Type = mysql.TypeJSON
+	j := types.NewDatum(json.CreateBinary(map[string]interface{}{"a": []interface{}{int64(1), "b"}}))
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{j}).ToRow(), nil)
+	c.Assert(err, IsNil)
+	c.Assert(mustDecodeStr(c, bs), Equals, `{"a": [1, "b"]}`)
+
 	year := types.NewIntDatum(0)
 	columns[0].Type = mysql.TypeYear
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{year}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{year}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "0000")
 
 	year.SetInt64(1984)
 	columns[0].Type = mysql.TypeYear
-	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{year}).ToRow())
+	bs, err = dumpTextRow(nil, columns, chunk.MutRowFromDatums([]types.Datum{year}).ToRow(), nil)
 	c.Assert(err, IsNil)
 	c.Assert(mustDecodeStr(c, bs), Equals, "1984")
 }
@@ -385,3 +392,22 @@ func (s *testUtilSuite) TestParseNullTermString(c *C) {
 		c.Assert(string(remain), Equals, t.remain)
 	}
 }
+
+func (s *testUtilSuite) TestDumpLargeValue(c *C) {
+	columns := []*ColumnInfo{{Type: mysql.TypeLongBlob}, {Type: mysql.TypeLong}, {Type: mysql.TypeVarchar}}
+	large := make([]byte, largeValueSize)
+	row := chunk.MutRowFromDatums(types.MakeDatums(large, 1, "abc")).ToRow()
+	for _, dump := range []func([]byte, []*ColumnInfo, chunk.Row, *[]largeValue) ([]byte, error){dumpTextRow, dumpBinaryRow} {
+		expected, err := dump(nil, columns, row, nil)
+		c.Assert(err, IsNil)
+		var values []largeValue
+		bs, err := dump(nil, columns, row, &values)
+		c.Assert(err, IsNil)
+		c.Assert(values, HasLen, 1)
+		c.Assert(len(bs), Equals, len(expected)-len(large))
+		// The large value is left out of the buffer without being copied.
+		c.Assert(&values[0].value[0], Equals, &row.GetBytes(0)[0])
+		off := values[0].offset
+		c.Assert(append(append(bs[:off:off], values[0].value...), bs[off:]...), DeepEquals, expected)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/opcode"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/sqlexec"
+	"go.uber.org/zap"
+)
+
+// batchDMLJob is a batch of the batch DML statement, it covers the rows whose shard column values
+// are in [start, end], or the rows whose shard column values are NULL.
+type batchDMLJob struct {
+	start    types.Datum
+	end      types.Datum
+	isNull   bool
+	rowCount uint64
+}
+
+func (job *batchDMLJob) String() string {
+	if job.isNull {
+		return "NULL"
+	}
+	start, err := job.start.ToString()
+	if err != nil {
+		start = fmt.Sprintf("%v", job.start.GetValue())
+	}
+	end, err := job.end.ToString()
+	if err != nil {
+		end = fmt.Sprintf("%v", job.end.GetValue())
+	}
+	return fmt.Sprintf("[%s, %s]", start, end)
+}
+
+// executeBatchDML splits the DML statement into batches by the ranges of the shard column, and
+// executes every batch in its own transaction, so the statement never builds a giant transaction.
+// The batches which have finished are not rolled back if a batch fails, the rows they deleted are
+// gone, so running the statement again resumes from the failed batch.
+func (s *session) executeBatchDML(ctx context.Context, stmt *ast.BatchDMLStmt) error {
+	if err := checkBatchDML(s, stmt); err != nil {
+		return err
+	}
+	jobs, err := s.splitBatchDMLJobs(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	del := stmt.DMLStmt
+	origWhere := del.Where
+	defer func() {
+		del.Where = origWhere
+	}()
+	var finishedRows uint64
+	for i, job := range jobs {
+		var cond ast.ExprNode
+		if job.isNull {
+			cond = &ast.IsNullExpr{Expr: &ast.ColumnNameExpr{Name: stmt.ShardColumn}}
+		} else {
+			cond = &ast.BetweenExpr{
+				Expr:  &ast.ColumnNameExpr{Name: stmt.ShardColumn},
+				Left:  ast.NewValueExpr(job.start.GetValue()),
+				Right: ast.NewValueExpr(job.end.GetValue()),
+			}
+		}
+		del.Where = cond
+		if origWhere != nil {
+			del.Where = &ast.BinaryOperationExpr{Op: opcode.LogicAnd, L: origWhere, R: cond}
+		}
+		if _, err = s.executeStmtNode(ctx, del); err != nil {
+			logutil.Logger(ctx).Warn("batch DML job failed",
+				zap.Int("job", i+1),
+				zap.Int("jobCount", len(jobs)),
+				zap.Stringer("range", job),
+				zap.Error(err))
+			return ErrBatchDMLJobFailed.GenWithStackByArgs(i+1, len(jobs), job.String(), i, finishedRows, err.Error())
+		}
+		affectedRows := s.sessionVars.StmtCtx.AffectedRows()
+		finishedRows += affectedRows
+		logutil.Logger(ctx).Info("batch DML job finished",
+			zap.Int("job", i+1),
+			zap.Int("jobCount", len(jobs)),
+			zap.Stringer("range", job),
+			zap.Uint64("affectedRows", affectedRows))
+	}
+
+	// The statement context belongs to the last job now, reset it to report the result of the
+	// whole statement.
+	if err = executor.ResetContextOfStmt(s, del); err != nil {
+		return err
+	}
+	s.sessionVars.StmtCtx.AddAffectedRows(finishedRows)
+	s.sessionVars.StmtCtx.AppendNote(errors.Errorf("split into %d batch DML jobs", len(jobs)))
+	return nil
+}
+
+func checkBatchDML(s *session, stmt *ast.BatchDMLStmt) error {
+	if s.sessionVars.InTxn() || !s.sessionVars.IsAutocommit() {
+		return ErrBatchDMLUnsupported.GenWithStackByArgs("it can only run in autocommit mode and out of a transaction")
+	}
+	if stmt.Limit == 0 {
+		return ErrBatchDMLUnsupported.GenWithStackByArgs("the batch size must be positive")
+	}
+	if stmt.DMLStmt.Order != nil || stmt.DMLStmt.Limit != nil {
+		return ErrBatchDMLUnsupported.GenWithStackByArgs("the DML statement can't have ORDER BY or LIMIT")
+	}
+	return nil
+}
+
+// splitBatchDMLJobs reads the shard column values of the rows to be changed in order, and splits
+// them into jobs of about stmt.Limit rows. The rows with the same value are always in the same job,
+// so are the rows with NULL values.
+func (s *session) splitBatchDMLJobs(ctx context.Context, stmt *ast.BatchDMLStmt) ([]*batchDMLJob, error) {
+	// The statement has no text, so its result can't be keyed by the result cache.
+	sel := &ast.SelectStmt{
+		SelectStmtOpts: &ast.SelectStmtOpts{SQLCache: false},
+		Fields: &ast.FieldList{Fields: []*ast.SelectField{
+			{Expr: &ast.ColumnNameExpr{Name: stmt.ShardColumn}},
+		}},
+		From:    stmt.DMLStmt.TableRefs,
+		Where:   stmt.DMLStmt.Where,
+		OrderBy: &ast.OrderByClause{Items: []*ast.ByItem{{Expr: &ast.ColumnNameExpr{Name: stmt.ShardColumn}}}},
+	}
+	rs, err := s.executeStmtNode(ctx, sel)
+	if err != nil {
+		return nil, err
+	}
+	defer terror.Call(rs.Close)
+
+	var jobs []*batchDMLJob
+	sc := s.sessionVars.StmtCtx
+	tp := &rs.Fields()[0].Column.FieldType
+	req := rs.NewChunk()
+	for {
+		if err = rs.Next(ctx, req); err != nil {
+			return nil, err
+		}
+		if req.NumRows() == 0 {
+			return jobs, nil
+		}
+		iter := chunk.NewIterator4Chunk(req)
+		for row := iter.Begin(); row != iter.End(); row = iter.Next() {
+			d := row.GetDatum(0, tp)
+			var last *batchDMLJob
+			if len(jobs) > 0 {
+				last = jobs[len(jobs)-1]
+			}
+			if d.IsNull() {
+				// NULLs come first in ascending order, they are all put in one job.
+				if last == nil || !last.isNull {
+					last = &batchDMLJob{isNull: true}
+					jobs = append(jobs, last)
+				}
+				last.rowCount++
+				continue
+			}
+			if last != nil && !last.isNull {
+				cmp, err := d.CompareDatum(sc, &last.end)
+				if err != nil {
+					return nil, err
+				}
+				if cmp == 0 || last.rowCount < stmt.Limit {
+					last.end = *d.Copy()
+					last.rowCount++
+					continue
+				}
+			}
+			jobs = append(jobs, &batchDMLJob{start: *d.Copy(), end: *d.Copy(), rowCount: 1})
+		}
+		req = chunk.Renew(req, s.sessionVars.MaxChunkSize)
+	}
+}
+
+// executeStmtNode compiles and runs a statement node built by the session itself.
+func (s *session) executeStmtNode(ctx context.Context, stmtNode ast.StmtNode) (sqlexec.RecordSet, error) {
+	s.PrepareTxnCtx(ctx)
+	if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
+		return nil, err
+	}
+	compiler := executor.Compiler{Ctx: s}
+	stmt, err := compiler.Compile(ctx, stmtNode)
+	if err != nil {
+		s.rollbackOnError(ctx)
+		return nil, err
+	}
+	return runStmt(ctx, s, stmt)
+}
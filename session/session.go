@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/ngaut/pools"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/domain"
@@ -50,6 +51,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/txninfo"
 	"go.uber.org/zap"
 )
 
@@ -122,6 +124,9 @@ type session struct {
 
 	// shared coprocessor client per session
 	client kv.Client
+
+	// stmtText is the text of the running statement, which is recorded in the info of the transaction.
+	stmtText string
 }
 
 // DDLOwnerChecker returns s.ddlOwnerChecker.
@@ -233,6 +238,14 @@ func (s *session) doCommit(ctx context.Context) error {
 	// Set this option for 2 phase commit to validate schema lease.
 	s.txn.SetOption(kv.SchemaChecker, domain.NewSchemaChecker(domain.GetDomain(s), s.sessionVars.TxnCtx.SchemaVersion, tableIDs))
 
+	// Drop the cached results which read the modified tables.
+	modifiedTableIDs, err := executor.ModifiedTableIDs(s.txn.GetMemBuffer())
+	if err != nil {
+		return err
+	}
+	executor.InvalidateResultCache(modifiedTableIDs)
+	defer executor.InvalidateResultCache(modifiedTableIDs)
+
 	return s.txn.Commit(sessionctx.SetCommitCtx(ctx, s))
 }
 
@@ -518,10 +531,21 @@ func (s *session) SetGlobalSysVar(name, value string) error {
 	sql := fmt.Sprintf(`REPLACE %s.%s VALUES ('%s', '%s');`,
 		mysql.SystemDB, mysql.GlobalVariablesTable, name, sVal)
 	_, _, err = s.ExecRestrictedSQL(sql)
-	return err
+	if err != nil {
+		return err
+	}
+	// Apply the variables cached in the server scope at once, the other
+	// servers apply them when they reload the global variables.
+	variable.SetLocalSystemVar(name, sVal)
+	domain.GetDomain(s).NotifyUpdateGlobalVars()
+	return nil
 }
 
 func (s *session) ParseSQL(ctx context.Context, sql, charset, collation string) ([]ast.StmtNode, []error, error) {
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan("session.ParseSQL", opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+	}
 	s.parser.SetSQLMode(s.sessionVars.SQLMode)
 	return s.parser.Parse(sql, charset, collation)
 }
@@ -535,6 +559,9 @@ func (s *session) executeStatement(ctx context.Context, connID uint64, stmtNode
 	}
 	recordSet, err := runStmt(ctx, s, stmt)
 	if err != nil {
+		if startTS := s.sessionVars.TxnCtx.StartTS; startTS != 0 {
+			ctx = logutil.WithFields(ctx, zap.Uint64("txnStartTS", startTS))
+		}
 		if !kv.ErrKeyExists.Equal(err) {
 			logutil.Logger(ctx).Warn("run statement failed",
 				zap.Int64("schemaVersion", s.sessionVars.TxnCtx.SchemaVersion),
@@ -560,6 +587,11 @@ func (s *session) executeStatement(ctx context.Context, connID uint64, stmtNode
 }
 
 func (s *session) Execute(ctx context.Context, sql string) (recordSets []sqlexec.RecordSet, err error) {
+	if span := opentracing.SpanFromContext(ctx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan("session.Execute", opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		ctx = opentracing.ContextWithSpan(ctx, span1)
+	}
 	if recordSets, err = s.execute(ctx, sql); err != nil {
 		s.sessionVars.StmtCtx.AppendError(err)
 	}
@@ -600,6 +632,26 @@ func (s *session) execute(ctx context.Context, sql string) (recordSets []sqlexec
 		if err := executor.ResetContextOfStmt(s, stmtNode); err != nil {
 			return nil, err
 		}
+		ctx := ctx
+		if !s.sessionVars.InRestrictedSQL {
+			// Every log line of the statement carries its digest.
+			ctx = logutil.WithFields(ctx, zap.String("digest", s.sessionVars.StmtCtx.SQLDigest()))
+		}
+		if batchStmt, ok := stmtNode.(*ast.BatchDMLStmt); ok {
+			// The batches are compiled and executed one by one as separate statements.
+			if err := s.executeBatchDML(ctx, batchStmt); err != nil {
+				return nil, err
+			}
+			if multiQuery {
+				recordSets = append(recordSets, &multiQueryNoDelayRecordSet{
+					affectedRows: s.AffectedRows(),
+					warnCount:    s.sessionVars.StmtCtx.WarningCount(),
+					lastInsertID: s.sessionVars.StmtCtx.LastInsertID,
+					status:       s.sessionVars.Status,
+				})
+			}
+			continue
+		}
 		stmt, err := compiler.Compile(ctx, stmtNode)
 		if err != nil {
 			s.rollbackOnError(ctx)
@@ -652,6 +704,7 @@ func (s *session) Txn(active bool) (kv.Transaction, error) {
 			return &s.txn, err
 		}
 		s.sessionVars.TxnCtx.StartTS = s.txn.StartTS()
+		s.updateTxnInfo()
 		if !s.sessionVars.IsAutocommit() {
 			s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, true)
 		}
@@ -662,6 +715,24 @@ func (s *session) Txn(active bool) (kv.Transaction, error) {
 	return &s.txn, nil
 }
 
+// updateTxnInfo updates the info of the valid transaction in txninfo.GlobalRegistry, so the others can see
+// which session it belongs to, the statement it's running and its mutations.
+func (s *session) updateTxnInfo() {
+	if s.txn.info == nil {
+		return
+	}
+	vars := s.sessionVars
+	txninfo.GlobalRegistry.Update(s.txn.info, func(info *txninfo.TxnInfo) {
+		info.ConnectionID = vars.ConnectionID
+		if vars.User != nil {
+			info.Username = vars.User.Username
+		}
+		info.CurrentDB = vars.CurrentDB
+		info.CurrentSQL = s.stmtText
+		info.Len, info.Size = s.txn.Len(), s.txn.Size()
+	})
+}
+
 func (s *session) NewTxn(ctx context.Context) error {
 	if s.txn.Valid() {
 		txnID := s.txn.StartTS()
@@ -685,6 +756,7 @@ func (s *session) NewTxn(ctx context.Context) error {
 		txn.SetOption(kv.ReplicaRead, kv.ReplicaReadFollower)
 	}
 	s.txn.changeInvalidToValid(txn)
+	s.updateTxnInfo()
 	is := domain.GetDomain(s).InfoSchema()
 	s.sessionVars.TxnCtx = &variable.TransactionContext{
 		InfoSchema:    is,
@@ -759,6 +831,12 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 	}
 
 	dom := domain.GetDomain(se)
+	err = dom.LoadGlobalVarsLoop(func() error {
+		return reloadGlobalVariables(se)
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	se1, err := createSession(store)
 	if err != nil {
@@ -769,6 +847,24 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 		return nil, err
 	}
 
+	se2, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	dom.EvolvePlanBaselinesLoop(func() error {
+		return executor.EvolvePlanBaselinesIfEnabled(context.Background(), se2)
+	})
+
+	se3, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	// The expired rows are deleted by the internal SQLs, which are allowed in the read-only mode.
+	se3.sessionVars.InRestrictedSQL = true
+	dom.TTLJobLoop(func() error {
+		return executor.RunTTLJobsIfEnabled(context.Background(), se3)
+	})
+
 	return dom, err
 }
 
@@ -911,11 +1007,18 @@ var builtinGlobalVariable = []string{
 	variable.CollationServer,
 	variable.NetWriteTimeout,
 	variable.MaxExecutionTime,
+	variable.SQLSafeUpdates,
 	variable.InnodbLockWaitTimeout,
+	variable.ReadOnly,
+	variable.SuperReadOnly,
 
 	/* TiDB specific global variables: */
 	variable.TiDBSkipUTF8Check,
 	variable.TiDBIndexLookupSize,
+	variable.TiDBEnablePaging,
+	variable.TiDBEnableChunkRPC,
+	variable.TiDBMinPagingSize,
+	variable.TiDBMaxPagingSize,
 	variable.TiDBIndexLookupConcurrency,
 	variable.TiDBIndexLookupJoinConcurrency,
 	variable.TiDBIndexSerialScanConcurrency,
@@ -930,6 +1033,7 @@ var builtinGlobalVariable = []string{
 	variable.TiDBDDLReorgBatchSize,
 	variable.TiDBDDLErrorCountLimit,
 	variable.TiDBOptInSubqToJoinAndAgg,
+	variable.TiDBOptEnableLateMaterialization,
 	variable.TiDBOptCorrelationThreshold,
 	variable.TiDBOptCorrelationExpFactor,
 	variable.TiDBOptCPUFactor,
@@ -940,13 +1044,24 @@ var builtinGlobalVariable = []string{
 	variable.TiDBOptMemoryFactor,
 	variable.TiDBOptDiskFactor,
 	variable.TiDBOptConcurrencyFactor,
+	variable.TiDBOptRangeMaxCount,
 	variable.TiDBDistSQLScanConcurrency,
 	variable.TiDBInitChunkSize,
 	variable.TiDBMaxChunkSize,
 	variable.TiDBEnableCascadesPlanner,
+	variable.TiDBEnableNonPreparedPlanCache,
+	variable.TiDBNonPreparedPlanCacheSize,
+	variable.TiDBEnableResultCache,
+	variable.TiDBResultCacheTSBucket,
+	variable.TiDBCapturePlanBaselines,
+	variable.TiDBUsePlanBaselines,
+	variable.TiDBRecordWorkload,
 	variable.TiDBEnableVectorizedExpression,
 	variable.TiDBEnableNoopFuncs,
+	variable.TiDBEnableRowChecksum,
+	variable.TiDBVerifyRowChecksum,
 	variable.TiDBMaxDeltaSchemaCount,
+	variable.TiDBExecutorConcurrencyLimit,
 }
 
 var (
@@ -961,6 +1076,20 @@ func initLoadCommonGlobalVarsSQL() {
 	})
 }
 
+// reloadGlobalVariables loads the commonly used global variables into the global
+// variable cache, and applies the ones which are cached in the server scope.
+func reloadGlobalVariables(se *session) error {
+	rows, fields, err := se.ExecRestrictedSQL(loadCommonGlobalVarsSQL)
+	if err != nil {
+		return err
+	}
+	domain.GetDomain(se).GetGlobalVarsCache().Update(rows, fields)
+	for _, row := range rows {
+		variable.SetLocalSystemVar(row.GetString(0), row.GetString(1))
+	}
+	return nil
+}
+
 // loadCommonGlobalVariablesIfNeeded loads and applies commonly used global variables for the session.
 func (s *session) loadCommonGlobalVariablesIfNeeded() error {
 	initLoadCommonGlobalVarsSQL()
@@ -1061,6 +1190,7 @@ func (s *session) InitTxnWithStartTS(startTS uint64) error {
 	}
 	s.txn.changeInvalidToValid(txn)
 	s.txn.SetCap(s.getMembufCap())
+	s.updateTxnInfo()
 	err = s.loadCommonGlobalVariablesIfNeeded()
 	if err != nil {
 		return err
@@ -16,13 +16,17 @@ package session_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -33,9 +37,12 @@ import (
 	"github.com/pingcap/tidb/store/mockstore/mocktikv"
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/testkit"
 	"github.com/pingcap/tidb/util/testleak"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 var _ = Suite(&testSessionSuite{})
@@ -106,6 +113,58 @@ func (s *testSessionSuite) TestQueryString(c *C) {
 	c.Assert(queryStr, Equals, "create table multi2 (a int)")
 }
 
+func (s *testSessionSuite) TestTracingSpans(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("create table trace_t (a int primary key, b int)")
+	tk.MustExec("insert into trace_t values (1, 1), (2, 2)")
+
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("test")
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+	rss, err := tk.Se.Execute(ctx, "select b from trace_t where a > 0")
+	c.Assert(err, IsNil)
+	rows, err := session.GetRows4Test(ctx, tk.Se, rss[0])
+	c.Assert(err, IsNil)
+	c.Assert(rows, HasLen, 2)
+	c.Assert(rss[0].Close(), IsNil)
+	span.Finish()
+
+	ops := make(map[string]bool)
+	var rpcCount int
+	for _, sp := range tracer.FinishedSpans() {
+		ops[sp.OperationName] = true
+		if strings.HasPrefix(sp.OperationName, "regionRequest.sendReqToRegion") {
+			rpcCount++
+		}
+	}
+	for _, op := range []string{"session.Execute", "session.ParseSQL", "executor.Compile", "planner.Optimize",
+		"*executor.TableReaderExecutor.Open", "*executor.TableReaderExecutor.Next", "*executor.TableReaderExecutor.Close"} {
+		c.Assert(ops[op], IsTrue, Commentf("missing span %s", op))
+	}
+	c.Assert(rpcCount, Greater, 0)
+}
+
+func (s *testSessionSuite) TestStatementLogFields(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("create table log_t (a int primary key)")
+
+	tk.MustExec("begin")
+	tk.MustExec("insert into log_t values (1)")
+	core, logs := observer.New(zap.WarnLevel)
+	ctx := logutil.WithLogger(context.Background(), zap.New(core))
+	sql := "insert into log_t values ('abc')"
+	_, err := tk.Se.Execute(ctx, sql)
+	c.Assert(err, NotNil)
+	defer tk.MustExec("rollback")
+
+	entries := logs.FilterMessage("run statement failed").AllUntimed()
+	c.Assert(entries, HasLen, 1)
+	fields := entries[0].ContextMap()
+	c.Assert(fields["digest"], Equals, parser.DigestHash(sql))
+	c.Assert(fields["txnStartTS"], Equals, tk.Se.GetSessionVars().TxnCtx.StartTS)
+	c.Assert(fields["txnStartTS"], Not(Equals), uint64(0))
+}
+
 // TestAutocommit . See https://dev.mysql.com/doc/internals/en/status-flags.html
 func (s *testSessionSuite) TestAutocommit(c *C) {
 	tk := testkit.NewTestKitWithInit(c, s.store)
@@ -467,6 +526,52 @@ func (s *testSessionSuite2) TestDeletePanic(c *C) {
 	tk.MustExec("delete from `t` where `c` = 2")
 }
 
+func (s *testSessionSuite2) TestBatchDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, v int, key idx_id(id))")
+	tk.MustExec("insert into t values (null, 1), (null, 2), (1, 1), (1, 2), (1, 3), (2, 1), (3, 0), (3, 1), (4, 1), (5, 1)")
+
+	// The rows with the same shard values are in the same job, so are the NULLs. The jobs are
+	// NULL, [1, 1], [2, 3] and [4, 5].
+	tk.MustExec("batch on id limit 2 delete from t where v > 0")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(9))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 split into 4 batch DML jobs"))
+	tk.MustQuery("select * from t").Check(testkit.Rows("3 0"))
+
+	tk.MustExec("insert into t values (6, 1), (7, 1), (8, 1)")
+	tk.MustExec("batch on w.id limit 100 delete from t as w")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(4))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 split into 1 batch DML jobs"))
+	tk.MustQuery("select * from t").Check(testkit.Rows())
+
+	tk.MustExec("batch on id limit 10 delete from t")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(0))
+	tk.MustQuery("show warnings").Check(testkit.Rows("Note 1105 split into 0 batch DML jobs"))
+
+	tk.MustGetErrCode("batch on id limit 0 delete from t", mysql.ErrBatchDMLUnsupported)
+	tk.MustGetErrCode("batch on id limit 10 delete from t order by id", mysql.ErrBatchDMLUnsupported)
+	tk.MustGetErrCode("batch on id limit 10 delete from t limit 1", mysql.ErrBatchDMLUnsupported)
+	tk.MustGetErrCode("batch on c limit 10 delete from t", mysql.ErrBadField)
+	tk.MustExec("begin")
+	tk.MustGetErrCode("batch on id limit 10 delete from t", mysql.ErrBatchDMLUnsupported)
+	tk.MustExec("rollback")
+	tk.MustExec("set autocommit = 0")
+	tk.MustGetErrCode("batch on id limit 10 delete from t", mysql.ErrBatchDMLUnsupported)
+	tk.MustExec("set autocommit = 1")
+
+	// The statements splitting the jobs have no text, they must not share the cached results.
+	tk.MustExec("set @@tidb_enable_result_cache = 1")
+	tk.MustExec("set @@tidb_result_cache_ts_bucket = 3600000")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("batch on id limit 1 delete from t where v > 10")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(0))
+	tk.MustExec("batch on id limit 1 delete from t where v > 0")
+	c.Assert(tk.Se.AffectedRows(), Equals, uint64(3))
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("0"))
+	tk.MustExec("set @@tidb_enable_result_cache = 0")
+}
+
 var _ = Suite(&testSchemaSuite{})
 var _ = SerialSuites(&testSchemaSerialSuite{})
 
@@ -809,3 +914,25 @@ func (s *testSessionSuite2) TestReplicaRead(c *C) {
 	tk.MustExec("set @@tidb_replica_read = 'leader';")
 	c.Assert(tk.Se.GetSessionVars().GetReplicaRead(), Equals, kv.ReplicaReadLeader)
 }
+
+func (s *testSessionSerialSuite) TestGlobalVarsPropagation(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	defer func() {
+		tk.MustExec(fmt.Sprintf("set @@global.tidb_distsql_scan_concurrency = %d", variable.DefDistSQLScanConcurrency))
+		tk.MustExec(fmt.Sprintf("set @@global.tidb_max_delta_schema_count = %d", variable.DefTiDBMaxDeltaSchemaCount))
+	}()
+
+	// The change is visible to new sessions at once, though the global variable cache
+	// has just been filled when creating tk.
+	tk.MustExec("set @@global.tidb_distsql_scan_concurrency = 7")
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	tk1.MustQuery("select @@tidb_distsql_scan_concurrency").Check(testkit.Rows("7"))
+	tk.MustQuery("select @@tidb_distsql_scan_concurrency").Check(testkit.Rows(fmt.Sprint(variable.DefDistSQLScanConcurrency)))
+
+	// The variable cached in the server scope is applied by the reload loop.
+	tk.MustExec("set @@global.tidb_max_delta_schema_count = 2048")
+	for i := 0; i < 100 && variable.GetMaxDeltaSchemaCount() != 2048; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(variable.GetMaxDeltaSchemaCount(), Equals, int64(2048))
+}
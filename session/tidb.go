@@ -32,6 +32,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -168,7 +169,7 @@ func finishStmt(ctx context.Context, sctx sessionctx.Context, se *session, sessV
 	meetsErr error, sql sqlexec.Statement) error {
 	if meetsErr != nil {
 		if !sessVars.InTxn() {
-			logutil.BgLogger().Info("rollbackTxn for ddl/autocommit failed")
+			logutil.Logger(ctx).Info("rollbackTxn for ddl/autocommit failed")
 			se.RollbackTxn(ctx)
 		}
 		return meetsErr
@@ -216,6 +217,10 @@ func runStmt(ctx context.Context, sctx sessionctx.Context, s sqlexec.Statement)
 	if err != nil {
 		return nil, err
 	}
+	// The KV requests sent for the statement report their retries as its warnings.
+	ctx = kv.WithBackoffReporter(ctx, backoffWarningReporter(sessVars.StmtCtx))
+	se.stmtText = s.OriginText()
+	se.updateTxnInfo()
 	rs, err = s.Exec(ctx)
 	sessVars.TxnCtx.StatementCount++
 	if !s.IsReadOnly() {
@@ -229,9 +234,14 @@ func runStmt(ctx context.Context, sctx sessionctx.Context, s sqlexec.Statement)
 				}
 			}
 		} else {
-			logutil.BgLogger().Error("get txn failed", zap.Error(err1))
+			logutil.Logger(ctx).Error("get txn failed", zap.Error(err1))
 		}
 	}
+	// The statement which returns a record set is still running until the record set is drained.
+	if rs == nil {
+		se.stmtText = ""
+	}
+	se.updateTxnInfo()
 	err = finishStmt(ctx, sctx, se, sessVars, err, s)
 
 	if se.txn.pending() {
@@ -248,6 +258,14 @@ func runStmt(ctx context.Context, sctx sessionctx.Context, s sqlexec.Statement)
 	return rs, err
 }
 
+// backoffWarningReporter returns a kv.BackoffReporter which appends the backoffs
+// to sc as warnings, so the latency of the statement can be explained by them.
+func backoffWarningReporter(sc *stmtctx.StatementContext) kv.BackoffReporter {
+	return func(typ string, attempts int, sleepMS int, err error) {
+		sc.AppendWarning(kv.ErrRetryAfterBackoff.FastGenByArgs(typ, attempts, sleepMS, err))
+	}
+}
+
 // GetHistory get all stmtHistory in current txn. Exported only for test.
 func GetHistory(ctx sessionctx.Context) *StmtHistory {
 	hist, ok := ctx.GetSessionVars().TxnCtx.History.(*StmtHistory)
@@ -316,12 +334,16 @@ func ResultSetToStringSlice(ctx context.Context, s Session, rs sqlexec.RecordSet
 
 // Session errors.
 var (
-	ErrForUpdateCantRetry = terror.ClassSession.New(mysql.ErrForUpdateCantRetry, mysql.MySQLErrName[mysql.ErrForUpdateCantRetry])
+	ErrForUpdateCantRetry  = terror.ClassSession.New(mysql.ErrForUpdateCantRetry, mysql.MySQLErrName[mysql.ErrForUpdateCantRetry])
+	ErrBatchDMLUnsupported = terror.ClassSession.New(mysql.ErrBatchDMLUnsupported, mysql.MySQLErrName[mysql.ErrBatchDMLUnsupported])
+	ErrBatchDMLJobFailed   = terror.ClassSession.New(mysql.ErrBatchDMLJobFailed, mysql.MySQLErrName[mysql.ErrBatchDMLJobFailed])
 )
 
 func init() {
 	sessionMySQLErrCodes := map[terror.ErrCode]uint16{
-		mysql.ErrForUpdateCantRetry: mysql.ErrForUpdateCantRetry,
+		mysql.ErrForUpdateCantRetry:  mysql.ErrForUpdateCantRetry,
+		mysql.ErrBatchDMLUnsupported: mysql.ErrBatchDMLUnsupported,
+		mysql.ErrBatchDMLJobFailed:   mysql.ErrBatchDMLJobFailed,
 	}
 	terror.ErrClassToMySQLCodes[terror.ClassSession] = sessionMySQLErrCodes
 }
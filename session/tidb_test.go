@@ -14,6 +14,8 @@
 package session
 
 import (
+	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/logutil"
@@ -107,3 +110,16 @@ func (s *testMainSuite) TestKeysNeedLock(c *C) {
 		c.Assert(keyNeedToLock(tt.key, tt.val), Equals, tt.need)
 	}
 }
+
+func (s *testMainSuite) TestBackoffWarningReporter(c *C) {
+	sc := &stmtctx.StatementContext{}
+	ctx := kv.WithBackoffReporter(context.Background(), backoffWarningReporter(sc))
+	kv.ReportBackoff(ctx, "regionMiss", 1, 2, errors.New("epoch not match"))
+	kv.ReportBackoff(ctx, "regionMiss", 2, 4, errors.New("epoch not match"))
+	kv.ReportBackoff(context.Background(), "txnLock", 1, 100, errors.New("lock"))
+	warnings := sc.GetWarnings()
+	c.Assert(warnings, HasLen, 2)
+	c.Assert(kv.ErrRetryAfterBackoff.Equal(warnings[0].Err), IsTrue)
+	c.Assert(warnings[0].Err.Error(), Equals, "[kv:1105]KV request is retried after regionMiss backoff #1 of 2ms: epoch not match")
+	c.Assert(warnings[1].Err.Error(), Equals, "[kv:1105]KV request is retried after regionMiss backoff #2 of 4ms: epoch not match")
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/txninfo"
 	"go.uber.org/zap"
 )
 
@@ -49,6 +50,9 @@ type TxnState struct {
 	// If doNotCommit is not nil, Commit() will not commit the transaction.
 	// doNotCommit flag may be set when StmtCommit fail.
 	doNotCommit error
+
+	// info is the info of the transaction in txninfo.GlobalRegistry while it's valid.
+	info *txninfo.TxnInfo
 }
 
 func (st *TxnState) init() {
@@ -104,6 +108,7 @@ func (st *TxnState) GoString() string {
 func (st *TxnState) changeInvalidToValid(txn kv.Transaction) {
 	st.Transaction = txn
 	st.txnFuture = nil
+	st.info = txninfo.GlobalRegistry.Register(txn.StartTS())
 }
 
 func (st *TxnState) changeInvalidToPending(future *txnFuture) {
@@ -126,12 +131,17 @@ func (st *TxnState) changePendingToValid(txnCap int) error {
 	}
 	txn.SetCap(txnCap)
 	st.Transaction = txn
+	st.info = txninfo.GlobalRegistry.Register(txn.StartTS())
 	return nil
 }
 
 func (st *TxnState) changeToInvalid() {
 	st.Transaction = nil
 	st.txnFuture = nil
+	if st.info != nil {
+		txninfo.GlobalRegistry.Unregister(st.info)
+		st.info = nil
+	}
 }
 
 // dirtyTableOperation represents an operation to dirtyTable, we log the operation
@@ -17,9 +17,15 @@ import (
 	"math"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/pingcap/tidb/bindinfo"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/tracing"
 	"go.uber.org/zap"
 )
 
@@ -32,6 +38,9 @@ const (
 	WarnLevelNote = "Note"
 )
 
+// ErrMemExceedThreshold is returned when the memory used by a statement exceeds its quota.
+var ErrMemExceedThreshold = terror.ClassExecutor.New(mysql.ErrMemExceedThreshold, mysql.MySQLErrName[mysql.ErrMemExceedThreshold])
+
 // SQLWarn relates a sql warning and it's level.
 type SQLWarn struct {
 	Level string
@@ -54,6 +63,7 @@ type StatementContext struct {
 	IgnoreTruncate         bool
 	IgnoreZeroInDate       bool
 	BadNullAsWarning       bool
+	DupKeyAsWarning        bool
 	DividedByZeroAsWarning bool
 	TruncateAsWarning      bool
 	OverflowAsWarning      bool
@@ -67,6 +77,8 @@ type StatementContext struct {
 	// then cast the float string to int string. Otherwise, we cast string to integer
 	// prefix in a strict way, only extract 0-9 and (+ or - in first bit).
 	CastStrToIntStrict bool
+	// VerifyRowChecksum indicates whether to verify the checksums stored in the rows read by the statement.
+	VerifyRowChecksum bool
 
 	// mu struct holds variables that change during execution.
 	mu struct {
@@ -116,6 +128,34 @@ type StatementContext struct {
 	nowTs          time.Time // use this variable for now/current_timestamp calculation/cache for one stmt
 	stmtTimeCached bool
 	StmtType       string
+
+	// HypoIndexes are the hypothetical indexes of the tables, keyed by the table IDs. The planner
+	// considers them as if they existed, so the plans using them can be estimated but not executed.
+	HypoIndexes map[int64][]*model.IndexInfo
+
+	// PlanBaseline is the baseline whose access paths are forced when the statement is planned.
+	PlanBaseline *bindinfo.Baseline
+
+	// OptimTracer records the decisions of the optimizer, it's only set when the statement is explained
+	// WITH OPTIMIZER TRACE.
+	OptimTracer *tracing.OptimizeTracer
+
+	// ReadFromTableCache indicates whether the statement read a cached table from its cache.
+	ReadFromTableCache bool
+
+	// memUsage is the bytes of memory held by the operators of the statement. Only the operators
+	// which may hold data proportional to the input, like the distinct aggregate functions, report it.
+	memUsage int64
+	// spilledBytes is the bytes of data spilled to the disk by the operators of the statement.
+	spilledBytes int64
+
+	// OriginalSQL is the text of the statement.
+	OriginalSQL string
+	// sqlDigest is the digest of OriginalSQL, it's computed on the first use.
+	sqlDigest struct {
+		sync.Once
+		digest string
+	}
 }
 
 // StmtHints are SessionVars related sql hints.
@@ -124,11 +164,21 @@ type StmtHints struct {
 	HasAllowInSubqToJoinAndAggHint bool
 	HasMemQuotaHint                bool
 	HasReplicaReadHint             bool
+	HasMaxExecutionTime            bool
 
 	// Hint Information
 	AllowInSubqToJoinAndAgg bool
 	MemQuotaQuery           int64
 	ReplicaRead             byte
+	MaxExecutionTime        uint64
+}
+
+// SQLDigest returns the digest of the statement text, it's computed at most once for a statement.
+func (sc *StatementContext) SQLDigest() string {
+	sc.sqlDigest.Do(func() {
+		sc.sqlDigest.digest = parser.DigestHash(sc.OriginalSQL)
+	})
+	return sc.sqlDigest.digest
 }
 
 // GetNowTsCached getter for nowTs, if not set get now time and cache it
@@ -277,6 +327,25 @@ func (sc *StatementContext) SetWarnings(warns []SQLWarn) {
 	sc.mu.Unlock()
 }
 
+// TruncateWarnings truncates the warnings to the first start ones and returns the truncated warnings.
+// It's used to drop the warnings produced by an evaluation whose result is discarded.
+func (sc *StatementContext) TruncateWarnings(start int) []SQLWarn {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if start < 0 || start >= len(sc.mu.warnings) {
+		return nil
+	}
+	truncated := make([]SQLWarn, len(sc.mu.warnings)-start)
+	copy(truncated, sc.mu.warnings[start:])
+	sc.mu.warnings = sc.mu.warnings[:start]
+	for _, w := range truncated {
+		if w.Level == WarnLevelError {
+			sc.mu.errorCount--
+		}
+	}
+	return truncated
+}
+
 // AppendWarning appends a warning with level 'Warning'.
 func (sc *StatementContext) AppendWarning(warn error) {
 	sc.mu.Lock()
@@ -335,6 +404,31 @@ func (sc *StatementContext) HandleOverflow(err error, warnErr error) error {
 	return err
 }
 
+// ConsumeMemory adds bytes to the memory usage of the statement, and returns ErrMemExceedThreshold
+// if the usage exceeds the MEMORY_QUOTA of the statement. The label names the holder of the memory.
+func (sc *StatementContext) ConsumeMemory(label string, bytes int64) error {
+	usage := atomic.AddInt64(&sc.memUsage, bytes)
+	if sc.MemQuotaQuery > 0 && usage > sc.MemQuotaQuery {
+		return ErrMemExceedThreshold.GenWithStackByArgs(label, usage, sc.MemQuotaQuery, "")
+	}
+	return nil
+}
+
+// MemoryUsage returns the bytes of memory held by the operators of the statement.
+func (sc *StatementContext) MemoryUsage() int64 {
+	return atomic.LoadInt64(&sc.memUsage)
+}
+
+// AddSpilledBytes adds bytes to the data spilled to the disk by the operators of the statement.
+func (sc *StatementContext) AddSpilledBytes(bytes int64) {
+	atomic.AddInt64(&sc.spilledBytes, bytes)
+}
+
+// SpilledBytes returns the bytes of data spilled to the disk by the operators of the statement.
+func (sc *StatementContext) SpilledBytes() int64 {
+	return atomic.LoadInt64(&sc.spilledBytes)
+}
+
 // ResetForRetry resets the changed states during execution.
 func (sc *StatementContext) ResetForRetry() {
 	sc.mu.Lock()
@@ -398,6 +492,9 @@ func (sc *StatementContext) PushDownFlags() uint64 {
 	if sc.PadCharToFullLength {
 		flags |= model.FlagPadCharToFullLength
 	}
+	if sc.VerifyRowChecksum {
+		flags |= model.FlagVerifyRowChecksum
+	}
 	return flags
 }
 
@@ -411,6 +508,7 @@ func (sc *StatementContext) SetFlagsFromPBFlag(flags uint64) {
 	sc.OverflowAsWarning = (flags & model.FlagOverflowAsWarning) > 0
 	sc.IgnoreZeroInDate = (flags & model.FlagIgnoreZeroInDate) > 0
 	sc.DividedByZeroAsWarning = (flags & model.FlagDividedByZeroAsWarning) > 0
+	sc.VerifyRowChecksum = (flags & model.FlagVerifyRowChecksum) > 0
 }
 
 //CopTasksDetails collects some useful information of cop-tasks during execution.
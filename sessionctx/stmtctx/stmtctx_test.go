@@ -14,7 +14,10 @@
 package stmtctx_test
 
 import (
+	"errors"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"testing"
 )
@@ -49,3 +52,41 @@ func (s *stmtctxSuit) TestStatementContextPushDownFLags(c *C) {
 		c.Assert(got, Equals, tt.out, Commentf("get %v, want %v", got, tt.out))
 	}
 }
+
+func (s *stmtctxSuit) TestConsumeMemory(c *C) {
+	sc := &stmtctx.StatementContext{}
+	c.Assert(sc.ConsumeMemory("test", 100), IsNil)
+	c.Assert(sc.MemoryUsage(), Equals, int64(100))
+
+	sc = &stmtctx.StatementContext{}
+	sc.MemQuotaQuery = 150
+	c.Assert(sc.ConsumeMemory("test", 100), IsNil)
+	err := sc.ConsumeMemory("test", 100)
+	c.Assert(stmtctx.ErrMemExceedThreshold.Equal(err), IsTrue)
+	c.Assert(sc.MemoryUsage(), Equals, int64(200))
+}
+
+func (s *stmtctxSuit) TestTruncateWarnings(c *C) {
+	sc := &stmtctx.StatementContext{}
+	sc.AppendWarning(errors.New("w1"))
+	sc.AppendError(errors.New("e1"))
+	sc.AppendNote(errors.New("n1"))
+	c.Assert(sc.TruncateWarnings(3), IsNil)
+	truncated := sc.TruncateWarnings(1)
+	c.Assert(truncated, HasLen, 2)
+	c.Assert(truncated[0].Level, Equals, stmtctx.WarnLevelError)
+	c.Assert(truncated[1].Level, Equals, stmtctx.WarnLevelNote)
+	ec, wc := sc.NumErrorWarnings()
+	c.Assert(ec, Equals, uint16(0))
+	c.Assert(wc, Equals, 1)
+	c.Assert(sc.GetWarnings()[0].Err.Error(), Equals, "w1")
+}
+
+func (s *stmtctxSuit) TestSQLDigest(c *C) {
+	sql := "select * from t where a = 1"
+	sc := &stmtctx.StatementContext{OriginalSQL: sql}
+	c.Assert(sc.SQLDigest(), Equals, parser.DigestHash(sql))
+	// The digest is computed once, it doesn't follow the later changes of the text.
+	sc.OriginalSQL = "select 1"
+	c.Assert(sc.SQLDigest(), Equals, parser.DigestHash(sql))
+}
@@ -25,11 +25,13 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
@@ -39,6 +41,14 @@ var (
 	ErrSnapshotTooOld = terror.ClassVariable.New(mysql.ErrSnapshotTooOld, mysql.MySQLErrName[mysql.ErrSnapshotTooOld])
 )
 
+// OptimizerTraceInfo is the optimizer trace of an explained statement.
+type OptimizerTraceInfo struct {
+	// Query is the text of the explained statement.
+	Query string
+	// Trace is the JSON encoded trace.
+	Trace string
+}
+
 // TransactionContext is used to store variables that has transaction scope.
 type TransactionContext struct {
 	forUpdateTS   uint64
@@ -49,6 +59,9 @@ type TransactionContext struct {
 	StartTS       uint64
 	Shard         *int64
 	TableDeltaMap map[int64]TableDelta
+	// CachedTables records the write lock leases of the cached tables written in the transaction,
+	// keyed by the table IDs. The transaction must commit before the leases.
+	CachedTables map[int64]uint64
 
 	CreateTime     time.Time
 	StatementCount int
@@ -131,7 +144,10 @@ type SessionVars struct {
 	// UsersLock is a lock for user defined variables.
 	UsersLock sync.RWMutex
 	// Users are user defined variables.
-	Users map[string]string
+	Users map[string]types.Datum
+	// UserVarTypes are the types of the user defined variables, which decide the types of the
+	// expressions reading them.
+	UserVarTypes map[string]*types.FieldType
 	// systems variables, don't modify it directly, use GetSystemVar/SetSystemVar method.
 	systems map[string]string
 	// SysWarningCount is the system variable "warning_count", because it is on the hot path, so we extract it from the systems
@@ -172,6 +188,9 @@ type SessionVars struct {
 	// PlanColumnID is the unique id for column when building plan.
 	PlanColumnID int64
 
+	// User is the user identity with which the session login.
+	User *auth.UserIdentity
+
 	// CurrentDB is the default database of this session.
 	CurrentDB string
 
@@ -190,6 +209,9 @@ type SessionVars struct {
 	// LastFoundRows is the number of found rows of last query statement
 	LastFoundRows uint64
 
+	// LastOptimizerTrace is the optimizer trace of the last statement explained WITH OPTIMIZER TRACE.
+	LastOptimizerTrace *OptimizerTraceInfo
+
 	// StmtCtx holds variables for current executing statement.
 	StmtCtx *stmtctx.StatementContext
 
@@ -200,6 +222,15 @@ type SessionVars struct {
 	// This variable is currently not recommended to be turned on.
 	AllowWriteRowID bool
 
+	// EnableLateMaterialization enables the late materialization of the table scans with selective filters.
+	EnableLateMaterialization bool
+
+	// EnablePaging indicates whether the coprocessor requests return the results in pages.
+	EnablePaging bool
+
+	// EnableChunkRPC indicates whether the coprocessor responses are encoded in the chunk format.
+	EnableChunkRPC bool
+
 	// CorrelationThreshold is the guard to enable row count estimation using column order correlation.
 	CorrelationThreshold float64
 
@@ -247,6 +278,51 @@ type SessionVars struct {
 	// EnableCascadesPlanner enables the cascades planner.
 	EnableCascadesPlanner bool
 
+	// EnableNonPreparedPlanCache indicates whether to cache the plans of simple ad-hoc queries.
+	EnableNonPreparedPlanCache bool
+
+	// NonPreparedPlanCacheSize is the max number of plans kept by NonPreparedPlanCache.
+	NonPreparedPlanCacheSize int
+
+	// NonPreparedPlanCache caches the plans of simple ad-hoc queries, it's created by the planner on demand.
+	NonPreparedPlanCache *kvcache.SimpleLRUCache
+
+	// FoundInPlanCache indicates whether the plan of the current statement comes from the plan cache.
+	FoundInPlanCache bool
+
+	// PrevFoundInPlanCache indicates whether the plan of the last statement comes from the plan cache.
+	PrevFoundInPlanCache bool
+
+	// EnableResultCache indicates whether to cache the results of the read-only SELECT statements.
+	EnableResultCache bool
+
+	// ResultCacheTSBucket is the width in milliseconds of the read timestamp buckets of the result cache.
+	ResultCacheTSBucket int64
+
+	// FoundInResultCache indicates whether the result of the current statement comes from the result cache.
+	FoundInResultCache bool
+
+	// PrevFoundInResultCache indicates whether the result of the last statement comes from the result cache.
+	PrevFoundInResultCache bool
+
+	// CapturePlanBaselines indicates whether to capture the plans of the repeated statements as their baselines.
+	CapturePlanBaselines bool
+
+	// UsePlanBaselines indicates whether to plan the statements with their plan baselines.
+	UsePlanBaselines bool
+
+	// FoundInPlanBaseline indicates whether the plan of the current statement comes from its plan baseline.
+	FoundInPlanBaseline bool
+
+	// PrevFoundInPlanBaseline indicates whether the plan of the last statement comes from its plan baseline.
+	PrevFoundInPlanBaseline bool
+
+	// RecordWorkload indicates whether to record the SELECT statements for the index advisor.
+	RecordWorkload bool
+
+	// VerifyRowChecksum indicates whether to verify the checksums stored in the rows read.
+	VerifyRowChecksum bool
+
 	// EnableVectorizedExpression  enables the vectorized expression evaluation.
 	EnableVectorizedExpression bool
 
@@ -275,6 +351,9 @@ type SessionVars struct {
 	// to use the greedy join reorder algorithm.
 	TiDBOptJoinReorderThreshold int
 
+	// RangeMaxCount is the max number of the index ranges built from the equal and in conditions, 0 means no limit.
+	RangeMaxCount int
+
 	// SlowQueryFile indicates which slow query log file for SLOW_QUERY table to parse.
 	SlowQueryFile string
 
@@ -283,6 +362,10 @@ type SessionVars struct {
 	// See https://dev.mysql.com/doc/refman/5.7/en/server-system-variables.html#sysvar_max_execution_time
 	MaxExecutionTime uint64
 
+	// SQLSafeUpdates rejects the DELETE statements that neither use a key in the WHERE clause nor have a LIMIT.
+	// See https://dev.mysql.com/doc/refman/5.7/en/server-system-variables.html#sysvar_sql_safe_updates
+	SQLSafeUpdates bool
+
 	// Killed is a flag to indicate that this query is killed.
 	Killed uint32
 
@@ -315,7 +398,8 @@ type SessionVars struct {
 	// replicaRead is used for reading data from replicas, only follower is supported at this time.
 	replicaRead kv.ReplicaReadType
 
-	// RowEncoder is reused in session for encode row data.
+	// RowEncoder is reused in session for encode row data, its Checksum indicates whether to store the
+	// checksum in the rows written.
 	RowEncoder rowcodec.Encoder
 }
 
@@ -342,7 +426,8 @@ type ConnectionInfo struct {
 // NewSessionVars creates a session vars object.
 func NewSessionVars() *SessionVars {
 	vars := &SessionVars{
-		Users:                       make(map[string]string),
+		Users:                       make(map[string]types.Datum),
+		UserVarTypes:                make(map[string]*types.FieldType),
 		systems:                     make(map[string]string),
 		TxnCtx:                      &TransactionContext{},
 		KVVars:                      kv.NewVariables(),
@@ -367,11 +452,18 @@ func NewSessionVars() *SessionVars {
 		EnableVectorizedExpression:  DefEnableVectorizedExpression,
 		CommandValue:                uint32(mysql.ComSleep),
 		TiDBOptJoinReorderThreshold: DefTiDBOptJoinReorderThreshold,
+		RangeMaxCount:               DefTiDBOptRangeMaxCount,
 		WaitSplitRegionFinish:       DefTiDBWaitSplitRegionFinish,
 		WaitSplitRegionTimeout:      DefWaitSplitRegionTimeout,
 		EnableNoopFuncs:             DefTiDBEnableNoopFuncs,
 		replicaRead:                 kv.ReplicaReadLeader,
 		AllowRemoveAutoInc:          DefTiDBAllowRemoveAutoInc,
+		NonPreparedPlanCacheSize:    DefTiDBNonPreparedPlanCacheSize,
+		ResultCacheTSBucket:         DefTiDBResultCacheTSBucket,
+		CapturePlanBaselines:        DefTiDBCapturePlanBaselines,
+		UsePlanBaselines:            DefTiDBUsePlanBaselines,
+		RecordWorkload:              DefTiDBRecordWorkload,
+		EnableChunkRPC:              DefTiDBEnableChunkRPC,
 	}
 	vars.Concurrency = Concurrency{
 		IndexLookupConcurrency:     DefIndexLookupConcurrency,
@@ -387,6 +479,8 @@ func NewSessionVars() *SessionVars {
 		IndexLookupSize: DefIndexLookupSize,
 		InitChunkSize:   DefInitChunkSize,
 		MaxChunkSize:    DefMaxChunkSize,
+		MinPagingSize:   DefMinPagingSize,
+		MaxPagingSize:   DefMaxPagingSize,
 	}
 	return vars
 }
@@ -494,6 +588,36 @@ func (s *SessionVars) Location() *time.Location {
 	return loc
 }
 
+// SetUserVar sets the value and the type of a user defined variable, the variable is removed if
+// the value is NULL.
+func (s *SessionVars) SetUserVar(name string, d types.Datum, tp *types.FieldType) {
+	s.UsersLock.Lock()
+	defer s.UsersLock.Unlock()
+	if d.IsNull() {
+		delete(s.Users, name)
+		delete(s.UserVarTypes, name)
+		return
+	}
+	s.Users[name] = d
+	s.UserVarTypes[name] = tp
+}
+
+// GetUserVar gets the value of a user defined variable.
+func (s *SessionVars) GetUserVar(name string) (types.Datum, bool) {
+	s.UsersLock.RLock()
+	defer s.UsersLock.RUnlock()
+	d, ok := s.Users[name]
+	return d, ok
+}
+
+// GetUserVarType gets the type of a user defined variable.
+func (s *SessionVars) GetUserVarType(name string) (*types.FieldType, bool) {
+	s.UsersLock.RLock()
+	defer s.UsersLock.RUnlock()
+	tp, ok := s.UserVarTypes[name]
+	return tp, ok
+}
+
 // GetSystemVar gets the string value of a system variable.
 func (s *SessionVars) GetSystemVar(name string) (string, bool) {
 	if name == WarningCount {
@@ -562,10 +686,14 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 	case MaxExecutionTime:
 		timeoutMS := tidbOptPositiveInt32(val, 0)
 		s.MaxExecutionTime = uint64(timeoutMS)
+	case SQLSafeUpdates:
+		s.SQLSafeUpdates = TiDBOptOn(val)
 	case TiDBSkipUTF8Check:
 		s.SkipUTF8Check = TiDBOptOn(val)
 	case TiDBOptAggPushDown:
 		s.AllowAggPushDown = TiDBOptOn(val)
+	case TiDBOptEnableLateMaterialization:
+		s.EnableLateMaterialization = TiDBOptOn(val)
 	case TiDBOptWriteRowID:
 		s.AllowWriteRowID = TiDBOptOn(val)
 	case TiDBOptInSubqToJoinAndAgg:
@@ -598,6 +726,14 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.IndexLookupJoinConcurrency = tidbOptPositiveInt32(val, DefIndexLookupJoinConcurrency)
 	case TiDBIndexLookupSize:
 		s.IndexLookupSize = tidbOptPositiveInt32(val, DefIndexLookupSize)
+	case TiDBEnablePaging:
+		s.EnablePaging = TiDBOptOn(val)
+	case TiDBEnableChunkRPC:
+		s.EnableChunkRPC = TiDBOptOn(val)
+	case TiDBMinPagingSize:
+		s.MinPagingSize = tidbOptPositiveInt32(val, DefMinPagingSize)
+	case TiDBMaxPagingSize:
+		s.MaxPagingSize = tidbOptPositiveInt32(val, DefMaxPagingSize)
 	case TiDBHashJoinConcurrency:
 		s.HashJoinConcurrency = tidbOptPositiveInt32(val, DefTiDBHashJoinConcurrency)
 	case TiDBProjectionConcurrency:
@@ -616,7 +752,7 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.KVVars.BackOffWeight = tidbOptPositiveInt32(val, kv.DefBackOffWeight)
 	case TiDBConstraintCheckInPlace:
 		s.ConstraintCheckInPlace = TiDBOptOn(val)
-	case TiDBCurrentTS, TiDBConfig:
+	case TiDBCurrentTS, TiDBConfig, TiDBFoundInPlanCache, TiDBFoundInResultCache, TiDBFoundInPlanBaseline:
 		return ErrReadOnly
 	case TiDBMaxChunkSize:
 		s.MaxChunkSize = tidbOptPositiveInt32(val, DefMaxChunkSize)
@@ -626,6 +762,29 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		atomic.StoreUint32(&ProcessGeneralLog, uint32(tidbOptPositiveInt32(val, DefTiDBGeneralLog)))
 	case TiDBEnableCascadesPlanner:
 		s.EnableCascadesPlanner = TiDBOptOn(val)
+	case TiDBEnableNonPreparedPlanCache:
+		s.EnableNonPreparedPlanCache = TiDBOptOn(val)
+		if !s.EnableNonPreparedPlanCache {
+			s.NonPreparedPlanCache = nil
+		}
+	case TiDBNonPreparedPlanCacheSize:
+		s.NonPreparedPlanCacheSize = int(tidbOptPositiveInt32(val, DefTiDBNonPreparedPlanCacheSize))
+		// The cache is recreated with the new size when it's used next time.
+		s.NonPreparedPlanCache = nil
+	case TiDBEnableResultCache:
+		s.EnableResultCache = TiDBOptOn(val)
+	case TiDBResultCacheTSBucket:
+		s.ResultCacheTSBucket = tidbOptInt64(val, DefTiDBResultCacheTSBucket)
+	case TiDBCapturePlanBaselines:
+		s.CapturePlanBaselines = TiDBOptOn(val)
+	case TiDBUsePlanBaselines:
+		s.UsePlanBaselines = TiDBOptOn(val)
+	case TiDBRecordWorkload:
+		s.RecordWorkload = TiDBOptOn(val)
+	case TiDBEnableRowChecksum:
+		s.RowEncoder.Checksum = TiDBOptOn(val)
+	case TiDBVerifyRowChecksum:
+		s.VerifyRowChecksum = TiDBOptOn(val)
 	case TiDBDDLReorgPriority:
 		s.setDDLReorgPriority(val)
 	case TiDBEnableRadixJoin:
@@ -634,6 +793,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 		s.EnableVectorizedExpression = TiDBOptOn(val)
 	case TiDBOptJoinReorderThreshold:
 		s.TiDBOptJoinReorderThreshold = tidbOptPositiveInt32(val, DefTiDBOptJoinReorderThreshold)
+	case TiDBOptRangeMaxCount:
+		s.RangeMaxCount = int(tidbOptInt64(val, DefTiDBOptRangeMaxCount))
 	case TiDBSlowQueryFile:
 		s.SlowQueryFile = val
 	case TiDBWaitSplitRegionFinish:
@@ -653,6 +814,8 @@ func (s *SessionVars) SetSystemVar(name string, val string) error {
 	// It's a global variable, but it also wants to be cached in server.
 	case TiDBMaxDeltaSchemaCount:
 		SetMaxDeltaSchemaCount(tidbOptInt64(val, DefTiDBMaxDeltaSchemaCount))
+	case TiDBExecutorConcurrencyLimit:
+		SetExecConcurrencyLimit(tidbOptInt64(val, DefTiDBExecConcurrencyLimit))
 	}
 	s.systems[name] = val
 	return nil
@@ -667,6 +830,14 @@ func SetLocalSystemVar(name string, val string) {
 		SetDDLReorgBatchSize(int32(tidbOptPositiveInt32(val, DefTiDBDDLReorgBatchSize)))
 	case TiDBDDLErrorCountLimit:
 		SetDDLErrorCountLimit(tidbOptInt64(val, DefTiDBDDLErrorCountLimit))
+	case TiDBMaxDeltaSchemaCount:
+		SetMaxDeltaSchemaCount(tidbOptInt64(val, DefTiDBMaxDeltaSchemaCount))
+	case TiDBExecutorConcurrencyLimit:
+		SetExecConcurrencyLimit(tidbOptInt64(val, DefTiDBExecConcurrencyLimit))
+	case ReadOnly:
+		SetReadOnly(TiDBOptOn(val))
+	case SuperReadOnly:
+		SetSuperReadOnly(TiDBOptOn(val))
 	}
 }
 
@@ -682,12 +853,6 @@ const (
 	MaxExecutionTime     = "max_execution_time"
 )
 
-// these variables are useless for TiDB, but still need to validate their values for some compatible issues.
-// TODO: some more variables need to be added here.
-const (
-	serverReadOnly = "read_only"
-)
-
 var (
 	// TxIsolationNames are the valid values of the variable "tx_isolation" or "transaction_isolation".
 	TxIsolationNames = map[string]struct{}{
@@ -744,4 +909,10 @@ type BatchSize struct {
 
 	// MaxChunkSize defines max row count of a Chunk during query execution.
 	MaxChunkSize int
+
+	// MinPagingSize defines the max row count of the first page of a coprocessor request.
+	MinPagingSize int
+
+	// MaxPagingSize defines the max row count of a page of a coprocessor request.
+	MaxPagingSize int
 }
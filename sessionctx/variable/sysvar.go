@@ -426,7 +426,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, LogBinTrustFunctionCreators, "0"},
 	{ScopeNone, "innodb_write_io_threads", "4"},
 	{ScopeGlobal, "mysql_native_password_proxy_users", ""},
-	{ScopeGlobal, serverReadOnly, "0"},
+	{ScopeGlobal, ReadOnly, "0"},
 	{ScopeNone, "large_page_size", "0"},
 	{ScopeNone, "table_open_cache_instances", "1"},
 	{ScopeGlobal, InnodbStatsPersistent, "1"},
@@ -630,6 +630,7 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, TiDBBuildStatsConcurrency, strconv.Itoa(DefBuildStatsConcurrency)},
 	{ScopeGlobal | ScopeSession, TiDBDistSQLScanConcurrency, strconv.Itoa(DefDistSQLScanConcurrency)},
 	{ScopeGlobal | ScopeSession, TiDBOptInSubqToJoinAndAgg, BoolToIntStr(DefOptInSubqToJoinAndAgg)},
+	{ScopeGlobal | ScopeSession, TiDBOptEnableLateMaterialization, BoolToIntStr(DefOptEnableLateMaterialization)},
 	{ScopeGlobal | ScopeSession, TiDBOptCorrelationThreshold, strconv.FormatFloat(DefOptCorrelationThreshold, 'f', -1, 64)},
 	{ScopeGlobal | ScopeSession, TiDBOptCorrelationExpFactor, strconv.Itoa(DefOptCorrelationExpFactor)},
 	{ScopeGlobal | ScopeSession, TiDBOptCPUFactor, strconv.FormatFloat(DefOptCPUFactor, 'f', -1, 64)},
@@ -642,6 +643,10 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, TiDBOptDiskFactor, strconv.FormatFloat(DefOptDiskFactor, 'f', -1, 64)},
 	{ScopeGlobal | ScopeSession, TiDBOptConcurrencyFactor, strconv.FormatFloat(DefOptConcurrencyFactor, 'f', -1, 64)},
 	{ScopeGlobal | ScopeSession, TiDBIndexLookupSize, strconv.Itoa(DefIndexLookupSize)},
+	{ScopeGlobal | ScopeSession, TiDBEnablePaging, BoolToIntStr(DefTiDBEnablePaging)},
+	{ScopeGlobal | ScopeSession, TiDBEnableChunkRPC, BoolToIntStr(DefTiDBEnableChunkRPC)},
+	{ScopeGlobal | ScopeSession, TiDBMinPagingSize, strconv.Itoa(DefMinPagingSize)},
+	{ScopeGlobal | ScopeSession, TiDBMaxPagingSize, strconv.Itoa(DefMaxPagingSize)},
 	{ScopeGlobal | ScopeSession, TiDBIndexLookupConcurrency, strconv.Itoa(DefIndexLookupConcurrency)},
 	{ScopeGlobal | ScopeSession, TiDBIndexLookupJoinConcurrency, strconv.Itoa(DefIndexLookupJoinConcurrency)},
 	{ScopeGlobal | ScopeSession, TiDBIndexSerialScanConcurrency, strconv.Itoa(DefIndexSerialScanConcurrency)},
@@ -660,6 +665,22 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal | ScopeSession, TiDBConstraintCheckInPlace, BoolToIntStr(DefTiDBConstraintCheckInPlace)},
 	{ScopeGlobal | ScopeSession, TiDBEnableVectorizedExpression, BoolToIntStr(DefEnableVectorizedExpression)},
 	{ScopeGlobal | ScopeSession, TiDBSkipIsolationLevelCheck, BoolToIntStr(DefTiDBSkipIsolationLevelCheck)},
+	{ScopeGlobal | ScopeSession, TiDBEnableNonPreparedPlanCache, BoolToIntStr(DefTiDBNonPreparedPlanCache)},
+	{ScopeGlobal | ScopeSession, TiDBNonPreparedPlanCacheSize, strconv.Itoa(DefTiDBNonPreparedPlanCacheSize)},
+	{ScopeSession, TiDBFoundInPlanCache, BoolToIntStr(false)},
+	{ScopeGlobal | ScopeSession, TiDBEnableResultCache, BoolToIntStr(DefTiDBEnableResultCache)},
+	{ScopeGlobal | ScopeSession, TiDBResultCacheTSBucket, strconv.Itoa(DefTiDBResultCacheTSBucket)},
+	{ScopeSession, TiDBFoundInResultCache, BoolToIntStr(false)},
+	{ScopeGlobal | ScopeSession, TiDBCapturePlanBaselines, BoolToIntStr(DefTiDBCapturePlanBaselines)},
+	{ScopeGlobal | ScopeSession, TiDBUsePlanBaselines, BoolToIntStr(DefTiDBUsePlanBaselines)},
+	{ScopeGlobal, TiDBEvolvePlanBaselines, BoolToIntStr(DefTiDBEvolvePlanBaselines)},
+	{ScopeSession, TiDBFoundInPlanBaseline, BoolToIntStr(false)},
+	{ScopeGlobal | ScopeSession, TiDBRecordWorkload, BoolToIntStr(DefTiDBRecordWorkload)},
+	{ScopeGlobal | ScopeSession, TiDBEnableRowChecksum, BoolToIntStr(DefTiDBEnableRowChecksum)},
+	{ScopeGlobal | ScopeSession, TiDBVerifyRowChecksum, BoolToIntStr(DefTiDBVerifyRowChecksum)},
+	{ScopeGlobal, TiDBTTLJobEnable, BoolToIntStr(DefTiDBTTLJobEnable)},
+	{ScopeGlobal, TiDBTTLDeleteBatchSize, strconv.Itoa(DefTiDBTTLDeleteBatchSize)},
+	{ScopeGlobal, TiDBTTLDeleteRateLimit, strconv.Itoa(DefTiDBTTLDeleteRateLimit)},
 	/* The following variable is defined as session scope but is actually server scope. */
 	{ScopeSession, TiDBGeneralLog, strconv.Itoa(DefTiDBGeneralLog)},
 	{ScopeSession, TiDBConfig, ""},
@@ -668,8 +689,10 @@ var defaultSysVars = []*SysVar{
 	{ScopeGlobal, TiDBDDLErrorCountLimit, strconv.Itoa(DefTiDBDDLErrorCountLimit)},
 	{ScopeSession, TiDBDDLReorgPriority, "PRIORITY_LOW"},
 	{ScopeGlobal, TiDBMaxDeltaSchemaCount, strconv.Itoa(DefTiDBMaxDeltaSchemaCount)},
+	{ScopeGlobal, TiDBExecutorConcurrencyLimit, strconv.Itoa(DefTiDBExecConcurrencyLimit)},
 	{ScopeSession, TiDBEnableRadixJoin, BoolToIntStr(DefTiDBUseRadixJoin)},
 	{ScopeGlobal | ScopeSession, TiDBOptJoinReorderThreshold, strconv.Itoa(DefTiDBOptJoinReorderThreshold)},
+	{ScopeGlobal | ScopeSession, TiDBOptRangeMaxCount, strconv.Itoa(DefTiDBOptRangeMaxCount)},
 	{ScopeSession, TiDBSlowQueryFile, ""},
 	{ScopeGlobal, TiDBScatterRegion, BoolToIntStr(DefTiDBScatterRegion)},
 	{ScopeSession, TiDBWaitSplitRegionFinish, BoolToIntStr(DefTiDBWaitSplitRegionFinish)},
@@ -805,6 +828,8 @@ const (
 	MasterVerifyChecksum = "master_verify_checksum"
 	// ValidatePasswordCheckUserName is the name for 'validate_password_check_user_name' system variable.
 	ValidatePasswordCheckUserName = "validate_password_check_user_name"
+	// ReadOnly is the name for 'read_only' system variable.
+	ReadOnly = "read_only"
 	// SuperReadOnly is the name for 'super_read_only' system variable.
 	SuperReadOnly = "super_read_only"
 	// SQLNotes is the name for 'sql_notes' system variable.
@@ -43,6 +43,10 @@ const (
 	// tidb_opt_write_row_id is used to enable/disable the operations of insert、replace and update to _tidb_rowid.
 	TiDBOptWriteRowID = "tidb_opt_write_row_id"
 
+	// tidb_opt_enable_late_materialization is used to enable/disable the late materialization of table scans, which
+	// scans the columns used by the selective filters first and reads the other columns only for the qualified rows.
+	TiDBOptEnableLateMaterialization = "tidb_opt_enable_late_materialization"
+
 	// TiDBCurrentTS is used to get the current transaction timestamp.
 	// It is read-only.
 	TiDBCurrentTS = "tidb_current_ts"
@@ -111,6 +115,24 @@ const (
 	// Large value may do more work than needed if the query has a limit.
 	TiDBIndexLookupSize = "tidb_index_lookup_size"
 
+	// tidb_enable_paging is used to enable/disable the paging of coprocessor requests.
+	// With paging enabled, the coprocessor returns the results of a scan in pages. The first page has
+	// 'tidb_min_paging_size' rows at most, and the page size doubles for every following page until it
+	// reaches 'tidb_max_paging_size', so the queries with a small limit stop early and the big scans
+	// don't need to buffer the whole results in the store.
+	TiDBEnablePaging = "tidb_enable_paging"
+
+	// tidb_enable_chunk_rpc is used to enable/disable the chunk encoding of the coprocessor responses.
+	// With the chunk encoding, the rows are returned column by column in the chunk format, so they are
+	// decoded into the chunks without decoding the values one by one.
+	TiDBEnableChunkRPC = "tidb_enable_chunk_rpc"
+
+	// tidb_min_paging_size is the max number of rows scanned for the first page of a coprocessor request.
+	TiDBMinPagingSize = "tidb_min_paging_size"
+
+	// tidb_max_paging_size is the max number of rows scanned for a page of a coprocessor request.
+	TiDBMaxPagingSize = "tidb_max_paging_size"
+
 	// tidb_index_lookup_concurrency is used for index lookup executor.
 	// A lookup task may have 'tidb_index_lookup_size' of handles at maximun, the handles may be distributed
 	// in many TiKV nodes, we executes multiple concurrent index lookup tasks concurrently to reduce the time
@@ -182,6 +204,10 @@ const (
 	// deltaSchemaInfos is a queue that maintains the history of schema changes.
 	TiDBMaxDeltaSchemaCount = "tidb_max_delta_schema_count"
 
+	// tidb_executor_concurrency_limit is the max number of the worker goroutines of the executors
+	// of all the running statements in the server, 0 means unlimited.
+	TiDBExecutorConcurrencyLimit = "tidb_executor_concurrency_limit"
+
 	// tidb_scatter_region will scatter the regions for DDLs when it is ON.
 	TiDBScatterRegion = "tidb_scatter_region"
 
@@ -206,11 +232,73 @@ const (
 	// we'll choose a rather time consuming algorithm to calculate the join order.
 	TiDBOptJoinReorderThreshold = "tidb_opt_join_reorder_threshold"
 
+	// tidb_opt_range_max_count is the max number of the index ranges built from the cartesian product of the
+	// equal and in conditions on the index columns, 0 means no limit. The conditions on the index columns that
+	// would exceed it are used as filters instead.
+	TiDBOptRangeMaxCount = "tidb_opt_range_max_count"
+
 	// SlowQueryFile indicates which slow query log file for SLOW_QUERY table to parse.
 	TiDBSlowQueryFile = "tidb_slow_query_file"
 
 	// TiDBEnableNoopFuncs set true will enable using fake funcs(like get_lock release_lock)
 	TiDBEnableNoopFuncs = "tidb_enable_noop_functions"
+
+	// tidb_enable_non_prepared_plan_cache indicates whether to cache the plans of simple ad-hoc queries,
+	// whose literals are turned into parameters so that the plan can be reused by queries of the same shape.
+	TiDBEnableNonPreparedPlanCache = "tidb_enable_non_prepared_plan_cache"
+
+	// tidb_non_prepared_plan_cache_size is the max number of plans kept by the non-prepared plan cache of a session.
+	TiDBNonPreparedPlanCacheSize = "tidb_non_prepared_plan_cache_size"
+
+	// TiDBFoundInPlanCache indicates whether the plan of the last statement came from the plan cache.
+	TiDBFoundInPlanCache = "last_plan_from_cache"
+
+	// tidb_enable_result_cache indicates whether to cache the results of the read-only SELECT statements,
+	// the cached results are reused by the same statements until the tables read by them are modified.
+	TiDBEnableResultCache = "tidb_enable_result_cache"
+
+	// tidb_result_cache_ts_bucket is the width in milliseconds of the buckets of the read timestamps,
+	// a cached result is only reused by the statements whose read timestamps are in the same bucket.
+	TiDBResultCacheTSBucket = "tidb_result_cache_ts_bucket"
+
+	// TiDBFoundInResultCache indicates whether the result of the last statement came from the result cache.
+	TiDBFoundInResultCache = "last_result_from_cache"
+
+	// tidb_capture_plan_baselines indicates whether to capture the plans of the SELECT statements executed
+	// repeatedly as their plan baselines.
+	TiDBCapturePlanBaselines = "tidb_capture_plan_baselines"
+
+	// tidb_use_plan_baselines indicates whether to plan the statements with their plan baselines.
+	TiDBUsePlanBaselines = "tidb_use_plan_baselines"
+
+	// tidb_evolve_plan_baselines indicates whether to evolve the plan baselines in the background, the
+	// plans chosen by the optimizer are tested and replace the baselines if they are faster.
+	TiDBEvolvePlanBaselines = "tidb_evolve_plan_baselines"
+
+	// TiDBFoundInPlanBaseline indicates whether the plan of the last statement came from its plan baseline.
+	TiDBFoundInPlanBaseline = "last_plan_from_baseline"
+
+	// tidb_record_workload indicates whether to record the SELECT statements executed as the workload
+	// analyzed by the index advisor.
+	TiDBRecordWorkload = "tidb_record_workload"
+
+	// tidb_enable_row_checksum indicates whether to store a checksum in the rows written, so that the
+	// corrupted rows can be detected when they're read.
+	TiDBEnableRowChecksum = "tidb_enable_row_checksum"
+
+	// tidb_verify_row_checksum indicates whether to verify the checksums stored in the rows read,
+	// the rows without checksum aren't verified.
+	TiDBVerifyRowChecksum = "tidb_verify_row_checksum"
+
+	// tidb_ttl_job_enable indicates whether to delete the expired rows of the TTL tables in the background.
+	TiDBTTLJobEnable = "tidb_ttl_job_enable"
+
+	// tidb_ttl_delete_batch_size is the number of the expired rows deleted by a TTL delete statement.
+	TiDBTTLDeleteBatchSize = "tidb_ttl_delete_batch_size"
+
+	// tidb_ttl_delete_rate_limit is the max number of the TTL delete statements executed per second
+	// on a TiDB instance, 0 means no limit.
+	TiDBTTLDeleteRateLimit = "tidb_ttl_delete_rate_limit"
 )
 
 // Default TiDB system variable values.
@@ -221,10 +309,15 @@ const (
 	DefIndexSerialScanConcurrency    = 1
 	DefIndexLookupSize               = 20000
 	DefDistSQLScanConcurrency        = 15
+	DefTiDBEnablePaging              = false
+	DefTiDBEnableChunkRPC            = true
+	DefMinPagingSize                 = 128
+	DefMaxPagingSize                 = 50 * 1024
 	DefBuildStatsConcurrency         = 4
 	DefSkipUTF8Check                 = false
 	DefOptAggPushDown                = false
 	DefOptWriteRowID                 = false
+	DefOptEnableLateMaterialization  = false
 	DefOptCorrelationThreshold       = 0.9
 	DefOptCorrelationExpFactor       = 1
 	DefOptCPUFactor                  = 3.0
@@ -252,18 +345,33 @@ const (
 	DefTiDBDDLReorgBatchSize         = 256
 	DefTiDBDDLErrorCountLimit        = 512
 	DefTiDBMaxDeltaSchemaCount       = 1024
+	DefTiDBExecConcurrencyLimit      = 0
 	DefTiDBHashAggPartialConcurrency = 4
 	DefTiDBHashAggFinalConcurrency   = 4
 	DefTiDBUseRadixJoin              = false
 	DefEnableVectorizedExpression    = true
 	DefTiDBOptJoinReorderThreshold   = 0
+	DefTiDBOptRangeMaxCount          = 10000
 	DefTiDBSkipIsolationLevelCheck   = false
 	DefTiDBScatterRegion             = false
 	DefTiDBWaitSplitRegionFinish     = true
 	DefWaitSplitRegionTimeout        = 300 // 300s
 	DefTiDBEnableNoopFuncs           = false
 	DefTiDBAllowRemoveAutoInc        = false
+	DefTiDBNonPreparedPlanCache      = false
+	DefTiDBNonPreparedPlanCacheSize  = 100
+	DefTiDBEnableResultCache         = false
+	DefTiDBResultCacheTSBucket       = 1000
+	DefTiDBCapturePlanBaselines      = false
+	DefTiDBUsePlanBaselines          = true
+	DefTiDBEvolvePlanBaselines       = false
+	DefTiDBRecordWorkload            = true
 	DefInnodbLockWaitTimeout         = 50 // 50s
+	DefTiDBEnableRowChecksum         = false
+	DefTiDBVerifyRowChecksum         = false
+	DefTiDBTTLJobEnable              = true
+	DefTiDBTTLDeleteBatchSize        = 100
+	DefTiDBTTLDeleteRateLimit        = 0
 )
 
 // Process global variables.
@@ -274,6 +382,9 @@ var (
 	ddlReorgBatchSize      int32 = DefTiDBDDLReorgBatchSize
 	ddlErrorCountlimit     int64 = DefTiDBDDLErrorCountLimit
 	maxDeltaSchemaCount    int64 = DefTiDBMaxDeltaSchemaCount
+	execConcurrencyLimit   int64 = DefTiDBExecConcurrencyLimit
+	readOnly               int32
+	superReadOnly          int32
 	// Export for testing.
 	MaxDDLReorgBatchSize  int32  = 10240
 	MinDDLReorgBatchSize  int32  = 32
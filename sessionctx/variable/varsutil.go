@@ -81,6 +81,36 @@ func GetMaxDeltaSchemaCount() int64 {
 	return atomic.LoadInt64(&maxDeltaSchemaCount)
 }
 
+// SetExecConcurrencyLimit sets the max number of the executor workers in the server.
+func SetExecConcurrencyLimit(limit int64) {
+	atomic.StoreInt64(&execConcurrencyLimit, limit)
+}
+
+// GetExecConcurrencyLimit gets the max number of the executor workers in the server.
+func GetExecConcurrencyLimit() int64 {
+	return atomic.LoadInt64(&execConcurrencyLimit)
+}
+
+// SetReadOnly sets whether the server rejects the writes of the users without the SUPER privilege.
+func SetReadOnly(on bool) {
+	atomic.StoreInt32(&readOnly, BoolToInt32(on))
+}
+
+// GetReadOnly gets whether the server rejects the writes of the users without the SUPER privilege.
+func GetReadOnly() bool {
+	return atomic.LoadInt32(&readOnly) == 1
+}
+
+// SetSuperReadOnly sets whether the server rejects the writes of all the users.
+func SetSuperReadOnly(on bool) {
+	atomic.StoreInt32(&superReadOnly, BoolToInt32(on))
+}
+
+// GetSuperReadOnly gets whether the server rejects the writes of all the users.
+func GetSuperReadOnly() bool {
+	return atomic.LoadInt32(&superReadOnly) == 1
+}
+
 // GetSessionSystemVar gets a system variable.
 // If it is a session only variable, use the default value defined in code.
 // Returns error if there is no such variable.
@@ -109,6 +139,12 @@ func GetSessionOnlySysVars(s *SessionVars, key string) (string, bool, error) {
 	switch sysVar.Name {
 	case TiDBCurrentTS:
 		return fmt.Sprintf("%d", s.TxnCtx.StartTS), true, nil
+	case TiDBFoundInPlanCache:
+		return BoolToIntStr(s.PrevFoundInPlanCache), true, nil
+	case TiDBFoundInResultCache:
+		return BoolToIntStr(s.PrevFoundInResultCache), true, nil
+	case TiDBFoundInPlanBaseline:
+		return BoolToIntStr(s.PrevFoundInPlanBaseline), true, nil
 	case TiDBGeneralLog:
 		return fmt.Sprintf("%d", atomic.LoadUint32(&ProcessGeneralLog)), true, nil
 	case TiDBConfig:
@@ -314,6 +350,12 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return checkUInt64SystemVar(name, value, 0, 2, vars)
 	case TiDBMaxDeltaSchemaCount:
 		return checkInt64SystemVar(name, value, 100, 16384, vars)
+	case TiDBExecutorConcurrencyLimit:
+		return checkInt64SystemVar(name, value, 0, math.MaxInt32, vars)
+	case TiDBResultCacheTSBucket:
+		return checkInt64SystemVar(name, value, 1, math.MaxInt32, vars)
+	case TiDBOptRangeMaxCount:
+		return checkInt64SystemVar(name, value, 0, math.MaxInt32, vars)
 	case SessionTrackGtids:
 		if strings.EqualFold(value, "OFF") || value == "0" {
 			return "OFF", nil
@@ -362,13 +404,15 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return "1", nil
 		}
 		return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
-	case TiDBSkipUTF8Check, TiDBOptAggPushDown, TiDBOptInSubqToJoinAndAgg,
-		TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs,
+	case TiDBSkipUTF8Check, TiDBOptAggPushDown, TiDBOptInSubqToJoinAndAgg, TiDBOptEnableLateMaterialization, TiDBEnablePaging,
+		TiDBEnableChunkRPC, TiDBEnableCascadesPlanner, TiDBEnableNoopFuncs, TiDBEnableNonPreparedPlanCache, TiDBEnableResultCache,
+		TiDBCapturePlanBaselines, TiDBUsePlanBaselines, TiDBEvolvePlanBaselines, TiDBRecordWorkload, TiDBTTLJobEnable,
+		TiDBEnableRowChecksum, TiDBVerifyRowChecksum,
 		TiDBScatterRegion, TiDBGeneralLog, TiDBConstraintCheckInPlace, TiDBEnableVectorizedExpression:
 		fallthrough
 	case GeneralLog, AvoidTemporalUpgrade, BigTables, CheckProxyUsers, LogBin,
 		CoreFile, EndMakersInJSON, SQLLogBin, OfflineMode, PseudoSlaveMode, LowPriorityUpdates,
-		SkipNameResolve, SQLSafeUpdates, serverReadOnly, SlaveAllowBatching,
+		SkipNameResolve, SQLSafeUpdates, ReadOnly, SlaveAllowBatching,
 		Flush, PerformanceSchema, LocalInFile, ShowOldTemporals, KeepFilesOnCreate, AutoCommit,
 		SQLWarnings, UniqueChecks, OldAlterTable, LogBinTrustFunctionCreators, SQLBigSelects,
 		BinlogDirectNonTransactionalUpdates, SQLQuoteShowCreate, AutomaticSpPrivileges,
@@ -415,8 +459,12 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 		return checkUInt64SystemVar(name, value, uint64(MinDDLReorgBatchSize), uint64(MaxDDLReorgBatchSize), vars)
 	case TiDBDDLErrorCountLimit:
 		return checkUInt64SystemVar(name, value, uint64(0), math.MaxInt64, vars)
+	case TiDBTTLDeleteBatchSize:
+		return checkUInt64SystemVar(name, value, 1, 10240, vars)
+	case TiDBTTLDeleteRateLimit:
+		return checkUInt64SystemVar(name, value, 0, math.MaxInt64, vars)
 	case TiDBIndexLookupConcurrency, TiDBIndexLookupJoinConcurrency,
-		TiDBIndexLookupSize,
+		TiDBIndexLookupSize, TiDBMinPagingSize, TiDBMaxPagingSize,
 		TiDBHashJoinConcurrency,
 		TiDBHashAggPartialConcurrency,
 		TiDBHashAggFinalConcurrency,
@@ -509,6 +557,15 @@ func ValidateSetSystemVar(vars *SessionVars, name string, value string) (string,
 			return value, errors.Errorf("tidb_init_chunk_size(%d) cannot be bigger than %d", v, initChunkSizeUpperBound)
 		}
 		return value, nil
+	case TiDBNonPreparedPlanCacheSize:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return value, ErrWrongTypeForVar.GenWithStackByArgs(name)
+		}
+		if v <= 0 {
+			return value, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+		return value, nil
 	case TiDBMaxChunkSize:
 		v, err := strconv.Atoi(value)
 		if err != nil {
@@ -0,0 +1,71 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statistics
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// AnalyzeJob is the progress of an analyze task, it's updated by the analyze worker as the
+// responses of the regions are merged.
+type AnalyzeJob struct {
+	DBName    string
+	TableName string
+	// JobInfo describes what is analyzed, e.g. "analyze index idx" or "analyze columns".
+	JobInfo string
+	// TotalRows is the estimated number of the rows to scan, 0 means it's unknown.
+	TotalRows int64
+	StartTime time.Time
+
+	processedRows int64
+}
+
+// Update adds the number of the rows which have been scanned.
+func (job *AnalyzeJob) Update(rowCount int64) {
+	atomic.AddInt64(&job.processedRows, rowCount)
+}
+
+// ProcessedRows returns the number of the rows which have been scanned.
+func (job *AnalyzeJob) ProcessedRows() int64 {
+	return atomic.LoadInt64(&job.processedRows)
+}
+
+// AddAnalyzeJob starts the analyze job and registers it as running.
+func (h *Handle) AddAnalyzeJob(job *AnalyzeJob) {
+	job.StartTime = time.Now()
+	h.analyzeJobs.Lock()
+	h.analyzeJobs.jobs[job] = struct{}{}
+	h.analyzeJobs.Unlock()
+}
+
+// RemoveAnalyzeJob unregisters the analyze job once it's finished.
+func (h *Handle) RemoveAnalyzeJob(job *AnalyzeJob) {
+	h.analyzeJobs.Lock()
+	delete(h.analyzeJobs.jobs, job)
+	h.analyzeJobs.Unlock()
+}
+
+// AnalyzeJobs returns the running analyze jobs ordered by the start time.
+func (h *Handle) AnalyzeJobs() []*AnalyzeJob {
+	h.analyzeJobs.Lock()
+	jobs := make([]*AnalyzeJob, 0, len(h.analyzeJobs.jobs))
+	for job := range h.analyzeJobs.jobs {
+		jobs = append(jobs, job)
+	}
+	h.analyzeJobs.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].StartTime.Before(jobs[j].StartTime) })
+	return jobs
+}
@@ -64,6 +64,12 @@ type Handle struct {
 	restrictedExec sqlexec.RestrictedSQLExecutor
 
 	lease atomic2.Duration
+
+	// analyzeJobs are the analyze tasks running on this server.
+	analyzeJobs struct {
+		sync.Mutex
+		jobs map[*AnalyzeJob]struct{}
+	}
 }
 
 // Clear the statsCache, only for test.
@@ -86,6 +92,7 @@ func NewHandle(ctx sessionctx.Context, lease time.Duration) *Handle {
 	}
 	handle.mu.ctx = ctx
 	handle.statsCache.Store(statsCache{tables: make(map[int64]*Table)})
+	handle.analyzeJobs.jobs = make(map[*AnalyzeJob]struct{})
 	return handle
 }
 
@@ -160,6 +167,14 @@ func (h *Handle) Update(is infoschema.InfoSchema) error {
 	return nil
 }
 
+// Reload drops all the cached statistics and reads them from storage again.
+func (h *Handle) Reload(is infoschema.InfoSchema) error {
+	h.statsCache.Lock()
+	h.statsCache.Store(statsCache{tables: make(map[int64]*Table)})
+	h.statsCache.Unlock()
+	return h.Update(is)
+}
+
 func getFullTableName(is infoschema.InfoSchema, tblInfo *model.TableInfo) string {
 	for _, schema := range is.AllSchemas() {
 		if t, err := is.TableByName(schema.Name, tblInfo.Name); err == nil {
@@ -72,6 +72,33 @@ func (s *testStatsSuite) TestStatsCache(c *C) {
 	c.Assert(statsTbl.Pseudo, IsFalse)
 }
 
+func (s *testStatsSuite) TestStatsCacheReload(c *C) {
+	defer cleanEnv(c, s.store, s.do)
+	testKit := testkit.NewTestKit(c, s.store)
+	testKit.MustExec("use test")
+	testKit.MustExec("create table t (c1 int, c2 int)")
+	testKit.MustExec("insert into t values(1, 2), (3, 4)")
+	testKit.MustExec("analyze table t")
+	do := s.do
+	is := do.InfoSchema()
+	tbl, err := is.TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	c.Assert(err, IsNil)
+	tableInfo := tbl.Meta()
+	statsTbl := do.StatsHandle().GetTableStats(tableInfo)
+	c.Assert(statsTbl.Pseudo, IsFalse)
+	c.Assert(statsTbl.Count, Equals, int64(2))
+
+	// Reload drops the cached stats and reads them from storage again.
+	c.Assert(do.StatsHandle().Reload(is), IsNil)
+	newStatsTbl := do.StatsHandle().GetTableStats(tableInfo)
+	c.Assert(newStatsTbl, Not(Equals), statsTbl)
+	assertTableEqual(c, newStatsTbl, statsTbl)
+
+	testKit.MustExec("admin reload stats")
+	c.Assert(do.StatsHandle().GetTableStats(tableInfo), Not(Equals), newStatsTbl)
+	assertTableEqual(c, do.StatsHandle().GetTableStats(tableInfo), statsTbl)
+}
+
 func assertTableEqual(c *C, a *statistics.Table, b *statistics.Table) {
 	c.Assert(a.Count, Equals, b.Count)
 	c.Assert(a.ModifyCount, Equals, b.ModifyCount)
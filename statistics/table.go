@@ -242,6 +242,34 @@ func (coll *HistColl) GetRowCountByIndexRanges(sc *stmtctx.StatementContext, idx
 	return result, errors.Trace(err)
 }
 
+// GetRowCountByEncodedIndexRange estimates the row count of the index whose encoded values are in [low, high),
+// an empty low or high means the range is unbounded on that side. The boundaries are usually the rest of the
+// index keys after the index prefix, e.g. the boundaries of the regions.
+func (coll *HistColl) GetRowCountByEncodedIndexRange(idxID int64, low, high []byte) float64 {
+	idx := coll.Indices[idxID]
+	if idx == nil || idx.IsInvalid(coll.Pseudo) {
+		switch {
+		case len(low) == 0 && len(high) == 0:
+			return float64(coll.Count)
+		case len(low) == 0 || len(high) == 0:
+			return float64(coll.Count) / pseudoLessRate
+		default:
+			return float64(coll.Count) / pseudoBetweenRate
+		}
+	}
+	// The null values are encoded as the smallest keys, so they are only in the ranges unbounded on the left.
+	nullCount := float64(idx.NullCount)
+	var lowCount float64
+	if len(low) > 0 {
+		lowCount = idx.lessRowCount(types.NewBytesDatum(low)) + nullCount
+	}
+	highCount := idx.TotalRowCount()
+	if len(high) > 0 {
+		highCount = idx.lessRowCount(types.NewBytesDatum(high)) + nullCount
+	}
+	return math.Max(0, highCount-lowCount) * idx.GetIncreaseFactor(coll.Count)
+}
+
 // PseudoAvgCountPerValue gets a pseudo average count if histogram not exists.
 func (t *Table) PseudoAvgCountPerValue() float64 {
 	return float64(t.Count) / pseudoEqualRate
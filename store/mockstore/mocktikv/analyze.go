@@ -168,6 +168,8 @@ func (h *rpcHandler) handleAnalyzeColumnsReq(req *coprocessor.Request, analyzeRe
 			startTS:   startTS,
 			mvccStore: h.mvccStore,
 			rd:        rd,
+
+			verifyChecksum: sc.VerifyRowChecksum,
 		},
 	}
 	e.fields = make([]*ast.ResultField, len(columns))
@@ -31,6 +31,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/rowcodec"
 
@@ -46,17 +47,24 @@ type dagContext struct {
 	evalCtx   *evalContext
 }
 
-func (h *rpcHandler) handleCopDAGRequest(req *coprocessor.Request) *coprocessor.Response {
+// handleCopDAGRequest handles the DAG request. If pagingSize is not 0, the scan stops after
+// pagingSize rows are read, and the scanned range is returned in the response.
+func (h *rpcHandler) handleCopDAGRequest(req *coprocessor.Request, pagingSize uint64) *coprocessor.Response {
 	resp := &coprocessor.Response{}
 	if err := h.checkRequestContext(req.GetContext()); err != nil {
 		resp.RegionError = err
 		return resp
 	}
-	dagCtx, e, dagReq, err := h.buildDAGExecutor(req)
+	dagCtx, e, _, err := h.buildDAGExecutor(req)
 	if err != nil {
 		resp.OtherError = err.Error()
 		return resp
 	}
+	var scan pagingExecutor
+	if pagingSize > 0 {
+		scan = findPagingExecutor(e)
+		scan.setPagingSize(pagingSize)
+	}
 
 	var rows [][][]byte
 	ctx := context.TODO()
@@ -74,12 +82,24 @@ func (h *rpcHandler) handleCopDAGRequest(req *coprocessor.Request) *coprocessor.
 
 	selResp := h.initSelectResponse(err, dagCtx.evalCtx.sc.GetWarnings(), e.Counts())
 	if err == nil {
-		err = h.fillUpData4SelectResponse(selResp, dagReq, rows)
+		err = h.fillUpData4SelectResponse(selResp, dagCtx, rows)
 	}
 	// FIXME: some err such as (overflow) will be include in Response.OtherError with calling this buildResp.
 	//  Such err should only be marshal in the data but not in OtherError.
 	//  However, we can not distinguish such err now.
-	return buildResp(selResp, err)
+	resp = buildResp(selResp, err)
+	if scan != nil && err == nil {
+		resp.Range = scan.scannedRange()
+	}
+	return resp
+}
+
+// findPagingExecutor returns the scan executor at the bottom of the DAG.
+func findPagingExecutor(e executor) pagingExecutor {
+	for e.GetSrcExec() != nil {
+		e = e.GetSrcExec()
+	}
+	return e.(pagingExecutor)
 }
 
 func (h *rpcHandler) buildDAGExecutor(req *coprocessor.Request) (*dagContext, executor, *tipb.DAGRequest, error) {
@@ -200,6 +220,8 @@ func (h *rpcHandler) buildTableScan(ctx *dagContext, executor *tipb.Executor) (*
 		startTS:   startTS,
 		mvccStore: h.mvccStore,
 		rd:        rd,
+
+		verifyChecksum: ctx.evalCtx.sc.VerifyRowChecksum,
 	}
 	if ctx.dagReq.CollectRangeCounts != nil && *ctx.dagReq.CollectRangeCounts {
 		e.counts = make([]int64, len(ranges))
@@ -396,6 +418,7 @@ func flagsToStatementContext(flags uint64) *stmtctx.StatementContext {
 	sc.OverflowAsWarning = (flags & model.FlagOverflowAsWarning) > 0
 	sc.IgnoreZeroInDate = (flags & model.FlagIgnoreZeroInDate) > 0
 	sc.DividedByZeroAsWarning = (flags & model.FlagDividedByZeroAsWarning) > 0
+	sc.VerifyRowChecksum = (flags & model.FlagVerifyRowChecksum) > 0
 	// TODO set FlagInUpdateOrDeleteStmt, FlagInUnionStmt,
 	return sc
 }
@@ -411,16 +434,84 @@ func (h *rpcHandler) initSelectResponse(err error, warnings []stmtctx.SQLWarn, c
 	return selResp
 }
 
-func (h *rpcHandler) fillUpData4SelectResponse(selResp *tipb.SelectResponse, dagReq *tipb.DAGRequest, rows [][][]byte) error {
+func (h *rpcHandler) fillUpData4SelectResponse(selResp *tipb.SelectResponse, dagCtx *dagContext, rows [][][]byte) error {
+	dagReq := dagCtx.dagReq
+	switch dagReq.EncodeType {
+	case tipb.EncodeType_TypeDefault:
+		h.encodeDefault(selResp, rows, dagReq.OutputOffsets)
+	case tipb.EncodeType_TypeChunk:
+		colTypes := h.constructRespSchema(dagCtx)
+		err := h.encodeChunk(selResp, rows, colTypes, dagReq.OutputOffsets, dagCtx.evalCtx.sc.TimeZone)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unsupported encode type %v", dagReq.EncodeType)
+	}
+	return nil
+}
+
+// constructRespSchema returns the field types of the rows returned by the
+// root executor of the DAG.
+func (h *rpcHandler) constructRespSchema(dagCtx *dagContext) []*types.FieldType {
+	root := dagCtx.dagReq.Executors[len(dagCtx.dagReq.Executors)-1]
+	agg := root.Aggregation
+	if agg == nil {
+		return dagCtx.evalCtx.fieldTps
+	}
+
+	schema := make([]*types.FieldType, 0, len(agg.AggFunc)+len(agg.GroupBy))
+	for _, aggFunc := range agg.AggFunc {
+		if aggFunc.Tp == tipb.ExprType_Avg {
+			// The partial result of AVG is the count and the sum.
+			schema = append(schema, types.NewFieldType(mysql.TypeLonglong))
+		}
+		schema = append(schema, expression.FieldTypeFromPB(aggFunc.FieldType))
+	}
+	for _, item := range agg.GroupBy {
+		schema = append(schema, expression.FieldTypeFromPB(item.FieldType))
+	}
+	return schema
+}
+
+func (h *rpcHandler) encodeDefault(selResp *tipb.SelectResponse, rows [][][]byte, colOrdinal []uint32) {
 	var chunks []tipb.Chunk
 	for i := range rows {
 		requestedRow := dummySlice
-		for _, ordinal := range dagReq.OutputOffsets {
+		for _, ordinal := range colOrdinal {
 			requestedRow = append(requestedRow, rows[i][ordinal]...)
 		}
 		chunks = appendRow(chunks, requestedRow, i)
 	}
 	selResp.Chunks = chunks
+	selResp.EncodeType = tipb.EncodeType_TypeDefault
+}
+
+// encodeChunk decodes the rows into chunks with rowsPerChunk rows, and encodes
+// every chunk in the chunk format, so TiDB can decode the columns directly.
+func (h *rpcHandler) encodeChunk(selResp *tipb.SelectResponse, rows [][][]byte, colTypes []*types.FieldType, colOrdinal []uint32, loc *time.Location) error {
+	var chunks []tipb.Chunk
+	respColTypes := make([]*types.FieldType, 0, len(colOrdinal))
+	for _, ordinal := range colOrdinal {
+		respColTypes = append(respColTypes, colTypes[ordinal])
+	}
+	chk := chunk.NewChunkWithCapacity(respColTypes, rowsPerChunk)
+	encoder := chunk.NewCodec(respColTypes)
+	decoder := codec.NewDecoder(chk, loc)
+	for i := range rows {
+		for j, ordinal := range colOrdinal {
+			_, err := decoder.DecodeOne(rows[i][ordinal], j, colTypes[ordinal])
+			if err != nil {
+				return err
+			}
+		}
+		if chk.NumRows() == rowsPerChunk || i == len(rows)-1 {
+			chunks = append(chunks, tipb.Chunk{RowsData: encoder.Encode(chk)})
+			chk.Reset()
+		}
+	}
+	selResp.Chunks = chunks
+	selResp.EncodeType = tipb.EncodeType_TypeChunk
 	return nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"context"
 	"sort"
 
+	"github.com/pingcap-incubator/tinykv/proto/pkg/coprocessor"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/kv"
@@ -35,6 +36,9 @@ var (
 	_ executor = &selectionExec{}
 	_ executor = &limitExec{}
 	_ executor = &topNExec{}
+
+	_ pagingExecutor = &tableScanExec{}
+	_ pagingExecutor = &indexScanExec{}
 )
 
 type executor interface {
@@ -45,6 +49,53 @@ type executor interface {
 	Next(ctx context.Context) ([][]byte, error)
 }
 
+// pagingExecutor is the scan executor which can stop after a page of rows is read.
+type pagingExecutor interface {
+	executor
+	setPagingSize(size uint64)
+	// scannedRange returns the range scanned by the executor if it stops at the end of a page,
+	// otherwise nil is returned.
+	scannedRange() *coprocessor.KeyRange
+}
+
+// pager limits the number of rows read by a scan executor.
+type pager struct {
+	pagingSize uint64
+	scanned    uint64
+}
+
+func (p *pager) setPagingSize(size uint64) {
+	p.pagingSize = size
+}
+
+func (p *pager) pageFull() bool {
+	return p.pagingSize > 0 && p.scanned >= p.pagingSize
+}
+
+// scannedRange returns the range scanned before the cursor and the seek key of a scan executor
+// if the page is full.
+func (p *pager) scannedRange(kvRanges []kv.KeyRange, cursor int, seekKey []byte, desc bool) *coprocessor.KeyRange {
+	if !p.pageFull() || len(kvRanges) == 0 {
+		return nil
+	}
+	next := seekKey
+	if next == nil {
+		if cursor >= len(kvRanges) {
+			return nil
+		}
+		if desc {
+			next = kvRanges[cursor].EndKey
+		} else {
+			next = kvRanges[cursor].StartKey
+		}
+	}
+	// The ranges are reversed for the desc scan.
+	if desc {
+		return &coprocessor.KeyRange{Start: next, End: kvRanges[0].EndKey}
+	}
+	return &coprocessor.KeyRange{Start: kvRanges[0].StartKey, End: next}
+}
+
 type tableScanExec struct {
 	*tipb.TableScan
 	colIDs    map[int64]int
@@ -55,10 +106,13 @@ type tableScanExec struct {
 	seekKey   []byte
 	start     int
 	counts    []int64
+	pager
 
 	src executor
 
 	rd *rowcodec.BytesDecoder
+	// verifyChecksum indicates whether to verify the checksums stored in the rows.
+	verifyChecksum bool
 }
 
 func (e *tableScanExec) SetSrcExec(exec executor) {
@@ -86,7 +140,22 @@ func (e *tableScanExec) Counts() []int64 {
 	return e.counts[e.start : e.cursor+1]
 }
 
+func (e *tableScanExec) scannedRange() *coprocessor.KeyRange {
+	return e.pager.scannedRange(e.kvRanges, e.cursor, e.seekKey, e.Desc)
+}
+
 func (e *tableScanExec) Next(ctx context.Context) (value [][]byte, err error) {
+	if e.pageFull() {
+		return nil, nil
+	}
+	value, err = e.next(ctx)
+	if value != nil {
+		e.scanned++
+	}
+	return value, err
+}
+
+func (e *tableScanExec) next(ctx context.Context) (value [][]byte, err error) {
 	for e.cursor < len(e.kvRanges) {
 		ran := e.kvRanges[e.cursor]
 		if ran.IsPoint() {
@@ -133,6 +202,11 @@ func (e *tableScanExec) getRowFromPoint(ran kv.KeyRange) ([][]byte, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if e.verifyChecksum {
+		if err = verifyRowChecksum(ran.StartKey, val); err != nil {
+			return nil, err
+		}
+	}
 	row, err := getRowData(e.Columns, e.colIDs, handle, val, e.rd)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -181,6 +255,11 @@ func (e *tableScanExec) getRowFromRange(ran kv.KeyRange) ([][]byte, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	if e.verifyChecksum {
+		if err = verifyRowChecksum(pair.Key, pair.Value); err != nil {
+			return nil, err
+		}
+	}
 	row, err := getRowData(e.Columns, e.colIDs, handle, pair.Value, e.rd)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -199,6 +278,7 @@ type indexScanExec struct {
 	pkStatus  tablecodec.PrimaryKeyStatus
 	start     int
 	counts    []int64
+	pager
 
 	src executor
 }
@@ -232,7 +312,22 @@ func (e *indexScanExec) isUnique() bool {
 	return e.Unique != nil && *e.Unique
 }
 
+func (e *indexScanExec) scannedRange() *coprocessor.KeyRange {
+	return e.pager.scannedRange(e.kvRanges, e.cursor, e.seekKey, e.Desc)
+}
+
 func (e *indexScanExec) Next(ctx context.Context) (value [][]byte, err error) {
+	if e.pageFull() {
+		return nil, nil
+	}
+	value, err = e.next(ctx)
+	if value != nil {
+		e.scanned++
+	}
+	return value, err
+}
+
+func (e *indexScanExec) next(ctx context.Context) (value [][]byte, err error) {
 	for e.cursor < len(e.kvRanges) {
 		ran := e.kvRanges[e.cursor]
 		if ran.IsPoint() && e.isUnique() {
@@ -517,6 +612,14 @@ func (e *limitExec) Next(ctx context.Context) (value [][]byte, err error) {
 	return value, nil
 }
 
+// verifyRowChecksum verifies the row value of the key against the checksum stored in it.
+func verifyRowChecksum(key, value []byte) error {
+	if err := rowcodec.VerifyChecksum(value); err != nil {
+		return errors.Annotatef(err, "key %s", kv.Key(key))
+	}
+	return nil
+}
+
 // getRowData decodes raw byte slice to row data.
 func getRowData(
 	columns []*tipb.ColumnInfo,
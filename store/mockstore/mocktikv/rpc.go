@@ -527,7 +527,7 @@ func (c *RPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.R
 		var res *coprocessor.Response
 		switch r.GetTp() {
 		case kv.ReqTypeDAG:
-			res = handler.handleCopDAGRequest(r)
+			res = handler.handleCopDAGRequest(r, req.PagingSize)
 		case kv.ReqTypeAnalyze:
 			res = handler.handleCopAnalyzeRequest(r)
 		default:
@@ -22,6 +22,7 @@ import (
 
 	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
 	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/terror"
 
@@ -362,6 +363,13 @@ func (c *twoPhaseCommitter) buildPrewriteRequest(batch batchKeys) *tikvrpc.Reque
 }
 
 func (actionPrewrite) handleSingleBatch(c *twoPhaseCommitter, bo *Backoffer, batch batchKeys) error {
+	failpoint.Inject("mockPrewriteErr", func(val failpoint.Value) {
+		// "primary" only fails the batch holding the primary key, any other value fails all batches.
+		if val.(string) != "primary" || bytes.Equal(batch.keys[0], c.primary()) {
+			failpoint.Return(errors.New("mock prewrite error"))
+		}
+	})
+
 	req := c.buildPrewriteRequest(batch)
 	for {
 		resp, err := c.store.SendReq(bo, req, batch.region, readTimeoutShort)
@@ -433,6 +441,22 @@ func (actionCommit) handleSingleBatch(c *twoPhaseCommitter, bo *Backoffer, batch
 		CommitVersion: c.commitTS,
 	}, pb.Context{})
 
+	isPrimary := bytes.Equal(batch.keys[0], c.primary())
+	failpoint.Inject("mockCommitErr", func(val failpoint.Value) {
+		// "primary" only fails committing the primary key, "secondary" only fails the
+		// other keys, which leaves the transaction committed.
+		switch val.(string) {
+		case "primary":
+			if isPrimary {
+				failpoint.Return(errors.New("mock commit primary error"))
+			}
+		case "secondary":
+			if !isPrimary {
+				failpoint.Return(errors.New("mock commit secondary error"))
+			}
+		}
+	})
+
 	sender := NewRegionRequestSender(c.store.regionCache, c.store.client)
 	resp, err := sender.SendReq(bo, req, batch.region, readTimeoutShort)
 
@@ -441,7 +465,6 @@ func (actionCommit) handleSingleBatch(c *twoPhaseCommitter, bo *Backoffer, batch
 	// Under this circumstance,  we can not declare the commit is complete (may lead to data lost), nor can we throw
 	// an error (may lead to the duplicated key error when upper level restarts the transaction). Currently the best
 	// solution is to populate this error and let upper layer drop the connection to the corresponding mysql client.
-	isPrimary := bytes.Equal(batch.keys[0], c.primary())
 	if isPrimary && sender.rpcError != nil {
 		c.setUndeterminedErr(errors.Trace(sender.rpcError))
 	}
@@ -577,6 +600,12 @@ func (c *twoPhaseCommitter) execute(ctx context.Context) (err error) {
 		return errors.Trace(err)
 	}
 
+	failpoint.Inject("beforeGetCommitTS", func(val failpoint.Value) {
+		if val.(bool) {
+			failpoint.Return(errors.New("mock error before getting commitTS"))
+		}
+	})
+
 	commitTS, err := c.store.getTimestampWithRetry(NewBackoffer(ctx, tsoMaxBackoff).WithVars(c.txn.vars))
 	if err != nil {
 		logutil.Logger(ctx).Warn("2PC get commitTS failed",
@@ -596,6 +625,11 @@ func (c *twoPhaseCommitter) execute(ctx context.Context) (err error) {
 	if err = c.checkSchemaValid(); err != nil {
 		return errors.Trace(err)
 	}
+	if check, ok := c.txn.us.GetOption(kv.CommitTSUpperBoundCheck).(func(uint64) bool); ok && !check(c.commitTS) {
+		err = errors.Errorf("conn %d txn commitTS exceeds the upper bound, txnStartTS: %d, comm: %d",
+			c.connID, c.startTS, c.commitTS)
+		return err
+	}
 
 	if c.store.oracle.IsExpired(c.startTS, kv.MaxTxnTimeUse) {
 		err = errors.Errorf("conn %d txn takes too much time, txnStartTS: %d, comm: %d",
@@ -140,3 +140,58 @@ func (s *testCommitterSuite) TestFailPrewriteRegionError(c *C) {
 	err = committer.prewriteKeys(NewBackoffer(ctx, 1000), committer.keys)
 	c.Assert(err, NotNil)
 }
+
+// TestFailPrewriteErr tests a failed prewrite aborts the transaction and leaves no data.
+func (s *testCommitterSuite) TestFailPrewriteErr(c *C) {
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/tikv/mockPrewriteErr", `return("primary")`), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/tikv/mockPrewriteErr"), IsNil)
+	}()
+
+	txn := s.begin(c)
+	c.Assert(txn.Set([]byte("d"), []byte("d1")), IsNil)
+	c.Assert(txn.Set([]byte("e"), []byte("e1")), IsNil)
+	err := txn.Commit(context.Background())
+	c.Assert(err, NotNil)
+
+	txn2 := s.begin(c)
+	_, err = txn2.Get(context.TODO(), []byte("d"))
+	c.Assert(kv.ErrNotExist.Equal(err), IsTrue)
+}
+
+// TestFailBeforeGetCommitTS tests an error between prewrite and commit aborts the transaction.
+func (s *testCommitterSuite) TestFailBeforeGetCommitTS(c *C) {
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/tikv/beforeGetCommitTS", `return(true)`), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/tikv/beforeGetCommitTS"), IsNil)
+	}()
+
+	txn := s.begin(c)
+	c.Assert(txn.Set([]byte("f"), []byte("f1")), IsNil)
+	err := txn.Commit(context.Background())
+	c.Assert(err, NotNil)
+
+	txn2 := s.begin(c)
+	_, err = txn2.Get(context.TODO(), []byte("f"))
+	c.Assert(kv.ErrNotExist.Equal(err), IsTrue)
+}
+
+// TestFailCommitSecondaryErr tests the transaction is still committed when only
+// committing secondary keys fails.
+func (s *testCommitterSuite) TestFailCommitSecondaryErr(c *C) {
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/tikv/mockCommitErr", `return("secondary")`), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/tikv/mockCommitErr"), IsNil)
+	}()
+
+	txn := s.begin(c)
+	c.Assert(txn.Set([]byte("g"), []byte("g1")), IsNil)
+	c.Assert(txn.Set([]byte("h"), []byte("h1")), IsNil)
+	err := txn.Commit(context.Background())
+	c.Assert(err, IsNil)
+
+	txn2 := s.begin(c)
+	value, err := txn2.Get(context.TODO(), []byte("g"))
+	c.Assert(err, IsNil)
+	c.Assert(value, BytesEquals, []byte("g1"))
+}
@@ -435,3 +435,24 @@ func (s *testCommitterSuite) TestPrewriteTxnSize(c *C) {
 	err = committer.prewriteKeys(NewBackoffer(ctx, PrewriteMaxBackoff), committer.keys)
 	c.Assert(err, IsNil)
 }
+
+func (s *testCommitterSuite) TestIngest(c *C) {
+	keys := [][]byte{[]byte("a1"), []byte("a2"), []byte("b1"), []byte("c1"), []byte("c2")}
+	values := [][]byte{[]byte("v1"), []byte("v2"), []byte("v3"), []byte("v4"), []byte("v5")}
+	startTS := s.begin(c).StartTS()
+	commitTS, err := s.store.Ingest(context.Background(), keys, values)
+	c.Assert(err, IsNil)
+	c.Assert(commitTS, Greater, startTS)
+	s.checkValues(c, map[string]string{"a1": "v1", "a2": "v2", "b1": "v3", "c1": "v4", "c2": "v5"})
+
+	// A lock on the keys makes the ingest fail.
+	txn := s.begin(c)
+	err = txn.Set([]byte("b2"), []byte("x"))
+	c.Assert(err, IsNil)
+	committer, err := newTwoPhaseCommitter(txn, 0)
+	c.Assert(err, IsNil)
+	c.Assert(committer.initKeysAndMutations(), IsNil)
+	c.Assert(committer.prewriteKeys(NewBackoffer(context.Background(), PrewriteMaxBackoff), committer.keys), IsNil)
+	_, err = s.store.Ingest(context.Background(), [][]byte{[]byte("b2")}, [][]byte{[]byte("y")})
+	c.Assert(err, NotNil)
+}
@@ -298,6 +298,7 @@ func (b *Backoffer) BackoffWithMaxSleep(typ backoffType, maxSleepMs int, err err
 		b.backoffTimes = make(map[backoffType]int)
 	}
 	b.backoffTimes[typ]++
+	kv.ReportBackoff(b.ctx, typ.String(), b.backoffTimes[typ], realSleep, err)
 
 	var startTs interface{}
 	if ts := b.ctx.Value(txnStartKey); ts != nil {
@@ -16,7 +16,9 @@ package tikv
 import (
 	"context"
 	"errors"
+
 	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/kv"
 )
 
 type testBackoffSuite struct {
@@ -40,3 +42,31 @@ func (s *testBackoffSuite) TestBackoffWithMax(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(b.totalSleep, Equals, 30)
 }
+
+func (s *testBackoffSuite) TestBackoffReporter(c *C) {
+	type report struct {
+		typ      string
+		attempts int
+		sleepMS  int
+		err      error
+	}
+	var reports []report
+	ctx := kv.WithBackoffReporter(context.TODO(), func(typ string, attempts int, sleepMS int, err error) {
+		reports = append(reports, report{typ, attempts, sleepMS, err})
+	})
+	b := NewBackoffer(ctx, 2000)
+	err1, err2 := errors.New("lock"), errors.New("region")
+	c.Assert(b.BackoffWithMaxSleep(boTxnLockFast, 10, err1), IsNil)
+	c.Assert(b.BackoffWithMaxSleep(BoRegionMiss, 1, err2), IsNil)
+	c.Assert(b.BackoffWithMaxSleep(boTxnLockFast, 10, err1), IsNil)
+	c.Assert(reports, DeepEquals, []report{
+		{"txnLockFast", 1, 10, err1},
+		{"regionMiss", 1, 1, err2},
+		{"txnLockFast", 2, 10, err1},
+	})
+
+	// The backoffs are not reported without a reporter.
+	b = NewBackoffer(context.TODO(), 2000)
+	c.Assert(b.BackoffWithMaxSleep(boTxnLockFast, 10, err1), IsNil)
+	c.Assert(reports, HasLen, 3)
+}
@@ -69,6 +69,9 @@ func (c *CopClient) Send(ctx context.Context, req *kv.Request, vars *kv.Variable
 	} else {
 		it.respChan = make(chan *copResponse, it.concurrency)
 	}
+	// The in-flight requests are aborted when the iterator is closed before all the responses
+	// are received, and when the ctx is done because the statement is killed or timed out.
+	ctx, it.cancel = context.WithCancel(ctx)
 	it.open(ctx)
 	return it
 }
@@ -81,6 +84,9 @@ type copTask struct {
 	respChan  chan *copResponse
 	storeAddr string
 	cmdType   tikvrpc.CmdType
+
+	// pagingSize is the max number of rows scanned for the task, 0 means the paging is disabled.
+	pagingSize uint64
 }
 
 func (r *copTask) String() string {
@@ -206,6 +212,10 @@ func buildCopTasks(bo *Backoffer, cache *RegionCache, ranges *copRanges, req *kv
 	cmdType := tikvrpc.CmdCop
 
 	rangesLen := ranges.len()
+	var pagingSize uint64
+	if req.Paging {
+		pagingSize = req.MinPagingSize
+	}
 	var tasks []*copTask
 	appendTask := func(regionWithRangeInfo *KeyLocation, ranges *copRanges) {
 		// TiKV will return gRPC error if the message is too large. So we need to limit the length of the ranges slice
@@ -218,8 +228,9 @@ func buildCopTasks(bo *Backoffer, cache *RegionCache, ranges *copRanges, req *kv
 				ranges: ranges.slice(i, nextI),
 				// Channel buffer is 2 for handling region split.
 				// In a common case, two region split tasks will not be blocked.
-				respChan: make(chan *copResponse, 2),
-				cmdType:  cmdType,
+				respChan:   make(chan *copResponse, 2),
+				cmdType:    cmdType,
+				pagingSize: pagingSize,
 			})
 			i = nextI
 		}
@@ -339,6 +350,8 @@ type copIterator struct {
 	// There are two cases we need to close the `finishCh` channel, one is when context is done, the other one is
 	// when the Close is called. we use atomic.CompareAndSwap `closed` to to make sure the channel is not closed twice.
 	closed uint32
+	// cancel cancels the context of the requests sent by the workers.
+	cancel context.CancelFunc
 
 	minCommitTSPushed
 }
@@ -529,7 +542,12 @@ func (it *copIterator) Next(ctx context.Context) (kv.ResultSubset, error) {
 	if it.respChan != nil {
 		// Get next fetched resp from chan
 		resp, ok, closed = it.recvFromRespCh(ctx, it.respChan)
-		if !ok || closed {
+		if closed {
+			// The ctx is done if Close() is not called, the error tells the statement
+			// is killed or timed out instead of finished.
+			return nil, errors.Trace(ctx.Err())
+		}
+		if !ok {
 			return nil, nil
 		}
 	} else {
@@ -541,8 +559,8 @@ func (it *copIterator) Next(ctx context.Context) (kv.ResultSubset, error) {
 			task := it.tasks[it.curr]
 			resp, ok, closed = it.recvFromRespCh(ctx, task.respChan)
 			if closed {
-				// Close() is already called, so Next() is invalid.
-				return nil, nil
+				// Close() is already called, so Next() is invalid, or the ctx is done.
+				return nil, errors.Trace(ctx.Err())
 			}
 			if ok {
 				break
@@ -609,6 +627,13 @@ func (worker *copIteratorWorker) handleTaskOnce(bo *Backoffer, task *copTask, ch
 		Data:    worker.req.Data,
 		Ranges:  task.ranges.toPBRanges(),
 	}, kvrpcpb.Context{})
+	req.PagingSize = task.pagingSize
+	failpoint.Inject("mockCopSendReqErr", func(val failpoint.Value) {
+		if val.(bool) {
+			failpoint.Return(nil, errors.New("mock coprocessor send request error"))
+		}
+	})
+
 	startTime := time.Now()
 	resp, rpcCtx, storeAddr, err := worker.SendReqCtx(bo, req, task.region, ReadTimeoutMedium, task.storeAddr)
 	if err != nil {
@@ -734,10 +759,48 @@ func (worker *copIteratorWorker) handleCopResponse(bo *Backoffer, rpcCtx *RPCCon
 			zap.Error(err))
 		return nil, errors.Trace(err)
 	}
-	worker.sendToRespCh(resp, ch, true)
+	if worker.sendToRespCh(resp, ch, true) {
+		return nil, nil
+	}
+	if task.pagingSize > 0 && resp.pbResp.Range != nil {
+		return worker.buildNextPageTask(task, resp.pbResp.Range), nil
+	}
 	return nil, nil
 }
 
+// buildNextPageTask builds the task to read the next page of the task, the scanned range
+// is removed from the ranges of the task. It returns nil if there are no ranges left.
+func (worker *copIteratorWorker) buildNextPageTask(task *copTask, scanned *coprocessor.KeyRange) []*copTask {
+	var remain *copRanges
+	if worker.req.Desc {
+		remain, _ = task.ranges.split(scanned.Start)
+	} else {
+		_, remain = task.ranges.split(scanned.End)
+	}
+	if remain.len() == 0 {
+		return nil
+	}
+	return []*copTask{{
+		region:     task.region,
+		ranges:     remain,
+		respChan:   task.respChan,
+		storeAddr:  task.storeAddr,
+		cmdType:    task.cmdType,
+		pagingSize: growPagingSize(task.pagingSize, worker.req.MaxPagingSize),
+	}}
+}
+
+// growPagingSize returns the size of the next page. The page size doubles for every page,
+// so a query which only needs a few rows can stop after the first small pages, while a
+// big scan soon reaches the max page size and doesn't pay for too many requests.
+func growPagingSize(size uint64, max uint64) uint64 {
+	size *= 2
+	if size > max {
+		size = max
+	}
+	return size
+}
+
 func (worker *copIteratorWorker) buildCopTasksFromRemain(bo *Backoffer, task *copTask) ([]*copTask, error) {
 	remainedRanges := task.ranges
 	return buildCopTasks(bo, worker.store.regionCache, remainedRanges, worker.req)
@@ -747,6 +810,7 @@ func (it *copIterator) Close() error {
 	if atomic.CompareAndSwapUint32(&it.closed, 0, 1) {
 		close(it.finishCh)
 	}
+	it.cancel()
 	it.wg.Wait()
 	return nil
 }
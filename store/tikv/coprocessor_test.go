@@ -17,6 +17,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/pingcap-incubator/tinykv/proto/pkg/coprocessor"
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/store/mockstore/mocktikv"
@@ -61,6 +62,30 @@ func (s *testCoprocessorSuite) TestRebuild(c *C) {
 	s.taskEqual(c, tasks[0], regionIDs[2], "q", "z")
 }
 
+func (s *testCoprocessorSuite) TestBuildNextPageTask(c *C) {
+	task := &copTask{ranges: buildCopRanges("a", "c", "e", "g"), pagingSize: 2}
+	worker := &copIteratorWorker{req: &kv.Request{MaxPagingSize: 3}}
+
+	tasks := worker.buildNextPageTask(task, &coprocessor.KeyRange{Start: []byte("a"), End: []byte("f")})
+	c.Assert(tasks, HasLen, 1)
+	c.Assert(tasks[0].pagingSize, Equals, uint64(3))
+	s.checkEqual(c, tasks[0].ranges, buildKeyRanges("f", "g"), false)
+
+	tasks = worker.buildNextPageTask(task, &coprocessor.KeyRange{Start: []byte("a"), End: []byte("g")})
+	c.Assert(tasks, HasLen, 0)
+
+	worker.req.Desc = true
+	tasks = worker.buildNextPageTask(task, &coprocessor.KeyRange{Start: []byte("b"), End: []byte("g")})
+	c.Assert(tasks, HasLen, 1)
+	s.checkEqual(c, tasks[0].ranges, buildKeyRanges("a", "b"), false)
+}
+
+func (s *testCoprocessorSuite) TestGrowPagingSize(c *C) {
+	c.Assert(growPagingSize(128, 1024), Equals, uint64(256))
+	c.Assert(growPagingSize(768, 1024), Equals, uint64(1024))
+	c.Assert(growPagingSize(1024, 1024), Equals, uint64(1024))
+}
+
 func buildKeyRanges(keys ...string) []kv.KeyRange {
 	var ranges []kv.KeyRange
 	for i := 0; i < len(keys); i += 2 {
@@ -0,0 +1,175 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikv
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/pingcap-incubator/tinykv/proto/pkg/kvrpcpb"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/store/tikv/tikvrpc"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+var _ kv.Ingester = &tikvStore{}
+
+// ingestConcurrency is the max number of batches sent concurrently by Ingest.
+const ingestConcurrency = 16
+
+// ingester writes sorted key-value pairs region by region. Unlike twoPhaseCommitter, every batch
+// takes its own first key as the primary lock, so batches are prewritten and committed independently,
+// and there is no lock TTL keeper, schema check or cleanup of a whole transaction.
+type ingester struct {
+	store    *tikvStore
+	values   map[string][]byte
+	startTS  uint64
+	commitTS uint64
+	lockTTL  uint64
+}
+
+// Ingest implements the kv.Ingester interface.
+func (s *tikvStore) Ingest(ctx context.Context, keys, values [][]byte) (uint64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	startTime := time.Now()
+	ing := &ingester{store: s, values: make(map[string][]byte, len(keys))}
+	size := 0
+	for i, k := range keys {
+		ing.values[string(k)] = values[i]
+		size += len(k) + len(values[i])
+	}
+	var err error
+	ing.startTS, err = s.getTimestampWithRetry(NewBackoffer(ctx, tsoMaxBackoff))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	ing.lockTTL = txnLockTTL(startTime, size)
+
+	if err = ing.doActionOnKeys(NewBackoffer(ctx, PrewriteMaxBackoff), actionPrewrite{}, keys); err != nil {
+		return 0, errors.Trace(err)
+	}
+	ing.commitTS, err = s.getTimestampWithRetry(NewBackoffer(ctx, tsoMaxBackoff))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	if err = ing.doActionOnKeys(NewBackoffer(ctx, CommitMaxBackoff), actionCommit{}, keys); err != nil {
+		return 0, errors.Trace(err)
+	}
+	logutil.BgLogger().Info("ingest finished", zap.Int("keys", len(keys)), zap.Int("size", size),
+		zap.Uint64("commitTS", ing.commitTS), zap.Duration("takeTime", time.Since(startTime)))
+	return ing.commitTS, nil
+}
+
+func (ing *ingester) keyValueSize(key []byte) int {
+	return len(key) + len(ing.values[string(key)])
+}
+
+// doActionOnKeys groups the keys into batches by region and does the action on them concurrently.
+func (ing *ingester) doActionOnKeys(bo *Backoffer, action twoPhaseCommitAction, keys [][]byte) error {
+	groups, _, err := ing.store.regionCache.GroupKeysByRegion(bo, keys, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var batches []batchKeys
+	for id, g := range groups {
+		batches = appendBatchBySize(batches, id, g, ing.keyValueSize, txnCommitBatchSize)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	ch := make(chan batchKeys)
+	for i := 0; i < ingestConcurrency && i < len(batches); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range ch {
+				// Each worker gets its own backoffer, like the batchExecutor in 2PC does.
+				if err := ing.handleSingleBatch(bo.Clone(), action, batch); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, batch := range batches {
+		ch <- batch
+	}
+	close(ch)
+	wg.Wait()
+	return errors.Trace(firstErr)
+}
+
+func (ing *ingester) handleSingleBatch(bo *Backoffer, action twoPhaseCommitAction, batch batchKeys) error {
+	var req *tikvrpc.Request
+	if _, ok := action.(actionPrewrite); ok {
+		mutations := make([]*pb.Mutation, len(batch.keys))
+		for i, k := range batch.keys {
+			mutations[i] = &pb.Mutation{Op: pb.Op_Put, Key: k, Value: ing.values[string(k)]}
+		}
+		req = tikvrpc.NewRequest(tikvrpc.CmdPrewrite, &pb.PrewriteRequest{
+			Mutations:    mutations,
+			PrimaryLock:  batch.keys[0],
+			StartVersion: ing.startTS,
+			LockTtl:      ing.lockTTL,
+		}, pb.Context{})
+	} else {
+		req = tikvrpc.NewRequest(tikvrpc.CmdCommit, &pb.CommitRequest{
+			StartVersion:  ing.startTS,
+			Keys:          batch.keys,
+			CommitVersion: ing.commitTS,
+		}, pb.Context{})
+	}
+
+	resp, err := ing.store.SendReq(bo, req, batch.region, readTimeoutShort)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	regionErr, err := resp.GetRegionError()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if regionErr != nil {
+		if err = bo.Backoff(BoRegionMiss, errors.New(regionErr.String())); err != nil {
+			return errors.Trace(err)
+		}
+		// re-split keys and try again.
+		return ing.doActionOnKeys(bo, action, batch.keys)
+	}
+	if resp.Resp == nil {
+		return errors.Trace(ErrBodyMissing)
+	}
+	switch r := resp.Resp.(type) {
+	case *pb.PrewriteResponse:
+		// The keys being ingested are not accessed by anyone else, so any lock or conflict is an error.
+		if keyErrs := r.GetErrors(); len(keyErrs) > 0 {
+			return errors.Trace(extractKeyErr(keyErrs[0]))
+		}
+	case *pb.CommitResponse:
+		if keyErr := r.GetError(); keyErr != nil {
+			return errors.Trace(extractKeyErr(keyErr))
+		}
+	}
+	return nil
+}
@@ -15,9 +15,11 @@ package tikv
 
 import (
 	"context"
+	"fmt"
 	"sync/atomic"
 	"time"
 
+	"github.com/opentracing/opentracing-go"
 	"github.com/pingcap-incubator/tinykv/proto/pkg/errorpb"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -139,7 +141,13 @@ func (s *RegionRequestSender) sendReqToRegion(bo *Backoffer, ctx *RPCContext, re
 	if e := tikvrpc.SetContext(req, ctx.Meta, ctx.Peer); e != nil {
 		return nil, false, errors.Trace(e)
 	}
-	resp, err = s.client.SendRequest(bo.ctx, ctx.Addr, req, timeout)
+	sendCtx := bo.ctx
+	if span := opentracing.SpanFromContext(sendCtx); span != nil && span.Tracer() != nil {
+		span1 := span.Tracer().StartSpan(fmt.Sprintf("regionRequest.sendReqToRegion, region ID: %d, type: %s", ctx.Region.GetID(), req.Type), opentracing.ChildOf(span.Context()))
+		defer span1.Finish()
+		sendCtx = opentracing.ContextWithSpan(sendCtx, span1)
+	}
+	resp, err = s.client.SendRequest(sendCtx, ctx.Addr, req, timeout)
 	if err != nil {
 		s.rpcError = err
 		if e := s.onSendFail(bo, ctx, err); e != nil {
@@ -154,6 +162,9 @@ func (s *RegionRequestSender) onSendFail(bo *Backoffer, ctx *RPCContext, err err
 	// If it failed because the context is cancelled by ourself, don't retry.
 	if errors.Cause(err) == context.Canceled {
 		return errors.Trace(err)
+	} else if bo.ctx.Err() == context.DeadlineExceeded {
+		// The deadline of the statement is exceeded, the store is not to blame.
+		return errors.Trace(err)
 	} else if atomic.LoadUint32(&ShuttingDown) > 0 {
 		return errTiDBShuttingDown
 	}
@@ -0,0 +1,115 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tikv
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap-incubator/tinykv/proto/pkg/schedulerpb"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+var _ kv.RegionStore = &tikvStore{}
+
+// scanRegionsLimit is the max number of the regions returned by a ScanRegions request to PD.
+const scanRegionsLimit = 128
+
+// GetRegions implements the kv.RegionStore interface.
+func (s *tikvStore) GetRegions(ctx context.Context, startKey, endKey kv.Key) ([]*kv.RegionInfo, error) {
+	bo := NewBackoffer(ctx, locateRegionMaxBackoff)
+	var regions []*kv.RegionInfo
+	for {
+		metas, leaders, err := s.pdClient.ScanRegions(ctx, startKey, endKey, scanRegionsLimit)
+		if err != nil {
+			if err = bo.Backoff(BoPDRPC, err); err != nil {
+				return nil, errors.Trace(err)
+			}
+			continue
+		}
+		if len(metas) == 0 {
+			return regions, nil
+		}
+		for i, meta := range metas {
+			region := &kv.RegionInfo{
+				ID:       meta.GetId(),
+				StartKey: meta.GetStartKey(),
+				EndKey:   meta.GetEndKey(),
+			}
+			if i < len(leaders) {
+				region.LeaderStoreID = leaders[i].GetStoreId()
+			}
+			for _, peer := range meta.GetPeers() {
+				region.StoreIDs = append(region.StoreIDs, peer.GetStoreId())
+			}
+			regions = append(regions, region)
+		}
+		startKey = metas[len(metas)-1].GetEndKey()
+		if len(startKey) == 0 || (len(endKey) > 0 && bytes.Compare(startKey, endKey) >= 0) {
+			return regions, nil
+		}
+	}
+}
+
+// ScatterRegions implements the kv.RegionStore interface.
+func (s *tikvStore) ScatterRegions(ctx context.Context, startKey, endKey kv.Key) ([]uint64, error) {
+	regions, err := s.GetRegions(ctx, startKey, endKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	regionIDs := make([]uint64, 0, len(regions))
+	for _, region := range regions {
+		if err = s.scatterRegion(ctx, region.ID); err != nil {
+			return regionIDs, errors.Trace(err)
+		}
+		regionIDs = append(regionIDs, region.ID)
+	}
+	return regionIDs, nil
+}
+
+func (s *tikvStore) scatterRegion(ctx context.Context, regionID uint64) error {
+	bo := NewBackoffer(ctx, scatterRegionBackoff)
+	for {
+		err := s.pdClient.ScatterRegion(ctx, regionID)
+		if err == nil {
+			break
+		}
+		if err = bo.Backoff(BoPDRPC, errors.New(err.Error())); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	logutil.Logger(ctx).Info("scatter region", zap.Uint64("regionID", regionID))
+	return nil
+}
+
+// WaitScatterRegionFinish implements the kv.RegionStore interface.
+func (s *tikvStore) WaitScatterRegionFinish(ctx context.Context, regionID uint64) error {
+	bo := NewBackoffer(ctx, waitScatterRegionFinishBackoff)
+	for {
+		resp, err := s.pdClient.GetOperator(ctx, regionID)
+		if err == nil && resp != nil {
+			// The region is scattered if there is no running scatter operator on it.
+			if !bytes.Equal(resp.Desc, []byte("scatter-region")) || resp.Status != schedulerpb.OperatorStatus_RUNNING {
+				return nil
+			}
+			err = errors.Errorf("region %d is being scattered", regionID)
+		}
+		if err = bo.Backoff(BoRegionMiss, errors.New(err.Error())); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
@@ -81,6 +81,28 @@ func (s *testSQLSuite) TestFailBusyServerCop(c *C) {
 	wg.Wait()
 }
 
+func (s *testSQLSuite) TestFailCopSendReqErr(c *C) {
+	se, err := session.CreateSession4Test(s.store)
+	c.Assert(err, IsNil)
+	sql := `SELECT variable_value FROM mysql.tidb WHERE variable_name="bootstrapped"`
+
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/store/tikv/mockCopSendReqErr", `return(true)`), IsNil)
+	rs, err := se.Execute(context.Background(), sql)
+	c.Assert(err, IsNil)
+	req := rs[0].NewChunk()
+	err = rs[0].Next(context.Background(), req)
+	c.Assert(err, ErrorMatches, ".*mock coprocessor send request error.*")
+	terror.Call(rs[0].Close)
+	c.Assert(failpoint.Disable("github.com/pingcap/tidb/store/tikv/mockCopSendReqErr"), IsNil)
+
+	rs, err = se.Execute(context.Background(), sql)
+	c.Assert(err, IsNil)
+	defer terror.Call(rs[0].Close)
+	req = rs[0].NewChunk()
+	c.Assert(rs[0].Next(context.Background(), req), IsNil)
+	c.Assert(req.GetRow(0).GetString(0), Equals, "True")
+}
+
 func TestMain(m *testing.M) {
 	ReadTimeoutMedium = 2 * time.Second
 	os.Exit(m.Run())
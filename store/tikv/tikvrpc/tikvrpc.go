@@ -82,6 +82,10 @@ type Request struct {
 	req  interface{}
 	kvrpcpb.Context
 	ReplicaReadSeed uint32
+	// PagingSize is the max number of rows scanned by a coprocessor request, 0 means no limit.
+	// The store returns the scanned range in the response if it stops early, so the rest of the
+	// ranges can be requested later. Stores which don't support paging return the whole result.
+	PagingSize uint64
 }
 
 // NewRequest returns new kv rpc request.
@@ -22,6 +22,8 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/model"
@@ -233,6 +235,8 @@ func NewColDesc(col *Column) *ColDesc {
 		//in order to match the rules of mysql 8.0.16 version
 		//see https://github.com/pingcap/tidb/issues/10337
 		extra = "DEFAULT_GENERATED on update CURRENT_TIMESTAMP" + OptionalFsp(&col.FieldType)
+	} else if col.DefaultIsExpr {
+		extra = "DEFAULT_GENERATED"
 	}
 
 	desc := &ColDesc{
@@ -320,9 +324,29 @@ func GetColOriginDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo) (ty
 
 // GetColDefaultValue gets default value of the column.
 func GetColDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo) (types.Datum, error) {
+	if col.DefaultIsExpr {
+		return getColDefaultExprValue(ctx, col)
+	}
 	return getColDefaultValue(ctx, col, col.GetDefaultValue())
 }
 
+// getColDefaultExprValue evaluates the default value expression of the column.
+func getColDefaultExprValue(ctx sessionctx.Context, col *model.ColumnInfo) (types.Datum, error) {
+	exprStr, ok := col.GetDefaultValue().(string)
+	if !ok {
+		return types.Datum{}, types.ErrInvalidDefault.GenWithStackByArgs(col.Name)
+	}
+	stmt, err := parser.New().ParseOneStmt("select "+exprStr, "", "")
+	if err != nil {
+		return types.Datum{}, err
+	}
+	d, err := expression.EvalAstExpr(ctx, stmt.(*ast.SelectStmt).Fields.Fields[0].Expr)
+	if err != nil {
+		return types.Datum{}, err
+	}
+	return CastValue(ctx, d, col)
+}
+
 func getColDefaultValue(ctx sessionctx.Context, col *model.ColumnInfo, defaultVal interface{}) (types.Datum, error) {
 	if defaultVal == nil {
 		return getColDefaultValueFromNil(ctx, col)
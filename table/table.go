@@ -136,6 +136,10 @@ type Table interface {
 	// Cols returns the columns of the table which is used in select.
 	Cols() []*Column
 
+	// VisibleCols returns the public columns of the table which aren't hidden, the hidden
+	// columns of the expression indexes can't be accessed by users.
+	VisibleCols() []*Column
+
 	// WritableCols returns columns of the table in writable states.
 	// Writable states includes Public, WriteOnly, WriteOnlyReorganization.
 	WritableCols() []*Column
@@ -216,6 +220,15 @@ type PhysicalTable interface {
 	GetPhysicalID() int64
 }
 
+// CachedTable is a table whose data is cached in the memory of the TiDB instance.
+type CachedTable interface {
+	Table
+
+	// TryReadFromCache returns the cached data of the table if it can be read by the transaction
+	// with the start ts, otherwise it returns nil and loads the cache in the background.
+	TryReadFromCache(store kv.Storage, ts uint64) kv.MemBuffer
+}
+
 // TableFromMeta builds a table.Table from *model.TableInfo.
 // Currently, it is assigned to tables.TableFromMeta in tidb package's init function.
 var TableFromMeta func(alloc autoid.Allocator, tblInfo *model.TableInfo) (Table, error)
@@ -0,0 +1,278 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tables
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/store/tikv/oracle"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// CachedTableLeaseDuration is the duration of the read and write locks of the cached tables.
+var CachedTableLeaseDuration = 3 * time.Second
+
+// CachedTableSizeLimit is the max size of the data of a cached table.
+const CachedTableSizeLimit = 64 * 1024 * 1024
+
+// The consistency of the cached tables is guaranteed by the lock of the table stored in the meta:
+//  1. A TiDB instance takes the read lock before loading the table into its cache. The table can't be
+//     written until the lease of the read lock, so the cache can be read by the transactions whose start
+//     ts are between the loading ts and the lease.
+//  2. A writer sets the lock to intend to stop the read lock from being renewed, waits for the read lock
+//     to expire, then takes the write lock. The table can't be cached until the lease of the write lock,
+//     and the transaction writing the table must commit before the lease.
+
+// cacheData is the data of a cached table loaded at the start ts, it can be read until the lease.
+type cacheData struct {
+	start uint64
+	lease uint64
+	kv.MemBuffer
+}
+
+// cachedTable is a table whose data is cached in memory.
+type cachedTable struct {
+	TableCommon
+
+	cacheData atomic.Value
+	// loading is 1 if the cache is being loaded in the background.
+	loading int32
+}
+
+// TryReadFromCache implements table.CachedTable TryReadFromCache interface.
+func (c *cachedTable) TryReadFromCache(store kv.Storage, ts uint64) kv.MemBuffer {
+	data, _ := c.cacheData.Load().(*cacheData)
+	if data != nil && data.start <= ts && ts < data.lease {
+		// Renew the lease in advance, so the reads don't miss the cache when the lease expires.
+		if oracle.GetTimeFromTS(data.lease).Sub(oracle.GetTimeFromTS(ts)) < CachedTableLeaseDuration/2 {
+			c.loadCacheInBackground(store)
+		}
+		return data.MemBuffer
+	}
+	c.loadCacheInBackground(store)
+	return nil
+}
+
+func (c *cachedTable) loadCacheInBackground(store kv.Storage) {
+	if !atomic.CompareAndSwapInt32(&c.loading, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&c.loading, 0)
+		if err := c.loadCache(store); err != nil {
+			logutil.BgLogger().Warn("load cached table failed", zap.Int64("tableID", c.tableID), zap.Error(err))
+		}
+	}()
+}
+
+func (c *cachedTable) loadCache(store kv.Storage) error {
+	lease, ok, err := lockCachedTableForRead(store, c.tableID)
+	if err != nil || !ok {
+		return err
+	}
+	// No write can commit between the read lock and the lease, so the data read at any ts after the
+	// read lock stays the same until the lease.
+	ver, err := store.CurrentVersion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ver.Ver >= lease {
+		return nil
+	}
+	snapshot, err := store.GetSnapshot(ver)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buf := kv.NewMemDbBuffer(kv.DefaultTxnMembufCap)
+	err = iterCachedTableData(snapshot, c.tableID, func(key kv.Key, value []byte) error {
+		return buf.Set(key, value)
+	})
+	if err != nil {
+		return err
+	}
+	c.cacheData.Store(&cacheData{start: ver.Ver, lease: lease, MemBuffer: buf})
+	return nil
+}
+
+// CheckCachedTableSize checks whether the data of the table is small enough to be cached.
+func CheckCachedTableSize(store kv.Storage, tableID int64) error {
+	ver, err := store.CurrentVersion()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	snapshot, err := store.GetSnapshot(ver)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return iterCachedTableData(snapshot, tableID, func(kv.Key, []byte) error { return nil })
+}
+
+// iterCachedTableData iterates the rows and the indexes of the table, it returns an error if the data is
+// larger than CachedTableSizeLimit.
+func iterCachedTableData(snapshot kv.Snapshot, tableID int64, fn func(key kv.Key, value []byte) error) error {
+	prefix := tablecodec.GenTablePrefix(tableID)
+	it, err := snapshot.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer it.Close()
+
+	size := 0
+	for it.Valid() && it.Key().HasPrefix(prefix) {
+		size += len(it.Key()) + len(it.Value())
+		if size > CachedTableSizeLimit {
+			return errors.Errorf("the data of the cached table is larger than %d bytes", CachedTableSizeLimit)
+		}
+		if err = fn(it.Key(), it.Value()); err != nil {
+			return errors.Trace(err)
+		}
+		if err = it.Next(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// lockCachedTableForRead takes or renews the read lock of the table. It returns false if the table is being
+// written or a writer is waiting.
+func lockCachedTableForRead(store kv.Storage, tableID int64) (lease uint64, ok bool, err error) {
+	err = kv.RunInNewTxn(store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		lock, err := m.GetCachedTableLock(tableID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		now := txn.StartTS()
+		ok = false
+		switch lock.Type {
+		case meta.CachedTableLockWrite:
+			if now < lock.Lease {
+				return nil
+			}
+		case meta.CachedTableLockIntend:
+			// The intention expires if the writer is gone before taking the write lock.
+			if now < addCachedTableLease(lock.Lease) {
+				return nil
+			}
+		}
+		lease = addCachedTableLease(now)
+		if lock.Type == meta.CachedTableLockRead && lock.Lease > lease {
+			lease = lock.Lease
+		}
+		ok = true
+		return m.SetCachedTableLock(tableID, &meta.CachedTableLock{Type: meta.CachedTableLockRead, Lease: lease})
+	})
+	return lease, ok, errors.Trace(err)
+}
+
+// lockCachedTableForWrite waits for the read lock of the table to expire, and takes the write lock.
+// It returns the lease of the write lock.
+func lockCachedTableForWrite(store kv.Storage, tableID int64) (uint64, error) {
+	for {
+		var lease, readLease uint64
+		err := kv.RunInNewTxn(store, true, func(txn kv.Transaction) error {
+			m := meta.NewMeta(txn)
+			lock, err := m.GetCachedTableLock(tableID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			now := txn.StartTS()
+			readLease = 0
+			if (lock.Type == meta.CachedTableLockRead || lock.Type == meta.CachedTableLockIntend) && now < lock.Lease {
+				readLease = lock.Lease
+				return m.SetCachedTableLock(tableID, &meta.CachedTableLock{Type: meta.CachedTableLockIntend, Lease: lock.Lease})
+			}
+			lease = addCachedTableLease(now)
+			if lock.Type == meta.CachedTableLockWrite && lock.Lease > lease {
+				lease = lock.Lease
+			}
+			return m.SetCachedTableLock(tableID, &meta.CachedTableLock{Type: meta.CachedTableLockWrite, Lease: lease})
+		})
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if readLease == 0 {
+			return lease, nil
+		}
+		time.Sleep(time.Until(oracle.GetTimeFromTS(readLease)))
+	}
+}
+
+func addCachedTableLease(ts uint64) uint64 {
+	return oracle.ComposeTS(oracle.ExtractPhysical(ts)+int64(CachedTableLeaseDuration/time.Millisecond), 0)
+}
+
+// lockForWrite takes the write lock of the table for the transaction, the transaction fails to commit
+// after the lease of the lock.
+func (c *cachedTable) lockForWrite(ctx sessionctx.Context) error {
+	txnCtx := ctx.GetSessionVars().TxnCtx
+	// The write lock is renewed only if it's going to expire.
+	if lease, ok := txnCtx.CachedTables[c.tableID]; ok && time.Until(oracle.GetTimeFromTS(lease)) > CachedTableLeaseDuration/2 {
+		return nil
+	}
+	txn, err := ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	lease, err := lockCachedTableForWrite(ctx.GetStore(), c.tableID)
+	if err != nil {
+		return err
+	}
+	if txnCtx.CachedTables == nil {
+		txnCtx.CachedTables = make(map[int64]uint64)
+	}
+	txnCtx.CachedTables[c.tableID] = lease
+	txn.SetOption(kv.CommitTSUpperBoundCheck, func(commitTS uint64) bool {
+		for _, lease := range txnCtx.CachedTables {
+			if commitTS >= lease {
+				return false
+			}
+		}
+		return true
+	})
+	return nil
+}
+
+// AddRecord implements table.Table AddRecord interface.
+func (c *cachedTable) AddRecord(ctx sessionctx.Context, r []types.Datum, opts ...table.AddRecordOption) (recordID int64, err error) {
+	if err = c.lockForWrite(ctx); err != nil {
+		return 0, err
+	}
+	return c.TableCommon.AddRecord(ctx, r, opts...)
+}
+
+// UpdateRecord implements table.Table UpdateRecord interface.
+func (c *cachedTable) UpdateRecord(ctx sessionctx.Context, h int64, oldData, newData []types.Datum, touched []bool) error {
+	if err := c.lockForWrite(ctx); err != nil {
+		return err
+	}
+	return c.TableCommon.UpdateRecord(ctx, h, oldData, newData, touched)
+}
+
+// RemoveRecord implements table.Table RemoveRecord interface.
+func (c *cachedTable) RemoveRecord(ctx sessionctx.Context, h int64, r []types.Datum) error {
+	if err := c.lockForWrite(ctx); err != nil {
+		return err
+	}
+	return c.TableCommon.RemoveRecord(ctx, h, r)
+}
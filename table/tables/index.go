@@ -97,6 +97,8 @@ type index struct {
 	idxInfo *model.IndexInfo
 	tblInfo *model.TableInfo
 	prefix  kv.Key
+	// descs records whether each index column is in descending order, nil if all are ascending.
+	descs []bool
 }
 
 // NewIndex builds a new Index object.
@@ -106,6 +108,7 @@ func NewIndex(physicalID int64, tblInfo *model.TableInfo, indexInfo *model.Index
 		tblInfo: tblInfo,
 		// The prefix can't encode from tblInfo.ID, because table partition may change the id to partition id.
 		prefix: tablecodec.EncodeTableIndexPrefix(physicalID, indexInfo.ID),
+		descs:  indexInfo.DescColumns(),
 	}
 	return index
 }
@@ -178,7 +181,7 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 	indexedValues = TruncateIndexValuesIfNeeded(c.tblInfo, c.idxInfo, indexedValues)
 	key = c.getIndexKeyBuf(buf, len(c.prefix)+len(indexedValues)*9+9)
 	key = append(key, []byte(c.prefix)...)
-	key, err = codec.EncodeKey(sc, key, indexedValues...)
+	key, err = codec.EncodeKeyWithDesc(sc, key, c.descs, indexedValues...)
 	if !distinct && err == nil {
 		key, err = codec.EncodeKey(sc, key, types.NewDatum(h))
 	}
@@ -252,6 +255,9 @@ func (c *index) Create(sctx sessionctx.Context, rm kv.RetrieverMutator, indexedV
 		err = rm.Set(key, v)
 		return 0, err
 	}
+	if err != nil {
+		return 0, err
+	}
 
 	handle, err := DecodeHandle(value)
 	if err != nil {
@@ -27,6 +27,8 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
@@ -35,9 +37,12 @@ import (
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	// Register the driver of the parser for the generated column expressions.
+	_ "github.com/pingcap/tidb/types/parser_driver"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/spaolacci/murmur3"
 	"go.uber.org/zap"
 )
@@ -49,6 +54,7 @@ type TableCommon struct {
 	physicalTableID int64
 	Columns         []*table.Column
 	publicColumns   []*table.Column
+	visibleColumns  []*table.Column
 	writableColumns []*table.Column
 	writableIndices []table.Index
 	indices         []table.Index
@@ -65,6 +71,13 @@ func MockTableFromMeta(tblInfo *model.TableInfo) table.Table {
 	columns := make([]*table.Column, 0, len(tblInfo.Columns))
 	for _, colInfo := range tblInfo.Columns {
 		col := table.ToColumn(colInfo)
+		if colInfo.IsGenerated() {
+			expr, err := ParseExpression(colInfo.GeneratedExprString)
+			if err != nil {
+				return nil
+			}
+			col.GeneratedExpr = expr
+		}
 		columns = append(columns, col)
 	}
 
@@ -95,9 +108,25 @@ func TableFromMeta(alloc autoid.Allocator, tblInfo *model.TableInfo) (table.Tabl
 		}
 
 		col := table.ToColumn(colInfo)
+		if colInfo.IsGenerated() {
+			expr, err := ParseExpression(colInfo.GeneratedExprString)
+			if err != nil {
+				return nil, err
+			}
+			col.GeneratedExpr = expr
+		}
 		columns = append(columns, col)
 	}
 
+	if tblInfo.TableCacheStatusType == model.TableCacheStatusEnable {
+		t := &cachedTable{}
+		initTableCommon(&t.TableCommon, tblInfo, tblInfo.ID, columns, alloc)
+		if err := initTableIndices(&t.TableCommon); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+
 	var t TableCommon
 	initTableCommon(&t, tblInfo, tblInfo.ID, columns, alloc)
 	if err := initTableIndices(&t); err != nil {
@@ -106,6 +135,15 @@ func TableFromMeta(alloc autoid.Allocator, tblInfo *model.TableInfo) (table.Tabl
 	return &t, nil
 }
 
+// ParseExpression parses the expression string of a generated column.
+func ParseExpression(expr string) (ast.ExprNode, error) {
+	stmt, err := parser.New().ParseOneStmt("select "+expr, "", "")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return stmt.(*ast.SelectStmt).Fields.Fields[0].Expr, nil
+}
+
 // initTableCommon initializes a TableCommon struct.
 func initTableCommon(t *TableCommon, tblInfo *model.TableInfo, physicalTableID int64, cols []*table.Column, alloc autoid.Allocator) {
 	t.tableID = tblInfo.ID
@@ -114,6 +152,7 @@ func initTableCommon(t *TableCommon, tblInfo *model.TableInfo, physicalTableID i
 	t.meta = tblInfo
 	t.Columns = cols
 	t.publicColumns = t.Cols()
+	t.visibleColumns = t.VisibleCols()
 	t.writableColumns = t.WritableCols()
 	t.writableIndices = t.WritableIndices()
 	t.recordPrefix = tablecodec.GenTableRecordPrefix(physicalTableID)
@@ -190,6 +229,20 @@ func (t *TableCommon) Cols() []*table.Column {
 	return publicColumns[0 : maxOffset+1]
 }
 
+// VisibleCols implements table.Table VisibleCols interface.
+func (t *TableCommon) VisibleCols() []*table.Column {
+	if len(t.visibleColumns) > 0 {
+		return t.visibleColumns
+	}
+	visibleColumns := make([]*table.Column, 0, len(t.Columns))
+	for _, col := range t.Cols() {
+		if !col.Hidden {
+			visibleColumns = append(visibleColumns, col)
+		}
+	}
+	return visibleColumns
+}
+
 // WritableCols implements table WritableCols interface.
 func (t *TableCommon) WritableCols() []*table.Column {
 	if len(t.writableColumns) > 0 {
@@ -592,6 +645,9 @@ func (t *TableCommon) RowWithCols(ctx sessionctx.Context, h int64, cols []*table
 	if err != nil {
 		return nil, err
 	}
+	if err = verifyRowChecksum(ctx, key, value); err != nil {
+		return nil, err
+	}
 	v, _, err := DecodeRawRowData(ctx, t.Meta(), h, cols, value)
 	if err != nil {
 		return nil, err
@@ -599,6 +655,17 @@ func (t *TableCommon) RowWithCols(ctx sessionctx.Context, h int64, cols []*table
 	return v, nil
 }
 
+// verifyRowChecksum verifies the row against the checksum stored in it if the statement asks for it.
+func verifyRowChecksum(ctx sessionctx.Context, key kv.Key, value []byte) error {
+	if !ctx.GetSessionVars().StmtCtx.VerifyRowChecksum {
+		return nil
+	}
+	if err := rowcodec.VerifyChecksum(value); err != nil {
+		return errors.Annotatef(err, "key %s", key)
+	}
+	return nil
+}
+
 // DecodeRawRowData decodes raw row data into a datum slice and a (columnID:columnValue) map.
 func DecodeRawRowData(ctx sessionctx.Context, meta *model.TableInfo, h int64, cols []*table.Column,
 	value []byte) ([]types.Datum, map[int64]types.Datum, error) {
@@ -774,6 +841,9 @@ func (t *TableCommon) IterRecords(ctx sessionctx.Context, startKey kv.Key, cols
 		if err != nil {
 			return err
 		}
+		if err = verifyRowChecksum(ctx, it.Key(), it.Value()); err != nil {
+			return err
+		}
 		rowMap, err := tablecodec.DecodeRow(it.Value(), colMap, ctx.GetSessionVars().Location())
 		if err != nil {
 			return err
@@ -941,6 +1011,9 @@ func CanSkip(info *model.TableInfo, col *table.Column, value types.Datum) bool {
 	if col.GetDefaultValue() == nil && value.IsNull() {
 		return true
 	}
+	if col.IsGenerated() {
+		return true
+	}
 	return false
 }
 
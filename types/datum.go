@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/hack"
 )
 
@@ -244,6 +245,18 @@ func (d *Datum) GetRaw() []byte {
 	return d.b
 }
 
+// GetMysqlJSON gets json.BinaryJSON value
+func (d *Datum) GetMysqlJSON() json.BinaryJSON {
+	return json.BinaryJSON{TypeCode: byte(d.i), Value: d.b}
+}
+
+// SetMysqlJSON sets json.BinaryJSON value
+func (d *Datum) SetMysqlJSON(b json.BinaryJSON) {
+	d.k = KindMysqlJSON
+	d.i = int64(b.TypeCode)
+	d.b = b.Value
+}
+
 // SetAutoID set the auto increment ID according to its int flag.
 func (d *Datum) SetAutoID(id int64, flag uint) {
 	if mysql.HasUnsignedFlag(flag) {
@@ -308,6 +321,8 @@ func (d *Datum) GetValue() interface{} {
 		return d.GetBytes()
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.GetBinaryLiteral()
+	case KindMysqlJSON:
+		return d.GetMysqlJSON()
 	default:
 		return d.GetInterface()
 	}
@@ -344,6 +359,8 @@ func (d *Datum) SetValue(val interface{}) {
 		d.SetBinaryLiteral(BinaryLiteral(x))
 	case HexLiteral:
 		d.SetBinaryLiteral(BinaryLiteral(x))
+	case json.BinaryJSON:
+		d.SetMysqlJSON(x)
 	default:
 		d.SetInterface(x)
 	}
@@ -386,6 +403,8 @@ func (d *Datum) CompareDatum(sc *stmtctx.StatementContext, ad *Datum) (int, erro
 		return d.compareBytes(sc, ad.GetBytes())
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.compareBinaryLiteral(sc, ad.GetBinaryLiteral())
+	case KindMysqlJSON:
+		return d.compareMysqlJSON(sc, ad.GetMysqlJSON())
 	default:
 		return 0, nil
 	}
@@ -487,6 +506,14 @@ func (d *Datum) compareBinaryLiteral(sc *stmtctx.StatementContext, b BinaryLiter
 	}
 }
 
+func (d *Datum) compareMysqlJSON(sc *stmtctx.StatementContext, target json.BinaryJSON) (int, error) {
+	origin, err := d.ToMysqlJSON()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return json.CompareBinary(origin, target), nil
+}
+
 // ConvertTo converts a datum to the target field type.
 func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
 	if d.k == KindNull {
@@ -506,6 +533,8 @@ func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datu
 		return d.convertToString(sc, target)
 	case mysql.TypeBit:
 		return d.convertToMysqlBit(sc, target)
+	case mysql.TypeJSON:
+		return d.convertToMysqlJSON(sc, target)
 	case mysql.TypeNull:
 		return Datum{}, nil
 	default:
@@ -581,6 +610,8 @@ func (d *Datum) convertToString(sc *stmtctx.StatementContext, target *FieldType)
 		s = d.GetString()
 	case KindBinaryLiteral, KindMysqlBit:
 		s = d.GetBinaryLiteral().ToString()
+	case KindMysqlJSON:
+		s = d.GetMysqlJSON().String()
 	default:
 		return invalidConv(d, target.Tp)
 	}
@@ -696,6 +727,40 @@ func (d *Datum) convertToMysqlBit(sc *stmtctx.StatementContext, target *FieldTyp
 	return ret, errors.Trace(err)
 }
 
+func (d *Datum) convertToMysqlJSON(sc *stmtctx.StatementContext, target *FieldType) (ret Datum, err error) {
+	j, err := d.ToMysqlJSON()
+	if err != nil {
+		return invalidConv(d, target.Tp)
+	}
+	ret.SetMysqlJSON(j)
+	return ret, nil
+}
+
+// ToMysqlJSON converts the datum to a JSON, the strings are parsed as the JSON text.
+func (d *Datum) ToMysqlJSON() (j json.BinaryJSON, err error) {
+	var in interface{}
+	switch d.Kind() {
+	case KindMysqlJSON:
+		return d.GetMysqlJSON(), nil
+	case KindInt64:
+		in = d.GetInt64()
+	case KindUint64:
+		in = d.GetUint64()
+	case KindFloat32, KindFloat64:
+		in = d.GetFloat64()
+	case KindString, KindBytes:
+		return json.ParseBinaryFromString(d.GetString())
+	case KindNull:
+		in = nil
+	default:
+		in, err = d.ToString()
+		if err != nil {
+			return j, errors.Trace(err)
+		}
+	}
+	return json.CreateBinary(in), nil
+}
+
 // ToBool converts to a bool.
 // We will use 1 for true, and 0 for false.
 func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
@@ -803,6 +868,8 @@ func (d *Datum) ToString() (string, error) {
 		return d.GetString(), nil
 	case KindBinaryLiteral, KindMysqlBit:
 		return d.GetBinaryLiteral().ToString(), nil
+	case KindMysqlJSON:
+		return d.GetMysqlJSON().String(), nil
 	default:
 		return "", errors.Errorf("cannot convert %v(type %T) to string", d.GetValue(), d.GetValue())
 	}
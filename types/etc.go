@@ -95,6 +95,7 @@ var kind2Str = map[byte]string{
 	KindMinNotNull: "min_not_null",
 	KindMaxValue:   "max_value",
 	KindRaw:        "raw",
+	KindMysqlJSON:  "json",
 }
 
 // TypeStr converts tp to a string.
@@ -25,4 +25,6 @@ const (
 	ETReal = ast.ETReal
 	// ETString represents type STRING in evaluation.
 	ETString = ast.ETString
+	// ETJson represents type JSON in evaluation.
+	ETJson = ast.ETJson
 )
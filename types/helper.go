@@ -39,6 +39,10 @@ func RoundFloat(f float64) float64 {
 // value f to become zero.
 func Round(f float64, dec int) float64 {
 	shift := math.Pow10(dec)
+	if shift == 0 {
+		// All the digits are cleared, the shift underflows.
+		return 0
+	}
 	tmp := f * shift
 	if math.IsInf(tmp, 0) {
 		return f
@@ -52,6 +56,10 @@ func Round(f float64, dec int) float64 {
 // value f to become zero.
 func Truncate(f float64, dec int) float64 {
 	shift := math.Pow10(dec)
+	if shift == 0 {
+		// All the digits are cleared, the shift underflows.
+		return 0
+	}
 	tmp := f * shift
 	if math.IsInf(tmp, 0) {
 		return f
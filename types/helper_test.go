@@ -57,9 +57,36 @@ func (s *testTypeHelperSuite) TestTruncate(c *C) {
 		{123.45, 1, 123.4},
 		{123.45, 2, 123.45},
 		{123.45, 3, 123.450},
+		{123.45, -2, 100},
+		{-123.45, -3, 0},
+		{123.45, -400, 0},
+		{123.45, 400, 123.45},
 	}
 	for _, tt := range tests {
 		res := Truncate(tt.f, tt.dec)
 		c.Assert(res, Equals, tt.expected)
 	}
 }
+
+func (s *testTypeHelperSuite) TestRound(c *C) {
+	c.Parallel()
+	tests := []struct {
+		f        float64
+		dec      int
+		expected float64
+	}{
+		{123.45, 0, 123},
+		{123.5, 0, 124},
+		{-123.5, 0, -124},
+		{123.45, 1, 123.5},
+		{150, -2, 200},
+		{-150, -2, -200},
+		{123.45, -3, 0},
+		{123.45, -400, 0},
+		{123.45, 400, 123.45},
+	}
+	for _, tt := range tests {
+		res := Round(tt.f, tt.dec)
+		c.Assert(res, Equals, tt.expected, Commentf("%v %v", tt.f, tt.dec))
+	}
+}
@@ -0,0 +1,482 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// BinaryJSON represents a binary encoded JSON object.
+// It can be randomly accessed without deserialization.
+type BinaryJSON struct {
+	TypeCode TypeCode
+	Value    []byte
+}
+
+// String implements fmt.Stringer interface.
+func (bj BinaryJSON) String() string {
+	out, err := bj.marshalTo(make([]byte, 0, len(bj.Value)))
+	terror.Log(err)
+	return string(out)
+}
+
+// Copy makes a copy of the BinaryJSON.
+func (bj BinaryJSON) Copy() BinaryJSON {
+	buf := make([]byte, len(bj.Value))
+	copy(buf, bj.Value)
+	return BinaryJSON{TypeCode: bj.TypeCode, Value: buf}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (bj BinaryJSON) MarshalJSON() ([]byte, error) {
+	return bj.marshalTo(make([]byte, 0, len(bj.Value)))
+}
+
+// GetInt64 gets the int64 value.
+func (bj BinaryJSON) GetInt64() int64 {
+	return int64(jsonEndian.Uint64(bj.Value))
+}
+
+// GetUint64 gets the uint64 value.
+func (bj BinaryJSON) GetUint64() uint64 {
+	return jsonEndian.Uint64(bj.Value)
+}
+
+// GetFloat64 gets the float64 value.
+func (bj BinaryJSON) GetFloat64() float64 {
+	return math.Float64frombits(bj.GetUint64())
+}
+
+// GetString gets the string value.
+func (bj BinaryJSON) GetString() []byte {
+	strLen, lenLen := binary.Uvarint(bj.Value)
+	return bj.Value[lenLen : lenLen+int(strLen)]
+}
+
+// GetElemCount gets the count of the elements of an object or an array.
+func (bj BinaryJSON) GetElemCount() int {
+	return int(jsonEndian.Uint32(bj.Value))
+}
+
+func (bj BinaryJSON) arrayGetElem(idx int) BinaryJSON {
+	return bj.valEntryGet(headerSize + idx*valEntrySize)
+}
+
+func (bj BinaryJSON) objectGetKey(i int) []byte {
+	keyOff := int(jsonEndian.Uint32(bj.Value[headerSize+i*keyEntrySize:]))
+	keyLen := int(jsonEndian.Uint16(bj.Value[headerSize+i*keyEntrySize+keyLenOff:]))
+	return bj.Value[keyOff : keyOff+keyLen]
+}
+
+func (bj BinaryJSON) objectGetVal(i int) BinaryJSON {
+	elemCount := bj.GetElemCount()
+	return bj.valEntryGet(headerSize + elemCount*keyEntrySize + i*valEntrySize)
+}
+
+// objectSearchKey searches the value of the key in the object.
+func (bj BinaryJSON) objectSearchKey(key []byte) (BinaryJSON, bool) {
+	elemCount := bj.GetElemCount()
+	idx := sort.Search(elemCount, func(i int) bool {
+		return bytes.Compare(bj.objectGetKey(i), key) >= 0
+	})
+	if idx < elemCount && bytes.Equal(bj.objectGetKey(idx), key) {
+		return bj.objectGetVal(idx), true
+	}
+	return BinaryJSON{}, false
+}
+
+func (bj BinaryJSON) valEntryGet(valEntryOff int) BinaryJSON {
+	tpCode := bj.Value[valEntryOff]
+	valOff := jsonEndian.Uint32(bj.Value[valEntryOff+valTypeSize:])
+	switch tpCode {
+	case TypeCodeLiteral:
+		return BinaryJSON{TypeCode: TypeCodeLiteral, Value: bj.Value[valEntryOff+valTypeSize : valEntryOff+valTypeSize+1]}
+	case TypeCodeUint64, TypeCodeInt64, TypeCodeFloat64:
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+8]}
+	case TypeCodeString:
+		strLen, lenLen := binary.Uvarint(bj.Value[valOff:])
+		totalLen := uint32(lenLen) + uint32(strLen)
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+totalLen]}
+	}
+	dataSize := jsonEndian.Uint32(bj.Value[valOff+dataSizeOff:])
+	return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+dataSize]}
+}
+
+func (bj BinaryJSON) marshalTo(buf []byte) ([]byte, error) {
+	switch bj.TypeCode {
+	case TypeCodeString:
+		return marshalStringTo(buf, bj.GetString()), nil
+	case TypeCodeLiteral:
+		return marshalLiteralTo(buf, bj.Value[0]), nil
+	case TypeCodeInt64:
+		return strconv.AppendInt(buf, bj.GetInt64(), 10), nil
+	case TypeCodeUint64:
+		return strconv.AppendUint(buf, bj.GetUint64(), 10), nil
+	case TypeCodeFloat64:
+		return marshalFloat64To(buf, bj.GetFloat64())
+	case TypeCodeArray:
+		return bj.marshalArrayTo(buf)
+	case TypeCodeObject:
+		return bj.marshalObjTo(buf)
+	}
+	return buf, errors.Errorf(unknownTypeCodeErrorMsg, bj.TypeCode)
+}
+
+func (bj BinaryJSON) marshalArrayTo(buf []byte) ([]byte, error) {
+	elemCount := bj.GetElemCount()
+	buf = append(buf, '[')
+	for i := 0; i < elemCount; i++ {
+		if i != 0 {
+			buf = append(buf, ", "...)
+		}
+		var err error
+		buf, err = bj.arrayGetElem(i).marshalTo(buf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return append(buf, ']'), nil
+}
+
+func (bj BinaryJSON) marshalObjTo(buf []byte) ([]byte, error) {
+	elemCount := bj.GetElemCount()
+	buf = append(buf, '{')
+	for i := 0; i < elemCount; i++ {
+		if i != 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = marshalStringTo(buf, bj.objectGetKey(i))
+		buf = append(buf, ": "...)
+		var err error
+		buf, err = bj.objectGetVal(i).marshalTo(buf)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+// marshalFloat64To formats the float like encoding/json.
+func marshalFloat64To(buf []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return buf, &json.UnsupportedValueError{Str: strconv.FormatFloat(f, 'g', -1, 64)}
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		n := len(buf)
+		if n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf, nil
+}
+
+func marshalStringTo(buf, s []byte) []byte {
+	buf = append(buf, '"')
+	for _, c := range s {
+		switch c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		default:
+			if c < 0x20 {
+				buf = append(buf, fmt.Sprintf(`\u%04x`, c)...)
+			} else {
+				buf = append(buf, c)
+			}
+		}
+	}
+	return append(buf, '"')
+}
+
+func marshalLiteralTo(b []byte, litType byte) []byte {
+	switch litType {
+	case LiteralFalse:
+		return append(b, "false"...)
+	case LiteralTrue:
+		return append(b, "true"...)
+	default:
+		return append(b, "null"...)
+	}
+}
+
+// ParseBinaryFromString parses a JSON text to BinaryJSON.
+func ParseBinaryFromString(s string) (bj BinaryJSON, err error) {
+	if len(s) == 0 {
+		return bj, ErrInvalidJSONText.GenWithStackByArgs("The document is empty")
+	}
+	if err = bj.UnmarshalJSON(hack.Slice(s)); err != nil {
+		return bj, ErrInvalidJSONText.GenWithStackByArgs(err)
+	}
+	return bj, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (bj *BinaryJSON) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var in interface{}
+	if err := decoder.Decode(&in); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("The document root must not be followed by other values.")
+	}
+	typeCode, buf, err := appendBinary(nil, in)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	bj.TypeCode = typeCode
+	bj.Value = buf
+	return nil
+}
+
+// CreateBinary creates a BinaryJSON from the Go value, which can be nil, bool, int64, uint64, float64,
+// string, json.Number, BinaryJSON, []interface{} or map[string]interface{}.
+func CreateBinary(in interface{}) BinaryJSON {
+	typeCode, buf, err := appendBinary(nil, in)
+	if err != nil {
+		panic(err)
+	}
+	return BinaryJSON{TypeCode: typeCode, Value: buf}
+}
+
+func appendBinary(buf []byte, in interface{}) (TypeCode, []byte, error) {
+	var typeCode byte
+	var err error
+	switch x := in.(type) {
+	case nil:
+		typeCode = TypeCodeLiteral
+		buf = append(buf, LiteralNil)
+	case bool:
+		typeCode = TypeCodeLiteral
+		if x {
+			buf = append(buf, LiteralTrue)
+		} else {
+			buf = append(buf, LiteralFalse)
+		}
+	case int64:
+		typeCode = TypeCodeInt64
+		buf = appendBinaryUint64(buf, uint64(x))
+	case uint64:
+		typeCode = TypeCodeUint64
+		buf = appendBinaryUint64(buf, x)
+	case float64:
+		typeCode = TypeCodeFloat64
+		buf = appendBinaryUint64(buf, math.Float64bits(x))
+	case json.Number:
+		typeCode, buf, err = appendBinaryNumber(buf, x)
+	case string:
+		typeCode = TypeCodeString
+		buf = appendBinaryString(buf, x)
+	case BinaryJSON:
+		typeCode = x.TypeCode
+		buf = append(buf, x.Value...)
+	case []interface{}:
+		typeCode = TypeCodeArray
+		buf, err = appendBinaryArray(buf, x)
+	case map[string]interface{}:
+		typeCode = TypeCodeObject
+		buf, err = appendBinaryObject(buf, x)
+	default:
+		err = errors.Errorf("unknown type: %s", reflect.TypeOf(in))
+	}
+	return typeCode, buf, err
+}
+
+func appendBinaryNumber(buf []byte, x json.Number) (TypeCode, []byte, error) {
+	if !strings.ContainsAny(string(x), ".eE") {
+		if i64, err := x.Int64(); err == nil {
+			return TypeCodeInt64, appendBinaryUint64(buf, uint64(i64)), nil
+		}
+		if u64, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+			return TypeCodeUint64, appendBinaryUint64(buf, u64), nil
+		}
+	}
+	f64, err := x.Float64()
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	return TypeCodeFloat64, appendBinaryUint64(buf, math.Float64bits(f64)), nil
+}
+
+func appendBinaryUint64(buf []byte, v uint64) []byte {
+	off := len(buf)
+	buf = appendZero(buf, 8)
+	jsonEndian.PutUint64(buf[off:], v)
+	return buf
+}
+
+func appendBinaryString(buf []byte, v string) []byte {
+	begin := len(buf)
+	buf = appendZero(buf, binary.MaxVarintLen64)
+	lenLen := binary.PutUvarint(buf[begin:], uint64(len(v)))
+	buf = buf[:begin+lenLen]
+	return append(buf, v...)
+}
+
+func appendBinaryArray(buf []byte, array []interface{}) ([]byte, error) {
+	docOff := len(buf)
+	buf = appendUint32(buf, uint32(len(array)))
+	buf = appendZero(buf, dataSizeOff)
+	valEntryBegin := len(buf)
+	buf = appendZero(buf, len(array)*valEntrySize)
+	for i, val := range array {
+		var err error
+		buf, err = appendBinaryValElem(buf, docOff, valEntryBegin+i*valEntrySize, val)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	jsonEndian.PutUint32(buf[docOff+dataSizeOff:], uint32(len(buf)-docOff))
+	return buf, nil
+}
+
+func appendBinaryObject(buf []byte, x map[string]interface{}) ([]byte, error) {
+	keys := make([]string, 0, len(x))
+	for key := range x {
+		if len(key) > math.MaxUint16 {
+			return nil, errors.New("the key of the JSON object is too long")
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	docOff := len(buf)
+	buf = appendUint32(buf, uint32(len(x)))
+	buf = appendZero(buf, dataSizeOff)
+	keyEntryBegin := len(buf)
+	buf = appendZero(buf, len(x)*keyEntrySize)
+	valEntryBegin := len(buf)
+	buf = appendZero(buf, len(x)*valEntrySize)
+	for i, key := range keys {
+		keyEntryOff := keyEntryBegin + i*keyEntrySize
+		jsonEndian.PutUint32(buf[keyEntryOff:], uint32(len(buf)-docOff))
+		jsonEndian.PutUint16(buf[keyEntryOff+keyLenOff:], uint16(len(key)))
+		buf = append(buf, key...)
+	}
+	for i, key := range keys {
+		var err error
+		buf, err = appendBinaryValElem(buf, docOff, valEntryBegin+i*valEntrySize, x[key])
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	jsonEndian.PutUint32(buf[docOff+dataSizeOff:], uint32(len(buf)-docOff))
+	return buf, nil
+}
+
+func appendBinaryValElem(buf []byte, docOff, valEntryOff int, val interface{}) ([]byte, error) {
+	elemDocOff := len(buf)
+	typeCode, buf, err := appendBinary(buf, val)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	buf[valEntryOff] = typeCode
+	if typeCode == TypeCodeLiteral {
+		// The literal is inlined in the value entry.
+		buf[valEntryOff+valTypeSize] = buf[elemDocOff]
+		return buf[:elemDocOff], nil
+	}
+	jsonEndian.PutUint32(buf[valEntryOff+valTypeSize:], uint32(elemDocOff-docOff))
+	return buf, nil
+}
+
+func appendZero(buf []byte, length int) []byte {
+	for i := 0; i < length; i++ {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	off := len(buf)
+	buf = appendZero(buf, 4)
+	jsonEndian.PutUint32(buf[off:], v)
+	return buf
+}
+
+// toGo converts the BinaryJSON to the Go value accepted by CreateBinary.
+func (bj BinaryJSON) toGo() interface{} {
+	switch bj.TypeCode {
+	case TypeCodeObject:
+		elemCount := bj.GetElemCount()
+		obj := make(map[string]interface{}, elemCount)
+		for i := 0; i < elemCount; i++ {
+			obj[string(bj.objectGetKey(i))] = bj.objectGetVal(i).toGo()
+		}
+		return obj
+	case TypeCodeArray:
+		elemCount := bj.GetElemCount()
+		array := make([]interface{}, 0, elemCount)
+		for i := 0; i < elemCount; i++ {
+			array = append(array, bj.arrayGetElem(i).toGo())
+		}
+		return array
+	}
+	return bj
+}
+
+// PeekBytesAsJSON peeks the bytes of a BinaryJSON encoded as its type code followed by its value, and
+// returns the length of them.
+func PeekBytesAsJSON(b []byte) (n int, err error) {
+	if len(b) <= valTypeSize {
+		return 0, errors.New("cannot peek JSON from empty bytes")
+	}
+	switch b[0] {
+	case TypeCodeObject, TypeCodeArray:
+		if len(b) >= valTypeSize+headerSize {
+			return valTypeSize + int(jsonEndian.Uint32(b[valTypeSize+dataSizeOff:])), nil
+		}
+	case TypeCodeString:
+		strLen, lenLen := binary.Uvarint(b[valTypeSize:])
+		if lenLen > 0 {
+			return valTypeSize + lenLen + int(strLen), nil
+		}
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		return valTypeSize + 8, nil
+	case TypeCodeLiteral:
+		return valTypeSize + 1, nil
+	}
+	return 0, errors.New("invalid JSON bytes")
+}
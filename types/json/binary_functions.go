@@ -0,0 +1,367 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/util/hack"
+)
+
+// Type returns type of BinaryJSON as string.
+func (bj BinaryJSON) Type() string {
+	switch bj.TypeCode {
+	case TypeCodeObject:
+		return "OBJECT"
+	case TypeCodeArray:
+		return "ARRAY"
+	case TypeCodeLiteral:
+		if bj.Value[0] == LiteralNil {
+			return "NULL"
+		}
+		return "BOOLEAN"
+	case TypeCodeInt64:
+		return "INTEGER"
+	case TypeCodeUint64:
+		return "UNSIGNED INTEGER"
+	case TypeCodeFloat64:
+		return "DOUBLE"
+	case TypeCodeString:
+		return "STRING"
+	default:
+		panic(fmt.Sprintf(unknownTypeCodeErrorMsg, bj.TypeCode))
+	}
+}
+
+// Unquote is for JSON_UNQUOTE, it returns the value of a JSON string, or the JSON text of the others.
+func (bj BinaryJSON) Unquote() string {
+	if bj.TypeCode == TypeCodeString {
+		return string(bj.GetString())
+	}
+	return bj.String()
+}
+
+// UnquoteString unquotes the string if it's a JSON string like `"a\tb"`, otherwise it returns the string
+// as it is.
+func UnquoteString(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s, nil
+	}
+	var res string
+	if err := json.Unmarshal(hack.Slice(s), &res); err != nil {
+		return "", ErrInvalidJSONText.GenWithStackByArgs(err)
+	}
+	return res, nil
+}
+
+// Extract receives several path expressions as arguments, matches them in bj, and returns:
+//
+//	ret: target JSON matched any path expressions. maybe autowrapped as an array.
+//	found: true if any path expressions matched.
+func (bj BinaryJSON) Extract(pathExprList []PathExpression) (ret BinaryJSON, found bool) {
+	buf := make([]BinaryJSON, 0, 1)
+	for _, pathExpr := range pathExprList {
+		buf = bj.extractTo(buf, pathExpr)
+	}
+	if len(buf) == 0 {
+		return ret, false
+	}
+	if len(pathExprList) == 1 && !pathExprList[0].ContainsAnyAsterisk() {
+		// A path without asterisks matches one value at most.
+		return buf[0], true
+	}
+	elems := make([]interface{}, 0, len(buf))
+	for _, elem := range buf {
+		elems = append(elems, elem)
+	}
+	return CreateBinary(elems), true
+}
+
+func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		return append(buf, bj)
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	switch currentLeg.typ {
+	case pathLegIndex:
+		if bj.TypeCode != TypeCodeArray {
+			// A value which isn't an array is treated as an array of one element.
+			if currentLeg.arrayIndex == 0 || currentLeg.arrayIndex == arrayIndexAsterisk {
+				buf = bj.extractTo(buf, subPathExpr)
+			}
+			return buf
+		}
+		elemCount := bj.GetElemCount()
+		if currentLeg.arrayIndex == arrayIndexAsterisk {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
+			}
+		} else if currentLeg.arrayIndex < elemCount {
+			buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
+		}
+	case pathLegKey:
+		if bj.TypeCode != TypeCodeObject {
+			return buf
+		}
+		if currentLeg.dotKey == "*" {
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, subPathExpr)
+			}
+		} else if child, ok := bj.objectSearchKey(hack.Slice(currentLeg.dotKey)); ok {
+			buf = child.extractTo(buf, subPathExpr)
+		}
+	case pathLegDoubleAsterisk:
+		buf = bj.extractTo(buf, subPathExpr)
+		switch bj.TypeCode {
+		case TypeCodeArray:
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, pathExpr)
+			}
+		case TypeCodeObject:
+			elemCount := bj.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, pathExpr)
+			}
+		}
+	}
+	return buf
+}
+
+// ModifyType is for modify a JSON. There are three valid values:
+// ModifyInsert, ModifyReplace and ModifySet.
+type ModifyType byte
+
+const (
+	// ModifyInsert is for insert a new element into a JSON.
+	ModifyInsert ModifyType = 0x01
+	// ModifyReplace is for replace an old elemList from a JSON.
+	ModifyReplace ModifyType = 0x02
+	// ModifySet = ModifyInsert | ModifyReplace
+	ModifySet ModifyType = 0x03
+)
+
+// Modify modifies a JSON object by insert, replace or set.
+// All path expressions cannot contain * or ** wildcard.
+// If any error occurs, the input won't be changed.
+func (bj BinaryJSON) Modify(pathExprList []PathExpression, values []BinaryJSON, mt ModifyType) (BinaryJSON, error) {
+	if len(pathExprList) != len(values) {
+		// TODO: should return 1582(42000)
+		return bj, errors.New("Incorrect parameter count")
+	}
+	for _, pathExpr := range pathExprList {
+		if pathExpr.ContainsAnyAsterisk() {
+			return bj, ErrInvalidJSONPathWildcard
+		}
+	}
+	doc := bj.toGo()
+	for i, pathExpr := range pathExprList {
+		doc = modify(doc, pathExpr.legs, values[i], mt)
+	}
+	typeCode, buf, err := appendBinary(nil, doc)
+	if err != nil {
+		return bj, errors.Trace(err)
+	}
+	return BinaryJSON{TypeCode: typeCode, Value: buf}, nil
+}
+
+// modify modifies the doc converted by toGo at the path, and returns the modified doc.
+func modify(doc interface{}, legs []pathLeg, value BinaryJSON, mt ModifyType) interface{} {
+	if len(legs) == 0 {
+		// The path exists.
+		if mt&ModifyReplace == 0 {
+			return doc
+		}
+		return value
+	}
+	leg := legs[0]
+	switch leg.typ {
+	case pathLegIndex:
+		array, ok := doc.([]interface{})
+		if !ok {
+			// A value which isn't an array is treated as an array of one element, it's wrapped into an
+			// array when a new element is appended.
+			if leg.arrayIndex == 0 {
+				return modify(doc, legs[1:], value, mt)
+			}
+			if len(legs) == 1 && mt&ModifyInsert != 0 {
+				return []interface{}{doc, value}
+			}
+			return doc
+		}
+		if leg.arrayIndex < len(array) {
+			array[leg.arrayIndex] = modify(array[leg.arrayIndex], legs[1:], value, mt)
+			return array
+		}
+		if len(legs) == 1 && mt&ModifyInsert != 0 {
+			return append(array, value)
+		}
+	case pathLegKey:
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc
+		}
+		if child, ok := obj[leg.dotKey]; ok {
+			obj[leg.dotKey] = modify(child, legs[1:], value, mt)
+		} else if len(legs) == 1 && mt&ModifyInsert != 0 {
+			obj[leg.dotKey] = value
+		}
+	}
+	return doc
+}
+
+// ContainsBinary checks whether the obj contains the target, it's for JSON_CONTAINS.
+//  1. A scalar contains a target scalar if and only if they are comparable and are equal.
+//  2. An array contains a target nonarray if and only if the target is contained in some element of the array.
+//  3. An array contains a target array if and only if every element of the target is contained in some
+//     element of the array.
+//  4. An object contains a target object if and only if, for each key in the target, there is a key with the
+//     same name in the object and the value associated with the target key is contained in the value
+//     associated with the object key.
+func ContainsBinary(obj, target BinaryJSON) bool {
+	switch obj.TypeCode {
+	case TypeCodeObject:
+		if target.TypeCode != TypeCodeObject {
+			return false
+		}
+		elemCount := target.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			val, ok := obj.objectSearchKey(target.objectGetKey(i))
+			if !ok || !ContainsBinary(val, target.objectGetVal(i)) {
+				return false
+			}
+		}
+		return true
+	case TypeCodeArray:
+		if target.TypeCode == TypeCodeArray {
+			elemCount := target.GetElemCount()
+			for i := 0; i < elemCount; i++ {
+				if !ContainsBinary(obj, target.arrayGetElem(i)) {
+					return false
+				}
+			}
+			return true
+		}
+		elemCount := obj.GetElemCount()
+		for i := 0; i < elemCount; i++ {
+			if ContainsBinary(obj.arrayGetElem(i), target) {
+				return true
+			}
+		}
+		return false
+	default:
+		return CompareBinary(obj, target) == 0
+	}
+}
+
+// jsonTypePrecedences is the precedences of the JSON types in comparison, see
+// https://dev.mysql.com/doc/refman/5.7/en/json.html#json-comparison.
+var jsonTypePrecedences = map[string]int{
+	"BOOLEAN":          -7,
+	"ARRAY":            -8,
+	"OBJECT":           -9,
+	"STRING":           -10,
+	"INTEGER":          -11,
+	"UNSIGNED INTEGER": -11,
+	"DOUBLE":           -11,
+	"NULL":             -12,
+}
+
+// CompareBinary compares two BinaryJSON. The values of different types are ordered by the precedences of
+// the types, the numbers are compared numerically.
+func CompareBinary(left, right BinaryJSON) int {
+	precedence1 := jsonTypePrecedences[left.Type()]
+	precedence2 := jsonTypePrecedences[right.Type()]
+	if precedence1 != precedence2 {
+		return compareInt64(int64(precedence1), int64(precedence2))
+	}
+	switch left.TypeCode {
+	case TypeCodeLiteral:
+		// false is less than true, and LiteralTrue is less than LiteralFalse.
+		return compareInt64(int64(right.Value[0]), int64(left.Value[0]))
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		return compareNumber(left, right)
+	case TypeCodeString:
+		return bytes.Compare(left.GetString(), right.GetString())
+	case TypeCodeArray:
+		leftCount, rightCount := left.GetElemCount(), right.GetElemCount()
+		for i := 0; i < leftCount && i < rightCount; i++ {
+			if cmp := CompareBinary(left.arrayGetElem(i), right.arrayGetElem(i)); cmp != 0 {
+				return cmp
+			}
+		}
+		return compareInt64(int64(leftCount), int64(rightCount))
+	default:
+		// The objects are only compared for equality.
+		return bytes.Compare(left.Value, right.Value)
+	}
+}
+
+func compareNumber(left, right BinaryJSON) int {
+	switch {
+	case left.TypeCode == TypeCodeInt64 && right.TypeCode == TypeCodeInt64:
+		return compareInt64(left.GetInt64(), right.GetInt64())
+	case left.TypeCode == TypeCodeUint64 && right.TypeCode == TypeCodeUint64:
+		return compareUint64(left.GetUint64(), right.GetUint64())
+	case left.TypeCode == TypeCodeInt64 && right.TypeCode == TypeCodeUint64:
+		if left.GetInt64() < 0 {
+			return -1
+		}
+		return compareUint64(uint64(left.GetInt64()), right.GetUint64())
+	case left.TypeCode == TypeCodeUint64 && right.TypeCode == TypeCodeInt64:
+		return -compareNumber(right, left)
+	}
+	return compareFloat64(left.toFloat64(), right.toFloat64())
+}
+
+func (bj BinaryJSON) toFloat64() float64 {
+	switch bj.TypeCode {
+	case TypeCodeInt64:
+		return float64(bj.GetInt64())
+	case TypeCodeUint64:
+		return float64(bj.GetUint64())
+	}
+	return bj.GetFloat64()
+}
+
+func compareInt64(x, y int64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareUint64(x, y uint64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareFloat64(x, y float64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
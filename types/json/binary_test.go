@@ -0,0 +1,228 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testJSONSuite{})
+
+type testJSONSuite struct{}
+
+func mustParseBinaryFromString(c *C, s string) BinaryJSON {
+	bj, err := ParseBinaryFromString(s)
+	c.Assert(err, IsNil)
+	return bj
+}
+
+func mustParseJSONPathExprs(c *C, exprs ...string) []PathExpression {
+	pathExprs := make([]PathExpression, 0, len(exprs))
+	for _, expr := range exprs {
+		pathExpr, err := ParseJSONPathExpr(expr)
+		c.Assert(err, IsNil)
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs
+}
+
+func (s *testJSONSuite) TestBinaryJSONMarshalUnmarshal(c *C) {
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{`{"b": [1, "2", {"c": null}], "a": true}`, `{"a": true, "b": [1, "2", {"c": null}]}`},
+		{`[1.5, -3, 18446744073709551615, false]`, `[1.5, -3, 18446744073709551615, false]`},
+		{`"a\tb\"c"`, `"a\tb\"c"`},
+		{`null`, `null`},
+		{`{}`, `{}`},
+		{`[]`, `[]`},
+		{`3`, `3`},
+	}
+	for _, tt := range tests {
+		bj := mustParseBinaryFromString(c, tt.input)
+		c.Assert(bj.String(), Equals, tt.output)
+		// The text output is parsed back to the same binary.
+		bj2 := mustParseBinaryFromString(c, bj.String())
+		c.Assert(bj2.TypeCode, Equals, bj.TypeCode)
+		c.Assert(bj2.Value, DeepEquals, bj.Value)
+
+		n, err := PeekBytesAsJSON(append(append([]byte{bj.TypeCode}, bj.Value...), 'x'))
+		c.Assert(err, IsNil)
+		c.Assert(n, Equals, len(bj.Value)+1)
+	}
+
+	for _, input := range []string{`{"a": 1`, `[1, 2] 3`, `abc`, ``, `[1]]`} {
+		_, err := ParseBinaryFromString(input)
+		c.Assert(ErrInvalidJSONText.Equal(err), IsTrue, Commentf("%s", input))
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONType(c *C) {
+	tests := []struct {
+		input string
+		tp    string
+	}{
+		{`{"a": 1}`, "OBJECT"},
+		{`[1]`, "ARRAY"},
+		{`null`, "NULL"},
+		{`true`, "BOOLEAN"},
+		{`-1`, "INTEGER"},
+		{`18446744073709551615`, "UNSIGNED INTEGER"},
+		{`1.5`, "DOUBLE"},
+		{`"a"`, "STRING"},
+	}
+	for _, tt := range tests {
+		c.Assert(mustParseBinaryFromString(c, tt.input).Type(), Equals, tt.tp)
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONExtract(c *C) {
+	bj := mustParseBinaryFromString(c, `{"a": [1, "2", {"aa": "bb"}, 4.5, {"aa": "cc"}], "b": true, "c": ["d"], "\"hello\"": "world"}`)
+	tests := []struct {
+		pathExprs []string
+		found     bool
+		output    string
+	}{
+		{[]string{"$"}, true, bj.String()},
+		{[]string{"$.a"}, true, `[1, "2", {"aa": "bb"}, 4.5, {"aa": "cc"}]`},
+		{[]string{"$.a[2].aa"}, true, `"bb"`},
+		{[]string{`$."\"hello\""`}, true, `"world"`},
+		{[]string{"$.b[0]"}, true, `true`},
+		{[]string{"$.b[1]"}, false, ``},
+		{[]string{"$.a[*].aa"}, true, `["bb", "cc"]`},
+		{[]string{"$.*[0]"}, true, `["world", 1, true, "d"]`},
+		{[]string{"$**.aa"}, true, `["bb", "cc"]`},
+		{[]string{"$.a[0]", "$.c"}, true, `[1, ["d"]]`},
+		{[]string{"$.d"}, false, ``},
+		{[]string{"$.a[0].aa"}, false, ``},
+	}
+	for _, tt := range tests {
+		res, found := bj.Extract(mustParseJSONPathExprs(c, tt.pathExprs...))
+		c.Assert(found, Equals, tt.found, Commentf("%v", tt.pathExprs))
+		if found {
+			c.Assert(res.String(), Equals, tt.output, Commentf("%v", tt.pathExprs))
+		}
+	}
+}
+
+func (s *testJSONSuite) TestBinaryJSONUnquote(c *C) {
+	c.Assert(mustParseBinaryFromString(c, `"a\tb"`).Unquote(), Equals, "a\tb")
+	c.Assert(mustParseBinaryFromString(c, `[1, "a"]`).Unquote(), Equals, `[1, "a"]`)
+
+	tests := []struct {
+		input  string
+		output string
+	}{
+		{`abc`, `abc`},
+		{`"abc"`, `abc`},
+		{`"a\"bé"`, `a"bé`},
+		{`"`, `"`},
+	}
+	for _, tt := range tests {
+		res, err := UnquoteString(tt.input)
+		c.Assert(err, IsNil)
+		c.Assert(res, Equals, tt.output)
+	}
+	_, err := UnquoteString(`"a\x"`)
+	c.Assert(ErrInvalidJSONText.Equal(err), IsTrue)
+}
+
+func (s *testJSONSuite) TestBinaryJSONModify(c *C) {
+	tests := []struct {
+		base   string
+		path   string
+		value  string
+		mt     ModifyType
+		output string
+	}{
+		{`null`, "$", `{}`, ModifySet, `{}`},
+		{`{}`, "$.a", `3`, ModifySet, `{"a": 3}`},
+		{`{"a": 3}`, "$.a", `[]`, ModifyReplace, `{"a": []}`},
+		{`{"a": 3}`, "$.b", `3`, ModifyReplace, `{"a": 3}`},
+		{`{"a": 3}`, "$.a", `4`, ModifyInsert, `{"a": 3}`},
+		{`{"a": []}`, "$.a[0]", `3`, ModifySet, `{"a": [3]}`},
+		{`{"a": [3]}`, "$.a[1]", `4`, ModifyInsert, `{"a": [3, 4]}`},
+		{`{"a": [3]}`, "$[0]", `4`, ModifySet, `4`},
+		{`{"a": [3]}`, "$[1]", `4`, ModifySet, `[{"a": [3]}, 4]`},
+		{`{"a": [3]}`, "$.b.c", `4`, ModifySet, `{"a": [3]}`},
+	}
+	for _, tt := range tests {
+		base := mustParseBinaryFromString(c, tt.base)
+		value := mustParseBinaryFromString(c, tt.value)
+		res, err := base.Modify(mustParseJSONPathExprs(c, tt.path), []BinaryJSON{value}, tt.mt)
+		c.Assert(err, IsNil)
+		c.Assert(res.String(), Equals, tt.output, Commentf("%s %s", tt.base, tt.path))
+		// The input isn't changed.
+		c.Assert(mustParseBinaryFromString(c, tt.base).Value, DeepEquals, base.Value)
+	}
+
+	base := mustParseBinaryFromString(c, `{"a": [3]}`)
+	_, err := base.Modify(mustParseJSONPathExprs(c, "$.a[*]"), []BinaryJSON{CreateBinary(int64(1))}, ModifySet)
+	c.Assert(ErrInvalidJSONPathWildcard.Equal(err), IsTrue)
+}
+
+func (s *testJSONSuite) TestBinaryJSONContains(c *C) {
+	tests := []struct {
+		obj      string
+		target   string
+		contains bool
+	}{
+		{`{"a": 1, "b": 2, "c": {"d": 4}}`, `1`, false},
+		{`{"a": 1, "b": 2, "c": {"d": 4}}`, `{"a": 1}`, true},
+		{`{"a": 1, "b": 2, "c": {"d": 4}}`, `{"a": 1, "c": {"d": 4}}`, true},
+		{`{"a": 1, "b": 2, "c": {"d": 4}}`, `{"a": 1, "c": {"d": 5}}`, false},
+		{`[1, [2, 3], "a"]`, `[3, 1]`, true},
+		{`[1, [2, 3], "a"]`, `"a"`, true},
+		{`[1, [2, 3], "a"]`, `[1, 4]`, false},
+		{`[1, 2]`, `1.0`, true},
+		{`"a"`, `["a"]`, false},
+	}
+	for _, tt := range tests {
+		obj := mustParseBinaryFromString(c, tt.obj)
+		target := mustParseBinaryFromString(c, tt.target)
+		c.Assert(ContainsBinary(obj, target), Equals, tt.contains, Commentf("%s %s", tt.obj, tt.target))
+	}
+}
+
+func (s *testJSONSuite) TestCompareBinary(c *C) {
+	tests := []struct {
+		left  string
+		right string
+		cmp   int
+	}{
+		{`1`, `1.0`, 0},
+		{`-1`, `18446744073709551615`, -1},
+		{`18446744073709551615`, `1.5`, 1},
+		{`"a"`, `"b"`, -1},
+		{`null`, `1`, -1},
+		{`false`, `true`, -1},
+		{`true`, `[1]`, 1},
+		{`[1, 2]`, `[1, 2, 3]`, -1},
+		{`[1, 3]`, `[1, 2, 3]`, 1},
+		{`{"a": 1}`, `{"a": 1}`, 0},
+	}
+	for _, tt := range tests {
+		left := mustParseBinaryFromString(c, tt.left)
+		right := mustParseBinaryFromString(c, tt.right)
+		c.Assert(CompareBinary(left, right), Equals, tt.cmp, Commentf("%s %s", tt.left, tt.right))
+		c.Assert(CompareBinary(right, left), Equals, -tt.cmp, Commentf("%s %s", tt.left, tt.right))
+	}
+}
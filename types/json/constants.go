@@ -0,0 +1,90 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/binary"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+)
+
+// TypeCode indicates JSON type.
+type TypeCode = byte
+
+const (
+	// TypeCodeObject indicates the JSON is an object.
+	TypeCodeObject TypeCode = 0x01
+	// TypeCodeArray indicates the JSON is an array.
+	TypeCodeArray TypeCode = 0x03
+	// TypeCodeLiteral indicates the JSON is a literal.
+	TypeCodeLiteral TypeCode = 0x04
+	// TypeCodeInt64 indicates the JSON is a signed integer.
+	TypeCodeInt64 TypeCode = 0x09
+	// TypeCodeUint64 indicates the JSON is a unsigned integer.
+	TypeCodeUint64 TypeCode = 0x0a
+	// TypeCodeFloat64 indicates the JSON is a double float number.
+	TypeCodeFloat64 TypeCode = 0x0b
+	// TypeCodeString indicates the JSON is a string.
+	TypeCodeString TypeCode = 0x0c
+)
+
+const (
+	// LiteralNil represents JSON null.
+	LiteralNil byte = 0x00
+	// LiteralTrue represents JSON true.
+	LiteralTrue byte = 0x01
+	// LiteralFalse represents JSON false.
+	LiteralFalse byte = 0x02
+)
+
+// The binary format of an object or an array is:
+//
+//	element-count(uint32) | size(uint32) | key-entries | value-entries | keys | values
+//
+// The key entry of an object is the offset(uint32) and the length(uint16) of the key, the keys are sorted.
+// The value entry is the type code(byte) and the offset(uint32) of the value, the literals are inlined
+// in the offset field. The offsets are relative to the beginning of the object or the array.
+const (
+	headerSize   = 8
+	dataSizeOff  = 4
+	keyEntrySize = 6
+	keyLenOff    = 4
+	valTypeSize  = 1
+	valEntrySize = 5
+)
+
+var jsonEndian = binary.LittleEndian
+
+const unknownTypeCodeErrorMsg = "unknown type code: %d"
+
+var (
+	// ErrInvalidJSONText means invalid JSON text.
+	ErrInvalidJSONText = terror.ClassJSON.New(mysql.ErrInvalidJSONText, mysql.MySQLErrName[mysql.ErrInvalidJSONText])
+	// ErrInvalidJSONPath means invalid JSON path.
+	ErrInvalidJSONPath = terror.ClassJSON.New(mysql.ErrInvalidJSONPath, mysql.MySQLErrName[mysql.ErrInvalidJSONPath])
+	// ErrInvalidJSONData means invalid JSON data.
+	ErrInvalidJSONData = terror.ClassJSON.New(mysql.ErrInvalidJSONData, mysql.MySQLErrName[mysql.ErrInvalidJSONData])
+	// ErrInvalidJSONPathWildcard means invalid JSON path that contain wildcard characters.
+	ErrInvalidJSONPathWildcard = terror.ClassJSON.New(mysql.ErrInvalidJSONPathWildcard, mysql.MySQLErrName[mysql.ErrInvalidJSONPathWildcard])
+)
+
+func init() {
+	terror.ErrClassToMySQLCodes[terror.ClassJSON] = map[terror.ErrCode]uint16{
+		mysql.ErrInvalidJSONText:         mysql.ErrInvalidJSONText,
+		mysql.ErrInvalidJSONPath:         mysql.ErrInvalidJSONPath,
+		mysql.ErrInvalidJSONData:         mysql.ErrInvalidJSONData,
+		mysql.ErrInvalidJSONPathWildcard: mysql.ErrInvalidJSONPathWildcard,
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+/*
+	From MySQL 5.7, JSON path expression grammar:
+		pathExpression ::= scope (pathLeg)*
+		scope ::= [ columnReference ] '$'
+		columnReference ::= // omit...
+		pathLeg ::= member | arrayLocation | '**'
+		member ::= '.' (keyName | '*')
+		arrayLocation ::= '[' (non-negative-integer | '*') ']'
+		keyName ::= ECMAScript-identifier | ECMAScript-string-literal
+
+	And some implementation limits in MySQL 5.7:
+		1) columnReference in scope must be empty now;
+		2) double asterisk(**) could not be last leg;
+*/
+
+type pathLegType byte
+
+const (
+	// pathLegKey indicates the path leg with '.key'.
+	pathLegKey pathLegType = 0x01
+	// pathLegIndex indicates the path leg with form '[number]'.
+	pathLegIndex pathLegType = 0x02
+	// pathLegDoubleAsterisk indicates the path leg with form '**'.
+	pathLegDoubleAsterisk pathLegType = 0x03
+)
+
+// pathLeg is only used by PathExpression.
+type pathLeg struct {
+	typ        pathLegType
+	arrayIndex int    // if typ is pathLegIndex, the value should be parsed into here.
+	dotKey     string // if typ is pathLegKey, the key should be parsed into here.
+}
+
+// arrayIndexAsterisk is for parsing `*` into a number.
+// we need this number represent "all".
+const arrayIndexAsterisk = -1
+
+// PathExpression is for JSON path expression.
+type PathExpression struct {
+	legs []pathLeg
+	// containsAsterisk is true if the path contains '*' or '**'.
+	containsAsterisk bool
+}
+
+// ContainsAnyAsterisk returns true if the path contains any asterisk.
+func (pe PathExpression) ContainsAnyAsterisk() bool {
+	return pe.containsAsterisk
+}
+
+// popOneLeg returns the first leg and the rest of the path.
+func (pe PathExpression) popOneLeg() (pathLeg, PathExpression) {
+	return pe.legs[0], PathExpression{legs: pe.legs[1:], containsAsterisk: pe.containsAsterisk}
+}
+
+// ParseJSONPathExpr parses a JSON path expression. Returns a PathExpression object which can be used in
+// JSON_EXTRACT, JSON_SET and so on.
+func ParseJSONPathExpr(pathExpr string) (PathExpression, error) {
+	var pe PathExpression
+	s := strings.TrimSpace(pathExpr)
+	if !strings.HasPrefix(s, "$") {
+		return pe, ErrInvalidJSONPath.GenWithStackByArgs(pathExpr)
+	}
+	s = strings.TrimLeft(s[1:], " \t")
+	for len(s) > 0 {
+		var leg pathLeg
+		var ok bool
+		switch {
+		case s[0] == '.':
+			leg, s, ok = parseKeyLeg(strings.TrimLeft(s[1:], " \t"))
+		case s[0] == '[':
+			leg, s, ok = parseIndexLeg(s[1:])
+		case strings.HasPrefix(s, "**"):
+			leg, s, ok = pathLeg{typ: pathLegDoubleAsterisk}, s[2:], true
+		}
+		if !ok {
+			return pe, ErrInvalidJSONPath.GenWithStackByArgs(pathExpr)
+		}
+		if leg.typ == pathLegDoubleAsterisk || leg.arrayIndex == arrayIndexAsterisk || leg.dotKey == "*" {
+			pe.containsAsterisk = true
+		}
+		pe.legs = append(pe.legs, leg)
+		s = strings.TrimLeft(s, " \t")
+	}
+	if len(pe.legs) > 0 && pe.legs[len(pe.legs)-1].typ == pathLegDoubleAsterisk {
+		return pe, ErrInvalidJSONPath.GenWithStackByArgs(pathExpr)
+	}
+	return pe, nil
+}
+
+// parseKeyLeg parses the key after '.', which is '*', an identifier or a quoted string.
+func parseKeyLeg(s string) (pathLeg, string, bool) {
+	leg := pathLeg{typ: pathLegKey}
+	if strings.HasPrefix(s, "*") {
+		leg.dotKey = "*"
+		return leg, s[1:], true
+	}
+	if strings.HasPrefix(s, `"`) {
+		end := 1
+		for ; end < len(s) && s[end] != '"'; end++ {
+			if s[end] == '\\' {
+				end++
+			}
+		}
+		if end >= len(s) || json.Unmarshal([]byte(s[:end+1]), &leg.dotKey) != nil {
+			return leg, s, false
+		}
+		return leg, s[end+1:], true
+	}
+	end := strings.IndexAny(s, ".[* \t\"")
+	if end < 0 {
+		end = len(s)
+	}
+	if end == 0 {
+		return leg, s, false
+	}
+	leg.dotKey = s[:end]
+	return leg, s[end:], true
+}
+
+// parseIndexLeg parses the '*' or the non-negative integer followed by ']'.
+func parseIndexLeg(s string) (pathLeg, string, bool) {
+	leg := pathLeg{typ: pathLegIndex}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return leg, s, false
+	}
+	index := strings.TrimSpace(s[:end])
+	if index == "*" {
+		leg.arrayIndex = arrayIndexAsterisk
+		return leg, s[end+1:], true
+	}
+	i, err := strconv.ParseUint(index, 10, 32)
+	if err != nil {
+		return leg, s, false
+	}
+	leg.arrayIndex = int(i)
+	return leg, s[end+1:], true
+}
@@ -0,0 +1,47 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	. "github.com/pingcap/check"
+)
+
+func (s *testJSONSuite) TestParseJSONPathExpr(c *C) {
+	tests := []struct {
+		expr             string
+		legs             []pathLeg
+		containsAsterisk bool
+	}{
+		{"$", nil, false},
+		{" $ ", nil, false},
+		{"$.a", []pathLeg{{typ: pathLegKey, dotKey: "a"}}, false},
+		{`$."a b"`, []pathLeg{{typ: pathLegKey, dotKey: "a b"}}, false},
+		{"$.a[1]", []pathLeg{{typ: pathLegKey, dotKey: "a"}, {typ: pathLegIndex, arrayIndex: 1}}, false},
+		{"$[ 2 ].b", []pathLeg{{typ: pathLegIndex, arrayIndex: 2}, {typ: pathLegKey, dotKey: "b"}}, false},
+		{"$.*", []pathLeg{{typ: pathLegKey, dotKey: "*"}}, true},
+		{"$[*]", []pathLeg{{typ: pathLegIndex, arrayIndex: arrayIndexAsterisk}}, true},
+		{"$**.a", []pathLeg{{typ: pathLegDoubleAsterisk}, {typ: pathLegKey, dotKey: "a"}}, true},
+	}
+	for _, tt := range tests {
+		pe, err := ParseJSONPathExpr(tt.expr)
+		c.Assert(err, IsNil, Commentf("%s", tt.expr))
+		c.Assert(pe.legs, DeepEquals, tt.legs, Commentf("%s", tt.expr))
+		c.Assert(pe.ContainsAnyAsterisk(), Equals, tt.containsAsterisk, Commentf("%s", tt.expr))
+	}
+
+	for _, expr := range []string{"", "a", "$.", "$[", "$[-1]", "$[a]", "$**", `$."a`, "$a"} {
+		_, err := ParseJSONPathExpr(expr)
+		c.Assert(ErrInvalidJSONPath.Equal(err), IsTrue, Commentf("%s", expr))
+	}
+}
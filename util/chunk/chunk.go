@@ -20,6 +20,7 @@ import (
 	"github.com/cznic/mathutil"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 )
 
 var msgErrSelNotNil = "The selection vector of Chunk is not nil. Please file a bug to the TiDB Team"
@@ -530,6 +531,12 @@ func (c *Chunk) AppendBytes(colIdx int, b []byte) {
 	c.columns[colIdx].AppendBytes(b)
 }
 
+// AppendJSON appends a JSON value to the chunk.
+func (c *Chunk) AppendJSON(colIdx int, j json.BinaryJSON) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendJSON(j)
+}
+
 func (c *Chunk) appendSel(colIdx int) {
 	if colIdx == 0 && c.sel != nil { // use column 0 as standard
 		c.sel = append(c.sel, c.columns[0].length)
@@ -551,6 +558,8 @@ func (c *Chunk) AppendDatum(colIdx int, d *types.Datum) {
 		c.AppendFloat64(colIdx, d.GetFloat64())
 	case types.KindString, types.KindBytes:
 		c.AppendBytes(colIdx, d.GetBytes())
+	case types.KindMysqlJSON:
+		c.AppendJSON(colIdx, d.GetMysqlJSON())
 	}
 }
 
@@ -20,6 +20,7 @@ import (
 	"unsafe"
 
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/hack"
 )
 
@@ -69,6 +70,8 @@ func (c *Column) Reset(eType types.EvalType) {
 		c.ResizeFloat64(0, false)
 	case types.ETString:
 		c.ReserveString(0)
+	case types.ETJson:
+		c.ReserveJSON(0)
 	default:
 		panic(fmt.Sprintf("invalid EvalType %v", eType))
 	}
@@ -205,6 +208,13 @@ func (c *Column) AppendBytes(b []byte) {
 	c.finishAppendVar()
 }
 
+// AppendJSON appends a BinaryJSON value into this Column.
+func (c *Column) AppendJSON(j json.BinaryJSON) {
+	c.data = append(c.data, j.TypeCode)
+	c.data = append(c.data, j.Value...)
+	c.finishAppendVar()
+}
+
 const (
 	sizeInt64   = int(unsafe.Sizeof(int64(0)))
 	sizeUint64  = int(unsafe.Sizeof(uint64(0)))
@@ -357,6 +367,11 @@ func (c *Column) ReserveBytes(n int) {
 	c.reserve(n, 8)
 }
 
+// ReserveJSON changes the column capacity to store n JSON elements and set the length to zero.
+func (c *Column) ReserveJSON(n int) {
+	c.reserve(n, 8)
+}
+
 // ReserveSet changes the column capacity to store n set elements and set the length to zero.
 func (c *Column) ReserveSet(n int) {
 	c.reserve(n, 8)
@@ -431,6 +446,12 @@ func (c *Column) GetBytes(rowID int) []byte {
 	return c.data[c.offsets[rowID]:c.offsets[rowID+1]]
 }
 
+// GetJSON returns the JSON in the specific row.
+func (c *Column) GetJSON(rowID int) json.BinaryJSON {
+	start := c.offsets[rowID]
+	return json.BinaryJSON{TypeCode: c.data[start], Value: c.data[start+1 : c.offsets[rowID+1]]}
+}
+
 // GetRaw returns the underlying raw bytes in the specific row.
 func (c *Column) GetRaw(rowID int) []byte {
 	var data []byte
@@ -18,6 +18,7 @@ import (
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 )
 
 // CompareFunc is a function to compare the two values in Row, the two columns must have the same type.
@@ -38,6 +39,8 @@ func GetCompareFunc(tp *types.FieldType) CompareFunc {
 	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
 		mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		return cmpString
+	case mysql.TypeJSON:
+		return cmpJSON
 	}
 	return nil
 }
@@ -92,6 +95,14 @@ func cmpFloat64(l Row, lCol int, r Row, rCol int) int {
 	return types.CompareFloat64(l.GetFloat64(lCol), r.GetFloat64(rCol))
 }
 
+func cmpJSON(l Row, lCol int, r Row, rCol int) int {
+	lNull, rNull := l.IsNull(lCol), r.IsNull(rCol)
+	if lNull || rNull {
+		return cmpNull(lNull, rNull)
+	}
+	return json.CompareBinary(l.GetJSON(lCol), r.GetJSON(rCol))
+}
+
 // Compare compares the value with ad.
 func Compare(row Row, colIdx int, ad *types.Datum) int {
 	switch ad.Kind() {
@@ -117,6 +128,8 @@ func Compare(row Row, colIdx int, ad *types.Datum) int {
 		return types.CompareFloat64(row.GetFloat64(colIdx), ad.GetFloat64())
 	case types.KindString, types.KindBytes:
 		return types.CompareString(row.GetString(colIdx), ad.GetString())
+	case types.KindMysqlJSON:
+		return json.CompareBinary(row.GetJSON(colIdx), ad.GetMysqlJSON())
 	default:
 		return 0
 	}
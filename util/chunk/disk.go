@@ -0,0 +1,209 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/types"
+)
+
+// ListInDisk holds a list of chunks in a temporary file on the disk. The chunks
+// can only be appended, and are read back by their indexes, the last chunk read
+// is cached because the rows are usually read chunk by chunk.
+type ListInDisk struct {
+	fieldTypes []*types.FieldType
+	codec      *Codec
+	// file is created when the first chunk is added.
+	file *os.File
+	// offsets[i] is the offset of the i-th chunk in the file, and the last
+	// element is the end of the file.
+	offsets []int64
+	length  int
+
+	// The cache may be read by many goroutines, e.g. the probe workers of hash join.
+	mu struct {
+		sync.Mutex
+		chkIdx int
+		chk    *Chunk
+	}
+}
+
+// NewListInDisk creates a new ListInDisk with field types.
+func NewListInDisk(fieldTypes []*types.FieldType) *ListInDisk {
+	l := &ListInDisk{
+		fieldTypes: fieldTypes,
+		codec:      NewCodec(fieldTypes),
+		offsets:    []int64{0},
+	}
+	l.mu.chkIdx = -1
+	return l
+}
+
+// Len returns the number of rows in the ListInDisk.
+func (l *ListInDisk) Len() int {
+	return l.length
+}
+
+// NumChunks returns the number of chunks in the ListInDisk.
+func (l *ListInDisk) NumChunks() int {
+	return len(l.offsets) - 1
+}
+
+// Add writes a chunk to the end of the file, the chunk can be reused after it.
+func (l *ListInDisk) Add(chk *Chunk) (err error) {
+	if chk.NumRows() == 0 {
+		return nil
+	}
+	if l.file == nil {
+		if l.file, err = ioutil.TempFile("", "tidb-spill-"); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	// The encoded chunk contains all the physical rows, so the selected rows are
+	// copied out first.
+	if chk.Sel() != nil {
+		compacted := NewChunkWithCapacity(l.fieldTypes, chk.NumRows())
+		for i := 0; i < chk.NumRows(); i++ {
+			compacted.AppendRow(chk.GetRow(i))
+		}
+		chk = compacted
+	}
+	data := l.codec.Encode(chk)
+	end := l.offsets[len(l.offsets)-1]
+	if _, err = l.file.WriteAt(data, end); err != nil {
+		return errors.Trace(err)
+	}
+	l.offsets = append(l.offsets, end+int64(len(data)))
+	l.length += chk.NumRows()
+	return nil
+}
+
+// size returns the bytes written to the file.
+func (l *ListInDisk) size() int64 {
+	return l.offsets[len(l.offsets)-1]
+}
+
+// readChunk reads the chunk at chkIdx from the file, it can be called concurrently.
+func (l *ListInDisk) readChunk(chkIdx int) (*Chunk, error) {
+	data := make([]byte, l.offsets[chkIdx+1]-l.offsets[chkIdx])
+	if _, err := l.file.ReadAt(data, l.offsets[chkIdx]); err != nil {
+		return nil, errors.Trace(err)
+	}
+	chk, _ := l.codec.Decode(data)
+	return chk, nil
+}
+
+// GetChunk reads the chunk at chkIdx from the file.
+func (l *ListInDisk) GetChunk(chkIdx int) (*Chunk, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.chkIdx == chkIdx {
+		return l.mu.chk, nil
+	}
+	chk, err := l.readChunk(chkIdx)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.chkIdx, l.mu.chk = chkIdx, chk
+	return chk, nil
+}
+
+// GetRow reads the chunk holding the row pointed by ptr, and returns the row.
+func (l *ListInDisk) GetRow(ptr RowPtr) (Row, error) {
+	chk, err := l.GetChunk(int(ptr.ChkIdx))
+	if err != nil {
+		return Row{}, err
+	}
+	return chk.GetRow(int(ptr.RowIdx)), nil
+}
+
+// GetRowWithCache is like GetRow, but the chunk is read by the cache of the caller instead
+// of the shared one, so the goroutines reading the rows at random don't wait for each other.
+func (l *ListInDisk) GetRowWithCache(ptr RowPtr, cache *DiskChunkCache) (Row, error) {
+	chkIdx := int(ptr.ChkIdx)
+	chk := cache.get(chkIdx)
+	if chk == nil {
+		var err error
+		if chk, err = l.readChunk(chkIdx); err != nil {
+			return Row{}, err
+		}
+		cache.put(chkIdx, chk)
+	}
+	return chk.GetRow(int(ptr.RowIdx)), nil
+}
+
+// Close removes the temporary file.
+func (l *ListInDisk) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	name := l.file.Name()
+	err := l.file.Close()
+	if removeErr := os.Remove(name); err == nil {
+		err = removeErr
+	}
+	l.file = nil
+	return errors.Trace(err)
+}
+
+// DiskChunkCache keeps the chunks recently read from a ListInDisk by a goroutine. The rows
+// read at random, like the build side rows matched by the probe workers of hash join, are
+// read from the file and decoded again whenever their chunk is not cached, so caching
+// several chunks saves most of the reads. It's not thread-safe, every goroutine should have
+// its own one.
+type DiskChunkCache struct {
+	capacity int
+	// chkIdxes and chunks are ordered from the most recently used one.
+	chkIdxes []int
+	chunks   []*Chunk
+}
+
+// NewDiskChunkCache creates a DiskChunkCache keeping at most capacity chunks.
+func NewDiskChunkCache(capacity int) *DiskChunkCache {
+	return &DiskChunkCache{
+		capacity: capacity,
+		chkIdxes: make([]int, 0, capacity),
+		chunks:   make([]*Chunk, 0, capacity),
+	}
+}
+
+// get returns the cached chunk at chkIdx and marks it as the most recently used one, or nil
+// if it's not cached.
+func (c *DiskChunkCache) get(chkIdx int) *Chunk {
+	for i, idx := range c.chkIdxes {
+		if idx == chkIdx {
+			chk := c.chunks[i]
+			copy(c.chkIdxes[1:i+1], c.chkIdxes[:i])
+			copy(c.chunks[1:i+1], c.chunks[:i])
+			c.chkIdxes[0], c.chunks[0] = chkIdx, chk
+			return chk
+		}
+	}
+	return nil
+}
+
+// put caches the chunk at chkIdx, the least recently used chunk is evicted if the cache is full.
+func (c *DiskChunkCache) put(chkIdx int, chk *Chunk) {
+	if len(c.chunks) < c.capacity {
+		c.chkIdxes = append(c.chkIdxes, 0)
+		c.chunks = append(c.chunks, nil)
+	}
+	copy(c.chkIdxes[1:], c.chkIdxes[:len(c.chkIdxes)-1])
+	copy(c.chunks[1:], c.chunks[:len(c.chunks)-1])
+	c.chkIdxes[0], c.chunks[0] = chkIdx, chk
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/hack"
 )
 
@@ -61,7 +62,16 @@ func MutRowFromDatums(datums []types.Datum) MutRow {
 func MutRowFromTypes(types []*types.FieldType) MutRow {
 	c := &Chunk{columns: make([]*Column, 0, len(types))}
 	for _, tp := range types {
-		col := makeMutRowColumn(zeroValForType(tp))
+		val := zeroValForType(tp)
+		var col *Column
+		if val == nil && getFixedLen(tp) == varElemLen {
+			// Keep the column layout consistent with the var-length column
+			// of a Chunk, so the row can be appended to it.
+			col = newMutRowVarLenColumn(0)
+			col.nullBitmap[0] = 0
+		} else {
+			col = makeMutRowColumn(val)
+		}
 		c.columns = append(c.columns, col)
 	}
 	return MutRow{c: c, idx: 0}
@@ -82,6 +92,8 @@ func zeroValForType(tp *types.FieldType) interface{} {
 		return ""
 	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		return []byte{}
+	case mysql.TypeJSON:
+		return json.CreateBinary(nil)
 	default:
 		return nil
 	}
@@ -109,6 +121,8 @@ func makeMutRowColumn(in interface{}) *Column {
 		return makeMutRowBytesColumn(hack.Slice(x))
 	case []byte:
 		return makeMutRowBytesColumn(x)
+	case json.BinaryJSON:
+		return makeMutRowBytesColumn(append([]byte{x.TypeCode}, x.Value...))
 	default:
 		return nil
 	}
@@ -198,6 +212,8 @@ func (mr MutRow) SetValue(colIdx int, val interface{}) {
 		setMutRowBytes(col, hack.Slice(x))
 	case []byte:
 		setMutRowBytes(col, x)
+	case json.BinaryJSON:
+		setMutRowBytes(col, append([]byte{x.TypeCode}, x.Value...))
 	}
 	col.nullBitmap[0] = 1
 }
@@ -223,6 +239,9 @@ func (mr MutRow) SetDatum(colIdx int, d types.Datum) {
 		binary.LittleEndian.PutUint32(mr.c.columns[colIdx].data, math.Float32bits(d.GetFloat32()))
 	case types.KindString, types.KindBytes:
 		setMutRowBytes(col, d.GetBytes())
+	case types.KindMysqlJSON:
+		j := d.GetMysqlJSON()
+		setMutRowBytes(col, append([]byte{j.TypeCode}, j.Value...))
 	default:
 		mr.c.columns[colIdx] = makeMutRowColumn(d.GetValue())
 	}
@@ -18,6 +18,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"testing"
 )
 
@@ -63,6 +64,7 @@ var allTypes = []*types.FieldType{
 		Elems:   []string{"a", "b"},
 	},
 	types.NewFieldType(mysql.TypeBit),
+	types.NewFieldType(mysql.TypeJSON),
 }
 
 func (s *testChunkSuite) TestMutRow(c *check.C) {
@@ -107,6 +109,17 @@ func (s *testChunkSuite) TestMutRow(c *check.C) {
 	row = mutRow.ToRow()
 	c.Assert(row.IsNull(0), check.IsTrue)
 	c.Assert(row.IsNull(1), check.IsFalse)
+
+	j, err := json.ParseBinaryFromString(`{"a": [1, "b"]}`)
+	c.Assert(err, check.IsNil)
+	mutRow = MutRowFromValues(j)
+	row = mutRow.ToRow()
+	c.Assert(row.GetJSON(0).String(), check.Equals, `{"a": [1, "b"]}`)
+	mutRow.SetDatums(types.NewDatum(json.CreateBinary("abc")))
+	c.Assert(row.GetJSON(0).String(), check.Equals, `"abc"`)
+	d := row.GetDatum(0, types.NewFieldType(mysql.TypeJSON))
+	c.Assert(d.Kind(), check.Equals, types.KindMysqlJSON)
+	c.Assert(d.GetMysqlJSON().String(), check.Equals, `"abc"`)
 }
 
 func BenchmarkMutRowSetDatums(b *testing.B) {
@@ -174,6 +187,27 @@ func (s *testChunkSuite) TestMutRowShallowCopyPartialRow(c *check.C) {
 	c.Assert(row.GetInt64(1), check.Equals, mutRow.ToRow().GetInt64(1))
 }
 
+func (s *testChunkSuite) TestMutRowAppendToChunk(c *check.C) {
+	colTypes := []*types.FieldType{
+		types.NewFieldType(mysql.TypeVarchar),
+		types.NewFieldType(mysql.TypeDatetime),
+		types.NewFieldType(mysql.TypeLonglong),
+	}
+	mutRow := MutRowFromTypes(colTypes)
+	chk := NewChunkWithCapacity(colTypes, 2)
+	mutRow.SetDatums(types.NewStringDatum("abc"), types.Datum{}, types.NewIntDatum(1))
+	chk.AppendRow(mutRow.ToRow())
+	mutRow.SetDatums(types.NewStringDatum("defg"), types.Datum{}, types.NewIntDatum(2))
+	chk.AppendRow(mutRow.ToRow())
+
+	c.Assert(chk.NumRows(), check.Equals, 2)
+	result := NewChunkWithCapacity(colTypes, 2)
+	result.AppendRow(chk.GetRow(1))
+	c.Assert(result.GetRow(0).GetString(0), check.Equals, "defg")
+	c.Assert(result.GetRow(0).IsNull(1), check.IsTrue)
+	c.Assert(result.GetRow(0).GetInt64(2), check.Equals, int64(2))
+}
+
 var rowsNum = 1024
 
 func BenchmarkMutRowShallowCopyPartialRow(b *testing.B) {
@@ -16,6 +16,7 @@ package chunk
 import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 )
 
 // Row represents a row of data, can be used to access values.
@@ -74,6 +75,11 @@ func (r Row) GetBytes(colIdx int) []byte {
 	return r.c.columns[colIdx].GetBytes(r.idx)
 }
 
+// GetJSON returns the JSON value with the colIdx.
+func (r Row) GetJSON(colIdx int) json.BinaryJSON {
+	return r.c.columns[colIdx].GetJSON(r.idx)
+}
+
 // GetDatumRow converts chunk.Row to types.DatumRow.
 // Keep in mind that GetDatumRow has a reference to r.c, which is a chunk,
 // this function works only if the underlying chunk is valid or unchanged.
@@ -116,6 +122,10 @@ func (r Row) GetDatum(colIdx int, tp *types.FieldType) types.Datum {
 		if !r.IsNull(colIdx) {
 			d.SetBytes(r.GetBytes(colIdx))
 		}
+	case mysql.TypeJSON:
+		if !r.IsNull(colIdx) {
+			d.SetMysqlJSON(r.GetJSON(colIdx))
+		}
 	}
 	return d
 }
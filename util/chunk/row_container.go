@@ -0,0 +1,342 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+import (
+	"sort"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/types"
+)
+
+// RowContainer holds the rows of an operator in memory, and transparently spills
+// them to a temporary file on the disk when its memory consumer reports that the
+// memory quota is exceeded. It is shared by the operators which have to hold all
+// their input rows, like Sort, HashJoin and Apply.
+//
+// The rows are spilled in the order they are added, so the RowPtrs returned
+// before the spilling stay valid, unless a sort function is set by SetSortBeforeSpill.
+type RowContainer struct {
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+
+	records *List
+	// memUsage is the memory held by records, which has been reported to consume.
+	memUsage int64
+	// consume reports the changes of the memory usage, a non-nil error means
+	// the memory quota is exceeded.
+	consume func(bytes int64) error
+	// consumeDisk reports the bytes written to the disk.
+	consumeDisk func(bytes int64)
+	// less sorts the rows before they are spilled if it's not nil.
+	less func(rowI, rowJ Row) bool
+
+	recordsInDisk *ListInDisk
+	// pending buffers the rows appended one by one after the spilling, it's
+	// written to the disk when it's full.
+	pending *Chunk
+}
+
+// NewRowContainer creates a new RowContainer in memory.
+func NewRowContainer(fieldTypes []*types.FieldType, initChunkSize, maxChunkSize int) *RowContainer {
+	return &RowContainer{
+		fieldTypes:   fieldTypes,
+		maxChunkSize: maxChunkSize,
+		records:      NewList(fieldTypes, initChunkSize, maxChunkSize),
+	}
+}
+
+// SetMemoryConsumer sets the function to report the changes of the memory usage,
+// the container spills to the disk once it returns an error.
+func (c *RowContainer) SetMemoryConsumer(consume func(bytes int64) error) {
+	c.consume = consume
+}
+
+// SetDiskConsumer sets the function to report the bytes written to the disk after the spilling.
+func (c *RowContainer) SetDiskConsumer(consume func(bytes int64)) {
+	c.consumeDisk = consume
+}
+
+// SetSortBeforeSpill makes the container sort its rows by less before spilling,
+// so a spilled container holds a sorted run. The RowPtrs returned before the
+// spilling are invalid after it.
+func (c *RowContainer) SetSortBeforeSpill(less func(rowI, rowJ Row) bool) {
+	c.less = less
+}
+
+// AlreadySpilled indicates whether the rows are in the disk.
+func (c *RowContainer) AlreadySpilled() bool {
+	return c.recordsInDisk != nil
+}
+
+// Len returns the number of rows in the container.
+func (c *RowContainer) Len() int {
+	if c.AlreadySpilled() {
+		return c.recordsInDisk.Len() + c.pending.NumRows()
+	}
+	return c.records.Len()
+}
+
+// NumChunks returns the number of chunks in the container.
+func (c *RowContainer) NumChunks() int {
+	if c.AlreadySpilled() {
+		if c.pending.NumRows() > 0 {
+			return c.recordsInDisk.NumChunks() + 1
+		}
+		return c.recordsInDisk.NumChunks()
+	}
+	return c.records.NumChunks()
+}
+
+// Add adds a chunk to the container, the chunk should not be used any more.
+func (c *RowContainer) Add(chk *Chunk) error {
+	if chk.NumRows() == 0 {
+		return nil
+	}
+	if c.AlreadySpilled() {
+		if err := c.flushPending(); err != nil {
+			return err
+		}
+		return c.addToDisk(chk)
+	}
+	c.records.Add(chk)
+	return c.consumeMemory(chk.MemoryUsage())
+}
+
+// AppendRow appends a row to the container, the row is copied.
+func (c *RowContainer) AppendRow(row Row) (RowPtr, error) {
+	if c.AlreadySpilled() {
+		if c.pending.NumRows() >= c.maxChunkSize {
+			if err := c.flushPending(); err != nil {
+				return RowPtr{}, err
+			}
+		}
+		ptr := RowPtr{ChkIdx: uint32(c.recordsInDisk.NumChunks()), RowIdx: uint32(c.pending.NumRows())}
+		c.pending.AppendRow(row)
+		return ptr, nil
+	}
+	numChunks := c.records.NumChunks()
+	ptr := c.records.AppendRow(row)
+	// The memory of a chunk is reported once it's full.
+	if numChunks > 0 && c.records.NumChunks() > numChunks {
+		return ptr, c.consumeMemory(c.records.GetChunk(numChunks - 1).MemoryUsage())
+	}
+	return ptr, nil
+}
+
+// GetChunk returns the chunk at chkIdx.
+func (c *RowContainer) GetChunk(chkIdx int) (*Chunk, error) {
+	if !c.AlreadySpilled() {
+		return c.records.GetChunk(chkIdx), nil
+	}
+	if chkIdx == c.recordsInDisk.NumChunks() {
+		return c.pending, nil
+	}
+	return c.recordsInDisk.GetChunk(chkIdx)
+}
+
+// GetRowWithCache is like GetRow, but the spilled rows are read by the cache of the caller, it
+// should be used by the goroutines reading the rows concurrently at random.
+func (c *RowContainer) GetRowWithCache(ptr RowPtr, cache *DiskChunkCache) (Row, error) {
+	if cache == nil || !c.AlreadySpilled() || int(ptr.ChkIdx) == c.recordsInDisk.NumChunks() {
+		return c.GetRow(ptr)
+	}
+	return c.recordsInDisk.GetRowWithCache(ptr, cache)
+}
+
+// GetRow returns the row pointed by ptr.
+func (c *RowContainer) GetRow(ptr RowPtr) (Row, error) {
+	if !c.AlreadySpilled() {
+		return c.records.GetRow(ptr), nil
+	}
+	if int(ptr.ChkIdx) == c.recordsInDisk.NumChunks() {
+		return c.pending.GetRow(int(ptr.RowIdx)), nil
+	}
+	return c.recordsInDisk.GetRow(ptr)
+}
+
+func (c *RowContainer) consumeMemory(bytes int64) error {
+	if c.consume == nil {
+		return nil
+	}
+	c.memUsage += bytes
+	// The chunks without columns only have virtual rows which can't be encoded.
+	if err := c.consume(bytes); err != nil && len(c.fieldTypes) > 0 {
+		return c.SpillToDisk()
+	}
+	return nil
+}
+
+// SpillToDisk writes all the rows in memory to the disk and releases their memory.
+func (c *RowContainer) SpillToDisk() error {
+	if c.AlreadySpilled() {
+		return nil
+	}
+	c.recordsInDisk = NewListInDisk(c.fieldTypes)
+	c.pending = New(c.fieldTypes, c.maxChunkSize, c.maxChunkSize)
+	var err error
+	if c.less != nil {
+		err = c.spillSortedRows()
+	} else {
+		for i := 0; i < c.records.NumChunks() && err == nil; i++ {
+			err = c.addToDisk(c.records.GetChunk(i))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	c.records.Reset()
+	c.releaseMemory()
+	return nil
+}
+
+func (c *RowContainer) spillSortedRows() error {
+	rows := make([]Row, 0, c.records.Len())
+	for i := 0; i < c.records.NumChunks(); i++ {
+		chk := c.records.GetChunk(i)
+		for j := 0; j < chk.NumRows(); j++ {
+			rows = append(rows, chk.GetRow(j))
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return c.less(rows[i], rows[j])
+	})
+	for _, row := range rows {
+		if _, err := c.AppendRow(row); err != nil {
+			return err
+		}
+	}
+	return c.flushPending()
+}
+
+func (c *RowContainer) flushPending() error {
+	if c.pending.NumRows() == 0 {
+		return nil
+	}
+	if err := c.addToDisk(c.pending); err != nil {
+		return err
+	}
+	c.pending.Reset()
+	return nil
+}
+
+func (c *RowContainer) addToDisk(chk *Chunk) error {
+	size := c.recordsInDisk.size()
+	if err := c.recordsInDisk.Add(chk); err != nil {
+		return err
+	}
+	if c.consumeDisk != nil {
+		c.consumeDisk(c.recordsInDisk.size() - size)
+	}
+	return nil
+}
+
+func (c *RowContainer) releaseMemory() {
+	if c.consume != nil && c.memUsage != 0 {
+		// Releasing memory never fails the quota.
+		_ = c.consume(-c.memUsage)
+	}
+	c.memUsage = 0
+}
+
+// Reset removes all the rows, the container goes back to the memory if it's spilled.
+func (c *RowContainer) Reset() error {
+	err := c.closeDisk()
+	c.records.Reset()
+	c.releaseMemory()
+	return err
+}
+
+// Close releases the memory and removes the temporary file if the rows are spilled.
+func (c *RowContainer) Close() error {
+	return c.Reset()
+}
+
+func (c *RowContainer) closeDisk() error {
+	if !c.AlreadySpilled() {
+		return nil
+	}
+	err := c.recordsInDisk.Close()
+	c.recordsInDisk, c.pending = nil, nil
+	return errors.Trace(err)
+}
+
+// Iterator4RowContainer iterates the rows of a RowContainer chunk by chunk. Since
+// reading the spilled rows may fail, the caller should check Error after iterating.
+type Iterator4RowContainer struct {
+	c         *RowContainer
+	chk       *Chunk
+	chkCursor int
+	rowCursor int
+	cur       Row
+	err       error
+}
+
+// NewIterator4RowContainer returns an Iterator for RowContainer.
+func NewIterator4RowContainer(c *RowContainer) *Iterator4RowContainer {
+	return &Iterator4RowContainer{c: c}
+}
+
+// Begin implements the Iterator interface.
+func (it *Iterator4RowContainer) Begin() Row {
+	it.chk, it.chkCursor, it.rowCursor, it.err = nil, 0, 0, nil
+	return it.Next()
+}
+
+// Next implements the Iterator interface.
+func (it *Iterator4RowContainer) Next() Row {
+	for it.chk == nil || it.rowCursor >= it.chk.NumRows() {
+		if it.err != nil || it.chkCursor >= it.c.NumChunks() {
+			it.ReachEnd()
+			return it.cur
+		}
+		it.chk, it.err = it.c.GetChunk(it.chkCursor)
+		if it.err != nil {
+			it.ReachEnd()
+			return it.cur
+		}
+		it.chkCursor++
+		it.rowCursor = 0
+	}
+	it.cur = it.chk.GetRow(it.rowCursor)
+	it.rowCursor++
+	return it.cur
+}
+
+// Current implements the Iterator interface.
+func (it *Iterator4RowContainer) Current() Row {
+	return it.cur
+}
+
+// End implements the Iterator interface.
+func (it *Iterator4RowContainer) End() Row {
+	return Row{}
+}
+
+// ReachEnd implements the Iterator interface.
+func (it *Iterator4RowContainer) ReachEnd() {
+	it.cur = it.End()
+	it.chkCursor = it.c.NumChunks()
+	it.chk = nil
+}
+
+// Len implements the Iterator interface.
+func (it *Iterator4RowContainer) Len() int {
+	return it.c.Len()
+}
+
+// Error returns the error met when reading the spilled rows.
+func (it *Iterator4RowContainer) Error() error {
+	return it.err
+}
@@ -0,0 +1,168 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chunk
+
+import (
+	"errors"
+	"os"
+
+	"github.com/pingcap/check"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+func newRowContainerTestChunk(fields []*types.FieldType, begin, end int) *Chunk {
+	chk := NewChunkWithCapacity(fields, end-begin)
+	for i := begin; i < end; i++ {
+		chk.AppendInt64(0, int64(i))
+		chk.AppendString(1, string(rune('a'+i%26)))
+	}
+	return chk
+}
+
+func (s *testChunkSuite) TestRowContainer(c *check.C) {
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar)}
+	rc := NewRowContainer(fields, 4, 4)
+	var quota, usage int64 = 1 << 30, 0
+	rc.SetMemoryConsumer(func(bytes int64) error {
+		usage += bytes
+		if usage > quota {
+			return errors.New("exceeded")
+		}
+		return nil
+	})
+
+	// The rows stay in memory while the quota is enough.
+	c.Assert(rc.Add(newRowContainerTestChunk(fields, 0, 4)), check.IsNil)
+	c.Assert(rc.AlreadySpilled(), check.IsFalse)
+	c.Assert(usage, check.Greater, int64(0))
+
+	// The rows are spilled in the order they are added, so the RowPtrs are still valid.
+	quota = usage
+	c.Assert(rc.Add(newRowContainerTestChunk(fields, 4, 8)), check.IsNil)
+	c.Assert(rc.AlreadySpilled(), check.IsTrue)
+	c.Assert(usage, check.Equals, int64(0))
+	file := rc.recordsInDisk.file.Name()
+	_, err := os.Stat(file)
+	c.Assert(err, check.IsNil)
+	ptr, err := rc.AppendRow(newRowContainerTestChunk(fields, 8, 9).GetRow(0))
+	c.Assert(err, check.IsNil)
+	c.Assert(ptr, check.Equals, RowPtr{ChkIdx: 2, RowIdx: 0})
+	c.Assert(rc.Add(newRowContainerTestChunk(fields, 9, 10)), check.IsNil)
+	c.Assert(rc.Len(), check.Equals, 10)
+	c.Assert(rc.NumChunks(), check.Equals, 4)
+	row, err := rc.GetRow(RowPtr{ChkIdx: 1, RowIdx: 2})
+	c.Assert(err, check.IsNil)
+	c.Assert(row.GetInt64(0), check.Equals, int64(6))
+	c.Assert(row.GetString(1), check.Equals, "g")
+	row, err = rc.GetRow(ptr)
+	c.Assert(err, check.IsNil)
+	c.Assert(row.GetInt64(0), check.Equals, int64(8))
+
+	it := NewIterator4RowContainer(rc)
+	c.Assert(it.Len(), check.Equals, 10)
+	i := int64(0)
+	for row := it.Begin(); row != it.End(); row = it.Next() {
+		c.Assert(row.GetInt64(0), check.Equals, i)
+		c.Assert(it.Current(), check.Equals, row)
+		i++
+	}
+	c.Assert(it.Error(), check.IsNil)
+	c.Assert(i, check.Equals, int64(10))
+
+	// Reset removes the temporary file and goes back to memory.
+	c.Assert(rc.Reset(), check.IsNil)
+	c.Assert(rc.AlreadySpilled(), check.IsFalse)
+	c.Assert(rc.Len(), check.Equals, 0)
+	_, err = os.Stat(file)
+	c.Assert(os.IsNotExist(err), check.IsTrue)
+	it = NewIterator4RowContainer(rc)
+	c.Assert(it.Begin(), check.Equals, it.End())
+	c.Assert(rc.Close(), check.IsNil)
+}
+
+func (s *testChunkSuite) TestRowContainerSortBeforeSpill(c *check.C) {
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar)}
+	rc := NewRowContainer(fields, 3, 3)
+	rc.SetSortBeforeSpill(func(rowI, rowJ Row) bool {
+		return rowI.GetInt64(0) > rowJ.GetInt64(0)
+	})
+	chk := newRowContainerTestChunk(fields, 0, 8)
+	// The selected rows are spilled only.
+	chk.SetSel([]int{1, 3, 5, 4, 7})
+	c.Assert(rc.Add(chk), check.IsNil)
+	c.Assert(rc.SpillToDisk(), check.IsNil)
+	c.Assert(rc.NumChunks(), check.Equals, 2)
+	var got []int64
+	it := NewIterator4RowContainer(rc)
+	for row := it.Begin(); row != it.End(); row = it.Next() {
+		got = append(got, row.GetInt64(0))
+	}
+	c.Assert(it.Error(), check.IsNil)
+	c.Assert(got, check.DeepEquals, []int64{7, 5, 4, 3, 1})
+	c.Assert(rc.Close(), check.IsNil)
+}
+
+func (s *testChunkSuite) TestRowContainerGetRowWithCache(c *check.C) {
+	fields := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeVarchar)}
+	rc := NewRowContainer(fields, 2, 2)
+	var spilled int64
+	rc.SetDiskConsumer(func(bytes int64) {
+		spilled += bytes
+	})
+	for i := 0; i < 5; i++ {
+		c.Assert(rc.Add(newRowContainerTestChunk(fields, i*2, i*2+2)), check.IsNil)
+	}
+	// The rows in memory don't need the cache.
+	row, err := rc.GetRowWithCache(RowPtr{ChkIdx: 1, RowIdx: 1}, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(row.GetInt64(0), check.Equals, int64(3))
+	c.Assert(spilled, check.Equals, int64(0))
+
+	c.Assert(rc.SpillToDisk(), check.IsNil)
+	c.Assert(spilled, check.Equals, rc.recordsInDisk.size())
+	c.Assert(spilled, check.Greater, int64(0))
+	_, err = rc.AppendRow(newRowContainerTestChunk(fields, 10, 11).GetRow(0))
+	c.Assert(err, check.IsNil)
+
+	cache := NewDiskChunkCache(2)
+	for _, ptr := range []RowPtr{{ChkIdx: 3, RowIdx: 0}, {ChkIdx: 0, RowIdx: 1}, {ChkIdx: 3, RowIdx: 1}, {ChkIdx: 4, RowIdx: 0}, {ChkIdx: 5, RowIdx: 0}} {
+		row, err = rc.GetRowWithCache(ptr, cache)
+		c.Assert(err, check.IsNil)
+		c.Assert(row.GetInt64(0), check.Equals, int64(ptr.ChkIdx*2+ptr.RowIdx))
+	}
+	// The chunk 0 is evicted as the least recently used one, the pending rows are not cached.
+	c.Assert(cache.chkIdxes, check.DeepEquals, []int{4, 3})
+	c.Assert(rc.Close(), check.IsNil)
+}
+
+func (s *testChunkSuite) TestDiskChunkCache(c *check.C) {
+	cache := NewDiskChunkCache(3)
+	chunks := make([]*Chunk, 5)
+	for i := range chunks {
+		chunks[i] = NewChunkWithCapacity(nil, 0)
+	}
+	c.Assert(cache.get(0), check.IsNil)
+	cache.put(0, chunks[0])
+	cache.put(1, chunks[1])
+	cache.put(2, chunks[2])
+	c.Assert(cache.get(0), check.Equals, chunks[0])
+	c.Assert(cache.chkIdxes, check.DeepEquals, []int{0, 2, 1})
+	cache.put(3, chunks[3])
+	c.Assert(cache.chkIdxes, check.DeepEquals, []int{3, 0, 2})
+	c.Assert(cache.get(1), check.IsNil)
+	c.Assert(cache.get(2), check.Equals, chunks[2])
+	c.Assert(cache.chkIdxes, check.DeepEquals, []int{2, 3, 0})
+	c.Assert(cache.chunks, check.DeepEquals, []*Chunk{chunks[2], chunks[3], chunks[0]})
+}
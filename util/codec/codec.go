@@ -26,7 +26,9 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
 )
 
 // First byte in the encoded value which specifies the encoding type.
@@ -45,6 +47,15 @@ const (
 	maxFlag          byte = 250
 )
 
+// The flags below are used by the values of descending index columns, they are sorted
+// in the reverse order of their ascending counterparts.
+const (
+	descMaxFlag        byte = 240
+	descFlag           byte = 241
+	descMinNotNullFlag byte = 242
+	descNilFlag        byte = 243
+)
+
 const (
 	sizeUint64  = unsafe.Sizeof(uint64(0))
 	sizeFloat64 = unsafe.Sizeof(float64(0))
@@ -86,6 +97,10 @@ func encode(sc *stmtctx.StatementContext, b []byte, vals []types.Datum, comparab
 			b = encodeBytes(b, vals[i].GetBytes(), comparable)
 		case types.KindNull:
 			b = append(b, NilFlag)
+		case types.KindMysqlJSON:
+			j := vals[i].GetMysqlJSON()
+			b = append(b, jsonFlag, j.TypeCode)
+			b = append(b, j.Value...)
 		case types.KindMinNotNull:
 			b = append(b, bytesFlag)
 		case types.KindMaxValue:
@@ -110,6 +125,8 @@ func EstimateValueSize(sc *stmtctx.StatementContext, val types.Datum) (int, erro
 		l = 9
 	case types.KindString, types.KindBytes:
 		l = valueSizeOfBytes(val.GetBytes())
+	case types.KindMysqlJSON:
+		l = 2 + len(val.GetMysqlJSON().Value)
 	case types.KindNull, types.KindMinNotNull, types.KindMaxValue:
 		l = 1
 	default:
@@ -203,6 +220,61 @@ func EncodeKey(sc *stmtctx.StatementContext, b []byte, v ...types.Datum) ([]byte
 	return encode(sc, b, v, true)
 }
 
+// EncodeKeyWithDesc appends the encoded values to byte slice b like EncodeKey, but the
+// i-th value is encoded in descending order if descs[i] is true, that is, the encoded
+// bytes of a descending value are sorted in the reverse order of the value.
+func EncodeKeyWithDesc(sc *stmtctx.StatementContext, b []byte, descs []bool, v ...types.Datum) ([]byte, error) {
+	if len(descs) == 0 {
+		return EncodeKey(sc, b, v...)
+	}
+	var err error
+	for i := range v {
+		if i < len(descs) && descs[i] {
+			b, err = encodeDesc(sc, b, v[i])
+		} else {
+			b, err = encode(sc, b, v[i:i+1], true)
+		}
+		if err != nil {
+			return b, errors.Trace(err)
+		}
+	}
+	return b, nil
+}
+
+// encodeDesc encodes a datum in descending order. The value is encoded with descFlag
+// followed by the reversed bytes of its comparable encoding.
+func encodeDesc(sc *stmtctx.StatementContext, b []byte, v types.Datum) ([]byte, error) {
+	switch v.Kind() {
+	case types.KindNull:
+		return append(b, descNilFlag), nil
+	case types.KindMinNotNull:
+		return append(b, descMinNotNullFlag), nil
+	case types.KindMaxValue:
+		return append(b, descMaxFlag), nil
+	}
+	b = append(b, descFlag)
+	l := len(b)
+	b, err := encode(sc, b, []types.Datum{v}, true)
+	if err != nil {
+		return b, errors.Trace(err)
+	}
+	reverseBytes(b[l:])
+	return b, nil
+}
+
+// cutDesc cuts the first descending encoded value from b, which doesn't contain the descFlag.
+// It returns the value in ascending encoding and the remains as byte slice.
+func cutDesc(b []byte) (data []byte, remain []byte, err error) {
+	buf := make([]byte, len(b))
+	copy(buf, b)
+	reverseBytes(buf)
+	l, err := peek(buf)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return buf[:l], b[l:], nil
+}
+
 // EncodeValue appends the encoded values to byte slice b, returning the appended
 // slice. It does not guarantee the order for comparison.
 func EncodeValue(sc *stmtctx.StatementContext, b []byte, v ...types.Datum) ([]byte, error) {
@@ -232,6 +304,9 @@ func encodeHashChunkRowIdx(sc *stmtctx.StatementContext, row chunk.Row, tp *type
 		b = row.GetRaw(idx)
 	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
 		flag = compactBytesFlag
+		b = collate.GetCollator(tp.Collate).Key(row.GetString(idx))
+	case mysql.TypeJSON:
+		flag = jsonFlag
 		b = row.GetBytes(idx)
 	default:
 		return 0, nil, errors.Errorf("unsupport column type for encode %d", tp.Tp)
@@ -314,6 +389,8 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 			_, _ = h[i].Write(b)
 		}
 	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString, mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		// The strings equal in the collation have the same key.
+		collator := collate.GetCollator(tp.Collate)
 		for i := 0; i < rows; i++ {
 			if sel != nil && !sel[i] {
 				continue
@@ -323,6 +400,25 @@ func HashChunkSelected(sc *stmtctx.StatementContext, h []hash.Hash64, chk *chunk
 				isNull[i] = true
 			} else {
 				buf[0] = compactBytesFlag
+				b = collator.Key(column.GetString(i))
+			}
+
+			// As the golang doc described, `Hash.Write` never returns an error.
+			// See https://golang.org/pkg/hash/#Hash
+			_, _ = h[i].Write(buf)
+			_, _ = h[i].Write(b)
+		}
+	case mysql.TypeJSON:
+		for i := 0; i < rows; i++ {
+			if sel != nil && !sel[i] {
+				continue
+			}
+			if column.IsNull(i) {
+				buf[0], b = NilFlag, nil
+				isNull[i] = true
+			} else {
+				// The raw bytes of a JSON column are its type code and its value.
+				buf[0] = jsonFlag
 				b = column.GetBytes(i)
 			}
 
@@ -430,12 +526,12 @@ func DecodeRange(b []byte, size int) ([]types.Datum, []byte, error) {
 
 	if len(b) == 1 {
 		switch b[0] {
-		case NilFlag:
+		case NilFlag, descNilFlag:
 			values = append(values, types.Datum{})
-		case bytesFlag:
+		case bytesFlag, descMinNotNullFlag:
 			values = append(values, types.MinNotNullDatum())
 		// `maxFlag + 1` for PrefixNext
-		case maxFlag, maxFlag + 1:
+		case maxFlag, maxFlag + 1, descMaxFlag:
 			values = append(values, types.MaxValueDatum())
 		default:
 			return values, b, errors.Errorf("invalid encoded key flag %v", b[0])
@@ -480,7 +576,22 @@ func DecodeOne(b []byte) (remain []byte, d types.Datum, err error) {
 		var v []byte
 		b, v, err = DecodeCompactBytes(b)
 		d.SetBytes(v)
-	case NilFlag:
+	case jsonFlag:
+		var size int
+		size, err = json.PeekBytesAsJSON(b)
+		if err != nil {
+			return b, d, errors.Trace(err)
+		}
+		j := json.BinaryJSON{TypeCode: b[0], Value: b[1:size]}
+		d.SetMysqlJSON(j)
+		b = b[size:]
+	case descFlag:
+		var data []byte
+		data, b, err = cutDesc(b)
+		if err == nil {
+			_, d, err = DecodeOne(data)
+		}
+	case NilFlag, descNilFlag:
 	default:
 		return b, d, errors.Errorf("invalid encoded key flag %v", flag)
 	}
@@ -534,7 +645,7 @@ func peek(b []byte) (length int, err error) {
 	b = b[1:]
 	var l int
 	switch flag {
-	case NilFlag:
+	case NilFlag, descNilFlag:
 	case intFlag, uintFlag, floatFlag, durationFlag:
 		// Those types are stored in 8 bytes.
 		l = 8
@@ -546,6 +657,12 @@ func peek(b []byte) (length int, err error) {
 		l, err = peekVarint(b)
 	case uvarintFlag:
 		l, err = peekUvarint(b)
+	case jsonFlag:
+		l, err = json.PeekBytesAsJSON(b)
+	case descFlag:
+		var data []byte
+		data, _, err = cutDesc(b)
+		l = len(data)
 	default:
 		return 0, errors.Errorf("invalid encoded key flag %v", flag)
 	}
@@ -680,7 +797,22 @@ func (decoder *Decoder) DecodeOne(b []byte, colIdx int, ft *types.FieldType) (re
 			return nil, errors.Trace(err)
 		}
 		chk.AppendBytes(colIdx, v)
-	case NilFlag:
+	case jsonFlag:
+		var size int
+		size, err = json.PeekBytesAsJSON(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		chk.AppendJSON(colIdx, json.BinaryJSON{TypeCode: b[0], Value: b[1:size]})
+		b = b[size:]
+	case descFlag:
+		var data []byte
+		data, b, err = cutDesc(b)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		_, err = decoder.DecodeOne(data, colIdx, ft)
+	case NilFlag, descNilFlag:
 		chk.AppendNull(colIdx)
 	default:
 		return nil, errors.Errorf("invalid encoded key flag %v", flag)
@@ -732,11 +864,21 @@ func HashGroupKey(sc *stmtctx.StatementContext, n int, col *chunk.Column, buf []
 			}
 		}
 	case types.ETString:
+		collator := collate.GetCollator(ft.Collate)
+		for i := 0; i < n; i++ {
+			if col.IsNull(i) {
+				buf[i] = append(buf[i], NilFlag)
+			} else {
+				buf[i] = encodeBytes(buf[i], collator.Key(col.GetString(i)), false)
+			}
+		}
+	case types.ETJson:
 		for i := 0; i < n; i++ {
 			if col.IsNull(i) {
 				buf[i] = append(buf[i], NilFlag)
 			} else {
-				buf[i] = encodeBytes(buf[i], col.GetBytes(i), false)
+				buf[i] = append(buf[i], jsonFlag)
+				buf[i] = append(buf[i], col.GetBytes(i)...)
 			}
 		}
 	default:
@@ -26,6 +26,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/testleak"
 )
@@ -641,6 +642,7 @@ func datumsForTest(sc *stmtctx.StatementContext) ([]types.Datum, []*types.FieldT
 		{[]byte("abc"), types.NewFieldType(mysql.TypeMediumBlob)},
 		{[]byte("abc"), types.NewFieldType(mysql.TypeLongBlob)},
 		{int64(1), types.NewFieldType(mysql.TypeYear)},
+		{json.CreateBinary("abc"), types.NewFieldType(mysql.TypeJSON)},
 	}
 
 	datums := make([]types.Datum, 0, len(table)+2)
@@ -670,7 +672,7 @@ func (s *testCodecSuite) TestDecodeRange(c *C) {
 	_, _, err := DecodeRange(nil, 0)
 	c.Assert(err, NotNil)
 
-	datums := types.MakeDatums(1, "abc", 1.1, []byte("def"))
+	datums := types.MakeDatums(1, "abc", 1.1, []byte("def"), json.CreateBinary([]interface{}{"a", int64(1)}))
 	rowData, err := EncodeValue(nil, nil, datums...)
 	c.Assert(err, IsNil)
 
@@ -682,7 +684,7 @@ func (s *testCodecSuite) TestDecodeRange(c *C) {
 		c.Assert(cmp, Equals, 0)
 	}
 
-	for _, b := range []byte{NilFlag, bytesFlag, maxFlag, maxFlag + 1} {
+	for _, b := range []byte{NilFlag, bytesFlag, maxFlag, maxFlag + 1, descNilFlag, descMinNotNullFlag, descMaxFlag} {
 		newData := append(rowData, b)
 		_, _, err := DecodeRange(newData, len(datums)+1)
 		c.Assert(err, IsNil)
@@ -768,6 +770,42 @@ func (s *testCodecSuite) TestHashChunkRow(c *C) {
 	testHashChunkRowEqual(c, "x", []byte("y"), false)
 }
 
+func (s *testCodecSuite) TestHashWithCollation(c *C) {
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	buf := make([]byte, 1)
+	tp := types.NewFieldType(mysql.TypeVarchar)
+	tp.Collate = "utf8mb4_general_ci"
+	tps := []*types.FieldType{tp}
+	chk := chunk.New(tps, 3, 3)
+	chk.AppendString(0, "abc")
+	chk.AppendString(0, "ABC ")
+	chk.AppendString(0, "abd")
+
+	rowHash := []hash.Hash64{fnv.New64(), fnv.New64(), fnv.New64()}
+	for i := range rowHash {
+		c.Assert(HashChunkRow(sc, rowHash[i], chk.GetRow(i), tps, []int{0}, buf), IsNil)
+	}
+	c.Assert(rowHash[0].Sum64(), Equals, rowHash[1].Sum64())
+	c.Assert(rowHash[0].Sum64(), Not(Equals), rowHash[2].Sum64())
+	e, err := EqualChunkRow(sc, chk.GetRow(0), tps, []int{0}, chk.GetRow(1), tps, []int{0})
+	c.Assert(err, IsNil)
+	c.Assert(e, IsTrue)
+	e, err = EqualChunkRow(sc, chk.GetRow(0), tps, []int{0}, chk.GetRow(2), tps, []int{0})
+	c.Assert(err, IsNil)
+	c.Assert(e, IsFalse)
+
+	vecHash := []hash.Hash64{fnv.New64(), fnv.New64(), fnv.New64()}
+	c.Assert(HashChunkColumns(sc, vecHash, chk, tp, 0, buf, make([]bool, 3)), IsNil)
+	for i := range vecHash {
+		c.Assert(vecHash[i].Sum64(), Equals, rowHash[i].Sum64())
+	}
+
+	keys, err := HashGroupKey(sc, 3, chk.Column(0), make([][]byte, 3), tp)
+	c.Assert(err, IsNil)
+	c.Assert(keys[0], BytesEquals, keys[1])
+	c.Assert(bytes.Equal(keys[0], keys[2]), IsFalse)
+}
+
 func (s *testCodecSuite) TestValueSizeOfSignedInt(c *C) {
 	testCase := []int64{64, 8192, 1048576, 134217728, 17179869184, 2199023255552, 281474976710656, 36028797018963968, 4611686018427387904}
 	var b []byte
@@ -864,3 +902,58 @@ func (s *testCodecSuite) TestHashChunkColumns(c *C) {
 		c.Assert(vecHash[2].Sum64(), Equals, rowHash[2].Sum64())
 	}
 }
+
+func (s *testCodecSuite) TestCodecKeyWithDesc(c *C) {
+	defer testleak.AfterTest(c)()
+	sc := &stmtctx.StatementContext{TimeZone: time.Local}
+	descs := []bool{false, true}
+	// The values are sorted by the key order, the second column is in descending order.
+	sorted := [][]types.Datum{
+		{types.NewDatum(nil), types.MaxValueDatum()},
+		{types.NewDatum(1), types.MaxValueDatum()},
+		{types.NewDatum(1), types.NewDatum("abcdefghi")},
+		{types.NewDatum(1), types.NewDatum("abcdefgh")},
+		{types.NewDatum(1), types.NewDatum("abc")},
+		{types.NewDatum(1), types.MinNotNullDatum()},
+		{types.NewDatum(1), types.NewDatum(nil)},
+		{types.NewDatum(2), types.NewDatum(-1.5)},
+		{types.NewDatum(2), types.NewDatum(-2.5)},
+	}
+	var prev []byte
+	for i, vals := range sorted {
+		b, err := EncodeKeyWithDesc(sc, nil, descs, vals...)
+		c.Assert(err, IsNil)
+		if i > 0 {
+			c.Assert(bytes.Compare(prev, b), Equals, -1, Commentf("%v", vals))
+		}
+		prev = b
+		if vals[1].Kind() == types.KindMaxValue || vals[1].Kind() == types.KindMinNotNull {
+			continue
+		}
+
+		decoded, err := Decode(b, 2)
+		c.Assert(err, IsNil)
+		for j := range vals {
+			cmp, err := decoded[j].CompareDatum(sc, &vals[j])
+			c.Assert(err, IsNil)
+			c.Assert(cmp, Equals, 0)
+		}
+		_, remain, err := CutOne(b)
+		c.Assert(err, IsNil)
+		data, remain, err := CutOne(remain)
+		c.Assert(err, IsNil)
+		c.Assert(remain, HasLen, 0)
+
+		tp := types.NewFieldType(mysql.TypeVarchar)
+		if vals[1].Kind() == types.KindFloat64 {
+			tp = types.NewFieldType(mysql.TypeDouble)
+		}
+		chk := chunk.New([]*types.FieldType{tp}, 1, 1)
+		_, err = NewDecoder(chk, sc.TimeZone).DecodeOne(data, 0, tp)
+		c.Assert(err, IsNil)
+		d := chk.GetRow(0).GetDatum(0, tp)
+		cmp, err := d.CompareDatum(sc, &vals[1])
+		c.Assert(err, IsNil)
+		c.Assert(cmp, Equals, 0)
+	}
+}
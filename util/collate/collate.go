@@ -0,0 +1,258 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collate
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/stringutil"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Collator provides functionality for comparing strings for a collation.
+type Collator interface {
+	// Compare returns an integer comparing the two strings.
+	// The result will be 0 if a == b, -1 if a < b, and +1 if a > b.
+	Compare(a, b string) int
+	// Key returns the collation key of str, two strings are equal in the
+	// collation if and only if their keys are equal. The returned key must
+	// not be modified.
+	Key(str string) []byte
+	// Pattern returns a WildcardPattern that matches strings in the collation.
+	Pattern() WildcardPattern
+}
+
+// WildcardPattern is the pattern of LIKE, which is compiled once and matched
+// against many strings.
+type WildcardPattern interface {
+	// Compile compiles the pattern with the escape character.
+	Compile(pattern string, escape rune)
+	// DoMatch returns whether str matches the compiled pattern.
+	DoMatch(str string) bool
+}
+
+var (
+	binCollatorInstance       = &binCollator{}
+	generalCICollatorInstance = &generalCICollator{}
+	unicodeCICollatorInstance = newUnicodeCICollator()
+
+	collators = map[string]Collator{
+		"utf8_general_ci":    generalCICollatorInstance,
+		"utf8mb4_general_ci": generalCICollatorInstance,
+		"utf8_unicode_ci":    unicodeCICollatorInstance,
+		"utf8mb4_unicode_ci": unicodeCICollatorInstance,
+	}
+)
+
+// GetCollator gets the collator of the collation, the strings are compared
+// by their bytes in the collations other than the case insensitive ones above.
+func GetCollator(collation string) Collator {
+	if c, ok := collators[strings.ToLower(collation)]; ok {
+		return c
+	}
+	return binCollatorInstance
+}
+
+// GetPattern returns a WildcardPattern that matches strings in the collation.
+// The wildcards match bytes in the binary collation of the binary strings, and
+// match characters in the other collations.
+func GetPattern(collation string) WildcardPattern {
+	if strings.EqualFold(collation, charset.CollationBin) {
+		return &bytePattern{}
+	}
+	return GetCollator(collation).Pattern()
+}
+
+// IsBinCollation returns whether the strings are compared by their bytes in the collation.
+func IsBinCollation(collation string) bool {
+	_, ok := collators[strings.ToLower(collation)]
+	return !ok
+}
+
+// truncateTailingSpace removes the trailing spaces, which are ignored by the
+// PAD SPACE collations.
+func truncateTailingSpace(str string) string {
+	return strings.TrimRight(str, " ")
+}
+
+type binCollator struct{}
+
+// Compare implements Collator interface.
+func (bc *binCollator) Compare(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+// Key implements Collator interface.
+func (bc *binCollator) Key(str string) []byte {
+	return hack.Slice(str)
+}
+
+// Pattern implements Collator interface.
+func (bc *binCollator) Pattern() WildcardPattern {
+	return &runePattern{}
+}
+
+// generalCICollator implements utf8mb4_general_ci, the characters are compared
+// by their upper cases, the accented Latin-1 letters are regarded as their base
+// letters and all the supplementary characters are equal.
+type generalCICollator struct{}
+
+// Compare implements Collator interface.
+func (gc *generalCICollator) Compare(a, b string) int {
+	a, b = truncateTailingSpace(a), truncateTailingSpace(b)
+	for len(a) > 0 && len(b) > 0 {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		wa, wb := generalCIWeight(ra), generalCIWeight(rb)
+		if wa != wb {
+			if wa < wb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// Key implements Collator interface.
+func (gc *generalCICollator) Key(str string) []byte {
+	str = truncateTailingSpace(str)
+	key := make([]byte, 0, len(str)*2)
+	for len(str) > 0 {
+		r, size := utf8.DecodeRuneInString(str)
+		w := generalCIWeight(r)
+		key = append(key, byte(w>>8), byte(w))
+		str = str[size:]
+	}
+	return key
+}
+
+// Pattern implements Collator interface.
+func (gc *generalCICollator) Pattern() WildcardPattern {
+	return &runePattern{equal: func(a, b rune) bool {
+		return generalCIWeight(a) == generalCIWeight(b)
+	}}
+}
+
+// latin1Weights is the weights of the characters from U+00C0 to U+00FF.
+var latin1Weights = [64]uint16{
+	'A', 'A', 'A', 'A', 'A', 'A', 0xC6, 'C', 'E', 'E', 'E', 'E', 'I', 'I', 'I', 'I',
+	0xD0, 'N', 'O', 'O', 'O', 'O', 'O', 0xD7, 0xD8, 'U', 'U', 'U', 'U', 'Y', 0xDE, 'S',
+	'A', 'A', 'A', 'A', 'A', 'A', 0xC6, 'C', 'E', 'E', 'E', 'E', 'I', 'I', 'I', 'I',
+	0xD0, 'N', 'O', 'O', 'O', 'O', 'O', 0xF7, 0xD8, 'U', 'U', 'U', 'U', 'Y', 0xDE, 'Y',
+}
+
+func generalCIWeight(r rune) uint16 {
+	switch {
+	case r > 0xFFFF:
+		return 0xFFFD
+	case r >= 0xC0 && r <= 0xFF:
+		return latin1Weights[r-0xC0]
+	}
+	return uint16(unicode.ToUpper(r))
+}
+
+// unicodeCICollator implements utf8mb4_unicode_ci, the strings are compared at
+// the primary level of the Unicode Collation Algorithm, which ignores the case,
+// the accents and the width of the characters.
+type unicodeCICollator struct {
+	// The collators of x/text are not safe for concurrent use.
+	pool sync.Pool
+}
+
+func newUnicodeCICollator() *unicodeCICollator {
+	return &unicodeCICollator{pool: sync.Pool{New: func() interface{} {
+		return collate.New(language.Und, collate.Loose)
+	}}}
+}
+
+// Compare implements Collator interface.
+func (uc *unicodeCICollator) Compare(a, b string) int {
+	c := uc.pool.Get().(*collate.Collator)
+	defer uc.pool.Put(c)
+	return c.CompareString(truncateTailingSpace(a), truncateTailingSpace(b))
+}
+
+// Key implements Collator interface.
+func (uc *unicodeCICollator) Key(str string) []byte {
+	c := uc.pool.Get().(*collate.Collator)
+	defer uc.pool.Put(c)
+	var buf collate.Buffer
+	return c.KeyFromString(&buf, truncateTailingSpace(str))
+}
+
+// Pattern implements Collator interface.
+func (uc *unicodeCICollator) Pattern() WildcardPattern {
+	return &runePattern{equal: func(a, b rune) bool {
+		return uc.Compare(string(a), string(b)) == 0
+	}}
+}
+
+// bytePattern is the WildcardPattern whose wildcards match bytes.
+type bytePattern struct {
+	patChars []byte
+	patTypes []byte
+	// runes is used if the escape character is not a single byte.
+	runes *runePattern
+}
+
+// Compile implements WildcardPattern interface.
+func (p *bytePattern) Compile(pattern string, escape rune) {
+	if escape >= utf8.RuneSelf {
+		p.runes = &runePattern{}
+		p.runes.Compile(pattern, escape)
+		return
+	}
+	p.runes = nil
+	p.patChars, p.patTypes = stringutil.CompilePattern(pattern, byte(escape))
+}
+
+// DoMatch implements WildcardPattern interface.
+func (p *bytePattern) DoMatch(str string) bool {
+	if p.runes != nil {
+		return p.runes.DoMatch(str)
+	}
+	return stringutil.DoMatch(str, p.patChars, p.patTypes)
+}
+
+// runePattern is the WildcardPattern whose wildcards match characters, the
+// characters are compared with equal, or by their code points if it is nil.
+type runePattern struct {
+	patChars []rune
+	patTypes []byte
+	equal    func(a, b rune) bool
+}
+
+// Compile implements WildcardPattern interface.
+func (p *runePattern) Compile(pattern string, escape rune) {
+	p.patChars, p.patTypes = stringutil.CompilePatternRunes(pattern, escape)
+}
+
+// DoMatch implements WildcardPattern interface.
+func (p *runePattern) DoMatch(str string) bool {
+	return stringutil.DoMatchRunes(str, p.patChars, p.patTypes, p.equal)
+}
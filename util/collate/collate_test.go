@@ -0,0 +1,129 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collate
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/tidb/util/testleak"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testCollateSuite{})
+
+type testCollateSuite struct{}
+
+type compareCase struct {
+	a, b   string
+	expect int
+}
+
+func testCompareAndKey(c *C, collation string, cases []compareCase) {
+	collator := GetCollator(collation)
+	for _, t := range cases {
+		comment := Commentf("%s: %q %q", collation, t.a, t.b)
+		c.Assert(collator.Compare(t.a, t.b), Equals, t.expect, comment)
+		c.Assert(collator.Compare(t.b, t.a), Equals, -t.expect, comment)
+		c.Assert(bytes.Equal(collator.Key(t.a), collator.Key(t.b)), Equals, t.expect == 0, comment)
+	}
+}
+
+func (s *testCollateSuite) TestBinCollator(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, collation := range []string{"binary", "utf8mb4_bin", "utf8_bin", "latin1_bin", ""} {
+		c.Assert(IsBinCollation(collation), IsTrue)
+		testCompareAndKey(c, collation, []compareCase{
+			{"a", "a", 0},
+			{"a", "A", 1},
+			{"a", "a ", -1},
+			{"a", "b", -1},
+			{"中文", "中文", 0},
+			{"", "a", -1},
+		})
+	}
+}
+
+func (s *testCollateSuite) TestGeneralCICollator(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, collation := range []string{"utf8mb4_general_ci", "UTF8_GENERAL_CI"} {
+		c.Assert(IsBinCollation(collation), IsFalse)
+		testCompareAndKey(c, collation, []compareCase{
+			{"a", "A", 0},
+			{"abc", "ABC ", 0},
+			{"À", "a", 0},
+			{"ß", "s", 0},
+			{"ß", "ss", -1},
+			{"Æ", "æ", 0},
+			{"a", "b", -1},
+			{"abc", "ABD", -1},
+			{"a", "ab", -1},
+			{"😀", "😃", 0},
+			{"中", "文", -1},
+			{"", " ", 0},
+		})
+	}
+}
+
+func (s *testCollateSuite) TestUnicodeCICollator(c *C) {
+	defer testleak.AfterTest(c)()
+	for _, collation := range []string{"utf8mb4_unicode_ci", "utf8_unicode_ci"} {
+		c.Assert(IsBinCollation(collation), IsFalse)
+		testCompareAndKey(c, collation, []compareCase{
+			{"a", "A", 0},
+			{"abc", "ABC ", 0},
+			{"á", "A", 0},
+			{"ß", "ss", 0},
+			{"a", "b", -1},
+			{"abc", "ABD", -1},
+			{"a", "ab", -1},
+			{"中", "文", -1},
+			{"", " ", 0},
+		})
+	}
+}
+
+func (s *testCollateSuite) TestPattern(c *C) {
+	defer testleak.AfterTest(c)()
+	cases := []struct {
+		collation string
+		pattern   string
+		escape    rune
+		input     string
+		match     bool
+	}{
+		{"binary", "a_c", '\\', "abc", true},
+		{"binary", "a_c", '\\', "a中c", false},
+		{"binary", "a___c", '\\', "a中c", true},
+		{"binary", "a%", '\\', "A", false},
+		{"binary", "中%a", '中', "%a", true},
+		{"utf8mb4_bin", "a_c", '\\', "a中c", true},
+		{"utf8mb4_bin", "a%", '\\', "A", false},
+		{"utf8mb4_bin", "a ", '\\', "a", false},
+		{"utf8mb4_general_ci", "a_C", '\\', "A中c", true},
+		{"utf8mb4_general_ci", "À%", '\\', "abc", true},
+		{"utf8mb4_general_ci", "a", '\\', "a ", false},
+		{"utf8mb4_unicode_ci", "A_c", '\\', "á中C", true},
+		{"utf8mb4_unicode_ci", "a%", '\\', "b", false},
+	}
+	for _, t := range cases {
+		p := GetPattern(t.collation)
+		p.Compile(t.pattern, t.escape)
+		c.Assert(p.DoMatch(t.input), Equals, t.match, Commentf("%v", t))
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+
+	"github.com/pingcap/errors"
+)
+
+// ErrInvalidIV is returned when the initialization vector is not a full block.
+var ErrInvalidIV = errors.New("the initialization vector is too short")
+
+// PKCS7Pad pads data to a multiple of blockSize, every padding byte is the
+// number of padding bytes, a full block is padded if data is already aligned.
+func PKCS7Pad(data []byte, blockSize int) []byte {
+	padLen := blockSize - len(data)%blockSize
+	return append(data, bytes.Repeat([]byte{byte(padLen)}, padLen)...)
+}
+
+// PKCS7Unpad removes the padding added by PKCS7Pad, the data is invalid if
+// the padding is malformed.
+func PKCS7Unpad(data []byte, blockSize int) ([]byte, error) {
+	length := len(data)
+	if length == 0 || length%blockSize != 0 {
+		return nil, errors.New("invalid padding: the length is not a multiple of the block size")
+	}
+	padLen := int(data[length-1])
+	if padLen == 0 || padLen > blockSize {
+		return nil, errors.New("invalid padding: the padding length is out of range")
+	}
+	for _, b := range data[length-padLen:] {
+		if int(b) != padLen {
+			return nil, errors.New("invalid padding: the padding bytes are inconsistent")
+		}
+	}
+	return data[:length-padLen], nil
+}
+
+// DeriveKeyMySQL derives a key of keySize bytes from the key given by users
+// in the same way as MySQL: the key is folded into keySize bytes with XOR.
+func DeriveKeyMySQL(key []byte, keySize int) []byte {
+	rKey := make([]byte, keySize)
+	for i := range key {
+		rKey[i%keySize] ^= key[i]
+	}
+	return rKey
+}
+
+// AESEncryptWithECB encrypts data with AES in the ECB mode, the data is padded
+// with PKCS7.
+func AESEncryptWithECB(data, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	blockSize := block.BlockSize()
+	crypted := PKCS7Pad(append([]byte(nil), data...), blockSize)
+	for i := 0; i < len(crypted); i += blockSize {
+		block.Encrypt(crypted[i:i+blockSize], crypted[i:i+blockSize])
+	}
+	return crypted, nil
+}
+
+// AESDecryptWithECB decrypts data encrypted by AESEncryptWithECB.
+func AESDecryptWithECB(crypted, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	blockSize := block.BlockSize()
+	if len(crypted)%blockSize != 0 {
+		return nil, errors.New("invalid data: the length is not a multiple of the block size")
+	}
+	data := make([]byte, len(crypted))
+	for i := 0; i < len(crypted); i += blockSize {
+		block.Decrypt(data[i:i+blockSize], crypted[i:i+blockSize])
+	}
+	return PKCS7Unpad(data, blockSize)
+}
+
+// AESEncryptWithCBC encrypts data with AES in the CBC mode, the data is padded
+// with PKCS7.
+func AESEncryptWithCBC(data, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(iv) < block.BlockSize() {
+		return nil, ErrInvalidIV
+	}
+	crypted := PKCS7Pad(append([]byte(nil), data...), block.BlockSize())
+	cipher.NewCBCEncrypter(block, iv[:block.BlockSize()]).CryptBlocks(crypted, crypted)
+	return crypted, nil
+}
+
+// AESDecryptWithCBC decrypts data encrypted by AESEncryptWithCBC.
+func AESDecryptWithCBC(crypted, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(iv) < block.BlockSize() {
+		return nil, ErrInvalidIV
+	}
+	if len(crypted)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid data: the length is not a multiple of the block size")
+	}
+	data := make([]byte, len(crypted))
+	cipher.NewCBCDecrypter(block, iv[:block.BlockSize()]).CryptBlocks(data, crypted)
+	return PKCS7Unpad(data, block.BlockSize())
+}
+
+// AESEncryptWithOFB encrypts data with AES in the OFB mode, the data is not padded.
+func AESEncryptWithOFB(data, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(iv) < block.BlockSize() {
+		return nil, ErrInvalidIV
+	}
+	crypted := make([]byte, len(data))
+	cipher.NewOFB(block, iv[:block.BlockSize()]).XORKeyStream(crypted, data)
+	return crypted, nil
+}
+
+// AESDecryptWithOFB decrypts data encrypted by AESEncryptWithOFB.
+func AESDecryptWithOFB(crypted, key, iv []byte) ([]byte, error) {
+	// The decryption of OFB is the same as the encryption.
+	return AESEncryptWithOFB(crypted, key, iv)
+}
+
+// AESEncryptWithCFB encrypts data with AES in the 128-bit CFB mode, the data
+// is not padded.
+func AESEncryptWithCFB(data, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(iv) < block.BlockSize() {
+		return nil, ErrInvalidIV
+	}
+	crypted := make([]byte, len(data))
+	cipher.NewCFBEncrypter(block, iv[:block.BlockSize()]).XORKeyStream(crypted, data)
+	return crypted, nil
+}
+
+// AESDecryptWithCFB decrypts data encrypted by AESEncryptWithCFB.
+func AESDecryptWithCFB(crypted, key, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(iv) < block.BlockSize() {
+		return nil, ErrInvalidIV
+	}
+	data := make([]byte, len(crypted))
+	cipher.NewCFBDecrypter(block, iv[:block.BlockSize()]).XORKeyStream(data, crypted)
+	return data, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"encoding/hex"
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testEncryptSuite{})
+
+type testEncryptSuite struct{}
+
+func (s *testEncryptSuite) TestPKCS7(c *C) {
+	cases := []struct {
+		data   string
+		padded string
+	}{
+		{"", "0808080808080808"},
+		{"01", "0107070707070707"},
+		{"01020304050607", "0102030405060701"},
+		{"0102030405060708", "01020304050607080808080808080808"},
+	}
+	for _, t := range cases {
+		data, _ := hex.DecodeString(t.data)
+		padded := PKCS7Pad(data, 8)
+		c.Assert(hex.EncodeToString(padded), Equals, t.padded)
+		unpadded, err := PKCS7Unpad(padded, 8)
+		c.Assert(err, IsNil)
+		c.Assert(hex.EncodeToString(unpadded), Equals, t.data)
+	}
+
+	for _, invalid := range []string{"", "01020304", "0102030405060700", "0102030405060709", "0102030405060302"} {
+		data, _ := hex.DecodeString(invalid)
+		_, err := PKCS7Unpad(data, 8)
+		c.Assert(err, NotNil, Commentf("%s", invalid))
+	}
+}
+
+func (s *testEncryptSuite) TestDeriveKeyMySQL(c *C) {
+	c.Assert(hex.EncodeToString(DeriveKeyMySQL([]byte("key"), 4)), Equals, "6b657900")
+	c.Assert(hex.EncodeToString(DeriveKeyMySQL([]byte("abcdef"), 4)), Equals, "04046364")
+}
+
+func (s *testEncryptSuite) TestAES(c *C) {
+	key := DeriveKeyMySQL([]byte("1234567890123456"), 16)
+	iv := []byte("1234567890123456")
+	// The results are the same as MySQL.
+	crypted, err := AESEncryptWithECB([]byte("pingcap"), key)
+	c.Assert(err, IsNil)
+	c.Assert(hex.EncodeToString(crypted), Equals, "697bfe9b3f8c2f289dd82c88c7bc95c4")
+	crypted, err = AESEncryptWithCBC([]byte("pingcap"), key, iv)
+	c.Assert(err, IsNil)
+	c.Assert(hex.EncodeToString(crypted), Equals, "2eca0077c5ea5768a0485aa522774792")
+
+	type cryptFunc func(data, key, iv []byte) ([]byte, error)
+	modes := []struct {
+		encrypt cryptFunc
+		decrypt cryptFunc
+		padded  bool
+		useIV   bool
+	}{
+		{func(data, key, _ []byte) ([]byte, error) { return AESEncryptWithECB(data, key) },
+			func(data, key, _ []byte) ([]byte, error) { return AESDecryptWithECB(data, key) }, true, false},
+		{AESEncryptWithCBC, AESDecryptWithCBC, true, true},
+		{AESEncryptWithOFB, AESDecryptWithOFB, false, true},
+		{AESEncryptWithCFB, AESDecryptWithCFB, false, true},
+	}
+	for _, m := range modes {
+		for _, data := range []string{"", "a", "pingcap", "0123456789abcdef", "0123456789abcdef0"} {
+			crypted, err := m.encrypt([]byte(data), key, iv)
+			c.Assert(err, IsNil)
+			if m.padded {
+				c.Assert(len(crypted), Equals, (len(data)/16+1)*16)
+			} else {
+				c.Assert(len(crypted), Equals, len(data))
+			}
+			decrypted, err := m.decrypt(crypted, key, iv)
+			c.Assert(err, IsNil)
+			c.Assert(string(decrypted), Equals, data)
+		}
+		if m.useIV {
+			_, err := m.encrypt([]byte("pingcap"), key, iv[:8])
+			c.Assert(err, NotNil)
+		}
+		_, err := m.encrypt([]byte("pingcap"), key[:5], iv)
+		c.Assert(err, NotNil)
+	}
+}
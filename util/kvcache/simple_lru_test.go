@@ -0,0 +1,140 @@
+// Copyright 2017 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvcache
+
+import (
+	"testing"
+
+	. "github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&testLRUCacheSuite{})
+
+type testLRUCacheSuite struct {
+}
+
+type mockCacheKey struct {
+	hash []byte
+	key  int64
+}
+
+func (mk *mockCacheKey) Hash() []byte {
+	if mk.hash != nil {
+		return mk.hash
+	}
+	mk.hash = make([]byte, 8)
+	for i := uint64(0); i < 8; i++ {
+		mk.hash[i] = byte((mk.key >> (i * 8)) & 0xff)
+	}
+	return mk.hash
+}
+
+func newMockHashKey(key int64) *mockCacheKey {
+	return &mockCacheKey{
+		key: key,
+	}
+}
+
+func (s *testLRUCacheSuite) TestPut(c *C) {
+	lru := NewSimpleLRUCache(3)
+	c.Assert(lru.Capacity(), Equals, uint(3))
+
+	keys := make([]*mockCacheKey, 5)
+	vals := make([]int64, 5)
+	for i := 0; i < 5; i++ {
+		keys[i] = newMockHashKey(int64(i))
+		vals[i] = int64(i)
+		lru.Put(keys[i], vals[i])
+	}
+	c.Assert(lru.Size(), Equals, 3)
+
+	// The first two keys are evicted.
+	for i := 0; i < 5; i++ {
+		value, exists := lru.Get(keys[i])
+		if i < 2 {
+			c.Assert(exists, IsFalse)
+			continue
+		}
+		c.Assert(exists, IsTrue)
+		c.Assert(value, Equals, vals[i])
+	}
+
+	// Putting an existing key overwrites its value without growing the cache.
+	lru.Put(keys[2], int64(10))
+	c.Assert(lru.Size(), Equals, 3)
+	value, exists := lru.Get(keys[2])
+	c.Assert(exists, IsTrue)
+	c.Assert(value, Equals, int64(10))
+}
+
+func (s *testLRUCacheSuite) TestGetMovesToFront(c *C) {
+	lru := NewSimpleLRUCache(2)
+	k1, k2, k3 := newMockHashKey(1), newMockHashKey(2), newMockHashKey(3)
+	lru.Put(k1, 1)
+	lru.Put(k2, 2)
+
+	// Touch k1 so that k2 becomes the least recently used one.
+	_, exists := lru.Get(k1)
+	c.Assert(exists, IsTrue)
+	lru.Put(k3, 3)
+
+	_, exists = lru.Get(k2)
+	c.Assert(exists, IsFalse)
+	_, exists = lru.Get(k1)
+	c.Assert(exists, IsTrue)
+	_, exists = lru.Get(k3)
+	c.Assert(exists, IsTrue)
+}
+
+func (s *testLRUCacheSuite) TestDelete(c *C) {
+	lru := NewSimpleLRUCache(3)
+	keys := make([]*mockCacheKey, 3)
+	for i := 0; i < 3; i++ {
+		keys[i] = newMockHashKey(int64(i))
+		lru.Put(keys[i], i)
+	}
+	c.Assert(lru.Size(), Equals, 3)
+
+	lru.Delete(keys[1])
+	c.Assert(lru.Size(), Equals, 2)
+	_, exists := lru.Get(keys[1])
+	c.Assert(exists, IsFalse)
+
+	// Deleting a missing key is a no-op.
+	lru.Delete(keys[1])
+	c.Assert(lru.Size(), Equals, 2)
+
+	lru.DeleteAll()
+	c.Assert(lru.Size(), Equals, 0)
+	for i := 0; i < 3; i++ {
+		_, exists = lru.Get(keys[i])
+		c.Assert(exists, IsFalse)
+	}
+}
+
+func (s *testLRUCacheSuite) TestValues(c *C) {
+	lru := NewSimpleLRUCache(3)
+	c.Assert(lru.Values(), HasLen, 0)
+	keys := make([]*mockCacheKey, 4)
+	for i := 0; i < 4; i++ {
+		keys[i] = newMockHashKey(int64(i))
+		lru.Put(keys[i], i)
+	}
+	lru.Get(keys[1])
+	c.Assert(lru.Values(), DeepEquals, []Value{1, 3, 2})
+}
@@ -258,6 +258,11 @@ func SetLevel(level string) error {
 	return nil
 }
 
+// GetLevel gets the zap logger's level.
+func GetLevel() string {
+	return zaplog.GetLevel().String()
+}
+
 type ctxLogKeyType struct{}
 
 var ctxLogKey = ctxLogKeyType{}
@@ -297,3 +302,14 @@ func WithKeyValue(ctx context.Context, key, value string) context.Context {
 	}
 	return context.WithValue(ctx, ctxLogKey, logger.With(zap.String(key, value)))
 }
+
+// WithFields attaches fields to the logger in context, every log line emitted
+// by the contextual logger carries them.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return context.WithValue(ctx, ctxLogKey, Logger(ctx).With(fields...))
+}
+
+// WithLogger attaches logger to context.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxLogKey, logger)
+}
@@ -28,6 +28,7 @@ import (
 	zaplog "github.com/pingcap/log"
 	log "github.com/sirupsen/logrus"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 const (
@@ -149,6 +150,23 @@ func (s *testLogSuite) TestZapLoggerWithKeys(c *C) {
 	os.Remove(fileCfg.Filename)
 }
 
+func (s *testLogSuite) TestWithFields(c *C) {
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := WithLogger(context.Background(), zap.New(core))
+	ctx = WithConnID(ctx, 1)
+	ctx = WithFields(ctx, zap.String("digest", "abc"), zap.Uint64("txnStartTS", 2))
+	Logger(ctx).Info("info msg", zap.String("key", "val"))
+
+	entries := logs.AllUntimed()
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].ContextMap(), DeepEquals, map[string]interface{}{
+		"conn":       uint32(1),
+		"digest":     "abc",
+		"txnStartTS": uint64(2),
+		"key":        "val",
+	})
+}
+
 func (s *testLogSuite) testZapLogger(ctx context.Context, c *C, fileName, pattern string) {
 	Logger(ctx).Debug("debug msg", zap.String("test with key", "true"))
 	Logger(ctx).Info("info msg", zap.String("test with key", "true"))
@@ -188,4 +206,5 @@ func (s *testLogSuite) TestSetLevel(c *C) {
 	err = SetLevel("DEBUG")
 	c.Assert(err, IsNil)
 	c.Assert(zaplog.GetLevel(), Equals, zap.DebugLevel)
+	c.Assert(GetLevel(), Equals, "debug")
 }
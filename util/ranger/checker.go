@@ -14,9 +14,14 @@
 package ranger
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/collate"
 )
 
 // conditionChecker checks if this condition can be pushed to index planner.
@@ -42,7 +47,10 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 	switch scalar.FuncName.L {
 	case ast.LogicOr, ast.LogicAnd:
 		return c.check(scalar.GetArgs()[0]) && c.check(scalar.GetArgs()[1])
-	case ast.EQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT:
+	case ast.EQ, ast.NullEQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT:
+		if !checkCollation(scalar) {
+			return false
+		}
 		if _, ok := scalar.GetArgs()[0].(*expression.Constant); ok {
 			if c.checkColumn(scalar.GetArgs()[1]) {
 				return scalar.FuncName.L != ast.NE || c.length == types.UnspecifiedLength
@@ -56,13 +64,17 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 	case ast.IsNull:
 		return c.checkColumn(scalar.GetArgs()[0])
 	case ast.UnaryNot:
-		if _, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
-			return c.check(scalar.GetArgs()[0])
+		if sf, ok := scalar.GetArgs()[0].(*expression.ScalarFunction); ok {
+			// "not like" and "not(a <=> b)" can't lead to a range.
+			if sf.FuncName.L == ast.Like || sf.FuncName.L == ast.NullEQ {
+				return false
+			}
+			return c.check(sf)
 		}
 		// "not column" or "not constant" can't lead to a range.
 		return false
 	case ast.In:
-		if !c.checkColumn(scalar.GetArgs()[0]) {
+		if !checkCollation(scalar) || !c.checkColumn(scalar.GetArgs()[0]) {
 			return false
 		}
 		for _, v := range scalar.GetArgs()[1:] {
@@ -71,10 +83,44 @@ func (c *conditionChecker) checkScalarFunction(scalar *expression.ScalarFunction
 			}
 		}
 		return true
+	case ast.Like:
+		return c.checkLikeFunc(scalar)
 	}
 	return false
 }
 
+// checkLikeFunc checks if the like function can lead to a range, which means the pattern is a constant string
+// that doesn't start with a wildcard. The condition is reserved in the filters if the rest of the pattern still
+// needs to be matched after the prefix, e.g. "a like 'ab%c'".
+func (c *conditionChecker) checkLikeFunc(scalar *expression.ScalarFunction) bool {
+	args := scalar.GetArgs()
+	if !checkCollation(scalar) || !c.checkColumn(args[0]) || args[0].GetType().EvalType() != types.ETString {
+		return false
+	}
+	pattern, ok := args[1].(*expression.Constant)
+	if !ok || pattern.Value.IsNull() {
+		return false
+	}
+	escape, ok := args[2].(*expression.Constant)
+	if !ok || escape.Value.IsNull() || escape.Value.GetInt64() < 0 || escape.Value.GetInt64() >= utf8.RuneSelf {
+		return false
+	}
+	patternStr, err := pattern.Value.ToString()
+	if err != nil {
+		return false
+	}
+	prefix, rest := splitLikePattern(patternStr, byte(escape.Value.GetInt64()))
+	if len(prefix) == 0 && len(rest) > 0 {
+		return false
+	}
+	// The pattern 'ab%' matches exactly the strings in the range of the prefix, but the range of the CHAR
+	// column includes the values which are only the same after their trailing spaces are trimmed.
+	if strings.Trim(rest, "%") != "" || args[0].GetType().Tp == mysql.TypeString {
+		c.shouldReserve = true
+	}
+	return true
+}
+
 func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
 	col, ok := expr.(*expression.Column)
 	if !ok {
@@ -82,3 +128,9 @@ func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
 	}
 	return c.colUniqueID == col.UniqueID
 }
+
+// checkCollation checks if the strings are compared by their bytes in the scalar function.
+// The ranges can't be built for the other collations since the index keys are sorted by bytes.
+func checkCollation(scalar *expression.ScalarFunction) bool {
+	return collate.IsBinCollation(expression.DeriveCollationFromExprs(scalar.GetArgs()...))
+}
@@ -87,15 +87,29 @@ func detachColumnDNFConditions(sctx sessionctx.Context, conditions []expression.
 	return accessConditions, hasResidualConditions
 }
 
-// getEqOrInColOffset checks if the expression is a eq function that one side is constant and another is column or an
-// in function which is `column in (constant list)`.
+// getEqOrInColOffset checks if the expression is a eq or null-safe eq function that one side is constant and another
+// is column, an in function which is `column in (constant list)` or an `column is null` function.
 // If so, it will return the offset of this column in the slice, otherwise return -1 for not found.
 func getEqOrInColOffset(expr expression.Expression, cols []*expression.Column) int {
 	f, ok := expr.(*expression.ScalarFunction)
 	if !ok {
 		return -1
 	}
-	if f.FuncName.L == ast.EQ {
+	// The NULL values are the same in all the collations.
+	if f.FuncName.L == ast.IsNull {
+		if c, ok := f.GetArgs()[0].(*expression.Column); ok {
+			for i, col := range cols {
+				if col.Equal(nil, c) {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+	if !checkCollation(f) {
+		return -1
+	}
+	if f.FuncName.L == ast.EQ || f.FuncName.L == ast.NullEQ {
 		if c, ok := f.GetArgs()[0].(*expression.Column); ok {
 			if _, ok := f.GetArgs()[1].(*expression.Constant); ok {
 				for i, col := range cols {
@@ -150,6 +164,17 @@ func detachCNFCondAndBuildRangeForIndex(sctx sessionctx.Context, conditions []ex
 	if emptyRange {
 		return res, nil
 	}
+	// The ranges of the following columns can only be appended to the point ranges, so all the remaining
+	// conditions are filters if the points are capped.
+	capCount := capEqAndInCount(sctx, accessConds)
+	capped := capCount < len(accessConds)
+	for i := capCount; i < len(accessConds); i++ {
+		// The conditions of the prefix columns are already in the filters.
+		if lengths[i] == types.UnspecifiedLength {
+			filterConds = append(filterConds, accessConds[i])
+		}
+	}
+	accessConds = accessConds[:capCount]
 
 	for ; eqCount < len(accessConds); eqCount++ {
 		if accessConds[eqCount].(*expression.ScalarFunction).FuncName.L != ast.EQ {
@@ -159,7 +184,7 @@ func detachCNFCondAndBuildRangeForIndex(sctx sessionctx.Context, conditions []ex
 	eqOrInCount := len(accessConds)
 	res.EqCondCount = eqCount
 	res.EqOrInCount = eqOrInCount
-	if eqOrInCount == len(cols) {
+	if eqOrInCount == len(cols) || capped {
 		filterConds = append(filterConds, newConditions...)
 		ranges, err = buildCNFIndexRange(sctx.GetSessionVars().StmtCtx, cols, tpSlice, lengths, eqOrInCount, accessConds)
 		if err != nil {
@@ -255,6 +280,26 @@ func ExtractEqAndInCondition(sctx sessionctx.Context, conditions []expression.Ex
 	return accesses, filters, newConditions, false
 }
 
+// capEqAndInCount returns the number of the leading eq or in conditions whose cartesian product of the points
+// doesn't exceed the limit of the ranges count. The conditions of the first column and the ones of a single
+// point are always used since they don't expand the ranges.
+func capEqAndInCount(sctx sessionctx.Context, accesses []expression.Expression) int {
+	maxCount := sctx.GetSessionVars().RangeMaxCount
+	if maxCount <= 0 || len(accesses) <= 1 {
+		return len(accesses)
+	}
+	rb := builder{sc: sctx.GetSessionVars().StmtCtx}
+	count := 1
+	for i, cond := range accesses {
+		newCount := count * (len(rb.build(cond)) / 2)
+		if i > 0 && newCount > count && newCount > maxCount {
+			return i
+		}
+		count = newCount
+	}
+	return len(accesses)
+}
+
 // detachDNFCondAndBuildRangeForIndex will detach the index filters from table filters when it's a DNF.
 // We will detach the conditions of every DNF items, then compose them to a DNF.
 func detachDNFCondAndBuildRangeForIndex(sctx sessionctx.Context, condition *expression.ScalarFunction,
@@ -356,7 +401,76 @@ func DetachCondAndBuildRangeForIndex(sctx sessionctx.Context, conditions []expre
 			return res, nil
 		}
 	}
-	return detachCNFCondAndBuildRangeForIndex(sctx, conditions, cols, newTpSlice, lengths, true)
+	res, err := detachCNFCondAndBuildRangeForIndex(sctx, conditions, cols, newTpSlice, lengths, true)
+	if err != nil {
+		return res, errors.Trace(err)
+	}
+	return tryDetachCNFCondAsDNF(sctx, res, conditions, cols, newTpSlice, lengths)
+}
+
+// dnfExpansionLimit is the max number of DNF items allowed when converting the index conditions to DNF.
+const dnfExpansionLimit = 64
+
+// tryDetachCNFCondAsDNF tries to build tighter ranges when some index conditions are left as filters
+// by the CNF detaching, e.g. for index (a, b), "(a = 1 or a = 2) and b = 3" only gets the ranges of
+// column a. Converting the index conditions to DNF gets the point ranges (1, 3) and (2, 3) instead.
+// The DNF result is used only if its ranges fully cover the index conditions.
+func tryDetachCNFCondAsDNF(sctx sessionctx.Context, res *DetachRangeResult, conditions []expression.Expression,
+	cols []*expression.Column, newTpSlice []*types.FieldType, lengths []int) (*DetachRangeResult, error) {
+	var idxConds, otherConds []expression.Expression
+	hasDNF, hasRemainedIdxCond := false, false
+	for _, cond := range conditions {
+		if !isIndexOnlyCond(cond, cols) {
+			otherConds = append(otherConds, cond)
+			continue
+		}
+		idxConds = append(idxConds, cond)
+		if sf, ok := cond.(*expression.ScalarFunction); ok && sf.FuncName.L == ast.LogicOr {
+			hasDNF = true
+		}
+		if expression.Contains(res.RemainedConds, cond) {
+			hasRemainedIdxCond = true
+		}
+	}
+	if !hasDNF || !hasRemainedIdxCond {
+		return res, nil
+	}
+	dnfItems, ok := expression.ToDNF(sctx, expression.ComposeCNFCondition(sctx, idxConds...), dnfExpansionLimit)
+	if !ok || len(dnfItems) <= 1 {
+		return res, nil
+	}
+	dnf, ok := expression.ComposeDNFCondition(sctx, dnfItems...).(*expression.ScalarFunction)
+	if !ok {
+		return res, nil
+	}
+	ranges, _, hasResidual, err := detachDNFCondAndBuildRangeForIndex(sctx, dnf, cols, newTpSlice, lengths)
+	if err != nil {
+		return res, errors.Trace(err)
+	}
+	if hasResidual {
+		return res, nil
+	}
+	res.Ranges = ranges
+	res.AccessConds = idxConds
+	res.RemainedConds = otherConds
+	return res, nil
+}
+
+// isIndexOnlyCond checks whether cond only references the index columns.
+func isIndexOnlyCond(cond expression.Expression, cols []*expression.Column) bool {
+	for _, col := range expression.ExtractColumns(cond) {
+		found := false
+		for _, idxCol := range cols {
+			if col.UniqueID == idxCol.UniqueID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // DetachSimpleCondAndBuildRangeForIndex will detach the index filters from table filters.
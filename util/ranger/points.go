@@ -14,6 +14,7 @@
 package ranger
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"sort"
@@ -236,6 +237,13 @@ func (r *builder) buildFormBinOp(expr *expression.ScalarFunction) []point {
 	if err != nil {
 		return nil
 	}
+	if op == ast.NullEQ {
+		// "a <=> null" is the same as "a is null", and the other values are compared in the same way as "=".
+		if value.IsNull() {
+			return []point{{start: true}, {}}
+		}
+		op = ast.EQ
+	}
 	if value.IsNull() {
 		return nil
 	}
@@ -407,6 +415,79 @@ func (r *builder) buildFromIn(expr *expression.ScalarFunction) ([]point, bool) {
 	return rangePoints[:curPos], hasNull
 }
 
+// buildFromLike builds the range of the strings starting with the prefix of the pattern, the pattern has been
+// checked to be a constant that doesn't start with a wildcard.
+func (r *builder) buildFromLike(expr *expression.ScalarFunction) []point {
+	pattern, err := expr.GetArgs()[1].Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	escape, err := expr.GetArgs()[2].Eval(chunk.Row{})
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	if pattern.IsNull() || escape.IsNull() {
+		return nil
+	}
+	patternStr, err := pattern.ToString()
+	if err != nil {
+		r.err = errors.Trace(err)
+		return fullRange
+	}
+	prefix, rest := splitLikePattern(patternStr, byte(escape.GetInt64()))
+	// The trailing spaces of the CHAR values are trimmed in the storage and may be padded when they are read,
+	// so the strings starting with the prefix without the trailing spaces are all included.
+	isChar := expr.GetArgs()[0].GetType().Tp == mysql.TypeString
+	if isChar {
+		prefix = bytes.TrimRight(prefix, " ")
+	}
+	if len(rest) == 0 && !isChar {
+		val := types.NewStringDatum(string(prefix))
+		return []point{{value: val, start: true}, {value: val}}
+	}
+	// The pattern 'ab_' needs at least one character after the prefix, so the prefix itself is excluded.
+	startPoint := point{value: types.NewStringDatum(string(prefix)), start: true, excl: !isChar && rest[0] == '_'}
+	endPoint := point{value: types.MaxValueDatum()}
+	// The upper bound is the smallest string which is greater than all the strings starting with the prefix,
+	// e.g. "abd" for "abc" and "ac" for "ab\xff".
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			upper := make([]byte, i+1)
+			copy(upper, prefix)
+			upper[i]++
+			endPoint = point{value: types.NewStringDatum(string(upper)), excl: true}
+			break
+		}
+	}
+	return []point{startPoint, endPoint}
+}
+
+// splitLikePattern splits the pattern of the like function into the prefix before the first wildcard, in which the
+// escaped characters are unescaped, and the rest of the pattern starting with the wildcard. The escape character
+// followed by the characters other than the wildcards and itself is a normal character, which is the same as
+// stringutil.CompilePattern.
+func splitLikePattern(pattern string, escape byte) ([]byte, string) {
+	prefix := make([]byte, 0, len(pattern))
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case escape:
+			if i < len(pattern)-1 {
+				if next := pattern[i+1]; next == escape || next == '_' || next == '%' {
+					c = next
+					i++
+				}
+			}
+		case '_', '%':
+			return prefix, pattern[i:]
+		}
+		prefix = append(prefix, c)
+	}
+	return prefix, ""
+}
+
 func (r *builder) buildFromNot(expr *expression.ScalarFunction) []point {
 	switch n := expr.FuncName.L; n {
 	case ast.In:
@@ -451,7 +532,7 @@ func (r *builder) buildFromNot(expr *expression.ScalarFunction) []point {
 
 func (r *builder) buildFromScalarFunc(expr *expression.ScalarFunction) []point {
 	switch op := expr.FuncName.L; op {
-	case ast.GE, ast.GT, ast.LT, ast.LE, ast.EQ, ast.NE:
+	case ast.GE, ast.GT, ast.LT, ast.LE, ast.EQ, ast.NullEQ, ast.NE:
 		return r.buildFormBinOp(expr)
 	case ast.LogicAnd:
 		return r.intersection(r.build(expr.GetArgs()[0]), r.build(expr.GetArgs()[1]))
@@ -466,6 +547,8 @@ func (r *builder) buildFromScalarFunc(expr *expression.ScalarFunction) []point {
 		return []point{startPoint, endPoint}
 	case ast.UnaryNot:
 		return r.buildFromNot(expr.GetArgs()[0].(*expression.ScalarFunction))
+	case ast.Like:
+		return r.buildFromLike(expr)
 	}
 
 	return nil
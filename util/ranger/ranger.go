@@ -139,7 +139,7 @@ func appendPoints2Ranges(sc *stmtctx.StatementContext, origin []*Range, rangePoi
 	var newIndexRanges []*Range
 	for i := 0; i < len(origin); i++ {
 		oRange := origin[i]
-		if !oRange.IsPoint(sc) {
+		if !oRange.IsPointNullable(sc) {
 			newIndexRanges = append(newIndexRanges, oRange)
 		} else {
 			newRanges, err := appendPoints2IndexRange(sc, oRange, rangePoints, ft)
@@ -291,6 +291,16 @@ func BuildColumnRange(conds []expression.Expression, sc *stmtctx.StatementContex
 	return buildColumnRange(conds, sc, tp, false, colLen)
 }
 
+// isPointFunc checks whether the function on a column and constants leads to points, which is used
+// to build the ranges of the leading index columns.
+func isPointFunc(funcName string) bool {
+	switch funcName {
+	case ast.EQ, ast.NullEQ, ast.In, ast.IsNull:
+		return true
+	}
+	return false
+}
+
 // buildCNFIndexRange builds the range for index where the top layer is CNF.
 func buildCNFIndexRange(sc *stmtctx.StatementContext, cols []*expression.Column, newTp []*types.FieldType, lengths []int,
 	eqAndInCount int, accessCondition []expression.Expression) ([]*Range, error) {
@@ -303,7 +313,7 @@ func buildCNFIndexRange(sc *stmtctx.StatementContext, cols []*expression.Column,
 		newTp = append(newTp, newFieldType(col.RetType))
 	}
 	for i := 0; i < eqAndInCount; i++ {
-		if sf, ok := accessCondition[i].(*expression.ScalarFunction); !ok || (sf.FuncName.L != ast.EQ && sf.FuncName.L != ast.In) {
+		if sf, ok := accessCondition[i].(*expression.ScalarFunction); !ok || !isPointFunc(sf.FuncName.L) {
 			break
 		}
 		// Build ranges for equal or in access conditions.
@@ -494,9 +504,10 @@ func newFieldType(tp *types.FieldType) *types.FieldType {
 }
 
 // points2EqOrInCond constructs a 'EQUAL' or 'IN' scalar function based on the
-// 'points'. The target column is extracted from the 'expr'.
+// 'points', or an 'IS NULL' function if the only point is NULL. The target column
+// is extracted from the 'expr'.
 // NOTE:
-// 1. 'expr' must be either 'EQUAL' or 'IN' function.
+// 1. 'expr' must be one of the point functions, see isPointFunc.
 // 2. 'points' should not be empty.
 func points2EqOrInCond(ctx sessionctx.Context, points []point, expr expression.Expression) expression.Expression {
 	// len(points) cannot be 0 here, since we impose early termination in ExtractEqAndInCondition
@@ -504,7 +515,7 @@ func points2EqOrInCond(ctx sessionctx.Context, points []point, expr expression.E
 	// Constant and Column args should have same RetType, simply get from first arg
 	retType := sf.GetArgs()[0].GetType()
 	args := make([]expression.Expression, 0, len(points)/2)
-	if sf.FuncName.L == ast.EQ {
+	if sf.FuncName.L == ast.EQ || sf.FuncName.L == ast.NullEQ {
 		if c, ok := sf.GetArgs()[0].(*expression.Column); ok {
 			args = append(args, c)
 		} else if c, ok := sf.GetArgs()[1].(*expression.Column); ok {
@@ -513,6 +524,10 @@ func points2EqOrInCond(ctx sessionctx.Context, points []point, expr expression.E
 	} else {
 		args = append(args, sf.GetArgs()[0])
 	}
+	// The NULL point only intersects with itself, so it's the only point if there is one.
+	if points[0].value.IsNull() {
+		return expression.NewFunctionInternal(ctx, ast.IsNull, sf.GetType(), args...)
+	}
 	for i := 0; i < len(points); i = i + 2 {
 		value := &expression.Constant{
 			Value:   points[i].value,
@@ -228,7 +228,7 @@ func (s *testRangerSuite) TestTableRange(c *C) {
 		{
 			exprStr:     "a = 1 or a = 3 or a = 4 or (b > 1 and (a = -1 or a = 5))",
 			accessConds: "[or(or(eq(test.t.a, 1), eq(test.t.a, 3)), or(eq(test.t.a, 4), or(eq(test.t.a, -1), eq(test.t.a, 5))))]",
-			filterConds: "[or(or(or(eq(test.t.a, 1), eq(test.t.a, 3)), eq(test.t.a, 4)), and(gt(test.t.b, 1), or(eq(test.t.a, -1), eq(test.t.a, 5))))]",
+			filterConds: "[or(or(eq(test.t.a, 1), eq(test.t.a, 3)), or(eq(test.t.a, 4), and(gt(test.t.b, 1), or(eq(test.t.a, -1), eq(test.t.a, 5)))))]",
 			resultStr:   "[[-1,-1] [1,1] [3,3] [4,4] [5,5]]",
 		},
 		{
@@ -408,7 +408,7 @@ func (s *testRangerSuite) TestIndexRange(c *C) {
 			indexPos:    0,
 			exprStr:     "(a > 'a' and a < 'b' and b < 1) or (a >= 'b' and a < 'c')",
 			accessConds: "[or(and(gt(test.t.a, a), lt(test.t.a, b)), and(ge(test.t.a, b), lt(test.t.a, c)))]",
-			filterConds: "[or(and(and(gt(test.t.a, a), lt(test.t.a, b)), lt(test.t.b, 1)), and(ge(test.t.a, b), lt(test.t.a, c)))]",
+			filterConds: "[or(and(gt(test.t.a, a), and(lt(test.t.a, b), lt(test.t.b, 1))), and(ge(test.t.a, b), lt(test.t.a, c)))]",
 			resultStr:   "[(\"a\",\"c\")]",
 		},
 		{
@@ -460,6 +460,181 @@ func (s *testRangerSuite) TestIndexRange(c *C) {
 			filterConds: "[or(lt(test.t.d, 你好), gt(test.t.d, 你好))]",
 			resultStr:   "[[-inf,\"你好\") (\"你好\",+inf]]",
 		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc%'",
+			accessConds: "[like(test.t.a, abc%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc_'",
+			accessConds: "[like(test.t.a, abc_, 92)]",
+			filterConds: "[like(test.t.a, abc_, 92)]",
+			resultStr:   "[(\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc%d'",
+			accessConds: "[like(test.t.a, abc%d, 92)]",
+			filterConds: "[like(test.t.a, abc%d, 92)]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc'",
+			accessConds: "[like(test.t.a, abc, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abc\"]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE '%abc'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.a, %abc, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE ''",
+			accessConds: "[like(test.t.a, , 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"\",\"\"]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab\\\\%'",
+			accessConds: "[like(test.t.a, ab\\%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab%\",\"ab%\"]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab\\\\_c%'",
+			accessConds: "[like(test.t.a, ab\\_c%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab_c\",\"ab_d\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'ab\\\\d%'",
+			accessConds: "[like(test.t.a, ab\\d%, 92)]",
+			filterConds: "[]",
+			resultStr:   "[[\"ab\\d\",\"ab\\e\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'a|%b%' ESCAPE '|'",
+			accessConds: "[like(test.t.a, a|%b%, 124)]",
+			filterConds: "[]",
+			resultStr:   "[[\"a%b\",\"a%c\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a NOT LIKE 'abc%'",
+			accessConds: "[]",
+			filterConds: "[not(like(test.t.a, abc%, 92))]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc%' and b = 1",
+			accessConds: "[like(test.t.a, abc%, 92)]",
+			filterConds: "[eq(test.t.b, 1)]",
+			resultStr:   "[[\"abc\",\"abd\")]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "a LIKE 'abc%' or a LIKE 'b%'",
+			accessConds: "[or(like(test.t.a, abc%, 92), like(test.t.a, b%, 92))]",
+			filterConds: "[]",
+			resultStr:   "[[\"abc\",\"abd\") [\"b\",\"c\")]",
+		},
+		{
+			indexPos:    2,
+			exprStr:     "d LIKE '你好啊%'",
+			accessConds: "[like(test.t.d, 你好啊%, 92)]",
+			filterConds: "[like(test.t.d, 你好啊%, 92)]",
+			resultStr:   "[[\"你好\",\"你好\"]]",
+		},
+		{
+			indexPos:    0,
+			exprStr:     "b LIKE '1%'",
+			accessConds: "[]",
+			filterConds: "[like(test.t.b, 1%, 92)]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "c <=> 1",
+			accessConds: "[nulleq(test.t.c, 1)]",
+			filterConds: "[]",
+			resultStr:   "[[1,1]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "c <=> NULL",
+			accessConds: "[nulleq(test.t.c, <nil>)]",
+			filterConds: "[]",
+			resultStr:   "[[NULL,NULL]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "c is null and a = 'x'",
+			accessConds: "[isnull(test.t.c) eq(test.t.a, x)]",
+			filterConds: "[]",
+			resultStr:   "[[NULL \"x\",NULL \"x\"]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "c <=> null and a > 'x'",
+			accessConds: "[nulleq(test.t.c, <nil>) gt(test.t.a, x)]",
+			filterConds: "[]",
+			resultStr:   "[(NULL \"x\",NULL +inf]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "c <=> 1 or c <=> null",
+			accessConds: "[or(nulleq(test.t.c, 1), nulleq(test.t.c, <nil>))]",
+			filterConds: "[]",
+			resultStr:   "[[NULL,NULL] [1,1]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "not (c <=> 1)",
+			accessConds: "[]",
+			filterConds: "[not(nulleq(test.t.c, 1))]",
+			resultStr:   "[[NULL,+inf]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "c is null and c = 1",
+			accessConds: "[]",
+			filterConds: "[]",
+			resultStr:   "[]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "(c = 1 or c = 2) and a = 'x'",
+			accessConds: "[or(eq(test.t.c, 1), eq(test.t.c, 2)) eq(test.t.a, x)]",
+			filterConds: "[]",
+			resultStr:   "[[1 \"x\",1 \"x\"] [2 \"x\",2 \"x\"]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "(c = 1 or c = 2) and (a = 'x' or a > 'y') and b > 1",
+			accessConds: "[or(eq(test.t.c, 1), eq(test.t.c, 2)) or(eq(test.t.a, x), gt(test.t.a, y))]",
+			filterConds: "[gt(test.t.b, 1)]",
+			resultStr:   "[[1 \"x\",1 \"x\"] (1 \"y\",1 +inf] [2 \"x\",2 \"x\"] (2 \"y\",2 +inf]]",
+		},
+		{
+			indexPos:    1,
+			exprStr:     "(c = 1 or c = 2) and (a = 'x' or b = 1)",
+			accessConds: "[or(eq(test.t.c, 1), eq(test.t.c, 2))]",
+			filterConds: "[or(eq(test.t.a, x), eq(test.t.b, 1))]",
+			resultStr:   "[[1,1] [2,2]]",
+		},
 	}
 
 	ctx := context.Background()
@@ -53,6 +53,16 @@ func (ran *Range) Clone() *Range {
 
 // IsPoint returns if the range is a point.
 func (ran *Range) IsPoint(sc *stmtctx.StatementContext) bool {
+	return ran.isPoint(sc, false)
+}
+
+// IsPointNullable returns if the range is a point, in which the NULL values are regarded as points too.
+// It's used to build the ranges of the index columns, but the NULL values are not unique in the unique indexes.
+func (ran *Range) IsPointNullable(sc *stmtctx.StatementContext) bool {
+	return ran.isPoint(sc, true)
+}
+
+func (ran *Range) isPoint(sc *stmtctx.StatementContext, nullable bool) bool {
 	if len(ran.LowVal) != len(ran.HighVal) {
 		return false
 	}
@@ -70,7 +80,7 @@ func (ran *Range) IsPoint(sc *stmtctx.StatementContext) bool {
 			return false
 		}
 
-		if a.IsNull() {
+		if a.IsNull() && !nullable {
 			return false
 		}
 	}
@@ -133,6 +143,21 @@ func (ran *Range) PrefixEqualLen(sc *stmtctx.StatementContext) (int, error) {
 	return len(ran.LowVal), nil
 }
 
+// IsPrefixPointRanges checks whether every range only varies on its last column,
+// that is, all the other columns of the range are points.
+func IsPrefixPointRanges(sc *stmtctx.StatementContext, ranges []*Range) bool {
+	for _, ran := range ranges {
+		if len(ran.LowVal) != len(ran.HighVal) {
+			return false
+		}
+		eqLen, err := ran.PrefixEqualLen(sc)
+		if err != nil || eqLen < len(ran.LowVal)-1 {
+			return false
+		}
+	}
+	return true
+}
+
 func formatDatum(d types.Datum, isLeftSide bool) string {
 	switch d.Kind() {
 	case types.KindNull:
@@ -16,31 +16,60 @@ package decoder
 import (
 	"time"
 
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/rowcodec"
 )
 
 // Column contains the info and generated expr of column.
 type Column struct {
-	Col *table.Column
+	Col     *table.Column
+	GenExpr expression.Expression
 }
 
 // RowDecoder decodes a byte slice into datums and eval the generated column value.
 type RowDecoder struct {
+	tbl      table.Table
+	mutRow   chunk.MutRow
+	columns  map[int64]Column
 	colTypes map[int64]*types.FieldType
+	// haveGenColumn is true if any of the columns is a generated column.
+	haveGenColumn bool
 }
 
 // NewRowDecoder returns a new RowDecoder.
 func NewRowDecoder(tbl table.Table, decodeColMap map[int64]Column) *RowDecoder {
 	colFieldMap := make(map[int64]*types.FieldType, len(decodeColMap))
+	haveGenCol := false
 	for id, col := range decodeColMap {
 		colFieldMap[id] = &col.Col.ColumnInfo.FieldType
+		if col.GenExpr != nil {
+			haveGenCol = true
+		}
+	}
+	if !haveGenCol {
+		return &RowDecoder{
+			colTypes: colFieldMap,
+		}
+	}
+
+	// The generated columns are evaluated on the rows of all the columns, which are indexed by the column offsets.
+	tps := make([]*types.FieldType, 0, len(tbl.Meta().Columns))
+	for _, col := range tbl.Meta().Columns {
+		tps = append(tps, &col.FieldType)
 	}
 	return &RowDecoder{
-		colTypes: colFieldMap,
+		tbl:           tbl,
+		mutRow:        chunk.MutRowFromTypes(tps),
+		columns:       decodeColMap,
+		colTypes:      colFieldMap,
+		haveGenColumn: true,
 	}
 }
 
@@ -55,24 +84,84 @@ func (rd *RowDecoder) DecodeAndEvalRowWithMap(ctx sessionctx.Context, handle int
 	if err != nil {
 		return nil, err
 	}
+	if !rd.haveGenColumn {
+		return row, nil
+	}
+
+	for _, dCol := range rd.columns {
+		colInfo := dCol.Col.ColumnInfo
+		if dCol.GenExpr != nil {
+			continue
+		}
+		if dCol.Col.IsPKHandleColumn(rd.tbl.Meta()) {
+			if mysql.HasUnsignedFlag(colInfo.Flag) {
+				rd.mutRow.SetValue(colInfo.Offset, uint64(handle))
+			} else {
+				rd.mutRow.SetValue(colInfo.Offset, handle)
+			}
+			continue
+		}
+		val, ok := row[colInfo.ID]
+		if !ok {
+			// The column is added after the row is written.
+			val, err = table.GetColOriginDefaultValue(ctx, colInfo)
+			if err != nil {
+				return nil, err
+			}
+		}
+		rd.mutRow.SetDatum(colInfo.Offset, val)
+	}
+	for id, col := range rd.columns {
+		if col.GenExpr == nil {
+			continue
+		}
+		val, err := col.GenExpr.Eval(rd.mutRow.ToRow())
+		if err != nil {
+			return nil, err
+		}
+		row[id], err = table.CastValue(ctx, val, col.Col.ColumnInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return row, nil
 }
 
 // BuildFullDecodeColMap build a map that contains [columnID -> struct{*table.Column, expression.Expression}] from
 // indexed columns and all of its depending columns.
-func BuildFullDecodeColMap(indexedCols []*table.Column) (map[int64]Column, error) {
+func BuildFullDecodeColMap(ctx sessionctx.Context, tbl table.Table, indexedCols []*table.Column) (map[int64]Column, error) {
 	pendingCols := make([]*table.Column, len(indexedCols))
 	copy(pendingCols, indexedCols)
 	decodeColMap := make(map[int64]Column, len(pendingCols))
 
+	var schema *expression.Schema
+	var names types.NameSlice
 	for i := 0; i < len(pendingCols); i++ {
 		col := pendingCols[i]
 		if _, ok := decodeColMap[col.ID]; ok {
 			continue // already discovered
 		}
 
+		if !col.IsGenerated() {
+			decodeColMap[col.ID] = Column{
+				Col: col,
+			}
+			continue
+		}
+		if schema == nil {
+			schema, names = expression.TableInfo2SchemaAndNames(ctx, model.NewCIStr(""), tbl.Meta())
+		}
+		genExpr, err := expression.RewriteAstExpr(ctx, col.GeneratedExpr, schema, names)
+		if err != nil {
+			return nil, err
+		}
+		// The columns of the schema are indexed by the column offsets, the depended columns are decoded too.
+		for _, depCol := range expression.ExtractColumns(genExpr) {
+			pendingCols = append(pendingCols, table.ToColumn(tbl.Meta().Columns[depCol.Index]))
+		}
 		decodeColMap[col.ID] = Column{
-			Col: col,
+			Col:     col,
+			GenExpr: genExpr,
 		}
 	}
 	return decodeColMap, nil
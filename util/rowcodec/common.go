@@ -15,6 +15,7 @@ package rowcodec
 
 import (
 	"encoding/binary"
+	"hash/crc32"
 	"reflect"
 	"unsafe"
 
@@ -24,7 +25,12 @@ import (
 // CodecVer is the constant number that represent the new row format.
 const CodecVer = 128
 
-var errInvalidCodecVer = errors.New("invalid codec version")
+var (
+	errInvalidCodecVer = errors.New("invalid codec version")
+	errInvalidChecksum = errors.New("invalid row checksum")
+	// ErrChecksumMismatch is returned by VerifyChecksum if the row doesn't match its checksum.
+	ErrChecksumMismatch = errors.New("row checksum mismatch")
+)
 
 // First byte in the encoded value which specifies the encoding type.
 const (
@@ -228,3 +234,24 @@ func IsRowKey(key []byte) bool {
 func IsNewFormat(rowData []byte) bool {
 	return rowData[0] == CodecVer
 }
+
+// HasChecksum checks whether the checksum is stored in the row data.
+func HasChecksum(rowData []byte) bool {
+	return len(rowData) > 1 && IsNewFormat(rowData) && rowData[1]&rowFlagChecksum > 0
+}
+
+// VerifyChecksum verifies the row data against the checksum stored in it, the rows without
+// checksum always pass.
+func VerifyChecksum(rowData []byte) error {
+	if !HasChecksum(rowData) {
+		return nil
+	}
+	n := len(rowData) - checksumLen
+	if n < 2 {
+		return errInvalidChecksum
+	}
+	if crc32.ChecksumIEEE(rowData[:n]) != binary.LittleEndian.Uint32(rowData[n:]) {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
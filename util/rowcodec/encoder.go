@@ -28,6 +28,8 @@ type Encoder struct {
 	row
 	tempColIDs []int64
 	values     []types.Datum
+	// Checksum indicates whether to append the checksum to the encoded rows.
+	Checksum bool
 }
 
 // Encode encodes a row from a datums slice.
@@ -44,6 +46,7 @@ func (encoder *Encoder) Encode(sc *stmtctx.StatementContext, colIDs []int64, val
 
 func (encoder *Encoder) reset() {
 	encoder.large = false
+	encoder.hasChecksum = encoder.Checksum
 	encoder.numNotNullCols = 0
 	encoder.numNullCols = 0
 	encoder.data = encoder.data[:0]
@@ -183,6 +186,10 @@ func EncodeValueDatum(sc *stmtctx.StatementContext, d types.Datum, buffer []byte
 		buffer = encodeUint(buffer, val)
 	case types.KindFloat32, types.KindFloat64:
 		buffer = codec.EncodeFloat(buffer, d.GetFloat64())
+	case types.KindMysqlJSON:
+		j := d.GetMysqlJSON()
+		buffer = append(buffer, j.TypeCode)
+		buffer = append(buffer, j.Value...)
 	case types.KindNull:
 	case types.KindMinNotNull:
 	case types.KindMaxValue:
@@ -15,13 +15,25 @@ package rowcodec
 
 import (
 	"encoding/binary"
+	"hash/crc32"
 )
 
+// The bits of the flag byte of a row.
+const (
+	rowFlagLarge byte = 1 << iota
+	// rowFlagChecksum indicates a CRC32 checksum of the preceding bytes is appended to the row.
+	rowFlagChecksum
+)
+
+// checksumLen is the length of the checksum appended to a row.
+const checksumLen = 4
+
 // row is the struct type used to access the a row.
 type row struct {
 	// small:  colID []byte, offsets []uint16, optimized for most cases.
 	// large:  colID []uint32, offsets []uint32.
 	large          bool
+	hasChecksum    bool
 	numNotNullCols uint16
 	numNullCols    uint16
 	colIDs         []byte
@@ -54,7 +66,8 @@ func (r *row) fromBytes(rowData []byte) error {
 	if rowData[0] != CodecVer {
 		return errInvalidCodecVer
 	}
-	r.large = rowData[1]&1 > 0
+	r.large = rowData[1]&rowFlagLarge > 0
+	r.hasChecksum = rowData[1]&rowFlagChecksum > 0
 	r.numNotNullCols = binary.LittleEndian.Uint16(rowData[2:])
 	r.numNullCols = binary.LittleEndian.Uint16(rowData[4:])
 	cursor := 6
@@ -73,15 +86,25 @@ func (r *row) fromBytes(rowData []byte) error {
 		r.offsets = bytes2U16Slice(rowData[cursor : cursor+offsetsLen])
 		cursor += offsetsLen
 	}
+	if r.hasChecksum {
+		if len(rowData)-cursor < checksumLen {
+			return errInvalidChecksum
+		}
+		rowData = rowData[:len(rowData)-checksumLen]
+	}
 	r.data = rowData[cursor:]
 	return nil
 }
 
 func (r *row) toBytes(buf []byte) []byte {
+	start := len(buf)
 	buf = append(buf, CodecVer)
 	flag := byte(0)
 	if r.large {
-		flag = 1
+		flag |= rowFlagLarge
+	}
+	if r.hasChecksum {
+		flag |= rowFlagChecksum
 	}
 	buf = append(buf, flag)
 	buf = append(buf, byte(r.numNotNullCols), byte(r.numNotNullCols>>8))
@@ -94,6 +117,11 @@ func (r *row) toBytes(buf []byte) []byte {
 		buf = append(buf, u16SliceToBytes(r.offsets)...)
 	}
 	buf = append(buf, r.data...)
+	if r.hasChecksum {
+		var checksum [checksumLen]byte
+		binary.LittleEndian.PutUint32(checksum[:], crc32.ChecksumIEEE(buf[start:]))
+		buf = append(buf, checksum[:]...)
+	}
 	return buf
 }
 
@@ -24,6 +24,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/rowcodec"
@@ -271,6 +272,14 @@ func (s *testSuite) TestTypesNewRowCodec(c *C) {
 			nil,
 			false,
 		},
+		{
+			13,
+			types.NewFieldType(mysql.TypeJSON),
+			types.NewDatum(json.CreateBinary(map[string]interface{}{"a": []interface{}{int64(1), "b"}})),
+			types.NewDatum(json.CreateBinary(map[string]interface{}{"a": []interface{}{int64(1), "b"}})),
+			nil,
+			false,
+		},
 		{
 			11,
 			types.NewFieldType(mysql.TypeNull),
@@ -502,6 +511,45 @@ func (s *testSuite) TestVarintCompatibility(c *C) {
 	encodeAndDecodeByte(c, testDataValue)
 }
 
+func (s *testSuite) TestChecksum(c *C) {
+	sc := new(stmtctx.StatementContext)
+	sc.TimeZone = time.UTC
+	colIDs := []int64{1, 2, 3}
+	dts := []types.Datum{types.NewIntDatum(1), types.NewStringDatum("abc"), types.NewDatum(nil)}
+	cols := []rowcodec.ColInfo{
+		{ID: 1, Tp: int32(mysql.TypeLonglong)},
+		{ID: 2, Tp: int32(mysql.TypeVarchar)},
+		{ID: 3, Tp: int32(mysql.TypeLonglong)},
+	}
+
+	var encoder rowcodec.Encoder
+	plainRow, err := encoder.Encode(sc, colIDs, dts, nil)
+	c.Assert(err, IsNil)
+	c.Assert(rowcodec.HasChecksum(plainRow), IsFalse)
+	c.Assert(rowcodec.VerifyChecksum(plainRow), IsNil)
+
+	encoder.Checksum = true
+	newRow, err := encoder.Encode(sc, colIDs, dts, nil)
+	c.Assert(err, IsNil)
+	c.Assert(rowcodec.HasChecksum(newRow), IsTrue)
+	c.Assert(newRow, HasLen, len(plainRow)+4)
+	c.Assert(rowcodec.VerifyChecksum(newRow), IsNil)
+
+	// The rows with checksum are decoded as the rows without checksum.
+	dm, err := rowcodec.NewDatumMapDecoder(cols, -1, sc.TimeZone).DecodeToDatumMap(newRow, -1, nil)
+	c.Assert(err, IsNil)
+	c.Assert(dm, HasLen, 3)
+	c.Assert(dm[1], DeepEquals, dts[0])
+	c.Assert(dm[2], DeepEquals, types.NewBytesDatum([]byte("abc")))
+	c.Assert(dm[3], DeepEquals, dts[2])
+
+	for _, i := range []int{2, len(newRow) - 5, len(newRow) - 1} {
+		corrupted := append([]byte(nil), newRow...)
+		corrupted[i] ^= 0xff
+		c.Assert(rowcodec.VerifyChecksum(corrupted), Equals, rowcodec.ErrChecksumMismatch)
+	}
+}
+
 func (s *testSuite) TestCodecUtil(c *C) {
 	colIDs := []int64{1, 2, 3, 4}
 	tps := make([]*types.FieldType, 4)
@@ -186,6 +186,50 @@ func CompilePattern(pattern string, escape byte) (patChars, patTypes []byte) {
 	return
 }
 
+// CompilePatternRunes is like CompilePattern, but the wildcards of the compiled
+// pattern match characters instead of bytes.
+func CompilePatternRunes(pattern string, escape rune) (patChars []rune, patTypes []byte) {
+	var lastAny bool
+	runes := []rune(pattern)
+	patChars = make([]rune, 0, len(runes))
+	patTypes = make([]byte, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		var tp byte
+		var c = runes[i]
+		switch c {
+		case escape:
+			lastAny = false
+			tp = patMatch
+			if i < len(runes)-1 {
+				i++
+				c = runes[i]
+				if !(c == escape || c == '_' || c == '%') {
+					// Invalid escape, fall back to the escape character as CompilePattern.
+					i--
+					c = escape
+				}
+			}
+		case '_':
+			if lastAny {
+				continue
+			}
+			tp = patOne
+		case '%':
+			if lastAny {
+				continue
+			}
+			lastAny = true
+			tp = patAny
+		default:
+			lastAny = false
+			tp = patMatch
+		}
+		patChars = append(patChars, c)
+		patTypes = append(patTypes, tp)
+	}
+	return
+}
+
 // NOTE: Currently tikv's like function is case sensitive, so we keep its behavior here.
 func matchByteCI(a, b byte) bool {
 	return a == b
@@ -272,6 +316,56 @@ func DoMatch(str string, patChars, patTypes []byte) bool {
 	return true
 }
 
+// DoMatchRunes matches the string with the patChars and patTypes compiled by
+// CompilePatternRunes, the characters are compared with equal, or compared by
+// their code points if equal is nil. Like DoMatch, it is linear in time.
+func DoMatchRunes(str string, patChars []rune, patTypes []byte, equal func(a, b rune) bool) bool {
+	var sIdx, pIdx, nextSIdx, nextPIdx int
+	for pIdx < len(patChars) || sIdx < len(str) {
+		if pIdx < len(patChars) {
+			switch patTypes[pIdx] {
+			case patMatch:
+				if sIdx < len(str) {
+					r, size := utf8.DecodeRuneInString(str[sIdx:])
+					if r == patChars[pIdx] || (equal != nil && equal(r, patChars[pIdx])) {
+						pIdx++
+						sIdx += size
+						continue
+					}
+				}
+			case patOne:
+				if sIdx < len(str) {
+					_, size := utf8.DecodeRuneInString(str[sIdx:])
+					pIdx++
+					sIdx += size
+					continue
+				}
+			case patAny:
+				// Try to match at sIdx.
+				// If that doesn't work out,
+				// restart at the next character.
+				size := 1
+				if sIdx < len(str) {
+					_, size = utf8.DecodeRuneInString(str[sIdx:])
+				}
+				nextPIdx = pIdx
+				nextSIdx = sIdx + size
+				pIdx++
+				continue
+			}
+		}
+		// Mismatch. Maybe restart.
+		if 0 < nextSIdx && nextSIdx <= len(str) {
+			pIdx = nextPIdx
+			sIdx = nextSIdx
+			continue
+		}
+		return false
+	}
+	// Matched all of pattern to all of name. Success.
+	return true
+}
+
 // IsExactMatch return true if no wildcard character
 func IsExactMatch(patTypes []byte) bool {
 	for _, pt := range patTypes {
@@ -15,6 +15,7 @@ package stringutil
 
 import (
 	"testing"
+	"unicode"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/tidb/util/testleak"
@@ -125,6 +126,45 @@ func (s *testStringUtilSuite) TestPatternMatch(c *C) {
 	}
 }
 
+func (s *testStringUtilSuite) TestPatternMatchRunes(c *C) {
+	defer testleak.AfterTest(c)()
+	tbl := []struct {
+		pattern string
+		input   string
+		escape  rune
+		match   bool
+	}{
+		{`a`, `a`, '\\', true},
+		{`_`, `中`, '\\', true},
+		{`__`, `中`, '\\', false},
+		{`a_c`, `a文c`, '\\', true},
+		{`%文`, `中文`, '\\', true},
+		{`中%`, `中文字`, '\\', true},
+		{`%文%`, `中文字`, '\\', true},
+		{`%字_`, `中文字`, '\\', false},
+		{`_文_`, `中文字`, '\\', true},
+		{`%`, ``, '\\', true},
+		{`_`, ``, '\\', false},
+		{`\%a`, `%a`, '\\', true},
+		{`\_a`, `中a`, '\\', false},
+		{`中%a`, `%a`, '中', true},
+		{`中%a`, `中a`, '中', false},
+		{`中中_`, `中x`, '中', true},
+		{`%_%_aA`, "aaaA", '\\', true},
+	}
+	for _, v := range tbl {
+		patChars, patTypes := CompilePatternRunes(v.pattern, v.escape)
+		match := DoMatchRunes(v.input, patChars, patTypes, nil)
+		c.Assert(match, Equals, v.match, Commentf("%v", v))
+	}
+
+	// The characters are compared with the equal function.
+	patChars, patTypes := CompilePatternRunes("a_C%", '\\')
+	equalFold := func(a, b rune) bool { return unicode.ToUpper(a) == unicode.ToUpper(b) }
+	c.Assert(DoMatchRunes("Abcd", patChars, patTypes, equalFold), IsTrue)
+	c.Assert(DoMatchRunes("Abcd", patChars, patTypes, nil), IsFalse)
+}
+
 func (s *testStringUtilSuite) TestCompileLike2Regexp(c *C) {
 	defer testleak.AfterTest(c)()
 	tbl := []struct {
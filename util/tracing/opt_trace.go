@@ -0,0 +1,99 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/pingcap/errors"
+)
+
+// OptimizeTracer records the decisions made by the optimizer when planning a statement.
+type OptimizeTracer struct {
+	// Logical records the logical rules applied to the plan, in order.
+	Logical []*LogicalRuleTrace `json:"logical"`
+	// Physical records the access paths enumerated for every DataSource and required property.
+	Physical []*DataSourceTrace `json:"physical"`
+	// FinalPlan is the chosen physical plan.
+	FinalPlan string `json:"final_plan"`
+	// Cost is the estimated cost of the chosen physical plan.
+	Cost float64 `json:"cost"`
+}
+
+// LogicalRuleTrace records the plan before and after applying a logical rule.
+type LogicalRuleTrace struct {
+	Rule   string `json:"rule"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// DataSourceTrace records the candidate access paths of a DataSource under a required property.
+type DataSourceTrace struct {
+	Table      string                `json:"table"`
+	Property   string                `json:"property"`
+	Candidates []*CandidatePathTrace `json:"candidates"`
+	// Chosen is the name of the path with the least cost, it's empty if no path can satisfy the property.
+	Chosen string `json:"chosen"`
+}
+
+// CandidatePathTrace records how an access path is estimated, and why it is pruned if so.
+type CandidatePathTrace struct {
+	Path        string   `json:"path"`
+	AccessConds []string `json:"access_conds"`
+	Ranges      []string `json:"ranges"`
+	RowCount    float64  `json:"row_count"`
+	Cost        float64  `json:"cost"`
+	Pruned      bool     `json:"pruned"`
+	Reason      string   `json:"reason,omitempty"`
+}
+
+// AppendLogicalRule records the applying of a logical rule.
+func (t *OptimizeTracer) AppendLogicalRule(rule, before, after string) {
+	t.Logical = append(t.Logical, &LogicalRuleTrace{Rule: rule, Before: before, After: after})
+}
+
+// AppendDataSource starts recording the access paths of a DataSource.
+func (t *OptimizeTracer) AppendDataSource(table, prop string) *DataSourceTrace {
+	ds := &DataSourceTrace{Table: table, Property: prop}
+	t.Physical = append(t.Physical, ds)
+	return ds
+}
+
+// AppendCandidate records a candidate access path.
+func (ds *DataSourceTrace) AppendCandidate(candidate *CandidatePathTrace) {
+	ds.Candidates = append(ds.Candidates, candidate)
+}
+
+// Candidate returns the recorded candidate path with the given name.
+func (ds *DataSourceTrace) Candidate(path string) *CandidatePathTrace {
+	for _, candidate := range ds.Candidates {
+		if candidate.Path == path {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// JSON encodes the trace to JSON. The plans are not HTML escaped so the trace is readable.
+func (t *OptimizeTracer) JSON() (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(t); err != nil {
+		return "", errors.Trace(err)
+	}
+	return buf.String(), nil
+}
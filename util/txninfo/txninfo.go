@@ -0,0 +1,84 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txninfo
+
+import (
+	"sort"
+	"sync"
+)
+
+// TxnInfo is the info of a running transaction.
+type TxnInfo struct {
+	StartTS      uint64
+	ConnectionID uint64
+	Username     string
+	CurrentDB    string
+	// CurrentSQL is the statement the transaction is running, it's empty if the transaction is idle.
+	CurrentSQL string
+	// Len and Size are the number and the total size of the mutations of the transaction, they're
+	// updated when the statements of the transaction start and finish.
+	Len  int
+	Size int
+}
+
+// Registry records the running transactions, the info of a transaction is updated by its session and
+// read by the others concurrently.
+type Registry struct {
+	mu   sync.RWMutex
+	txns map[*TxnInfo]struct{}
+}
+
+// GlobalRegistry records the running transactions of all the sessions of the server.
+var GlobalRegistry = NewRegistry()
+
+// NewRegistry creates a Registry.
+func NewRegistry() *Registry {
+	return &Registry{txns: make(map[*TxnInfo]struct{})}
+}
+
+// Register records the transaction started at startTS, and returns its info which should be passed to
+// Update and Unregister.
+func (r *Registry) Register(startTS uint64) *TxnInfo {
+	info := &TxnInfo{StartTS: startTS}
+	r.mu.Lock()
+	r.txns[info] = struct{}{}
+	r.mu.Unlock()
+	return info
+}
+
+// Update updates the info of the transaction by fn.
+func (r *Registry) Update(info *TxnInfo, fn func(info *TxnInfo)) {
+	r.mu.Lock()
+	fn(info)
+	r.mu.Unlock()
+}
+
+// Unregister removes the transaction after it's committed or rolled back.
+func (r *Registry) Unregister(info *TxnInfo) {
+	r.mu.Lock()
+	delete(r.txns, info)
+	r.mu.Unlock()
+}
+
+// Snapshot returns the copies of the infos of the running transactions in the order of the start ts.
+func (r *Registry) Snapshot() []TxnInfo {
+	r.mu.RLock()
+	infos := make([]TxnInfo, 0, len(r.txns))
+	for info := range r.txns {
+		infos = append(infos, *info)
+	}
+	r.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].StartTS < infos[j].StartTS })
+	return infos
+}
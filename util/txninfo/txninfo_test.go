@@ -0,0 +1,53 @@
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txninfo
+
+import (
+	"testing"
+
+	"github.com/pingcap/check"
+)
+
+func TestT(t *testing.T) {
+	check.TestingT(t)
+}
+
+var _ = check.Suite(&testRegistrySuite{})
+
+type testRegistrySuite struct{}
+
+func (s *testRegistrySuite) TestRegistry(c *check.C) {
+	r := NewRegistry()
+	c.Assert(r.Snapshot(), check.HasLen, 0)
+
+	info2 := r.Register(2)
+	info1 := r.Register(1)
+	r.Update(info2, func(info *TxnInfo) {
+		info.CurrentSQL = "select 1"
+		info.Len, info.Size = 3, 30
+	})
+	infos := r.Snapshot()
+	c.Assert(infos, check.HasLen, 2)
+	c.Assert(infos[0], check.Equals, TxnInfo{StartTS: 1})
+	c.Assert(infos[1], check.Equals, TxnInfo{StartTS: 2, CurrentSQL: "select 1", Len: 3, Size: 30})
+
+	// The snapshot is a copy, which isn't affected by the later updates.
+	r.Update(info2, func(info *TxnInfo) { info.CurrentSQL = "" })
+	c.Assert(infos[1].CurrentSQL, check.Equals, "select 1")
+
+	r.Unregister(info1)
+	infos = r.Snapshot()
+	c.Assert(infos, check.HasLen, 1)
+	c.Assert(infos[0].StartTS, check.Equals, uint64(2))
+}